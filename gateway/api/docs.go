@@ -15,21 +15,36 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/personal-profile": {
+        "/api/v1/activity": {
             "get": {
+                "description": "kind: event_created (новое мероприятие), talk_accepted (доклад принят в программу),\ngroup_joined (вступление в сообщество). Из сообществ в подписках приходят только новые мероприятия.",
                 "tags": [
-                    "Пользователь"
+                    "Подписки"
+                ],
+                "summary": "Действия тех, на кого подписан текущий пользователь, новые сначала",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Профиль текущего пользователя",
                 "responses": {
                     "200": {
-                        "description": "Параметры текущего пользователя",
+                        "description": "Лента",
                         "schema": {
-                            "$ref": "#/definitions/rest.OkResponse"
+                            "$ref": "#/definitions/rest.ActivitiesOkResponse"
                         }
                     },
                     "201": {
-                        "description": "Внутренняя ошибка сервиса",
+                        "description": "Ошибка при получении ленты",
                         "schema": {
                             "$ref": "#/definitions/rest.ErrResponse"
                         }
@@ -37,32 +52,121 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/sign-in": {
-            "post": {
+        "/api/v1/admin/audit": {
+            "get": {
+                "description": "Входы, регистрации, смена пароля, действия администраторов и организаторов.\nbefore и after - состояние объекта до и после изменения. Доступно только администраторам.",
                 "tags": [
-                    "Авторизация"
+                    "Администрирование"
                 ],
-                "summary": "Авторизация пользователя",
+                "summary": "Журнал аудита: кто, когда и откуда выполнил действие, новые записи сначала",
                 "parameters": [
                     {
-                        "description": "Почта и Пароль",
-                        "name": "Request",
-                        "in": "body",
-                        "required": true,
+                        "type": "integer",
+                        "description": "Кто выполнил действие",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Действие, например user.role_changed или auth.sign_in_failed",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тип объекта: user, event, talk, webhook или order",
+                        "name": "target_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор объекта",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Не раньше (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Раньше (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Записи журнала",
                         "schema": {
-                            "$ref": "#/definitions/rest.signInUpInput"
+                            "$ref": "#/definitions/rest.AuditEventsOkResponse"
                         }
+                    },
+                    "201": {
+                        "description": "Нет прав или неверный фильтр",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/jobs": {
+            "get": {
+                "description": "pending - ждёт выполнения, running - выполняется, done - выполнена, dead - исчерпала попытки.",
+                "tags": [
+                    "Администрирование"
+                ],
+                "summary": "Задачи очереди, новые сначала. Только для администраторов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Статус: pending, running, done, dead",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тип задачи",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешная авторизация",
+                        "description": "Задачи",
                         "schema": {
-                            "$ref": "#/definitions/rest.SignInOkResponse"
+                            "$ref": "#/definitions/rest.JobsOkResponse"
                         }
                     },
                     "201": {
-                        "description": "Ошибка при попытке авторизоваться",
+                        "description": "Ошибка при получении задач",
                         "schema": {
                             "$ref": "#/definitions/rest.ErrResponse"
                         }
@@ -70,113 +174,8185 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/sign-up": {
-            "post": {
+        "/api/v1/admin/jobs/{id}": {
+            "get": {
                 "tags": [
-                    "Регистрация"
+                    "Администрирование"
                 ],
-                "summary": "Регистрация нового пользователя",
+                "summary": "Задача очереди с полезной нагрузкой и последней ошибкой. Только для администраторов",
                 "parameters": [
                     {
-                        "description": "Почта и Пароль",
-                        "name": "Request",
-                        "in": "body",
-                        "required": true,
+                        "type": "integer",
+                        "description": "Идентификатор задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача",
                         "schema": {
-                            "$ref": "#/definitions/rest.signInUpInput"
+                            "$ref": "#/definitions/rest.JobOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
                         }
                     }
+                }
+            }
+        },
+        "/api/v1/admin/jobs/{id}/retry": {
+            "post": {
+                "description": "Задача в статусе dead возвращается в очередь и получает новый набор попыток.",
+                "tags": [
+                    "Администрирование"
+                ],
+                "summary": "Перезапуск задачи, исчерпавшей попытки. Только для администраторов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешная регистрация нового пользователя",
+                        "description": "Задача возвращена в очередь",
                         "schema": {
-                            "$ref": "#/definitions/rest.SignUpOkResponse"
+                            "$ref": "#/definitions/rest.StatusResponse"
                         }
                     },
                     "201": {
-                        "description": "Ошибка при попытке зарегистрироваться",
+                        "description": "Задача не найдена или не в статусе dead",
                         "schema": {
                             "$ref": "#/definitions/rest.ErrResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "rest.ErrResponse": {
-            "type": "object",
-            "properties": {
-                "status": {
-                    "type": "string",
-                    "example": "wrong_params, internal_server_error"
+        },
+        "/api/v1/admin/users": {
+            "get": {
+                "description": "Доступно только администраторам.",
+                "tags": [
+                    "Администрирование"
+                ],
+                "summary": "Поиск пользователей администратором",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Часть почты, имени или username либо идентификатор",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Роль: user, moderator или admin",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "true - только заблокированные, false - только активные",
+                        "name": "suspended",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "true - только аккаунты с одной почтой в разном регистре, подряд по почте",
+                        "name": "duplicate_email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество пользователей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пользователи",
+                        "schema": {
+                            "$ref": "#/definitions/rest.AdminUsersOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Нет прав или неверный фильтр",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
                 }
             }
         },
-        "rest.OkResponse": {
-            "type": "object",
-            "properties": {
-                "profile": {
-                    "$ref": "#/definitions/rest.ProfileResponse"
-                },
-                "status": {
-                    "type": "string",
-                    "example": "ok"
+        "/api/v1/admin/users/{id}": {
+            "get": {
+                "description": "Роль, блокировка, привязка Telegram, число мероприятий, сообществ, открытых жалоб\nи решений модераторов по пользователю.",
+                "tags": [
+                    "Администрирование"
+                ],
+                "summary": "Состояние аккаунта пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Аккаунт",
+                        "schema": {
+                            "$ref": "#/definitions/rest.AccountStateOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Пользователь не найден или нет прав",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
                 }
             }
         },
-        "rest.ProfileResponse": {
-            "type": "object",
-            "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "email@gmail.com"
+        "/api/v1/admin/users/{id}/logout": {
+            "post": {
+                "description": "Все выданные пользователю токены перестают приниматься.",
+                "tags": [
+                    "Администрирование"
+                ],
+                "summary": "Принудительный выход пользователя на всех устройствах",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Токены отозваны",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Пользователь не найден или нет прав",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
                 }
             }
         },
-        "rest.SignInOkResponse": {
-            "type": "object",
-            "properties": {
-                "status": {
-                    "type": "string",
-                    "example": "ok"
-                },
-                "token": {
-                    "type": "string",
-                    "example": "adsghjyjh5effa234353ty..."
+        "/api/v1/admin/users/{id}/merge": {
+            "post": {
+                "description": "Мероприятия, сообщества, доклады, записи, комментарии, диалоги, подписки и прочие данные\nдубликата переходят к аккаунту id, дубликат удаляется. Пустые поля профиля заполняются\nиз дубликата. Почта, пароль и роль аккаунта id не меняются.\nДубликат с ролью модератора или администратора слить нельзя.",
+                "tags": [
+                    "Администрирование"
+                ],
+                "summary": "Слияние дубликата source_id с аккаунтом пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор аккаунта, который остаётся",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Дубликат",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.mergeInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Аккаунты объединены",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Пользователь не найден или нет прав",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
                 }
             }
         },
-        "rest.SignUpOkResponse": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "integer",
-                    "example": 123
-                },
-                "status": {
-                    "type": "string",
-                    "example": "ok"
+        "/api/v1/admin/users/{id}/role": {
+            "put": {
+                "description": "Свою роль администратор изменить не может.",
+                "tags": [
+                    "Администрирование"
+                ],
+                "summary": "Смена роли пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Роль",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.roleInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Роль изменена",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Пользователь не найден или нет прав",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
                 }
             }
         },
-        "rest.signInUpInput": {
-            "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
-            "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "email@gmail.com"
+        "/api/v1/admin/users/{id}/suspend": {
+            "post": {
+                "description": "Заблокированный пользователь не может войти, его токены перестают приниматься.\nАдминистратора заблокировать нельзя.",
+                "tags": [
+                    "Администрирование"
+                ],
+                "summary": "Блокировка пользователя на days дней, 0 - бессрочно",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Срок и причина",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.suspendInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пользователь заблокирован",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Пользователь не найден или нет прав",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/users/{id}/unsuspend": {
+            "post": {
+                "tags": [
+                    "Администрирование"
+                ],
+                "summary": "Снятие блокировки пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Блокировка снята",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Пользователь не найден или нет прав",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/blocks": {
+            "get": {
+                "tags": [
+                    "Сообщения"
+                ],
+                "summary": "Пользователи, которых заблокировал текущий",
+                "responses": {
+                    "200": {
+                        "description": "Заблокированные",
+                        "schema": {
+                            "$ref": "#/definitions/rest.BlocksOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении списка",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/blocks/{id}": {
+            "post": {
+                "description": "Заблокированный не может начать с вами диалог и писать в личный диалог, его сообщения в группах не приходят вам в поток.",
+                "tags": [
+                    "Сообщения"
+                ],
+                "summary": "Блокировка пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пользователь заблокирован",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Сообщения"
+                ],
+                "summary": "Разблокировка пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пользователь разблокирован",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Пользователь не заблокирован",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/comments/{id}": {
+            "put": {
+                "tags": [
+                    "Обсуждения"
+                ],
+                "summary": "Редактирование комментария (только автор), прежний текст сохраняется в истории",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор комментария",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый текст",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.commentUpdateInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Комментарий изменён",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при изменении комментария",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Ответы на удалённый комментарий остаются в треде.",
+                "tags": [
+                    "Обсуждения"
+                ],
+                "summary": "Удаление комментария (автор, организатор мероприятия или владелец сообщества)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор комментария",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Комментарий удалён",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при удалении комментария",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/comments/{id}/history": {
+            "get": {
+                "tags": [
+                    "Обсуждения"
+                ],
+                "summary": "Прежние версии комментария (автор, организатор мероприятия или владелец сообщества)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор комментария",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "История правок",
+                        "schema": {
+                            "$ref": "#/definitions/rest.CommentHistoryOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении истории",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations": {
+            "get": {
+                "tags": [
+                    "Сообщения"
+                ],
+                "summary": "Диалоги текущего пользователя, недавно активные сначала",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Диалоги",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ConversationsOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении диалогов",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "С одним собеседником и без названия создаётся личный диалог; если он уже есть, возвращается существующий.\nНельзя начать диалог с тем, кто заблокировал вас или кого заблокировали вы.\nНе больше 20 новых диалогов в час, иначе too_many_requests.",
+                "tags": [
+                    "Сообщения"
+                ],
+                "summary": "Личный диалог или группа до 10 участников",
+                "parameters": [
+                    {
+                        "description": "Участники и название",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.conversationInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Диалог",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ConversationOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при создании диалога",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations/{id}": {
+            "get": {
+                "tags": [
+                    "Сообщения"
+                ],
+                "summary": "Диалог с участниками и их отметками о прочтении",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор диалога",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Диалог",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ConversationOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Диалог не найден",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations/{id}/messages": {
+            "get": {
+                "tags": [
+                    "Сообщения"
+                ],
+                "summary": "Сообщения диалога, новые сначала",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор диалога",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сообщения",
+                        "schema": {
+                            "$ref": "#/definitions/rest.MessagesOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Диалог не найден",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Участники получают событие message в потоке /api/v1/stream.\nВ личный диалог нельзя писать, если один из собеседников заблокировал другого.\nНе больше 30 сообщений в минуту, иначе too_many_requests.",
+                "tags": [
+                    "Сообщения"
+                ],
+                "summary": "Отправка сообщения в диалог",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор диалога",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Сообщение",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.messageInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сообщение отправлено",
+                        "schema": {
+                            "$ref": "#/definitions/rest.MessageOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при отправке сообщения",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations/{id}/read": {
+            "post": {
+                "description": "Остальные участники получают событие message_read в потоке /api/v1/stream.",
+                "tags": [
+                    "Сообщения"
+                ],
+                "summary": "Отметка сообщений до message_id прочитанными",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор диалога",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Последнее прочитанное сообщение",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.readInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Отметка о прочтении",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ReadOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Диалог не найден",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/data-exports/{token}": {
+            "get": {
+                "description": "Авторизация не нужна: ссылка содержит секрет и действует до expires_at.",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "Пользователь"
+                ],
+                "summary": "Скачивание архива с данными по ссылке из download_url",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Секрет из ссылки",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ZIP-архив",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "201": {
+                        "description": "Выгрузка не найдена или ссылка истекла",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/email-changes/confirm": {
+            "post": {
+                "description": "Авторизация не нужна: код из письма на новый адрес заменяет её. После подтверждения\nвсе выданные токены перестают действовать, входить нужно с новой почтой.",
+                "tags": [
+                    "Пользователь"
+                ],
+                "summary": "Подтверждение новой почты кодом из письма",
+                "parameters": [
+                    {
+                        "description": "Код из письма",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.confirmEmailChangeInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Почта изменена",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Код не найден или истёк, адрес уже занят",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events": {
+            "get": {
+                "tags": [
+                    "Мероприятия"
+                ],
+                "summary": "Список мероприятий, при заданном near - ближайшие к точке",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Координаты центра поиска: lat,lon",
+                        "name": "near",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Радиус поиска в километрах (по умолчанию 10)",
+                        "name": "radius_km",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Мероприятия",
+                        "schema": {
+                            "$ref": "#/definitions/rest.EventsOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Внутренняя ошибка сервиса",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "tags": [
+                    "Мероприятия"
+                ],
+                "summary": "Создание мероприятия",
+                "parameters": [
+                    {
+                        "description": "Параметры мероприятия",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.eventInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Мероприятие создано",
+                        "schema": {
+                            "$ref": "#/definitions/rest.IdResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при создании мероприятия",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}": {
+            "get": {
+                "tags": [
+                    "Мероприятия"
+                ],
+                "summary": "Мероприятие по идентификатору вместе с программой",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Мероприятие",
+                        "schema": {
+                            "$ref": "#/definitions/rest.EventOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Мероприятие не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}/agenda": {
+            "get": {
+                "tags": [
+                    "Программа"
+                ],
+                "summary": "Программа мероприятия с докладчиками",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Программа мероприятия",
+                        "schema": {
+                            "$ref": "#/definitions/rest.AgendaOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Мероприятие не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "tags": [
+                    "Программа"
+                ],
+                "summary": "Добавление слота (доклад, перерыв, нетворкинг) в программу (только организатор)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Слот программы",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.slotInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Слот добавлен",
+                        "schema": {
+                            "$ref": "#/definitions/rest.IdResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при добавлении слота",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}/agenda/{slotId}": {
+            "put": {
+                "tags": [
+                    "Программа"
+                ],
+                "summary": "Изменение слота программы: время, порядок, докладчики, ссылки на слайды и видео (только организатор)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор слота",
+                        "name": "slotId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Слот программы",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.slotUpdateInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Слот изменён",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при изменении слота",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Программа"
+                ],
+                "summary": "Удаление слота из программы (только организатор)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор слота",
+                        "name": "slotId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Слот удалён",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при удалении слота",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}/agenda/{slotId}/feedback": {
+            "get": {
+                "tags": [
+                    "Отзывы"
+                ],
+                "summary": "Отзывы о докладе (только докладчики этого доклада и организатор)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор слота программы",
+                        "name": "slotId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Отзывы о докладе",
+                        "schema": {
+                            "$ref": "#/definitions/rest.TalkFeedbackOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении отзывов",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "tags": [
+                    "Отзывы"
+                ],
+                "summary": "Оценка доклада из программы мероприятия после его окончания (только записавшиеся участники)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор слота программы",
+                        "name": "slotId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Отзыв",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.feedbackInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Отзыв сохранён",
+                        "schema": {
+                            "$ref": "#/definitions/rest.IdResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при сохранении отзыва",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}/attendance": {
+            "get": {
+                "tags": [
+                    "Билеты"
+                ],
+                "summary": "Текущее число записавшихся и пришедших (только организатор)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Посещаемость",
+                        "schema": {
+                            "$ref": "#/definitions/rest.AttendanceOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении посещаемости",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}/cancel": {
+            "post": {
+                "description": "Записаться на отменённое мероприятие нельзя. Вебхуки сообщества получат event.cancelled.",
+                "tags": [
+                    "Мероприятия"
+                ],
+                "summary": "Отмена мероприятия организатором",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Мероприятие отменено",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Мероприятие не найдено, уже отменено или нет прав",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}/cfp": {
+            "get": {
+                "tags": [
+                    "Доклады"
+                ],
+                "summary": "Параметры приёма докладов на мероприятие",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Параметры CFP",
+                        "schema": {
+                            "$ref": "#/definitions/rest.CFPOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "CFP не найден",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "tags": [
+                    "Доклады"
+                ],
+                "summary": "Открытие приёма докладов (CFP) на мероприятие",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Параметры CFP",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.cfpInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Приём докладов открыт",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при открытии CFP",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}/cfp/reviewers": {
+            "post": {
+                "tags": [
+                    "Доклады"
+                ],
+                "summary": "Назначение рецензента докладов (только организатор)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Рецензент",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.reviewerInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Рецензент назначен",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при назначении рецензента",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}/check-in": {
+            "post": {
+                "description": "Статусы ошибок: invalid_ticket - подпись не прошла проверку, wrong_event - билет на другое мероприятие,\nticket_cancelled - запись отменена, already_checked_in - билет уже использован.",
+                "tags": [
+                    "Билеты"
+                ],
+                "summary": "Проверка билета на входе и отметка прихода (только организатор)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Содержимое QR-кода",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.checkInInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Приход отмечен",
+                        "schema": {
+                            "$ref": "#/definitions/rest.CheckInOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Билет не прошёл проверку",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}/comments": {
+            "get": {
+                "tags": [
+                    "Обсуждения"
+                ],
+                "summary": "Треды обсуждения мероприятия с ответами, новые сначала",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество тредов (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Треды",
+                        "schema": {
+                            "$ref": "#/definitions/rest.CommentsOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении обсуждения",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Текст в Markdown, HTML очищается. Упомянутые через @username пользователи получают уведомление.",
+                "tags": [
+                    "Обсуждения"
+                ],
+                "summary": "Комментарий или ответ в обсуждении мероприятия",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Комментарий",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.commentInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Комментарий добавлен",
+                        "schema": {
+                            "$ref": "#/definitions/rest.IdResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при добавлении комментария",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}/feedback": {
+            "get": {
+                "tags": [
+                    "Отзывы"
+                ],
+                "summary": "Сводка оценок мероприятия и его докладов (только организатор)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сводка отзывов",
+                        "schema": {
+                            "$ref": "#/definitions/rest.EventFeedbackOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении отзывов",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Повторная оценка заменяет прежнюю. Автор анонимного отзыва не виден организатору.",
+                "tags": [
+                    "Отзывы"
+                ],
+                "summary": "Оценка мероприятия после его окончания (только записавшиеся участники)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Отзыв",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.feedbackInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Отзыв сохранён",
+                        "schema": {
+                            "$ref": "#/definitions/rest.IdResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при сохранении отзыва",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}/ics": {
+            "get": {
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "Мероприятия"
+                ],
+                "summary": "Файл .ics для добавления мероприятия в календарь, программа в описании",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Файл iCalendar",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "201": {
+                        "description": "Мероприятие не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}/invitations": {
+            "get": {
+                "description": "Только для организатора.",
+                "tags": [
+                    "Приглашения"
+                ],
+                "summary": "Приглашения и ссылки-приглашения на мероприятие",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Приглашения, новые первыми",
+                        "schema": {
+                            "$ref": "#/definitions/rest.InvitationsOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении приглашений",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Только для организатора. Нужно указать либо username, либо email. Пользователь\nполучает уведомление, а на почту приходит код, который принимается при регистрации или после входа.",
+                "tags": [
+                    "Приглашения"
+                ],
+                "summary": "Личное приглашение на мероприятие",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Кого пригласить",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.inviteInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Приглашение отправлено",
+                        "schema": {
+                            "$ref": "#/definitions/rest.InvitationOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при отправке приглашения",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}/invite-links": {
+            "post": {
+                "description": "Только для организатора. Ссылкой может воспользоваться любой, пока она не истекла\nи не исчерпано число использований.",
+                "tags": [
+                    "Приглашения"
+                ],
+                "summary": "Создание ссылки-приглашения на мероприятие",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Ограничения ссылки",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.inviteLinkInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ссылка создана",
+                        "schema": {
+                            "$ref": "#/definitions/rest.InvitationOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при создании ссылки",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}/orders": {
+            "get": {
+                "description": "Только для организатора.",
+                "tags": [
+                    "Билеты"
+                ],
+                "summary": "Заказы билетов на мероприятие",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Заказы, новые первыми",
+                        "schema": {
+                            "$ref": "#/definitions/rest.OrdersOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении заказов",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Заказ удерживает билет, пока его не оплатят по payment_url или не истечёт срок оплаты.\nБесплатный заказ оплачивается сразу. Оплаченный заказ записывает на мероприятие.",
+                "tags": [
+                    "Билеты"
+                ],
+                "summary": "Заказ билета на мероприятие",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Тип билета",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.orderInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Заказ создан",
+                        "schema": {
+                            "$ref": "#/definitions/rest.OrderOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при создании заказа",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}/rsvp": {
+            "post": {
+                "tags": [
+                    "Билеты"
+                ],
+                "summary": "Запись на мероприятие, в ответе - билет с подписанным QR-кодом",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Билет",
+                        "schema": {
+                            "$ref": "#/definitions/rest.TicketOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при записи на мероприятие",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Билеты"
+                ],
+                "summary": "Отмена записи на мероприятие, билет перестаёт действовать",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Запись отменена",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при отмене записи",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}/talks": {
+            "get": {
+                "tags": [
+                    "Доклады"
+                ],
+                "summary": "Заявки на доклады с оценками (организатор и рецензенты)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Заявки на доклады",
+                        "schema": {
+                            "$ref": "#/definitions/rest.TalksOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении заявок",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "tags": [
+                    "Доклады"
+                ],
+                "summary": "Подача заявки на доклад",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Заявка на доклад",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.talkInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Заявка принята на рассмотрение",
+                        "schema": {
+                            "$ref": "#/definitions/rest.IdResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при подаче заявки",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}/ticket": {
+            "get": {
+                "tags": [
+                    "Билеты"
+                ],
+                "summary": "Билет текущего пользователя: строка для QR-кода и PNG в base64",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Билет",
+                        "schema": {
+                            "$ref": "#/definitions/rest.TicketOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Билет не найден",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}/ticket-types": {
+            "get": {
+                "tags": [
+                    "Билеты"
+                ],
+                "summary": "Типы билетов мероприятия",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Типы билетов",
+                        "schema": {
+                            "$ref": "#/definitions/rest.TicketTypesOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении типов билетов",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Только для организатора. Пока у мероприятия есть билеты, записаться на него можно только\nс оплаченным заказом. Бесплатный билет стоит 0, платный, ранний и спонсорский - больше 0\nв указанной валюте. Ранние билеты продаются до sales_end_at, который раньше начала мероприятия.",
+                "tags": [
+                    "Билеты"
+                ],
+                "summary": "Создание типа билета на мероприятие",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Тип билета",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.ticketTypeInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Тип билета создан",
+                        "schema": {
+                            "$ref": "#/definitions/rest.IdResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при создании типа билета",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}/ticket.png": {
+            "get": {
+                "produces": [
+                    "image/png"
+                ],
+                "tags": [
+                    "Билеты"
+                ],
+                "summary": "QR-код билета текущего пользователя в формате PNG",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "QR-код",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "201": {
+                        "description": "Билет не найден",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/feed": {
+            "get": {
+                "description": "Мероприятия ранжируются по навыкам пользователя, его сообществам, прошлым посещениям,\nзаписям знакомых и расстоянию до площадки, reasons объясняет оценку.\nМероприятия, на которые пользователь уже записался или которые скрыл, не показываются.\nЛента обновляется раз в несколько минут.",
+                "tags": [
+                    "Лента"
+                ],
+                "summary": "Персональная лента предстоящих мероприятий",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Координаты пользователя в формате lat,lon",
+                        "name": "near",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Лента",
+                        "schema": {
+                            "$ref": "#/definitions/rest.FeedOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении ленты",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/feed/{id}/not-interested": {
+            "post": {
+                "description": "Мероприятие пропадает из ленты, другие мероприятия того же сообщества и организатора опускаются ниже.",
+                "tags": [
+                    "Лента"
+                ],
+                "summary": "Отметка «не интересно»",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Мероприятие скрыто",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Мероприятие не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Лента"
+                ],
+                "summary": "Отмена отметки «не интересно»",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор мероприятия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Мероприятие снова в ленте",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Мероприятие не было скрыто",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groups": {
+            "get": {
+                "tags": [
+                    "Сообщества"
+                ],
+                "summary": "Список сообществ",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сообщества",
+                        "schema": {
+                            "$ref": "#/definitions/rest.GroupsOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Внутренняя ошибка сервиса",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "tags": [
+                    "Сообщества"
+                ],
+                "summary": "Создание сообщества",
+                "parameters": [
+                    {
+                        "description": "Сообщество",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.groupInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сообщество создано",
+                        "schema": {
+                            "$ref": "#/definitions/rest.IdResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при создании сообщества",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groups/{id}": {
+            "get": {
+                "tags": [
+                    "Сообщества"
+                ],
+                "summary": "Сообщество по идентификатору",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор сообщества",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сообщество",
+                        "schema": {
+                            "$ref": "#/definitions/rest.GroupOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Сообщество не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groups/{id}/comments": {
+            "get": {
+                "tags": [
+                    "Обсуждения"
+                ],
+                "summary": "Треды обсуждения сообщества с ответами, новые сначала",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор сообщества",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество тредов (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Треды",
+                        "schema": {
+                            "$ref": "#/definitions/rest.CommentsOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении обсуждения",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Текст в Markdown, HTML очищается. Упомянутые через @username пользователи получают уведомление.",
+                "tags": [
+                    "Обсуждения"
+                ],
+                "summary": "Комментарий или ответ в обсуждении сообщества",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор сообщества",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Комментарий",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.commentInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Комментарий добавлен",
+                        "schema": {
+                            "$ref": "#/definitions/rest.IdResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при добавлении комментария",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groups/{id}/follow": {
+            "post": {
+                "description": "Новые мероприятия сообщества появятся в ленте подписок. Вступать в сообщество для этого не нужно.",
+                "tags": [
+                    "Подписки"
+                ],
+                "summary": "Подписка на сообщество",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор сообщества",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписка оформлена",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Сообщество не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Подписки"
+                ],
+                "summary": "Отписка от сообщества",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор сообщества",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписка отменена",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Подписки нет",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groups/{id}/followers": {
+            "get": {
+                "tags": [
+                    "Подписки"
+                ],
+                "summary": "Подписчики сообщества, новые сначала",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор сообщества",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписчики",
+                        "schema": {
+                            "$ref": "#/definitions/rest.FollowsOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении списка",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groups/{id}/invitations": {
+            "get": {
+                "description": "Только для владельца сообщества.",
+                "tags": [
+                    "Приглашения"
+                ],
+                "summary": "Приглашения и ссылки-приглашения в сообщество",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор сообщества",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Приглашения, новые первыми",
+                        "schema": {
+                            "$ref": "#/definitions/rest.InvitationsOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении приглашений",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Только для владельца сообщества. Нужно указать либо username, либо email. Пользователь\nполучает уведомление, а на почту приходит код, который принимается при регистрации или после входа.",
+                "tags": [
+                    "Приглашения"
+                ],
+                "summary": "Личное приглашение в сообщество",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор сообщества",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Кого пригласить",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.inviteInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Приглашение отправлено",
+                        "schema": {
+                            "$ref": "#/definitions/rest.InvitationOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при отправке приглашения",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groups/{id}/invite-links": {
+            "post": {
+                "description": "Только для владельца сообщества. Ссылкой может воспользоваться любой, пока она не истекла\nи не исчерпано число использований.",
+                "tags": [
+                    "Приглашения"
+                ],
+                "summary": "Создание ссылки-приглашения в сообщество",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор сообщества",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Ограничения ссылки",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.inviteLinkInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ссылка создана",
+                        "schema": {
+                            "$ref": "#/definitions/rest.InvitationOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при создании ссылки",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groups/{id}/members": {
+            "post": {
+                "tags": [
+                    "Сообщества"
+                ],
+                "summary": "Вступление текущего пользователя в сообщество",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор сообщества",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пользователь вступил в сообщество",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при вступлении в сообщество",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Сообщества"
+                ],
+                "summary": "Выход текущего пользователя из сообщества",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор сообщества",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пользователь вышел из сообщества",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при выходе из сообщества",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groups/{id}/webhooks": {
+            "get": {
+                "tags": [
+                    "Вебхуки"
+                ],
+                "summary": "Вебхуки сообщества. Доступно владельцу сообщества",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор сообщества",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Вебхуки",
+                        "schema": {
+                            "$ref": "#/definitions/rest.WebhooksOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Сообщество не найдено или нет прав",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "События: event.published, rsvp.created, event.cancelled. Секрет возвращается только при создании.\nКаждый запрос содержит заголовки X-DevMeets-Event, X-DevMeets-Delivery, X-DevMeets-Timestamp\nи X-DevMeets-Signature: sha256=hex(HMAC-SHA256(секрет, \"\u003ctimestamp\u003e.\u003cтело запроса\u003e\")).\nОтвет не из 2xx считается ошибкой, доставка повторяется с нарастающей задержкой.\nПосле серии ошибок подряд (по умолчанию 20) вебхук отключается, а владелец получает уведомление.",
+                "tags": [
+                    "Вебхуки"
+                ],
+                "summary": "Регистрация вебхука сообщества. Доступно владельцу сообщества",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор сообщества",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Вебхук",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.webhookInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Вебхук создан",
+                        "schema": {
+                            "$ref": "#/definitions/rest.WebhookOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при создании вебхука",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/invitations": {
+            "get": {
+                "tags": [
+                    "Приглашения"
+                ],
+                "summary": "Приглашения, которые ждут ответа текущего пользователя",
+                "responses": {
+                    "200": {
+                        "description": "Приглашения, новые первыми",
+                        "schema": {
+                            "$ref": "#/definitions/rest.InvitationsOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении приглашений",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/invitations/{id}": {
+            "delete": {
+                "description": "Только для владельца сообщества или организатора. Принявшие приглашение остаются\nучастниками, но после выхода не смогут вернуться по нему.",
+                "tags": [
+                    "Приглашения"
+                ],
+                "summary": "Отзыв приглашения или ссылки-приглашения",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор приглашения",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Приглашение отозвано",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при отзыве приглашения",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/invites/{token}": {
+            "get": {
+                "description": "Авторизация не нужна: по ссылке можно узнать, куда она приглашает, до входа или регистрации.",
+                "tags": [
+                    "Приглашения"
+                ],
+                "summary": "Куда ведёт приглашение",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Секрет из ссылки или код из письма",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Приглашение",
+                        "schema": {
+                            "$ref": "#/definitions/rest.InvitePreviewOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Приглашение не найдено, отозвано или истекло",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/invites/{token}/accept": {
+            "post": {
+                "description": "Пользователь сразу вступает в сообщество или записывается на мероприятие.\nЛичное приглашение может принять только тот, кому оно адресовано.",
+                "tags": [
+                    "Приглашения"
+                ],
+                "summary": "Принятие приглашения текущим пользователем",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Секрет из ссылки или код из письма",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Приглашение принято",
+                        "schema": {
+                            "$ref": "#/definitions/rest.InvitationOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при принятии приглашения",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/matches": {
+            "get": {
+                "description": "Кандидаты оцениваются по навыкам, уровню, городу и общим сообществам, reasons объясняет оценку.\nПодходят только участники с общей целью; те, с кем уже есть заявка, и заблокированные не показываются.",
+                "tags": [
+                    "Знакомства"
+                ],
+                "summary": "С кем стоит познакомиться",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Цель: mentorship, pair_programming или cofounding",
+                        "name": "goal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подборка",
+                        "schema": {
+                            "$ref": "#/definitions/rest.MatchesOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Пользователь не участвует в подборе",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/matches/requests": {
+            "get": {
+                "tags": [
+                    "Знакомства"
+                ],
+                "summary": "Входящие или отправленные заявки, новые сначала",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Входящие заявки (по умолчанию отправленные)",
+                        "name": "incoming",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Статус: pending, accepted или declined",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Заявки",
+                        "schema": {
+                            "$ref": "#/definitions/rest.MatchRequestsOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении заявок",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Цель должна быть у обоих. Получатель получает уведомление match_request.\nНе больше 20 заявок в сутки, иначе too_many_requests.",
+                "tags": [
+                    "Знакомства"
+                ],
+                "summary": "Заявка на знакомство с участником подбора",
+                "parameters": [
+                    {
+                        "description": "Заявка",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.matchRequestInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Заявка отправлена",
+                        "schema": {
+                            "$ref": "#/definitions/rest.IdResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при отправке заявки",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/matches/requests/{id}/accept": {
+            "post": {
+                "description": "Открывает личный диалог с автором заявки, его идентификатор возвращается в conversation_id.",
+                "tags": [
+                    "Знакомства"
+                ],
+                "summary": "Принятие заявки на знакомство",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор заявки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Заявка принята",
+                        "schema": {
+                            "$ref": "#/definitions/rest.MatchRequestOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Заявка не найдена, уже рассмотрена или нет прав",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/matches/requests/{id}/decline": {
+            "post": {
+                "tags": [
+                    "Знакомства"
+                ],
+                "summary": "Отклонение заявки на знакомство",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор заявки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Заявка отклонена",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Заявка не найдена, уже рассмотрена или нет прав",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/matches/settings": {
+            "get": {
+                "tags": [
+                    "Знакомства"
+                ],
+                "summary": "Цели, с которыми текущий пользователь участвует в подборе собеседников",
+                "responses": {
+                    "200": {
+                        "description": "Цели",
+                        "schema": {
+                            "$ref": "#/definitions/rest.MatchSettingsOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Пользователь не участвует в подборе",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Цели: mentorship (менторство), pair_programming (парное программирование), cofounding (совместный проект).\nПока пользователь не согласился, он не видит подборку и не попадает в чужие.",
+                "tags": [
+                    "Знакомства"
+                ],
+                "summary": "Согласие участвовать в подборе собеседников и цели знакомства",
+                "parameters": [
+                    {
+                        "description": "Цели",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.matchSettingsInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Настройки сохранены",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Неизвестная цель",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Знакомства"
+                ],
+                "summary": "Выход из подбора собеседников",
+                "responses": {
+                    "200": {
+                        "description": "Пользователь исключён из подбора",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Пользователь не участвует в подборе",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/moderation/log": {
+            "get": {
+                "tags": [
+                    "Модерация"
+                ],
+                "summary": "Журнал решений модераторов, новые сначала",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Только решения по содержимому этого пользователя",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Журнал",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ModerationLogOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Нет прав",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/moderation/reports": {
+            "get": {
+                "description": "Доступно модераторам и администраторам.",
+                "tags": [
+                    "Модерация"
+                ],
+                "summary": "Очередь жалоб для модераторов, старые сначала",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Статус: open (по умолчанию), dismissed или actioned",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тип содержимого: user, event, comment или message",
+                        "name": "target_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Жалобы",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ReportsOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Нет прав",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/moderation/reports/{id}": {
+            "get": {
+                "tags": [
+                    "Модерация"
+                ],
+                "summary": "Жалоба вместе с текстом содержимого на момент подачи",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор жалобы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Жалоба",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ReportOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Жалоба не найдена или нет прав",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/moderation/reports/{id}/resolve": {
+            "post": {
+                "description": "action: dismiss (отклонить), hide (скрыть содержимое), warn (предупредить автора),\nsuspend (заблокировать автора на suspend_days дней, 0 - бессрочно).\nРешение закрывает все открытые жалобы на то же содержимое и записывается в журнал модерации.\nМодераторов и администраторов заблокировать нельзя.",
+                "tags": [
+                    "Модерация"
+                ],
+                "summary": "Решение модератора по жалобе",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор жалобы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Решение",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.resolveReportInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Запись журнала модерации",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ModerationActionOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Жалоба не найдена, уже рассмотрена или нет прав",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notifications": {
+            "get": {
+                "description": "Однотипные уведомления (например, новые обсуждения в сообществе) склеиваются, count показывает их число.",
+                "tags": [
+                    "Уведомления"
+                ],
+                "summary": "Уведомления текущего пользователя, новые сначала, и число непрочитанных",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Только непрочитанные",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Уведомления",
+                        "schema": {
+                            "$ref": "#/definitions/rest.NotificationsOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении уведомлений",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notifications/preferences": {
+            "get": {
+                "description": "in_app - только в центре уведомлений, email - ещё и в периодической email-сводке, none - не доставлять.",
+                "tags": [
+                    "Уведомления"
+                ],
+                "summary": "Каналы доставки по типам уведомлений",
+                "responses": {
+                    "200": {
+                        "description": "Настройки",
+                        "schema": {
+                            "$ref": "#/definitions/rest.NotificationPreferencesOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Внутренняя ошибка сервиса",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "tags": [
+                    "Уведомления"
+                ],
+                "summary": "Изменение каналов доставки для указанных типов уведомлений",
+                "parameters": [
+                    {
+                        "description": "Настройки",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.notificationPreferencesInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Настройки после изменения",
+                        "schema": {
+                            "$ref": "#/definitions/rest.NotificationPreferencesOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при изменении настроек",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notifications/read-all": {
+            "post": {
+                "tags": [
+                    "Уведомления"
+                ],
+                "summary": "Отметка всех уведомлений прочитанными",
+                "responses": {
+                    "200": {
+                        "description": "Число отмеченных уведомлений",
+                        "schema": {
+                            "$ref": "#/definitions/rest.MarkAllReadOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Внутренняя ошибка сервиса",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notifications/{id}/read": {
+            "post": {
+                "tags": [
+                    "Уведомления"
+                ],
+                "summary": "Отметка уведомления прочитанным",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор уведомления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Уведомление прочитано",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Уведомление не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/orders": {
+            "get": {
+                "tags": [
+                    "Билеты"
+                ],
+                "summary": "Заказы билетов текущего пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Заказы, новые первыми",
+                        "schema": {
+                            "$ref": "#/definitions/rest.OrdersOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении заказов",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/orders/{id}": {
+            "get": {
+                "description": "Доступен покупателю и организатору мероприятия.",
+                "tags": [
+                    "Билеты"
+                ],
+                "summary": "Заказ билета",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор заказа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Заказ",
+                        "schema": {
+                            "$ref": "#/definitions/rest.OrderOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении заказа",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/orders/{id}/refund": {
+            "post": {
+                "description": "Только для организатора. Билет снова поступает в продажу, запись покупателя на мероприятие отменяется.",
+                "tags": [
+                    "Билеты"
+                ],
+                "summary": "Возврат денег за оплаченный заказ",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор заказа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Деньги возвращены",
+                        "schema": {
+                            "$ref": "#/definitions/rest.OrderOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при возврате",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payments/webhook": {
+            "post": {
+                "description": "Вызывается платёжным провайдером. Извещение эквайринга без верной подписи X-Payment-Signature\nотклоняется. Повторные извещения ничего не меняют.",
+                "tags": [
+                    "Билеты"
+                ],
+                "summary": "Приём извещений об исходе платежей",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "HMAC-SHA256 тела запроса в hex",
+                        "name": "X-Payment-Signature",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Извещение принято",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Неверная подпись или неизвестный платёж",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/personal-profile": {
+            "get": {
+                "tags": [
+                    "Пользователь"
+                ],
+                "summary": "Профиль текущего пользователя",
+                "responses": {
+                    "200": {
+                        "description": "Параметры текущего пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/rest.OkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Внутренняя ошибка сервиса",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "tags": [
+                    "Пользователь"
+                ],
+                "summary": "Изменение профиля текущего пользователя",
+                "parameters": [
+                    {
+                        "description": "Профиль",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.profileInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Профиль изменён",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при изменении профиля",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Нужен пароль. Аккаунт удаляется по истечении срока ожидания (по умолчанию 30 дней), все\nвыданные токены сразу перестают действовать. Вход до deletion_scheduled_at отменяет\nудаление. После удаления почта, профиль, записи\nна мероприятия, подписки и уведомления стираются, а мероприятия, комментарии, сообщения\nи отзывы остаются без имени автора. Модератору и администратору сначала нужно снять роль.",
+                "tags": [
+                    "Пользователь"
+                ],
+                "summary": "Удаление аккаунта текущего пользователя",
+                "parameters": [
+                    {
+                        "description": "Пароль",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.deleteAccountInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Удаление назначено",
+                        "schema": {
+                            "$ref": "#/definitions/rest.DeleteAccountOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Неверный пароль или нет прав",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/personal-profile/email": {
+            "post": {
+                "description": "Нужен пароль. На новый адрес приходит код подтверждения, на текущий - предупреждение.\nПока код не подтверждён, вход выполняется по текущему адресу. Новый запрос отменяет прежний.",
+                "tags": [
+                    "Пользователь"
+                ],
+                "summary": "Запрос смены почты текущего пользователя",
+                "parameters": [
+                    {
+                        "description": "Пароль и новая почта",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.emailChangeInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Код подтверждения отправлен",
+                        "schema": {
+                            "$ref": "#/definitions/rest.EmailChangeOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Неверный пароль или адрес уже занят",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/personal-profile/export": {
+            "get": {
+                "tags": [
+                    "Пользователь"
+                ],
+                "summary": "Последняя выгрузка данных текущего пользователя",
+                "responses": {
+                    "200": {
+                        "description": "Выгрузка",
+                        "schema": {
+                            "$ref": "#/definitions/rest.DataExportOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Выгрузки нет или ссылка истекла",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Архив собирается в фоне: профиль, записи на мероприятия, комментарии и сообщения в JSON.\nКогда он готов, приходит уведомление, а в выгрузке появляется download_url. Пока предыдущая\nвыгрузка собирается, возвращается она.",
+                "tags": [
+                    "Пользователь"
+                ],
+                "summary": "Запрос архива с данными текущего пользователя",
+                "responses": {
+                    "200": {
+                        "description": "Выгрузка запрошена",
+                        "schema": {
+                            "$ref": "#/definitions/rest.DataExportOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Внутренняя ошибка сервиса",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/personal-profile/password": {
+            "put": {
+                "description": "Нужен текущий пароль. Все выданные ранее токены перестают действовать, в ответе новый токен.",
+                "tags": [
+                    "Авторизация"
+                ],
+                "summary": "Смена пароля текущего пользователя",
+                "parameters": [
+                    {
+                        "description": "Текущий и новый пароль",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.changePasswordInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пароль изменён",
+                        "schema": {
+                            "$ref": "#/definitions/rest.SignInOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Неверный текущий пароль",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reports": {
+            "post": {
+                "description": "Жалобу разбирают модераторы. Пожаловаться на сообщение может только участник диалога.\nНе больше 10 жалоб в час, иначе too_many_requests.",
+                "tags": [
+                    "Модерация"
+                ],
+                "summary": "Жалоба на пользователя, мероприятие, комментарий или сообщение",
+                "parameters": [
+                    {
+                        "description": "Жалоба",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.reportInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Жалоба принята",
+                        "schema": {
+                            "$ref": "#/definitions/rest.IdResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Содержимое не найдено или жалоба уже подана",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/search": {
+            "get": {
+                "description": "Названия весят больше описаний, опечатки в названиях и именах находятся по триграммам.\nФильтр, неприменимый к типу (даты для сообществ и разработчиков, навык для мероприятий и сообществ), исключает этот тип из выдачи.",
+                "tags": [
+                    "Поиск"
+                ],
+                "summary": "Полнотекстовый поиск по мероприятиям, сообществам и разработчикам",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поисковый запрос",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Типы через запятую: event, group, developer",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Город",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Мероприятия с даты (RFC 3339)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Мероприятия до даты (RFC 3339)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Навык разработчика",
+                        "name": "skill",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результаты поиска, сниппеты размечены тегом mark",
+                        "schema": {
+                            "$ref": "#/definitions/rest.SearchOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при поиске",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sign-in": {
+            "post": {
+                "tags": [
+                    "Авторизация"
+                ],
+                "summary": "Авторизация пользователя",
+                "parameters": [
+                    {
+                        "description": "Почта и Пароль",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.signInUpInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешная авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/rest.SignInOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при попытке авторизоваться",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sign-up": {
+            "post": {
+                "description": "Если пользователь пришёл по приглашению, invite_token применяет его сразу после\nрегистрации. Приглашения, отправленные на почту, принимаются только по коду из письма.",
+                "tags": [
+                    "Регистрация"
+                ],
+                "summary": "Регистрация нового пользователя",
+                "parameters": [
+                    {
+                        "description": "Почта, пароль и код приглашения",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.signUpInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешная регистрация нового пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/rest.SignUpOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при попытке зарегистрироваться",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/speakers": {
+            "post": {
+                "tags": [
+                    "Программа"
+                ],
+                "summary": "Создание карточки докладчика: своей или внешнего гостя",
+                "parameters": [
+                    {
+                        "description": "Докладчик",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.speakerInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Докладчик создан",
+                        "schema": {
+                            "$ref": "#/definitions/rest.IdResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при создании докладчика",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/speakers/{id}": {
+            "get": {
+                "tags": [
+                    "Программа"
+                ],
+                "summary": "Карточка докладчика",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор докладчика",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Докладчик",
+                        "schema": {
+                            "$ref": "#/definitions/rest.SpeakerOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Докладчик не найден",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "tags": [
+                    "Программа"
+                ],
+                "summary": "Изменение карточки докладчика (автор карточки или сам докладчик)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор докладчика",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Докладчик",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.speakerInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Докладчик изменён",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при изменении докладчика",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stream": {
+            "get": {
+                "description": "Пользователь получает свои уведомления (notification), счётчик пришедших на свои мероприятия (attendance),\nличные сообщения (message) и отметки о прочтении (message_read),\nа по темам - число записавшихся (rsvp_count) и изменения обсуждений (comment).\nРаз в 25 секунд приходит heartbeat: комментарий SSE или ping-фрейм WebSocket.\nПосле переподключения пропущенные события досылаются по Last-Event-ID (или параметру last_event_id).\nEventSource и WebSocket в браузере не передают заголовки, поэтому токен можно передать параметром access_token.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Поток событий"
+                ],
+                "summary": "Поток событий в реальном времени: SSE, а при заголовке Upgrade - WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Темы через запятую: event:\u003cid\u003e, group:\u003cid\u003e",
+                        "name": "topics",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор последнего полученного события",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "То же, что Last-Event-ID, для WebSocket",
+                        "name": "last_event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT, если нельзя передать заголовок Authorization",
+                        "name": "access_token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Событие",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StreamMessage"
+                        }
+                    },
+                    "201": {
+                        "description": "Неверные темы",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/talks/{id}/accept": {
+            "post": {
+                "tags": [
+                    "Доклады"
+                ],
+                "summary": "Принятие доклада в программу мероприятия (только организатор)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор доклада",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Доклад принят и добавлен в программу",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при принятии доклада",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/talks/{id}/reject": {
+            "post": {
+                "tags": [
+                    "Доклады"
+                ],
+                "summary": "Отклонение доклада (только организатор)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор доклада",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Доклад отклонён",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при отклонении доклада",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/talks/{id}/reviews": {
+            "get": {
+                "tags": [
+                    "Доклады"
+                ],
+                "summary": "Рецензии на доклад (организатор и рецензенты)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор доклада",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Рецензии",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ReviewsOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении рецензий",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "tags": [
+                    "Доклады"
+                ],
+                "summary": "Оценка доклада рецензентом",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор доклада",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Оценка и комментарий",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.reviewInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Рецензия сохранена",
+                        "schema": {
+                            "$ref": "#/definitions/rest.IdResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при сохранении рецензии",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/telegram/link": {
+            "delete": {
+                "tags": [
+                    "Telegram"
+                ],
+                "summary": "Отвязка Telegram-аккаунта",
+                "responses": {
+                    "200": {
+                        "description": "Аккаунт отвязан",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Аккаунт не привязан",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/telegram/link-code": {
+            "post": {
+                "description": "Код действует 15 минут. Его нужно отправить боту командой /link \u003cкод\u003e или открыть ссылку link.\nПосле привязки можно записываться на мероприятия кнопкой под анонсом в Telegram.\nНовый код отменяет прежний.",
+                "tags": [
+                    "Telegram"
+                ],
+                "summary": "Одноразовый код для привязки Telegram-аккаунта",
+                "responses": {
+                    "200": {
+                        "description": "Код привязки",
+                        "schema": {
+                            "$ref": "#/definitions/rest.TelegramLinkCodeOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Бот не настроен",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/telegram/webhook": {
+            "post": {
+                "description": "Вызывается серверами Telegram. Запрос без верного заголовка X-Telegram-Bot-Api-Secret-Token отклоняется.",
+                "tags": [
+                    "Telegram"
+                ],
+                "summary": "Приём обновлений от Telegram в режиме webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Секрет вебхука",
+                        "name": "X-Telegram-Bot-Api-Secret-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновление принято",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Неверный секрет или бот работает в режиме polling",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tickets/public-key": {
+            "get": {
+                "description": "Билет имеет вид DM1.\u003cclaims\u003e.\u003csignature\u003e: подпись Ed25519 строки \"DM1.\u003cclaims\u003e\", части в base64url.",
+                "tags": [
+                    "Билеты"
+                ],
+                "summary": "Публичный ключ Ed25519 для офлайн-проверки подписи билетов",
+                "responses": {
+                    "200": {
+                        "description": "Публичный ключ (сырые 32 байта в base64)",
+                        "schema": {
+                            "$ref": "#/definitions/rest.PublicKeyOkResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/follow": {
+            "post": {
+                "description": "Его новые мероприятия, принятые доклады и вступления в сообщества появятся в ленте подписок.\nПодписаться на того, с кем есть блокировка, нельзя.",
+                "tags": [
+                    "Подписки"
+                ],
+                "summary": "Подписка на пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписка оформлена",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Пользователь не найден или заблокирован",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Подписки"
+                ],
+                "summary": "Отписка от пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписка отменена",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Подписки нет",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/followers": {
+            "get": {
+                "tags": [
+                    "Подписки"
+                ],
+                "summary": "Подписчики пользователя, новые сначала",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписчики",
+                        "schema": {
+                            "$ref": "#/definitions/rest.FollowsOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении списка",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/following": {
+            "get": {
+                "tags": [
+                    "Подписки"
+                ],
+                "summary": "Пользователи, на которых подписан пользователь, новые подписки сначала",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписки",
+                        "schema": {
+                            "$ref": "#/definitions/rest.FollowsOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении списка",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/following/groups": {
+            "get": {
+                "tags": [
+                    "Подписки"
+                ],
+                "summary": "Сообщества, на которые подписан пользователь, новые подписки сначала",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сообщества",
+                        "schema": {
+                            "$ref": "#/definitions/rest.GroupFollowsOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при получении списка",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/venues": {
+            "get": {
+                "tags": [
+                    "Площадки"
+                ],
+                "summary": "Поиск площадок по названию или адресу, при заданном near - сортировка по расстоянию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Подстрока названия или адреса",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Координаты центра поиска: lat,lon",
+                        "name": "near",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Радиус поиска в километрах (по умолчанию 10)",
+                        "name": "radius_km",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Площадки",
+                        "schema": {
+                            "$ref": "#/definitions/rest.VenuesOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при поиске площадок",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "tags": [
+                    "Площадки"
+                ],
+                "summary": "Создание площадки для офлайн-мероприятий",
+                "parameters": [
+                    {
+                        "description": "Площадка",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.venueInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Площадка создана",
+                        "schema": {
+                            "$ref": "#/definitions/rest.IdResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при создании площадки",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/venues/{id}": {
+            "get": {
+                "tags": [
+                    "Площадки"
+                ],
+                "summary": "Площадка по идентификатору",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор площадки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Площадка",
+                        "schema": {
+                            "$ref": "#/definitions/rest.VenueOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Площадка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "tags": [
+                    "Площадки"
+                ],
+                "summary": "Изменение площадки (только автор)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор площадки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Площадка",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.venueInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Площадка изменена",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при изменении площадки",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}": {
+            "put": {
+                "description": "active=true включает вебхук, отключённый из-за ошибок, и сбрасывает счётчик ошибок.",
+                "tags": [
+                    "Вебхуки"
+                ],
+                "summary": "Изменение адреса, событий и состояния вебхука",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Вебхук",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.webhookUpdateInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Вебхук изменён",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Ошибка при изменении вебхука",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Вебхуки"
+                ],
+                "summary": "Удаление вебхука вместе с журналом доставок",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Вебхук удалён",
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Вебхук не найден или нет прав",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}/deliveries": {
+            "get": {
+                "tags": [
+                    "Вебхуки"
+                ],
+                "summary": "Попытки доставки событий на вебхук, новые сначала",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Доставки",
+                        "schema": {
+                            "$ref": "#/definitions/rest.WebhookDeliveriesOkResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Вебхук не найден или нет прав",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "rest.AccountStateOkResponse": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "$ref": "#/definitions/rest.AccountStateResponse"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.AccountStateResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "email@gmail.com"
+                },
+                "email_duplicate": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "events_organized": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "groups_owned": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "moderation_actions": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Иван Петров"
+                },
+                "open_reports": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "role": {
+                    "type": "string",
+                    "example": "user"
+                },
+                "suspended": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "suspended_at": {
+                    "type": "string",
+                    "example": "2024-02-20T13:00:00+03:00"
+                },
+                "suspended_until": {
+                    "type": "string",
+                    "example": "2024-02-27T13:00:00+03:00"
+                },
+                "suspension_reason": {
+                    "type": "string",
+                    "example": "Спам в обсуждениях"
+                },
+                "telegram_linked": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "username": {
+                    "type": "string",
+                    "example": "ivan_petrov"
+                }
+            }
+        },
+        "rest.ActivitiesOkResponse": {
+            "type": "object",
+            "properties": {
+                "activities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.ActivityResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "MzE"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.ActivityResponse": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "actor_name": {
+                    "type": "string",
+                    "example": "Иван Петров"
+                },
+                "actor_username": {
+                    "type": "string",
+                    "example": "gopher"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-20T12:00:00+03:00"
+                },
+                "event_id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "event_title": {
+                    "type": "string",
+                    "example": "Go Meetup #12"
+                },
+                "group_id": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "group_name": {
+                    "type": "string",
+                    "example": "Moscow Gophers"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 31
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "event_created"
+                },
+                "talk_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "talk_title": {
+                    "type": "string",
+                    "example": "Профилирование Go-сервисов"
+                }
+            }
+        },
+        "rest.AdminUserResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "email@gmail.com"
+                },
+                "email_duplicate": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Иван Петров"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "user"
+                },
+                "suspended": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "suspended_at": {
+                    "type": "string",
+                    "example": "2024-02-20T13:00:00+03:00"
+                },
+                "suspended_until": {
+                    "type": "string",
+                    "example": "2024-02-27T13:00:00+03:00"
+                },
+                "suspension_reason": {
+                    "type": "string",
+                    "example": "Спам в обсуждениях"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "ivan_petrov"
+                }
+            }
+        },
+        "rest.AdminUsersOkResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.AdminUserResponse"
+                    }
+                }
+            }
+        },
+        "rest.AgendaOkResponse": {
+            "type": "object",
+            "properties": {
+                "agenda": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.AgendaSlotResponse"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.AgendaSlotResponse": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": ""
+                },
+                "ends_at": {
+                    "type": "string",
+                    "example": "2024-03-01T19:30:00+03:00"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "talk"
+                },
+                "position": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "slides_url": {
+                    "type": "string",
+                    "example": "https://example.com/slides.pdf"
+                },
+                "speakers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.SpeakerResponse"
+                    }
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2024-03-01T19:00:00+03:00"
+                },
+                "talk_id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Профилирование Go-сервисов"
+                },
+                "video_url": {
+                    "type": "string",
+                    "example": "https://youtu.be/xxxx"
+                }
+            }
+        },
+        "rest.AttendanceOkResponse": {
+            "type": "object",
+            "properties": {
+                "attendance": {
+                    "$ref": "#/definitions/rest.AttendanceResponse"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.AttendanceResponse": {
+            "type": "object",
+            "properties": {
+                "checked_in": {
+                    "type": "integer",
+                    "example": 87
+                },
+                "going": {
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "rest.AuditEventResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "user.role_changed"
+                },
+                "actor_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-20T13:00:00+03:00"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "host/abcdef-000001"
+                },
+                "target_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "target_type": {
+                    "type": "string",
+                    "example": "user"
+                }
+            }
+        },
+        "rest.AuditEventsOkResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.AuditEventResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "MTIw"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.BlockResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-20T12:00:00+03:00"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Иван Петров"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "username": {
+                    "type": "string",
+                    "example": "spammer"
+                }
+            }
+        },
+        "rest.BlocksOkResponse": {
+            "type": "object",
+            "properties": {
+                "blocks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.BlockResponse"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.CFPOkResponse": {
+            "type": "object",
+            "properties": {
+                "cfp": {
+                    "$ref": "#/definitions/rest.CFPResponse"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.CFPResponse": {
+            "type": "object",
+            "properties": {
+                "deadline": {
+                    "type": "string",
+                    "example": "2024-02-15T23:59:59+03:00"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Ищем доклады про Go в продакшене"
+                },
+                "event_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "is_open": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "rest.CheckInOkResponse": {
+            "type": "object",
+            "properties": {
+                "attendance": {
+                    "$ref": "#/definitions/rest.AttendanceResponse"
+                },
+                "checked_in_at": {
+                    "type": "string",
+                    "example": "2024-03-01T18:55:00+03:00"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 123
+                }
+            }
+        },
+        "rest.CommentHistoryOkResponse": {
+            "type": "object",
+            "properties": {
+                "revisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.CommentRevisionResponse"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.CommentResponse": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "body": {
+                    "type": "string",
+                    "example": "Есть ли рядом **парковка**?"
+                },
+                "body_html": {
+                    "type": "string",
+                    "example": "\u003cp\u003eЕсть ли рядом \u003cstrong\u003eпарковка\u003c/strong\u003e?\u003c/p\u003e"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-20T12:00:00+03:00"
+                },
+                "deleted": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "edited_at": {
+                    "type": "string",
+                    "example": "2024-02-20T12:05:00+03:00"
+                },
+                "hidden": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "parent_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "replies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.CommentResponse"
+                    }
+                }
+            }
+        },
+        "rest.CommentRevisionResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "Есть ли рядом парковка?"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-20T12:05:00+03:00"
+                }
+            }
+        },
+        "rest.CommentsOkResponse": {
+            "type": "object",
+            "properties": {
+                "comments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.CommentResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "MTI"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.ConversationMemberResponse": {
+            "type": "object",
+            "properties": {
+                "joined_at": {
+                    "type": "string",
+                    "example": "2024-02-20T12:00:00+03:00"
+                },
+                "last_read_message_id": {
+                    "type": "integer",
+                    "example": 127
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Иван Петров"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "username": {
+                    "type": "string",
+                    "example": "gopher"
+                }
+            }
+        },
+        "rest.ConversationOkResponse": {
+            "type": "object",
+            "properties": {
+                "conversation": {
+                    "$ref": "#/definitions/rest.ConversationResponse"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.ConversationResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-20T12:00:00+03:00"
+                },
+                "created_by": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "direct"
+                },
+                "last_message": {
+                    "$ref": "#/definitions/rest.MessageResponse"
+                },
+                "last_message_at": {
+                    "type": "string",
+                    "example": "2024-02-20T12:05:00+03:00"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.ConversationMemberResponse"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Организаторы Go meetup"
+                },
+                "unread_count": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "rest.ConversationsOkResponse": {
+            "type": "object",
+            "properties": {
+                "conversations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.ConversationResponse"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.DataExportOkResponse": {
+            "type": "object",
+            "properties": {
+                "export": {
+                    "$ref": "#/definitions/rest.DataExportResponse"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.DataExportResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-20T13:00:00+03:00"
+                },
+                "download_url": {
+                    "type": "string",
+                    "example": "/api/v1/data-exports/3f9a..."
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-02-22T13:01:00+03:00"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "ready_at": {
+                    "type": "string",
+                    "example": "2024-02-20T13:01:00+03:00"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ready"
+                }
+            }
+        },
+        "rest.DeleteAccountOkResponse": {
+            "type": "object",
+            "properties": {
+                "deletion_scheduled_at": {
+                    "type": "string",
+                    "example": "2024-03-21T13:00:00+03:00"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.EmailChangeOkResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-02-21T13:00:00+03:00"
+                },
+                "new_email": {
+                    "type": "string",
+                    "example": "new-email@gmail.com"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.ErrResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "wrong_params, forbidden, not_found, conflict, too_many_requests, internal_server_error"
+                }
+            }
+        },
+        "rest.EventFeedbackOkResponse": {
+            "type": "object",
+            "properties": {
+                "comments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.FeedbackCommentResponse"
+                    }
+                },
+                "rating": {
+                    "$ref": "#/definitions/rest.RatingSummaryResponse"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "talks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.TalkRatingResponse"
+                    }
+                }
+            }
+        },
+        "rest.EventOkResponse": {
+            "type": "object",
+            "properties": {
+                "event": {
+                    "$ref": "#/definitions/rest.EventResponse"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.EventResponse": {
+            "type": "object",
+            "properties": {
+                "agenda": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.AgendaSlotResponse"
+                    }
+                },
+                "cancelled_at": {
+                    "type": "string",
+                    "example": "2024-02-25T10:00:00+03:00"
+                },
+                "city": {
+                    "type": "string",
+                    "example": "Москва"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Доклады про конкурентность в Go"
+                },
+                "distance_km": {
+                    "type": "number",
+                    "example": 1.7
+                },
+                "ends_at": {
+                    "type": "string",
+                    "example": "2024-03-01T22:00:00+03:00"
+                },
+                "group_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "organizer_id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "private": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2024-03-01T19:00:00+03:00"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Go meetup #12"
+                },
+                "venue_id": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "rest.EventsOkResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.EventResponse"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.FeedItemResponse": {
+            "type": "object",
+            "properties": {
+                "event": {
+                    "$ref": "#/definitions/rest.EventResponse"
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Мероприятие вашего сообщества",
+                        "Идут ваши знакомые: 2"
+                    ]
+                },
+                "score": {
+                    "type": "number",
+                    "example": 8.5
+                }
+            }
+        },
+        "rest.FeedOkResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.FeedItemResponse"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.FeedbackCommentResponse": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "example": "Отличные доклады, хотелось бы больше времени на вопросы"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-03-02T10:00:00+03:00"
+                },
+                "rating": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 123
+                }
+            }
+        },
+        "rest.FollowResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-20T12:00:00+03:00"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Иван Петров"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "username": {
+                    "type": "string",
+                    "example": "gopher"
+                }
+            }
+        },
+        "rest.FollowsOkResponse": {
+            "type": "object",
+            "properties": {
+                "follows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.FollowResponse"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.GroupFollowResponse": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "Москва"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-20T12:00:00+03:00"
+                },
+                "group_id": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Moscow Gophers"
+                }
+            }
+        },
+        "rest.GroupFollowsOkResponse": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.GroupFollowResponse"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.GroupOkResponse": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "$ref": "#/definitions/rest.GroupResponse"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.GroupResponse": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "Москва"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-10T12:00:00+03:00"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Сообщество Go-разработчиков Москвы"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Moscow Gophers"
+                },
+                "owner_id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "private": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "rest.GroupsOkResponse": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.GroupResponse"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.IdResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.InvitationOkResponse": {
+            "type": "object",
+            "properties": {
+                "invitation": {
+                    "$ref": "#/definitions/rest.InvitationResponse"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.InvitationResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-20T13:00:00+03:00"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "friend@gmail.com"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-03-01T00:00:00+03:00"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "invite_url": {
+                    "type": "string",
+                    "example": "/api/v1/invites/9f2c4e..."
+                },
+                "invitee_id": {
+                    "type": "integer",
+                    "example": 456
+                },
+                "inviter_id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "max_uses": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "revoked_at": {
+                    "type": "string",
+                    "example": "2024-02-25T10:00:00+03:00"
+                },
+                "subject_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "subject_title": {
+                    "type": "string",
+                    "example": "Moscow Gophers"
+                },
+                "subject_type": {
+                    "type": "string",
+                    "example": "group"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "9f2c4e..."
+                },
+                "uses": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "rest.InvitationsOkResponse": {
+            "type": "object",
+            "properties": {
+                "invitations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.InvitationResponse"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.InvitePreviewOkResponse": {
+            "type": "object",
+            "properties": {
+                "invite": {
+                    "$ref": "#/definitions/rest.InvitePreviewResponse"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.InvitePreviewResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-03-01T00:00:00+03:00"
+                },
+                "personal": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "subject_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "subject_title": {
+                    "type": "string",
+                    "example": "Moscow Gophers"
+                },
+                "subject_type": {
+                    "type": "string",
+                    "example": "group"
+                }
+            }
+        },
+        "rest.JobOkResponse": {
+            "type": "object",
+            "properties": {
+                "job": {
+                    "$ref": "#/definitions/rest.JobResponse"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.JobResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-20T11:00:00+03:00"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1024
+                },
+                "last_error": {
+                    "type": "string",
+                    "example": "dial tcp: connection refused"
+                },
+                "max_attempts": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "run_at": {
+                    "type": "string",
+                    "example": "2024-02-20T12:00:00+03:00"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "dead"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "email.send"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-02-20T12:00:05+03:00"
+                }
+            }
+        },
+        "rest.JobsOkResponse": {
+            "type": "object",
+            "properties": {
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.JobResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "MTAyNA"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.MarkAllReadOkResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.MatchRequestOkResponse": {
+            "type": "object",
+            "properties": {
+                "request": {
+                    "$ref": "#/definitions/rest.MatchRequestResponse"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.MatchRequestResponse": {
+            "type": "object",
+            "properties": {
+                "conversation_id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-20T12:00:00+03:00"
+                },
+                "goal": {
+                    "type": "string",
+                    "example": "pair_programming"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Привет! Хочу вместе покодить на Go по выходным"
+                },
+                "recipient_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "requester_id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "responded_at": {
+                    "type": "string",
+                    "example": "2024-02-20T13:00:00+03:00"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                }
+            }
+        },
+        "rest.MatchRequestsOkResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string",
+                    "example": "Mw"
+                },
+                "requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.MatchRequestResponse"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.MatchResponse": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "Москва"
+                },
+                "goal": {
+                    "type": "string",
+                    "example": "mentorship"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Иван Петров"
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Может стать вашим ментором: уровень senior",
+                        "Общие навыки: go"
+                    ]
+                },
+                "score": {
+                    "type": "number",
+                    "example": 7.5
+                },
+                "seniority": {
+                    "type": "string",
+                    "example": "senior"
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "go",
+                        "postgres"
+                    ]
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "username": {
+                    "type": "string",
+                    "example": "gopher"
+                }
+            }
+        },
+        "rest.MatchSettingsOkResponse": {
+            "type": "object",
+            "properties": {
+                "goals": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "mentorship",
+                        "pair_programming"
+                    ]
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.MatchesOkResponse": {
+            "type": "object",
+            "properties": {
+                "matches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.MatchResponse"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.MessageOkResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "$ref": "#/definitions/rest.MessageResponse"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "Привет! Увидимся на митапе?"
+                },
+                "conversation_id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-20T12:05:00+03:00"
+                },
+                "hidden": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 128
+                },
+                "sender_id": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "rest.MessagesOkResponse": {
+            "type": "object",
+            "properties": {
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.MessageResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "MTI4"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.ModerationActionOkResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/rest.ModerationActionResponse"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.ModerationActionResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "hide"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-20T13:00:00+03:00"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "moderator_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Реклама"
+                },
+                "report_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        9,
+                        10
+                    ]
+                },
+                "suspended_until": {
+                    "type": "string",
+                    "example": "2024-02-27T13:00:00+03:00"
+                },
+                "target_id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "target_type": {
+                    "type": "string",
+                    "example": "comment"
+                },
+                "target_user_id": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "rest.ModerationLogOkResponse": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.ModerationActionResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "NQ"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.NotificationPreferenceResponse": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string",
+                    "example": "in_app"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "group_discussion"
+                }
+            }
+        },
+        "rest.NotificationPreferencesOkResponse": {
+            "type": "object",
+            "properties": {
+                "preferences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.NotificationPreferenceResponse"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.NotificationResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "Ваш доклад добавлен в программу мероприятия «Go meetup #12»"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-20T11:00:00+03:00"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "read": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "read_at": {
+                    "type": "string",
+                    "example": "2024-02-20T12:00:00+03:00"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Доклад «Конкурентность в Go» принят"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "talk_accepted"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-02-20T11:30:00+03:00"
+                }
+            }
+        },
+        "rest.NotificationsOkResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string",
+                    "example": "NDI"
+                },
+                "notifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.NotificationResponse"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "unread_count": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "rest.OkResponse": {
+            "type": "object",
+            "properties": {
+                "profile": {
+                    "$ref": "#/definitions/rest.ProfileResponse"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.OrderOkResponse": {
+            "type": "object",
+            "properties": {
+                "order": {
+                    "$ref": "#/definitions/rest.OrderResponse"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.OrderResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 150000
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-20T13:00:00+03:00"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "event_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-02-20T13:15:00+03:00"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 31
+                },
+                "paid_at": {
+                    "type": "string",
+                    "example": "2024-02-20T13:05:00+03:00"
+                },
+                "payment_url": {
+                    "description": "PaymentUrl - страница оплаты, пока заказ не оплачен",
+                    "type": "string",
+                    "example": "https://pay.example.com/checkout/2d8f"
+                },
+                "refunded_at": {
+                    "type": "string",
+                    "example": "2024-02-22T10:00:00+03:00"
+                },
+                "status": {
+                    "description": "Status - pending, paid, refunded или expired",
+                    "type": "string",
+                    "example": "pending"
+                },
+                "ticket_type_id": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 123
+                }
+            }
+        },
+        "rest.OrdersOkResponse": {
+            "type": "object",
+            "properties": {
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.OrderResponse"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.ProfileResponse": {
+            "type": "object",
+            "properties": {
+                "bio": {
+                    "type": "string",
+                    "example": "Пишу на Go, люблю распределённые системы"
+                },
+                "city": {
+                    "type": "string",
+                    "example": "Москва"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "email@gmail.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Иван Петров"
+                },
+                "seniority": {
+                    "type": "string",
+                    "example": "senior"
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "go",
+                        "postgresql"
+                    ]
+                },
+                "username": {
+                    "type": "string",
+                    "example": "ivan_petrov"
+                }
+            }
+        },
+        "rest.PublicKeyOkResponse": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string",
+                    "example": "Ed25519"
+                },
+                "public_key": {
+                    "type": "string",
+                    "example": "MCowBQYDK2VwAyEA..."
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.RatingSummaryResponse": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "type": "number",
+                    "example": 4.6
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "distribution": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        0,
+                        1,
+                        2,
+                        10,
+                        29
+                    ]
+                }
+            }
+        },
+        "rest.ReadOkResponse": {
+            "type": "object",
+            "properties": {
+                "last_read_message_id": {
+                    "type": "integer",
+                    "example": 128
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.ReportOkResponse": {
+            "type": "object",
+            "properties": {
+                "report": {
+                    "$ref": "#/definitions/rest.ReportResponse"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.ReportResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-20T12:00:00+03:00"
+                },
+                "details": {
+                    "type": "string",
+                    "example": "Реклама курсов в каждом обсуждении"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 9
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "spam"
+                },
+                "reporter_id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "resolved_at": {
+                    "type": "string",
+                    "example": "2024-02-20T13:00:00+03:00"
+                },
+                "resolved_by": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "snapshot": {
+                    "type": "string",
+                    "example": "Лучшие курсы по Go со скидкой!"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "open"
+                },
+                "target_id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "target_type": {
+                    "type": "string",
+                    "example": "comment"
+                },
+                "target_user_id": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "rest.ReportsOkResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string",
+                    "example": "OQ"
+                },
+                "reports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.ReportResponse"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.ReviewResponse": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "example": "Хорошая тема, но слишком длинно"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-03T12:00:00+03:00"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "reviewer_id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "score": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "rest.ReviewsOkResponse": {
+            "type": "object",
+            "properties": {
+                "reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.ReviewResponse"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.SearchOkResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.SearchResultResponse"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.SearchResultResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "rank": {
+                    "type": "number",
+                    "example": 0.42
+                },
+                "snippet": {
+                    "description": "Snippet - фрагмент описания в HTML: текст экранирован, совпадения обёрнуты в mark",
+                    "type": "string",
+                    "example": "Доклады про \u003cmark\u003eконкурентность\u003c/mark\u003e в Go"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Go meetup #12"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "event"
+                }
+            }
+        },
+        "rest.SignInOkResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "adsghjyjh5effa234353ty..."
+                }
+            }
+        },
+        "rest.SignUpOkResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.SpeakerOkResponse": {
+            "type": "object",
+            "properties": {
+                "speaker": {
+                    "$ref": "#/definitions/rest.SpeakerResponse"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.SpeakerResponse": {
+            "type": "object",
+            "properties": {
+                "bio": {
+                    "type": "string",
+                    "example": "Backend-разработчик, 10 лет в Go"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Иван Петров"
+                },
+                "photo_url": {
+                    "type": "string",
+                    "example": "https://example.com/photo.jpg"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 123
+                }
+            }
+        },
+        "rest.StatusResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.StreamMessage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1024
+                },
+                "type": {
+                    "type": "string",
+                    "example": "rsvp_count"
+                }
+            }
+        },
+        "rest.TalkFeedbackOkResponse": {
+            "type": "object",
+            "properties": {
+                "comments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.FeedbackCommentResponse"
+                    }
+                },
+                "rating": {
+                    "$ref": "#/definitions/rest.RatingSummaryResponse"
+                },
+                "slot_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Конкурентность в Go"
+                }
+            }
+        },
+        "rest.TalkRatingResponse": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "type": "number",
+                    "example": 4.6
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "distribution": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        0,
+                        1,
+                        2,
+                        10,
+                        29
+                    ]
+                },
+                "slot_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Конкурентность в Go"
+                }
+            }
+        },
+        "rest.TalkResponse": {
+            "type": "object",
+            "properties": {
+                "abstract": {
+                    "type": "string",
+                    "example": "Как найти утечку памяти за 15 минут"
+                },
+                "avg_score": {
+                    "type": "number",
+                    "example": 4.5
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-01T12:00:00+03:00"
+                },
+                "duration": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "event_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "level": {
+                    "type": "string",
+                    "example": "intermediate"
+                },
+                "reviews_count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "speaker_id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "status": {
+                    "type": "string",
+                    "example": "submitted"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "go",
+                        "performance"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Профилирование Go-сервисов"
+                }
+            }
+        },
+        "rest.TalksOkResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "talks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.TalkResponse"
+                    }
+                }
+            }
+        },
+        "rest.TelegramLinkCodeOkResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "3f9a1c0be47d2a65"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-02-20T12:15:00+03:00"
+                },
+                "link": {
+                    "type": "string",
+                    "example": "https://t.me/dev_meets_bot?start=3f9a1c0be47d2a65"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.TicketOkResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "ticket": {
+                    "$ref": "#/definitions/rest.TicketResponse"
+                }
+            }
+        },
+        "rest.TicketResponse": {
+            "type": "object",
+            "properties": {
+                "event_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 17
+                },
+                "payload": {
+                    "type": "string",
+                    "example": "DM1.eyJ0IjoxLCJlIjoxLCJ1IjoxMjMsImlhdCI6MTcwOTI5MTYwMH0.c2ln..."
+                },
+                "qr_png": {
+                    "type": "string",
+                    "example": "iVBORw0KGgoAAAANSUhEUgAA..."
+                }
+            }
+        },
+        "rest.TicketTypeResponse": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "description": "Available - сколько билетов ещё можно заказать",
+                    "type": "integer",
+                    "example": 12
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "event_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "early_bird"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Ранняя регистрация"
+                },
+                "price": {
+                    "type": "integer",
+                    "example": 150000
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "sales_end_at": {
+                    "type": "string",
+                    "example": "2024-02-20T00:00:00+03:00"
+                }
+            }
+        },
+        "rest.TicketTypesOkResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "ticket_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.TicketTypeResponse"
+                    }
+                }
+            }
+        },
+        "rest.VenueOkResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "venue": {
+                    "$ref": "#/definitions/rest.VenueResponse"
+                }
+            }
+        },
+        "rest.VenueResponse": {
+            "type": "object",
+            "properties": {
+                "accessibility_notes": {
+                    "type": "string",
+                    "example": "Пандус у главного входа, лифт"
+                },
+                "address": {
+                    "type": "string",
+                    "example": "Москва, ул. Льва Толстого, 16"
+                },
+                "capacity": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "distance_km": {
+                    "type": "number",
+                    "example": 1.7
+                },
+                "host_company": {
+                    "type": "string",
+                    "example": "Яндекс"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "lat": {
+                    "type": "number",
+                    "example": 55.7338
+                },
+                "lon": {
+                    "type": "number",
+                    "example": 37.5879
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Офис Яндекса"
+                }
+            }
+        },
+        "rest.VenuesOkResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "venues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.VenueResponse"
+                    }
+                }
+            }
+        },
+        "rest.WebhookDeliveriesOkResponse": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.WebhookDeliveryResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "MTAyNA"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "rest.WebhookDeliveryResponse": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-20T12:00:00+03:00"
+                },
+                "delivery_id": {
+                    "type": "string",
+                    "example": "5d41402abc4b2a76b9719d911017c592"
+                },
+                "duration_ms": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "error": {
+                    "type": "string",
+                    "example": "webhook responded with non-2xx status: 502"
+                },
+                "event_type": {
+                    "type": "string",
+                    "example": "rsvp.created"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1024
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "rest.WebhookOkResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "webhook": {
+                    "$ref": "#/definitions/rest.WebhookResponse"
+                }
+            }
+        },
+        "rest.WebhookResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-01T12:00:00+03:00"
+                },
+                "disabled_at": {
+                    "type": "string",
+                    "example": "2024-02-20T12:00:00+03:00"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "event.published",
+                        "rsvp.created"
+                    ]
+                },
+                "failure_count": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "group_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/dev-meets"
+                }
+            }
+        },
+        "rest.WebhooksOkResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "webhooks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.WebhookResponse"
+                    }
+                }
+            }
+        },
+        "rest.cfpInput": {
+            "type": "object",
+            "required": [
+                "deadline"
+            ],
+            "properties": {
+                "deadline": {
+                    "type": "string",
+                    "example": "2024-02-15T23:59:59+03:00"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Ищем доклады про Go в продакшене"
+                }
+            }
+        },
+        "rest.changePasswordInput": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string",
+                    "example": "password"
+                },
+                "new_password": {
+                    "description": "bcrypt учитывает только первые 72 байта пароля",
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 8,
+                    "example": "new-password"
+                }
+            }
+        },
+        "rest.checkInInput": {
+            "type": "object",
+            "required": [
+                "payload"
+            ],
+            "properties": {
+                "payload": {
+                    "type": "string",
+                    "example": "DM1.eyJ0IjoxLCJlIjoxLCJ1IjoxMjMsImlhdCI6MTcwOTI5MTYwMH0.c2ln..."
+                }
+            }
+        },
+        "rest.commentInput": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 10000,
+                    "example": "Есть ли рядом парковка? @organizer"
+                },
+                "parent_id": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "rest.commentUpdateInput": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 10000,
+                    "example": "Есть ли рядом бесплатная парковка?"
+                }
+            }
+        },
+        "rest.confirmEmailChangeInput": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "3f9a..."
+                }
+            }
+        },
+        "rest.conversationInput": {
+            "type": "object",
+            "required": [
+                "user_ids"
+            ],
+            "properties": {
+                "title": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Организаторы Go meetup"
+                },
+                "user_ids": {
+                    "type": "array",
+                    "maxItems": 9,
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        12
+                    ]
+                }
+            }
+        },
+        "rest.deleteAccountInput": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "password"
+                }
+            }
+        },
+        "rest.emailChangeInput": {
+            "type": "object",
+            "required": [
+                "new_email",
+                "password"
+            ],
+            "properties": {
+                "new_email": {
+                    "type": "string",
+                    "maxLength": 254,
+                    "example": "new-email@gmail.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password"
+                }
+            }
+        },
+        "rest.eventInput": {
+            "type": "object",
+            "required": [
+                "ends_at",
+                "starts_at",
+                "title"
+            ],
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Москва"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Доклады про конкурентность в Go"
+                },
+                "ends_at": {
+                    "type": "string",
+                    "example": "2024-03-01T22:00:00+03:00"
+                },
+                "group_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "private": {
+                    "description": "Private - записаться можно только по приглашению",
+                    "type": "boolean",
+                    "example": false
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2024-03-01T19:00:00+03:00"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Go meetup #12"
+                },
+                "venue_id": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "rest.feedbackInput": {
+            "type": "object",
+            "required": [
+                "rating"
+            ],
+            "properties": {
+                "anonymous": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "comment": {
+                    "type": "string",
+                    "maxLength": 5000,
+                    "example": "Отличные доклады, хотелось бы больше времени на вопросы"
+                },
+                "rating": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1,
+                    "example": 5
+                }
+            }
+        },
+        "rest.groupInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Москва"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 5000,
+                    "example": "Сообщество Go-разработчиков Москвы"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Moscow Gophers"
+                },
+                "private": {
+                    "description": "Private - вступить можно только по приглашению",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "rest.inviteInput": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 254,
+                    "example": "friend@gmail.com"
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "gopher"
+                }
+            }
+        },
+        "rest.inviteLinkInput": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "ExpiresAt - до какого момента действует ссылка, не указано - бессрочно",
+                    "type": "string",
+                    "example": "2024-03-01T00:00:00+03:00"
+                },
+                "max_uses": {
+                    "description": "MaxUses - сколько раз можно воспользоваться ссылкой, не указано - без ограничения",
+                    "type": "integer",
+                    "maximum": 10000,
+                    "minimum": 1,
+                    "example": 50
+                }
+            }
+        },
+        "rest.matchRequestInput": {
+            "type": "object",
+            "required": [
+                "goal",
+                "user_id"
+            ],
+            "properties": {
+                "goal": {
+                    "type": "string",
+                    "enum": [
+                        "mentorship",
+                        "pair_programming",
+                        "cofounding"
+                    ],
+                    "example": "pair_programming"
+                },
+                "message": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Привет! Хочу вместе покодить на Go по выходным"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "rest.matchSettingsInput": {
+            "type": "object",
+            "required": [
+                "goals"
+            ],
+            "properties": {
+                "goals": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "mentorship",
+                        "pair_programming"
+                    ]
+                }
+            }
+        },
+        "rest.mergeInput": {
+            "type": "object",
+            "required": [
+                "source_id"
+            ],
+            "properties": {
+                "source_id": {
+                    "type": "integer",
+                    "example": 15
+                }
+            }
+        },
+        "rest.messageInput": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 4000,
+                    "example": "Привет! Увидимся на митапе?"
+                }
+            }
+        },
+        "rest.notificationPreferenceInput": {
+            "type": "object",
+            "required": [
+                "channel",
+                "type"
+            ],
+            "properties": {
+                "channel": {
+                    "type": "string",
+                    "enum": [
+                        "in_app",
+                        "email",
+                        "none"
+                    ],
+                    "example": "email"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "group_discussion"
+                }
+            }
+        },
+        "rest.notificationPreferencesInput": {
+            "type": "object",
+            "required": [
+                "preferences"
+            ],
+            "properties": {
+                "preferences": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/rest.notificationPreferenceInput"
+                    }
+                }
+            }
+        },
+        "rest.orderInput": {
+            "type": "object",
+            "required": [
+                "ticket_type_id"
+            ],
+            "properties": {
+                "ticket_type_id": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 4
+                }
+            }
+        },
+        "rest.profileInput": {
+            "type": "object",
+            "properties": {
+                "bio": {
+                    "type": "string",
+                    "maxLength": 2000,
+                    "example": "Пишу на Go, люблю распределённые системы"
+                },
+                "city": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Москва"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Иван Петров"
+                },
+                "seniority": {
+                    "type": "string",
+                    "enum": [
+                        "junior",
+                        "middle",
+                        "senior",
+                        "lead"
+                    ],
+                    "example": "senior"
+                },
+                "skills": {
+                    "type": "array",
+                    "maxItems": 30,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "go",
+                        "postgresql"
+                    ]
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 33,
+                    "example": "ivan_petrov"
+                }
+            }
+        },
+        "rest.readInput": {
+            "type": "object",
+            "required": [
+                "message_id"
+            ],
+            "properties": {
+                "message_id": {
+                    "type": "integer",
+                    "example": 128
+                }
+            }
+        },
+        "rest.reportInput": {
+            "type": "object",
+            "required": [
+                "reason",
+                "target_id",
+                "target_type"
+            ],
+            "properties": {
+                "details": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Реклама курсов в каждом обсуждении"
+                },
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "spam",
+                        "abuse",
+                        "inappropriate",
+                        "other"
+                    ],
+                    "example": "spam"
+                },
+                "target_id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "target_type": {
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "event",
+                        "comment",
+                        "message"
+                    ],
+                    "example": "comment"
+                }
+            }
+        },
+        "rest.resolveReportInput": {
+            "type": "object",
+            "required": [
+                "action"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "dismiss",
+                        "hide",
+                        "warn",
+                        "suspend"
+                    ],
+                    "example": "hide"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Реклама"
+                },
+                "suspend_days": {
+                    "type": "integer",
+                    "maximum": 365,
+                    "minimum": 0,
+                    "example": 7
+                }
+            }
+        },
+        "rest.reviewInput": {
+            "type": "object",
+            "required": [
+                "score"
+            ],
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "example": "Хорошая тема, но слишком длинно"
+                },
+                "score": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1,
+                    "example": 4
+                }
+            }
+        },
+        "rest.reviewerInput": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "user_id": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "rest.roleInput": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "moderator",
+                        "admin"
+                    ],
+                    "example": "moderator"
+                }
+            }
+        },
+        "rest.signInUpInput": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "email@gmail.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password"
+                }
+            }
+        },
+        "rest.signUpInput": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "email@gmail.com"
+                },
+                "invite_token": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "9f2c4e..."
                 },
                 "password": {
                     "type": "string",
                     "example": "password"
                 }
             }
+        },
+        "rest.slotInput": {
+            "type": "object",
+            "required": [
+                "kind",
+                "title"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": ""
+                },
+                "ends_at": {
+                    "type": "string",
+                    "example": "2024-03-01T20:15:00+03:00"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "talk",
+                        "break",
+                        "networking"
+                    ],
+                    "example": "break"
+                },
+                "slides_url": {
+                    "type": "string",
+                    "example": "https://example.com/slides.pdf"
+                },
+                "speaker_ids": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2024-03-01T20:00:00+03:00"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Кофе-брейк"
+                },
+                "video_url": {
+                    "type": "string",
+                    "example": "https://youtu.be/xxxx"
+                }
+            }
+        },
+        "rest.slotUpdateInput": {
+            "type": "object",
+            "required": [
+                "kind",
+                "position",
+                "title"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": ""
+                },
+                "ends_at": {
+                    "type": "string",
+                    "example": "2024-03-01T20:15:00+03:00"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "talk",
+                        "break",
+                        "networking"
+                    ],
+                    "example": "break"
+                },
+                "position": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "slides_url": {
+                    "type": "string",
+                    "example": "https://example.com/slides.pdf"
+                },
+                "speaker_ids": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2024-03-01T20:00:00+03:00"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Кофе-брейк"
+                },
+                "video_url": {
+                    "type": "string",
+                    "example": "https://youtu.be/xxxx"
+                }
+            }
+        },
+        "rest.speakerInput": {
+            "type": "object",
+            "properties": {
+                "bio": {
+                    "type": "string",
+                    "example": "Backend-разработчик, 10 лет в Go"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Иван Петров"
+                },
+                "photo_url": {
+                    "type": "string",
+                    "example": "https://example.com/photo.jpg"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 123
+                }
+            }
+        },
+        "rest.suspendInput": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "integer",
+                    "maximum": 365,
+                    "minimum": 0,
+                    "example": 7
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Спам в обсуждениях"
+                }
+            }
+        },
+        "rest.talkInput": {
+            "type": "object",
+            "required": [
+                "abstract",
+                "duration",
+                "level",
+                "tags",
+                "title"
+            ],
+            "properties": {
+                "abstract": {
+                    "type": "string",
+                    "example": "Как найти утечку памяти за 15 минут"
+                },
+                "duration": {
+                    "type": "integer",
+                    "maximum": 240,
+                    "example": 30
+                },
+                "level": {
+                    "type": "string",
+                    "enum": [
+                        "beginner",
+                        "intermediate",
+                        "advanced"
+                    ],
+                    "example": "intermediate"
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "go",
+                        "performance"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Профилирование Go-сервисов"
+                }
+            }
+        },
+        "rest.ticketTypeInput": {
+            "type": "object",
+            "required": [
+                "kind",
+                "name",
+                "quantity"
+            ],
+            "properties": {
+                "currency": {
+                    "description": "Currency - код валюты ISO 4217, у бесплатных билетов не нужен",
+                    "type": "string",
+                    "maxLength": 3,
+                    "minLength": 3,
+                    "example": "RUB"
+                },
+                "kind": {
+                    "description": "Kind - free, paid, early_bird или sponsor",
+                    "type": "string",
+                    "enum": [
+                        "free",
+                        "paid",
+                        "early_bird",
+                        "sponsor"
+                    ],
+                    "example": "early_bird"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Ранняя регистрация"
+                },
+                "price": {
+                    "description": "Price - цена в копейках, у бесплатных билетов 0",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 150000
+                },
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 100000,
+                    "minimum": 1,
+                    "example": 50
+                },
+                "sales_end_at": {
+                    "description": "SalesEndAt - до какого момента продаются билеты, для early_bird обязателен",
+                    "type": "string",
+                    "example": "2024-02-20T00:00:00+03:00"
+                }
+            }
+        },
+        "rest.venueInput": {
+            "type": "object",
+            "required": [
+                "address",
+                "name"
+            ],
+            "properties": {
+                "accessibility_notes": {
+                    "type": "string",
+                    "example": "Пандус у главного входа, лифт"
+                },
+                "address": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Москва, ул. Льва Толстого, 16"
+                },
+                "capacity": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 120
+                },
+                "host_company": {
+                    "type": "string",
+                    "example": "Яндекс"
+                },
+                "lat": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90,
+                    "example": 55.7338
+                },
+                "lon": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180,
+                    "example": 37.5879
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Офис Яндекса"
+                }
+            }
+        },
+        "rest.webhookInput": {
+            "type": "object",
+            "required": [
+                "event_types",
+                "url"
+            ],
+            "properties": {
+                "event_types": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "event.published",
+                        "rsvp.created"
+                    ]
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2000,
+                    "example": "https://example.com/hooks/dev-meets"
+                }
+            }
+        },
+        "rest.webhookUpdateInput": {
+            "type": "object",
+            "required": [
+                "event_types",
+                "url"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "event_types": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "event.published",
+                        "rsvp.created"
+                    ]
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2000,
+                    "example": "https://example.com/hooks/dev-meets"
+                }
+            }
         }
     }
 }`
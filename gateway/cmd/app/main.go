@@ -5,8 +5,6 @@ import (
 	"dev_meets/internal/config"
 	"log/slog"
 	"os"
-	"os/signal"
-	"syscall"
 )
 
 const (
@@ -22,18 +20,9 @@ func main() {
 
 	application := app.New(log, cfg)
 
-	go func() {
-		application.Run()
-	}()
+	// Run сам ждёт SIGINT/SIGTERM и завершает работу
+	application.Run()
 
-	// Graceful shutdown
-
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
-
-	<-stop
-
-	application.Stop()
 	log.Info("Gracefully stopped")
 }
 
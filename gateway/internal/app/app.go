@@ -4,9 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"dev_meets/internal/config"
+	"dev_meets/internal/jobs"
 	"dev_meets/internal/service"
 	"dev_meets/internal/storage"
 	"dev_meets/internal/transport/rest"
+	"dev_meets/pkg/email"
+	"dev_meets/pkg/payment"
+	"dev_meets/pkg/ticket"
 	"fmt"
 	_ "github.com/lib/pq"
 	"log/slog"
@@ -22,6 +26,12 @@ type App struct {
 	logger     *slog.Logger
 	config     *config.Config
 	db         *sql.DB
+	services   *service.Service
+	listener   *storage.StreamListener
+	queue      *jobs.Queue
+	// workers отменяется при остановке приложения и завершает фоновые задачи сервисов
+	workers     context.Context
+	stopWorkers context.CancelFunc
 }
 
 func New(
@@ -30,10 +40,83 @@ func New(
 ) *App {
 	db := initDbConnection(conf)
 	repos := storage.NewRepository(db, log)
-	services := service.NewService(repos, log)
+	signer, err := ticket.NewSigner(conf.Tickets.SigningKey)
+	if err != nil {
+		panic(err)
+	}
+
+	listener, err := storage.NewStreamListener(dsn(conf), log)
+	if err != nil {
+		panic(err)
+	}
+
+	queue := jobs.NewQueue(repos.JobPostgres, jobs.Config{
+		Concurrency:  conf.Jobs.Concurrency,
+		PollInterval: conf.Jobs.PollInterval,
+		Lease:        conf.Jobs.Lease,
+	}, log)
+
+	paymentConfig := service.PaymentConfig{
+		Provider:        conf.Payments.Provider,
+		HoldTTL:         conf.Payments.HoldTTL,
+		FakeAutoConfirm: conf.Payments.FakeAutoConfirm,
+		Acquirer: payment.AcquirerConfig{
+			APIURL:        conf.Payments.APIURL,
+			ShopID:        conf.Payments.ShopID,
+			SecretKey:     conf.Payments.SecretKey,
+			WebhookSecret: conf.Payments.WebhookSecret,
+			ReturnURL:     conf.Payments.ReturnURL,
+			Timeout:       conf.Payments.Timeout,
+		},
+	}
+	payments, err := service.NewPaymentProvider(paymentConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	services := service.NewService(repos, signer, queue, payments, service.Config{
+		ReminderOffsets: conf.Reminders.Offsets,
+		Webhooks: service.WebhookConfig{
+			Timeout:              conf.Webhooks.Timeout,
+			DisableAfter:         conf.Webhooks.DisableAfter,
+			AllowPrivateNetworks: conf.Webhooks.AllowPrivateNetworks,
+		},
+		Telegram: service.TelegramConfig{
+			Token:         conf.Telegram.Token,
+			APIURL:        conf.Telegram.APIURL,
+			BotUsername:   conf.Telegram.BotUsername,
+			Mode:          conf.Telegram.Mode,
+			WebhookURL:    conf.Telegram.WebhookURL,
+			WebhookSecret: conf.Telegram.WebhookSecret,
+			PollTimeout:   conf.Telegram.PollTimeout,
+		},
+		Audit: service.AuditConfig{
+			Retention: conf.Audit.Retention,
+		},
+		Accounts: service.AccountConfig{
+			DeletionGrace:  conf.Accounts.DeletionGrace,
+			ExportTTL:      conf.Accounts.ExportTTL,
+			EmailChangeTTL: conf.Accounts.EmailChangeTTL,
+		},
+		Invitations: service.InvitationConfig{
+			PersonalTTL: conf.Invitations.PersonalTTL,
+		},
+		Payments: paymentConfig,
+		Mail: email.Config{
+			Host:        conf.Mail.Host,
+			Port:        conf.Mail.Port,
+			From:        conf.Mail.From,
+			Username:    conf.Mail.Username,
+			Password:    conf.Mail.Password,
+			ImplicitTLS: conf.Mail.ImplicitTLS,
+			Timeout:     conf.Mail.Timeout,
+		},
+	}, log)
 	handlers := rest.NewHandler(services, log)
 	router := handlers.InitRoutes()
 
+	workers, stopWorkers := context.WithCancel(context.Background())
+
 	log.Info("initializing server", slog.String("address", conf.HTTPServer.Address))
 
 	srv := &http.Server{
@@ -43,12 +126,20 @@ func New(
 		WriteTimeout: conf.HTTPServer.Timeout,
 		IdleTimeout:  conf.HTTPServer.IdleTimeout,
 	}
+	// Shutdown не отменяет контекст запросов и не ждёт перехваченные WebSocket-соединения,
+	// поэтому открытые потоки событий закрываются отдельно
+	srv.RegisterOnShutdown(services.CloseStreams)
 
 	return &App{
-		HTTPServer: srv,
-		logger:     log,
-		config:     conf,
-		db:         db,
+		HTTPServer:  srv,
+		logger:      log,
+		config:      conf,
+		db:          db,
+		services:    services,
+		listener:    listener,
+		queue:       queue,
+		workers:     workers,
+		stopWorkers: stopWorkers,
 	}
 }
 
@@ -66,17 +157,25 @@ func (a *App) Run() {
 
 	a.logger.Info("server started")
 
+	go a.services.RunDigests(a.workers, a.config.Notifications.DigestInterval)
+	go a.services.RunStream(a.workers, a.listener.Events(a.workers))
+	go a.services.RunReminders(a.workers, a.config.Reminders.Interval)
+	go a.queue.Run(a.workers)
+	go a.services.RunTelegram(a.workers)
+	go a.services.RunAuditRetention(a.workers)
+
 	<-done
 	a.logger.Info("stopping server")
 
+	// фоновые задачи больше не берут новую работу, пока сервер дообслуживает запросы
+	a.stopWorkers()
+
 	// TODO: move timeout to config
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	if err := a.HTTPServer.Shutdown(ctx); err != nil {
-		a.logger.Error("failed to stop server")
-
-		return
+		a.logger.Error("failed to stop server", slog.String("error", err.Error()))
 	}
 
 	a.Stop()
@@ -84,14 +183,20 @@ func (a *App) Run() {
 }
 
 func (a *App) Stop() {
+	a.stopWorkers()
+	a.queue.Drain(a.config.Jobs.DrainTimeout)
 	a.db.Close()
 }
 
-func initDbConnection(cnf *config.Config) *sql.DB {
-	psqlInfo := fmt.Sprintf("host=%s port=%d user=%s "+
+// dsn собирает строку подключения к Postgres
+func dsn(cnf *config.Config) string {
+	return fmt.Sprintf("host=%s port=%d user=%s "+
 		"password=%s dbname=%s sslmode=disable",
 		cnf.Postgresql.Host, cnf.Postgresql.Port, cnf.Postgresql.User, cnf.Postgresql.Password, cnf.Postgresql.DB)
-	db, err := sql.Open("postgres", psqlInfo)
+}
+
+func initDbConnection(cnf *config.Config) *sql.DB {
+	db, err := sql.Open("postgres", dsn(cnf))
 	if err != nil {
 		panic(err)
 	}
@@ -12,6 +12,17 @@ type Config struct {
 	Env        string `env-default:"local"`
 	Postgresql `yaml:"postgresql"`
 	HTTPServer `yaml:"http_server"`
+	Tickets
+	Notifications `yaml:"notifications"`
+	Reminders     `yaml:"reminders"`
+	Jobs          `yaml:"jobs"`
+	Webhooks      `yaml:"webhooks"`
+	Telegram      `yaml:"telegram"`
+	Audit         `yaml:"audit"`
+	Accounts      `yaml:"accounts"`
+	Invitations   `yaml:"invitations"`
+	Payments      `yaml:"payments"`
+	Mail          `yaml:"mail"`
 }
 
 type Postgresql struct {
@@ -29,6 +40,106 @@ type HTTPServer struct {
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
 }
 
+type Tickets struct {
+	// SigningKey - seed-ключ Ed25519 (32 байта в base64) для подписи билетов
+	SigningKey string `env-default:""`
+}
+
+type Notifications struct {
+	// DigestInterval - период рассылки email-сводок уведомлений
+	DigestInterval time.Duration `yaml:"digest_interval" env-default:"1h"`
+}
+
+type Reminders struct {
+	// Offsets - за сколько до начала мероприятия напоминать участникам
+	Offsets []time.Duration `yaml:"offsets" env-default:"24h,1h"`
+	// Interval - период проверки, не пора ли отправить напоминания
+	Interval time.Duration `yaml:"interval" env-default:"1m"`
+}
+
+type Jobs struct {
+	// Concurrency - сколько фоновых задач реплика выполняет одновременно
+	Concurrency  int           `yaml:"concurrency" env-default:"4"`
+	PollInterval time.Duration `yaml:"poll_interval" env-default:"1s"`
+	// Lease - время на выполнение задачи, после которого её заберёт другой воркер
+	Lease time.Duration `yaml:"lease" env-default:"5m"`
+	// DrainTimeout - сколько ждать завершения начатых задач при остановке
+	DrainTimeout time.Duration `yaml:"drain_timeout" env-default:"30s"`
+}
+
+type Webhooks struct {
+	Timeout time.Duration `yaml:"timeout" env-default:"10s"`
+	// DisableAfter - после скольких неудачных доставок подряд вебхук отключается
+	DisableAfter int `yaml:"disable_after" env-default:"20"`
+	// AllowPrivateNetworks разрешает вебхуки на адреса локальной сети
+	AllowPrivateNetworks bool `yaml:"allow_private_networks" env-default:"false"`
+}
+
+type Telegram struct {
+	// Token - токен бота из TELEGRAM_BOT_TOKEN. Без него бот не запускается
+	Token       string `env-default:""`
+	APIURL      string `yaml:"api_url" env-default:"https://api.telegram.org"`
+	BotUsername string `yaml:"bot_username" env-default:""`
+	// Mode - polling (обновления забирает одна реплика, взявшая блокировку в базе) или webhook (Telegram присылает их в /api/v1/telegram/webhook)
+	Mode       string `yaml:"mode" env-default:"polling"`
+	WebhookURL string `yaml:"webhook_url" env-default:""`
+	// WebhookSecret - секрет вебхука из TELEGRAM_WEBHOOK_SECRET
+	WebhookSecret string        `env-default:""`
+	PollTimeout   time.Duration `yaml:"poll_timeout" env-default:"30s"`
+}
+
+type Audit struct {
+	// Retention - сколько хранить записи журнала аудита, 0 - всегда
+	Retention time.Duration `yaml:"retention" env-default:"8760h"`
+}
+
+type Accounts struct {
+	// DeletionGrace - через сколько после запроса аккаунт удаляется, если пользователь не передумал
+	DeletionGrace time.Duration `yaml:"deletion_grace" env-default:"720h"`
+	// ExportTTL - сколько действует ссылка на выгрузку данных
+	ExportTTL time.Duration `yaml:"export_ttl" env-default:"48h"`
+	// EmailChangeTTL - сколько действует код подтверждения новой почты
+	EmailChangeTTL time.Duration `yaml:"email_change_ttl" env-default:"24h"`
+}
+
+type Invitations struct {
+	// PersonalTTL - сколько действует приглашение по имени пользователя или почте
+	PersonalTTL time.Duration `yaml:"personal_ttl" env-default:"336h"`
+}
+
+type Payments struct {
+	// Provider - acquirer или fake (платежи в памяти, только в окружении local). Значения
+	// по умолчанию нет: провайдер выбирают явно
+	Provider string `yaml:"provider" env-required:"true"`
+	// HoldTTL - сколько неоплаченный заказ удерживает билет
+	HoldTTL time.Duration `yaml:"hold_ttl" env-default:"15m"`
+	// FakeAutoConfirm - фейковый провайдер считает платежи оплаченными сразу, только в окружении local
+	FakeAutoConfirm bool   `yaml:"fake_auto_confirm" env-default:"false"`
+	APIURL          string `yaml:"api_url" env-default:""`
+	ShopID          string `yaml:"shop_id" env-default:""`
+	// SecretKey - ключ API эквайринга из PAYMENT_SECRET_KEY
+	SecretKey string `env-default:""`
+	// WebhookSecret - ключ подписи вебхуков эквайринга из PAYMENT_WEBHOOK_SECRET
+	WebhookSecret string `env-default:""`
+	// ReturnURL - куда эквайринг возвращает покупателя после оплаты
+	ReturnURL string        `yaml:"return_url" env-default:""`
+	Timeout   time.Duration `yaml:"timeout" env-default:"10s"`
+}
+
+type Mail struct {
+	// Host - SMTP-сервер. Без него письма только пишутся в лог, что допустимо лишь в окружении local
+	Host string `yaml:"host" env-default:""`
+	Port int    `yaml:"port" env-default:"587"`
+	// From - адрес отправителя, можно с именем: "dev meets <noreply@devmeets.ru>"
+	From     string `yaml:"from" env-default:""`
+	Username string `yaml:"username" env-default:""`
+	// Password - пароль SMTP из SMTP_PASSWORD
+	Password string `env-default:""`
+	// ImplicitTLS - TLS с первого байта (порт 465) вместо STARTTLS
+	ImplicitTLS bool          `yaml:"implicit_tls" env-default:"false"`
+	Timeout     time.Duration `yaml:"timeout" env-default:"10s"`
+}
+
 func MustLoad() *Config {
 	var cfg Config
 
@@ -36,8 +147,9 @@ func MustLoad() *Config {
 	pgUser := os.Getenv("POSTGRES_USER")
 	pgPassword := os.Getenv("POSTGRES_PASSWORD")
 	pgDB := os.Getenv("POSTGRES_DB")
+	ticketKey := os.Getenv("TICKET_SIGNING_KEY")
 
-	if env == "" || pgUser == "" || pgPassword == "" || pgDB == "" {
+	if env == "" || pgUser == "" || pgPassword == "" || pgDB == "" || ticketKey == "" {
 		log.Fatal("required env variables are not set")
 	}
 
@@ -56,6 +168,22 @@ func MustLoad() *Config {
 		cfg.Postgresql.User = pgUser
 		cfg.Postgresql.Password = pgPassword
 		cfg.Postgresql.DB = pgDB
+		cfg.Tickets.SigningKey = ticketKey
+		cfg.Telegram.Token = os.Getenv("TELEGRAM_BOT_TOKEN")
+		cfg.Telegram.WebhookSecret = os.Getenv("TELEGRAM_WEBHOOK_SECRET")
+		cfg.Payments.SecretKey = os.Getenv("PAYMENT_SECRET_KEY")
+		cfg.Payments.WebhookSecret = os.Getenv("PAYMENT_WEBHOOK_SECRET")
+		cfg.Mail.Password = os.Getenv("SMTP_PASSWORD")
+	}
+
+	// без SMTP письма с кодами подтверждения и приглашениями никуда не уходят
+	if env != "local" && (cfg.Mail.Host == "" || cfg.Mail.From == "") {
+		log.Fatal("mail host and from are required outside local environment")
+	}
+
+	// фейковый провайдер выдаёт билеты без денег
+	if env != "local" && (cfg.Payments.Provider == "fake" || cfg.Payments.FakeAutoConfirm) {
+		log.Fatal("fake payment provider is allowed only in local environment")
 	}
 
 	return &cfg
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+const (
+	DataExportPending = "pending"
+	DataExportReady   = "ready"
+)
+
+// DataExport - выгрузка данных пользователя. Архив скачивается по ссылке с Token
+// до ExpiresAt
+type DataExport struct {
+	ID        int
+	UserID    int
+	Token     string
+	Status    string
+	Archive   []byte
+	CreatedAt time.Time
+	ReadyAt   *time.Time
+	ExpiresAt *time.Time
+}
+
+// EmailChange - запрошенная смена почты. NewEmail начинает действовать после
+// подтверждения секретом Token из письма на новый адрес, до ExpiresAt
+type EmailChange struct {
+	ID        int
+	UserID    int
+	NewEmail  string
+	Token     string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
@@ -0,0 +1,30 @@
+package models
+
+var UserRoles = []string{
+	UserRoleUser,
+	UserRoleModerator,
+	UserRoleAdmin,
+}
+
+// AdminUserFilter - поиск пользователей администратором. Query ищет по почте, имени,
+// username и идентификатору, Suspended - только заблокированные или только активные,
+// DuplicateEmail - только аккаунты, почта которых без учёта регистра совпадает с чужой
+type AdminUserFilter struct {
+	Query          string
+	Role           string
+	Suspended      *bool
+	DuplicateEmail bool
+	Limit          int
+	Offset         int
+}
+
+// AccountState - пользователь и сводка по его аккаунту для администратора
+type AccountState struct {
+	User
+	TelegramLinked  bool
+	EventsOrganized int
+	GroupsOwned     int
+	// OpenReports - открытые жалобы на пользователя и его содержимое
+	OpenReports       int
+	ModerationActions int
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+const (
+	AgendaSlotTalk       = "talk"
+	AgendaSlotBreak      = "break"
+	AgendaSlotNetworking = "networking"
+)
+
+type AgendaSlot struct {
+	ID          int
+	EventID     int
+	Position    int
+	Kind        string
+	TalkID      *int
+	Title       string
+	Description string
+	StartsAt    *time.Time
+	EndsAt      *time.Time
+	SlidesURL   string
+	VideoURL    string
+	Speakers    []Speaker
+}
+
+// Speaker - докладчик мероприятия: зарегистрированный пользователь (UserID != nil)
+// или внешний гость
+type Speaker struct {
+	ID        int
+	UserID    *int
+	Name      string
+	Bio       string
+	PhotoURL  string
+	CreatedBy int
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Действия, которые попадают в журнал аудита
+const (
+	AuditSignIn          = "auth.sign_in"
+	AuditSignInFailed    = "auth.sign_in_failed"
+	AuditSignUp          = "auth.sign_up"
+	AuditPasswordChanged = "auth.password_changed"
+
+	AuditAccountDeletionRequested = "account.deletion_requested"
+	AuditAccountDeletionCancelled = "account.deletion_cancelled"
+	AuditAccountDeleted           = "account.deleted"
+	AuditDataExportRequested      = "account.data_export_requested"
+	AuditEmailChangeRequested     = "account.email_change_requested"
+	AuditEmailChanged             = "account.email_changed"
+
+	AuditUserRoleChanged    = "user.role_changed"
+	AuditUserSuspended      = "user.suspended"
+	AuditUserUnsuspended    = "user.unsuspended"
+	AuditUserSessionRevoked = "user.sessions_revoked"
+	AuditUserMerged         = "user.merged"
+
+	AuditEventCreated   = "event.created"
+	AuditEventCancelled = "event.cancelled"
+	AuditCFPOpened      = "cfp.opened"
+	AuditReviewerAdded  = "cfp.reviewer_added"
+	AuditTalkAccepted   = "talk.accepted"
+	AuditTalkRejected   = "talk.rejected"
+	AuditWebhookCreated = "webhook.created"
+	AuditWebhookUpdated = "webhook.updated"
+	AuditWebhookDeleted = "webhook.deleted"
+	AuditOrderRefunded  = "order.refunded"
+)
+
+// Объекты, над которыми выполняются действия из журнала аудита
+const (
+	AuditTargetUser    = "user"
+	AuditTargetEvent   = "event"
+	AuditTargetTalk    = "talk"
+	AuditTargetWebhook = "webhook"
+	AuditTargetOrder   = "order"
+)
+
+var AuditTargets = []string{
+	AuditTargetUser,
+	AuditTargetEvent,
+	AuditTargetTalk,
+	AuditTargetWebhook,
+	AuditTargetOrder,
+}
+
+// RequestMeta - данные HTTP-запроса, в рамках которого выполняется действие
+type RequestMeta struct {
+	IP        string
+	RequestID string
+}
+
+// AuditEvent - запись журнала аудита. ActorID - кто выполнил действие, nil - неизвестный
+// пользователь (например, неудачный вход с несуществующей почтой), TargetID 0 - объекта нет.
+// Before и After - состояние объекта до и после изменения в JSON, nil - состояния нет
+type AuditEvent struct {
+	ID         int64
+	ActorID    *int
+	Action     string
+	TargetType string
+	TargetID   int
+	Before     json.RawMessage
+	After      json.RawMessage
+	RequestMeta
+	CreatedAt time.Time
+}
+
+type AuditFilter struct {
+	ActorID    int
+	Action     string
+	TargetType string
+	TargetID   int
+	From       *time.Time
+	To         *time.Time
+	Cursor     int64 // идентификатор последней полученной записи, 0 - с самой новой
+	Limit      int
+}
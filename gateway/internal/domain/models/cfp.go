@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+const (
+	TalkStatusSubmitted = "submitted"
+	TalkStatusAccepted  = "accepted"
+	TalkStatusRejected  = "rejected"
+)
+
+const (
+	TalkLevelBeginner     = "beginner"
+	TalkLevelIntermediate = "intermediate"
+	TalkLevelAdvanced     = "advanced"
+)
+
+type CallForPapers struct {
+	EventID     int
+	Description string
+	Deadline    time.Time
+	CreatedAt   time.Time
+}
+
+func (c CallForPapers) IsOpen(now time.Time) bool {
+	return now.Before(c.Deadline)
+}
+
+type Talk struct {
+	ID        int
+	EventID   int
+	SpeakerID int
+	Title     string
+	Abstract  string
+	Level     string
+	Duration  int // minutes
+	Tags      []string
+	Status    string
+	CreatedAt time.Time
+}
+
+type TalkReview struct {
+	ID         int
+	TalkID     int
+	ReviewerID int
+	Score      int
+	Comment    string
+	CreatedAt  time.Time
+}
+
+type TalkSummary struct {
+	Talk
+	AvgScore     float64
+	ReviewsCount int
+}
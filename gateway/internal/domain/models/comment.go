@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+const (
+	CommentSubjectEvent = "event"
+	CommentSubjectGroup = "group"
+)
+
+// Comment - сообщение в обсуждении мероприятия или сообщества. Ответы на любой
+// комментарий треда относятся к его корню (RootID), ParentID указывает, на что ответили
+type Comment struct {
+	ID          int
+	SubjectType string
+	SubjectID   int
+	RootID      *int
+	ParentID    *int
+	AuthorID    int
+	Body        string
+	BodyHTML    string
+	CreatedAt   time.Time
+	EditedAt    *time.Time
+	DeletedAt   *time.Time
+	// HiddenAt задано, если комментарий скрыл модератор. Текст такого комментария не отдаётся
+	HiddenAt *time.Time
+	Replies  []Comment
+}
+
+type CommentRevision struct {
+	ID        int
+	CommentID int
+	Body      string
+	CreatedAt time.Time
+}
+
+type CommentFilter struct {
+	SubjectType string
+	SubjectID   int
+	Cursor      int // идентификатор последнего полученного треда, 0 - с начала
+	Limit       int
+}
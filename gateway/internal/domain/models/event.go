@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type Event struct {
+	ID          int
+	OrganizerID int
+	VenueID     *int
+	GroupID     *int
+	Title       string
+	Description string
+	City        string
+	StartsAt    time.Time
+	EndsAt      time.Time
+	CreatedAt   time.Time
+	CancelledAt *time.Time
+	// Private - записаться на мероприятие можно только по приглашению
+	Private bool
+	Agenda  []AgendaSlot
+	// DistanceKm заполняется только при поиске по расстоянию
+	DistanceKm *float64
+}
+
+type EventFilter struct {
+	Near   *GeoFilter
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,31 @@
+package models
+
+// FeedCandidate - предстоящее мероприятие и сигналы, по которым оно ранжируется в ленте пользователя
+type FeedCandidate struct {
+	Event
+	// InUserGroup - мероприятие сообщества, в котором состоит пользователь
+	InUserGroup bool
+	// FriendsGoing - сколько записалось тех, на кого пользователь подписан или с кем у него личный диалог
+	FriendsGoing int
+	// AttendedInGroup и AttendedOrganizer - на скольких прошедших мероприятиях
+	// этого сообщества и этого организатора пользователь отметился
+	AttendedInGroup   int
+	AttendedOrganizer int
+	// DismissedInGroup и DismissedOrganizer - сколько мероприятий этого сообщества
+	// и этого организатора пользователь отметил как неинтересные
+	DismissedInGroup   int
+	DismissedOrganizer int
+	Going              int
+}
+
+// FeedProfile - то, что известно о пользователе для ранжирования ленты
+type FeedProfile struct {
+	Skills []string
+	City   string
+}
+
+type FeedItem struct {
+	Event   Event
+	Score   float64
+	Reasons []string
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+const (
+	MinRating = 1
+	MaxRating = 5
+)
+
+// Feedback - оценка мероприятия (SlotID == nil) или доклада из его программы.
+// У анонимных отзывов автор известен только хранилищу
+type Feedback struct {
+	ID        int
+	EventID   int
+	SlotID    *int
+	UserID    int
+	Rating    int
+	Comment   string
+	Anonymous bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type RatingSummary struct {
+	Count        int
+	Average      float64
+	Distribution [MaxRating]int // Distribution[i] - число оценок i+1
+}
+
+type TalkRating struct {
+	SlotID int
+	Title  string
+	RatingSummary
+}
+
+// EventFeedbackReport - сводка отзывов для организатора
+type EventFeedbackReport struct {
+	RatingSummary
+	Comments []Feedback
+	Talks    []TalkRating
+}
+
+// TalkFeedbackReport - отзывы на доклад для его докладчиков
+type TalkFeedbackReport struct {
+	Slot AgendaSlot
+	RatingSummary
+	Comments []Feedback
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Действия пользователей, которые попадают в ленту подписок
+const (
+	ActivityEventCreated = "event_created"
+	ActivityTalkAccepted = "talk_accepted"
+	ActivityGroupJoined  = "group_joined"
+)
+
+// Follow - подписчик или пользователь, на которого подписаны
+type Follow struct {
+	UserID    int
+	Username  string
+	Name      string
+	CreatedAt time.Time
+}
+
+// GroupFollow - сообщество, на которое подписан пользователь
+type GroupFollow struct {
+	GroupID   int
+	Name      string
+	City      string
+	CreatedAt time.Time
+}
+
+// Activity - действие пользователя. Названия сообщества, мероприятия и доклада
+// подставляются при чтении, поэтому всегда актуальны
+type Activity struct {
+	ID            int
+	Kind          string
+	ActorID       int
+	ActorName     string
+	ActorUsername string
+	GroupID       *int
+	GroupName     string
+	EventID       *int
+	EventTitle    string
+	TalkID        *int
+	TalkTitle     string
+	CreatedAt     time.Time
+}
+
+type ActivityFilter struct {
+	Cursor int // идентификатор последнего полученного действия, 0 - с самого нового
+	Limit  int
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+const (
+	GroupRoleOwner  = "owner"
+	GroupRoleMember = "member"
+)
+
+type Group struct {
+	ID          int
+	OwnerID     int
+	Name        string
+	Description string
+	City        string
+	// Private - вступить в сообщество можно только по приглашению
+	Private   bool
+	CreatedAt time.Time
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+const (
+	InvitationSubjectGroup = "group"
+	InvitationSubjectEvent = "event"
+)
+
+// Invitation - приглашение в закрытое сообщество или на закрытое мероприятие. Личное
+// приглашение адресовано пользователю InviteeID или почте Email, у которой ещё нет
+// аккаунта, и используется один раз. Ссылка ни к кому не привязана: её можно использовать
+// MaxUses раз, nil - без ограничения. ExpiresAt nil - бессрочно
+type Invitation struct {
+	ID          int
+	SubjectType string
+	SubjectID   int
+	InviterID   int
+	InviteeID   *int
+	Email       string
+	Token       string
+	MaxUses     *int
+	Uses        int
+	ExpiresAt   *time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+	// SubjectTitle - название сообщества или мероприятия
+	SubjectTitle string
+}
+
+// Personal сообщает, адресовано ли приглашение конкретному человеку
+func (i Invitation) Personal() bool {
+	return i.InviteeID != nil || i.Email != ""
+}
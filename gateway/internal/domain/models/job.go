@@ -0,0 +1,35 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	JobStatusPending = "pending"
+	JobStatusRunning = "running"
+	JobStatusDone    = "done"
+	// JobStatusDead - задача исчерпала попытки и ждёт ручного перезапуска
+	JobStatusDead = "dead"
+)
+
+// Job - фоновая задача в очереди
+type Job struct {
+	ID          int64
+	Type        string
+	Payload     json.RawMessage
+	Status      string
+	Attempts    int
+	MaxAttempts int
+	RunAt       time.Time
+	LastError   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type JobFilter struct {
+	Status string
+	Type   string
+	Cursor int
+	Limit  int
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Цели знакомства
+const (
+	MatchGoalMentorship      = "mentorship"
+	MatchGoalPairProgramming = "pair_programming"
+	MatchGoalCofounding      = "cofounding"
+)
+
+var MatchGoals = []string{
+	MatchGoalMentorship,
+	MatchGoalPairProgramming,
+	MatchGoalCofounding,
+}
+
+const (
+	MatchRequestPending  = "pending"
+	MatchRequestAccepted = "accepted"
+	MatchRequestDeclined = "declined"
+)
+
+// MatchCandidate - участник подбора со всем, что нужно для оценки совпадения.
+// Goals - цели знакомства, которые пользователь указал, согласившись на подбор
+type MatchCandidate struct {
+	UserID int
+	Profile
+	Goals    []string
+	GroupIDs []int
+}
+
+// Match - предложенный собеседник с оценкой и объяснением, почему он подходит
+type Match struct {
+	Candidate MatchCandidate
+	Goal      string
+	Score     float64
+	Reasons   []string
+}
+
+type MatchRequest struct {
+	ID             int
+	RequesterID    int
+	RecipientID    int
+	Goal           string
+	Message        string
+	Status         string
+	ConversationID *int
+	CreatedAt      time.Time
+	RespondedAt    *time.Time
+}
+
+type MatchRequestFilter struct {
+	// Incoming - заявки, адресованные пользователю, иначе отправленные им
+	Incoming bool
+	Status   string
+	Cursor   int // идентификатор последней полученной заявки, 0 - с начала
+	Limit    int
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+const (
+	ConversationDirect = "direct"
+	ConversationGroup  = "group"
+)
+
+// Conversation - личный диалог двух пользователей или небольшая группа
+type Conversation struct {
+	ID            int
+	Kind          string
+	Title         string
+	CreatedBy     int
+	Members       []ConversationMember
+	LastMessage   *Message
+	UnreadCount   int
+	CreatedAt     time.Time
+	LastMessageAt *time.Time
+}
+
+type ConversationMember struct {
+	UserID   int
+	Username string
+	Name     string
+	// LastReadMessageID - последнее сообщение, которое участник прочитал
+	LastReadMessageID int
+	JoinedAt          time.Time
+}
+
+type Message struct {
+	ID             int
+	ConversationID int
+	SenderID       int
+	Body           string
+	CreatedAt      time.Time
+	// HiddenAt задано, если сообщение скрыл модератор. Текст такого сообщения не отдаётся
+	HiddenAt *time.Time
+}
+
+type MessageFilter struct {
+	Cursor int // идентификатор последнего полученного сообщения, 0 - с конца переписки
+	Limit  int
+}
+
+// Block - пользователь, которого заблокировал текущий
+type Block struct {
+	UserID    int
+	Username  string
+	Name      string
+	CreatedAt time.Time
+}
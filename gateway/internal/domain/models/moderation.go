@@ -0,0 +1,104 @@
+package models
+
+import "time"
+
+// На что можно пожаловаться
+const (
+	ReportTargetUser    = "user"
+	ReportTargetEvent   = "event"
+	ReportTargetComment = "comment"
+	ReportTargetMessage = "message"
+)
+
+var ReportTargets = []string{
+	ReportTargetUser,
+	ReportTargetEvent,
+	ReportTargetComment,
+	ReportTargetMessage,
+}
+
+const (
+	ReportReasonSpam          = "spam"
+	ReportReasonAbuse         = "abuse"
+	ReportReasonInappropriate = "inappropriate"
+	ReportReasonOther         = "other"
+)
+
+var ReportReasons = []string{
+	ReportReasonSpam,
+	ReportReasonAbuse,
+	ReportReasonInappropriate,
+	ReportReasonOther,
+}
+
+const (
+	ReportOpen      = "open"
+	ReportDismissed = "dismissed"
+	ReportActioned  = "actioned"
+)
+
+// Решения модератора по жалобе
+const (
+	ModerationDismiss = "dismiss"
+	ModerationHide    = "hide"
+	ModerationWarn    = "warn"
+	ModerationSuspend = "suspend"
+)
+
+// Report - жалоба на пользователя или его содержимое. TargetUserID - автор
+// содержимого, Snapshot - текст содержимого на момент жалобы
+type Report struct {
+	ID           int
+	ReporterID   int
+	TargetType   string
+	TargetID     int
+	TargetUserID int
+	Reason       string
+	Details      string
+	Snapshot     string
+	Status       string
+	ResolvedBy   *int
+	ResolvedAt   *time.Time
+	CreatedAt    time.Time
+}
+
+type ReportFilter struct {
+	Status     string
+	TargetType string
+	Cursor     int // идентификатор последней полученной жалобы, 0 - с самой старой
+	Limit      int
+}
+
+// ReportTarget - то, на что жалуются: автор и текст для снимка
+type ReportTarget struct {
+	UserID  int
+	Content string
+}
+
+// ModerationDecision - решение модератора по жалобе. SuspendDays задаёт срок
+// блокировки для suspend, 0 - бессрочно
+type ModerationDecision struct {
+	Action      string
+	Note        string
+	SuspendDays int
+}
+
+// ModerationAction - запись журнала модерации
+type ModerationAction struct {
+	ID             int
+	ModeratorID    *int
+	Action         string
+	TargetType     string
+	TargetID       int
+	TargetUserID   *int
+	ReportIDs      []int
+	Note           string
+	SuspendedUntil *time.Time
+	CreatedAt      time.Time
+}
+
+type ModerationActionFilter struct {
+	TargetUserID int
+	Cursor       int // идентификатор последней полученной записи, 0 - с самой новой
+	Limit        int
+}
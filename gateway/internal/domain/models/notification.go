@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+const (
+	NotificationTalkAccepted    = "talk_accepted"
+	NotificationTalkRejected    = "talk_rejected"
+	NotificationMention         = "mention"
+	NotificationGroupDiscussion = "group_discussion"
+	NotificationEventReminder   = "event_reminder"
+	NotificationWebhookDisabled = "webhook_disabled"
+	NotificationMatchRequest    = "match_request"
+	NotificationMatchAccepted   = "match_accepted"
+	NotificationInvitation      = "invitation"
+	// NotificationModerationWarning и NotificationDataExportReady нельзя отключить,
+	// поэтому их нет в NotificationTypes
+	NotificationModerationWarning = "moderation_warning"
+	NotificationDataExportReady   = "data_export_ready"
+)
+
+// NotificationTypes - типы уведомлений, для которых пользователь может выбрать канал доставки
+var NotificationTypes = []string{
+	NotificationTalkAccepted,
+	NotificationTalkRejected,
+	NotificationMention,
+	NotificationGroupDiscussion,
+	NotificationEventReminder,
+	NotificationWebhookDisabled,
+	NotificationMatchRequest,
+	NotificationMatchAccepted,
+	NotificationInvitation,
+}
+
+const (
+	// NotificationChannelInApp - только в центре уведомлений
+	NotificationChannelInApp = "in_app"
+	// NotificationChannelEmail - в центре уведомлений и в периодической email-сводке
+	NotificationChannelEmail = "email"
+	// NotificationChannelNone - уведомления этого типа не доставляются
+	NotificationChannelNone = "none"
+)
+
+// Notification - уведомление пользователя. Уведомления с одинаковым GroupKey
+// копятся в одном непрочитанном уведомлении, Count показывает их число
+type Notification struct {
+	ID        int
+	UserID    int
+	Type      string
+	Title     string
+	Body      string
+	GroupKey  string
+	Count     int
+	ReadAt    *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type NotificationFilter struct {
+	UnreadOnly bool
+	Cursor     int // идентификатор последнего полученного уведомления, 0 - с начала
+	Limit      int
+}
+
+type NotificationPreference struct {
+	Type    string
+	Channel string
+}
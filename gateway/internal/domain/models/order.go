@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+const (
+	TicketKindFree      = "free"
+	TicketKindPaid      = "paid"
+	TicketKindEarlyBird = "early_bird"
+	TicketKindSponsor   = "sponsor"
+)
+
+var TicketKinds = []string{TicketKindFree, TicketKindPaid, TicketKindEarlyBird, TicketKindSponsor}
+
+// TicketType - тип билета мероприятия. Price - в минимальных единицах валюты Currency
+// (копейках), у бесплатных билетов 0. Reserved - сколько билетов оплачено или
+// удерживается неоплаченными заказами. После SalesEndAt билеты не продаются
+type TicketType struct {
+	ID         int
+	EventID    int
+	Kind       string
+	Name       string
+	Price      int64
+	Currency   string
+	Quantity   int
+	Reserved   int
+	SalesEndAt *time.Time
+	CreatedAt  time.Time
+}
+
+// Available - сколько билетов ещё можно заказать
+func (t TicketType) Available() int {
+	return t.Quantity - t.Reserved
+}
+
+// OnSale сообщает, продаются ли билеты в момент now
+func (t TicketType) OnSale(now time.Time) bool {
+	return t.SalesEndAt == nil || now.Before(*t.SalesEndAt)
+}
+
+const (
+	OrderStatusPending  = "pending"
+	OrderStatusPaid     = "paid"
+	OrderStatusRefunded = "refunded"
+	OrderStatusExpired  = "expired"
+)
+
+// Order - заказ билета. Неоплаченный заказ удерживает билет до ExpiresAt. PaymentID -
+// номер платежа у провайдера Provider, PaymentURL - страница оплаты для покупателя
+type Order struct {
+	ID           int
+	EventID      int
+	TicketTypeID int
+	UserID       int
+	Amount       int64
+	Currency     string
+	Status       string
+	Provider     string
+	PaymentID    string
+	PaymentURL   string
+	ExpiresAt    time.Time
+	PaidAt       *time.Time
+	RefundedAt   *time.Time
+	CreatedAt    time.Time
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Reminder - напоминание участнику о мероприятии за Offset до начала
+type Reminder struct {
+	EventID    int
+	UserID     int
+	Offset     time.Duration
+	EventTitle string
+	StartsAt   time.Time
+}
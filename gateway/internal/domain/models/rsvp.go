@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+const (
+	RSVPStatusGoing     = "going"
+	RSVPStatusCancelled = "cancelled"
+)
+
+// RSVP - запись пользователя на мероприятие. Её идентификатор служит номером билета
+type RSVP struct {
+	ID          int
+	EventID     int
+	UserID      int
+	Status      string
+	CheckedInAt *time.Time
+	CheckedInBy *int
+	CreatedAt   time.Time
+}
+
+type Ticket struct {
+	RSVP
+	Payload string
+}
+
+type Attendance struct {
+	Going     int
+	CheckedIn int
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	SearchTypeEvent     = "event"
+	SearchTypeGroup     = "group"
+	SearchTypeDeveloper = "developer"
+)
+
+type SearchQuery struct {
+	Query    string
+	Types    []string
+	City     string
+	DateFrom *time.Time
+	DateTo   *time.Time
+	Skill    string
+	Limit    int
+	Offset   int
+}
+
+// Wants сообщает, нужно ли искать сущности типа t. Пустой Types означает все типы
+func (q SearchQuery) Wants(t string) bool {
+	return len(q.Types) == 0 || slices.Contains(q.Types, t)
+}
+
+type SearchResult struct {
+	Type    string
+	ID      int
+	Title   string
+	Snippet string
+	Rank    float64
+}
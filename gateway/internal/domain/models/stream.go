@@ -0,0 +1,44 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	StreamEventNotification = "notification"
+	StreamEventRSVPCount    = "rsvp_count"
+	StreamEventAttendance   = "attendance"
+	StreamEventComment      = "comment"
+	StreamEventMessage      = "message"
+	StreamEventMessageRead  = "message_read"
+)
+
+const (
+	StreamTopicEvent = "event"
+	StreamTopicGroup = "group"
+)
+
+// StreamEvent - событие для real-time доставки. Адресовано либо одному пользователю
+// (UserID != nil), либо всем подписчикам темы вида "event:1" или "group:3"
+type StreamEvent struct {
+	ID        int64
+	UserID    *int
+	Topic     string
+	Type      string
+	Payload   json.RawMessage
+	CreatedAt time.Time
+}
+
+func StreamTopic(kind string, id int) string {
+	return fmt.Sprintf("%s:%d", kind, id)
+}
+
+// StreamSubscription - подписка клиента на события. Если клиент не успевает
+// их читать, канал Events закрывается, и клиент переподключается с Last-Event-ID
+type StreamSubscription struct {
+	UserID int
+	Topics []string
+	Events chan StreamEvent
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// TelegramLinkCode - одноразовый код, которым пользователь привязывает Telegram-аккаунт
+type TelegramLinkCode struct {
+	Code string
+	// Link открывает бота сразу с кодом, пустая если имя бота не настроено
+	Link      string
+	ExpiresAt time.Time
+}
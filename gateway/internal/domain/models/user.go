@@ -1,7 +1,56 @@
 package models
 
+import "time"
+
+const (
+	SeniorityJunior = "junior"
+	SeniorityMiddle = "middle"
+	SenioritySenior = "senior"
+	SeniorityLead   = "lead"
+)
+
+const (
+	UserRoleUser      = "user"
+	UserRoleModerator = "moderator"
+	UserRoleAdmin     = "admin"
+)
+
 type User struct {
-	ID       int
-	Email    string
-	PassHash string
+	ID    int
+	Email string
+	// EmailDuplicate - аккаунт заведён до нормализации почты на адрес, который в другом
+	// регистре уже был у другого аккаунта. Такие аккаунты объединяет администратор
+	EmailDuplicate bool
+	PassHash       string
+	Role           string
+	// SuspendedAt задано у заблокированного пользователя, SuspendedUntil - срок
+	// блокировки, nil - бессрочно
+	SuspendedAt      *time.Time
+	SuspendedUntil   *time.Time
+	SuspensionReason string
+	// TokenVersion растёт при принудительном выходе и отзывает ранее выданные токены
+	TokenVersion int
+	// DeletionScheduledAt задано, если пользователь запросил удаление аккаунта: после этого
+	// момента аккаунт будет обезличен. DeletedAt - аккаунт уже обезличен
+	DeletionScheduledAt *time.Time
+	DeletedAt           *time.Time
+	Profile
+}
+
+// Suspended сообщает, действует ли блокировка пользователя в момент now
+func (u User) Suspended(now time.Time) bool {
+	if u.SuspendedAt == nil {
+		return false
+	}
+
+	return u.SuspendedUntil == nil || now.Before(*u.SuspendedUntil)
+}
+
+type Profile struct {
+	Name      string
+	Username  string
+	City      string
+	Bio       string
+	Skills    []string
+	Seniority string
 }
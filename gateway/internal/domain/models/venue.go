@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+type Venue struct {
+	ID                 int
+	Name               string
+	Address            string
+	Location           GeoPoint
+	Capacity           int
+	AccessibilityNotes string
+	HostCompany        string
+	CreatedBy          int
+	CreatedAt          time.Time
+	// DistanceKm заполняется только при поиске по расстоянию
+	DistanceKm *float64
+}
+
+type GeoPoint struct {
+	Lat float64
+	Lon float64
+}
+
+// GeoFilter ограничивает выборку окружностью радиуса RadiusKm вокруг Point
+type GeoFilter struct {
+	Point    GeoPoint
+	RadiusKm float64
+}
+
+type VenueFilter struct {
+	Query  string
+	Near   *GeoFilter
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	WebhookEventPublished = "event.published"
+	WebhookRSVPCreated    = "rsvp.created"
+	WebhookEventCancelled = "event.cancelled"
+)
+
+// WebhookEventTypes - события, на которые можно подписать вебхук
+var WebhookEventTypes = []string{
+	WebhookEventPublished,
+	WebhookRSVPCreated,
+	WebhookEventCancelled,
+}
+
+// Webhook - адрес, на который отправляются события сообщества
+type Webhook struct {
+	ID         int
+	GroupID    int
+	URL        string
+	Secret     string
+	EventTypes []string
+	Active     bool
+	// FailureCount - число неудачных доставок подряд
+	FailureCount int
+	DisabledAt   *time.Time
+	CreatedAt    time.Time
+}
+
+// WebhookDelivery - попытка доставки события на вебхук
+type WebhookDelivery struct {
+	ID         int64
+	WebhookID  int
+	DeliveryID string
+	EventType  string
+	Payload    json.RawMessage
+	Attempt    int
+	StatusCode int
+	Error      string
+	Duration   time.Duration
+	Success    bool
+	CreatedAt  time.Time
+}
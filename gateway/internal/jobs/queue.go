@@ -0,0 +1,304 @@
+// Package jobs - очередь фоновых задач поверх Postgres. Задачи переживают перезапуск,
+// выполняются на любой из реплик, повторяются с экспоненциальной задержкой, а исчерпав
+// попытки, остаются в статусе dead до ручного перезапуска
+package jobs
+
+import (
+	"context"
+	"dev_meets/internal/domain/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxAttempts = 10
+	minBackoff         = 10 * time.Second
+	maxBackoff         = time.Hour
+	doneRetention      = 7 * 24 * time.Hour
+	cleanupPeriod      = time.Hour
+)
+
+var ErrUnknownJobType = errors.New("no handler registered for job type")
+
+// Storage хранит задачи очереди
+type Storage interface {
+	CreateJob(job models.Job) (int64, error)
+	ClaimJobs(types []string, limit int, lease time.Duration) ([]models.Job, error)
+	// CompleteJob, RetryJob и BuryJob возвращают false, если попытка attempt уже не владеет
+	// задачей: воркер не уложился в lease и задачу забрал другой
+	CompleteJob(id int64, attempt int) (bool, error)
+	RetryJob(id int64, attempt int, runAt time.Time, lastError string) (bool, error)
+	BuryJob(id int64, attempt int, lastError string) (bool, error)
+	DeleteDoneJobsBefore(before time.Time) (int, error)
+}
+
+// HandlerFunc выполняет задачу. Ошибка означает, что задачу нужно повторить позже,
+// ошибка, обёрнутая в Permanent, - что повторять бессмысленно
+type HandlerFunc func(ctx context.Context, payload json.RawMessage) error
+
+type Config struct {
+	// Concurrency - сколько задач одна реплика выполняет одновременно
+	Concurrency int
+	// PollInterval - как часто проверять очередь на новые задачи
+	PollInterval time.Duration
+	// Lease - сколько времени даётся на выполнение задачи. Задачу, не завершённую
+	// за это время, заберёт другой воркер
+	Lease time.Duration
+}
+
+type Queue struct {
+	repo   Storage
+	config Config
+	logger *slog.Logger
+
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+
+	// running отменяется, если задачи не успели завершиться за время Drain
+	running     context.Context
+	stopRunning context.CancelFunc
+	inFlight    sync.WaitGroup
+	slots       chan struct{}
+	// stopped закрывается, когда Run вернулся: после этого poll не добавит новых задач в inFlight
+	stopped chan struct{}
+}
+
+func NewQueue(repo Storage, config Config, logger *slog.Logger) *Queue {
+	running, stopRunning := context.WithCancel(context.Background())
+
+	return &Queue{
+		repo:        repo,
+		config:      config,
+		logger:      logger,
+		handlers:    make(map[string]HandlerFunc),
+		running:     running,
+		stopRunning: stopRunning,
+		slots:       make(chan struct{}, config.Concurrency),
+		stopped:     make(chan struct{}),
+	}
+}
+
+// Handle регистрирует обработчик задач типа jobType с полезной нагрузкой типа T.
+// Нагрузка, которую не удалось разобрать, сразу переводит задачу в dead
+func Handle[T any](q *Queue, jobType string, handler func(ctx context.Context, payload T) error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.handlers[jobType] = func(ctx context.Context, raw json.RawMessage) error {
+		var payload T
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return Permanent(fmt.Errorf("decode payload: %w", err))
+		}
+
+		return handler(ctx, payload)
+	}
+}
+
+// Enqueue ставит задачу в очередь на немедленное выполнение
+func (q *Queue) Enqueue(jobType string, payload any) (int64, error) {
+	return q.EnqueueAt(jobType, payload, time.Now())
+}
+
+// EnqueueAt ставит задачу в очередь на выполнение не раньше runAt
+func (q *Queue) EnqueueAt(jobType string, payload any, runAt time.Time) (int64, error) {
+	const op = "jobs.Queue.EnqueueAt"
+
+	q.mu.RLock()
+	_, ok := q.handlers[jobType]
+	q.mu.RUnlock()
+	if !ok {
+		return 0, fmt.Errorf("%s: %w: %s", op, ErrUnknownJobType, jobType)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := q.repo.CreateJob(models.Job{
+		Type:        jobType,
+		Payload:     data,
+		MaxAttempts: defaultMaxAttempts,
+		RunAt:       runAt,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// Run забирает и выполняет задачи, пока не отменён ctx. Уже начатые задачи
+// продолжают выполняться, дождаться их можно через Drain
+func (q *Queue) Run(ctx context.Context) {
+	defer close(q.stopped)
+
+	poll := time.NewTicker(q.config.PollInterval)
+	defer poll.Stop()
+
+	cleanup := time.NewTicker(cleanupPeriod)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+			q.poll(ctx)
+		case <-cleanup.C:
+			deleted, err := q.repo.DeleteDoneJobsBefore(time.Now().Add(-doneRetention))
+			if err != nil {
+				q.logger.Error("failed to clean up jobs", slog.String("error", err.Error()))
+				continue
+			}
+			q.logger.Debug("jobs cleaned up", slog.Int("deleted", deleted))
+		}
+	}
+}
+
+// Drain ждёт завершения начатых задач не дольше timeout. Задачи, не успевшие
+// завершиться, отменяются и после истечения Lease достанутся другой реплике.
+// Вызывается после отмены контекста Run: сначала дожидается выхода из Run, чтобы
+// задачи, которые poll успел забрать, тоже попали в ожидание
+func (q *Queue) Drain(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		<-q.stopped
+		q.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		q.logger.Info("jobs did not finish in time, cancelling")
+		q.stopRunning()
+		<-done
+	}
+}
+
+// poll забирает столько задач, сколько свободных воркеров, пока очередь не опустеет
+func (q *Queue) poll(ctx context.Context) {
+	q.mu.RLock()
+	types := make([]string, 0, len(q.handlers))
+	for jobType := range q.handlers {
+		types = append(types, jobType)
+	}
+	q.mu.RUnlock()
+
+	for ctx.Err() == nil {
+		free := cap(q.slots) - len(q.slots)
+		if free == 0 {
+			return
+		}
+
+		claimed, err := q.repo.ClaimJobs(types, free, q.config.Lease)
+		if err != nil {
+			q.logger.Error("failed to claim jobs", slog.String("error", err.Error()))
+			return
+		}
+
+		for _, job := range claimed {
+			q.slots <- struct{}{}
+			q.inFlight.Add(1)
+			go q.process(job)
+		}
+
+		if len(claimed) < free {
+			return
+		}
+	}
+}
+
+func (q *Queue) process(job models.Job) {
+	defer func() {
+		<-q.slots
+		q.inFlight.Done()
+	}()
+
+	q.mu.RLock()
+	handler := q.handlers[job.Type]
+	q.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(q.running, q.config.Lease)
+	defer cancel()
+
+	err := safeCall(ctx, handler, job.Payload)
+	if err == nil {
+		owned, err := q.repo.CompleteJob(job.ID, job.Attempts)
+		if err != nil {
+			q.logger.Error("failed to complete job", slog.Int64("job_id", job.ID), slog.String("error", err.Error()))
+		} else if !owned {
+			q.lostJob(job)
+		}
+		return
+	}
+
+	log := q.logger.With(
+		slog.Int64("job_id", job.ID),
+		slog.String("type", job.Type),
+		slog.Int("attempt", job.Attempts),
+		slog.String("error", err.Error()),
+	)
+
+	if isPermanent(err) || job.Attempts >= job.MaxAttempts {
+		log.Error("job failed, moving to dead")
+		owned, err := q.repo.BuryJob(job.ID, job.Attempts, err.Error())
+		if err != nil {
+			q.logger.Error("failed to bury job", slog.Int64("job_id", job.ID), slog.String("error", err.Error()))
+		} else if !owned {
+			q.lostJob(job)
+		}
+		return
+	}
+
+	log.Info("job failed, will retry")
+	owned, err := q.repo.RetryJob(job.ID, job.Attempts, time.Now().Add(Backoff(job.Attempts)), err.Error())
+	if err != nil {
+		q.logger.Error("failed to reschedule job", slog.Int64("job_id", job.ID), slog.String("error", err.Error()))
+	} else if !owned {
+		q.lostJob(job)
+	}
+}
+
+// lostJob отмечает в логе попытку, результат которой отброшен: пока она выполнялась,
+// истёк lease и задачу забрал другой воркер. Судьбу задачи решит его попытка
+func (q *Queue) lostJob(job models.Job) {
+	q.logger.Warn("job lease lost, result discarded",
+		slog.Int64("job_id", job.ID),
+		slog.String("type", job.Type),
+		slog.Int("attempt", job.Attempts),
+	)
+}
+
+// safeCall превращает панику обработчика в ошибку, чтобы она не роняла воркер
+func safeCall(ctx context.Context, handler HandlerFunc, payload json.RawMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return handler(ctx, payload)
+}
+
+// Backoff возвращает задержку перед следующей попыткой: 10s, 20s, 40s и так далее до часа
+func Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	delay := minBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= maxBackoff {
+			return maxBackoff
+		}
+	}
+
+	return delay
+}
@@ -0,0 +1,264 @@
+package jobs
+
+import (
+	"context"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryStorage повторяет семантику закрепления задач JobPostgres: каждая выдача
+// увеличивает attempts, а завершить задачу может только попытка, которая ею владеет
+type memoryStorage struct {
+	mu   sync.Mutex
+	jobs map[int64]*models.Job
+	next int64
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{jobs: make(map[int64]*models.Job)}
+}
+
+func (s *memoryStorage) CreateJob(job models.Job) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.next++
+	job.ID = s.next
+	job.Status = models.JobStatusPending
+	s.jobs[job.ID] = &job
+
+	return job.ID, nil
+}
+
+// ClaimJobs выдаёт задачи без учёта lease: тест сам решает, когда задача "перехвачена"
+func (s *memoryStorage) ClaimJobs(types []string, limit int, lease time.Duration) ([]models.Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var claimed []models.Job
+	for _, job := range s.jobs {
+		if len(claimed) == limit || job.Status == models.JobStatusDone || job.Status == models.JobStatusDead {
+			continue
+		}
+		job.Status = models.JobStatusRunning
+		job.Attempts++
+		claimed = append(claimed, *job)
+	}
+
+	return claimed, nil
+}
+
+func (s *memoryStorage) finish(id int64, attempt int, update func(job *models.Job)) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok || job.Status != models.JobStatusRunning || job.Attempts != attempt {
+		return false, nil
+	}
+	update(job)
+
+	return true, nil
+}
+
+func (s *memoryStorage) CompleteJob(id int64, attempt int) (bool, error) {
+	return s.finish(id, attempt, func(job *models.Job) {
+		job.Status = models.JobStatusDone
+		job.LastError = ""
+	})
+}
+
+func (s *memoryStorage) RetryJob(id int64, attempt int, runAt time.Time, lastError string) (bool, error) {
+	return s.finish(id, attempt, func(job *models.Job) {
+		job.Status = models.JobStatusPending
+		job.RunAt = runAt
+		job.LastError = lastError
+	})
+}
+
+func (s *memoryStorage) BuryJob(id int64, attempt int, lastError string) (bool, error) {
+	return s.finish(id, attempt, func(job *models.Job) {
+		job.Status = models.JobStatusDead
+		job.LastError = lastError
+	})
+}
+
+func (s *memoryStorage) DeleteDoneJobsBefore(before time.Time) (int, error) {
+	return 0, nil
+}
+
+func (s *memoryStorage) job(id int64) models.Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return *s.jobs[id]
+}
+
+func newTestQueue(repo Storage) *Queue {
+	return NewQueue(repo, Config{Concurrency: 2, PollInterval: time.Second, Lease: time.Minute},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+// runClaimed выполняет выданную задачу так же, как poll, но синхронно
+func runClaimed(q *Queue, job models.Job) {
+	q.slots <- struct{}{}
+	q.inFlight.Add(1)
+	q.process(job)
+}
+
+func claimOne(t *testing.T, repo *memoryStorage) models.Job {
+	t.Helper()
+
+	claimed, err := repo.ClaimJobs(nil, 1, time.Minute)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("ClaimJobs() = %v, %v, want one job", claimed, err)
+	}
+
+	return claimed[0]
+}
+
+func TestQueueLostLease(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(ctx context.Context, payload struct{}) error
+	}{
+		{
+			name:    "complete",
+			handler: func(ctx context.Context, payload struct{}) error { return nil },
+		},
+		{
+			name:    "retry",
+			handler: func(ctx context.Context, payload struct{}) error { return errors.New("temporary") },
+		},
+		{
+			name:    "bury",
+			handler: func(ctx context.Context, payload struct{}) error { return Permanent(errors.New("broken")) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryStorage()
+			q := newTestQueue(repo)
+			Handle(q, "test", tt.handler)
+
+			id, err := q.Enqueue("test", struct{}{})
+			if err != nil {
+				t.Fatalf("Enqueue() error = %v", err)
+			}
+
+			// первый воркер не уложился в lease, и задачу забрал второй
+			stale := claimOne(t, repo)
+			current := claimOne(t, repo)
+
+			runClaimed(q, stale)
+
+			job := repo.job(id)
+			if job.Status != models.JobStatusRunning || job.Attempts != current.Attempts {
+				t.Fatalf("stale attempt changed job: status %q, attempts %d", job.Status, job.Attempts)
+			}
+
+			runClaimed(q, current)
+
+			if job := repo.job(id); job.Status == models.JobStatusRunning {
+				t.Errorf("current attempt did not finish job: status %q", job.Status)
+			}
+		})
+	}
+}
+
+func TestQueueProcess(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus string
+	}{
+		{name: "success", err: nil, wantStatus: models.JobStatusDone},
+		{name: "temporary error", err: errors.New("temporary"), wantStatus: models.JobStatusPending},
+		{name: "permanent error", err: Permanent(errors.New("broken")), wantStatus: models.JobStatusDead},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryStorage()
+			q := newTestQueue(repo)
+			Handle(q, "test", func(ctx context.Context, payload struct{}) error { return tt.err })
+
+			id, err := q.Enqueue("test", struct{}{})
+			if err != nil {
+				t.Fatalf("Enqueue() error = %v", err)
+			}
+
+			runClaimed(q, claimOne(t, repo))
+
+			if job := repo.job(id); job.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", job.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// blockingStorage задерживает ClaimJobs, пока тест не отпустит release
+type blockingStorage struct {
+	*memoryStorage
+	claiming chan struct{}
+	release  chan struct{}
+}
+
+func (s *blockingStorage) ClaimJobs(types []string, limit int, lease time.Duration) ([]models.Job, error) {
+	close(s.claiming)
+	<-s.release
+
+	return s.memoryStorage.ClaimJobs(types, limit, lease)
+}
+
+// Задача, которую poll забрал уже после начала Drain, тоже успевает завершиться
+func TestQueueDrainWaitsForPoll(t *testing.T) {
+	repo := &blockingStorage{memoryStorage: newMemoryStorage(), claiming: make(chan struct{}), release: make(chan struct{})}
+	q := NewQueue(repo, Config{Concurrency: 1, PollInterval: time.Millisecond, Lease: time.Minute},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	finished := make(chan struct{})
+	Handle(q, "test", func(ctx context.Context, payload struct{}) error {
+		close(finished)
+		return nil
+	})
+	id, err := q.Enqueue("test", struct{}{})
+	if err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go q.Run(ctx)
+	<-repo.claiming
+	cancel()
+
+	drained := make(chan struct{})
+	go func() {
+		q.Drain(time.Minute)
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		t.Fatal("Drain() returned while poll was claiming jobs")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(repo.release)
+	<-drained
+
+	select {
+	case <-finished:
+	default:
+		t.Fatal("Drain() returned before the claimed job ran")
+	}
+	if job := repo.job(id); job.Status != models.JobStatusDone {
+		t.Errorf("status = %q, want %q", job.Status, models.JobStatusDone)
+	}
+}
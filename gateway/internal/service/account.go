@@ -0,0 +1,513 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/jobs"
+	"dev_meets/internal/storage"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+	"log/slog"
+	"time"
+)
+
+const (
+	jobDeleteAccount      = "account.delete"
+	jobExportData         = "account.export"
+	jobDeleteDataExports  = "account.delete_exports"
+	dataExportTokenBytes  = 32
+	emailChangeTokenBytes = 32
+	accountDeletionLayout = "02.01.2006 15:04 MST"
+)
+
+var ErrInvalidEmailChange = errors.New("new email matches the current one")
+
+type AccountConfig struct {
+	// DeletionGrace - сколько ждать перед удалением аккаунта: за это время
+	// пользователь может передумать, войдя снова
+	DeletionGrace time.Duration
+	// ExportTTL - сколько действует ссылка на готовую выгрузку данных
+	ExportTTL time.Duration
+	// EmailChangeTTL - сколько действует секрет из письма для подтверждения новой почты
+	EmailChangeTTL time.Duration
+}
+
+// AccountService меняет почту пользователей, удаляет аккаунты по их запросу и выгружает
+// их данные. Удаление и сборка выгрузки - задачи в очереди: удаление выполняется по
+// истечении срока ожидания, выгрузка собирается в фоне и скачивается по ссылке с секретом
+type AccountService struct {
+	repo     AccountStorageInt
+	users    UserStorageInt
+	queue    *jobs.Queue
+	notifier Notifier
+	mailer   Mailer
+	audit    Auditor
+	config   AccountConfig
+	logger   *slog.Logger
+}
+
+type accountJob struct {
+	UserID int `json:"user_id"`
+}
+
+type dataExportJob struct {
+	ExportID int `json:"export_id"`
+	UserID   int `json:"user_id"`
+}
+
+func NewAccountService(
+	repo AccountStorageInt,
+	users UserStorageInt,
+	queue *jobs.Queue,
+	notifier Notifier,
+	mailer Mailer,
+	audit Auditor,
+	config AccountConfig,
+	logger *slog.Logger,
+) *AccountService {
+	s := &AccountService{
+		repo:     repo,
+		users:    users,
+		queue:    queue,
+		notifier: notifier,
+		mailer:   mailer,
+		audit:    audit,
+		config:   config,
+		logger:   logger,
+	}
+	jobs.Handle(queue, jobDeleteAccount, s.deleteAccount)
+	jobs.Handle(queue, jobExportData, s.exportData)
+	jobs.Handle(queue, jobDeleteDataExports, s.deleteDataExports)
+
+	return s
+}
+
+// RequestEmailChange начинает смену почты после проверки пароля. На новый адрес уходит
+// секрет для подтверждения, на текущий - предупреждение. Почта меняется только в
+// ConfirmEmailChange, до этого вход по-прежнему выполняется по текущему адресу
+func (s *AccountService) RequestEmailChange(
+	ctx context.Context,
+	userID int,
+	password, newEmail string,
+) (models.EmailChange, error) {
+	const op = "service.AccountService.RequestEmailChange"
+
+	user, err := s.users.User(userID)
+	if err != nil {
+		return models.EmailChange{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PassHash), []byte(password)); err != nil {
+		return models.EmailChange{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+	}
+	newEmail = normalizeEmail(newEmail)
+	if newEmail == normalizeEmail(user.Email) {
+		return models.EmailChange{}, fmt.Errorf("%s: %w", op, ErrInvalidEmailChange)
+	}
+
+	// занятость адреса проверяется заранее, чтобы не слать письмо впустую. Окончательно
+	// её проверяет уникальный индекс при подтверждении
+	_, err = s.users.UserByEmail(newEmail)
+	if err == nil {
+		return models.EmailChange{}, fmt.Errorf("%s: %w", op, storage.ErrEmailTaken)
+	}
+	if !errors.Is(err, storage.ErrUserNotFound) {
+		return models.EmailChange{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	token := make([]byte, emailChangeTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return models.EmailChange{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	change, err := s.repo.CreateEmailChange(models.EmailChange{
+		UserID:    userID,
+		NewEmail:  newEmail,
+		Token:     hex.EncodeToString(token),
+		ExpiresAt: time.Now().Add(s.config.EmailChangeTTL),
+	})
+	if err != nil {
+		return models.EmailChange{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.audit.Audit(newAuditEvent(ctx, userID, models.AuditEmailChangeRequested, models.AuditTargetUser, userID,
+		map[string]string{"email": user.Email}, map[string]string{"new_email": newEmail}))
+
+	body := fmt.Sprintf("Код подтверждения новой почты: %s. Он действует до %s. Если вы не меняли "+
+		"почту, просто проигнорируйте это письмо.", change.Token, change.ExpiresAt.Format(accountDeletionLayout))
+	if err := s.mailer.Send(newEmail, "Подтверждение почты", body); err != nil {
+		s.logger.Error("failed to send email change confirmation",
+			slog.Int("user_id", userID), slog.String("error", err.Error()),
+		)
+	}
+
+	body = fmt.Sprintf("Запрошена смена почты аккаунта на %s. Если это были не вы, смените пароль.", newEmail)
+	if err := s.mailer.Send(user.Email, "Смена почты", body); err != nil {
+		s.logger.Error("failed to send email change notice",
+			slog.Int("user_id", userID), slog.String("error", err.Error()),
+		)
+	}
+
+	return change, nil
+}
+
+// ConfirmEmailChange меняет почту по секрету из письма. Все выданные токены отзываются,
+// дальше вход выполняется по новому адресу
+func (s *AccountService) ConfirmEmailChange(ctx context.Context, token string) error {
+	const op = "service.AccountService.ConfirmEmailChange"
+
+	change, err := s.repo.EmailChange(token)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := s.users.User(change.UserID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	audit := newAuditEvent(ctx, user.ID, models.AuditEmailChanged, models.AuditTargetUser, user.ID,
+		map[string]string{"email": user.Email}, map[string]string{"email": change.NewEmail})
+	if err := s.repo.CompleteEmailChange(change, audit); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("email changed", slog.Int("user_id", user.ID))
+
+	body := fmt.Sprintf("Почта аккаунта изменена на %s, все сеансы завершены. Если это были не вы, "+
+		"обратитесь в поддержку.", change.NewEmail)
+	if err := s.mailer.Send(user.Email, "Почта изменена", body); err != nil {
+		s.logger.Error("failed to send email changed notice",
+			slog.Int("user_id", user.ID), slog.String("error", err.Error()),
+		)
+	}
+
+	return nil
+}
+
+// DeleteAccount назначает удаление аккаунта после проверки пароля. Выданные токены
+// отзываются; вход до назначенного момента отменяет удаление. Модератору или
+// администратору сначала нужно снять роль, чтобы платформа не осталась без них незаметно
+func (s *AccountService) DeleteAccount(ctx context.Context, userID int, password string) (time.Time, error) {
+	const op = "service.AccountService.DeleteAccount"
+
+	user, err := s.users.User(userID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PassHash), []byte(password)); err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+	}
+	if user.Role != models.UserRoleUser {
+		return time.Time{}, fmt.Errorf("%s: %w", op, ErrForbidden)
+	}
+
+	// задача ставится первой: без назначенного удаления она ничего не сделает,
+	// а назначенное удаление без задачи не выполнилось бы никогда
+	at := time.Now().Add(s.config.DeletionGrace)
+	if _, err := s.queue.EnqueueAt(jobDeleteAccount, accountJob{UserID: userID}, at); err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	audit := newAuditEvent(ctx, userID, models.AuditAccountDeletionRequested, models.AuditTargetUser, userID,
+		nil, map[string]time.Time{"deletion_scheduled_at": at})
+	if err := s.repo.ScheduleDeletion(userID, at, audit); err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("account deletion scheduled", slog.Int("user_id", userID), slog.Time("at", at))
+
+	body := fmt.Sprintf("Аккаунт и личные данные будут удалены %s. Чтобы отменить удаление, "+
+		"войдите в аккаунт до этого момента.", at.Format(accountDeletionLayout))
+	if err := s.mailer.Send(user.Email, "Удаление аккаунта", body); err != nil {
+		s.logger.Error("failed to send account deletion email",
+			slog.Int("user_id", userID), slog.String("error", err.Error()),
+		)
+	}
+
+	return at, nil
+}
+
+// deleteAccount обезличивает аккаунт, если удаление не отменили
+func (s *AccountService) deleteAccount(ctx context.Context, job accountJob) error {
+	audit := newAuditEvent(ctx, job.UserID, models.AuditAccountDeleted, models.AuditTargetUser, job.UserID, nil, nil)
+	if err := s.repo.AnonymizeUser(job.UserID, audit); err != nil {
+		if errors.Is(err, storage.ErrDeletionNotScheduled) {
+			return nil
+		}
+
+		return err
+	}
+
+	s.logger.Info("account deleted", slog.Int("user_id", job.UserID))
+
+	return nil
+}
+
+// RequestDataExport ставит в очередь сборку архива с данными пользователя. Пока
+// предыдущая выгрузка собирается, возвращается она
+func (s *AccountService) RequestDataExport(ctx context.Context, userID int) (models.DataExport, error) {
+	const op = "service.AccountService.RequestDataExport"
+
+	latest, err := s.repo.LatestDataExport(userID)
+	if err == nil && latest.Status == models.DataExportPending {
+		return latest, nil
+	}
+	if err != nil && !errors.Is(err, storage.ErrDataExportNotFound) {
+		return models.DataExport{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	token := make([]byte, dataExportTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return models.DataExport{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	export, err := s.repo.CreateDataExport(userID, hex.EncodeToString(token))
+	if err != nil {
+		return models.DataExport{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := s.queue.Enqueue(jobExportData, dataExportJob{ExportID: export.ID, UserID: userID}); err != nil {
+		return models.DataExport{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.audit.Audit(newAuditEvent(ctx, userID, models.AuditDataExportRequested, models.AuditTargetUser, userID,
+		nil, map[string]int{"export_id": export.ID}))
+
+	return export, nil
+}
+
+// DataExport возвращает последнюю выгрузку пользователя, ссылка на которую ещё действует
+func (s *AccountService) DataExport(userID int) (models.DataExport, error) {
+	const op = "service.AccountService.DataExport"
+
+	export, err := s.repo.LatestDataExport(userID)
+	if err != nil {
+		return models.DataExport{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return export, nil
+}
+
+// DataExportArchive возвращает готовый архив по секрету из ссылки
+func (s *AccountService) DataExportArchive(token string) (models.DataExport, error) {
+	const op = "service.AccountService.DataExportArchive"
+
+	export, err := s.repo.DataExportArchive(token)
+	if err != nil {
+		return models.DataExport{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return export, nil
+}
+
+// exportProfile - профиль в выгрузке. Хеш пароля, версия токенов и служебные поля в неё не попадают
+type exportProfile struct {
+	ID         int       `json:"id"`
+	Email      string    `json:"email"`
+	Name       string    `json:"name"`
+	Username   string    `json:"username,omitempty"`
+	City       string    `json:"city"`
+	Bio        string    `json:"bio"`
+	Skills     []string  `json:"skills"`
+	Seniority  string    `json:"seniority,omitempty"`
+	Role       string    `json:"role"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+type exportRSVP struct {
+	ID          int        `json:"id"`
+	EventID     int        `json:"event_id"`
+	Status      string     `json:"status"`
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type exportComment struct {
+	ID          int        `json:"id"`
+	SubjectType string     `json:"subject_type"`
+	SubjectID   int        `json:"subject_id"`
+	ParentID    *int       `json:"parent_id,omitempty"`
+	Body        string     `json:"body"`
+	CreatedAt   time.Time  `json:"created_at"`
+	EditedAt    *time.Time `json:"edited_at,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	HiddenAt    *time.Time `json:"hidden_at,omitempty"`
+}
+
+type exportMessage struct {
+	ID             int        `json:"id"`
+	ConversationID int        `json:"conversation_id"`
+	Body           string     `json:"body"`
+	CreatedAt      time.Time  `json:"created_at"`
+	HiddenAt       *time.Time `json:"hidden_at,omitempty"`
+}
+
+// exportData собирает архив выгрузки, сохраняет его и сообщает пользователю, что он готов
+func (s *AccountService) exportData(ctx context.Context, job dataExportJob) error {
+	archive, err := s.buildDataArchive(job.UserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return jobs.Permanent(err)
+		}
+
+		return err
+	}
+
+	expiresAt := time.Now().Add(s.config.ExportTTL)
+	if err := s.repo.CompleteDataExport(job.ExportID, archive, expiresAt); err != nil {
+		if errors.Is(err, storage.ErrDataExportNotFound) {
+			return jobs.Permanent(err)
+		}
+
+		return err
+	}
+
+	if _, err := s.queue.EnqueueAt(jobDeleteDataExports, struct{}{}, expiresAt); err != nil {
+		s.logger.Error("failed to schedule data export cleanup", slog.String("error", err.Error()))
+	}
+
+	err = s.notifier.Notify(models.Notification{
+		UserID: job.UserID,
+		Type:   models.NotificationDataExportReady,
+		Title:  "Выгрузка данных готова",
+		Body:   "Скачать архив можно до " + expiresAt.Format(accountDeletionLayout),
+	})
+	if err != nil {
+		s.logger.Error("failed to notify about data export", slog.String("error", err.Error()))
+	}
+
+	s.logger.Info("data export ready", slog.Int("user_id", job.UserID), slog.Int("export_id", job.ExportID))
+
+	return nil
+}
+
+// buildDataArchive собирает ZIP с JSON-файлами профиля, записей на мероприятия,
+// комментариев и сообщений пользователя
+func (s *AccountService) buildDataArchive(userID int) ([]byte, error) {
+	user, err := s.users.User(userID)
+	if err != nil {
+		return nil, err
+	}
+	rsvps, err := s.repo.UserRSVPs(userID)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := s.repo.UserComments(userID)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := s.repo.UserMessages(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", exportProfile{
+			ID:         user.ID,
+			Email:      user.Email,
+			Name:       user.Name,
+			Username:   user.Username,
+			City:       user.City,
+			Bio:        user.Bio,
+			Skills:     user.Skills,
+			Seniority:  user.Seniority,
+			Role:       user.Role,
+			ExportedAt: time.Now(),
+		}},
+		{"rsvps.json", newExportRSVPs(rsvps)},
+		{"comments.json", newExportComments(comments)},
+		{"messages.json", newExportMessages(messages)},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func newExportRSVPs(rsvps []models.RSVP) []exportRSVP {
+	exported := make([]exportRSVP, 0, len(rsvps))
+	for _, rsvp := range rsvps {
+		exported = append(exported, exportRSVP{
+			ID:          rsvp.ID,
+			EventID:     rsvp.EventID,
+			Status:      rsvp.Status,
+			CheckedInAt: rsvp.CheckedInAt,
+			CreatedAt:   rsvp.CreatedAt,
+		})
+	}
+
+	return exported
+}
+
+func newExportComments(comments []models.Comment) []exportComment {
+	exported := make([]exportComment, 0, len(comments))
+	for _, comment := range comments {
+		exported = append(exported, exportComment{
+			ID:          comment.ID,
+			SubjectType: comment.SubjectType,
+			SubjectID:   comment.SubjectID,
+			ParentID:    comment.ParentID,
+			Body:        comment.Body,
+			CreatedAt:   comment.CreatedAt,
+			EditedAt:    comment.EditedAt,
+			DeletedAt:   comment.DeletedAt,
+			HiddenAt:    comment.HiddenAt,
+		})
+	}
+
+	return exported
+}
+
+func newExportMessages(messages []models.Message) []exportMessage {
+	exported := make([]exportMessage, 0, len(messages))
+	for _, message := range messages {
+		exported = append(exported, exportMessage{
+			ID:             message.ID,
+			ConversationID: message.ConversationID,
+			Body:           message.Body,
+			CreatedAt:      message.CreatedAt,
+			HiddenAt:       message.HiddenAt,
+		})
+	}
+
+	return exported
+}
+
+// deleteDataExports удаляет выгрузки, ссылки на которые истекли
+func (s *AccountService) deleteDataExports(ctx context.Context, _ struct{}) error {
+	deleted, err := s.repo.DeleteExpiredDataExports()
+	if err != nil {
+		return err
+	}
+
+	s.logger.Debug("expired data exports deleted", slog.Int("deleted", deleted))
+
+	return nil
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const maxSuspensionReason = 1000
+
+var (
+	ErrInvalidRole        = errors.New("unknown user role")
+	ErrOwnAccount         = errors.New("action is not allowed on own account")
+	ErrInvalidMerge       = errors.New("cannot merge account into itself")
+	ErrInvalidSuspension  = errors.New("invalid suspension duration or reason")
+	ErrInvalidAdminFilter = errors.New("invalid user search filter")
+)
+
+// AdminService - управление аккаунтами пользователей администраторами платформы.
+// Каждое изменение записывается в журнал аудита в той же транзакции
+type AdminService struct {
+	repo   AdminStorageInt
+	users  UserStorageInt
+	logger *slog.Logger
+}
+
+func NewAdminService(repo AdminStorageInt, users UserStorageInt, logger *slog.Logger) *AdminService {
+	return &AdminService{repo: repo, users: users, logger: logger}
+}
+
+// auditUser - состояние аккаунта, которое попадает в журнал аудита
+type auditUser struct {
+	ID               int        `json:"id"`
+	Email            string     `json:"email"`
+	Name             string     `json:"name,omitempty"`
+	Username         string     `json:"username,omitempty"`
+	Role             string     `json:"role"`
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`
+	SuspendedUntil   *time.Time `json:"suspended_until,omitempty"`
+	SuspensionReason string     `json:"suspension_reason,omitempty"`
+	TokenVersion     int        `json:"token_version"`
+}
+
+func newAuditUser(user models.User) auditUser {
+	return auditUser{
+		ID:               user.ID,
+		Email:            user.Email,
+		Name:             user.Name,
+		Username:         user.Username,
+		Role:             user.Role,
+		SuspendedAt:      user.SuspendedAt,
+		SuspendedUntil:   user.SuspendedUntil,
+		SuspensionReason: user.SuspensionReason,
+		TokenVersion:     user.TokenVersion,
+	}
+}
+
+// userAudit готовит запись аудита об изменении пользователя before на after
+func userAudit(ctx context.Context, adminID int, action string, before, after models.User) models.AuditEvent {
+	return newAuditEvent(ctx, adminID, action, models.AuditTargetUser, before.ID, newAuditUser(before), newAuditUser(after))
+}
+
+func (s *AdminService) AdminUsers(adminID int, filter models.AdminUserFilter) ([]models.User, error) {
+	const op = "service.AdminService.AdminUsers"
+
+	if err := requireAdmin(s.users, adminID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	filter.Query = strings.TrimSpace(filter.Query)
+	if filter.Role != "" && !slices.Contains(models.UserRoles, filter.Role) {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidAdminFilter)
+	}
+
+	users, err := s.repo.SearchUsers(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+func (s *AdminService) AdminUser(adminID, userID int) (models.AccountState, error) {
+	const op = "service.AdminService.AdminUser"
+
+	if err := requireAdmin(s.users, adminID); err != nil {
+		return models.AccountState{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	state, err := s.repo.AccountState(userID)
+	if err != nil {
+		return models.AccountState{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return state, nil
+}
+
+// SetUserRole назначает пользователю роль. Свою роль администратор не меняет,
+// чтобы на платформе не остаться без администраторов по ошибке
+func (s *AdminService) SetUserRole(ctx context.Context, adminID, userID int, role string) error {
+	const op = "service.AdminService.SetUserRole"
+
+	if err := requireAdmin(s.users, adminID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !slices.Contains(models.UserRoles, role) {
+		return fmt.Errorf("%s: %w", op, ErrInvalidRole)
+	}
+	if userID == adminID {
+		return fmt.Errorf("%s: %w", op, ErrOwnAccount)
+	}
+
+	user, err := s.users.User(userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	updated := user
+	updated.Role = role
+	err = s.repo.SetUserRole(userID, role, userAudit(ctx, adminID, models.AuditUserRoleChanged, user, updated))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("user role changed",
+		slog.Int("admin_id", adminID), slog.Int("user_id", userID), slog.String("role", role),
+	)
+
+	return nil
+}
+
+// SuspendUser блокирует пользователя на days дней, 0 - бессрочно. Администратора
+// заблокировать нельзя: сначала нужно снять с него роль
+func (s *AdminService) SuspendUser(ctx context.Context, adminID, userID, days int, reason string) error {
+	const op = "service.AdminService.SuspendUser"
+
+	if err := requireAdmin(s.users, adminID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	reason = strings.TrimSpace(reason)
+	if days < 0 || days > maxSuspendDays || utf8.RuneCountInString(reason) > maxSuspensionReason {
+		return fmt.Errorf("%s: %w", op, ErrInvalidSuspension)
+	}
+	if userID == adminID {
+		return fmt.Errorf("%s: %w", op, ErrOwnAccount)
+	}
+
+	user, err := s.users.User(userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if user.Role == models.UserRoleAdmin {
+		return fmt.Errorf("%s: %w", op, ErrForbidden)
+	}
+
+	now := time.Now()
+	updated := user
+	updated.SuspendedAt = &now
+	updated.SuspendedUntil = nil
+	updated.SuspensionReason = reason
+	if days > 0 {
+		until := now.AddDate(0, 0, days)
+		updated.SuspendedUntil = &until
+	}
+
+	err = s.repo.SuspendUser(userID, updated.SuspendedUntil, reason,
+		userAudit(ctx, adminID, models.AuditUserSuspended, user, updated))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("user suspended", slog.Int("admin_id", adminID), slog.Int("user_id", userID))
+
+	return nil
+}
+
+func (s *AdminService) UnsuspendUser(ctx context.Context, adminID, userID int) error {
+	const op = "service.AdminService.UnsuspendUser"
+
+	if err := requireAdmin(s.users, adminID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := s.users.User(userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	updated := user
+	updated.SuspendedAt = nil
+	updated.SuspendedUntil = nil
+	updated.SuspensionReason = ""
+	err = s.repo.UnsuspendUser(userID, userAudit(ctx, adminID, models.AuditUserUnsuspended, user, updated))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("user unsuspended", slog.Int("admin_id", adminID), slog.Int("user_id", userID))
+
+	return nil
+}
+
+// ForceLogout отзывает все выданные пользователю токены
+func (s *AdminService) ForceLogout(ctx context.Context, adminID, userID int) error {
+	const op = "service.AdminService.ForceLogout"
+
+	if err := requireAdmin(s.users, adminID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := s.users.User(userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	updated := user
+	updated.TokenVersion++
+	err = s.repo.RevokeSessions(userID, userAudit(ctx, adminID, models.AuditUserSessionRevoked, user, updated))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("user sessions revoked", slog.Int("admin_id", adminID), slog.Int("user_id", userID))
+
+	return nil
+}
+
+// MergeUsers переносит данные дубликата sourceID на аккаунт targetID и удаляет дубликат.
+// Дубликат не может быть аккаунтом самого администратора или аккаунтом с ролью:
+// роль сначала нужно снять, чтобы не потерять её незаметно
+func (s *AdminService) MergeUsers(ctx context.Context, adminID, targetID, sourceID int) error {
+	const op = "service.AdminService.MergeUsers"
+
+	if err := requireAdmin(s.users, adminID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if targetID == sourceID {
+		return fmt.Errorf("%s: %w", op, ErrInvalidMerge)
+	}
+	if sourceID == adminID {
+		return fmt.Errorf("%s: %w", op, ErrOwnAccount)
+	}
+
+	target, err := s.users.User(targetID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	source, err := s.users.User(sourceID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if source.Role != models.UserRoleUser {
+		return fmt.Errorf("%s: %w", op, ErrForbidden)
+	}
+
+	audit := newAuditEvent(ctx, adminID, models.AuditUserMerged, models.AuditTargetUser, targetID,
+		map[string]auditUser{"target": newAuditUser(target), "source": newAuditUser(source)}, nil)
+	if err := s.repo.MergeUsers(targetID, sourceID, audit); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("users merged",
+		slog.Int("admin_id", adminID), slog.Int("target_id", targetID), slog.Int("source_id", sourceID),
+	)
+
+	return nil
+}
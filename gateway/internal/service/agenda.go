@@ -0,0 +1,174 @@
+package service
+
+import (
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+var (
+	ErrInvalidSlotKind  = errors.New("invalid agenda slot kind")
+	ErrInvalidSlotTimes = errors.New("agenda slot is outside of the event time")
+)
+
+type AgendaService struct {
+	repo   AgendaStorageInt
+	events EventStorageInt
+	logger *slog.Logger
+}
+
+func NewAgendaService(repo AgendaStorageInt, events EventStorageInt, logger *slog.Logger) *AgendaService {
+	return &AgendaService{repo: repo, events: events, logger: logger}
+}
+
+func (s *AgendaService) Agenda(eventID int) ([]models.AgendaSlot, error) {
+	const op = "service.AgendaService.Agenda"
+
+	if _, err := s.events.Event(eventID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	slots, err := s.repo.Agenda(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return slots, nil
+}
+
+func (s *AgendaService) AddSlot(userID int, slot models.AgendaSlot, speakerIDs []int) (int, error) {
+	const op = "service.AgendaService.AddSlot"
+
+	event, err := organizedEvent(s.events, userID, slot.EventID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := validateSlot(event, slot); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Слоты докладов из CFP создаются только при принятии доклада
+	slot.TalkID = nil
+
+	id, err := s.repo.CreateSlot(slot, speakerIDs)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// UpdateSlot обновляет слот программы. Если speakerIDs == nil, докладчики слота не меняются
+func (s *AgendaService) UpdateSlot(userID int, slot models.AgendaSlot, speakerIDs []int) error {
+	const op = "service.AgendaService.UpdateSlot"
+
+	event, err := organizedEvent(s.events, userID, slot.EventID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	current, err := s.repo.Slot(slot.ID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Слот доклада из CFP остаётся слотом доклада
+	if current.TalkID != nil {
+		slot.Kind = models.AgendaSlotTalk
+	}
+
+	if err := validateSlot(event, slot); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.repo.UpdateSlot(slot, speakerIDs); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *AgendaService) DeleteSlot(userID, eventID, slotID int) error {
+	const op = "service.AgendaService.DeleteSlot"
+
+	if _, err := organizedEvent(s.events, userID, eventID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.repo.DeleteSlot(eventID, slotID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CreateSpeaker создаёт карточку докладчика. Привязать карточку к аккаунту можно только
+// к своему: карточку другого пользователя создаёт принятие его доклада
+func (s *AgendaService) CreateSpeaker(speaker models.Speaker) (int, error) {
+	const op = "service.AgendaService.CreateSpeaker"
+
+	if speaker.UserID != nil && *speaker.UserID != speaker.CreatedBy {
+		return 0, fmt.Errorf("%s: %w", op, ErrForbidden)
+	}
+
+	id, err := s.repo.CreateSpeaker(speaker)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (s *AgendaService) Speaker(id int) (models.Speaker, error) {
+	const op = "service.AgendaService.Speaker"
+
+	speaker, err := s.repo.Speaker(id)
+	if err != nil {
+		return models.Speaker{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return speaker, nil
+}
+
+// UpdateSpeaker разрешает менять карточку докладчика её автору или самому докладчику
+func (s *AgendaService) UpdateSpeaker(userID int, speaker models.Speaker) error {
+	const op = "service.AgendaService.UpdateSpeaker"
+
+	current, err := s.repo.Speaker(speaker.ID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	isOwner := current.UserID != nil && *current.UserID == userID
+	if !isOwner && current.CreatedBy != userID {
+		return fmt.Errorf("%s: %w", op, ErrForbidden)
+	}
+
+	if err := s.repo.UpdateSpeaker(speaker); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func validateSlot(event models.Event, slot models.AgendaSlot) error {
+	switch slot.Kind {
+	case models.AgendaSlotTalk, models.AgendaSlotBreak, models.AgendaSlotNetworking:
+	default:
+		return ErrInvalidSlotKind
+	}
+
+	if slot.StartsAt != nil && slot.StartsAt.Before(event.StartsAt) {
+		return ErrInvalidSlotTimes
+	}
+	if slot.EndsAt != nil && slot.EndsAt.After(event.EndsAt) {
+		return ErrInvalidSlotTimes
+	}
+	if slot.StartsAt != nil && slot.EndsAt != nil && slot.EndsAt.Before(*slot.StartsAt) {
+		return ErrInvalidSlotTimes
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/storage"
+	"errors"
+	"testing"
+)
+
+// fakeSpeakerStorage хранит карточки докладчиков в памяти
+type fakeSpeakerStorage struct {
+	AgendaStorageInt
+	speakers map[int]models.Speaker
+}
+
+func (s *fakeSpeakerStorage) CreateSpeaker(speaker models.Speaker) (int, error) {
+	speaker.ID = len(s.speakers) + 1
+	s.speakers[speaker.ID] = speaker
+
+	return speaker.ID, nil
+}
+
+func (s *fakeSpeakerStorage) Speaker(id int) (models.Speaker, error) {
+	speaker, ok := s.speakers[id]
+	if !ok {
+		return models.Speaker{}, storage.ErrSpeakerNotFound
+	}
+
+	return speaker, nil
+}
+
+func (s *fakeSpeakerStorage) UpdateSpeaker(speaker models.Speaker) error {
+	current := s.speakers[speaker.ID]
+	current.Name, current.Bio, current.PhotoURL = speaker.Name, speaker.Bio, speaker.PhotoURL
+	s.speakers[speaker.ID] = current
+
+	return nil
+}
+
+func TestCreateSpeaker(t *testing.T) {
+	const author, victim = 1, 2
+	userID := func(id int) *int { return &id }
+
+	tests := []struct {
+		name    string
+		speaker models.Speaker
+		wantErr error
+	}{
+		{name: "guest", speaker: models.Speaker{Name: "Гость", CreatedBy: author}},
+		{name: "own account", speaker: models.Speaker{UserID: userID(author), CreatedBy: author}},
+		{
+			name:    "another user's account",
+			speaker: models.Speaker{UserID: userID(victim), Name: "Подмена", CreatedBy: author},
+			wantErr: ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeSpeakerStorage{speakers: make(map[int]models.Speaker)}
+			s := NewAgendaService(repo, nil, testLogger())
+
+			_, err := s.CreateSpeaker(tt.speaker)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("CreateSpeaker() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && len(repo.speakers) != 0 {
+				t.Errorf("rejected speaker was stored: %+v", repo.speakers)
+			}
+		})
+	}
+}
+
+func TestUpdateSpeaker(t *testing.T) {
+	const author, speaker, stranger = 1, 2, 3
+	speakerID := speaker
+
+	tests := []struct {
+		name    string
+		card    models.Speaker
+		userID  int
+		wantErr error
+	}{
+		{name: "author of a guest card", card: models.Speaker{ID: 1, Name: "Гость", CreatedBy: author}, userID: author},
+		{name: "speaker of an accepted talk", card: models.Speaker{ID: 1, UserID: &speakerID}, userID: speaker},
+		{
+			name:    "stranger",
+			card:    models.Speaker{ID: 1, Name: "Гость", CreatedBy: author},
+			userID:  stranger,
+			wantErr: ErrForbidden,
+		},
+		{
+			name:    "stranger on an accepted talk",
+			card:    models.Speaker{ID: 1, UserID: &speakerID},
+			userID:  stranger,
+			wantErr: ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeSpeakerStorage{speakers: map[int]models.Speaker{tt.card.ID: tt.card}}
+			s := NewAgendaService(repo, nil, testLogger())
+
+			err := s.UpdateSpeaker(tt.userID, models.Speaker{ID: tt.card.ID, Name: "Новое имя"})
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("UpdateSpeaker() error = %v, want %v", err, tt.wantErr)
+			}
+			if changed := repo.speakers[tt.card.ID].Name == "Новое имя"; changed != (tt.wantErr == nil) {
+				t.Errorf("name changed = %v, want %v", changed, tt.wantErr == nil)
+			}
+		})
+	}
+}
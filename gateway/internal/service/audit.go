@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"dev_meets/internal/domain/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// auditCleanupInterval - как часто удаляются записи старше срока хранения
+const auditCleanupInterval = time.Hour
+
+var ErrInvalidAuditFilter = errors.New("invalid audit target type or period")
+
+type AuditConfig struct {
+	// Retention - срок хранения записей журнала, 0 - хранить всегда
+	Retention time.Duration
+}
+
+// AuditService ведёт журнал аудита: кто, когда и откуда изменил аккаунт, роль,
+// мероприятие или вебхук. Записи только добавляются и удаляются по сроку хранения
+type AuditService struct {
+	repo   AuditStorageInt
+	users  UserStorageInt
+	config AuditConfig
+	logger *slog.Logger
+}
+
+func NewAuditService(repo AuditStorageInt, users UserStorageInt, config AuditConfig, logger *slog.Logger) *AuditService {
+	return &AuditService{repo: repo, users: users, config: config, logger: logger}
+}
+
+type requestMetaKey struct{}
+
+// WithRequestMeta сохраняет в контексте адрес и идентификатор запроса для журнала аудита
+func WithRequestMeta(ctx context.Context, meta models.RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+// newAuditEvent готовит запись аудита с данными запроса из ctx. actorID 0 - действие
+// неизвестного пользователя, before и after - состояние объекта, nil - состояния нет
+func newAuditEvent(
+	ctx context.Context,
+	actorID int,
+	action, targetType string,
+	targetID int,
+	before, after any,
+) models.AuditEvent {
+	meta, _ := ctx.Value(requestMetaKey{}).(models.RequestMeta)
+
+	event := models.AuditEvent{
+		Action:      action,
+		TargetType:  targetType,
+		TargetID:    targetID,
+		Before:      auditState(before),
+		After:       auditState(after),
+		RequestMeta: meta,
+	}
+	if actorID != 0 {
+		event.ActorID = &actorID
+	}
+
+	return event
+}
+
+func auditState(state any) json.RawMessage {
+	if state == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil
+	}
+
+	return data
+}
+
+// Audit записывает действие в журнал. Ошибка записи не отменяет уже выполненное
+// действие, поэтому только логируется
+func (s *AuditService) Audit(event models.AuditEvent) {
+	if err := s.repo.CreateAuditEvent(event); err != nil {
+		s.logger.Error("failed to write audit event",
+			slog.String("action", event.Action),
+			slog.String("request_id", event.RequestID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// AuditEvents возвращает журнал аудита, новые записи сначала. Доступно администраторам
+func (s *AuditService) AuditEvents(userID int, filter models.AuditFilter) ([]models.AuditEvent, error) {
+	const op = "service.AuditService.AuditEvents"
+
+	if err := requireAdmin(s.users, userID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if filter.TargetType != "" && !slices.Contains(models.AuditTargets, filter.TargetType) ||
+		filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidAuditFilter)
+	}
+
+	events, err := s.repo.AuditEvents(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
+
+// RunAuditRetention удаляет записи старше срока хранения, пока не отменён ctx
+func (s *AuditService) RunAuditRetention(ctx context.Context) {
+	if s.config.Retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(auditCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.repo.DeleteAuditEventsBefore(time.Now().Add(-s.config.Retention))
+			if err != nil {
+				s.logger.Error("failed to clean up audit events", slog.String("error", err.Error()))
+				continue
+			}
+			s.logger.Debug("audit events cleaned up", slog.Int("deleted", deleted))
+		}
+	}
+}
@@ -1,12 +1,15 @@
 package service
 
 import (
+	"context"
 	"dev_meets/internal/domain/models"
+	"dev_meets/internal/storage"
 	"dev_meets/pkg/jwt"
 	"errors"
 	"fmt"
 	"golang.org/x/crypto/bcrypt"
 	"log/slog"
+	"strings"
 	"time"
 )
 
@@ -16,34 +19,87 @@ const (
 
 var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrUserSuspended      = errors.New("user is suspended")
+	ErrSessionRevoked     = errors.New("session is revoked")
 )
 
 type AuthService struct {
-	repo   UserStorageInt
-	logger *slog.Logger
+	repo        UserStorageInt
+	audit       Auditor
+	invitations SignUpInvitations
+	logger      *slog.Logger
 }
 
-func NewAuthService(repo UserStorageInt, logger *slog.Logger) *AuthService {
-	return &AuthService{repo: repo, logger: logger}
+func NewAuthService(repo UserStorageInt, audit Auditor, invitations SignUpInvitations, logger *slog.Logger) *AuthService {
+	return &AuthService{repo: repo, audit: audit, invitations: invitations, logger: logger}
 }
 
-func (s *AuthService) Login(email, password string) (string, error) {
+// normalizeEmail приводит почту к каноническому виду, в котором она хранится и ищется:
+// без пробелов по краям и в нижнем регистре
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// signInFailure - причина неудачного входа для журнала аудита
+type signInFailure struct {
+	Email  string `json:"email"`
+	Reason string `json:"reason"`
+}
+
+func (s *AuthService) Login(ctx context.Context, email, password string) (string, error) {
 	const op = "service.AuthService.Login"
 
 	s.logger.Info("attempting to login user")
 
-	user, err := s.repo.UserByEmail(email)
+	email = normalizeEmail(email)
+	users, err := s.repo.UsersByEmail(email)
 	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			s.audit.Audit(newAuditEvent(ctx, 0, models.AuditSignInFailed, models.AuditTargetUser, 0,
+				nil, signInFailure{Email: email, Reason: "unknown_email"}))
+		}
+
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PassHash), []byte(password)); err != nil {
+	// у дубликатов, заведённых до нормализации почты, пароли разные: вход выполняется
+	// в тот аккаунт, к которому подошёл пароль
+	var user models.User
+	matched := false
+	for _, candidate := range users {
+		if bcrypt.CompareHashAndPassword([]byte(candidate.PassHash), []byte(password)) == nil {
+			user, matched = candidate, true
+			break
+		}
+	}
+	if !matched {
 		s.logger.Info("invalid credentials")
+		s.audit.Audit(newAuditEvent(ctx, 0, models.AuditSignInFailed, models.AuditTargetUser, users[0].ID,
+			nil, signInFailure{Email: email, Reason: "invalid_password"}))
 
 		return "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
 	}
 
+	if user.Suspended(time.Now()) {
+		s.logger.Info("suspended user tried to login", slog.Int("user_id", user.ID))
+		s.audit.Audit(newAuditEvent(ctx, user.ID, models.AuditSignInFailed, models.AuditTargetUser, user.ID,
+			nil, signInFailure{Email: email, Reason: "suspended"}))
+
+		return "", fmt.Errorf("%s: %w", op, ErrUserSuspended)
+	}
+
+	if user.DeletionScheduledAt != nil {
+		if err := s.repo.CancelDeletion(user.ID); err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Info("account deletion cancelled", slog.Int("user_id", user.ID))
+		s.audit.Audit(newAuditEvent(ctx, user.ID, models.AuditAccountDeletionCancelled, models.AuditTargetUser, user.ID,
+			map[string]time.Time{"deletion_scheduled_at": *user.DeletionScheduledAt}, nil))
+	}
+
 	s.logger.Info("user logged in successfully")
+	s.audit.Audit(newAuditEvent(ctx, user.ID, models.AuditSignIn, models.AuditTargetUser, user.ID, nil, nil))
 
 	token, err := jwt.NewToken(user, tokenTTL)
 	if err != nil {
@@ -55,7 +111,30 @@ func (s *AuthService) Login(email, password string) (string, error) {
 	return token, nil
 }
 
-func (s *AuthService) RegisterNewUser(user models.User, pass string) (int, error) {
+// CheckUserActive проверяет, что пользователь существует, не заблокирован и токен
+// не отозван принудительным выходом. Вызывается на каждый авторизованный запрос,
+// чтобы блокировка и выход действовали сразу
+func (s *AuthService) CheckUserActive(userID, tokenVersion int) error {
+	const op = "service.AuthService.CheckUserActive"
+
+	user, err := s.repo.User(userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.Suspended(time.Now()) {
+		return fmt.Errorf("%s: %w", op, ErrUserSuspended)
+	}
+	if tokenVersion != user.TokenVersion {
+		return fmt.Errorf("%s: %w", op, ErrSessionRevoked)
+	}
+
+	return nil
+}
+
+// RegisterNewUser регистрирует пользователя и применяет его приглашения: отправленные
+// на его почту и то, по которому он пришёл (inviteToken, может быть пустым)
+func (s *AuthService) RegisterNewUser(ctx context.Context, user models.User, pass, inviteToken string) (int, error) {
 	const op = "service.AuthService.RegisterNewUser"
 
 	passHash, err := bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
@@ -65,6 +144,54 @@ func (s *AuthService) RegisterNewUser(user models.User, pass string) (int, error
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 	user.PassHash = string(passHash)
+	user.Email = normalizeEmail(user.Email)
+
+	id, err := s.repo.CreateUser(user)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.audit.Audit(newAuditEvent(ctx, id, models.AuditSignUp, models.AuditTargetUser, id,
+		nil, map[string]string{"email": user.Email}))
 
-	return s.repo.CreateUser(user)
+	s.invitations.ApplySignUpInvitation(id, inviteToken)
+
+	return id, nil
+}
+
+// ChangePassword меняет пароль пользователя после проверки текущего. Все выданные
+// ранее токены отзываются, взамен возвращается новый
+func (s *AuthService) ChangePassword(ctx context.Context, userID int, current, password string) (string, error) {
+	const op = "service.AuthService.ChangePassword"
+
+	user, err := s.repo.User(userID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PassHash), []byte(current)); err != nil {
+		return "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+	}
+
+	passHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	previous := user.TokenVersion
+	user.TokenVersion, err = s.repo.UpdatePassword(userID, string(passHash))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("password changed", slog.Int("user_id", userID))
+	s.audit.Audit(newAuditEvent(ctx, userID, models.AuditPasswordChanged, models.AuditTargetUser, userID,
+		map[string]int{"token_version": previous}, map[string]int{"token_version": user.TokenVersion}))
+
+	token, err := jwt.NewToken(user, tokenTTL)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, nil
 }
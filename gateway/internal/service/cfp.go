@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+var (
+	ErrForbidden       = errors.New("forbidden")
+	ErrCFPClosed       = errors.New("call for papers is closed")
+	ErrInvalidDeadline = errors.New("invalid call for papers deadline")
+	ErrOwnTalkReview   = errors.New("speaker cannot review own talk")
+)
+
+type CFPService struct {
+	repo       CFPStorageInt
+	events     EventStorageInt
+	notifier   Notifier
+	activities ActivityRecorder
+	audit      Auditor
+	logger     *slog.Logger
+}
+
+func NewCFPService(
+	repo CFPStorageInt,
+	events EventStorageInt,
+	notifier Notifier,
+	activities ActivityRecorder,
+	audit Auditor,
+	logger *slog.Logger,
+) *CFPService {
+	return &CFPService{
+		repo:       repo,
+		events:     events,
+		notifier:   notifier,
+		activities: activities,
+		audit:      audit,
+		logger:     logger,
+	}
+}
+
+func (s *CFPService) OpenCFP(ctx context.Context, userID int, cfp models.CallForPapers) error {
+	const op = "service.CFPService.OpenCFP"
+
+	event, err := organizedEvent(s.events, userID, cfp.EventID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !cfp.IsOpen(time.Now()) || cfp.Deadline.After(event.StartsAt) {
+		return fmt.Errorf("%s: %w", op, ErrInvalidDeadline)
+	}
+
+	if err := s.repo.CreateCFP(cfp); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("call for papers opened", slog.Int("event_id", cfp.EventID))
+	s.audit.Audit(newAuditEvent(ctx, userID, models.AuditCFPOpened, models.AuditTargetEvent, cfp.EventID,
+		nil, map[string]any{"description": cfp.Description, "deadline": cfp.Deadline}))
+
+	return nil
+}
+
+func (s *CFPService) CFP(eventID int) (models.CallForPapers, error) {
+	const op = "service.CFPService.CFP"
+
+	cfp, err := s.repo.CFP(eventID)
+	if err != nil {
+		return models.CallForPapers{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return cfp, nil
+}
+
+func (s *CFPService) AddReviewer(ctx context.Context, userID, eventID, reviewerID int) error {
+	const op = "service.CFPService.AddReviewer"
+
+	if _, err := organizedEvent(s.events, userID, eventID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := s.repo.CFP(eventID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.repo.AddReviewer(eventID, reviewerID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.audit.Audit(newAuditEvent(ctx, userID, models.AuditReviewerAdded, models.AuditTargetEvent, eventID,
+		nil, map[string]int{"reviewer_id": reviewerID}))
+
+	return nil
+}
+
+func (s *CFPService) SubmitTalk(talk models.Talk) (int, error) {
+	const op = "service.CFPService.SubmitTalk"
+
+	cfp, err := s.repo.CFP(talk.EventID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !cfp.IsOpen(time.Now()) {
+		return 0, fmt.Errorf("%s: %w", op, ErrCFPClosed)
+	}
+
+	id, err := s.repo.CreateTalk(talk)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("talk submitted", slog.Int("talk_id", id), slog.Int("event_id", talk.EventID))
+
+	return id, nil
+}
+
+func (s *CFPService) Talks(userID, eventID int) ([]models.TalkSummary, error) {
+	const op = "service.CFPService.Talks"
+
+	if err := s.checkReviewer(userID, eventID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	talks, err := s.repo.TalksByEvent(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return talks, nil
+}
+
+func (s *CFPService) ReviewTalk(review models.TalkReview) (int, error) {
+	const op = "service.CFPService.ReviewTalk"
+
+	talk, err := s.repo.Talk(review.TalkID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if talk.SpeakerID == review.ReviewerID {
+		return 0, fmt.Errorf("%s: %w", op, ErrOwnTalkReview)
+	}
+
+	if err := s.checkReviewer(review.ReviewerID, talk.EventID); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := s.repo.CreateReview(review)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (s *CFPService) Reviews(userID, talkID int) ([]models.TalkReview, error) {
+	const op = "service.CFPService.Reviews"
+
+	talk, err := s.repo.Talk(talkID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.checkReviewer(userID, talk.EventID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	reviews, err := s.repo.ReviewsByTalk(talkID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return reviews, nil
+}
+
+func (s *CFPService) AcceptTalk(ctx context.Context, userID, talkID int) error {
+	const op = "service.CFPService.AcceptTalk"
+
+	talk, err := s.repo.Talk(talkID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	event, err := organizedEvent(s.events, userID, talk.EventID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.repo.AcceptTalk(talkID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.audit.Audit(newAuditEvent(ctx, userID, models.AuditTalkAccepted, models.AuditTargetTalk, talkID,
+		map[string]string{"status": talk.Status}, map[string]string{"status": models.TalkStatusAccepted}))
+
+	s.notify(models.Notification{
+		UserID: talk.SpeakerID,
+		Type:   models.NotificationTalkAccepted,
+		Title:  fmt.Sprintf("Доклад «%s» принят", talk.Title),
+		Body:   fmt.Sprintf("Ваш доклад добавлен в программу мероприятия «%s»", event.Title),
+	})
+
+	s.activities.RecordActivity(models.Activity{
+		Kind:    models.ActivityTalkAccepted,
+		ActorID: talk.SpeakerID,
+		GroupID: event.GroupID,
+		EventID: &talk.EventID,
+		TalkID:  &talk.ID,
+	})
+
+	return nil
+}
+
+func (s *CFPService) RejectTalk(ctx context.Context, userID, talkID int) error {
+	const op = "service.CFPService.RejectTalk"
+
+	talk, err := s.repo.Talk(talkID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	event, err := organizedEvent(s.events, userID, talk.EventID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.repo.RejectTalk(talkID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.audit.Audit(newAuditEvent(ctx, userID, models.AuditTalkRejected, models.AuditTargetTalk, talkID,
+		map[string]string{"status": talk.Status}, map[string]string{"status": models.TalkStatusRejected}))
+
+	s.notify(models.Notification{
+		UserID: talk.SpeakerID,
+		Type:   models.NotificationTalkRejected,
+		Title:  fmt.Sprintf("Доклад «%s» отклонён", talk.Title),
+		Body:   fmt.Sprintf("К сожалению, доклад не вошёл в программу мероприятия «%s»", event.Title),
+	})
+
+	return nil
+}
+
+// checkReviewer проверяет, что пользователь может рецензировать доклады мероприятия:
+// это организатор или назначенный им рецензент
+func (s *CFPService) checkReviewer(userID, eventID int) error {
+	event, err := s.events.Event(eventID)
+	if err != nil {
+		return err
+	}
+
+	if event.OrganizerID == userID {
+		return nil
+	}
+
+	ok, err := s.repo.IsReviewer(eventID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+// notify не прерывает основной сценарий при ошибке доставки уведомления
+func (s *CFPService) notify(n models.Notification) {
+	if err := s.notifier.Notify(n); err != nil {
+		s.logger.Error("failed to send notification", slog.String("error", err.Error()))
+	}
+}
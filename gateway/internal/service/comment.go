@@ -0,0 +1,337 @@
+package service
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/storage"
+	"dev_meets/pkg/markdown"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+const (
+	maxCommentLength     = 10000
+	commentPreviewLength = 140
+)
+
+var (
+	ErrInvalidComment        = errors.New("comment must not be empty or longer than 10000 characters")
+	ErrInvalidCommentSubject = errors.New("comments are available for events and groups only")
+)
+
+type CommentService struct {
+	repo      CommentStorageInt
+	users     UserStorageInt
+	events    EventStorageInt
+	groups    GroupStorageInt
+	notifier  Notifier
+	publisher Publisher
+	logger    *slog.Logger
+}
+
+func NewCommentService(
+	repo CommentStorageInt,
+	users UserStorageInt,
+	events EventStorageInt,
+	groups GroupStorageInt,
+	notifier Notifier,
+	publisher Publisher,
+	logger *slog.Logger,
+) *CommentService {
+	return &CommentService{
+		repo:      repo,
+		users:     users,
+		events:    events,
+		groups:    groups,
+		notifier:  notifier,
+		publisher: publisher,
+		logger:    logger,
+	}
+}
+
+// commentSubject - мероприятие или сообщество, к которому относится обсуждение
+type commentSubject struct {
+	Title string
+	// ModeratorID - организатор мероприятия или владелец сообщества, может удалять чужие комментарии
+	ModeratorID int
+}
+
+// CreateComment добавляет комментарий или ответ и уведомляет упомянутых в нём пользователей
+func (s *CommentService) CreateComment(comment models.Comment) (int, error) {
+	const op = "service.CommentService.CreateComment"
+
+	subject, err := s.subject(comment.SubjectType, comment.SubjectID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	comment.RootID = nil
+	if comment.ParentID != nil {
+		parent, err := s.repo.Comment(*comment.ParentID)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+		if parent.SubjectType != comment.SubjectType || parent.SubjectID != comment.SubjectID {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrCommentNotFound)
+		}
+
+		comment.RootID = parent.RootID
+		if comment.RootID == nil {
+			comment.RootID = &parent.ID
+		}
+	}
+
+	if comment.Body, comment.BodyHTML, err = renderComment(comment.Body); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := s.repo.CreateComment(comment)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	comment.ID = id
+	s.publishComment(comment, "created")
+	s.notifyMentions(comment.AuthorID, subject, markdown.Mentions(comment.Body))
+	if comment.SubjectType == models.CommentSubjectGroup && comment.RootID == nil {
+		s.notifyGroupMembers(comment, subject)
+	}
+
+	return id, nil
+}
+
+// Comments возвращает страницу тредов обсуждения
+func (s *CommentService) Comments(filter models.CommentFilter) ([]models.Comment, error) {
+	const op = "service.CommentService.Comments"
+
+	if _, err := s.subject(filter.SubjectType, filter.SubjectID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	threads, err := s.repo.Threads(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return threads, nil
+}
+
+// UpdateComment меняет текст комментария. Править может только автор,
+// уведомления получают только впервые упомянутые пользователи
+func (s *CommentService) UpdateComment(userID, id int, body string) error {
+	const op = "service.CommentService.UpdateComment"
+
+	comment, err := s.repo.Comment(id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if comment.AuthorID != userID {
+		return fmt.Errorf("%s: %w", op, ErrForbidden)
+	}
+
+	body, bodyHTML, err := renderComment(body)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.repo.UpdateComment(id, body, bodyHTML); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.publishComment(comment, "updated")
+
+	mentioned := make(map[string]struct{})
+	for _, username := range markdown.Mentions(comment.Body) {
+		mentioned[username] = struct{}{}
+	}
+
+	added := make([]string, 0)
+	for _, username := range markdown.Mentions(body) {
+		if _, ok := mentioned[username]; !ok {
+			added = append(added, username)
+		}
+	}
+
+	if len(added) > 0 {
+		subject, err := s.subject(comment.SubjectType, comment.SubjectID)
+		if err != nil {
+			s.logger.Error("failed to load comment subject", slog.String("error", err.Error()))
+			return nil
+		}
+
+		s.notifyMentions(userID, subject, added)
+	}
+
+	return nil
+}
+
+// DeleteComment удаляет комментарий. Удалять может автор, а также организатор
+// мероприятия или владелец сообщества
+func (s *CommentService) DeleteComment(userID, id int) error {
+	const op = "service.CommentService.DeleteComment"
+
+	comment, err := s.repo.Comment(id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.checkModerator(userID, comment); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.repo.DeleteComment(id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.publishComment(comment, "deleted")
+
+	return nil
+}
+
+// CommentHistory возвращает прежние версии комментария. История доступна автору
+// и модератору обсуждения, так как хранит в том числе текст удалённых комментариев
+func (s *CommentService) CommentHistory(userID, id int) ([]models.CommentRevision, error) {
+	const op = "service.CommentService.CommentHistory"
+
+	comment, err := s.repo.Comment(id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.checkModerator(userID, comment); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	revisions, err := s.repo.CommentRevisions(id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return revisions, nil
+}
+
+func (s *CommentService) subject(subjectType string, subjectID int) (commentSubject, error) {
+	switch subjectType {
+	case models.CommentSubjectEvent:
+		event, err := s.events.Event(subjectID)
+		if err != nil {
+			return commentSubject{}, err
+		}
+
+		return commentSubject{Title: event.Title, ModeratorID: event.OrganizerID}, nil
+	case models.CommentSubjectGroup:
+		group, err := s.groups.Group(subjectID)
+		if err != nil {
+			return commentSubject{}, err
+		}
+
+		return commentSubject{Title: group.Name, ModeratorID: group.OwnerID}, nil
+	default:
+		return commentSubject{}, ErrInvalidCommentSubject
+	}
+}
+
+// checkModerator проверяет, что пользователь - автор комментария или модератор обсуждения
+func (s *CommentService) checkModerator(userID int, comment models.Comment) error {
+	if comment.AuthorID == userID {
+		return nil
+	}
+
+	subject, err := s.subject(comment.SubjectType, comment.SubjectID)
+	if err != nil {
+		return err
+	}
+
+	if subject.ModeratorID != userID {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+// publishComment сообщает подписчикам обсуждения, что комментарий изменился.
+// Текст клиенты перечитывают сами, чтобы в журнал событий не попадало удалённое
+func (s *CommentService) publishComment(comment models.Comment, action string) {
+	s.publisher.PublishToTopic(models.StreamTopic(comment.SubjectType, comment.SubjectID), models.StreamEventComment, map[string]any{
+		"id":      comment.ID,
+		"root_id": comment.RootID,
+		"action":  action,
+	})
+}
+
+// notifyMentions уведомляет упомянутых пользователей. Ошибки доставки не прерывают
+// публикацию комментария
+func (s *CommentService) notifyMentions(authorID int, subject commentSubject, usernames []string) {
+	if len(usernames) == 0 {
+		return
+	}
+
+	users, err := s.users.UsersByUsernames(usernames)
+	if err != nil {
+		s.logger.Error("failed to resolve mentions", slog.String("error", err.Error()))
+		return
+	}
+
+	for _, user := range users {
+		if user.ID == authorID {
+			continue
+		}
+
+		err := s.notifier.Notify(models.Notification{
+			UserID: user.ID,
+			Type:   models.NotificationMention,
+			Title:  "Вас упомянули в обсуждении",
+			Body:   fmt.Sprintf("Вас упомянули в обсуждении «%s»", subject.Title),
+		})
+		if err != nil {
+			s.logger.Error("failed to send notification", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// notifyGroupMembers сообщает участникам сообщества о новом треде. Уведомления
+// склеиваются по сообществу, поэтому активное обсуждение не засыпает участников
+func (s *CommentService) notifyGroupMembers(comment models.Comment, subject commentSubject) {
+	members, err := s.groups.MemberIDs(comment.SubjectID)
+	if err != nil {
+		s.logger.Error("failed to load group members", slog.String("error", err.Error()))
+		return
+	}
+
+	preview := []rune(comment.Body)
+	if len(preview) > commentPreviewLength {
+		preview = append(preview[:commentPreviewLength], '…')
+	}
+
+	for _, memberID := range members {
+		if memberID == comment.AuthorID {
+			continue
+		}
+
+		err := s.notifier.Notify(models.Notification{
+			UserID:   memberID,
+			Type:     models.NotificationGroupDiscussion,
+			Title:    fmt.Sprintf("Новые обсуждения в сообществе «%s»", subject.Title),
+			Body:     string(preview),
+			GroupKey: fmt.Sprintf("group:%d:discussion", comment.SubjectID),
+		})
+		if err != nil {
+			s.logger.Error("failed to send notification", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// renderComment проверяет текст комментария и возвращает его вместе с безопасным HTML
+func renderComment(body string) (string, string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || len([]rune(body)) > maxCommentLength {
+		return "", "", ErrInvalidComment
+	}
+
+	html, err := markdown.Render(body)
+	if err != nil {
+		return "", "", err
+	}
+
+	return body, html, nil
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+var (
+	ErrInvalidEventDates = errors.New("event ends before it starts")
+)
+
+type EventService struct {
+	repo       EventStorageInt
+	agenda     AgendaStorageInt
+	groups     GroupStorageInt
+	webhooks   WebhookDispatcher
+	announcer  EventAnnouncer
+	activities ActivityRecorder
+	audit      Auditor
+	logger     *slog.Logger
+}
+
+func NewEventService(
+	repo EventStorageInt,
+	agenda AgendaStorageInt,
+	groups GroupStorageInt,
+	webhooks WebhookDispatcher,
+	announcer EventAnnouncer,
+	activities ActivityRecorder,
+	audit Auditor,
+	logger *slog.Logger,
+) *EventService {
+	return &EventService{
+		repo:       repo,
+		agenda:     agenda,
+		groups:     groups,
+		webhooks:   webhooks,
+		announcer:  announcer,
+		activities: activities,
+		audit:      audit,
+		logger:     logger,
+	}
+}
+
+// auditedEvent - состояние мероприятия для журнала аудита
+type auditedEvent struct {
+	Title       string     `json:"title"`
+	GroupID     *int       `json:"group_id,omitempty"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+}
+
+func newAuditedEvent(event models.Event) auditedEvent {
+	return auditedEvent{
+		Title:       event.Title,
+		GroupID:     event.GroupID,
+		StartsAt:    event.StartsAt,
+		EndsAt:      event.EndsAt,
+		CancelledAt: event.CancelledAt,
+	}
+}
+
+func (s *EventService) CreateEvent(ctx context.Context, event models.Event) (int, error) {
+	const op = "service.EventService.CreateEvent"
+
+	if event.EndsAt.Before(event.StartsAt) {
+		return 0, fmt.Errorf("%s: %w", op, ErrInvalidEventDates)
+	}
+
+	// Мероприятие от имени сообщества может создать только его владелец
+	if event.GroupID != nil {
+		if _, err := ownedGroup(s.groups, event.OrganizerID, *event.GroupID); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	id, err := s.repo.CreateEvent(event)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("event created", slog.Int("event_id", id), slog.Int("organizer_id", event.OrganizerID))
+	s.audit.Audit(newAuditEvent(ctx, event.OrganizerID, models.AuditEventCreated, models.AuditTargetEvent, id,
+		nil, newAuditedEvent(event)))
+
+	s.activities.RecordActivity(models.Activity{
+		Kind:    models.ActivityEventCreated,
+		ActorID: event.OrganizerID,
+		GroupID: event.GroupID,
+		EventID: &id,
+	})
+
+	if event.GroupID != nil {
+		event.ID = id
+		s.webhooks.DispatchWebhook(*event.GroupID, models.WebhookEventPublished, webhookEventData{Event: newWebhookEvent(event)})
+		s.announcer.AnnounceEvent(event)
+	}
+
+	return id, nil
+}
+
+// CancelEvent отменяет мероприятие. Отменить может только организатор
+func (s *EventService) CancelEvent(ctx context.Context, userID, eventID int) error {
+	const op = "service.EventService.CancelEvent"
+
+	event, err := organizedEvent(s.repo, userID, eventID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.repo.CancelEvent(eventID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("event cancelled", slog.Int("event_id", eventID), slog.Int("organizer_id", userID))
+
+	before := newAuditedEvent(event)
+	now := time.Now()
+	event.CancelledAt = &now
+	s.audit.Audit(newAuditEvent(ctx, userID, models.AuditEventCancelled, models.AuditTargetEvent, eventID,
+		before, newAuditedEvent(event)))
+
+	if event.GroupID != nil {
+		s.webhooks.DispatchWebhook(*event.GroupID, models.WebhookEventCancelled, webhookEventData{Event: newWebhookEvent(event)})
+	}
+
+	return nil
+}
+
+func (s *EventService) Event(id int) (models.Event, error) {
+	const op = "service.EventService.Event"
+
+	event, err := s.repo.Event(id)
+	if err != nil {
+		return models.Event{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	event.Agenda, err = s.agenda.Agenda(id)
+	if err != nil {
+		return models.Event{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return event, nil
+}
+
+func (s *EventService) Events(filter models.EventFilter) ([]models.Event, error) {
+	const op = "service.EventService.Events"
+
+	if err := validateGeoFilter(filter.Near); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	events, err := s.repo.Events(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
+
+// organizedEvent возвращает мероприятие, если userID является его организатором
+func organizedEvent(events EventStorageInt, userID, eventID int) (models.Event, error) {
+	event, err := events.Event(eventID)
+	if err != nil {
+		return models.Event{}, err
+	}
+
+	if event.OrganizerID != userID {
+		return models.Event{}, ErrForbidden
+	}
+
+	return event, nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/storage"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// Общие заглушки хранилищ и сервисов для тестов пакета. Заглушки встраивают интерфейс
+// и реализуют только методы, которые нужны тестам; вызов остальных - ошибка теста
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+type fakeEventStorage struct {
+	EventStorageInt
+	events map[int]models.Event
+}
+
+func newFakeEventStorage(events ...models.Event) *fakeEventStorage {
+	s := &fakeEventStorage{events: make(map[int]models.Event)}
+	for _, event := range events {
+		s.events[event.ID] = event
+	}
+
+	return s
+}
+
+func (s *fakeEventStorage) Event(id int) (models.Event, error) {
+	event, ok := s.events[id]
+	if !ok {
+		return models.Event{}, storage.ErrEventNotFound
+	}
+
+	return event, nil
+}
+
+type fakeGroupStorage struct {
+	GroupStorageInt
+	groups map[int]models.Group
+}
+
+func (s *fakeGroupStorage) Group(id int) (models.Group, error) {
+	group, ok := s.groups[id]
+	if !ok {
+		return models.Group{}, storage.ErrGroupNotFound
+	}
+
+	return group, nil
+}
+
+// fakeInvitations - принятые приглашения в виде "subjectType:subjectID:userID"
+type fakeInvitations map[string]bool
+
+func (f fakeInvitations) HasInvitation(subjectType string, subjectID, userID int) (bool, error) {
+	return f[fmt.Sprintf("%s:%d:%d", subjectType, subjectID, userID)], nil
+}
+
+// fakeRSVPCreator записывает на мероприятия из events
+type fakeRSVPCreator struct {
+	mu     sync.Mutex
+	events map[int]error
+	rsvps  map[int][]int
+}
+
+func (c *fakeRSVPCreator) RSVP(userID, eventID int) (models.Ticket, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err, ok := c.events[eventID]
+	if !ok {
+		return models.Ticket{}, storage.ErrEventNotFound
+	}
+	if err != nil {
+		return models.Ticket{}, err
+	}
+	c.rsvps[eventID] = append(c.rsvps[eventID], userID)
+
+	return models.Ticket{}, nil
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"dev_meets/internal/domain/models"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// feedPool - сколько ближайших мероприятий ранжируется для ленты
+	feedPool     = 300
+	feedCacheTTL = 5 * time.Minute
+	// feedCacheSweep - при таком числе записей кэш вычищается от устаревших
+	feedCacheSweep = 10000
+)
+
+// FeedService строит персональную ленту предстоящих мероприятий. Ранжирование
+// выполняет ScoreFeedEvent, готовая лента кэшируется в памяти реплики на feedCacheTTL
+type FeedService struct {
+	repo   FeedStorageInt
+	logger *slog.Logger
+
+	mu    sync.Mutex
+	cache map[feedCacheKey]feedCacheEntry
+}
+
+type feedCacheKey struct {
+	userID int
+	near   models.GeoPoint
+	// hasNear отличает ленту без координат от ленты для точки (0, 0)
+	hasNear bool
+}
+
+type feedCacheEntry struct {
+	items   []models.FeedItem
+	expires time.Time
+}
+
+func NewFeedService(repo FeedStorageInt, logger *slog.Logger) *FeedService {
+	return &FeedService{repo: repo, logger: logger, cache: make(map[feedCacheKey]feedCacheEntry)}
+}
+
+// Feed возвращает страницу ленты. near, если задан, учитывает расстояние до площадки
+func (s *FeedService) Feed(userID int, near *models.GeoPoint, limit, offset int) ([]models.FeedItem, error) {
+	const op = "service.FeedService.Feed"
+
+	if near != nil && (near.Lat < -90 || near.Lat > 90 || near.Lon < -180 || near.Lon > 180) {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidGeoFilter)
+	}
+
+	key := feedCacheKey{userID: userID}
+	if near != nil {
+		key.near, key.hasNear = *near, true
+	}
+
+	items, ok := s.cached(key)
+	if !ok {
+		var err error
+		items, err = s.rank(userID, near)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		s.store(key, items)
+	}
+
+	if offset >= len(items) {
+		return []models.FeedItem{}, nil
+	}
+
+	return items[offset:min(offset+limit, len(items))], nil
+}
+
+// DismissEvent скрывает мероприятие из ленты и понижает похожие
+func (s *FeedService) DismissEvent(userID, eventID int) error {
+	const op = "service.FeedService.DismissEvent"
+
+	if err := s.repo.DismissEvent(userID, eventID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.invalidate(userID)
+
+	return nil
+}
+
+func (s *FeedService) UndismissEvent(userID, eventID int) error {
+	const op = "service.FeedService.UndismissEvent"
+
+	if err := s.repo.UndismissEvent(userID, eventID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.invalidate(userID)
+
+	return nil
+}
+
+func (s *FeedService) rank(userID int, near *models.GeoPoint) ([]models.FeedItem, error) {
+	profile, err := s.repo.FeedProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.repo.FeedCandidates(userID, near, feedPool)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	items := make([]models.FeedItem, 0, len(candidates))
+	for _, c := range candidates {
+		score, reasons := ScoreFeedEvent(profile, c, now)
+		items = append(items, models.FeedItem{Event: c.Event, Score: score, Reasons: reasons})
+	}
+
+	// При равной оценке выше то, что раньше начнётся, так порядок всегда один и тот же
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Score != items[j].Score {
+			return items[i].Score > items[j].Score
+		}
+		if !items[i].Event.StartsAt.Equal(items[j].Event.StartsAt) {
+			return items[i].Event.StartsAt.Before(items[j].Event.StartsAt)
+		}
+
+		return items[i].Event.ID < items[j].Event.ID
+	})
+
+	return items, nil
+}
+
+func (s *FeedService) cached(key feedCacheKey) ([]models.FeedItem, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.cache[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+
+	return entry.items, true
+}
+
+func (s *FeedService) store(key feedCacheKey, items []models.FeedItem) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if len(s.cache) >= feedCacheSweep {
+		for k, entry := range s.cache {
+			if now.After(entry.expires) {
+				delete(s.cache, k)
+			}
+		}
+	}
+
+	s.cache[key] = feedCacheEntry{items: items, expires: now.Add(feedCacheTTL)}
+}
+
+// invalidate сбрасывает все закэшированные ленты пользователя
+func (s *FeedService) invalidate(userID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k := range s.cache {
+		if k.userID == userID {
+			delete(s.cache, k)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"dev_meets/internal/domain/models"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Веса сигналов ленты
+const (
+	feedSkillWeight          = 2.0
+	feedMaxSkills            = 3
+	feedGroupWeight          = 3.0
+	feedAttendedGroupWeight  = 1.0
+	feedAttendedOrgWeight    = 0.5
+	feedMaxAttended          = 3
+	feedFriendWeight         = 1.5
+	feedMaxFriends           = 4
+	feedDismissedGroupWeight = 2.0
+	feedDismissedOrgWeight   = 1.0
+	feedMaxDismissed         = 3
+	feedCityWeight           = 1.5
+	feedPopularityWeight     = 0.5
+	// feedSoonWeight достаётся мероприятию, которое вот-вот начнётся, и убывает до нуля к feedHorizon
+	feedSoonWeight = 1.5
+	feedHorizon    = 60 * 24 * time.Hour
+)
+
+// ScoreFeedEvent оценивает, насколько мероприятие интересно пользователю, и объясняет
+// оценку. Функция чистая: результат зависит только от аргументов, в том числе от now
+func ScoreFeedEvent(profile models.FeedProfile, c models.FeedCandidate, now time.Time) (float64, []string) {
+	var score float64
+	var reasons []string
+
+	if skills := mentionedSkills(profile.Skills, c.Title+" "+c.Description); len(skills) > 0 {
+		score += feedSkillWeight * float64(min(len(skills), feedMaxSkills))
+		reasons = append(reasons, "Про ваши навыки: "+strings.Join(skills, ", "))
+	}
+
+	if c.InUserGroup {
+		score += feedGroupWeight
+		reasons = append(reasons, "Мероприятие вашего сообщества")
+	}
+
+	if c.AttendedInGroup > 0 {
+		score += feedAttendedGroupWeight * float64(min(c.AttendedInGroup, feedMaxAttended))
+		reasons = append(reasons, fmt.Sprintf("Вы были на мероприятиях этого сообщества: %d", c.AttendedInGroup))
+	}
+	if c.AttendedOrganizer > 0 {
+		score += feedAttendedOrgWeight * float64(min(c.AttendedOrganizer, feedMaxAttended))
+	}
+
+	if c.FriendsGoing > 0 {
+		score += feedFriendWeight * float64(min(c.FriendsGoing, feedMaxFriends))
+		reasons = append(reasons, fmt.Sprintf("Идут ваши знакомые: %d", c.FriendsGoing))
+	}
+
+	switch {
+	case c.DistanceKm != nil && *c.DistanceKm <= 5:
+		score += 2
+		reasons = append(reasons, fmt.Sprintf("Рядом с вами: %.1f км", *c.DistanceKm))
+	case c.DistanceKm != nil && *c.DistanceKm <= 20:
+		score += 1
+		reasons = append(reasons, fmt.Sprintf("Недалеко: %.0f км", *c.DistanceKm))
+	case c.DistanceKm != nil && *c.DistanceKm > 100:
+		score -= 1
+	case c.DistanceKm == nil && profile.City != "" && strings.EqualFold(profile.City, c.City):
+		score += feedCityWeight
+		reasons = append(reasons, "В вашем городе")
+	}
+
+	// «Не интересно» у похожих мероприятий понижает оценку, но не исключает их
+	score -= feedDismissedGroupWeight * float64(min(c.DismissedInGroup, feedMaxDismissed))
+	score -= feedDismissedOrgWeight * float64(min(c.DismissedOrganizer, feedMaxDismissed))
+
+	score += feedPopularityWeight * math.Log1p(float64(c.Going))
+
+	if until := c.StartsAt.Sub(now); until > 0 && until < feedHorizon {
+		score += feedSoonWeight * (1 - float64(until)/float64(feedHorizon))
+	}
+
+	return score, reasons
+}
+
+// mentionedSkills возвращает навыки, которые встречаются в тексте отдельным словом
+func mentionedSkills(skills []string, text string) []string {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#'
+	}) {
+		words[word] = true
+	}
+
+	var mentioned []string
+	for _, skill := range skills {
+		if words[strings.ToLower(skill)] {
+			mentioned = append(mentioned, skill)
+		}
+	}
+
+	return mentioned
+}
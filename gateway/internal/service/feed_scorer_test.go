@@ -0,0 +1,156 @@
+package service
+
+import (
+	"dev_meets/internal/domain/models"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScoreFeedEvent(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	// мероприятие за горизонтом ленты не получает прибавки за близость
+	later := now.Add(feedHorizon + time.Hour)
+	km := func(distance float64) *float64 { return &distance }
+	candidate := func(update func(c *models.FeedCandidate)) models.FeedCandidate {
+		c := models.FeedCandidate{Event: models.Event{Title: "Встреча", City: "Казань", StartsAt: later}}
+		if update != nil {
+			update(&c)
+		}
+
+		return c
+	}
+
+	tests := []struct {
+		name        string
+		profile     models.FeedProfile
+		candidate   models.FeedCandidate
+		wantScore   float64
+		wantReasons []string
+	}{
+		{
+			name:      "no signals",
+			candidate: candidate(nil),
+		},
+		{
+			name:    "mentioned skills",
+			profile: models.FeedProfile{Skills: []string{"Go", "Rust", "C++", "Python"}},
+			candidate: candidate(func(c *models.FeedCandidate) {
+				c.Title = "Go, rust и c++ в проде"
+				c.Description = "Golang не считается"
+			}),
+			wantScore:   3 * feedSkillWeight,
+			wantReasons: []string{"Про ваши навыки: Go, Rust, C++"},
+		},
+		{
+			name:    "mentioned skills are capped",
+			profile: models.FeedProfile{Skills: []string{"Go", "SQL", "Kafka", "Redis"}},
+			candidate: candidate(func(c *models.FeedCandidate) {
+				c.Description = "Go, SQL, Kafka и Redis"
+			}),
+			wantScore:   feedMaxSkills * feedSkillWeight,
+			wantReasons: []string{"Про ваши навыки: Go, SQL, Kafka, Redis"},
+		},
+		{
+			name:        "user group",
+			candidate:   candidate(func(c *models.FeedCandidate) { c.InUserGroup = true }),
+			wantScore:   feedGroupWeight,
+			wantReasons: []string{"Мероприятие вашего сообщества"},
+		},
+		{
+			name: "attended group and organizer",
+			candidate: candidate(func(c *models.FeedCandidate) {
+				c.AttendedInGroup = 5
+				c.AttendedOrganizer = 2
+			}),
+			wantScore:   feedMaxAttended*feedAttendedGroupWeight + 2*feedAttendedOrgWeight,
+			wantReasons: []string{"Вы были на мероприятиях этого сообщества: 5"},
+		},
+		{
+			name:        "friends going",
+			candidate:   candidate(func(c *models.FeedCandidate) { c.FriendsGoing = 10 }),
+			wantScore:   feedMaxFriends * feedFriendWeight,
+			wantReasons: []string{"Идут ваши знакомые: 10"},
+		},
+		{
+			name:        "nearby",
+			profile:     models.FeedProfile{City: "Казань"},
+			candidate:   candidate(func(c *models.FeedCandidate) { c.DistanceKm = km(3.24) }),
+			wantScore:   2,
+			wantReasons: []string{"Рядом с вами: 3.2 км"},
+		},
+		{
+			name:        "not far",
+			candidate:   candidate(func(c *models.FeedCandidate) { c.DistanceKm = km(15) }),
+			wantScore:   1,
+			wantReasons: []string{"Недалеко: 15 км"},
+		},
+		{
+			name:      "far away in the same city name",
+			profile:   models.FeedProfile{City: "Казань"},
+			candidate: candidate(func(c *models.FeedCandidate) { c.DistanceKm = km(150) }),
+			wantScore: -1,
+		},
+		{
+			name:        "same city without distance",
+			profile:     models.FeedProfile{City: "казань"},
+			candidate:   candidate(nil),
+			wantScore:   feedCityWeight,
+			wantReasons: []string{"В вашем городе"},
+		},
+		{
+			name: "dismissed similar events",
+			candidate: candidate(func(c *models.FeedCandidate) {
+				c.DismissedInGroup = 10
+				c.DismissedOrganizer = 1
+			}),
+			wantScore: -feedMaxDismissed*feedDismissedGroupWeight - feedDismissedOrgWeight,
+		},
+		{
+			name:      "popularity",
+			candidate: candidate(func(c *models.FeedCandidate) { c.Going = 99 }),
+			wantScore: feedPopularityWeight * math.Log(100),
+		},
+		{
+			name:      "starts soon",
+			candidate: candidate(func(c *models.FeedCandidate) { c.StartsAt = now.Add(feedHorizon / 4) }),
+			wantScore: feedSoonWeight * 0.75,
+		},
+		{
+			name:      "already started",
+			candidate: candidate(func(c *models.FeedCandidate) { c.StartsAt = now.Add(-time.Hour) }),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reasons := ScoreFeedEvent(tt.profile, tt.candidate, now)
+			if math.Abs(score-tt.wantScore) > 1e-9 {
+				t.Errorf("score = %v, want %v", score, tt.wantScore)
+			}
+			if strings.Join(reasons, "\n") != strings.Join(tt.wantReasons, "\n") {
+				t.Errorf("reasons = %q, want %q", reasons, tt.wantReasons)
+			}
+		})
+	}
+}
+
+func TestScoreFeedEventIsDeterministic(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	profile := models.FeedProfile{Skills: []string{"Go", "Kafka"}, City: "Казань"}
+	c := models.FeedCandidate{
+		Event:        models.Event{Title: "Go и Kafka", City: "Казань", StartsAt: now.Add(7 * 24 * time.Hour)},
+		InUserGroup:  true,
+		FriendsGoing: 2,
+		Going:        40,
+	}
+
+	score, reasons := ScoreFeedEvent(profile, c, now)
+	for i := 0; i < 10; i++ {
+		again, againReasons := ScoreFeedEvent(profile, c, now)
+		if again != score || strings.Join(againReasons, "\n") != strings.Join(reasons, "\n") {
+			t.Fatalf("ScoreFeedEvent() = %v %q, then %v %q", score, reasons, again, againReasons)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/storage"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+var (
+	ErrEventNotFinished = errors.New("event is not finished yet")
+	ErrInvalidRating    = errors.New("rating must be from 1 to 5")
+	ErrSlotNotTalk      = errors.New("agenda slot is not a talk")
+)
+
+type FeedbackService struct {
+	repo   FeedbackStorageInt
+	events EventStorageInt
+	agenda AgendaStorageInt
+	rsvps  RSVPStorageInt
+	logger *slog.Logger
+}
+
+func NewFeedbackService(
+	repo FeedbackStorageInt,
+	events EventStorageInt,
+	agenda AgendaStorageInt,
+	rsvps RSVPStorageInt,
+	logger *slog.Logger,
+) *FeedbackService {
+	return &FeedbackService{repo: repo, events: events, agenda: agenda, rsvps: rsvps, logger: logger}
+}
+
+// LeaveEventFeedback сохраняет оценку мероприятия. Оценивать можно только
+// после окончания мероприятия и только записавшимся на него
+func (s *FeedbackService) LeaveEventFeedback(feedback models.Feedback) (int, error) {
+	const op = "service.FeedbackService.LeaveEventFeedback"
+
+	feedback.SlotID = nil
+	if err := s.checkFeedback(feedback); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := s.repo.SaveFeedback(feedback)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// LeaveTalkFeedback сохраняет оценку доклада из программы мероприятия
+func (s *FeedbackService) LeaveTalkFeedback(feedback models.Feedback) (int, error) {
+	const op = "service.FeedbackService.LeaveTalkFeedback"
+
+	if feedback.SlotID == nil {
+		return 0, fmt.Errorf("%s: %w", op, storage.ErrSlotNotFound)
+	}
+
+	slot, err := s.eventSlot(feedback.EventID, *feedback.SlotID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if slot.Kind != models.AgendaSlotTalk {
+		return 0, fmt.Errorf("%s: %w", op, ErrSlotNotTalk)
+	}
+
+	speaker, err := s.agenda.IsSlotSpeaker(slot.ID, feedback.UserID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if speaker {
+		return 0, fmt.Errorf("%s: %w", op, ErrOwnTalkReview)
+	}
+
+	if err := s.checkFeedback(feedback); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := s.repo.SaveFeedback(feedback)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// EventFeedback возвращает организатору сводку оценок мероприятия и его докладов
+func (s *FeedbackService) EventFeedback(organizerID, eventID int) (models.EventFeedbackReport, error) {
+	const op = "service.FeedbackService.EventFeedback"
+
+	if _, err := organizedEvent(s.events, organizerID, eventID); err != nil {
+		return models.EventFeedbackReport{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	feedback, err := s.repo.EventFeedback(eventID)
+	if err != nil {
+		return models.EventFeedbackReport{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	talks, err := s.repo.TalkRatings(eventID)
+	if err != nil {
+		return models.EventFeedbackReport{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return models.EventFeedbackReport{
+		RatingSummary: summarizeRatings(feedback),
+		Comments:      publicComments(feedback),
+		Talks:         talks,
+	}, nil
+}
+
+// TalkFeedback возвращает отзывы на доклад. Их видят докладчики этого доклада
+// и организатор мероприятия
+func (s *FeedbackService) TalkFeedback(userID, eventID, slotID int) (models.TalkFeedbackReport, error) {
+	const op = "service.FeedbackService.TalkFeedback"
+
+	slot, err := s.eventSlot(eventID, slotID)
+	if err != nil {
+		return models.TalkFeedbackReport{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	speaker, err := s.agenda.IsSlotSpeaker(slotID, userID)
+	if err != nil {
+		return models.TalkFeedbackReport{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if !speaker {
+		if _, err := organizedEvent(s.events, userID, eventID); err != nil {
+			return models.TalkFeedbackReport{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	feedback, err := s.repo.SlotFeedback(slotID)
+	if err != nil {
+		return models.TalkFeedbackReport{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return models.TalkFeedbackReport{
+		Slot:          slot,
+		RatingSummary: summarizeRatings(feedback),
+		Comments:      publicComments(feedback),
+	}, nil
+}
+
+// checkFeedback проверяет оценку и право пользователя оставить отзыв
+func (s *FeedbackService) checkFeedback(feedback models.Feedback) error {
+	if feedback.Rating < models.MinRating || feedback.Rating > models.MaxRating {
+		return ErrInvalidRating
+	}
+
+	event, err := s.events.Event(feedback.EventID)
+	if err != nil {
+		return err
+	}
+
+	if time.Now().Before(event.EndsAt) {
+		return ErrEventNotFinished
+	}
+
+	rsvp, err := s.rsvps.RSVP(feedback.EventID, feedback.UserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrRSVPNotFound) {
+			return ErrForbidden
+		}
+
+		return err
+	}
+
+	if rsvp.Status != models.RSVPStatusGoing && rsvp.CheckedInAt == nil {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+// eventSlot возвращает слот, если он входит в программу мероприятия
+func (s *FeedbackService) eventSlot(eventID, slotID int) (models.AgendaSlot, error) {
+	slot, err := s.agenda.Slot(slotID)
+	if err != nil {
+		return models.AgendaSlot{}, err
+	}
+
+	if slot.EventID != eventID {
+		return models.AgendaSlot{}, storage.ErrSlotNotFound
+	}
+
+	return slot, nil
+}
+
+func summarizeRatings(feedback []models.Feedback) models.RatingSummary {
+	var summary models.RatingSummary
+	if len(feedback) == 0 {
+		return summary
+	}
+
+	total := 0
+	for _, f := range feedback {
+		summary.Distribution[f.Rating-1]++
+		total += f.Rating
+	}
+	summary.Count = len(feedback)
+	summary.Average = float64(total) / float64(summary.Count)
+
+	return summary
+}
+
+// publicComments оставляет отзывы с текстом и скрывает авторов анонимных отзывов
+func publicComments(feedback []models.Feedback) []models.Feedback {
+	comments := make([]models.Feedback, 0, len(feedback))
+	for _, f := range feedback {
+		if f.Comment == "" {
+			continue
+		}
+		if f.Anonymous {
+			f.UserID = 0
+		}
+		comments = append(comments, f)
+	}
+
+	return comments
+}
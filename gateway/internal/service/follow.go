@@ -0,0 +1,147 @@
+package service
+
+import (
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+var (
+	ErrInvalidFollow = errors.New("cannot follow yourself")
+)
+
+// FollowService - подписки на пользователей и сообщества и лента их действий.
+// Действие записывается один раз от имени автора, а лента подписчика собирается
+// из действий тех, на кого он подписан, в момент чтения
+type FollowService struct {
+	repo   FollowStorageInt
+	blocks BlockChecker
+	logger *slog.Logger
+}
+
+func NewFollowService(repo FollowStorageInt, blocks BlockChecker, logger *slog.Logger) *FollowService {
+	return &FollowService{repo: repo, blocks: blocks, logger: logger}
+}
+
+// FollowUser подписывает пользователя на followeeID. Подписаться на того, с кем
+// есть блокировка, нельзя
+func (s *FollowService) FollowUser(userID, followeeID int) error {
+	const op = "service.FollowService.FollowUser"
+
+	if userID == followeeID {
+		return fmt.Errorf("%s: %w", op, ErrInvalidFollow)
+	}
+
+	blocked, err := s.blocks.BlockedBetween(userID, []int{followeeID})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if blocked {
+		return fmt.Errorf("%s: %w", op, ErrUserBlocked)
+	}
+
+	if err := s.repo.FollowUser(userID, followeeID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *FollowService) UnfollowUser(userID, followeeID int) error {
+	const op = "service.FollowService.UnfollowUser"
+
+	if err := s.repo.UnfollowUser(userID, followeeID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *FollowService) FollowGroup(userID, groupID int) error {
+	const op = "service.FollowService.FollowGroup"
+
+	if err := s.repo.FollowGroup(userID, groupID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *FollowService) UnfollowGroup(userID, groupID int) error {
+	const op = "service.FollowService.UnfollowGroup"
+
+	if err := s.repo.UnfollowGroup(userID, groupID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *FollowService) Followers(userID, limit, offset int) ([]models.Follow, error) {
+	const op = "service.FollowService.Followers"
+
+	follows, err := s.repo.Followers(userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return follows, nil
+}
+
+func (s *FollowService) Following(userID, limit, offset int) ([]models.Follow, error) {
+	const op = "service.FollowService.Following"
+
+	follows, err := s.repo.Following(userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return follows, nil
+}
+
+func (s *FollowService) FollowedGroups(userID, limit, offset int) ([]models.GroupFollow, error) {
+	const op = "service.FollowService.FollowedGroups"
+
+	groups, err := s.repo.FollowedGroups(userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return groups, nil
+}
+
+func (s *FollowService) GroupFollowers(groupID, limit, offset int) ([]models.Follow, error) {
+	const op = "service.FollowService.GroupFollowers"
+
+	follows, err := s.repo.GroupFollowers(groupID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return follows, nil
+}
+
+// Activities возвращает ленту подписок пользователя, новые действия сначала
+func (s *FollowService) Activities(userID int, filter models.ActivityFilter) ([]models.Activity, error) {
+	const op = "service.FollowService.Activities"
+
+	activities, err := s.repo.Activities(userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return activities, nil
+}
+
+// RecordActivity записывает действие пользователя. Ошибка только логируется:
+// действие уже совершено, и лента подписчиков не должна его откатывать
+func (s *FollowService) RecordActivity(activity models.Activity) {
+	if err := s.repo.CreateActivity(activity); err != nil {
+		s.logger.Error("failed to record activity",
+			slog.String("kind", activity.Kind),
+			slog.Int("actor_id", activity.ActorID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
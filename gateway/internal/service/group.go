@@ -0,0 +1,112 @@
+package service
+
+import (
+	"dev_meets/internal/domain/models"
+	"fmt"
+	"log/slog"
+)
+
+type GroupService struct {
+	repo        GroupStorageInt
+	activities  ActivityRecorder
+	invitations InvitationChecker
+	logger      *slog.Logger
+}
+
+func NewGroupService(
+	repo GroupStorageInt,
+	activities ActivityRecorder,
+	invitations InvitationChecker,
+	logger *slog.Logger,
+) *GroupService {
+	return &GroupService{repo: repo, activities: activities, invitations: invitations, logger: logger}
+}
+
+func (s *GroupService) CreateGroup(group models.Group) (int, error) {
+	const op = "service.GroupService.CreateGroup"
+
+	id, err := s.repo.CreateGroup(group)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("group created", slog.Int("group_id", id), slog.Int("owner_id", group.OwnerID))
+
+	return id, nil
+}
+
+func (s *GroupService) Group(id int) (models.Group, error) {
+	const op = "service.GroupService.Group"
+
+	group, err := s.repo.Group(id)
+	if err != nil {
+		return models.Group{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return group, nil
+}
+
+func (s *GroupService) Groups(limit, offset int) ([]models.Group, error) {
+	const op = "service.GroupService.Groups"
+
+	groups, err := s.repo.Groups(limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return groups, nil
+}
+
+func (s *GroupService) JoinGroup(userID, groupID int) error {
+	const op = "service.GroupService.JoinGroup"
+
+	group, err := s.repo.Group(groupID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if group.Private {
+		invited, err := s.invitations.HasInvitation(models.InvitationSubjectGroup, groupID, userID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if !invited {
+			return fmt.Errorf("%s: %w", op, ErrInvitationRequired)
+		}
+	}
+
+	joined, err := s.repo.AddMember(groupID, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if joined {
+		s.activities.RecordActivity(models.Activity{Kind: models.ActivityGroupJoined, ActorID: userID, GroupID: &groupID})
+	}
+
+	return nil
+}
+
+func (s *GroupService) LeaveGroup(userID, groupID int) error {
+	const op = "service.GroupService.LeaveGroup"
+
+	if err := s.repo.RemoveMember(groupID, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ownedGroup возвращает сообщество, если userID является его владельцем
+func ownedGroup(groups GroupStorageInt, userID, groupID int) (models.Group, error) {
+	group, err := groups.Group(groupID)
+	if err != nil {
+		return models.Group{}, err
+	}
+
+	if group.OwnerID != userID {
+		return models.Group{}, ErrForbidden
+	}
+
+	return group, nil
+}
@@ -1,9 +1,385 @@
 package service
 
-import "dev_meets/internal/domain/models"
+import (
+	"context"
+	"crypto/ed25519"
+	"dev_meets/internal/domain/models"
+	"dev_meets/pkg/payment"
+	"dev_meets/pkg/telegram"
+	"dev_meets/pkg/ticket"
+	"net/http"
+	"time"
+)
 
 type UserStorageInt interface {
 	CreateUser(user models.User) (int, error)
 	UserByEmail(email string) (models.User, error)
+	UsersByEmail(email string) ([]models.User, error)
 	User(id int) (models.User, error)
+	UpdateProfile(id int, profile models.Profile) error
+	UsersByUsernames(usernames []string) ([]models.User, error)
+	UpdatePassword(id int, passHash string) (int, error)
+	CancelDeletion(id int) error
+}
+
+type EventStorageInt interface {
+	CreateEvent(event models.Event) (int, error)
+	Event(id int) (models.Event, error)
+	Events(filter models.EventFilter) ([]models.Event, error)
+	CancelEvent(id int) error
+}
+
+type CFPStorageInt interface {
+	CreateCFP(cfp models.CallForPapers) error
+	CFP(eventID int) (models.CallForPapers, error)
+	AddReviewer(eventID, userID int) error
+	IsReviewer(eventID, userID int) (bool, error)
+	CreateTalk(talk models.Talk) (int, error)
+	Talk(id int) (models.Talk, error)
+	TalksByEvent(eventID int) ([]models.TalkSummary, error)
+	CreateReview(review models.TalkReview) (int, error)
+	ReviewsByTalk(talkID int) ([]models.TalkReview, error)
+	AcceptTalk(talkID int) error
+	RejectTalk(talkID int) error
+}
+
+type AgendaStorageInt interface {
+	Agenda(eventID int) ([]models.AgendaSlot, error)
+	Slot(id int) (models.AgendaSlot, error)
+	CreateSlot(slot models.AgendaSlot, speakerIDs []int) (int, error)
+	UpdateSlot(slot models.AgendaSlot, speakerIDs []int) error
+	DeleteSlot(eventID, slotID int) error
+	CreateSpeaker(speaker models.Speaker) (int, error)
+	Speaker(id int) (models.Speaker, error)
+	UpdateSpeaker(speaker models.Speaker) error
+	IsSlotSpeaker(slotID, userID int) (bool, error)
+}
+
+type VenueStorageInt interface {
+	CreateVenue(venue models.Venue) (int, error)
+	UpdateVenue(venue models.Venue) error
+	Venue(id int) (models.Venue, error)
+	Venues(filter models.VenueFilter) ([]models.Venue, error)
+}
+
+type GroupStorageInt interface {
+	CreateGroup(group models.Group) (int, error)
+	Group(id int) (models.Group, error)
+	Groups(limit, offset int) ([]models.Group, error)
+	AddMember(groupID, userID int) (bool, error)
+	RemoveMember(groupID, userID int) error
+	MemberRole(groupID, userID int) (string, error)
+	MemberIDs(groupID int) ([]int, error)
+}
+
+type SearchStorageInt interface {
+	Search(query models.SearchQuery) ([]models.SearchResult, error)
+}
+
+type RSVPStorageInt interface {
+	CreateRSVP(eventID, userID int) (models.RSVP, error)
+	CancelRSVP(eventID, userID int) error
+	RSVP(eventID, userID int) (models.RSVP, error)
+	RSVPByID(id int) (models.RSVP, error)
+	CheckIn(id, checkedInBy int) (models.RSVP, error)
+	Attendance(eventID int) (models.Attendance, error)
+}
+
+type FeedbackStorageInt interface {
+	SaveFeedback(feedback models.Feedback) (int, error)
+	EventFeedback(eventID int) ([]models.Feedback, error)
+	SlotFeedback(slotID int) ([]models.Feedback, error)
+	TalkRatings(eventID int) ([]models.TalkRating, error)
+}
+
+type CommentStorageInt interface {
+	CreateComment(comment models.Comment) (int, error)
+	Comment(id int) (models.Comment, error)
+	Threads(filter models.CommentFilter) ([]models.Comment, error)
+	UpdateComment(id int, body, bodyHTML string) error
+	DeleteComment(id int) error
+	CommentRevisions(commentID int) ([]models.CommentRevision, error)
+}
+
+type NotificationStorageInt interface {
+	CreateNotification(notification models.Notification, email bool) (int, error)
+	Notifications(userID int, filter models.NotificationFilter) ([]models.Notification, error)
+	UnreadCount(userID int) (int, error)
+	MarkRead(userID, id int) error
+	MarkAllRead(userID int) (int, error)
+	NotificationPreferences(userID int) ([]models.NotificationPreference, error)
+	NotificationChannel(userID int, notificationType string) (string, error)
+	SetNotificationPreferences(userID int, preferences []models.NotificationPreference) error
+	ClaimEmailNotifications(users int) ([]models.Notification, error)
+	ReleaseEmailNotifications(ids []int) error
+}
+
+type StreamStorageInt interface {
+	CreateStreamEvent(event models.StreamEvent) (int64, error)
+	StreamEvent(id int64) (models.StreamEvent, error)
+	StreamEventsAfter(afterID int64, limit int) ([]models.StreamEvent, error)
+	SubscriberStreamEvents(userID int, topics []string, afterID int64, limit int) ([]models.StreamEvent, error)
+	LastStreamEventID() (int64, error)
+	DeleteStreamEventsBefore(before time.Time) (int, error)
+}
+
+type ReminderStorageInt interface {
+	ClaimReminders(offset time.Duration, from, to time.Time) ([]models.Reminder, error)
+}
+
+type JobStorageInt interface {
+	Job(id int64) (models.Job, error)
+	Jobs(filter models.JobFilter) ([]models.Job, error)
+	RequeueJob(id int64) error
+}
+
+type WebhookStorageInt interface {
+	CreateWebhook(webhook models.Webhook) (int, error)
+	Webhook(id int) (models.Webhook, error)
+	GroupWebhooks(groupID int) ([]models.Webhook, error)
+	SubscribedWebhooks(groupID int, eventType string) ([]models.Webhook, error)
+	UpdateWebhook(webhook models.Webhook) error
+	DeleteWebhook(id int) error
+	RecordDelivery(delivery models.WebhookDelivery, disableAfter int) (bool, error)
+	WebhookDeliveries(webhookID, cursor, limit int) ([]models.WebhookDelivery, error)
+}
+
+type TelegramStorageInt interface {
+	CreateTelegramLinkCode(userID int, code string, expiresAt time.Time) error
+	LinkTelegramAccount(code string, telegramUserID int64) (int, error)
+	UnlinkTelegramAccount(userID int) error
+	UserIDByTelegramID(telegramUserID int64) (int, error)
+	AddGroupChat(groupID int, chatID int64) error
+	RemoveGroupChat(groupID int, chatID int64) error
+	RemoveChat(chatID int64) error
+	GroupChats(groupID int) ([]int64, error)
+	WithTelegramPollLock(ctx context.Context, poll func(ctx context.Context)) (bool, error)
+}
+
+type MessageStorageInt interface {
+	CreateConversation(conversation models.Conversation, memberIDs []int) (int, error)
+	Conversation(id, userID int) (models.Conversation, error)
+	Conversations(userID, limit, offset int) ([]models.Conversation, error)
+	CreateMessage(message models.Message, limit int, since time.Time) (models.Message, error)
+	Messages(conversationID int, filter models.MessageFilter) ([]models.Message, error)
+	MarkConversationRead(conversationID, userID, messageID int) (int, error)
+	CountConversationsSince(userID int, since time.Time) (int, error)
+	BlockUser(blockerID, blockedID int) error
+	UnblockUser(blockerID, blockedID int) error
+	Blocks(userID int) ([]models.Block, error)
+	BlockedBetween(userID int, userIDs []int) (bool, error)
+	Blockers(blockedID int, userIDs []int) ([]int, error)
+}
+
+type MatchStorageInt interface {
+	SetMatchProfile(userID int, goals []string) error
+	DeleteMatchProfile(userID int) error
+	MatchCandidate(userID int) (models.MatchCandidate, error)
+	MatchCandidates(userID int, goal string, limit int) ([]models.MatchCandidate, error)
+	CreateMatchRequest(request models.MatchRequest) (int, error)
+	MatchRequest(id int) (models.MatchRequest, error)
+	MatchRequests(userID int, filter models.MatchRequestFilter) ([]models.MatchRequest, error)
+	RespondMatchRequest(id int, status string, conversationID *int) error
+	CountMatchRequestsSince(userID int, since time.Time) (int, error)
+}
+
+type FollowStorageInt interface {
+	FollowUser(followerID, followeeID int) error
+	UnfollowUser(followerID, followeeID int) error
+	FollowGroup(userID, groupID int) error
+	UnfollowGroup(userID, groupID int) error
+	Followers(userID, limit, offset int) ([]models.Follow, error)
+	Following(userID, limit, offset int) ([]models.Follow, error)
+	GroupFollowers(groupID, limit, offset int) ([]models.Follow, error)
+	FollowedGroups(userID, limit, offset int) ([]models.GroupFollow, error)
+	CreateActivity(activity models.Activity) error
+	Activities(userID int, filter models.ActivityFilter) ([]models.Activity, error)
+}
+
+type ModerationStorageInt interface {
+	ReportTarget(reporterID int, targetType string, targetID int) (models.ReportTarget, error)
+	CreateReport(report models.Report) (int, error)
+	CountReportsSince(reporterID int, since time.Time) (int, error)
+	Report(id int) (models.Report, error)
+	Reports(filter models.ReportFilter) ([]models.Report, error)
+	Moderate(action models.ModerationAction) (models.ModerationAction, error)
+	ModerationActions(filter models.ModerationActionFilter) ([]models.ModerationAction, error)
+}
+
+type AdminStorageInt interface {
+	SearchUsers(filter models.AdminUserFilter) ([]models.User, error)
+	AccountState(userID int) (models.AccountState, error)
+	SetUserRole(userID int, role string, audit models.AuditEvent) error
+	SuspendUser(userID int, until *time.Time, reason string, audit models.AuditEvent) error
+	UnsuspendUser(userID int, audit models.AuditEvent) error
+	RevokeSessions(userID int, audit models.AuditEvent) error
+	MergeUsers(targetID, sourceID int, audit models.AuditEvent) error
+}
+
+type AuditStorageInt interface {
+	CreateAuditEvent(event models.AuditEvent) error
+	AuditEvents(filter models.AuditFilter) ([]models.AuditEvent, error)
+	DeleteAuditEventsBefore(before time.Time) (int, error)
+}
+
+type AccountStorageInt interface {
+	ScheduleDeletion(userID int, at time.Time, audit models.AuditEvent) error
+	AnonymizeUser(userID int, audit models.AuditEvent) error
+	UserRSVPs(userID int) ([]models.RSVP, error)
+	UserComments(userID int) ([]models.Comment, error)
+	UserMessages(userID int) ([]models.Message, error)
+	CreateDataExport(userID int, token string) (models.DataExport, error)
+	LatestDataExport(userID int) (models.DataExport, error)
+	CompleteDataExport(id int, archive []byte, expiresAt time.Time) error
+	DataExportArchive(token string) (models.DataExport, error)
+	DeleteExpiredDataExports() (int, error)
+	CreateEmailChange(change models.EmailChange) (models.EmailChange, error)
+	EmailChange(token string) (models.EmailChange, error)
+	CompleteEmailChange(change models.EmailChange, audit models.AuditEvent) error
+}
+
+type InvitationStorageInt interface {
+	CreateInvitation(invitation models.Invitation) (int, error)
+	Invitation(id int) (models.Invitation, error)
+	InvitationByToken(token string) (models.Invitation, error)
+	SubjectInvitations(subjectType string, subjectID, limit, offset int) ([]models.Invitation, error)
+	UserInvitations(userID int) ([]models.Invitation, error)
+	RevokeInvitation(id int) error
+	AcceptInvitation(token string, userID int) (models.Invitation, error)
+	InvitationChecker
+}
+
+type OrderStorageInt interface {
+	CreateTicketType(ticketType models.TicketType) (int, error)
+	TicketType(id int) (models.TicketType, error)
+	EventTicketTypes(eventID int) ([]models.TicketType, error)
+	CreateOrder(order models.Order) (models.Order, error)
+	SetOrderPayment(id int, provider, paymentID, paymentURL string) error
+	Order(id int) (models.Order, error)
+	OrderByPayment(provider, paymentID string) (models.Order, error)
+	UserOrders(userID, limit, offset int) ([]models.Order, error)
+	EventOrders(eventID, limit, offset int) ([]models.Order, error)
+	PayOrder(id int) (models.Order, *models.RSVP, error)
+	ExpireOrder(id int) error
+	CancelOrder(id int) error
+	RefundOrder(id int, audit models.AuditEvent) (models.Order, error)
+	TicketChecker
+}
+
+type FeedStorageInt interface {
+	FeedProfile(userID int) (models.FeedProfile, error)
+	FeedCandidates(userID int, near *models.GeoPoint, limit int) ([]models.FeedCandidate, error)
+	DismissEvent(userID, eventID int) error
+	UndismissEvent(userID, eventID int) error
+}
+
+// TicketSigner подписывает билеты и проверяет их подпись
+type TicketSigner interface {
+	Sign(claims ticket.Claims) (string, error)
+	Verify(payload string) (ticket.Claims, error)
+	PublicKey() ed25519.PublicKey
+}
+
+// Notifier доставляет пользователю уведомление о событии в системе
+type Notifier interface {
+	Notify(n models.Notification) error
+}
+
+// Mailer отправляет письмо на адрес пользователя
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// Publisher отправляет события подписчикам в реальном времени
+type Publisher interface {
+	PublishToUser(userID int, eventType string, payload any)
+	PublishToTopic(topic, eventType string, payload any)
+}
+
+// WebhookDispatcher отправляет события сообщества на его вебхуки
+type WebhookDispatcher interface {
+	DispatchWebhook(groupID int, eventType string, data any)
+}
+
+// EventAnnouncer публикует анонс нового мероприятия сообщества во внешние каналы
+type EventAnnouncer interface {
+	AnnounceEvent(event models.Event)
+}
+
+// ActivityRecorder записывает действие пользователя для ленты его подписчиков
+type ActivityRecorder interface {
+	RecordActivity(activity models.Activity)
+}
+
+// Auditor записывает действие в журнал аудита
+type Auditor interface {
+	Audit(event models.AuditEvent)
+}
+
+// RSVPCreator записывает пользователя на мероприятие
+type RSVPCreator interface {
+	RSVP(userID, eventID int) (models.Ticket, error)
+}
+
+// GroupJoiner вступает в сообщество от имени пользователя
+type GroupJoiner interface {
+	JoinGroup(userID, groupID int) error
+}
+
+// RSVPEvents сообщает о записях на мероприятие, которые создаются и отменяются вместе
+// с оплатой и возвратом заказа, минуя RSVPService.RSVP
+type RSVPEvents interface {
+	RSVPCreated(rsvp models.RSVP)
+	RSVPCancelled(eventID int)
+}
+
+// InvitationChecker проверяет, приглашён ли пользователь в закрытое сообщество или на
+// закрытое мероприятие
+type InvitationChecker interface {
+	HasInvitation(subjectType string, subjectID, userID int) (bool, error)
+}
+
+// TicketChecker проверяет, нужен ли пользователю оплаченный заказ, чтобы записаться
+// на мероприятие
+type TicketChecker interface {
+	TicketRequired(eventID, userID int) (bool, error)
+}
+
+// PaymentProvider создаёт платежи за заказы и возвращает деньги. Об исходе платежа
+// провайдер сообщает вебхуком, который разбирает ParseWebhook
+type PaymentProvider interface {
+	Name() string
+	CreatePayment(ctx context.Context, req payment.Request) (payment.Payment, error)
+	Refund(ctx context.Context, paymentID string, value int64, currency string) error
+	ParseWebhook(header http.Header, body []byte) (payment.Event, error)
+}
+
+// SignUpInvitations применяет приглашение нового пользователя после регистрации
+type SignUpInvitations interface {
+	ApplySignUpInvitation(userID int, token string)
+}
+
+// TelegramClient - методы Telegram Bot API, которые использует бот
+type TelegramClient interface {
+	GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]telegram.Update, error)
+	SendMessage(ctx context.Context, params telegram.SendMessageParams) error
+	AnswerCallbackQuery(ctx context.Context, callbackQueryID, text string) error
+	SetWebhook(ctx context.Context, url, secret string) error
+	DeleteWebhook(ctx context.Context) error
+}
+
+// MatchScorer оценивает, насколько кандидат подходит пользователю для цели знакомства,
+// и объясняет оценку. Нулевая оценка исключает кандидата из подбора
+type MatchScorer interface {
+	Score(user, candidate models.MatchCandidate, goal string) (float64, []string)
+}
+
+// BlockChecker проверяет блокировки между пользователями
+type BlockChecker interface {
+	BlockedBetween(userID int, userIDs []int) (bool, error)
+}
+
+// ConversationOpener открывает диалог от имени пользователя
+type ConversationOpener interface {
+	CreateConversation(userID int, memberIDs []int, title string) (models.Conversation, error)
 }
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"crypto/rand"
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/storage"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	invitationTokenBytes = 32
+	invitationLayout     = "02.01.2006 15:04 MST"
+)
+
+var (
+	ErrInvalidInvitation  = errors.New("invitation must name exactly one other user by username or email")
+	ErrInvalidInviteLink  = errors.New("invite link must expire in the future and allow at least one use")
+	ErrInvitationRequired = errors.New("joining requires an invitation")
+)
+
+type InvitationConfig struct {
+	// PersonalTTL - сколько действует приглашение по имени пользователя или почте
+	PersonalTTL time.Duration
+}
+
+// InvitationService выдаёт приглашения в закрытые сообщества и на закрытые мероприятия.
+// Владелец сообщества или организатор мероприятия приглашает человека по имени
+// пользователя или почте либо создаёт ссылку со сроком действия и числом использований.
+// Принятое приглашение сразу вступает в сообщество или записывает на мероприятие
+type InvitationService struct {
+	repo     InvitationStorageInt
+	users    UserStorageInt
+	groups   GroupStorageInt
+	events   EventStorageInt
+	joiner   GroupJoiner
+	rsvps    RSVPCreator
+	notifier Notifier
+	mailer   Mailer
+	config   InvitationConfig
+	logger   *slog.Logger
+}
+
+func NewInvitationService(
+	repo InvitationStorageInt,
+	users UserStorageInt,
+	groups GroupStorageInt,
+	events EventStorageInt,
+	joiner GroupJoiner,
+	rsvps RSVPCreator,
+	notifier Notifier,
+	mailer Mailer,
+	config InvitationConfig,
+	logger *slog.Logger,
+) *InvitationService {
+	return &InvitationService{
+		repo:     repo,
+		users:    users,
+		groups:   groups,
+		events:   events,
+		joiner:   joiner,
+		rsvps:    rsvps,
+		notifier: notifier,
+		mailer:   mailer,
+		config:   config,
+		logger:   logger,
+	}
+}
+
+// Invite приглашает пользователя по имени или человека по почте. Пользователь получает
+// уведомление, а на почту уходит код. Приглашение на почту не привязывается к аккаунту с
+// этим адресом: почта аккаунта не подтверждена, поэтому принять приглашение может только
+// тот, кто получил код
+func (s *InvitationService) Invite(
+	inviterID int,
+	subjectType string,
+	subjectID int,
+	username, email string,
+) (models.Invitation, error) {
+	const op = "service.InvitationService.Invite"
+
+	title, err := s.managedSubject(inviterID, subjectType, subjectID)
+	if err != nil {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if (username == "") == (email == "") {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, ErrInvalidInvitation)
+	}
+
+	invitation := models.Invitation{
+		SubjectType:  subjectType,
+		SubjectID:    subjectID,
+		InviterID:    inviterID,
+		SubjectTitle: title,
+	}
+
+	if username != "" {
+		users, err := s.users.UsersByUsernames([]string{username})
+		if err != nil {
+			return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+		}
+		if len(users) == 0 {
+			return models.Invitation{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+		if users[0].ID == inviterID {
+			return models.Invitation{}, fmt.Errorf("%s: %w", op, ErrInvalidInvitation)
+		}
+		invitation.InviteeID = &users[0].ID
+	} else {
+		invitation.Email = normalizeEmail(email)
+	}
+
+	token, err := newInvitationToken()
+	if err != nil {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+	maxUses := 1
+	expiresAt := time.Now().Add(s.config.PersonalTTL)
+	invitation.Token, invitation.MaxUses, invitation.ExpiresAt = token, &maxUses, &expiresAt
+
+	invitation.ID, err = s.repo.CreateInvitation(invitation)
+	if err != nil {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+	invitation.CreatedAt = time.Now()
+
+	s.logger.Info("invitation created",
+		slog.Int("invitation_id", invitation.ID), slog.String("subject_type", subjectType), slog.Int("subject_id", subjectID),
+	)
+
+	s.deliver(invitation)
+
+	return invitation, nil
+}
+
+// CreateInviteLink создаёт ссылку-приглашение. maxUses nil - без ограничения числа
+// использований, expiresAt nil - бессрочно
+func (s *InvitationService) CreateInviteLink(
+	inviterID int,
+	subjectType string,
+	subjectID int,
+	maxUses *int,
+	expiresAt *time.Time,
+) (models.Invitation, error) {
+	const op = "service.InvitationService.CreateInviteLink"
+
+	title, err := s.managedSubject(inviterID, subjectType, subjectID)
+	if err != nil {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if (maxUses != nil && *maxUses < 1) || (expiresAt != nil && !expiresAt.After(time.Now())) {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, ErrInvalidInviteLink)
+	}
+
+	token, err := newInvitationToken()
+	if err != nil {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	invitation := models.Invitation{
+		SubjectType:  subjectType,
+		SubjectID:    subjectID,
+		InviterID:    inviterID,
+		Token:        token,
+		MaxUses:      maxUses,
+		ExpiresAt:    expiresAt,
+		SubjectTitle: title,
+	}
+	invitation.ID, err = s.repo.CreateInvitation(invitation)
+	if err != nil {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+	invitation.CreatedAt = time.Now()
+
+	s.logger.Info("invite link created",
+		slog.Int("invitation_id", invitation.ID), slog.String("subject_type", subjectType), slog.Int("subject_id", subjectID),
+	)
+
+	return invitation, nil
+}
+
+// SubjectInvitations возвращает приглашения в сообщество или на мероприятие для его
+// владельца или организатора
+func (s *InvitationService) SubjectInvitations(
+	userID int,
+	subjectType string,
+	subjectID, limit, offset int,
+) ([]models.Invitation, error) {
+	const op = "service.InvitationService.SubjectInvitations"
+
+	if _, err := s.managedSubject(userID, subjectType, subjectID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	invitations, err := s.repo.SubjectInvitations(subjectType, subjectID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invitations, nil
+}
+
+// UserInvitations возвращает приглашения, которые ждут ответа пользователя
+func (s *InvitationService) UserInvitations(userID int) ([]models.Invitation, error) {
+	const op = "service.InvitationService.UserInvitations"
+
+	invitations, err := s.repo.UserInvitations(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invitations, nil
+}
+
+// RevokeInvitation отзывает приглашение. Принявшие его остаются в сообществе или
+// записанными на мероприятие, но после выхода вернуться по нему уже не смогут
+func (s *InvitationService) RevokeInvitation(userID, id int) error {
+	const op = "service.InvitationService.RevokeInvitation"
+
+	invitation, err := s.repo.Invitation(id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := s.managedSubject(userID, invitation.SubjectType, invitation.SubjectID); err != nil {
+		// чужие приглашения выглядят как несуществующие
+		if errors.Is(err, ErrForbidden) {
+			return fmt.Errorf("%s: %w", op, storage.ErrInvitationNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.repo.RevokeInvitation(id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("invitation revoked", slog.Int("invitation_id", id), slog.Int("user_id", userID))
+
+	return nil
+}
+
+// Invitation возвращает действующее приглашение по секрету, чтобы показать, куда
+// оно ведёт, ещё до входа или регистрации
+func (s *InvitationService) Invitation(token string) (models.Invitation, error) {
+	const op = "service.InvitationService.Invitation"
+
+	invitation, err := s.repo.InvitationByToken(token)
+	if err != nil {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invitation, nil
+}
+
+// AcceptInvitation принимает приглашение и сразу вступает в сообщество или записывает
+// на мероприятие
+func (s *InvitationService) AcceptInvitation(userID int, token string) (models.Invitation, error) {
+	const op = "service.InvitationService.AcceptInvitation"
+
+	invitation, err := s.repo.AcceptInvitation(token, userID)
+	if err != nil {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	switch invitation.SubjectType {
+	case models.InvitationSubjectGroup:
+		err = s.joiner.JoinGroup(userID, invitation.SubjectID)
+	case models.InvitationSubjectEvent:
+		_, err = s.rsvps.RSVP(userID, invitation.SubjectID)
+	}
+	if err != nil {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("invitation accepted", slog.Int("invitation_id", invitation.ID), slog.Int("user_id", userID))
+
+	return invitation, nil
+}
+
+// ApplySignUpInvitation вызывается после регистрации и принимает приглашение, с которым
+// пришёл пользователь. Ошибка только записывается в журнал - регистрация из-за неё не откатывается
+func (s *InvitationService) ApplySignUpInvitation(userID int, token string) {
+	if token == "" {
+		return
+	}
+	if _, err := s.AcceptInvitation(userID, token); err != nil {
+		s.logger.Info("failed to apply sign-up invitation", slog.Int("user_id", userID), slog.String("error", err.Error()))
+	}
+}
+
+// managedSubject возвращает название сообщества или мероприятия, если userID - его
+// владелец или организатор
+func (s *InvitationService) managedSubject(userID int, subjectType string, subjectID int) (string, error) {
+	switch subjectType {
+	case models.InvitationSubjectGroup:
+		group, err := ownedGroup(s.groups, userID, subjectID)
+		if err != nil {
+			return "", err
+		}
+
+		return group.Name, nil
+	case models.InvitationSubjectEvent:
+		event, err := organizedEvent(s.events, userID, subjectID)
+		if err != nil {
+			return "", err
+		}
+		if event.CancelledAt != nil {
+			return "", storage.ErrEventCancelled
+		}
+
+		return event.Title, nil
+	default:
+		return "", ErrInvalidInvitation
+	}
+}
+
+// deliver сообщает о личном приглашении: пользователю - уведомлением, на почту - письмом с кодом
+func (s *InvitationService) deliver(invitation models.Invitation) {
+	where := fmt.Sprintf("в сообщество «%s»", invitation.SubjectTitle)
+	if invitation.SubjectType == models.InvitationSubjectEvent {
+		where = fmt.Sprintf("на мероприятие «%s»", invitation.SubjectTitle)
+	}
+
+	if invitation.InviteeID != nil {
+		err := s.notifier.Notify(models.Notification{
+			UserID: *invitation.InviteeID,
+			Type:   models.NotificationInvitation,
+			Title:  "Вас пригласили " + where,
+			Body:   "Принять приглашение можно до " + invitation.ExpiresAt.Format(invitationLayout),
+		})
+		if err != nil {
+			s.logger.Error("failed to send notification", slog.String("error", err.Error()))
+		}
+
+		return
+	}
+
+	body := fmt.Sprintf("Вас пригласили %s. Зарегистрируйтесь с кодом приглашения %s, и оно применится "+
+		"автоматически, а если аккаунт уже есть, примите приглашение по этому коду после входа. Код действует до %s.",
+		where, invitation.Token, invitation.ExpiresAt.Format(invitationLayout))
+	if err := s.mailer.Send(invitation.Email, "Приглашение", body); err != nil {
+		s.logger.Error("failed to send invitation",
+			slog.Int("invitation_id", invitation.ID), slog.String("error", err.Error()),
+		)
+	}
+}
+
+func newInvitationToken() (string, error) {
+	token := make([]byte, invitationTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(token), nil
+}
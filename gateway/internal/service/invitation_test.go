@@ -0,0 +1,374 @@
+package service
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/storage"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeInvitationStorage хранит приглашения в памяти. Личное приглашение по имени
+// принимает только адресат, по почте - любой, у кого есть код
+type fakeInvitationStorage struct {
+	InvitationStorageInt
+	invitations []models.Invitation
+}
+
+func (s *fakeInvitationStorage) CreateInvitation(invitation models.Invitation) (int, error) {
+	invitation.ID = len(s.invitations) + 1
+	s.invitations = append(s.invitations, invitation)
+
+	return invitation.ID, nil
+}
+
+func (s *fakeInvitationStorage) AcceptInvitation(token string, userID int) (models.Invitation, error) {
+	for i, invitation := range s.invitations {
+		if invitation.Token != token || (invitation.InviteeID != nil && *invitation.InviteeID != userID) {
+			continue
+		}
+		if invitation.MaxUses != nil && invitation.Uses >= *invitation.MaxUses {
+			return models.Invitation{}, storage.ErrInvitationUsedUp
+		}
+		s.invitations[i].Uses++
+
+		return s.invitations[i], nil
+	}
+
+	return models.Invitation{}, storage.ErrInvitationNotFound
+}
+
+// fakeUserStorage находит пользователей по имени. Остальные методы, в том числе поиск
+// по почте, не реализованы: приглашение не должно к ним обращаться
+type fakeUserStorage struct {
+	UserStorageInt
+	users []models.User
+}
+
+func (s *fakeUserStorage) UsersByUsernames(usernames []string) ([]models.User, error) {
+	var found []models.User
+	for _, user := range s.users {
+		for _, username := range usernames {
+			if user.Username == username {
+				found = append(found, user)
+			}
+		}
+	}
+
+	return found, nil
+}
+
+type fakeGroupJoiner struct {
+	joined map[int][]int
+}
+
+func (j *fakeGroupJoiner) JoinGroup(userID, groupID int) error {
+	j.joined[groupID] = append(j.joined[groupID], userID)
+	return nil
+}
+
+type fakeNotifier struct {
+	notifications []models.Notification
+}
+
+func (n *fakeNotifier) Notify(notification models.Notification) error {
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+type sentMail struct {
+	to, subject, body string
+}
+
+type fakeMailer struct {
+	sent []sentMail
+}
+
+func (m *fakeMailer) Send(to, subject, body string) error {
+	m.sent = append(m.sent, sentMail{to: to, subject: subject, body: body})
+	return nil
+}
+
+const (
+	invitationOwner   = 10
+	invitationInvitee = 20
+	invitationGroupID = 3
+	invitationEventID = 7
+)
+
+func newTestInvitationService(
+	repo *fakeInvitationStorage,
+	events *fakeEventStorage,
+	joiner *fakeGroupJoiner,
+	rsvps *fakeRSVPCreator,
+	notifier *fakeNotifier,
+	mailer *fakeMailer,
+) *InvitationService {
+	users := &fakeUserStorage{users: []models.User{
+		{ID: invitationOwner, Profile: models.Profile{Username: "owner"}},
+		{ID: invitationInvitee, Profile: models.Profile{Username: "invitee"}},
+	}}
+	groups := &fakeGroupStorage{groups: map[int]models.Group{
+		invitationGroupID: {ID: invitationGroupID, OwnerID: invitationOwner, Name: "Gophers"},
+	}}
+
+	return NewInvitationService(repo, users, groups, events, joiner, rsvps, notifier, mailer,
+		InvitationConfig{PersonalTTL: 72 * time.Hour}, testLogger())
+}
+
+func invitationEvent() models.Event {
+	return models.Event{ID: invitationEventID, OrganizerID: invitationOwner, Title: "GoConf", Private: true}
+}
+
+func newFakeGroupJoiner() *fakeGroupJoiner {
+	return &fakeGroupJoiner{joined: make(map[int][]int)}
+}
+
+// newInvitationRSVPs записывает на мероприятие invitationEventID с ошибкой err
+func newInvitationRSVPs(err error) *fakeRSVPCreator {
+	return &fakeRSVPCreator{events: map[int]error{invitationEventID: err}, rsvps: make(map[int][]int)}
+}
+
+func TestInviteRules(t *testing.T) {
+	tests := []struct {
+		name        string
+		inviterID   int
+		subjectType string
+		subjectID   int
+		username    string
+		email       string
+		event       func(event *models.Event)
+		wantErr     error
+	}{
+		{
+			name: "by username", inviterID: invitationOwner, subjectType: models.InvitationSubjectGroup,
+			subjectID: invitationGroupID, username: "invitee",
+		},
+		{
+			name: "by email", inviterID: invitationOwner, subjectType: models.InvitationSubjectEvent,
+			subjectID: invitationEventID, email: "guest@example.com",
+		},
+		{
+			name: "yourself by username", inviterID: invitationOwner, subjectType: models.InvitationSubjectGroup,
+			subjectID: invitationGroupID, username: "owner", wantErr: ErrInvalidInvitation,
+		},
+		{
+			name: "unknown username", inviterID: invitationOwner, subjectType: models.InvitationSubjectGroup,
+			subjectID: invitationGroupID, username: "nobody", wantErr: storage.ErrUserNotFound,
+		},
+		{
+			name: "both username and email", inviterID: invitationOwner, subjectType: models.InvitationSubjectGroup,
+			subjectID: invitationGroupID, username: "invitee", email: "guest@example.com", wantErr: ErrInvalidInvitation,
+		},
+		{
+			name: "nobody", inviterID: invitationOwner, subjectType: models.InvitationSubjectGroup,
+			subjectID: invitationGroupID, wantErr: ErrInvalidInvitation,
+		},
+		{
+			name: "not the group owner", inviterID: invitationInvitee, subjectType: models.InvitationSubjectGroup,
+			subjectID: invitationGroupID, username: "owner", wantErr: ErrForbidden,
+		},
+		{
+			name: "not the organizer", inviterID: invitationInvitee, subjectType: models.InvitationSubjectEvent,
+			subjectID: invitationEventID, email: "guest@example.com", wantErr: ErrForbidden,
+		},
+		{
+			name: "cancelled event", inviterID: invitationOwner, subjectType: models.InvitationSubjectEvent,
+			subjectID: invitationEventID, username: "invitee",
+			event: func(event *models.Event) {
+				cancelledAt := time.Now()
+				event.CancelledAt = &cancelledAt
+			},
+			wantErr: storage.ErrEventCancelled,
+		},
+		{
+			name: "unknown subject type", inviterID: invitationOwner, subjectType: "venue",
+			subjectID: 1, username: "invitee", wantErr: ErrInvalidInvitation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := invitationEvent()
+			if tt.event != nil {
+				tt.event(&event)
+			}
+			repo := &fakeInvitationStorage{}
+			s := newTestInvitationService(repo, newFakeEventStorage(event), newFakeGroupJoiner(), newInvitationRSVPs(nil),
+				&fakeNotifier{}, &fakeMailer{})
+
+			_, err := s.Invite(tt.inviterID, tt.subjectType, tt.subjectID, tt.username, tt.email)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Invite() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && len(repo.invitations) != 0 {
+				t.Errorf("rejected invitation was stored: %+v", repo.invitations)
+			}
+		})
+	}
+}
+
+func TestInviteByUsernameNotifiesUser(t *testing.T) {
+	notifier, mailer := &fakeNotifier{}, &fakeMailer{}
+	s := newTestInvitationService(&fakeInvitationStorage{}, newFakeEventStorage(invitationEvent()), newFakeGroupJoiner(),
+		newInvitationRSVPs(nil), notifier, mailer)
+
+	invitation, err := s.Invite(invitationOwner, models.InvitationSubjectGroup, invitationGroupID, "invitee", "")
+	if err != nil {
+		t.Fatalf("Invite() error = %v", err)
+	}
+
+	if invitation.InviteeID == nil || *invitation.InviteeID != invitationInvitee || invitation.Email != "" {
+		t.Errorf("invitation = %+v, want bound to user %d", invitation, invitationInvitee)
+	}
+	if invitation.MaxUses == nil || *invitation.MaxUses != 1 || invitation.ExpiresAt == nil {
+		t.Errorf("personal invitation is not single-use with expiry: %+v", invitation)
+	}
+	if len(notifier.notifications) != 1 || notifier.notifications[0].UserID != invitationInvitee {
+		t.Errorf("notifications = %+v, want one for the invitee", notifier.notifications)
+	}
+	if len(mailer.sent) != 0 {
+		t.Errorf("invitation by username sent mail: %+v", mailer.sent)
+	}
+}
+
+// Почта аккаунта не подтверждена, поэтому приглашение на почту не привязывается к
+// аккаунту с этим адресом, и принять его можно только по коду из письма
+func TestInviteByEmailIsBoundToCode(t *testing.T) {
+	rsvps, notifier, mailer := newInvitationRSVPs(nil), &fakeNotifier{}, &fakeMailer{}
+	s := newTestInvitationService(&fakeInvitationStorage{}, newFakeEventStorage(invitationEvent()), newFakeGroupJoiner(),
+		rsvps, notifier, mailer)
+
+	invitation, err := s.Invite(invitationOwner, models.InvitationSubjectEvent, invitationEventID, "", " Guest@Example.com ")
+	if err != nil {
+		t.Fatalf("Invite() error = %v", err)
+	}
+
+	if invitation.InviteeID != nil || invitation.Email != "guest@example.com" {
+		t.Errorf("invitation = %+v, want unbound with normalized email", invitation)
+	}
+	if len(notifier.notifications) != 0 {
+		t.Errorf("invitation by email notified: %+v", notifier.notifications)
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0].to != "guest@example.com" ||
+		!strings.Contains(mailer.sent[0].body, invitation.Token) {
+		t.Fatalf("mail = %+v, want code sent to guest@example.com", mailer.sent)
+	}
+
+	if _, err := s.AcceptInvitation(invitationInvitee, "wrong"); !errors.Is(err, storage.ErrInvitationNotFound) {
+		t.Errorf("accept with wrong code error = %v, want ErrInvitationNotFound", err)
+	}
+	if _, err := s.AcceptInvitation(invitationInvitee, invitation.Token); err != nil {
+		t.Fatalf("accept with code error = %v", err)
+	}
+	if got := rsvps.rsvps[invitationEventID]; len(got) != 1 || got[0] != invitationInvitee {
+		t.Errorf("rsvps = %v, want [%d]", got, invitationInvitee)
+	}
+	if _, err := s.AcceptInvitation(invitationOwner, invitation.Token); !errors.Is(err, storage.ErrInvitationUsedUp) {
+		t.Errorf("second accept error = %v, want ErrInvitationUsedUp", err)
+	}
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	tests := []struct {
+		name        string
+		subjectType string
+		subjectID   int
+		userID      int
+		rsvpErr     error
+		wantErr     error
+		wantJoined  bool
+		wantRSVP    bool
+	}{
+		{name: "group", subjectType: models.InvitationSubjectGroup, subjectID: invitationGroupID, userID: invitationInvitee, wantJoined: true},
+		{name: "event", subjectType: models.InvitationSubjectEvent, subjectID: invitationEventID, userID: invitationInvitee, wantRSVP: true},
+		{
+			name: "event requires a ticket", subjectType: models.InvitationSubjectEvent, subjectID: invitationEventID,
+			userID: invitationInvitee, rsvpErr: ErrTicketRequired, wantErr: ErrTicketRequired,
+		},
+		{
+			name: "addressed to another user", subjectType: models.InvitationSubjectGroup, subjectID: invitationGroupID,
+			userID: invitationOwner, wantErr: storage.ErrInvitationNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			joiner, rsvps := newFakeGroupJoiner(), newInvitationRSVPs(tt.rsvpErr)
+			s := newTestInvitationService(&fakeInvitationStorage{}, newFakeEventStorage(invitationEvent()), joiner, rsvps,
+				&fakeNotifier{}, &fakeMailer{})
+
+			invitation, err := s.Invite(invitationOwner, tt.subjectType, tt.subjectID, "invitee", "")
+			if err != nil {
+				t.Fatalf("Invite() error = %v", err)
+			}
+
+			_, err = s.AcceptInvitation(tt.userID, invitation.Token)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("AcceptInvitation() error = %v, want %v", err, tt.wantErr)
+			}
+			if joined := len(joiner.joined[invitationGroupID]) == 1; joined != tt.wantJoined {
+				t.Errorf("joined = %v, want %v", joiner.joined, tt.wantJoined)
+			}
+			if rsvped := len(rsvps.rsvps[invitationEventID]) == 1; rsvped != tt.wantRSVP {
+				t.Errorf("rsvps = %v, want %v", rsvps.rsvps, tt.wantRSVP)
+			}
+		})
+	}
+}
+
+func TestApplySignUpInvitation(t *testing.T) {
+	joiner := newFakeGroupJoiner()
+	s := newTestInvitationService(&fakeInvitationStorage{}, newFakeEventStorage(invitationEvent()), joiner,
+		newInvitationRSVPs(nil), &fakeNotifier{}, &fakeMailer{})
+
+	link, err := s.CreateInviteLink(invitationOwner, models.InvitationSubjectGroup, invitationGroupID, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateInviteLink() error = %v", err)
+	}
+
+	// без кода и с неверным кодом регистрация проходит без вступления
+	s.ApplySignUpInvitation(invitationInvitee, "")
+	s.ApplySignUpInvitation(invitationInvitee, "wrong")
+	if len(joiner.joined[invitationGroupID]) != 0 {
+		t.Fatalf("joined without a valid code: %v", joiner.joined)
+	}
+
+	s.ApplySignUpInvitation(invitationInvitee, link.Token)
+	if got := joiner.joined[invitationGroupID]; len(got) != 1 || got[0] != invitationInvitee {
+		t.Errorf("joined = %v, want [%d]", got, invitationInvitee)
+	}
+}
+
+func TestCreateInviteLinkRules(t *testing.T) {
+	zero, one := 0, 1
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		maxUses   *int
+		expiresAt *time.Time
+		wantErr   error
+	}{
+		{name: "unlimited"},
+		{name: "single use until tomorrow", maxUses: &one, expiresAt: &future},
+		{name: "no uses", maxUses: &zero, wantErr: ErrInvalidInviteLink},
+		{name: "already expired", expiresAt: &past, wantErr: ErrInvalidInviteLink},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestInvitationService(&fakeInvitationStorage{}, newFakeEventStorage(invitationEvent()), newFakeGroupJoiner(),
+				newInvitationRSVPs(nil), &fakeNotifier{}, &fakeMailer{})
+
+			link, err := s.CreateInviteLink(invitationOwner, models.InvitationSubjectEvent, invitationEventID, tt.maxUses, tt.expiresAt)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("CreateInviteLink() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (link.Personal() || link.Token == "") {
+				t.Errorf("link = %+v, want impersonal with a token", link)
+			}
+		})
+	}
+}
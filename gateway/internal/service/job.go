@@ -0,0 +1,75 @@
+package service
+
+import (
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+var ErrInvalidJobFilter = errors.New("unknown job status")
+
+// JobService даёт администраторам просматривать очередь фоновых задач
+// и перезапускать задачи, исчерпавшие попытки
+type JobService struct {
+	repo   JobStorageInt
+	users  UserStorageInt
+	logger *slog.Logger
+}
+
+func NewJobService(repo JobStorageInt, users UserStorageInt, logger *slog.Logger) *JobService {
+	return &JobService{repo: repo, users: users, logger: logger}
+}
+
+func (s *JobService) Jobs(userID int, filter models.JobFilter) ([]models.Job, error) {
+	const op = "service.JobService.Jobs"
+
+	if err := requireAdmin(s.users, userID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	switch filter.Status {
+	case "", models.JobStatusPending, models.JobStatusRunning, models.JobStatusDone, models.JobStatusDead:
+	default:
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidJobFilter)
+	}
+
+	jobs, err := s.repo.Jobs(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return jobs, nil
+}
+
+func (s *JobService) Job(userID int, id int64) (models.Job, error) {
+	const op = "service.JobService.Job"
+
+	if err := requireAdmin(s.users, userID); err != nil {
+		return models.Job{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	job, err := s.repo.Job(id)
+	if err != nil {
+		return models.Job{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return job, nil
+}
+
+// RetryJob возвращает задачу в статусе dead в очередь с новым набором попыток
+func (s *JobService) RetryJob(userID int, id int64) error {
+	const op = "service.JobService.RetryJob"
+
+	if err := requireAdmin(s.users, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.repo.RequeueJob(id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("job requeued", slog.Int64("job_id", id), slog.Int("user_id", userID))
+
+	return nil
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/storage"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// matchCandidatePool - сколько кандидатов оценивать при подборе
+	matchCandidatePool  = 200
+	maxMatchMessage     = 500
+	matchRequestsPerDay = 20
+)
+
+var (
+	ErrInvalidMatchGoal    = errors.New("unknown match goal or it is not shared by both users")
+	ErrInvalidMatchMessage = errors.New("match request message must be up to 500 characters")
+	ErrInvalidMatchFilter  = errors.New("unknown match request status")
+)
+
+// MatchService подбирает участников, с которыми стоит познакомиться: для менторства,
+// парного программирования или совместного проекта. В подборе участвуют только те,
+// кто на это согласился. Принятая заявка открывает личный диалог
+type MatchService struct {
+	repo          MatchStorageInt
+	blocks        BlockChecker
+	conversations ConversationOpener
+	notifier      Notifier
+	scorer        MatchScorer
+	logger        *slog.Logger
+}
+
+func NewMatchService(
+	repo MatchStorageInt,
+	blocks BlockChecker,
+	conversations ConversationOpener,
+	notifier Notifier,
+	scorer MatchScorer,
+	logger *slog.Logger,
+) *MatchService {
+	return &MatchService{
+		repo:          repo,
+		blocks:        blocks,
+		conversations: conversations,
+		notifier:      notifier,
+		scorer:        scorer,
+		logger:        logger,
+	}
+}
+
+// MatchGoals возвращает цели, с которыми пользователь участвует в подборе
+func (s *MatchService) MatchGoals(userID int) ([]string, error) {
+	const op = "service.MatchService.MatchGoals"
+
+	me, err := s.repo.MatchCandidate(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return me.Goals, nil
+}
+
+// SetMatchGoals включает участие в подборе с указанными целями
+func (s *MatchService) SetMatchGoals(userID int, goals []string) error {
+	const op = "service.MatchService.SetMatchGoals"
+
+	unique := make([]string, 0, len(goals))
+	for _, goal := range goals {
+		if !slices.Contains(models.MatchGoals, goal) {
+			return fmt.Errorf("%s: %w", op, ErrInvalidMatchGoal)
+		}
+		if !slices.Contains(unique, goal) {
+			unique = append(unique, goal)
+		}
+	}
+	if len(unique) == 0 {
+		return fmt.Errorf("%s: %w", op, ErrInvalidMatchGoal)
+	}
+
+	if err := s.repo.SetMatchProfile(userID, unique); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DisableMatching исключает пользователя из подбора. Отправленные заявки остаются
+func (s *MatchService) DisableMatching(userID int) error {
+	const op = "service.MatchService.DisableMatching"
+
+	if err := s.repo.DeleteMatchProfile(userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Matches возвращает лучших кандидатов для пользователя. Для каждого выбирается
+// общая цель с наибольшей оценкой; goal ограничивает подбор одной целью
+func (s *MatchService) Matches(userID int, goal string, limit int) ([]models.Match, error) {
+	const op = "service.MatchService.Matches"
+
+	if goal != "" && !slices.Contains(models.MatchGoals, goal) {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidMatchGoal)
+	}
+
+	me, err := s.repo.MatchCandidate(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	candidates, err := s.repo.MatchCandidates(userID, goal, matchCandidatePool)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	matches := make([]models.Match, 0, len(candidates))
+	for _, candidate := range candidates {
+		best := models.Match{Candidate: candidate}
+		for _, g := range me.Goals {
+			if (goal != "" && g != goal) || !slices.Contains(candidate.Goals, g) {
+				continue
+			}
+
+			score, reasons := s.scorer.Score(me, candidate, g)
+			if score > best.Score {
+				best.Goal, best.Score, best.Reasons = g, score, reasons
+			}
+		}
+		if best.Score > 0 {
+			matches = append(matches, best)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return matches, nil
+}
+
+// RequestMatch отправляет заявку на знакомство. Цель должна быть у обоих участников
+func (s *MatchService) RequestMatch(userID, recipientID int, goal, message string) (int, error) {
+	const op = "service.MatchService.RequestMatch"
+
+	message = strings.TrimSpace(message)
+	if utf8.RuneCountInString(message) > maxMatchMessage {
+		return 0, fmt.Errorf("%s: %w", op, ErrInvalidMatchMessage)
+	}
+
+	me, err := s.repo.MatchCandidate(userID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	recipient, err := s.repo.MatchCandidate(recipientID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if userID == recipientID || !slices.Contains(me.Goals, goal) || !slices.Contains(recipient.Goals, goal) {
+		return 0, fmt.Errorf("%s: %w", op, ErrInvalidMatchGoal)
+	}
+
+	blocked, err := s.blocks.BlockedBetween(userID, []int{recipientID})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if blocked {
+		return 0, fmt.Errorf("%s: %w", op, ErrUserBlocked)
+	}
+
+	sent, err := s.repo.CountMatchRequestsSince(userID, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if sent >= matchRequestsPerDay {
+		return 0, fmt.Errorf("%s: %w", op, ErrRateLimited)
+	}
+
+	id, err := s.repo.CreateMatchRequest(models.MatchRequest{
+		RequesterID: userID,
+		RecipientID: recipientID,
+		Goal:        goal,
+		Message:     message,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.notify(models.Notification{
+		UserID: recipientID,
+		Type:   models.NotificationMatchRequest,
+		Title:  fmt.Sprintf("%s хочет познакомиться", displayName(me.Profile)),
+		Body:   message,
+	})
+
+	return id, nil
+}
+
+// MatchRequests возвращает входящие или исходящие заявки, новые сначала
+func (s *MatchService) MatchRequests(userID int, filter models.MatchRequestFilter) ([]models.MatchRequest, error) {
+	const op = "service.MatchService.MatchRequests"
+
+	if filter.Status != "" && filter.Status != models.MatchRequestPending &&
+		filter.Status != models.MatchRequestAccepted && filter.Status != models.MatchRequestDeclined {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidMatchFilter)
+	}
+
+	requests, err := s.repo.MatchRequests(userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return requests, nil
+}
+
+// AcceptMatchRequest принимает заявку и открывает личный диалог с её автором
+func (s *MatchService) AcceptMatchRequest(userID, id int) (models.MatchRequest, error) {
+	const op = "service.MatchService.AcceptMatchRequest"
+
+	request, err := s.incomingRequest(userID, id)
+	if err != nil {
+		return models.MatchRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conversation, err := s.conversations.CreateConversation(userID, []int{request.RequesterID}, "")
+	if err != nil {
+		return models.MatchRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.repo.RespondMatchRequest(id, models.MatchRequestAccepted, &conversation.ID); err != nil {
+		return models.MatchRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	request.Status = models.MatchRequestAccepted
+	request.ConversationID = &conversation.ID
+
+	name := "Пользователь"
+	for _, member := range conversation.Members {
+		if member.UserID == userID {
+			name = displayName(models.Profile{Name: member.Name, Username: member.Username})
+		}
+	}
+	s.notify(models.Notification{
+		UserID: request.RequesterID,
+		Type:   models.NotificationMatchAccepted,
+		Title:  fmt.Sprintf("%s принял(а) заявку на знакомство", name),
+		Body:   "Диалог уже открыт в сообщениях",
+	})
+
+	return request, nil
+}
+
+func (s *MatchService) DeclineMatchRequest(userID, id int) error {
+	const op = "service.MatchService.DeclineMatchRequest"
+
+	if _, err := s.incomingRequest(userID, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.repo.RespondMatchRequest(id, models.MatchRequestDeclined, nil); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// incomingRequest возвращает заявку на рассмотрении, адресованную userID
+func (s *MatchService) incomingRequest(userID, id int) (models.MatchRequest, error) {
+	request, err := s.repo.MatchRequest(id)
+	if err != nil {
+		return models.MatchRequest{}, err
+	}
+
+	if request.RecipientID != userID {
+		return models.MatchRequest{}, ErrForbidden
+	}
+	if request.Status != models.MatchRequestPending {
+		return models.MatchRequest{}, storage.ErrMatchRequestNotPending
+	}
+
+	return request, nil
+}
+
+func (s *MatchService) notify(notification models.Notification) {
+	if err := s.notifier.Notify(notification); err != nil {
+		s.logger.Error("failed to send match notification", slog.String("error", err.Error()))
+	}
+}
+
+func displayName(profile models.Profile) string {
+	switch {
+	case profile.Name != "":
+		return profile.Name
+	case profile.Username != "":
+		return "@" + profile.Username
+	default:
+		return "Пользователь"
+	}
+}
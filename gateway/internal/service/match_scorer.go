@@ -0,0 +1,131 @@
+package service
+
+import (
+	"dev_meets/internal/domain/models"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var seniorityLevels = map[string]int{
+	models.SeniorityJunior: 1,
+	models.SeniorityMiddle: 2,
+	models.SenioritySenior: 3,
+	models.SeniorityLead:   4,
+}
+
+// ProfileMatchScorer - оценка совпадения по профилю: общие и дополняющие навыки,
+// разница в уровне, город и общие сообщества. Вес каждого признака зависит от цели
+type ProfileMatchScorer struct{}
+
+func NewProfileMatchScorer() *ProfileMatchScorer {
+	return &ProfileMatchScorer{}
+}
+
+// Score возвращает оценку кандидата для цели и причины, из которых она сложилась.
+// Нулевая оценка означает, что кандидат для этой цели не подходит
+func (ProfileMatchScorer) Score(user, candidate models.MatchCandidate, goal string) (float64, []string) {
+	var score float64
+	var reasons []string
+
+	shared, complementary := compareSkills(user.Skills, candidate.Skills)
+	userLevel, candidateLevel := seniorityLevels[user.Seniority], seniorityLevels[candidate.Seniority]
+
+	switch goal {
+	case models.MatchGoalMentorship:
+		// Менторство возможно только при разнице в уровне, и ментору нужны навыки ученика
+		if userLevel == 0 || candidateLevel == 0 || userLevel == candidateLevel || len(shared) == 0 {
+			return 0, nil
+		}
+
+		gap := candidateLevel - userLevel
+		if gap > 0 {
+			reasons = append(reasons, fmt.Sprintf("Может стать вашим ментором: уровень %s", candidate.Seniority))
+		} else {
+			gap = -gap
+			reasons = append(reasons, fmt.Sprintf("Ищет ментора: уровень %s", candidate.Seniority))
+		}
+		score += 2 * float64(min(gap, 2))
+		score += float64(min(len(shared), 5))
+		reasons = append(reasons, "Общие навыки: "+strings.Join(shared, ", "))
+	case models.MatchGoalPairProgramming:
+		if len(shared) == 0 {
+			return 0, nil
+		}
+
+		score += 1.5 * float64(min(len(shared), 5))
+		reasons = append(reasons, "Общие навыки: "+strings.Join(shared, ", "))
+		if userLevel != 0 && candidateLevel != 0 && abs(userLevel-candidateLevel) <= 1 {
+			score += 1.5
+			reasons = append(reasons, "Близкий уровень: "+candidate.Seniority)
+		}
+	case models.MatchGoalCofounding:
+		if len(complementary) == 0 {
+			return 0, nil
+		}
+
+		score += 0.75 * float64(min(len(complementary), 4))
+		reasons = append(reasons, "Дополняет ваши навыки: "+strings.Join(complementary[:min(len(complementary), 4)], ", "))
+		if len(shared) > 0 {
+			score += 0.5
+			reasons = append(reasons, "Общие навыки: "+strings.Join(shared, ", "))
+		}
+	default:
+		return 0, nil
+	}
+
+	if user.City != "" && strings.EqualFold(user.City, candidate.City) {
+		// Сооснователю город важнее, чем напарнику по удалённому парному программированию
+		if goal == models.MatchGoalCofounding {
+			score += 2.5
+		} else {
+			score += 1.5
+		}
+		reasons = append(reasons, "Один город: "+candidate.City)
+	}
+
+	if groups := countShared(user.GroupIDs, candidate.GroupIDs); groups > 0 {
+		score += float64(min(groups, 3))
+		reasons = append(reasons, fmt.Sprintf("Общих сообществ: %d", groups))
+	}
+
+	return score, reasons
+}
+
+// compareSkills возвращает навыки, которые есть у обоих, и навыки кандидата, которых нет у пользователя
+func compareSkills(user, candidate []string) ([]string, []string) {
+	own := make(map[string]bool, len(user))
+	for _, skill := range user {
+		own[strings.ToLower(skill)] = true
+	}
+
+	var shared, complementary []string
+	for _, skill := range candidate {
+		if own[strings.ToLower(skill)] {
+			shared = append(shared, skill)
+		} else {
+			complementary = append(complementary, skill)
+		}
+	}
+
+	return shared, complementary
+}
+
+func countShared(a, b []int) int {
+	count := 0
+	for _, id := range a {
+		if slices.Contains(b, id) {
+			count++
+		}
+	}
+
+	return count
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
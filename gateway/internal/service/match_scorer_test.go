@@ -0,0 +1,120 @@
+package service
+
+import (
+	"dev_meets/internal/domain/models"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestProfileMatchScorer(t *testing.T) {
+	person := func(seniority, city string, groupIDs []int, skills ...string) models.MatchCandidate {
+		return models.MatchCandidate{
+			Profile:  models.Profile{City: city, Skills: skills, Seniority: seniority},
+			GroupIDs: groupIDs,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		user        models.MatchCandidate
+		candidate   models.MatchCandidate
+		goal        string
+		wantScore   float64
+		wantReasons []string
+	}{
+		{
+			name:        "mentor for a junior",
+			user:        person(models.SeniorityJunior, "", nil, "Go"),
+			candidate:   person(models.SenioritySenior, "", nil, "go", "SQL"),
+			goal:        models.MatchGoalMentorship,
+			wantScore:   2*2 + 1,
+			wantReasons: []string{"Может стать вашим ментором: уровень senior", "Общие навыки: go"},
+		},
+		{
+			name:        "mentee for a lead, level gap is capped",
+			user:        person(models.SeniorityLead, "", nil, "Go", "SQL"),
+			candidate:   person(models.SeniorityJunior, "", nil, "Go", "SQL"),
+			goal:        models.MatchGoalMentorship,
+			wantScore:   2*2 + 2,
+			wantReasons: []string{"Ищет ментора: уровень junior", "Общие навыки: Go, SQL"},
+		},
+		{
+			name:      "mentorship needs a level gap",
+			user:      person(models.SeniorityMiddle, "", nil, "Go"),
+			candidate: person(models.SeniorityMiddle, "", nil, "Go"),
+			goal:      models.MatchGoalMentorship,
+		},
+		{
+			name:      "mentorship needs known levels",
+			user:      person("", "", nil, "Go"),
+			candidate: person(models.SenioritySenior, "", nil, "Go"),
+			goal:      models.MatchGoalMentorship,
+		},
+		{
+			name:      "mentorship needs shared skills",
+			user:      person(models.SeniorityJunior, "", nil, "Go"),
+			candidate: person(models.SenioritySenior, "", nil, "Java"),
+			goal:      models.MatchGoalMentorship,
+		},
+		{
+			name:      "pair programming with close level, city and groups",
+			user:      person(models.SeniorityMiddle, "Казань", []int{1, 2, 3, 4, 5}, "Go", "SQL"),
+			candidate: person(models.SenioritySenior, "казань", []int{1, 2, 3, 4}, "Go", "SQL", "Kafka"),
+			goal:      models.MatchGoalPairProgramming,
+			wantScore: 1.5*2 + 1.5 + 1.5 + 3,
+			wantReasons: []string{
+				"Общие навыки: Go, SQL", "Близкий уровень: senior", "Один город: казань", "Общих сообществ: 4",
+			},
+		},
+		{
+			name:        "pair programming with distant level",
+			user:        person(models.SeniorityJunior, "", nil, "Go"),
+			candidate:   person(models.SeniorityLead, "", nil, "Go"),
+			goal:        models.MatchGoalPairProgramming,
+			wantScore:   1.5,
+			wantReasons: []string{"Общие навыки: Go"},
+		},
+		{
+			name:      "pair programming needs shared skills",
+			user:      person(models.SeniorityMiddle, "Казань", nil, "Go"),
+			candidate: person(models.SeniorityMiddle, "Казань", nil, "Swift"),
+			goal:      models.MatchGoalPairProgramming,
+		},
+		{
+			name:      "cofounder with complementary skills in the same city",
+			user:      person("", "Казань", nil, "Go"),
+			candidate: person("", "Казань", nil, "Go", "Design", "Sales", "Marketing", "iOS", "Android"),
+			goal:      models.MatchGoalCofounding,
+			wantScore: 0.75*4 + 0.5 + 2.5,
+			wantReasons: []string{
+				"Дополняет ваши навыки: Design, Sales, Marketing, iOS", "Общие навыки: Go", "Один город: Казань",
+			},
+		},
+		{
+			name:      "cofounding needs complementary skills",
+			user:      person("", "Казань", nil, "Go", "SQL"),
+			candidate: person("", "Казань", nil, "SQL"),
+			goal:      models.MatchGoalCofounding,
+		},
+		{
+			name:      "unknown goal",
+			user:      person(models.SeniorityJunior, "Казань", nil, "Go"),
+			candidate: person(models.SenioritySenior, "Казань", nil, "Go"),
+			goal:      "hiring",
+		},
+	}
+
+	scorer := NewProfileMatchScorer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reasons := scorer.Score(tt.user, tt.candidate, tt.goal)
+			if math.Abs(score-tt.wantScore) > 1e-9 {
+				t.Errorf("score = %v, want %v", score, tt.wantScore)
+			}
+			if strings.Join(reasons, "\n") != strings.Join(tt.wantReasons, "\n") {
+				t.Errorf("reasons = %q, want %q", reasons, tt.wantReasons)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/storage"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxConversationMembers = 10
+	maxConversationTitle   = 100
+	maxMessageLength       = 4000
+	// Ограничения против спама: сообщений в минуту и новых диалогов в час
+	messagesPerMinute    = 30
+	conversationsPerHour = 20
+)
+
+var (
+	ErrInvalidConversation = errors.New("conversation must have from 1 to 9 other members and a title up to 100 characters")
+	ErrInvalidMessage      = errors.New("message must not be empty or longer than 4000 characters")
+	ErrInvalidBlock        = errors.New("cannot block yourself")
+	ErrUserBlocked         = errors.New("user is blocked")
+	ErrRateLimited         = errors.New("too many requests, try again later")
+)
+
+// MessageService - личные сообщения: диалоги двух пользователей и небольшие группы.
+// Новые сообщения и отметки о прочтении приходят участникам через real-time поток
+type MessageService struct {
+	repo      MessageStorageInt
+	publisher Publisher
+	logger    *slog.Logger
+}
+
+func NewMessageService(repo MessageStorageInt, publisher Publisher, logger *slog.Logger) *MessageService {
+	return &MessageService{repo: repo, publisher: publisher, logger: logger}
+}
+
+// CreateConversation открывает диалог с memberIDs. С одним собеседником и без названия
+// это личный диалог: повторный вызов вернёт уже существующий
+func (s *MessageService) CreateConversation(userID int, memberIDs []int, title string) (models.Conversation, error) {
+	const op = "service.MessageService.CreateConversation"
+
+	others := make([]int, 0, len(memberIDs))
+	for _, id := range memberIDs {
+		if id != userID && id > 0 && !slices.Contains(others, id) {
+			others = append(others, id)
+		}
+	}
+
+	title = strings.TrimSpace(title)
+	if len(others) == 0 || len(others) >= maxConversationMembers || utf8.RuneCountInString(title) > maxConversationTitle {
+		return models.Conversation{}, fmt.Errorf("%s: %w", op, ErrInvalidConversation)
+	}
+
+	blocked, err := s.repo.BlockedBetween(userID, others)
+	if err != nil {
+		return models.Conversation{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if blocked {
+		return models.Conversation{}, fmt.Errorf("%s: %w", op, ErrUserBlocked)
+	}
+
+	created, err := s.repo.CountConversationsSince(userID, time.Now().Add(-time.Hour))
+	if err != nil {
+		return models.Conversation{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if created >= conversationsPerHour {
+		return models.Conversation{}, fmt.Errorf("%s: %w", op, ErrRateLimited)
+	}
+
+	conversation := models.Conversation{Kind: models.ConversationGroup, Title: title, CreatedBy: userID}
+	if len(others) == 1 && title == "" {
+		conversation.Kind = models.ConversationDirect
+	}
+
+	id, err := s.repo.CreateConversation(conversation, append(others, userID))
+	if err != nil {
+		return models.Conversation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conversation, err = s.repo.Conversation(id, userID)
+	if err != nil {
+		return models.Conversation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return conversation, nil
+}
+
+func (s *MessageService) Conversation(userID, id int) (models.Conversation, error) {
+	const op = "service.MessageService.Conversation"
+
+	conversation, err := s.repo.Conversation(id, userID)
+	if err != nil {
+		return models.Conversation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return conversation, nil
+}
+
+// Conversations возвращает диалоги пользователя, недавно активные сначала
+func (s *MessageService) Conversations(userID, limit, offset int) ([]models.Conversation, error) {
+	const op = "service.MessageService.Conversations"
+
+	conversations, err := s.repo.Conversations(userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return conversations, nil
+}
+
+// Messages возвращает историю диалога, новые сообщения сначала
+func (s *MessageService) Messages(userID, conversationID int, filter models.MessageFilter) ([]models.Message, error) {
+	const op = "service.MessageService.Messages"
+
+	if _, err := s.repo.Conversation(conversationID, userID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	messages, err := s.repo.Messages(conversationID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return messages, nil
+}
+
+// SendMessage отправляет сообщение в диалог. В личный диалог нельзя писать, если
+// один из собеседников заблокировал другого; в группе сообщение не придёт в поток тем,
+// кто заблокировал отправителя
+func (s *MessageService) SendMessage(userID, conversationID int, body string) (models.Message, error) {
+	const op = "service.MessageService.SendMessage"
+
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > maxMessageLength {
+		return models.Message{}, fmt.Errorf("%s: %w", op, ErrInvalidMessage)
+	}
+
+	conversation, err := s.repo.Conversation(conversationID, userID)
+	if err != nil {
+		return models.Message{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	others := otherMembers(conversation, userID)
+	if conversation.Kind == models.ConversationDirect {
+		blocked, err := s.repo.BlockedBetween(userID, others)
+		if err != nil {
+			return models.Message{}, fmt.Errorf("%s: %w", op, err)
+		}
+		if blocked {
+			return models.Message{}, fmt.Errorf("%s: %w", op, ErrUserBlocked)
+		}
+	}
+
+	message, err := s.repo.CreateMessage(
+		models.Message{ConversationID: conversationID, SenderID: userID, Body: body},
+		messagesPerMinute, time.Now().Add(-time.Minute),
+	)
+	if err != nil {
+		if errors.Is(err, storage.ErrMessageLimit) {
+			return models.Message{}, fmt.Errorf("%s: %w", op, ErrRateLimited)
+		}
+
+		return models.Message{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.publishMessage(message, others)
+
+	return message, nil
+}
+
+// ReadConversation отмечает сообщения диалога до messageID прочитанными
+// и сообщает об этом остальным участникам
+func (s *MessageService) ReadConversation(userID, conversationID, messageID int) (int, error) {
+	const op = "service.MessageService.ReadConversation"
+
+	conversation, err := s.repo.Conversation(conversationID, userID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	lastRead, err := s.repo.MarkConversationRead(conversationID, userID, messageID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	payload := map[string]any{
+		"conversation_id":      conversationID,
+		"user_id":              userID,
+		"last_read_message_id": lastRead,
+	}
+	for _, memberID := range otherMembers(conversation, userID) {
+		s.publisher.PublishToUser(memberID, models.StreamEventMessageRead, payload)
+	}
+
+	return lastRead, nil
+}
+
+func (s *MessageService) BlockUser(userID, blockedID int) error {
+	const op = "service.MessageService.BlockUser"
+
+	if userID == blockedID {
+		return fmt.Errorf("%s: %w", op, ErrInvalidBlock)
+	}
+
+	if err := s.repo.BlockUser(userID, blockedID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *MessageService) UnblockUser(userID, blockedID int) error {
+	const op = "service.MessageService.UnblockUser"
+
+	if err := s.repo.UnblockUser(userID, blockedID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *MessageService) Blocks(userID int) ([]models.Block, error) {
+	const op = "service.MessageService.Blocks"
+
+	blocks, err := s.repo.Blocks(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return blocks, nil
+}
+
+// publishMessage отправляет сообщение в поток отправителю (для других его устройств)
+// и получателям, которые не заблокировали отправителя
+func (s *MessageService) publishMessage(message models.Message, recipients []int) {
+	blockers, err := s.repo.Blockers(message.SenderID, recipients)
+	if err != nil {
+		s.logger.Error("failed to load blockers", slog.Int("message_id", message.ID), slog.String("error", err.Error()))
+		return
+	}
+
+	payload := map[string]any{
+		"id":              message.ID,
+		"conversation_id": message.ConversationID,
+		"sender_id":       message.SenderID,
+		"body":            message.Body,
+		"created_at":      message.CreatedAt,
+	}
+	s.publisher.PublishToUser(message.SenderID, models.StreamEventMessage, payload)
+	for _, userID := range recipients {
+		if !slices.Contains(blockers, userID) {
+			s.publisher.PublishToUser(userID, models.StreamEventMessage, payload)
+		}
+	}
+}
+
+func otherMembers(conversation models.Conversation, userID int) []int {
+	others := make([]int, 0, len(conversation.Members))
+	for _, member := range conversation.Members {
+		if member.UserID != userID {
+			others = append(others, member.UserID)
+		}
+	}
+
+	return others
+}
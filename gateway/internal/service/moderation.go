@@ -0,0 +1,238 @@
+package service
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/storage"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxReportDetails  = 1000
+	maxModerationNote = 1000
+	maxSuspendDays    = 365
+	// reportsPerHour ограничивает жалобы одного пользователя, чтобы ими не заваливали очередь
+	reportsPerHour = 10
+)
+
+var (
+	ErrInvalidReport           = errors.New("invalid report target, reason or details")
+	ErrInvalidModerationAction = errors.New("invalid moderation action")
+	ErrInvalidReportFilter     = errors.New("invalid report status or target type")
+)
+
+// ModerationService - жалобы пользователей и их разбор модераторами и администраторами.
+// Каждое решение закрывает все открытые жалобы на то же содержимое и попадает в журнал модерации
+type ModerationService struct {
+	repo     ModerationStorageInt
+	users    UserStorageInt
+	notifier Notifier
+	logger   *slog.Logger
+}
+
+func NewModerationService(
+	repo ModerationStorageInt,
+	users UserStorageInt,
+	notifier Notifier,
+	logger *slog.Logger,
+) *ModerationService {
+	return &ModerationService{repo: repo, users: users, notifier: notifier, logger: logger}
+}
+
+// Report принимает жалобу на пользователя, мероприятие, комментарий или сообщение.
+// Вместе с жалобой сохраняется текущий текст содержимого
+func (s *ModerationService) Report(userID int, report models.Report) (int, error) {
+	const op = "service.ModerationService.Report"
+
+	report.Details = strings.TrimSpace(report.Details)
+	if !slices.Contains(models.ReportTargets, report.TargetType) || !slices.Contains(models.ReportReasons, report.Reason) ||
+		utf8.RuneCountInString(report.Details) > maxReportDetails {
+		return 0, fmt.Errorf("%s: %w", op, ErrInvalidReport)
+	}
+
+	target, err := s.repo.ReportTarget(userID, report.TargetType, report.TargetID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if target.UserID == userID {
+		return 0, fmt.Errorf("%s: %w", op, ErrInvalidReport)
+	}
+
+	sent, err := s.repo.CountReportsSince(userID, time.Now().Add(-time.Hour))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if sent >= reportsPerHour {
+		return 0, fmt.Errorf("%s: %w", op, ErrRateLimited)
+	}
+
+	report.ReporterID = userID
+	report.TargetUserID = target.UserID
+	report.Snapshot = target.Content
+
+	id, err := s.repo.CreateReport(report)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("report created", slog.Int("report_id", id), slog.String("target_type", report.TargetType))
+
+	return id, nil
+}
+
+// Reports возвращает очередь жалоб, по умолчанию открытых
+func (s *ModerationService) Reports(userID int, filter models.ReportFilter) ([]models.Report, error) {
+	const op = "service.ModerationService.Reports"
+
+	if err := requireModerator(s.users, userID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if filter.Status == "" {
+		filter.Status = models.ReportOpen
+	}
+	if !slices.Contains([]string{models.ReportOpen, models.ReportDismissed, models.ReportActioned}, filter.Status) ||
+		filter.TargetType != "" && !slices.Contains(models.ReportTargets, filter.TargetType) {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidReportFilter)
+	}
+
+	reports, err := s.repo.Reports(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return reports, nil
+}
+
+func (s *ModerationService) ModerationReport(userID, id int) (models.Report, error) {
+	const op = "service.ModerationService.ModerationReport"
+
+	if err := requireModerator(s.users, userID); err != nil {
+		return models.Report{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	report, err := s.repo.Report(id)
+	if err != nil {
+		return models.Report{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return report, nil
+}
+
+// ResolveReport применяет решение модератора к содержимому жалобы: dismiss отклоняет
+// жалобы, hide скрывает содержимое, warn предупреждает автора, suspend блокирует его.
+// Заблокировать модератора или администратора нельзя
+func (s *ModerationService) ResolveReport(userID, reportID int, decision models.ModerationDecision) (models.ModerationAction, error) {
+	const op = "service.ModerationService.ResolveReport"
+
+	if err := requireModerator(s.users, userID); err != nil {
+		return models.ModerationAction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	decision.Note = strings.TrimSpace(decision.Note)
+	if utf8.RuneCountInString(decision.Note) > maxModerationNote || decision.SuspendDays < 0 ||
+		decision.SuspendDays > maxSuspendDays {
+		return models.ModerationAction{}, fmt.Errorf("%s: %w", op, ErrInvalidModerationAction)
+	}
+
+	report, err := s.repo.Report(reportID)
+	if err != nil {
+		return models.ModerationAction{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if report.Status != models.ReportOpen {
+		return models.ModerationAction{}, fmt.Errorf("%s: %w", op, storage.ErrReportNotOpen)
+	}
+
+	action := models.ModerationAction{
+		ModeratorID:  &userID,
+		Action:       decision.Action,
+		TargetType:   report.TargetType,
+		TargetID:     report.TargetID,
+		TargetUserID: &report.TargetUserID,
+		Note:         decision.Note,
+	}
+
+	switch decision.Action {
+	case models.ModerationDismiss, models.ModerationWarn:
+	case models.ModerationHide:
+		if report.TargetType == models.ReportTargetUser {
+			return models.ModerationAction{}, fmt.Errorf("%s: %w", op, ErrInvalidModerationAction)
+		}
+	case models.ModerationSuspend:
+		target, err := s.users.User(report.TargetUserID)
+		if err != nil {
+			return models.ModerationAction{}, fmt.Errorf("%s: %w", op, err)
+		}
+		if target.Role != models.UserRoleUser {
+			return models.ModerationAction{}, fmt.Errorf("%s: %w", op, ErrForbidden)
+		}
+		if decision.SuspendDays > 0 {
+			until := time.Now().AddDate(0, 0, decision.SuspendDays)
+			action.SuspendedUntil = &until
+		}
+	default:
+		return models.ModerationAction{}, fmt.Errorf("%s: %w", op, ErrInvalidModerationAction)
+	}
+
+	action, err = s.repo.Moderate(action)
+	if err != nil {
+		return models.ModerationAction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("report resolved",
+		slog.Int("report_id", reportID),
+		slog.Int("moderator_id", userID),
+		slog.String("action", action.Action),
+	)
+
+	if action.Action == models.ModerationWarn || action.Action == models.ModerationSuspend {
+		s.notifyTarget(report, action)
+	}
+
+	return action, nil
+}
+
+// ModerationLog возвращает журнал модерации, новые записи сначала
+func (s *ModerationService) ModerationLog(userID int, filter models.ModerationActionFilter) ([]models.ModerationAction, error) {
+	const op = "service.ModerationService.ModerationLog"
+
+	if err := requireModerator(s.users, userID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	actions, err := s.repo.ModerationActions(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return actions, nil
+}
+
+func (s *ModerationService) notifyTarget(report models.Report, action models.ModerationAction) {
+	n := models.Notification{
+		UserID: report.TargetUserID,
+		Type:   models.NotificationModerationWarning,
+		Title:  "Предупреждение модератора",
+		Body:   "Ваше содержимое нарушает правила сообщества",
+	}
+	if action.Action == models.ModerationSuspend {
+		n.Title = "Аккаунт заблокирован"
+		n.Body = "Аккаунт заблокирован бессрочно за нарушение правил сообщества"
+		if action.SuspendedUntil != nil {
+			n.Body = fmt.Sprintf("Аккаунт заблокирован до %s за нарушение правил сообщества",
+				action.SuspendedUntil.Format("02.01.2006 15:04"))
+		}
+	}
+	if action.Note != "" {
+		n.Body += ": " + action.Note
+	}
+
+	if err := s.notifier.Notify(n); err != nil {
+		s.logger.Error("failed to send notification", slog.String("error", err.Error()))
+	}
+}
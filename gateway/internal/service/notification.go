@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// digestBatchSize - скольким пользователям за раз собирать сводки
+const digestBatchSize = 100
+
+var ErrInvalidNotificationPreference = errors.New("unknown notification type or channel")
+
+// NotificationService хранит уведомления пользователей и доставляет их по выбранным
+// каналам. Другие сервисы публикуют уведомления через интерфейс Notifier
+type NotificationService struct {
+	repo      NotificationStorageInt
+	users     UserStorageInt
+	mailer    Mailer
+	publisher Publisher
+	logger    *slog.Logger
+}
+
+func NewNotificationService(
+	repo NotificationStorageInt,
+	users UserStorageInt,
+	mailer Mailer,
+	publisher Publisher,
+	logger *slog.Logger,
+) *NotificationService {
+	return &NotificationService{repo: repo, users: users, mailer: mailer, publisher: publisher, logger: logger}
+}
+
+// Notify сохраняет уведомление в центре уведомлений с учётом настроек пользователя
+// и сразу отправляет его в открытые подключения пользователя. Уведомления с каналом
+// email дополнительно попадают в ближайшую email-сводку
+func (s *NotificationService) Notify(notification models.Notification) error {
+	const op = "service.NotificationService.Notify"
+
+	channel, err := s.repo.NotificationChannel(notification.UserID, notification.Type)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if channel == models.NotificationChannelNone {
+		return nil
+	}
+
+	id, err := s.repo.CreateNotification(notification, channel == models.NotificationChannelEmail)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.publisher.PublishToUser(notification.UserID, models.StreamEventNotification, map[string]any{
+		"id":    id,
+		"type":  notification.Type,
+		"title": notification.Title,
+		"body":  notification.Body,
+	})
+
+	return nil
+}
+
+// Notifications возвращает страницу уведомлений пользователя и число непрочитанных
+func (s *NotificationService) Notifications(userID int, filter models.NotificationFilter) ([]models.Notification, int, error) {
+	const op = "service.NotificationService.Notifications"
+
+	notifications, err := s.repo.Notifications(userID, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	unread, err := s.repo.UnreadCount(userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return notifications, unread, nil
+}
+
+func (s *NotificationService) MarkRead(userID, id int) error {
+	const op = "service.NotificationService.MarkRead"
+
+	if err := s.repo.MarkRead(userID, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *NotificationService) MarkAllRead(userID int) (int, error) {
+	const op = "service.NotificationService.MarkAllRead"
+
+	count, err := s.repo.MarkAllRead(userID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+// NotificationPreferences возвращает каналы доставки по всем типам уведомлений,
+// для невыбранных - канал по умолчанию
+func (s *NotificationService) NotificationPreferences(userID int) ([]models.NotificationPreference, error) {
+	const op = "service.NotificationService.NotificationPreferences"
+
+	saved, err := s.repo.NotificationPreferences(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	channels := make(map[string]string, len(saved))
+	for _, preference := range saved {
+		channels[preference.Type] = preference.Channel
+	}
+
+	preferences := make([]models.NotificationPreference, 0, len(models.NotificationTypes))
+	for _, notificationType := range models.NotificationTypes {
+		channel, ok := channels[notificationType]
+		if !ok {
+			channel = models.NotificationChannelInApp
+		}
+		preferences = append(preferences, models.NotificationPreference{Type: notificationType, Channel: channel})
+	}
+
+	return preferences, nil
+}
+
+func (s *NotificationService) SetNotificationPreferences(userID int, preferences []models.NotificationPreference) error {
+	const op = "service.NotificationService.SetNotificationPreferences"
+
+	for _, preference := range preferences {
+		if !slices.Contains(models.NotificationTypes, preference.Type) {
+			return fmt.Errorf("%s: %w", op, ErrInvalidNotificationPreference)
+		}
+
+		switch preference.Channel {
+		case models.NotificationChannelInApp, models.NotificationChannelEmail, models.NotificationChannelNone:
+		default:
+			return fmt.Errorf("%s: %w", op, ErrInvalidNotificationPreference)
+		}
+	}
+
+	if err := s.repo.SetNotificationPreferences(userID, preferences); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RunDigests рассылает email-сводки раз в interval, пока не отменён ctx
+func (s *NotificationService) RunDigests(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.SendDigests(); err != nil {
+				s.logger.Error("failed to send notification digests", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+// SendDigests отправляет каждому пользователю одно письмо со всеми накопившимися
+// уведомлениями вместо отдельного письма на каждое. Уведомления забираются из очереди
+// до отправки, поэтому с нескольких реплик сводка не уходит дважды; если письмо
+// отправить не удалось, неотправленные уведомления возвращаются в очередь
+func (s *NotificationService) SendDigests() error {
+	const op = "service.NotificationService.SendDigests"
+
+	for {
+		pending, err := s.repo.ClaimEmailNotifications(digestBatchSize)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+
+		for start := 0; start < len(pending); {
+			end := start
+			for end < len(pending) && pending[end].UserID == pending[start].UserID {
+				end++
+			}
+
+			if err := s.sendDigest(pending[start:end]); err != nil {
+				s.releaseDigests(pending[start:])
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			start = end
+		}
+	}
+}
+
+func (s *NotificationService) releaseDigests(notifications []models.Notification) {
+	ids := make([]int, 0, len(notifications))
+	for _, n := range notifications {
+		ids = append(ids, n.ID)
+	}
+
+	if err := s.repo.ReleaseEmailNotifications(ids); err != nil {
+		s.logger.Error("failed to release notification digests", slog.String("error", err.Error()))
+	}
+}
+
+// sendDigest отправляет сводку уведомлений одного пользователя
+func (s *NotificationService) sendDigest(notifications []models.Notification) error {
+	var body strings.Builder
+	for _, n := range notifications {
+		body.WriteString("• " + n.Title)
+		if n.Count > 1 {
+			body.WriteString(fmt.Sprintf(" (%d)", n.Count))
+		}
+		if n.Body != "" {
+			body.WriteString("\n  " + n.Body)
+		}
+		body.WriteString("\n")
+	}
+
+	user, err := s.users.User(notifications[0].UserID)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("Новые уведомления: %d", len(notifications))
+	return s.mailer.Send(user.Email, subject, body.String())
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"dev_meets/internal/jobs"
+	"dev_meets/pkg/email"
+	"log/slog"
+)
+
+const jobSendEmail = "email.send"
+
+// NewMailer возвращает отправку через SMTP-сервер из конфигурации. Без сервера письма
+// только пишутся в лог - так можно работать лишь локально, конфигурация других окружений
+// без сервера не загружается
+func NewMailer(config email.Config, logger *slog.Logger) Mailer {
+	if config.Host == "" {
+		logger.Warn("smtp server is not configured, emails are written to the log")
+		return NewLogMailer(logger)
+	}
+
+	return email.NewSMTP(config)
+}
+
+// LogMailer пишет письма в лог вместо отправки. Тело письма не логируется: в нём бывают
+// коды подтверждения и ссылки-приглашения
+type LogMailer struct {
+	logger *slog.Logger
+}
+
+func NewLogMailer(logger *slog.Logger) *LogMailer {
+	return &LogMailer{logger: logger}
+}
+
+func (m *LogMailer) Send(to, subject, body string) error {
+	m.logger.Info("email",
+		slog.String("to", to),
+		slog.String("subject", subject),
+		slog.Int("body_length", len(body)),
+	)
+
+	return nil
+}
+
+type emailJob struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// QueuedMailer ставит письма в очередь фоновых задач, а отправляет их mailer.
+// Недоступность почтового сервера не задерживает запрос, письмо отправится повторно
+type QueuedMailer struct {
+	queue *jobs.Queue
+}
+
+func NewQueuedMailer(queue *jobs.Queue, mailer Mailer) *QueuedMailer {
+	jobs.Handle(queue, jobSendEmail, func(ctx context.Context, job emailJob) error {
+		if err := mailer.Send(job.To, job.Subject, job.Body); err != nil {
+			if email.IsPermanent(err) {
+				return jobs.Permanent(err)
+			}
+
+			return err
+		}
+
+		return nil
+	})
+
+	return &QueuedMailer{queue: queue}
+}
+
+func (m *QueuedMailer) Send(to, subject, body string) error {
+	_, err := m.queue.Enqueue(jobSendEmail, emailJob{To: to, Subject: subject, Body: body})
+
+	return err
+}
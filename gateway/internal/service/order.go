@@ -0,0 +1,430 @@
+package service
+
+import (
+	"context"
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/jobs"
+	"dev_meets/internal/storage"
+	"dev_meets/pkg/payment"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"time"
+)
+
+const jobExpireOrder = "order.expire"
+
+var (
+	ErrInvalidTicketType = errors.New("invalid ticket type")
+	ErrTicketSalesClosed = errors.New("ticket sales are closed")
+	ErrTicketRequired    = errors.New("event requires a paid ticket")
+)
+
+var currencyRe = regexp.MustCompile(`^[A-Z]{3}$`)
+
+type PaymentConfig struct {
+	// Provider - acquirer или fake. Фейковый провайдер допускается только локально,
+	// это проверяет загрузка конфигурации
+	Provider string
+	// HoldTTL - сколько неоплаченный заказ удерживает билет
+	HoldTTL time.Duration
+	// FakeAutoConfirm - фейковый провайдер считает платёж оплаченным сразу, без вебхука
+	FakeAutoConfirm bool
+	Acquirer        payment.AcquirerConfig
+}
+
+// NewPaymentProvider создаёт провайдера, выбранного в конфигурации. Эквайринг без
+// адреса API, магазина или ключей не создаётся: заказы нельзя было бы ни оплатить, ни
+// подтвердить вебхуком
+func NewPaymentProvider(config PaymentConfig) (PaymentProvider, error) {
+	switch config.Provider {
+	case payment.FakeName:
+		return payment.NewFake(config.FakeAutoConfirm), nil
+	case payment.AcquirerName:
+		acquirer := config.Acquirer
+		if acquirer.APIURL == "" || acquirer.ShopID == "" || acquirer.SecretKey == "" || acquirer.WebhookSecret == "" {
+			return nil, errors.New("acquirer api url, shop id, secret key and webhook secret are required")
+		}
+
+		return payment.NewAcquirer(acquirer), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", config.Provider)
+	}
+}
+
+// OrderService продаёт билеты на мероприятия. Организатор заводит типы билетов,
+// покупатель оформляет заказ, который удерживает билет до оплаты или до истечения
+// HoldTTL. Оплаченный заказ записывает покупателя на мероприятие, возврат отменяет запись
+type OrderService struct {
+	repo        OrderStorageInt
+	events      EventStorageInt
+	invitations InvitationChecker
+	rsvps       RSVPEvents
+	provider    PaymentProvider
+	queue       *jobs.Queue
+	audit       Auditor
+	config      PaymentConfig
+	logger      *slog.Logger
+}
+
+type orderJob struct {
+	OrderID int `json:"order_id"`
+}
+
+func NewOrderService(
+	repo OrderStorageInt,
+	events EventStorageInt,
+	invitations InvitationChecker,
+	rsvps RSVPEvents,
+	provider PaymentProvider,
+	queue *jobs.Queue,
+	audit Auditor,
+	config PaymentConfig,
+	logger *slog.Logger,
+) *OrderService {
+	s := &OrderService{
+		repo:        repo,
+		events:      events,
+		invitations: invitations,
+		rsvps:       rsvps,
+		provider:    provider,
+		queue:       queue,
+		audit:       audit,
+		config:      config,
+		logger:      logger,
+	}
+	jobs.Handle(queue, jobExpireOrder, s.expireOrder)
+
+	return s
+}
+
+// CreateTicketType заводит тип билета. Заводить билеты может только организатор
+// мероприятия, пока оно не прошло и не отменено
+func (s *OrderService) CreateTicketType(userID int, ticketType models.TicketType) (int, error) {
+	const op = "service.OrderService.CreateTicketType"
+
+	event, err := organizedEvent(s.events, userID, ticketType.EventID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if time.Now().After(event.EndsAt) {
+		return 0, fmt.Errorf("%s: %w", op, ErrEventFinished)
+	}
+	if event.CancelledAt != nil {
+		return 0, fmt.Errorf("%s: %w", op, storage.ErrEventCancelled)
+	}
+
+	if err := validateTicketType(ticketType, event); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := s.repo.CreateTicketType(ticketType)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// validateTicketType проверяет цену и сроки продаж: бесплатный билет стоит 0, остальные
+// продаются за деньги в валюте ISO 4217, а ранние билеты - только до начала мероприятия
+func validateTicketType(ticketType models.TicketType, event models.Event) error {
+	if !slices.Contains(models.TicketKinds, ticketType.Kind) {
+		return ErrInvalidTicketType
+	}
+
+	if ticketType.Kind == models.TicketKindFree {
+		if ticketType.Price != 0 {
+			return ErrInvalidTicketType
+		}
+	} else if ticketType.Price <= 0 || !currencyRe.MatchString(ticketType.Currency) {
+		return ErrInvalidTicketType
+	}
+
+	if ticketType.Kind == models.TicketKindEarlyBird &&
+		(ticketType.SalesEndAt == nil || !ticketType.SalesEndAt.Before(event.StartsAt)) {
+		return ErrInvalidTicketType
+	}
+
+	return nil
+}
+
+func (s *OrderService) TicketTypes(eventID int) ([]models.TicketType, error) {
+	const op = "service.OrderService.TicketTypes"
+
+	if _, err := s.events.Event(eventID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ticketTypes, err := s.repo.EventTicketTypes(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ticketTypes, nil
+}
+
+// CreateOrder оформляет заказ билета и создаёт платёж у провайдера. Бесплатный заказ
+// оплачивается сразу. Если платёж не удалось создать, билет освобождается
+func (s *OrderService) CreateOrder(ctx context.Context, userID, eventID, ticketTypeID int) (models.Order, error) {
+	const op = "service.OrderService.CreateOrder"
+
+	ticketType, err := s.repo.TicketType(ticketTypeID)
+	if err != nil {
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if ticketType.EventID != eventID {
+		return models.Order{}, fmt.Errorf("%s: %w", op, storage.ErrTicketTypeNotFound)
+	}
+
+	event, err := s.events.Event(ticketType.EventID)
+	if err != nil {
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	if now.After(event.EndsAt) {
+		return models.Order{}, fmt.Errorf("%s: %w", op, ErrEventFinished)
+	}
+	if event.CancelledAt != nil {
+		return models.Order{}, fmt.Errorf("%s: %w", op, storage.ErrEventCancelled)
+	}
+	if !ticketType.OnSale(now) {
+		return models.Order{}, fmt.Errorf("%s: %w", op, ErrTicketSalesClosed)
+	}
+	if event.Private && event.OrganizerID != userID {
+		invited, err := s.invitations.HasInvitation(models.InvitationSubjectEvent, event.ID, userID)
+		if err != nil {
+			return models.Order{}, fmt.Errorf("%s: %w", op, err)
+		}
+		if !invited {
+			return models.Order{}, fmt.Errorf("%s: %w", op, ErrInvitationRequired)
+		}
+	}
+
+	order, err := s.repo.CreateOrder(models.Order{
+		EventID:      event.ID,
+		TicketTypeID: ticketType.ID,
+		UserID:       userID,
+		Amount:       ticketType.Price,
+		Currency:     ticketType.Currency,
+		ExpiresAt:    now.Add(s.config.HoldTTL),
+	})
+	if err != nil {
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if order.Amount == 0 {
+		order, err = s.pay(order.ID)
+		if err != nil {
+			return models.Order{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return order, nil
+	}
+
+	p, err := s.provider.CreatePayment(ctx, payment.Request{
+		OrderID:     order.ID,
+		Amount:      order.Amount,
+		Currency:    order.Currency,
+		Description: fmt.Sprintf("%s: %s", event.Title, ticketType.Name),
+	})
+	if err != nil {
+		if err := s.repo.CancelOrder(order.ID); err != nil {
+			s.logger.Error("failed to release order", slog.Int("order_id", order.ID), slog.String("error", err.Error()))
+		}
+
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.repo.SetOrderPayment(order.ID, s.provider.Name(), p.ID, p.ConfirmationURL); err != nil {
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+	order.Provider, order.PaymentID, order.PaymentURL = s.provider.Name(), p.ID, p.ConfirmationURL
+
+	if p.Status == payment.StatusSucceeded {
+		order, err = s.pay(order.ID)
+		if err != nil {
+			return models.Order{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return order, nil
+	}
+
+	if _, err := s.queue.EnqueueAt(jobExpireOrder, orderJob{OrderID: order.ID}, order.ExpiresAt); err != nil {
+		s.logger.Error("failed to schedule order expiry", slog.Int("order_id", order.ID), slog.String("error", err.Error()))
+	}
+
+	return order, nil
+}
+
+// Order возвращает заказ покупателю или организатору мероприятия
+func (s *OrderService) Order(userID, id int) (models.Order, error) {
+	const op = "service.OrderService.Order"
+
+	order, err := s.repo.Order(id)
+	if err != nil {
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if order.UserID != userID {
+		if _, err := organizedEvent(s.events, userID, order.EventID); err != nil {
+			return models.Order{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return order, nil
+}
+
+func (s *OrderService) UserOrders(userID, limit, offset int) ([]models.Order, error) {
+	const op = "service.OrderService.UserOrders"
+
+	orders, err := s.repo.UserOrders(userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return orders, nil
+}
+
+// EventOrders возвращает заказы на мероприятие. Смотреть их может только организатор
+func (s *OrderService) EventOrders(userID, eventID, limit, offset int) ([]models.Order, error) {
+	const op = "service.OrderService.EventOrders"
+
+	if _, err := organizedEvent(s.events, userID, eventID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	orders, err := s.repo.EventOrders(eventID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return orders, nil
+}
+
+// RefundOrder возвращает покупателю деньги за оплаченный заказ и отменяет его запись
+// на мероприятие. Оформить возврат может только организатор
+func (s *OrderService) RefundOrder(ctx context.Context, userID, id int) (models.Order, error) {
+	const op = "service.OrderService.RefundOrder"
+
+	order, err := s.repo.Order(id)
+	if err != nil {
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err := organizedEvent(s.events, userID, order.EventID); err != nil {
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if order.Status != models.OrderStatusPaid {
+		return models.Order{}, fmt.Errorf("%s: %w", op, storage.ErrOrderNotPaid)
+	}
+
+	if order.Amount > 0 {
+		if err := s.provider.Refund(ctx, order.PaymentID, order.Amount, order.Currency); err != nil {
+			return models.Order{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	order, err = s.refund(ctx, userID, order)
+	if err != nil {
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return order, nil
+}
+
+// HandlePaymentWebhook применяет извещение провайдера об исходе платежа. Извещения
+// могут повторяться, поэтому уже применённые ничего не меняют. Если оплата пришла,
+// когда билет уже не удержать, деньги возвращаются покупателю
+func (s *OrderService) HandlePaymentWebhook(ctx context.Context, header http.Header, body []byte) error {
+	const op = "service.OrderService.HandlePaymentWebhook"
+
+	event, err := s.provider.ParseWebhook(header, body)
+	if err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			return fmt.Errorf("%s: %w", op, ErrForbidden)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	order, err := s.repo.OrderByPayment(s.provider.Name(), event.PaymentID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	switch event.Status {
+	case payment.StatusSucceeded:
+		_, err := s.pay(order.ID)
+		if errors.Is(err, storage.ErrTicketsSoldOut) || errors.Is(err, storage.ErrOrderNotPending) ||
+			errors.Is(err, storage.ErrOrderExists) {
+			s.logger.Warn("payment for unavailable order, refunding",
+				slog.Int("order_id", order.ID), slog.String("reason", err.Error()))
+			if err := s.provider.Refund(ctx, order.PaymentID, order.Amount, order.Currency); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	case payment.StatusCanceled:
+		if err := s.repo.CancelOrder(order.ID); err != nil && !errors.Is(err, storage.ErrOrderNotPending) {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	case payment.StatusRefunded:
+		if _, err := s.refund(ctx, 0, order); err != nil && !errors.Is(err, storage.ErrOrderNotPaid) {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *OrderService) refund(ctx context.Context, actorID int, order models.Order) (models.Order, error) {
+	audit := newAuditEvent(ctx, actorID, models.AuditOrderRefunded, models.AuditTargetOrder, order.ID,
+		map[string]string{"status": order.Status}, map[string]string{"status": models.OrderStatusRefunded})
+	order, err := s.repo.RefundOrder(order.ID, audit)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	s.logger.Info("order refunded", slog.Int("order_id", order.ID), slog.Int("event_id", order.EventID))
+	s.rsvps.RSVPCancelled(order.EventID)
+
+	return order, nil
+}
+
+// pay оплачивает заказ и, если оплата новая, сообщает о записи покупателя на мероприятие
+func (s *OrderService) pay(id int) (models.Order, error) {
+	order, rsvp, err := s.repo.PayOrder(id)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	if rsvp != nil {
+		s.logger.Info("order paid", slog.Int("order_id", order.ID), slog.Int("event_id", order.EventID))
+		s.rsvps.RSVPCreated(*rsvp)
+	}
+
+	return order, nil
+}
+
+// expireOrder снимает удержание с заказа, который так и не оплатили
+func (s *OrderService) expireOrder(ctx context.Context, job orderJob) error {
+	if err := s.repo.ExpireOrder(job.OrderID); err != nil {
+		if errors.Is(err, storage.ErrOrderNotPending) {
+			return nil
+		}
+
+		return err
+	}
+
+	s.logger.Info("order expired", slog.Int("order_id", job.OrderID))
+
+	return nil
+}
@@ -0,0 +1,653 @@
+package service
+
+import (
+	"context"
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/jobs"
+	"dev_meets/internal/storage"
+	"dev_meets/pkg/payment"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeOrderStorage повторяет правила OrderPostgres в памяти: заказ удерживает билет,
+// у пользователя один действующий заказ на мероприятие, оплата записывает на мероприятие
+type fakeOrderStorage struct {
+	OrderStorageInt
+
+	mu          sync.Mutex
+	ticketTypes map[int]*models.TicketType
+	orders      map[int]*models.Order
+	rsvps       map[[2]int]models.RSVP
+	nextID      int
+}
+
+func newFakeOrderStorage(ticketTypes ...models.TicketType) *fakeOrderStorage {
+	s := &fakeOrderStorage{
+		ticketTypes: make(map[int]*models.TicketType),
+		orders:      make(map[int]*models.Order),
+		rsvps:       make(map[[2]int]models.RSVP),
+	}
+	for _, ticketType := range ticketTypes {
+		ticketType := ticketType
+		s.ticketTypes[ticketType.ID] = &ticketType
+	}
+
+	return s
+}
+
+func (s *fakeOrderStorage) TicketType(id int) (models.TicketType, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ticketType, ok := s.ticketTypes[id]
+	if !ok {
+		return models.TicketType{}, storage.ErrTicketTypeNotFound
+	}
+
+	return *ticketType, nil
+}
+
+func (s *fakeOrderStorage) reserve(ticketTypeID int) error {
+	ticketType := s.ticketTypes[ticketTypeID]
+	if ticketType.Reserved >= ticketType.Quantity {
+		return storage.ErrTicketsSoldOut
+	}
+	ticketType.Reserved++
+
+	return nil
+}
+
+func (s *fakeOrderStorage) activeOrder(eventID, userID, exceptID int) bool {
+	for _, order := range s.orders {
+		if order.ID != exceptID && order.EventID == eventID && order.UserID == userID &&
+			(order.Status == models.OrderStatusPending || order.Status == models.OrderStatusPaid) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *fakeOrderStorage) CreateOrder(order models.Order) (models.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.activeOrder(order.EventID, order.UserID, 0) {
+		return models.Order{}, storage.ErrOrderExists
+	}
+	if err := s.reserve(order.TicketTypeID); err != nil {
+		return models.Order{}, err
+	}
+
+	s.nextID++
+	order.ID = s.nextID
+	order.Status = models.OrderStatusPending
+	order.CreatedAt = time.Now()
+	s.orders[order.ID] = &order
+
+	return order, nil
+}
+
+func (s *fakeOrderStorage) SetOrderPayment(id int, provider, paymentID, paymentURL string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order := s.orders[id]
+	order.Provider, order.PaymentID, order.PaymentURL = provider, paymentID, paymentURL
+
+	return nil
+}
+
+func (s *fakeOrderStorage) Order(id int) (models.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, ok := s.orders[id]
+	if !ok {
+		return models.Order{}, storage.ErrOrderNotFound
+	}
+
+	return *order, nil
+}
+
+func (s *fakeOrderStorage) OrderByPayment(provider, paymentID string) (models.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, order := range s.orders {
+		if order.Provider == provider && order.PaymentID == paymentID {
+			return *order, nil
+		}
+	}
+
+	return models.Order{}, storage.ErrOrderNotFound
+}
+
+func (s *fakeOrderStorage) PayOrder(id int) (models.Order, *models.RSVP, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, ok := s.orders[id]
+	if !ok {
+		return models.Order{}, nil, storage.ErrOrderNotFound
+	}
+
+	switch order.Status {
+	case models.OrderStatusPaid:
+		return *order, nil, nil
+	case models.OrderStatusRefunded:
+		return models.Order{}, nil, storage.ErrOrderNotPending
+	case models.OrderStatusExpired:
+		if s.activeOrder(order.EventID, order.UserID, order.ID) {
+			return models.Order{}, nil, storage.ErrOrderExists
+		}
+		if err := s.reserve(order.TicketTypeID); err != nil {
+			return models.Order{}, nil, err
+		}
+	}
+
+	now := time.Now()
+	order.Status, order.PaidAt = models.OrderStatusPaid, &now
+
+	rsvp := models.RSVP{
+		ID:        len(s.rsvps) + 1,
+		EventID:   order.EventID,
+		UserID:    order.UserID,
+		Status:    models.RSVPStatusGoing,
+		CreatedAt: now,
+	}
+	s.rsvps[[2]int{order.EventID, order.UserID}] = rsvp
+
+	return *order, &rsvp, nil
+}
+
+func (s *fakeOrderStorage) release(id int, onlyOverdue bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order := s.orders[id]
+	if order.Status != models.OrderStatusPending || (onlyOverdue && order.ExpiresAt.After(time.Now())) {
+		return storage.ErrOrderNotPending
+	}
+	order.Status = models.OrderStatusExpired
+	s.ticketTypes[order.TicketTypeID].Reserved--
+
+	return nil
+}
+
+func (s *fakeOrderStorage) ExpireOrder(id int) error {
+	return s.release(id, true)
+}
+
+func (s *fakeOrderStorage) CancelOrder(id int) error {
+	return s.release(id, false)
+}
+
+func (s *fakeOrderStorage) RefundOrder(id int, audit models.AuditEvent) (models.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order := s.orders[id]
+	if order.Status != models.OrderStatusPaid {
+		return models.Order{}, storage.ErrOrderNotPaid
+	}
+	now := time.Now()
+	order.Status, order.RefundedAt = models.OrderStatusRefunded, &now
+	s.ticketTypes[order.TicketTypeID].Reserved--
+
+	key := [2]int{order.EventID, order.UserID}
+	rsvp := s.rsvps[key]
+	rsvp.Status = models.RSVPStatusCancelled
+	s.rsvps[key] = rsvp
+
+	return *order, nil
+}
+
+func (s *fakeOrderStorage) TicketRequired(eventID, userID int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sold := false
+	for _, ticketType := range s.ticketTypes {
+		sold = sold || ticketType.EventID == eventID
+	}
+	for _, order := range s.orders {
+		if order.EventID == eventID && order.UserID == userID && order.Status == models.OrderStatusPaid {
+			return false, nil
+		}
+	}
+
+	return sold, nil
+}
+
+func (s *fakeOrderStorage) reserved(ticketTypeID int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ticketTypes[ticketTypeID].Reserved
+}
+
+// expire переносит срок удержания заказа в прошлое
+func (s *fakeOrderStorage) expire(id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.orders[id].ExpiresAt = time.Now().Add(-time.Minute)
+}
+
+// fakeRSVPEvents запоминает, о каких записях сообщил OrderService
+type fakeRSVPEvents struct {
+	created   []models.RSVP
+	cancelled []int
+}
+
+func (f *fakeRSVPEvents) RSVPCreated(rsvp models.RSVP) {
+	f.created = append(f.created, rsvp)
+}
+
+func (f *fakeRSVPEvents) RSVPCancelled(eventID int) {
+	f.cancelled = append(f.cancelled, eventID)
+}
+
+// fakeJobStorage запоминает поставленные в очередь задачи, не выполняя их
+type fakeJobStorage struct {
+	jobs.Storage
+	jobs []models.Job
+}
+
+func (s *fakeJobStorage) CreateJob(job models.Job) (int64, error) {
+	s.jobs = append(s.jobs, job)
+
+	return int64(len(s.jobs)), nil
+}
+
+const (
+	orderOrganizer = 1
+	orderBuyer     = 2
+	orderOther     = 3
+	orderEventID   = 10
+)
+
+func orderEvent() models.Event {
+	now := time.Now()
+
+	return models.Event{
+		ID:          orderEventID,
+		OrganizerID: orderOrganizer,
+		Title:       "GoConf",
+		StartsAt:    now.Add(24 * time.Hour),
+		EndsAt:      now.Add(26 * time.Hour),
+	}
+}
+
+// orderTicketTypes - платный (id 1) и бесплатный (id 2) билеты на orderEventID по quantity штук
+func orderTicketTypes(quantity int) []models.TicketType {
+	return []models.TicketType{
+		{ID: 1, EventID: orderEventID, Kind: models.TicketKindPaid, Name: "Стандарт", Price: 150000, Currency: "RUB", Quantity: quantity},
+		{ID: 2, EventID: orderEventID, Kind: models.TicketKindFree, Name: "Студенческий", Quantity: quantity},
+	}
+}
+
+func newTestOrderService(
+	repo *fakeOrderStorage,
+	events *fakeEventStorage,
+	invitations fakeInvitations,
+	rsvps *fakeRSVPEvents,
+	provider *payment.Fake,
+	jobRepo *fakeJobStorage,
+) *OrderService {
+	queue := jobs.NewQueue(jobRepo, jobs.Config{Concurrency: 1}, testLogger())
+
+	return NewOrderService(repo, events, invitations, rsvps, provider, queue, nil,
+		PaymentConfig{Provider: payment.FakeName, HoldTTL: 15 * time.Minute}, testLogger())
+}
+
+// paymentWebhook присылает извещение фейкового провайдера о платеже
+func paymentWebhook(s *OrderService, paymentID, status string) error {
+	body := fmt.Sprintf(`{"payment_id": %q, "status": %q}`, paymentID, status)
+	return s.HandlePaymentWebhook(context.Background(), http.Header{}, []byte(body))
+}
+
+func TestOrderLifecycle(t *testing.T) {
+	ctx := context.Background()
+	repo, rsvps, jobRepo := newFakeOrderStorage(orderTicketTypes(10)...), &fakeRSVPEvents{}, &fakeJobStorage{}
+	provider := payment.NewFake(false)
+	s := newTestOrderService(repo, newFakeEventStorage(orderEvent()), nil, rsvps, provider, jobRepo)
+
+	order, err := s.CreateOrder(ctx, orderBuyer, orderEventID, 1)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+	if order.Status != models.OrderStatusPending || order.PaymentID == "" || order.PaymentURL == "" {
+		t.Fatalf("new order = %+v, want pending with payment", order)
+	}
+	if got := repo.reserved(1); got != 1 {
+		t.Errorf("reserved = %d, want 1", got)
+	}
+	if len(jobRepo.jobs) != 1 || jobRepo.jobs[0].Type != jobExpireOrder || !jobRepo.jobs[0].RunAt.Equal(order.ExpiresAt) {
+		t.Errorf("jobs = %+v, want expiry at %v", jobRepo.jobs, order.ExpiresAt)
+	}
+	if required, _ := repo.TicketRequired(orderEventID, orderBuyer); !required {
+		t.Error("unpaid order lets the buyer rsvp")
+	}
+
+	if err := paymentWebhook(s, order.PaymentID, payment.StatusSucceeded); err != nil {
+		t.Fatalf("payment webhook error = %v", err)
+	}
+	// провайдер повторяет извещения, повтор ничего не меняет
+	if err := paymentWebhook(s, order.PaymentID, payment.StatusSucceeded); err != nil {
+		t.Fatalf("repeated payment webhook error = %v", err)
+	}
+
+	order, _ = repo.Order(order.ID)
+	if order.Status != models.OrderStatusPaid {
+		t.Fatalf("status after payment = %q, want paid", order.Status)
+	}
+	if len(rsvps.created) != 1 || rsvps.created[0].UserID != orderBuyer {
+		t.Errorf("rsvps created = %+v, want one for the buyer", rsvps.created)
+	}
+	if required, _ := repo.TicketRequired(orderEventID, orderBuyer); required {
+		t.Error("paid order does not let the buyer rsvp")
+	}
+
+	if _, err := s.RefundOrder(ctx, orderBuyer, order.ID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("refund by buyer error = %v, want ErrForbidden", err)
+	}
+
+	order, err = s.RefundOrder(ctx, orderOrganizer, order.ID)
+	if err != nil {
+		t.Fatalf("RefundOrder() error = %v", err)
+	}
+	if order.Status != models.OrderStatusRefunded {
+		t.Errorf("status after refund = %q, want refunded", order.Status)
+	}
+	if got := repo.reserved(1); got != 0 {
+		t.Errorf("reserved after refund = %d, want 0", got)
+	}
+	if len(rsvps.cancelled) != 1 {
+		t.Errorf("rsvps cancelled = %v, want one", rsvps.cancelled)
+	}
+	// деньги уже возвращены провайдером
+	if err := provider.Refund(ctx, order.PaymentID, order.Amount, order.Currency); err == nil {
+		t.Error("payment is refundable twice")
+	}
+	if _, err := s.RefundOrder(ctx, orderOrganizer, order.ID); !errors.Is(err, storage.ErrOrderNotPaid) {
+		t.Errorf("second refund error = %v, want ErrOrderNotPaid", err)
+	}
+}
+
+func TestOrderPaidImmediately(t *testing.T) {
+	tests := []struct {
+		name         string
+		autoConfirm  bool
+		ticketTypeID int
+		wantPayment  bool
+	}{
+		{name: "auto confirmed payment", autoConfirm: true, ticketTypeID: 1, wantPayment: true},
+		{name: "free ticket", autoConfirm: false, ticketTypeID: 2, wantPayment: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rsvps, jobRepo := &fakeRSVPEvents{}, &fakeJobStorage{}
+			s := newTestOrderService(newFakeOrderStorage(orderTicketTypes(10)...), newFakeEventStorage(orderEvent()), nil,
+				rsvps, payment.NewFake(tt.autoConfirm), jobRepo)
+
+			order, err := s.CreateOrder(context.Background(), orderBuyer, orderEventID, tt.ticketTypeID)
+			if err != nil {
+				t.Fatalf("CreateOrder() error = %v", err)
+			}
+			if order.Status != models.OrderStatusPaid {
+				t.Errorf("status = %q, want paid", order.Status)
+			}
+			if (order.PaymentID != "") != tt.wantPayment {
+				t.Errorf("payment id = %q, want payment %v", order.PaymentID, tt.wantPayment)
+			}
+			if len(rsvps.created) != 1 {
+				t.Errorf("rsvps created = %d, want 1", len(rsvps.created))
+			}
+			if len(jobRepo.jobs) != 0 {
+				t.Errorf("paid order scheduled expiry: %+v", jobRepo.jobs)
+			}
+		})
+	}
+}
+
+func TestOrderHolds(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeOrderStorage(orderTicketTypes(1)...)
+	s := newTestOrderService(repo, newFakeEventStorage(orderEvent()), nil, &fakeRSVPEvents{}, payment.NewFake(false), &fakeJobStorage{})
+
+	held, err := s.CreateOrder(ctx, orderBuyer, orderEventID, 1)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+
+	if _, err := s.CreateOrder(ctx, orderBuyer, orderEventID, 1); !errors.Is(err, storage.ErrOrderExists) {
+		t.Errorf("second order error = %v, want ErrOrderExists", err)
+	}
+	if _, err := s.CreateOrder(ctx, orderOther, orderEventID, 1); !errors.Is(err, storage.ErrTicketsSoldOut) {
+		t.Errorf("order over quantity error = %v, want ErrTicketsSoldOut", err)
+	}
+
+	// отменённый платёж освобождает билет
+	if err := paymentWebhook(s, held.PaymentID, payment.StatusCanceled); err != nil {
+		t.Fatalf("cancel webhook error = %v", err)
+	}
+	if got := repo.reserved(1); got != 0 {
+		t.Errorf("reserved after cancel = %d, want 0", got)
+	}
+
+	if _, err := s.CreateOrder(ctx, orderOther, orderEventID, 1); err != nil {
+		t.Errorf("order after release error = %v", err)
+	}
+}
+
+func TestOrderExpiry(t *testing.T) {
+	ctx := context.Background()
+	repo, rsvps := newFakeOrderStorage(orderTicketTypes(1)...), &fakeRSVPEvents{}
+	provider := payment.NewFake(false)
+	s := newTestOrderService(repo, newFakeEventStorage(orderEvent()), nil, rsvps, provider, &fakeJobStorage{})
+
+	order, err := s.CreateOrder(ctx, orderBuyer, orderEventID, 1)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+
+	// задача может прийти раньше срока, например после ручного перезапуска
+	if err := s.expireOrder(ctx, orderJob{OrderID: order.ID}); err != nil {
+		t.Fatalf("early expireOrder() error = %v", err)
+	}
+	if got, _ := repo.Order(order.ID); got.Status != models.OrderStatusPending {
+		t.Fatalf("status before expiry = %q, want pending", got.Status)
+	}
+
+	repo.expire(order.ID)
+	if err := s.expireOrder(ctx, orderJob{OrderID: order.ID}); err != nil {
+		t.Fatalf("expireOrder() error = %v", err)
+	}
+	if got, _ := repo.Order(order.ID); got.Status != models.OrderStatusExpired {
+		t.Errorf("status after expiry = %q, want expired", got.Status)
+	}
+	if got := repo.reserved(1); got != 0 {
+		t.Errorf("reserved after expiry = %d, want 0", got)
+	}
+
+	// билет освободился и достался другому, поэтому запоздавшая оплата возвращается
+	if _, err := s.CreateOrder(ctx, orderOther, orderEventID, 1); err != nil {
+		t.Fatalf("CreateOrder() after expiry error = %v", err)
+	}
+	if err := paymentWebhook(s, order.PaymentID, payment.StatusSucceeded); err != nil {
+		t.Fatalf("late payment webhook error = %v", err)
+	}
+	if got, _ := repo.Order(order.ID); got.Status != models.OrderStatusExpired {
+		t.Errorf("status after late payment = %q, want expired", got.Status)
+	}
+	if err := provider.Refund(ctx, order.PaymentID, order.Amount, order.Currency); err == nil {
+		t.Error("late payment was not refunded")
+	}
+	if len(rsvps.created) != 0 {
+		t.Errorf("late payment created rsvps: %+v", rsvps.created)
+	}
+}
+
+func TestOrderLatePaymentWithTicketsLeft(t *testing.T) {
+	ctx := context.Background()
+	repo, rsvps := newFakeOrderStorage(orderTicketTypes(1)...), &fakeRSVPEvents{}
+	s := newTestOrderService(repo, newFakeEventStorage(orderEvent()), nil, rsvps, payment.NewFake(false), &fakeJobStorage{})
+
+	order, err := s.CreateOrder(ctx, orderBuyer, orderEventID, 1)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+	repo.expire(order.ID)
+	if err := s.expireOrder(ctx, orderJob{OrderID: order.ID}); err != nil {
+		t.Fatalf("expireOrder() error = %v", err)
+	}
+
+	// билет ещё есть, поэтому запоздавшая оплата снова его удерживает
+	if err := paymentWebhook(s, order.PaymentID, payment.StatusSucceeded); err != nil {
+		t.Fatalf("late payment webhook error = %v", err)
+	}
+	if got, _ := repo.Order(order.ID); got.Status != models.OrderStatusPaid {
+		t.Errorf("status after late payment = %q, want paid", got.Status)
+	}
+	if got := repo.reserved(1); got != 1 {
+		t.Errorf("reserved after late payment = %d, want 1", got)
+	}
+	if len(rsvps.created) != 1 {
+		t.Errorf("rsvps created = %d, want 1", len(rsvps.created))
+	}
+}
+
+func TestCreateOrderRules(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	invited := fakeInvitations{fmt.Sprintf("%s:%d:%d", models.InvitationSubjectEvent, orderEventID, orderBuyer): true}
+
+	tests := []struct {
+		name         string
+		eventID      int
+		ticketTypeID int
+		event        func(event *models.Event)
+		ticketType   func(ticketType *models.TicketType)
+		invitations  fakeInvitations
+		wantErr      error
+	}{
+		{name: "ticket type of another event", eventID: orderEventID + 1, ticketTypeID: 1, wantErr: storage.ErrTicketTypeNotFound},
+		{name: "unknown ticket type", eventID: orderEventID, ticketTypeID: 99, wantErr: storage.ErrTicketTypeNotFound},
+		{
+			name: "sales closed", eventID: orderEventID, ticketTypeID: 1,
+			ticketType: func(ticketType *models.TicketType) { ticketType.SalesEndAt = &past },
+			wantErr:    ErrTicketSalesClosed,
+		},
+		{
+			name: "cancelled event", eventID: orderEventID, ticketTypeID: 1,
+			event:   func(event *models.Event) { event.CancelledAt = &past },
+			wantErr: storage.ErrEventCancelled,
+		},
+		{
+			name: "finished event", eventID: orderEventID, ticketTypeID: 1,
+			event:   func(event *models.Event) { event.EndsAt = past },
+			wantErr: ErrEventFinished,
+		},
+		{
+			name: "private event without invitation", eventID: orderEventID, ticketTypeID: 1,
+			event:   func(event *models.Event) { event.Private = true },
+			wantErr: ErrInvitationRequired,
+		},
+		{
+			name: "private event with invitation", eventID: orderEventID, ticketTypeID: 1,
+			event:       func(event *models.Event) { event.Private = true },
+			invitations: invited,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, ticketTypes := orderEvent(), orderTicketTypes(10)
+			if tt.event != nil {
+				tt.event(&event)
+			}
+			if tt.ticketType != nil {
+				tt.ticketType(&ticketTypes[0])
+			}
+			repo := newFakeOrderStorage(ticketTypes...)
+			s := newTestOrderService(repo, newFakeEventStorage(event), tt.invitations, &fakeRSVPEvents{},
+				payment.NewFake(false), &fakeJobStorage{})
+
+			_, err := s.CreateOrder(context.Background(), orderBuyer, tt.eventID, tt.ticketTypeID)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("CreateOrder() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && repo.reserved(1) != 0 {
+				t.Errorf("rejected order holds a ticket")
+			}
+		})
+	}
+}
+func TestValidateTicketType(t *testing.T) {
+	event := models.Event{StartsAt: time.Now().Add(48 * time.Hour)}
+	before := event.StartsAt.Add(-time.Hour)
+	after := event.StartsAt.Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		ticketType models.TicketType
+		wantErr    bool
+	}{
+		{name: "free", ticketType: models.TicketType{Kind: models.TicketKindFree}},
+		{name: "free with price", ticketType: models.TicketType{Kind: models.TicketKindFree, Price: 100, Currency: "RUB"}, wantErr: true},
+		{name: "paid", ticketType: models.TicketType{Kind: models.TicketKindPaid, Price: 100, Currency: "RUB"}},
+		{name: "paid without price", ticketType: models.TicketType{Kind: models.TicketKindPaid, Currency: "RUB"}, wantErr: true},
+		{name: "paid in unknown currency", ticketType: models.TicketType{Kind: models.TicketKindPaid, Price: 100, Currency: "rub"}, wantErr: true},
+		{name: "early bird", ticketType: models.TicketType{Kind: models.TicketKindEarlyBird, Price: 100, Currency: "RUB", SalesEndAt: &before}},
+		{name: "early bird without deadline", ticketType: models.TicketType{Kind: models.TicketKindEarlyBird, Price: 100, Currency: "RUB"}, wantErr: true},
+		{name: "early bird after start", ticketType: models.TicketType{Kind: models.TicketKindEarlyBird, Price: 100, Currency: "RUB", SalesEndAt: &after}, wantErr: true},
+		{name: "unknown kind", ticketType: models.TicketType{Kind: "vip", Price: 100, Currency: "RUB"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTicketType(tt.ticketType, event)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateTicketType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewPaymentProvider(t *testing.T) {
+	acquirer := payment.AcquirerConfig{APIURL: "https://acquirer.test", ShopID: "1", SecretKey: "key", WebhookSecret: "secret"}
+	missingSecret := acquirer
+	missingSecret.WebhookSecret = ""
+
+	tests := []struct {
+		name     string
+		config   PaymentConfig
+		wantName string
+		wantErr  bool
+	}{
+		{name: "fake", config: PaymentConfig{Provider: payment.FakeName}, wantName: payment.FakeName},
+		{name: "acquirer", config: PaymentConfig{Provider: payment.AcquirerName, Acquirer: acquirer}, wantName: payment.AcquirerName},
+		{name: "acquirer without webhook secret", config: PaymentConfig{Provider: payment.AcquirerName, Acquirer: missingSecret}, wantErr: true},
+		{name: "empty", config: PaymentConfig{}, wantErr: true},
+		{name: "unknown", config: PaymentConfig{Provider: "cash"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewPaymentProvider(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewPaymentProvider() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && provider.Name() != tt.wantName {
+				t.Errorf("provider = %q, want %q", provider.Name(), tt.wantName)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"dev_meets/internal/domain/models"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// ReminderService напоминает записавшимся участникам о скором начале мероприятия
+type ReminderService struct {
+	repo     ReminderStorageInt
+	notifier Notifier
+	// offsets - за сколько до начала напоминать, по убыванию
+	offsets []time.Duration
+	logger  *slog.Logger
+}
+
+func NewReminderService(repo ReminderStorageInt, notifier Notifier, offsets []time.Duration, logger *slog.Logger) *ReminderService {
+	offsets = slices.Clone(offsets)
+	slices.Sort(offsets)
+	slices.Reverse(offsets)
+
+	return &ReminderService{repo: repo, notifier: notifier, offsets: offsets, logger: logger}
+}
+
+// RunReminders проверяет, не пора ли отправить напоминания, раз в interval, пока не отменён ctx
+func (s *ReminderService) RunReminders(ctx context.Context, interval time.Duration) {
+	if len(s.offsets) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.SendReminders(time.Now()); err != nil {
+				s.logger.Error("failed to send reminders", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+// SendReminders отправляет напоминания, срок которых наступил к моменту now.
+// Каждое мероприятие попадает в окно только одного отступа: записавшийся за полчаса
+// до начала получит напоминание за час, но не за сутки
+func (s *ReminderService) SendReminders(now time.Time) error {
+	const op = "service.ReminderService.SendReminders"
+
+	for i, offset := range s.offsets {
+		from := now
+		if i+1 < len(s.offsets) {
+			from = now.Add(s.offsets[i+1])
+		}
+
+		reminders, err := s.repo.ClaimReminders(offset, from, now.Add(offset))
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, reminder := range reminders {
+			s.remind(reminder, now)
+		}
+	}
+
+	return nil
+}
+
+// remind отправляет напоминание с учётом настроек уведомлений пользователя.
+// Напоминание уже отмечено отправленным, поэтому при ошибке доставки не повторяется
+func (s *ReminderService) remind(reminder models.Reminder, now time.Time) {
+	err := s.notifier.Notify(models.Notification{
+		UserID: reminder.UserID,
+		Type:   models.NotificationEventReminder,
+		Title:  fmt.Sprintf("«%s» начнётся через %s", reminder.EventTitle, humanizeDuration(reminder.StartsAt.Sub(now))),
+		Body:   fmt.Sprintf("Начало %s", reminder.StartsAt.Format("02.01.2006 15:04 MST")),
+	})
+	if err != nil {
+		s.logger.Error("failed to send reminder",
+			slog.Int("event_id", reminder.EventID),
+			slog.Int("user_id", reminder.UserID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// humanizeDuration округляет длительность до часов или минут
+func humanizeDuration(d time.Duration) string {
+	if d >= time.Hour {
+		return fmt.Sprintf("%d ч", int(d.Round(time.Hour).Hours()))
+	}
+
+	minutes := int(d.Round(time.Minute).Minutes())
+	if minutes < 1 {
+		minutes = 1
+	}
+
+	return fmt.Sprintf("%d мин", minutes)
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/storage"
+	"dev_meets/pkg/ticket"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+var (
+	ErrEventFinished    = errors.New("event is already finished")
+	ErrInvalidTicket    = errors.New("invalid ticket")
+	ErrTicketWrongEvent = errors.New("ticket is issued for another event")
+)
+
+type RSVPService struct {
+	repo        RSVPStorageInt
+	events      EventStorageInt
+	invitations InvitationChecker
+	tickets     TicketChecker
+	signer      TicketSigner
+	publisher   Publisher
+	webhooks    WebhookDispatcher
+	logger      *slog.Logger
+}
+
+func NewRSVPService(
+	repo RSVPStorageInt,
+	events EventStorageInt,
+	invitations InvitationChecker,
+	tickets TicketChecker,
+	signer TicketSigner,
+	publisher Publisher,
+	webhooks WebhookDispatcher,
+	logger *slog.Logger,
+) *RSVPService {
+	return &RSVPService{
+		repo:        repo,
+		events:      events,
+		invitations: invitations,
+		tickets:     tickets,
+		signer:      signer,
+		publisher:   publisher,
+		webhooks:    webhooks,
+		logger:      logger,
+	}
+}
+
+// RSVP записывает пользователя на мероприятие и выдаёт подписанный билет. На закрытое
+// мероприятие записывается только приглашённый или сам организатор. Если на мероприятие
+// продаются билеты, записаться можно только с оплаченным заказом
+func (s *RSVPService) RSVP(userID, eventID int) (models.Ticket, error) {
+	const op = "service.RSVPService.RSVP"
+
+	event, err := s.events.Event(eventID)
+	if err != nil {
+		return models.Ticket{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if time.Now().After(event.EndsAt) {
+		return models.Ticket{}, fmt.Errorf("%s: %w", op, ErrEventFinished)
+	}
+	if event.CancelledAt != nil {
+		return models.Ticket{}, fmt.Errorf("%s: %w", op, storage.ErrEventCancelled)
+	}
+	if event.Private && event.OrganizerID != userID {
+		invited, err := s.invitations.HasInvitation(models.InvitationSubjectEvent, eventID, userID)
+		if err != nil {
+			return models.Ticket{}, fmt.Errorf("%s: %w", op, err)
+		}
+		if !invited {
+			return models.Ticket{}, fmt.Errorf("%s: %w", op, ErrInvitationRequired)
+		}
+	}
+	if event.OrganizerID != userID {
+		required, err := s.tickets.TicketRequired(eventID, userID)
+		if err != nil {
+			return models.Ticket{}, fmt.Errorf("%s: %w", op, err)
+		}
+		if required {
+			return models.Ticket{}, fmt.Errorf("%s: %w", op, ErrTicketRequired)
+		}
+	}
+
+	rsvp, err := s.repo.CreateRSVP(eventID, userID)
+	if err != nil {
+		return models.Ticket{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	t, err := s.issue(rsvp)
+	if err != nil {
+		return models.Ticket{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.rsvpCreated(event, rsvp)
+
+	return t, nil
+}
+
+// RSVPCreated сообщает о записи, которую создала оплата заказа, так же, как о записи через RSVP
+func (s *RSVPService) RSVPCreated(rsvp models.RSVP) {
+	event, err := s.events.Event(rsvp.EventID)
+	if err != nil {
+		s.logger.Error("failed to load event", slog.Int("event_id", rsvp.EventID), slog.String("error", err.Error()))
+		return
+	}
+
+	s.rsvpCreated(event, rsvp)
+}
+
+// RSVPCancelled сообщает новое число записавшихся после возврата заказа
+func (s *RSVPService) RSVPCancelled(eventID int) {
+	s.publishRSVPCount(eventID)
+}
+
+// rsvpCreated публикует новое число записавшихся и отправляет вебхук rsvp.created
+func (s *RSVPService) rsvpCreated(event models.Event, rsvp models.RSVP) {
+	s.publishRSVPCount(event.ID)
+
+	if event.GroupID != nil {
+		data := webhookRSVPData{Event: newWebhookEvent(event)}
+		data.RSVP.UserID = rsvp.UserID
+		data.RSVP.CreatedAt = rsvp.CreatedAt
+		s.webhooks.DispatchWebhook(*event.GroupID, models.WebhookRSVPCreated, data)
+	}
+}
+
+func (s *RSVPService) CancelRSVP(userID, eventID int) error {
+	const op = "service.RSVPService.CancelRSVP"
+
+	if err := s.repo.CancelRSVP(eventID, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.publishRSVPCount(eventID)
+
+	return nil
+}
+
+// Ticket возвращает билет пользователя на мероприятие
+func (s *RSVPService) Ticket(userID, eventID int) (models.Ticket, error) {
+	const op = "service.RSVPService.Ticket"
+
+	rsvp, err := s.repo.RSVP(eventID, userID)
+	if err != nil {
+		return models.Ticket{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rsvp.Status != models.RSVPStatusGoing {
+		return models.Ticket{}, fmt.Errorf("%s: %w", op, ErrInvalidTicket)
+	}
+
+	t, err := s.issue(rsvp)
+	if err != nil {
+		return models.Ticket{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return t, nil
+}
+
+// CheckIn проверяет билет на входе: подпись, мероприятие и повторное использование.
+// Отмечать приход может только организатор мероприятия
+func (s *RSVPService) CheckIn(organizerID, eventID int, payload string) (models.RSVP, models.Attendance, error) {
+	const op = "service.RSVPService.CheckIn"
+
+	if _, err := organizedEvent(s.events, organizerID, eventID); err != nil {
+		return models.RSVP{}, models.Attendance{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	claims, err := s.signer.Verify(payload)
+	if err != nil {
+		s.logger.Info("invalid ticket presented", slog.Int("event_id", eventID), slog.String("error", err.Error()))
+
+		return models.RSVP{}, models.Attendance{}, fmt.Errorf("%s: %w", op, ErrInvalidTicket)
+	}
+
+	if claims.EventID != eventID {
+		return models.RSVP{}, models.Attendance{}, fmt.Errorf("%s: %w", op, ErrTicketWrongEvent)
+	}
+
+	rsvp, err := s.repo.RSVPByID(claims.TicketID)
+	if err != nil {
+		return models.RSVP{}, models.Attendance{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Подпись валидна, но номер билета мог быть переиспользован после удаления записи
+	if rsvp.EventID != claims.EventID || rsvp.UserID != claims.UserID {
+		return models.RSVP{}, models.Attendance{}, fmt.Errorf("%s: %w", op, ErrInvalidTicket)
+	}
+
+	rsvp, err = s.repo.CheckIn(rsvp.ID, organizerID)
+	if err != nil {
+		return rsvp, models.Attendance{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	attendance, err := s.repo.Attendance(eventID)
+	if err != nil {
+		return models.RSVP{}, models.Attendance{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("attendee checked in", slog.Int("event_id", eventID), slog.Int("user_id", rsvp.UserID))
+
+	// Счётчик пришедших виден только организатору, число записавшихся - всем
+	s.publisher.PublishToUser(organizerID, models.StreamEventAttendance, map[string]any{
+		"event_id":   eventID,
+		"going":      attendance.Going,
+		"checked_in": attendance.CheckedIn,
+	})
+
+	return rsvp, attendance, nil
+}
+
+func (s *RSVPService) Attendance(organizerID, eventID int) (models.Attendance, error) {
+	const op = "service.RSVPService.Attendance"
+
+	if _, err := organizedEvent(s.events, organizerID, eventID); err != nil {
+		return models.Attendance{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	attendance, err := s.repo.Attendance(eventID)
+	if err != nil {
+		return models.Attendance{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return attendance, nil
+}
+
+// TicketPublicKey возвращает ключ для офлайн-проверки билетов приложением на входе
+func (s *RSVPService) TicketPublicKey() ed25519.PublicKey {
+	return s.signer.PublicKey()
+}
+
+// publishRSVPCount сообщает подписчикам мероприятия новое число записавшихся
+func (s *RSVPService) publishRSVPCount(eventID int) {
+	attendance, err := s.repo.Attendance(eventID)
+	if err != nil {
+		s.logger.Error("failed to count rsvps", slog.String("error", err.Error()))
+		return
+	}
+
+	s.publisher.PublishToTopic(models.StreamTopic(models.StreamTopicEvent, eventID), models.StreamEventRSVPCount, map[string]any{
+		"event_id": eventID,
+		"going":    attendance.Going,
+	})
+}
+
+func (s *RSVPService) issue(rsvp models.RSVP) (models.Ticket, error) {
+	payload, err := s.signer.Sign(ticket.Claims{
+		TicketID: rsvp.ID,
+		EventID:  rsvp.EventID,
+		UserID:   rsvp.UserID,
+		IssuedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		return models.Ticket{}, err
+	}
+
+	return models.Ticket{RSVP: rsvp, Payload: payload}, nil
+}
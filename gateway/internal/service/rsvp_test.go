@@ -0,0 +1,422 @@
+package service
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/storage"
+	"dev_meets/pkg/ticket"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeRSVPStorage хранит записи на мероприятия в памяти
+type fakeRSVPStorage struct {
+	RSVPStorageInt
+	rsvps []models.RSVP
+}
+
+func (s *fakeRSVPStorage) CreateRSVP(eventID, userID int) (models.RSVP, error) {
+	rsvp := models.RSVP{
+		ID:        len(s.rsvps) + 1,
+		EventID:   eventID,
+		UserID:    userID,
+		Status:    models.RSVPStatusGoing,
+		CreatedAt: time.Now(),
+	}
+	s.rsvps = append(s.rsvps, rsvp)
+
+	return rsvp, nil
+}
+
+func (s *fakeRSVPStorage) RSVP(eventID, userID int) (models.RSVP, error) {
+	for _, rsvp := range s.rsvps {
+		if rsvp.EventID == eventID && rsvp.UserID == userID {
+			return rsvp, nil
+		}
+	}
+
+	return models.RSVP{}, storage.ErrRSVPNotFound
+}
+
+func (s *fakeRSVPStorage) RSVPByID(id int) (models.RSVP, error) {
+	for _, rsvp := range s.rsvps {
+		if rsvp.ID == id {
+			return rsvp, nil
+		}
+	}
+
+	return models.RSVP{}, storage.ErrRSVPNotFound
+}
+
+func (s *fakeRSVPStorage) CheckIn(id, checkedInBy int) (models.RSVP, error) {
+	for i, rsvp := range s.rsvps {
+		if rsvp.ID == id {
+			now := time.Now()
+			s.rsvps[i].CheckedInAt, s.rsvps[i].CheckedInBy = &now, &checkedInBy
+			return s.rsvps[i], nil
+		}
+	}
+
+	return models.RSVP{}, storage.ErrRSVPNotFound
+}
+
+func (s *fakeRSVPStorage) Attendance(eventID int) (models.Attendance, error) {
+	var attendance models.Attendance
+	for _, rsvp := range s.rsvps {
+		if rsvp.EventID == eventID && rsvp.Status == models.RSVPStatusGoing {
+			attendance.Going++
+			if rsvp.CheckedInAt != nil {
+				attendance.CheckedIn++
+			}
+		}
+	}
+
+	return attendance, nil
+}
+
+// fakeTicketChecker - пользователи, у которых нет оплаченного заказа на мероприятие с билетами
+type fakeTicketChecker map[int]bool
+
+func (f fakeTicketChecker) TicketRequired(eventID, userID int) (bool, error) {
+	return f[userID], nil
+}
+
+type published struct {
+	topic     string
+	eventType string
+	payload   any
+}
+
+// fakePublisher запоминает события для подписчиков мероприятий
+type fakePublisher struct {
+	events []published
+}
+
+func (p *fakePublisher) PublishToUser(userID int, eventType string, payload any) {}
+
+func (p *fakePublisher) PublishToTopic(topic, eventType string, payload any) {
+	p.events = append(p.events, published{topic: topic, eventType: eventType, payload: payload})
+}
+
+type dispatched struct {
+	groupID   int
+	eventType string
+	data      any
+}
+
+// fakeWebhooks запоминает события, отправленные на вебхуки сообществ
+type fakeWebhooks struct {
+	events []dispatched
+}
+
+func (w *fakeWebhooks) DispatchWebhook(groupID int, eventType string, data any) {
+	w.events = append(w.events, dispatched{groupID: groupID, eventType: eventType, data: data})
+}
+
+const (
+	rsvpOrganizer = 1
+	rsvpUser      = 2
+	rsvpGroupID   = 5
+)
+
+// newTestTicketSigner - подпись билетов нулевым ключом, одинаковым во всех тестах
+func newTestTicketSigner(t *testing.T) *ticket.Signer {
+	t.Helper()
+
+	signer, err := ticket.NewSigner(base64.StdEncoding.EncodeToString(make([]byte, ed25519.SeedSize)))
+	if err != nil {
+		t.Fatalf("NewSigner() error = %v", err)
+	}
+
+	return signer
+}
+
+func newTestRSVPService(
+	repo *fakeRSVPStorage,
+	events *fakeEventStorage,
+	invitations fakeInvitations,
+	tickets fakeTicketChecker,
+	signer *ticket.Signer,
+	publisher *fakePublisher,
+	webhooks *fakeWebhooks,
+) *RSVPService {
+	return NewRSVPService(repo, events, invitations, tickets, signer, publisher, webhooks, testLogger())
+}
+
+func rsvpEvent(update func(event *models.Event)) models.Event {
+	now := time.Now()
+	groupID := rsvpGroupID
+	event := models.Event{
+		ID:          7,
+		OrganizerID: rsvpOrganizer,
+		GroupID:     &groupID,
+		Title:       "GoConf",
+		StartsAt:    now.Add(24 * time.Hour),
+		EndsAt:      now.Add(26 * time.Hour),
+	}
+	if update != nil {
+		update(&event)
+	}
+
+	return event
+}
+
+func TestRSVPRules(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	private := func(event *models.Event) { event.Private = true }
+	invited := fakeInvitations{"event:7:2": true}
+
+	tests := []struct {
+		name        string
+		event       models.Event
+		userID      int
+		invitations fakeInvitations
+		tickets     fakeTicketChecker
+		wantErr     error
+	}{
+		{name: "public event", event: rsvpEvent(nil), userID: rsvpUser},
+		{
+			name:    "finished event",
+			event:   rsvpEvent(func(event *models.Event) { event.StartsAt, event.EndsAt = past.Add(-time.Hour), past }),
+			userID:  rsvpUser,
+			wantErr: ErrEventFinished,
+		},
+		{
+			name:    "cancelled event",
+			event:   rsvpEvent(func(event *models.Event) { event.CancelledAt = &past }),
+			userID:  rsvpUser,
+			wantErr: storage.ErrEventCancelled,
+		},
+		{name: "private event without invitation", event: rsvpEvent(private), userID: rsvpUser, wantErr: ErrInvitationRequired},
+		{name: "private event with invitation", event: rsvpEvent(private), userID: rsvpUser, invitations: invited},
+		{name: "private event organizer", event: rsvpEvent(private), userID: rsvpOrganizer},
+		{
+			name:    "paid event without order",
+			event:   rsvpEvent(nil),
+			userID:  rsvpUser,
+			tickets: fakeTicketChecker{rsvpUser: true},
+			wantErr: ErrTicketRequired,
+		},
+		{
+			name:    "paid event organizer",
+			event:   rsvpEvent(nil),
+			userID:  rsvpOrganizer,
+			tickets: fakeTicketChecker{rsvpOrganizer: true},
+		},
+		{
+			name:        "private paid event with invitation but without order",
+			event:       rsvpEvent(private),
+			userID:      rsvpUser,
+			invitations: invited,
+			tickets:     fakeTicketChecker{rsvpUser: true},
+			wantErr:     ErrTicketRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, publisher, webhooks := &fakeRSVPStorage{}, &fakePublisher{}, &fakeWebhooks{}
+			signer := newTestTicketSigner(t)
+			s := newTestRSVPService(repo, newFakeEventStorage(tt.event), tt.invitations, tt.tickets, signer, publisher, webhooks)
+
+			issued, err := s.RSVP(tt.userID, tt.event.ID)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("RSVP() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				if len(repo.rsvps) != 0 || len(publisher.events) != 0 || len(webhooks.events) != 0 {
+					t.Errorf("rejected rsvp left traces: %+v, %+v, %+v", repo.rsvps, publisher.events, webhooks.events)
+				}
+				return
+			}
+
+			claims, err := ticket.Verify(signer.PublicKey(), issued.Payload)
+			if err != nil {
+				t.Fatalf("ticket does not verify: %v", err)
+			}
+			if claims.EventID != tt.event.ID || claims.UserID != tt.userID || claims.TicketID != issued.RSVP.ID {
+				t.Errorf("ticket claims = %+v, want rsvp %+v", claims, issued.RSVP)
+			}
+		})
+	}
+}
+
+func TestRSVPPublishesCountAndWebhook(t *testing.T) {
+	tests := []struct {
+		name         string
+		event        models.Event
+		wantWebhooks int
+	}{
+		{name: "group event", event: rsvpEvent(nil), wantWebhooks: 1},
+		{name: "personal event", event: rsvpEvent(func(event *models.Event) { event.GroupID = nil })},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher, webhooks := &fakePublisher{}, &fakeWebhooks{}
+			s := newTestRSVPService(&fakeRSVPStorage{}, newFakeEventStorage(tt.event), nil, nil,
+				newTestTicketSigner(t), publisher, webhooks)
+
+			if _, err := s.RSVP(rsvpUser, tt.event.ID); err != nil {
+				t.Fatalf("RSVP() error = %v", err)
+			}
+
+			wantTopic := models.StreamTopic(models.StreamTopicEvent, tt.event.ID)
+			if len(publisher.events) != 1 || publisher.events[0].topic != wantTopic ||
+				publisher.events[0].eventType != models.StreamEventRSVPCount {
+				t.Fatalf("published = %+v, want rsvp count on %s", publisher.events, wantTopic)
+			}
+			if going := publisher.events[0].payload.(map[string]any)["going"]; going != 1 {
+				t.Errorf("published going = %v, want 1", going)
+			}
+
+			if len(webhooks.events) != tt.wantWebhooks {
+				t.Fatalf("webhooks = %+v, want %d", webhooks.events, tt.wantWebhooks)
+			}
+			if tt.wantWebhooks == 0 {
+				return
+			}
+			webhook := webhooks.events[0]
+			data, ok := webhook.data.(webhookRSVPData)
+			if webhook.groupID != rsvpGroupID || webhook.eventType != models.WebhookRSVPCreated || !ok ||
+				data.RSVP.UserID != rsvpUser {
+				t.Errorf("webhook = %+v, want rsvp.created for user %d in group %d", webhook, rsvpUser, rsvpGroupID)
+			}
+		})
+	}
+}
+
+// Записи, созданные оплатой заказа, сообщаются так же, как записи через RSVP
+func TestRSVPEventsFromOrders(t *testing.T) {
+	event := rsvpEvent(nil)
+	repo, publisher, webhooks := &fakeRSVPStorage{}, &fakePublisher{}, &fakeWebhooks{}
+	s := newTestRSVPService(repo, newFakeEventStorage(event), nil, nil, newTestTicketSigner(t), publisher, webhooks)
+
+	rsvp, _ := repo.CreateRSVP(event.ID, rsvpUser)
+	s.RSVPCreated(rsvp)
+
+	if len(publisher.events) != 1 || len(webhooks.events) != 1 {
+		t.Fatalf("after RSVPCreated published %+v, webhooks %+v", publisher.events, webhooks.events)
+	}
+
+	repo.rsvps[0].Status = models.RSVPStatusCancelled
+	s.RSVPCancelled(event.ID)
+
+	if len(publisher.events) != 2 || len(webhooks.events) != 1 {
+		t.Fatalf("after RSVPCancelled published %+v, webhooks %+v", publisher.events, webhooks.events)
+	}
+	if going := publisher.events[1].payload.(map[string]any)["going"]; going != 0 {
+		t.Errorf("published going after cancel = %v, want 0", going)
+	}
+
+	// мероприятие могли удалить, пока шла оплата
+	s.RSVPCreated(models.RSVP{EventID: event.ID + 1, UserID: rsvpUser})
+	if len(publisher.events) != 2 || len(webhooks.events) != 1 {
+		t.Errorf("rsvp for a missing event was published: %+v, %+v", publisher.events, webhooks.events)
+	}
+}
+
+func TestRSVPTicketIsIssuedOnlyForGoing(t *testing.T) {
+	event := rsvpEvent(nil)
+	repo := &fakeRSVPStorage{rsvps: []models.RSVP{
+		{ID: 1, EventID: event.ID, UserID: rsvpUser, Status: models.RSVPStatusCancelled},
+		{ID: 2, EventID: event.ID, UserID: rsvpOrganizer, Status: models.RSVPStatusGoing},
+	}}
+	signer := newTestTicketSigner(t)
+	s := newTestRSVPService(repo, newFakeEventStorage(event), nil, nil, signer, &fakePublisher{}, &fakeWebhooks{})
+
+	if _, err := s.Ticket(rsvpUser, event.ID); !errors.Is(err, ErrInvalidTicket) {
+		t.Errorf("Ticket() for cancelled rsvp error = %v, want ErrInvalidTicket", err)
+	}
+	if _, err := s.Ticket(rsvpUser, event.ID+1); !errors.Is(err, storage.ErrRSVPNotFound) {
+		t.Errorf("Ticket() without rsvp error = %v, want ErrRSVPNotFound", err)
+	}
+
+	issued, err := s.Ticket(rsvpOrganizer, event.ID)
+	if err != nil {
+		t.Fatalf("Ticket() error = %v", err)
+	}
+	if claims, err := ticket.Verify(signer.PublicKey(), issued.Payload); err != nil || claims.TicketID != 2 {
+		t.Errorf("ticket claims = %+v, %v, want ticket 2", claims, err)
+	}
+}
+
+func TestCheckIn(t *testing.T) {
+	event := rsvpEvent(nil)
+	signer := newTestTicketSigner(t)
+	otherSigner, err := ticket.NewSigner(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, ed25519.SeedSize)))
+	if err != nil {
+		t.Fatalf("NewSigner() error = %v", err)
+	}
+	sign := func(signer *ticket.Signer, claims ticket.Claims) string {
+		payload, err := signer.Sign(claims)
+		if err != nil {
+			t.Fatalf("Sign() error = %v", err)
+		}
+		return payload
+	}
+	valid := ticket.Claims{TicketID: 1, EventID: event.ID, UserID: rsvpUser}
+
+	tests := []struct {
+		name        string
+		organizerID int
+		owner       int
+		payload     string
+		wantErr     error
+	}{
+		{name: "valid", organizerID: rsvpOrganizer, owner: rsvpUser, payload: sign(signer, valid)},
+		{name: "not the organizer", organizerID: rsvpUser, owner: rsvpUser, payload: sign(signer, valid), wantErr: ErrForbidden},
+		{name: "signed with another key", organizerID: rsvpOrganizer, owner: rsvpUser, payload: sign(otherSigner, valid), wantErr: ErrInvalidTicket},
+		{name: "malformed", organizerID: rsvpOrganizer, owner: rsvpUser, payload: "DM1.garbage", wantErr: ErrInvalidTicket},
+		{
+			name:        "ticket for another event",
+			organizerID: rsvpOrganizer,
+			owner:       rsvpUser,
+			payload:     sign(signer, ticket.Claims{TicketID: 1, EventID: event.ID + 1, UserID: rsvpUser}),
+			wantErr:     ErrTicketWrongEvent,
+		},
+		{
+			// запись удалили, и её номер достался другому участнику
+			name:        "reused ticket id",
+			organizerID: rsvpOrganizer,
+			owner:       rsvpOrganizer,
+			payload:     sign(signer, valid),
+			wantErr:     ErrInvalidTicket,
+		},
+		{
+			name:        "unknown ticket id",
+			organizerID: rsvpOrganizer,
+			owner:       rsvpUser,
+			payload:     sign(signer, ticket.Claims{TicketID: 2, EventID: event.ID, UserID: rsvpUser}),
+			wantErr:     storage.ErrRSVPNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRSVPStorage{rsvps: []models.RSVP{
+				{ID: 1, EventID: event.ID, UserID: tt.owner, Status: models.RSVPStatusGoing},
+			}}
+			s := newTestRSVPService(repo, newFakeEventStorage(event), nil, nil, signer, &fakePublisher{}, &fakeWebhooks{})
+
+			rsvp, attendance, err := s.CheckIn(tt.organizerID, event.ID, tt.payload)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("CheckIn() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				if repo.rsvps[0].CheckedInAt != nil {
+					t.Errorf("rejected ticket was checked in: %+v", repo.rsvps[0])
+				}
+				return
+			}
+			if rsvp.CheckedInBy == nil || *rsvp.CheckedInBy != tt.organizerID || attendance.CheckedIn != 1 {
+				t.Errorf("CheckIn() = %+v, %+v, want checked in by %d", rsvp, attendance, tt.organizerID)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	minSearchQueryLen = 2
+	maxSearchQueryLen = 200
+)
+
+var (
+	ErrInvalidSearchQuery = errors.New("invalid search query")
+)
+
+type SearchService struct {
+	repo   SearchStorageInt
+	logger *slog.Logger
+}
+
+func NewSearchService(repo SearchStorageInt, logger *slog.Logger) *SearchService {
+	return &SearchService{repo: repo, logger: logger}
+}
+
+func (s *SearchService) Search(query models.SearchQuery) ([]models.SearchResult, error) {
+	const op = "service.SearchService.Search"
+
+	query.Query = strings.TrimSpace(query.Query)
+	if n := utf8.RuneCountInString(query.Query); n < minSearchQueryLen || n > maxSearchQueryLen {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidSearchQuery)
+	}
+
+	for _, t := range query.Types {
+		switch t {
+		case models.SearchTypeEvent, models.SearchTypeGroup, models.SearchTypeDeveloper:
+		default:
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidSearchQuery)
+		}
+	}
+
+	if query.DateFrom != nil && query.DateTo != nil && query.DateTo.Before(*query.DateFrom) {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidSearchQuery)
+	}
+
+	results, err := s.repo.Search(query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return results, nil
+}
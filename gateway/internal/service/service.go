@@ -1,18 +1,130 @@
 package service
 
 import (
+	"dev_meets/internal/jobs"
 	"dev_meets/internal/storage"
+	"dev_meets/pkg/email"
+	"dev_meets/pkg/telegram"
+	"dev_meets/pkg/ticket"
 	"log/slog"
+	"time"
 )
 
 type Service struct {
 	*AuthService
 	*UserService
+	*EventService
+	*CFPService
+	*AgendaService
+	*VenueService
+	*GroupService
+	*SearchService
+	*RSVPService
+	*FeedbackService
+	*CommentService
+	*NotificationService
+	*StreamService
+	*ReminderService
+	*JobService
+	*WebhookService
+	*TelegramService
+	*MessageService
+	*MatchService
+	*FeedService
+	*FollowService
+	*ModerationService
+	*AdminService
+	*AuditService
+	*AccountService
+	*InvitationService
+	*OrderService
 }
 
-func NewService(repos *storage.Repository, logger *slog.Logger) *Service {
+// Config - настройки сервисов, которые приходят из конфигурации приложения
+type Config struct {
+	ReminderOffsets []time.Duration
+	Webhooks        WebhookConfig
+	Telegram        TelegramConfig
+	Audit           AuditConfig
+	Accounts        AccountConfig
+	Invitations     InvitationConfig
+	Payments        PaymentConfig
+	Mail            email.Config
+}
+
+func NewService(
+	repos *storage.Repository,
+	signer *ticket.Signer,
+	queue *jobs.Queue,
+	payments PaymentProvider,
+	config Config,
+	logger *slog.Logger,
+) *Service {
+	audit := NewAuditService(repos.AuditPostgres, repos.UserPostgres, config.Audit, logger)
+	stream := NewStreamService(repos.StreamPostgres, logger)
+	mailer := NewQueuedMailer(queue, NewMailer(config.Mail, logger))
+	notifier := NewNotificationService(repos.NotificationPostgres, repos.UserPostgres, mailer, stream, logger)
+	webhooks := NewWebhookService(repos.WebhookPostgres, repos.GroupPostgres, queue, notifier, audit, config.Webhooks, logger)
+	messages := NewMessageService(repos.MessagePostgres, stream, logger)
+	follows := NewFollowService(repos.FollowPostgres, repos.MessagePostgres, logger)
+	rsvps := NewRSVPService(repos.RSVPPostgres, repos.EventPostgres, repos.InvitationPostgres, repos.OrderPostgres, signer, stream, webhooks, logger)
+	groups := NewGroupService(repos.GroupPostgres, follows, repos.InvitationPostgres, logger)
+	invitations := NewInvitationService(
+		repos.InvitationPostgres,
+		repos.UserPostgres,
+		repos.GroupPostgres,
+		repos.EventPostgres,
+		groups,
+		rsvps,
+		notifier,
+		mailer,
+		config.Invitations,
+		logger,
+	)
+	bot := NewTelegramService(
+		repos.TelegramPostgres,
+		repos.EventPostgres,
+		repos.GroupPostgres,
+		rsvps,
+		telegram.NewClient(config.Telegram.APIURL, config.Telegram.Token),
+		queue,
+		config.Telegram,
+		logger,
+	)
+
 	return &Service{
-		AuthService: NewAuthService(repos.UserPostgres, logger),
-		UserService: NewUserService(repos.UserPostgres, logger),
+		AuthService:         NewAuthService(repos.UserPostgres, audit, invitations, logger),
+		UserService:         NewUserService(repos.UserPostgres, logger),
+		EventService:        NewEventService(repos.EventPostgres, repos.AgendaPostgres, repos.GroupPostgres, webhooks, bot, follows, audit, logger),
+		CFPService:          NewCFPService(repos.CFPPostgres, repos.EventPostgres, notifier, follows, audit, logger),
+		AgendaService:       NewAgendaService(repos.AgendaPostgres, repos.EventPostgres, logger),
+		VenueService:        NewVenueService(repos.VenuePostgres, logger),
+		GroupService:        groups,
+		SearchService:       NewSearchService(repos.SearchPostgres, logger),
+		RSVPService:         rsvps,
+		FeedbackService:     NewFeedbackService(repos.FeedbackPostgres, repos.EventPostgres, repos.AgendaPostgres, repos.RSVPPostgres, logger),
+		NotificationService: notifier,
+		CommentService:      NewCommentService(repos.CommentPostgres, repos.UserPostgres, repos.EventPostgres, repos.GroupPostgres, notifier, stream, logger),
+		StreamService:       stream,
+		ReminderService:     NewReminderService(repos.ReminderPostgres, notifier, config.ReminderOffsets, logger),
+		JobService:          NewJobService(repos.JobPostgres, repos.UserPostgres, logger),
+		WebhookService:      webhooks,
+		TelegramService:     bot,
+		MessageService:      messages,
+		MatchService: NewMatchService(
+			repos.MatchPostgres, repos.MessagePostgres, messages, notifier, NewProfileMatchScorer(), logger,
+		),
+		FeedService:       NewFeedService(repos.FeedPostgres, logger),
+		FollowService:     follows,
+		ModerationService: NewModerationService(repos.ModerationPostgres, repos.UserPostgres, notifier, logger),
+		AdminService:      NewAdminService(repos.AdminPostgres, repos.UserPostgres, logger),
+		AuditService:      audit,
+		AccountService: NewAccountService(
+			repos.AccountPostgres, repos.UserPostgres, queue, notifier, mailer, audit, config.Accounts, logger,
+		),
+		InvitationService: invitations,
+		OrderService: NewOrderService(
+			repos.OrderPostgres, repos.EventPostgres, repos.InvitationPostgres, rsvps, payments, queue, audit, config.Payments, logger,
+		),
 	}
 }
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"dev_meets/internal/domain/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxStreamTopics     = 20
+	streamBufferSize    = 64
+	streamBacklogLimit  = 500
+	streamRetention     = 24 * time.Hour
+	streamCleanupPeriod = time.Hour
+	streamCatchUpBatch  = 500
+)
+
+var ErrInvalidStreamTopic = errors.New("stream topic must look like event:<id> or group:<id>")
+
+// StreamService доставляет события подписчикам в реальном времени. События пишутся
+// в журнал в Postgres, а каждая реплика шлюза узнаёт о них через LISTEN/NOTIFY
+// и раздаёт своим подписчикам
+type StreamService struct {
+	repo   StreamStorageInt
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	byUser  map[int]map[*models.StreamSubscription]struct{}
+	byTopic map[string]map[*models.StreamSubscription]struct{}
+	// lastID - наибольший идентификатор разосланного события
+	lastID int64
+	// closed - реплика останавливается, новые подписки сразу закрываются
+	closed bool
+}
+
+func NewStreamService(repo StreamStorageInt, logger *slog.Logger) *StreamService {
+	return &StreamService{
+		repo:    repo,
+		logger:  logger,
+		byUser:  make(map[int]map[*models.StreamSubscription]struct{}),
+		byTopic: make(map[string]map[*models.StreamSubscription]struct{}),
+	}
+}
+
+// PublishToUser отправляет событие всем подключениям пользователя. Ошибки
+// публикации не прерывают основной сценарий
+func (s *StreamService) PublishToUser(userID int, eventType string, payload any) {
+	s.publish(models.StreamEvent{UserID: &userID, Type: eventType}, payload)
+}
+
+// PublishToTopic отправляет событие всем подписчикам темы
+func (s *StreamService) PublishToTopic(topic, eventType string, payload any) {
+	s.publish(models.StreamEvent{Topic: topic, Type: eventType}, payload)
+}
+
+func (s *StreamService) publish(event models.StreamEvent, payload any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		s.logger.Error("failed to encode stream event", slog.String("error", err.Error()))
+		return
+	}
+	event.Payload = data
+
+	if _, err := s.repo.CreateStreamEvent(event); err != nil {
+		s.logger.Error("failed to publish stream event", slog.String("error", err.Error()))
+	}
+}
+
+// Subscribe подписывает пользователя на его события и на темы. Если передан
+// lastEventID, вместе с подпиской возвращаются пропущенные с тех пор события
+func (s *StreamService) Subscribe(userID int, topics []string, lastEventID int64) (*models.StreamSubscription, []models.StreamEvent, error) {
+	const op = "service.StreamService.Subscribe"
+
+	if len(topics) > maxStreamTopics {
+		return nil, nil, fmt.Errorf("%s: %w", op, ErrInvalidStreamTopic)
+	}
+	for _, topic := range topics {
+		if !validStreamTopic(topic) {
+			return nil, nil, fmt.Errorf("%s: %w", op, ErrInvalidStreamTopic)
+		}
+	}
+
+	sub := &models.StreamSubscription{
+		UserID: userID,
+		Topics: topics,
+		Events: make(chan models.StreamEvent, streamBufferSize),
+	}
+
+	// Подписываемся до чтения журнала, чтобы не потерять события между этими шагами.
+	// Повторы клиент отбрасывает по идентификатору
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		close(sub.Events)
+
+		return sub, nil, nil
+	}
+	addSubscription(s.byUser, userID, sub)
+	for _, topic := range topics {
+		addSubscription(s.byTopic, topic, sub)
+	}
+	s.mu.Unlock()
+
+	if lastEventID <= 0 {
+		return sub, nil, nil
+	}
+
+	backlog, err := s.repo.SubscriberStreamEvents(userID, topics, lastEventID, streamBacklogLimit)
+	if err != nil {
+		s.Unsubscribe(sub)
+
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sub, backlog, nil
+}
+
+// Unsubscribe отменяет подписку и закрывает её канал
+func (s *StreamService) Unsubscribe(sub *models.StreamSubscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remove(sub)
+}
+
+// CloseStreams закрывает все подписки реплики, чтобы открытые потоки SSE и WebSocket
+// завершились при остановке сервера. Новые подписки после этого закрываются сразу
+func (s *StreamService) CloseStreams() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for _, subs := range s.byUser {
+		for sub := range subs {
+			s.remove(sub)
+		}
+	}
+}
+
+// RunStream раздаёт подписчикам события, о которых сообщает ids, и чистит
+// устаревший журнал, пока не отменён ctx
+func (s *StreamService) RunStream(ctx context.Context, ids <-chan int64) {
+	lastID, err := s.repo.LastStreamEventID()
+	if err != nil {
+		s.logger.Error("failed to read stream position", slog.String("error", err.Error()))
+	}
+	s.lastID = lastID
+
+	cleanup := time.NewTicker(streamCleanupPeriod)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-ids:
+			if id == 0 {
+				s.catchUp()
+				continue
+			}
+
+			event, err := s.repo.StreamEvent(id)
+			if err != nil {
+				s.logger.Error("failed to load stream event", slog.String("error", err.Error()))
+				continue
+			}
+			s.dispatch(event)
+		case <-cleanup.C:
+			deleted, err := s.repo.DeleteStreamEventsBefore(time.Now().Add(-streamRetention))
+			if err != nil {
+				s.logger.Error("failed to clean up stream events", slog.String("error", err.Error()))
+				continue
+			}
+			s.logger.Debug("stream events cleaned up", slog.Int("deleted", deleted))
+		}
+	}
+}
+
+// catchUp дочитывает журнал после переподключения к базе
+func (s *StreamService) catchUp() {
+	for {
+		events, err := s.repo.StreamEventsAfter(s.lastID, streamCatchUpBatch)
+		if err != nil {
+			s.logger.Error("failed to catch up stream events", slog.String("error", err.Error()))
+			return
+		}
+
+		for _, event := range events {
+			s.dispatch(event)
+		}
+
+		if len(events) < streamCatchUpBatch {
+			return
+		}
+	}
+}
+
+// dispatch отправляет событие подходящим подписчикам этой реплики. Подписчик,
+// который не успевает читать, отключается
+func (s *StreamService) dispatch(event models.StreamEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if event.ID > s.lastID {
+		s.lastID = event.ID
+	}
+
+	var targets map[*models.StreamSubscription]struct{}
+	if event.UserID != nil {
+		targets = s.byUser[*event.UserID]
+	} else {
+		targets = s.byTopic[event.Topic]
+	}
+
+	for sub := range targets {
+		select {
+		case sub.Events <- event:
+		default:
+			s.logger.Info("stream subscriber is too slow, disconnecting", slog.Int("user_id", sub.UserID))
+			s.remove(sub)
+		}
+	}
+}
+
+// remove вызывается под s.mu
+func (s *StreamService) remove(sub *models.StreamSubscription) {
+	if _, ok := s.byUser[sub.UserID][sub]; !ok {
+		return
+	}
+
+	removeSubscription(s.byUser, sub.UserID, sub)
+	for _, topic := range sub.Topics {
+		removeSubscription(s.byTopic, topic, sub)
+	}
+	close(sub.Events)
+}
+
+func addSubscription[K comparable](index map[K]map[*models.StreamSubscription]struct{}, key K, sub *models.StreamSubscription) {
+	if index[key] == nil {
+		index[key] = make(map[*models.StreamSubscription]struct{})
+	}
+	index[key][sub] = struct{}{}
+}
+
+func removeSubscription[K comparable](index map[K]map[*models.StreamSubscription]struct{}, key K, sub *models.StreamSubscription) {
+	delete(index[key], sub)
+	if len(index[key]) == 0 {
+		delete(index, key)
+	}
+}
+
+func validStreamTopic(topic string) bool {
+	kind, id, ok := strings.Cut(topic, ":")
+	if !ok || (kind != models.StreamTopicEvent && kind != models.StreamTopicGroup) {
+		return false
+	}
+
+	n, err := strconv.Atoi(id)
+
+	return err == nil && n > 0
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestStreamServiceCloseStreams(t *testing.T) {
+	s := NewStreamService(nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	userSub, _, err := s.Subscribe(1, nil, 0)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	topicSub, _, err := s.Subscribe(2, []string{"event:1"}, 0)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	s.CloseStreams()
+
+	if _, ok := <-userSub.Events; ok {
+		t.Error("user subscription is not closed")
+	}
+	if _, ok := <-topicSub.Events; ok {
+		t.Error("topic subscription is not closed")
+	}
+
+	late, _, err := s.Subscribe(3, nil, 0)
+	if err != nil {
+		t.Fatalf("Subscribe() after close error = %v", err)
+	}
+	if _, ok := <-late.Events; ok {
+		t.Error("subscription after close is not closed")
+	}
+
+	// отписка закрытой подписки не должна паниковать
+	s.Unsubscribe(userSub)
+	s.Unsubscribe(late)
+}
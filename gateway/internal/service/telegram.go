@@ -0,0 +1,488 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/jobs"
+	"dev_meets/internal/storage"
+	"dev_meets/pkg/telegram"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	TelegramModePolling = "polling"
+	TelegramModeWebhook = "webhook"
+
+	jobTelegramAnnounce = "telegram.announce"
+	telegramLinkCodeTTL = 15 * time.Minute
+	// telegramRSVPPrefix - префикс callback_data кнопки записи на мероприятие
+	telegramRSVPPrefix     = "rsvp:"
+	telegramRequestTimeout = 10 * time.Second
+	telegramRetryDelay     = 5 * time.Second
+	// telegramLeaderRetry - как часто реплика без блокировки поллинга пробует её взять
+	telegramLeaderRetry    = 30 * time.Second
+	maxAnnounceDescription = 1000
+)
+
+var ErrTelegramDisabled = errors.New("telegram bot is not configured")
+
+type TelegramConfig struct {
+	// Token - токен бота. Пустой токен отключает бота
+	Token  string
+	APIURL string
+	// BotUsername нужен для ссылки на привязку аккаунта t.me/<bot>?start=<code>
+	BotUsername string
+	// Mode - polling или webhook. В режиме polling обновления получает одна реплика,
+	// взявшая блокировку в базе, остальные ждут, пока она освободится
+	Mode          string
+	WebhookURL    string
+	WebhookSecret string
+	PollTimeout   time.Duration
+}
+
+// TelegramService - бот dev meets: публикует новые мероприятия сообществ в привязанные
+// чаты и записывает на них по кнопке под анонсом. Чтобы бот узнавал пользователя,
+// тот привязывает Telegram-аккаунт одноразовым кодом
+type TelegramService struct {
+	repo   TelegramStorageInt
+	events EventStorageInt
+	groups GroupStorageInt
+	rsvps  RSVPCreator
+	client TelegramClient
+	queue  *jobs.Queue
+	config TelegramConfig
+	logger *slog.Logger
+}
+
+type telegramAnnounceJob struct {
+	EventID int   `json:"event_id"`
+	ChatID  int64 `json:"chat_id"`
+}
+
+func NewTelegramService(
+	repo TelegramStorageInt,
+	events EventStorageInt,
+	groups GroupStorageInt,
+	rsvps RSVPCreator,
+	client TelegramClient,
+	queue *jobs.Queue,
+	config TelegramConfig,
+	logger *slog.Logger,
+) *TelegramService {
+	s := &TelegramService{
+		repo:   repo,
+		events: events,
+		groups: groups,
+		rsvps:  rsvps,
+		client: client,
+		queue:  queue,
+		config: config,
+		logger: logger,
+	}
+	jobs.Handle(queue, jobTelegramAnnounce, s.announce)
+
+	return s
+}
+
+// TelegramEnabled сообщает, настроен ли бот
+func (s *TelegramService) TelegramEnabled() bool {
+	return s.config.Token != ""
+}
+
+// CreateTelegramLinkCode выдаёт одноразовый код привязки и ссылку, открывающую бота с этим кодом
+func (s *TelegramService) CreateTelegramLinkCode(userID int) (models.TelegramLinkCode, error) {
+	const op = "service.TelegramService.CreateTelegramLinkCode"
+
+	if !s.TelegramEnabled() {
+		return models.TelegramLinkCode{}, fmt.Errorf("%s: %w", op, ErrTelegramDisabled)
+	}
+
+	raw := make([]byte, 8)
+	if _, err := rand.Read(raw); err != nil {
+		return models.TelegramLinkCode{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	code := models.TelegramLinkCode{
+		Code:      hex.EncodeToString(raw),
+		ExpiresAt: time.Now().Add(telegramLinkCodeTTL),
+	}
+	if s.config.BotUsername != "" {
+		code.Link = fmt.Sprintf("https://t.me/%s?start=%s", s.config.BotUsername, code.Code)
+	}
+
+	if err := s.repo.CreateTelegramLinkCode(userID, code.Code, code.ExpiresAt); err != nil {
+		return models.TelegramLinkCode{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return code, nil
+}
+
+func (s *TelegramService) UnlinkTelegram(userID int) error {
+	const op = "service.TelegramService.UnlinkTelegram"
+
+	if err := s.repo.UnlinkTelegramAccount(userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// HandleTelegramWebhook обрабатывает обновление, которое Telegram прислал на вебхук.
+// Запрос без верного секрета отклоняется
+func (s *TelegramService) HandleTelegramWebhook(ctx context.Context, secret string, update telegram.Update) error {
+	const op = "service.TelegramService.HandleTelegramWebhook"
+
+	if !s.TelegramEnabled() || s.config.Mode != TelegramModeWebhook {
+		return fmt.Errorf("%s: %w", op, ErrTelegramDisabled)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(s.config.WebhookSecret)) != 1 {
+		return fmt.Errorf("%s: %w", op, ErrForbidden)
+	}
+
+	s.handleUpdate(ctx, update)
+
+	return nil
+}
+
+// RunTelegram запускает получение обновлений, пока не отменён ctx. В режиме webhook
+// только регистрирует вебхук: обновления приходят в HTTP-обработчик
+func (s *TelegramService) RunTelegram(ctx context.Context) {
+	if !s.TelegramEnabled() {
+		return
+	}
+
+	if s.config.Mode == TelegramModeWebhook {
+		if s.config.WebhookURL == "" || s.config.WebhookSecret == "" {
+			s.logger.Error("telegram webhook url or secret is not set")
+			return
+		}
+
+		if err := s.client.SetWebhook(ctx, s.config.WebhookURL, s.config.WebhookSecret); err != nil {
+			s.logger.Error("failed to set telegram webhook", slog.String("error", err.Error()))
+		}
+
+		return
+	}
+
+	// getUpdates с одного токена с нескольких реплик конфликтует, поэтому обновления
+	// получает только реплика, взявшая блокировку. Если она упадёт, блокировку возьмёт другая
+	waiting := false
+	for {
+		held, err := s.repo.WithTelegramPollLock(ctx, s.poll)
+		switch {
+		case err != nil:
+			s.logger.Error("failed to take telegram poll lock", slog.String("error", err.Error()))
+		case !held && !waiting:
+			s.logger.Info("telegram updates are polled by another replica, waiting")
+		}
+		waiting = err == nil && !held
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(telegramLeaderRetry):
+		}
+	}
+}
+
+// poll получает обновления long polling'ом, пока не отменён ctx
+func (s *TelegramService) poll(ctx context.Context) {
+	s.logger.Info("telegram polling started")
+
+	if err := s.client.DeleteWebhook(ctx); err != nil {
+		s.logger.Error("failed to delete telegram webhook", slog.String("error", err.Error()))
+	}
+
+	var offset int64
+	for {
+		pollCtx, cancel := context.WithTimeout(ctx, s.config.PollTimeout+telegramRequestTimeout)
+		updates, err := s.client.GetUpdates(pollCtx, offset, s.config.PollTimeout)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			s.logger.Error("failed to get telegram updates", slog.String("error", err.Error()))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(telegramRetryDelay):
+			}
+
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			s.handleUpdate(ctx, update)
+		}
+	}
+}
+
+// AnnounceEvent ставит в очередь анонс мероприятия сообщества во все его чаты
+func (s *TelegramService) AnnounceEvent(event models.Event) {
+	if !s.TelegramEnabled() || event.GroupID == nil {
+		return
+	}
+
+	chats, err := s.repo.GroupChats(*event.GroupID)
+	if err != nil {
+		s.logger.Error("failed to load telegram chats", slog.Int("group_id", *event.GroupID), slog.String("error", err.Error()))
+		return
+	}
+
+	for _, chatID := range chats {
+		if _, err := s.queue.Enqueue(jobTelegramAnnounce, telegramAnnounceJob{EventID: event.ID, ChatID: chatID}); err != nil {
+			s.logger.Error("failed to enqueue telegram announcement",
+				slog.Int("event_id", event.ID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// announce отправляет анонс в чат. Если бота удалили из чата, чат отвязывается
+// от сообществ и задача не повторяется
+func (s *TelegramService) announce(ctx context.Context, job telegramAnnounceJob) error {
+	event, err := s.events.Event(job.EventID)
+	if err != nil {
+		if errors.Is(err, storage.ErrEventNotFound) {
+			return nil
+		}
+
+		return err
+	}
+	if event.CancelledAt != nil {
+		return nil
+	}
+
+	err = s.client.SendMessage(ctx, telegram.SendMessageParams{
+		ChatID: job.ChatID,
+		Text:   announcementText(event),
+		ReplyMarkup: &telegram.InlineKeyboardMarkup{InlineKeyboard: [][]telegram.InlineKeyboardButton{{
+			{Text: "Пойду", CallbackData: telegramRSVPPrefix + strconv.Itoa(event.ID)},
+		}}},
+	})
+
+	var apiErr *telegram.Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case http.StatusForbidden:
+			if err := s.repo.RemoveChat(job.ChatID); err != nil {
+				s.logger.Error("failed to remove telegram chat", slog.String("error", err.Error()))
+			}
+
+			return jobs.Permanent(err)
+		case http.StatusBadRequest:
+			return jobs.Permanent(err)
+		}
+	}
+
+	return err
+}
+
+func (s *TelegramService) handleUpdate(ctx context.Context, update telegram.Update) {
+	ctx, cancel := context.WithTimeout(ctx, telegramRequestTimeout)
+	defer cancel()
+
+	switch {
+	case update.Message != nil:
+		s.handleMessage(ctx, *update.Message)
+	case update.CallbackQuery != nil:
+		s.handleCallback(ctx, *update.CallbackQuery)
+	}
+}
+
+func (s *TelegramService) handleMessage(ctx context.Context, message telegram.Message) {
+	command, arg, ok := parseTelegramCommand(message.Text, s.config.BotUsername)
+	if !ok || message.From == nil {
+		return
+	}
+
+	var reply string
+	switch {
+	case message.Chat.IsPrivate() && (command == "start" || command == "link"):
+		reply = s.link(arg, message.From.ID)
+	case !message.Chat.IsPrivate() && command == "connect":
+		reply = s.connectChat(arg, message.From.ID, message.Chat.ID)
+	case !message.Chat.IsPrivate() && command == "disconnect":
+		reply = s.disconnectChat(arg, message.From.ID, message.Chat.ID)
+	default:
+		return
+	}
+
+	if err := s.client.SendMessage(ctx, telegram.SendMessageParams{ChatID: message.Chat.ID, Text: reply}); err != nil {
+		s.logger.Error("failed to reply in telegram", slog.String("error", err.Error()))
+	}
+}
+
+func (s *TelegramService) link(code string, telegramUserID int64) string {
+	if code == "" {
+		return "Чтобы привязать аккаунт, получите код в профиле dev meets и отправьте его командой /link <код>"
+	}
+
+	userID, err := s.repo.LinkTelegramAccount(code, telegramUserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrLinkCodeNotFound) {
+			return "Код не найден или устарел. Получите новый в профиле dev meets"
+		}
+
+		s.logger.Error("failed to link telegram account", slog.String("error", err.Error()))
+		return "Не удалось привязать аккаунт, попробуйте позже"
+	}
+
+	s.logger.Info("telegram account linked", slog.Int("user_id", userID))
+
+	return "Аккаунт привязан. Теперь можно записываться на мероприятия кнопкой под анонсом"
+}
+
+func (s *TelegramService) connectChat(arg string, telegramUserID, chatID int64) string {
+	groupID, reply := s.chatGroup(arg, telegramUserID)
+	if reply != "" {
+		return reply
+	}
+
+	if err := s.repo.AddGroupChat(groupID, chatID); err != nil {
+		s.logger.Error("failed to connect telegram chat", slog.String("error", err.Error()))
+		return "Не удалось подключить чат, попробуйте позже"
+	}
+
+	return "Чат подключён: сюда будут приходить анонсы новых мероприятий сообщества"
+}
+
+func (s *TelegramService) disconnectChat(arg string, telegramUserID, chatID int64) string {
+	groupID, reply := s.chatGroup(arg, telegramUserID)
+	if reply != "" {
+		return reply
+	}
+
+	if err := s.repo.RemoveGroupChat(groupID, chatID); err != nil {
+		s.logger.Error("failed to disconnect telegram chat", slog.String("error", err.Error()))
+		return "Не удалось отключить чат, попробуйте позже"
+	}
+
+	return "Чат отключён от сообщества"
+}
+
+// chatGroup проверяет, что автор команды /connect или /disconnect владеет сообществом.
+// Если нет, возвращает текст ответа с причиной
+func (s *TelegramService) chatGroup(arg string, telegramUserID int64) (int, string) {
+	groupID, err := strconv.Atoi(arg)
+	if err != nil || groupID <= 0 {
+		return 0, "Укажите идентификатор сообщества после команды, например /connect 3"
+	}
+
+	userID, err := s.repo.UserIDByTelegramID(telegramUserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrTelegramAccountNotFound) {
+			return 0, "Сначала привяжите аккаунт dev meets в личном чате с ботом"
+		}
+
+		s.logger.Error("failed to find telegram account", slog.String("error", err.Error()))
+		return 0, "Не удалось проверить права, попробуйте позже"
+	}
+
+	if _, err := ownedGroup(s.groups, userID, groupID); err != nil {
+		if errors.Is(err, ErrForbidden) || errors.Is(err, storage.ErrGroupNotFound) {
+			return 0, "Подключать чаты может только владелец сообщества"
+		}
+
+		s.logger.Error("failed to load group", slog.String("error", err.Error()))
+		return 0, "Не удалось проверить права, попробуйте позже"
+	}
+
+	return groupID, ""
+}
+
+func (s *TelegramService) handleCallback(ctx context.Context, query telegram.CallbackQuery) {
+	if !strings.HasPrefix(query.Data, telegramRSVPPrefix) {
+		return
+	}
+
+	text := s.rsvp(strings.TrimPrefix(query.Data, telegramRSVPPrefix), query.From.ID)
+	if err := s.client.AnswerCallbackQuery(ctx, query.ID, text); err != nil {
+		s.logger.Error("failed to answer telegram callback", slog.String("error", err.Error()))
+	}
+}
+
+func (s *TelegramService) rsvp(arg string, telegramUserID int64) string {
+	eventID, err := strconv.Atoi(arg)
+	if err != nil {
+		return "Мероприятие не найдено"
+	}
+
+	userID, err := s.repo.UserIDByTelegramID(telegramUserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrTelegramAccountNotFound) {
+			return "Сначала привяжите аккаунт dev meets: откройте личный чат с ботом"
+		}
+
+		s.logger.Error("failed to find telegram account", slog.String("error", err.Error()))
+		return "Не удалось записаться, попробуйте позже"
+	}
+
+	if _, err := s.rsvps.RSVP(userID, eventID); err != nil {
+		switch {
+		case errors.Is(err, storage.ErrEventNotFound):
+			return "Мероприятие не найдено"
+		case errors.Is(err, storage.ErrEventCancelled):
+			return "Мероприятие отменено"
+		case errors.Is(err, ErrEventFinished):
+			return "Мероприятие уже прошло"
+		case errors.Is(err, ErrInvitationRequired):
+			return "Записаться можно только по приглашению организатора"
+		case errors.Is(err, ErrTicketRequired):
+			return "На это мероприятие нужен оплаченный билет, купить его можно на сайте"
+		}
+
+		s.logger.Error("failed to rsvp from telegram", slog.Int("event_id", eventID), slog.String("error", err.Error()))
+		return "Не удалось записаться, попробуйте позже"
+	}
+
+	return "Вы записаны! Билет доступен в dev meets"
+}
+
+// parseTelegramCommand разбирает "/command@bot arg" на команду и аргумент.
+// Команды, адресованные другому боту, пропускаются
+func parseTelegramCommand(text, botUsername string) (string, string, bool) {
+	if !strings.HasPrefix(text, "/") {
+		return "", "", false
+	}
+
+	command, arg, _ := strings.Cut(strings.TrimPrefix(text, "/"), " ")
+	command, bot, addressed := strings.Cut(command, "@")
+	if addressed && botUsername != "" && !strings.EqualFold(bot, botUsername) {
+		return "", "", false
+	}
+
+	return strings.ToLower(command), strings.TrimSpace(arg), true
+}
+
+func announcementText(event models.Event) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Новое мероприятие: %s\n\n", event.Title)
+	fmt.Fprintf(&b, "%s, %s\n", event.City, event.StartsAt.Format("02.01.2006 15:04"))
+
+	description := []rune(event.Description)
+	if len(description) > maxAnnounceDescription {
+		description = append(description[:maxAnnounceDescription], '…')
+	}
+	if len(description) > 0 {
+		b.WriteString("\n")
+		b.WriteString(string(description))
+	}
+
+	return b.String()
+}
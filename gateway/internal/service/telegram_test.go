@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/jobs"
+	"dev_meets/internal/storage"
+	"dev_meets/pkg/telegram"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testBotToken = "123:secret"
+
+// fakeBotAPI - Bot API на httptest.Server: отдаёт заранее заданные обновления через
+// getUpdates и запоминает ответы бота
+type fakeBotAPI struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	updates  []telegram.Update
+	polls    int
+	messages []telegram.SendMessageParams
+	answers  map[string]string
+}
+
+func newFakeBotAPI(t *testing.T, updates ...telegram.Update) *fakeBotAPI {
+	api := &fakeBotAPI{updates: updates, answers: make(map[string]string)}
+	api.Server = httptest.NewServer(http.HandlerFunc(api.serve))
+	t.Cleanup(api.Close)
+
+	return api
+}
+
+func (a *fakeBotAPI) serve(w http.ResponseWriter, r *http.Request) {
+	method, ok := strings.CutPrefix(r.URL.Path, "/bot"+testBotToken+"/")
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": false, "error_code": 401, "description": "Unauthorized"})
+		return
+	}
+
+	var result any = true
+	switch method {
+	case "getUpdates":
+		var params struct {
+			Offset int64 `json:"offset"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&params)
+
+		a.mu.Lock()
+		a.polls++
+		pending := make([]telegram.Update, 0)
+		for _, update := range a.updates {
+			if update.UpdateID >= params.Offset {
+				pending = append(pending, update)
+			}
+		}
+		a.mu.Unlock()
+
+		if len(pending) == 0 {
+			// long polling: пустой ответ приходит не сразу
+			select {
+			case <-r.Context().Done():
+			case <-time.After(20 * time.Millisecond):
+			}
+		}
+		result = pending
+	case "sendMessage":
+		var params telegram.SendMessageParams
+		_ = json.NewDecoder(r.Body).Decode(&params)
+
+		a.mu.Lock()
+		a.messages = append(a.messages, params)
+		a.mu.Unlock()
+	case "answerCallbackQuery":
+		var params struct {
+			ID   string `json:"callback_query_id"`
+			Text string `json:"text"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&params)
+
+		a.mu.Lock()
+		a.answers[params.ID] = params.Text
+		a.mu.Unlock()
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": result})
+}
+
+// replies возвращает ответы бота в чат chatID
+func (a *fakeBotAPI) replies(chatID int64) []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var texts []string
+	for _, message := range a.messages {
+		if message.ChatID == chatID {
+			texts = append(texts, message.Text)
+		}
+	}
+
+	return texts
+}
+
+func (a *fakeBotAPI) answer(id string) (string, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	text, ok := a.answers[id]
+	return text, ok
+}
+
+func (a *fakeBotAPI) pollCount() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.polls
+}
+
+// fakeTelegramStorage хранит коды привязки, аккаунты и чаты в памяти. lockHeld - взята ли
+// блокировка поллинга другой репликой
+type fakeTelegramStorage struct {
+	TelegramStorageInt
+
+	mu       sync.Mutex
+	codes    map[string]int
+	accounts map[int64]int
+	chats    map[int][]int64
+	lockHeld bool
+}
+
+func (s *fakeTelegramStorage) LinkTelegramAccount(code string, telegramUserID int64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userID, ok := s.codes[code]
+	if !ok {
+		return 0, storage.ErrLinkCodeNotFound
+	}
+	delete(s.codes, code)
+	s.accounts[telegramUserID] = userID
+
+	return userID, nil
+}
+
+func (s *fakeTelegramStorage) UserIDByTelegramID(telegramUserID int64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userID, ok := s.accounts[telegramUserID]
+	if !ok {
+		return 0, storage.ErrTelegramAccountNotFound
+	}
+
+	return userID, nil
+}
+
+func (s *fakeTelegramStorage) AddGroupChat(groupID int, chatID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.chats[groupID] = append(s.chats[groupID], chatID)
+	return nil
+}
+
+func (s *fakeTelegramStorage) groupChats(groupID int) []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.chats[groupID]
+}
+
+func (s *fakeTelegramStorage) WithTelegramPollLock(ctx context.Context, poll func(ctx context.Context)) (bool, error) {
+	if s.lockHeld {
+		return false, nil
+	}
+
+	poll(ctx)
+	return true, nil
+}
+
+func newTestTelegramService(api *fakeBotAPI, repo *fakeTelegramStorage, rsvps *fakeRSVPCreator) *TelegramService {
+	groups := &fakeGroupStorage{groups: map[int]models.Group{3: {ID: 3, OwnerID: 10}}}
+	queue := jobs.NewQueue(nil, jobs.Config{Concurrency: 1}, testLogger())
+
+	return NewTelegramService(repo, nil, groups, rsvps, telegram.NewClient(api.URL, testBotToken), queue,
+		TelegramConfig{
+			Token:       testBotToken,
+			BotUsername: "devmeets_bot",
+			Mode:        TelegramModePolling,
+			PollTimeout: time.Second,
+		}, testLogger())
+}
+
+// waitFor ждёт, пока выполнится условие, не дольше секунды
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestTelegramPolling(t *testing.T) {
+	const (
+		owner    int64 = 100
+		stranger int64 = 200
+		group    int64 = -300
+	)
+
+	privateChat := func(from int64) telegram.Chat { return telegram.Chat{ID: from, Type: "private"} }
+	groupChat := telegram.Chat{ID: group, Type: "supergroup"}
+
+	api := newFakeBotAPI(t,
+		telegram.Update{UpdateID: 1, Message: &telegram.Message{
+			From: &telegram.User{ID: owner}, Chat: privateChat(owner), Text: "/start expired",
+		}},
+		telegram.Update{UpdateID: 2, Message: &telegram.Message{
+			From: &telegram.User{ID: owner}, Chat: privateChat(owner), Text: "/start abc123",
+		}},
+		telegram.Update{UpdateID: 3, Message: &telegram.Message{
+			From: &telegram.User{ID: stranger}, Chat: groupChat, Text: "/connect@devmeets_bot 3",
+		}},
+		telegram.Update{UpdateID: 4, Message: &telegram.Message{
+			From: &telegram.User{ID: owner}, Chat: groupChat, Text: "/connect@other_bot 3",
+		}},
+		telegram.Update{UpdateID: 5, Message: &telegram.Message{
+			From: &telegram.User{ID: owner}, Chat: groupChat, Text: "/connect@devmeets_bot 3",
+		}},
+		telegram.Update{UpdateID: 6, CallbackQuery: &telegram.CallbackQuery{
+			ID: "rsvp-owner", From: telegram.User{ID: owner}, Data: "rsvp:7",
+		}},
+		telegram.Update{UpdateID: 7, CallbackQuery: &telegram.CallbackQuery{
+			ID: "rsvp-stranger", From: telegram.User{ID: stranger}, Data: "rsvp:7",
+		}},
+		telegram.Update{UpdateID: 8, CallbackQuery: &telegram.CallbackQuery{
+			ID: "rsvp-paid", From: telegram.User{ID: owner}, Data: "rsvp:8",
+		}},
+	)
+	repo := &fakeTelegramStorage{
+		codes:    map[string]int{"abc123": 10},
+		accounts: make(map[int64]int),
+		chats:    make(map[int][]int64),
+	}
+	rsvps := &fakeRSVPCreator{
+		events: map[int]error{7: nil, 8: ErrTicketRequired},
+		rsvps:  make(map[int][]int),
+	}
+	s := newTestTelegramService(api, repo, rsvps)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.RunTelegram(ctx)
+	}()
+
+	waitFor(t, "callback answers", func() bool {
+		_, ok := api.answer("rsvp-paid")
+		return ok
+	})
+	cancel()
+	<-done
+
+	wantPrivate := []string{
+		"Код не найден или устарел. Получите новый в профиле dev meets",
+		"Аккаунт привязан. Теперь можно записываться на мероприятия кнопкой под анонсом",
+	}
+	if got := api.replies(owner); strings.Join(got, "\n") != strings.Join(wantPrivate, "\n") {
+		t.Errorf("private replies = %q, want %q", got, wantPrivate)
+	}
+
+	// команда для другого бота пропускается без ответа
+	wantGroup := []string{
+		"Сначала привяжите аккаунт dev meets в личном чате с ботом",
+		"Чат подключён: сюда будут приходить анонсы новых мероприятий сообщества",
+	}
+	if got := api.replies(group); strings.Join(got, "\n") != strings.Join(wantGroup, "\n") {
+		t.Errorf("group replies = %q, want %q", got, wantGroup)
+	}
+	if chats := repo.groupChats(3); len(chats) != 1 || chats[0] != group {
+		t.Errorf("group chats = %v, want [%d]", chats, group)
+	}
+
+	answers := map[string]string{
+		"rsvp-owner":    "Вы записаны! Билет доступен в dev meets",
+		"rsvp-stranger": "Сначала привяжите аккаунт dev meets: откройте личный чат с ботом",
+		"rsvp-paid":     "На это мероприятие нужен оплаченный билет, купить его можно на сайте",
+	}
+	for id, want := range answers {
+		if got, _ := api.answer(id); got != want {
+			t.Errorf("answer %s = %q, want %q", id, got, want)
+		}
+	}
+	if got := rsvps.rsvps[7]; len(got) != 1 || got[0] != 10 {
+		t.Errorf("rsvps for event 7 = %v, want [10]", got)
+	}
+}
+
+func TestTelegramPollingLockedByAnotherReplica(t *testing.T) {
+	api := newFakeBotAPI(t, telegram.Update{UpdateID: 1, Message: &telegram.Message{
+		From: &telegram.User{ID: 1}, Chat: telegram.Chat{ID: 1, Type: "private"}, Text: "/start abc123",
+	}})
+	repo := &fakeTelegramStorage{lockHeld: true}
+	s := newTestTelegramService(api, repo, &fakeRSVPCreator{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	s.RunTelegram(ctx)
+
+	if polls := api.pollCount(); polls != 0 {
+		t.Errorf("getUpdates called %d times without the poll lock", polls)
+	}
+	if replies := api.replies(1); len(replies) != 0 {
+		t.Errorf("replies without the poll lock = %q", replies)
+	}
+}
@@ -3,10 +3,19 @@ package service
 import (
 	"dev_meets/internal/domain/models"
 	"dev_meets/pkg/jwt"
+	"errors"
 	"fmt"
 	"log/slog"
+	"regexp"
+	"strings"
 )
 
+var (
+	ErrInvalidProfile = errors.New("invalid profile")
+)
+
+var usernameRe = regexp.MustCompile(`^[a-zA-Z0-9_]{3,32}$`)
+
 type UserService struct {
 	repo   UserStorageInt
 	logger *slog.Logger
@@ -27,3 +36,75 @@ func (s *UserService) CurrentUser(token string) (models.User, error) {
 
 	return user, nil
 }
+
+func (s *UserService) UpdateProfile(userID int, profile models.Profile) error {
+	const op = "service.UserService.UpdateProfile"
+
+	profile.Username = strings.TrimPrefix(strings.TrimSpace(profile.Username), "@")
+	if profile.Username != "" && !usernameRe.MatchString(profile.Username) {
+		return fmt.Errorf("%s: %w", op, ErrInvalidProfile)
+	}
+
+	switch profile.Seniority {
+	case "", models.SeniorityJunior, models.SeniorityMiddle, models.SenioritySenior, models.SeniorityLead:
+	default:
+		return fmt.Errorf("%s: %w", op, ErrInvalidProfile)
+	}
+
+	profile.Skills = normalizeSkills(profile.Skills)
+
+	if err := s.repo.UpdateProfile(userID, profile); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// normalizeSkills приводит навыки к нижнему регистру и убирает дубликаты,
+// чтобы фильтр по навыку работал через индекс по skills
+func normalizeSkills(skills []string) []string {
+	seen := make(map[string]struct{}, len(skills))
+	normalized := make([]string, 0, len(skills))
+	for _, skill := range skills {
+		skill = strings.ToLower(strings.TrimSpace(skill))
+		if skill == "" {
+			continue
+		}
+		if _, ok := seen[skill]; ok {
+			continue
+		}
+		seen[skill] = struct{}{}
+		normalized = append(normalized, skill)
+	}
+
+	return normalized
+}
+
+// requireModerator проверяет, что пользователь может разбирать жалобы: это модератор
+// или администратор платформы
+func requireModerator(users UserStorageInt, userID int) error {
+	user, err := users.User(userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Role != models.UserRoleModerator && user.Role != models.UserRoleAdmin {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+// requireAdmin проверяет, что пользователь - администратор платформы
+func requireAdmin(users UserStorageInt, userID int) error {
+	user, err := users.User(userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Role != models.UserRoleAdmin {
+		return ErrForbidden
+	}
+
+	return nil
+}
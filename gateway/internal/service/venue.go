@@ -0,0 +1,99 @@
+package service
+
+import (
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+const (
+	maxSearchRadiusKm = 500
+)
+
+var (
+	ErrInvalidGeoFilter = errors.New("invalid coordinates or radius")
+)
+
+type VenueService struct {
+	repo   VenueStorageInt
+	logger *slog.Logger
+}
+
+func NewVenueService(repo VenueStorageInt, logger *slog.Logger) *VenueService {
+	return &VenueService{repo: repo, logger: logger}
+}
+
+func (s *VenueService) CreateVenue(venue models.Venue) (int, error) {
+	const op = "service.VenueService.CreateVenue"
+
+	id, err := s.repo.CreateVenue(venue)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("venue created", slog.Int("venue_id", id))
+
+	return id, nil
+}
+
+// UpdateVenue разрешает менять площадку только её автору
+func (s *VenueService) UpdateVenue(userID int, venue models.Venue) error {
+	const op = "service.VenueService.UpdateVenue"
+
+	current, err := s.repo.Venue(venue.ID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if current.CreatedBy != userID {
+		return fmt.Errorf("%s: %w", op, ErrForbidden)
+	}
+
+	if err := s.repo.UpdateVenue(venue); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *VenueService) Venue(id int) (models.Venue, error) {
+	const op = "service.VenueService.Venue"
+
+	venue, err := s.repo.Venue(id)
+	if err != nil {
+		return models.Venue{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return venue, nil
+}
+
+func (s *VenueService) Venues(filter models.VenueFilter) ([]models.Venue, error) {
+	const op = "service.VenueService.Venues"
+
+	if err := validateGeoFilter(filter.Near); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	venues, err := s.repo.Venues(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return venues, nil
+}
+
+func validateGeoFilter(filter *models.GeoFilter) error {
+	if filter == nil {
+		return nil
+	}
+
+	if filter.Point.Lat < -90 || filter.Point.Lat > 90 || filter.Point.Lon < -180 || filter.Point.Lon > 180 {
+		return ErrInvalidGeoFilter
+	}
+	if filter.RadiusKm <= 0 || filter.RadiusKm > maxSearchRadiusKm {
+		return ErrInvalidGeoFilter
+	}
+
+	return nil
+}
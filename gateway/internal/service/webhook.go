@@ -0,0 +1,488 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/jobs"
+	"dev_meets/internal/storage"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	jobDeliverWebhook  = "webhook.deliver"
+	maxWebhooksInGroup = 10
+	webhookSecretBytes = 32
+
+	WebhookEventHeader     = "X-DevMeets-Event"
+	WebhookDeliveryHeader  = "X-DevMeets-Delivery"
+	WebhookTimestampHeader = "X-DevMeets-Timestamp"
+	WebhookSignatureHeader = "X-DevMeets-Signature"
+)
+
+var (
+	ErrInvalidWebhook      = errors.New("webhook url must be http(s) and event types must be known")
+	ErrTooManyWebhooks     = errors.New("too many webhooks in group")
+	errPrivateWebhookAddr  = errors.New("webhook address is in a private network")
+	errWebhookStatusFailed = errors.New("webhook responded with non-2xx status")
+)
+
+type WebhookConfig struct {
+	Timeout time.Duration
+	// DisableAfter - после скольких неудачных доставок подряд вебхук отключается
+	DisableAfter int
+	// AllowPrivateNetworks разрешает адреса в локальной сети, например для разработки
+	AllowPrivateNetworks bool
+}
+
+// WebhookService отправляет события сообщества на адреса, которые указал его владелец.
+// Каждая доставка - отдельная задача в очереди, поэтому медленный или недоступный
+// адрес не задерживает запрос, а неудачная доставка повторяется
+type WebhookService struct {
+	repo     WebhookStorageInt
+	groups   GroupStorageInt
+	queue    *jobs.Queue
+	notifier Notifier
+	audit    Auditor
+	client   *http.Client
+	config   WebhookConfig
+	logger   *slog.Logger
+}
+
+type webhookJob struct {
+	WebhookID  int             `json:"webhook_id"`
+	DeliveryID string          `json:"delivery_id"`
+	EventType  string          `json:"event_type"`
+	Body       json.RawMessage `json:"body"`
+}
+
+// webhookEnvelope - тело запроса на вебхук
+type webhookEnvelope struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+func NewWebhookService(
+	repo WebhookStorageInt,
+	groups GroupStorageInt,
+	queue *jobs.Queue,
+	notifier Notifier,
+	audit Auditor,
+	config WebhookConfig,
+	logger *slog.Logger,
+) *WebhookService {
+	s := &WebhookService{
+		repo:     repo,
+		groups:   groups,
+		queue:    queue,
+		notifier: notifier,
+		audit:    audit,
+		client:   newWebhookClient(config),
+		config:   config,
+		logger:   logger,
+	}
+	jobs.Handle(queue, jobDeliverWebhook, s.deliver)
+
+	return s
+}
+
+// auditWebhook - состояние вебхука для журнала аудита, без секрета
+type auditWebhook struct {
+	GroupID    int      `json:"group_id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Active     bool     `json:"active"`
+}
+
+func newAuditWebhook(webhook models.Webhook) auditWebhook {
+	return auditWebhook{
+		GroupID:    webhook.GroupID,
+		URL:        webhook.URL,
+		EventTypes: webhook.EventTypes,
+		Active:     webhook.Active,
+	}
+}
+
+// CreateWebhook регистрирует вебхук сообщества. Секрет для проверки подписи
+// возвращается только здесь
+func (s *WebhookService) CreateWebhook(ctx context.Context, userID int, webhook models.Webhook) (models.Webhook, error) {
+	const op = "service.WebhookService.CreateWebhook"
+
+	if _, err := ownedGroup(s.groups, userID, webhook.GroupID); err != nil {
+		return models.Webhook{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := validateWebhook(webhook); err != nil {
+		return models.Webhook{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	existing, err := s.repo.GroupWebhooks(webhook.GroupID)
+	if err != nil {
+		return models.Webhook{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(existing) >= maxWebhooksInGroup {
+		return models.Webhook{}, fmt.Errorf("%s: %w", op, ErrTooManyWebhooks)
+	}
+
+	secret := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return models.Webhook{}, fmt.Errorf("%s: %w", op, err)
+	}
+	webhook.Secret = hex.EncodeToString(secret)
+
+	id, err := s.repo.CreateWebhook(webhook)
+	if err != nil {
+		return models.Webhook{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	created, err := s.repo.Webhook(id)
+	if err != nil {
+		return models.Webhook{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.audit.Audit(newAuditEvent(ctx, userID, models.AuditWebhookCreated, models.AuditTargetWebhook, id,
+		nil, newAuditWebhook(created)))
+
+	return created, nil
+}
+
+func (s *WebhookService) Webhooks(userID, groupID int) ([]models.Webhook, error) {
+	const op = "service.WebhookService.Webhooks"
+
+	if _, err := ownedGroup(s.groups, userID, groupID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	webhooks, err := s.repo.GroupWebhooks(groupID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return webhooks, nil
+}
+
+// UpdateWebhook меняет адрес, события и состояние вебхука. Так же владелец
+// включает вебхук, отключённый из-за неудачных доставок
+func (s *WebhookService) UpdateWebhook(ctx context.Context, userID int, webhook models.Webhook) error {
+	const op = "service.WebhookService.UpdateWebhook"
+
+	existing, err := s.ownedWebhook(userID, webhook.ID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := validateWebhook(webhook); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.repo.UpdateWebhook(webhook); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	webhook.GroupID = existing.GroupID
+	s.audit.Audit(newAuditEvent(ctx, userID, models.AuditWebhookUpdated, models.AuditTargetWebhook, webhook.ID,
+		newAuditWebhook(existing), newAuditWebhook(webhook)))
+
+	return nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, userID, id int) error {
+	const op = "service.WebhookService.DeleteWebhook"
+
+	existing, err := s.ownedWebhook(userID, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.repo.DeleteWebhook(id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.audit.Audit(newAuditEvent(ctx, userID, models.AuditWebhookDeleted, models.AuditTargetWebhook, id,
+		newAuditWebhook(existing), nil))
+
+	return nil
+}
+
+// WebhookDeliveries возвращает журнал попыток доставки, новые сначала
+func (s *WebhookService) WebhookDeliveries(userID, webhookID, cursor, limit int) ([]models.WebhookDelivery, error) {
+	const op = "service.WebhookService.WebhookDeliveries"
+
+	if _, err := s.ownedWebhook(userID, webhookID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deliveries, err := s.repo.WebhookDeliveries(webhookID, cursor, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+// DispatchWebhook ставит в очередь доставку события на все вебхуки сообщества,
+// подписанные на eventType. Ошибки не прерывают основной сценарий
+func (s *WebhookService) DispatchWebhook(groupID int, eventType string, data any) {
+	webhooks, err := s.repo.SubscribedWebhooks(groupID, eventType)
+	if err != nil {
+		s.logger.Error("failed to load webhooks", slog.Int("group_id", groupID), slog.String("error", err.Error()))
+		return
+	}
+
+	for _, webhook := range webhooks {
+		deliveryID, err := newDeliveryID()
+		if err != nil {
+			s.logger.Error("failed to generate delivery id", slog.String("error", err.Error()))
+			return
+		}
+
+		body, err := json.Marshal(webhookEnvelope{ID: deliveryID, Type: eventType, CreatedAt: time.Now(), Data: data})
+		if err != nil {
+			s.logger.Error("failed to encode webhook payload", slog.String("error", err.Error()))
+			return
+		}
+
+		if _, err := s.queue.Enqueue(jobDeliverWebhook, webhookJob{
+			WebhookID:  webhook.ID,
+			DeliveryID: deliveryID,
+			EventType:  eventType,
+			Body:       body,
+		}); err != nil {
+			s.logger.Error("failed to enqueue webhook delivery",
+				slog.Int("webhook_id", webhook.ID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// deliver отправляет одно событие на вебхук. Неудачная попытка попадает в журнал
+// и возвращает ошибку, чтобы очередь повторила доставку позже
+func (s *WebhookService) deliver(ctx context.Context, job webhookJob) error {
+	webhook, err := s.repo.Webhook(job.WebhookID)
+	if err != nil {
+		if errors.Is(err, storage.ErrWebhookNotFound) {
+			return nil
+		}
+
+		return err
+	}
+	if !webhook.Active || !slices.Contains(webhook.EventTypes, job.EventType) {
+		return nil
+	}
+
+	delivery := models.WebhookDelivery{
+		WebhookID:  webhook.ID,
+		DeliveryID: job.DeliveryID,
+		EventType:  job.EventType,
+		Payload:    job.Body,
+	}
+
+	start := time.Now()
+	delivery.StatusCode, err = s.post(ctx, webhook, job)
+	delivery.Duration = time.Since(start)
+	delivery.Success = err == nil
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	disabled, recordErr := s.repo.RecordDelivery(delivery, s.config.DisableAfter)
+	if recordErr != nil {
+		s.logger.Error("failed to record webhook delivery",
+			slog.Int("webhook_id", webhook.ID),
+			slog.String("error", recordErr.Error()),
+		)
+	}
+	if disabled {
+		s.notifyDisabled(webhook)
+		// Вебхук отключён, повторять доставку незачем
+		return nil
+	}
+
+	return err
+}
+
+// post отправляет запрос, подписанный HMAC-SHA256 от "<timestamp>.<тело>"
+func (s *WebhookService) post(ctx context.Context, webhook models.Webhook, job webhookJob) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(job.Body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "dev-meets-webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, job.EventType)
+	req.Header.Set(WebhookDeliveryHeader, job.DeliveryID)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhook(webhook.Secret, timestamp, job.Body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("%w: %d", errWebhookStatusFailed, resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+func (s *WebhookService) notifyDisabled(webhook models.Webhook) {
+	s.logger.Info("webhook disabled after repeated failures", slog.Int("webhook_id", webhook.ID))
+
+	group, err := s.groups.Group(webhook.GroupID)
+	if err != nil {
+		s.logger.Error("failed to load webhook group", slog.String("error", err.Error()))
+		return
+	}
+
+	if err := s.notifier.Notify(models.Notification{
+		UserID: group.OwnerID,
+		Type:   models.NotificationWebhookDisabled,
+		Title:  fmt.Sprintf("Вебхук сообщества «%s» отключён", group.Name),
+		Body: fmt.Sprintf("%d доставок подряд на %s завершились ошибкой. Проверьте адрес и включите вебхук снова",
+			s.config.DisableAfter, webhook.URL),
+	}); err != nil {
+		s.logger.Error("failed to notify about disabled webhook", slog.String("error", err.Error()))
+	}
+}
+
+// ownedWebhook возвращает вебхук, если userID владеет его сообществом
+func (s *WebhookService) ownedWebhook(userID, id int) (models.Webhook, error) {
+	webhook, err := s.repo.Webhook(id)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	if _, err := ownedGroup(s.groups, userID, webhook.GroupID); err != nil {
+		return models.Webhook{}, err
+	}
+
+	return webhook, nil
+}
+
+// webhookEvent - мероприятие в теле вебхука
+type webhookEvent struct {
+	ID          int        `json:"id"`
+	GroupID     *int       `json:"group_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	City        string     `json:"city"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+}
+
+type webhookEventData struct {
+	Event webhookEvent `json:"event"`
+}
+
+type webhookRSVPData struct {
+	Event webhookEvent `json:"event"`
+	RSVP  struct {
+		UserID    int       `json:"user_id"`
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"rsvp"`
+}
+
+func newWebhookEvent(event models.Event) webhookEvent {
+	return webhookEvent{
+		ID:          event.ID,
+		GroupID:     event.GroupID,
+		Title:       event.Title,
+		Description: event.Description,
+		City:        event.City,
+		StartsAt:    event.StartsAt,
+		EndsAt:      event.EndsAt,
+		CancelledAt: event.CancelledAt,
+	}
+}
+
+// SignWebhook возвращает подпись тела запроса в hex. Получатель вычисляет её тем же
+// способом и сравнивает с заголовком X-DevMeets-Signature
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func validateWebhook(webhook models.Webhook) error {
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidWebhook
+	}
+
+	if len(webhook.EventTypes) == 0 {
+		return ErrInvalidWebhook
+	}
+	for _, eventType := range webhook.EventTypes {
+		if !slices.Contains(models.WebhookEventTypes, eventType) {
+			return ErrInvalidWebhook
+		}
+	}
+
+	return nil
+}
+
+func newDeliveryID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+// newWebhookClient возвращает HTTP-клиент, который не ходит по редиректам и, если
+// это не разрешено явно, не подключается к адресам локальной сети
+func newWebhookClient(config WebhookConfig) *http.Client {
+	dialer := &net.Dialer{Timeout: config.Timeout}
+	if !config.AllowPrivateNetworks {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			ip := net.ParseIP(host)
+			if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+				ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+				return errPrivateWebhookAddr
+			}
+
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout:   config.Timeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"dev_meets/internal/domain/models"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignWebhook(t *testing.T) {
+	const (
+		secret    = "secret"
+		timestamp = "1700000000"
+		body      = `{"id":"1"}`
+		want      = "086f6aff7bd084c98679825129c5a64dbad88c760016d6d2c0fb123f27951d54"
+	)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      string
+		wantEqual bool
+	}{
+		{name: "same input", secret: secret, timestamp: timestamp, body: body, wantEqual: true},
+		{name: "another secret", secret: "other", timestamp: timestamp, body: body},
+		{name: "another timestamp", secret: secret, timestamp: "1700000001", body: body},
+		{name: "another body", secret: secret, timestamp: timestamp, body: `{"id":"2"}`},
+		// точка отделяет время от тела, поэтому сдвиг цифр между ними меняет подпись
+		{name: "shifted boundary", secret: secret, timestamp: "170000000", body: `0{"id":"1"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SignWebhook(tt.secret, tt.timestamp, []byte(tt.body))
+			if (got == want) != tt.wantEqual {
+				t.Errorf("SignWebhook() = %s, equal to %s: %v, want %v", got, want, got == want, tt.wantEqual)
+			}
+		})
+	}
+}
+
+func webhookTarget(url string) models.Webhook {
+	return models.Webhook{ID: 1, URL: url, Secret: "secret", Active: true}
+}
+
+func newTestWebhookService(config WebhookConfig) *WebhookService {
+	return &WebhookService{client: newWebhookClient(config), config: config, logger: testLogger()}
+}
+
+func TestWebhookPostHeaders(t *testing.T) {
+	var received *http.Request
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	s := newTestWebhookService(WebhookConfig{Timeout: time.Second, AllowPrivateNetworks: true})
+	webhook := webhookJob{DeliveryID: "d1", EventType: "event.created", Body: json.RawMessage(`{"id":"d1"}`)}
+
+	status, err := s.post(context.Background(), webhookTarget(server.URL), webhook)
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("post() = %d, %v, want 200", status, err)
+	}
+
+	if got := received.Header.Get(WebhookEventHeader); got != webhook.EventType {
+		t.Errorf("%s = %q, want %q", WebhookEventHeader, got, webhook.EventType)
+	}
+	if got := received.Header.Get(WebhookDeliveryHeader); got != webhook.DeliveryID {
+		t.Errorf("%s = %q, want %q", WebhookDeliveryHeader, got, webhook.DeliveryID)
+	}
+
+	timestamp := received.Header.Get(WebhookTimestampHeader)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(unix, 0)).Abs() > time.Minute {
+		t.Fatalf("%s = %q, want current unix time", WebhookTimestampHeader, timestamp)
+	}
+	want := "sha256=" + SignWebhook("secret", timestamp, receivedBody)
+	if got := received.Header.Get(WebhookSignatureHeader); got != want {
+		t.Errorf("%s = %q, want %q", WebhookSignatureHeader, got, want)
+	}
+	if string(receivedBody) != string(webhook.Body) {
+		t.Errorf("body = %s, want %s", receivedBody, webhook.Body)
+	}
+}
+
+// Адреса локальной сети и облачных метаданных недоступны, если это не разрешено явно
+func TestWebhookClientRefusesPrivateAddresses(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{name: "loopback", url: "http://127.0.0.1:8080/hook"},
+		{name: "loopback v6", url: "http://[::1]:8080/hook"},
+		{name: "unspecified", url: "http://0.0.0.0:8080/hook"},
+		{name: "link-local metadata", url: "http://169.254.169.254/latest/meta-data"},
+		{name: "link-local v6", url: "http://[fe80::1]/hook"},
+		{name: "private 10/8", url: "http://10.0.0.1/hook"},
+		{name: "private 172.16/12", url: "http://172.16.0.1/hook"},
+		{name: "private 192.168/16", url: "http://192.168.1.1/hook"},
+		{name: "unique local v6", url: "http://[fd00::1]/hook"},
+		// адрес проверяется при подключении, после разрешения имени
+		{name: "hostname of loopback", url: "http://localhost:8080/hook"},
+	}
+
+	s := newTestWebhookService(WebhookConfig{Timeout: time.Second})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.post(context.Background(), webhookTarget(tt.url), webhookJob{Body: json.RawMessage(`{}`)})
+			if !errors.Is(err, errPrivateWebhookAddr) {
+				t.Errorf("post(%s) error = %v, want %v", tt.url, err, errPrivateWebhookAddr)
+			}
+		})
+	}
+}
+
+// Публичный адрес может перенаправить запрос во внутреннюю сеть. Клиент не идёт
+// по редиректу, а доставка считается неудачной
+func TestWebhookClientDoesNotFollowRedirects(t *testing.T) {
+	internalHit := false
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		internalHit = true
+	}))
+	defer internal.Close()
+
+	public := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, internal.URL, http.StatusTemporaryRedirect)
+	}))
+	defer public.Close()
+
+	// тестовые серверы слушают loopback, поэтому проверка адресов здесь отключена
+	s := newTestWebhookService(WebhookConfig{Timeout: time.Second, AllowPrivateNetworks: true})
+
+	status, err := s.post(context.Background(), webhookTarget(public.URL), webhookJob{Body: json.RawMessage(`{}`)})
+	if !errors.Is(err, errWebhookStatusFailed) || status != http.StatusTemporaryRedirect {
+		t.Errorf("post() = %d, %v, want %d and %v", status, err, http.StatusTemporaryRedirect, errWebhookStatusFailed)
+	}
+	if internalHit {
+		t.Error("client followed the redirect")
+	}
+}
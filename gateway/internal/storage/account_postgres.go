@@ -0,0 +1,467 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+const (
+	dataExportColumns  = "id, user_id, token, status, created_at, ready_at, expires_at"
+	emailChangeColumns = "id, user_id, new_email, token, created_at, expires_at"
+)
+
+type AccountPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewAccountPostgres(db *sql.DB, logger *slog.Logger) *AccountPostgres {
+	return &AccountPostgres{db: db, log: logger}
+}
+
+// ScheduleDeletion назначает удаление аккаунта на at и отзывает выданные токены
+func (r *AccountPostgres) ScheduleDeletion(userID int, at time.Time, audit models.AuditEvent) error {
+	const op = "repository.AccountPostgres.ScheduleDeletion"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(
+		"UPDATE users SET deletion_scheduled_at = $2, token_version = token_version + 1 "+
+			"WHERE id = $1 AND deleted_at IS NULL",
+		userID, at,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+	}
+
+	if err := insertAuditEvent(tx, audit); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// anonymizeStatements удаляют личные данные пользователя $1. Мероприятия, сообщества,
+// доклады, комментарии, сообщения и отзывы остаются, но больше не указывают на человека
+var anonymizeStatements = []string{
+	// участие и подписки
+	"DELETE FROM rsvps WHERE user_id = $1",
+	"DELETE FROM event_reminders WHERE user_id = $1",
+	"DELETE FROM group_members WHERE user_id = $1 AND role <> 'owner'",
+	"DELETE FROM group_follows WHERE user_id = $1",
+	"DELETE FROM user_follows WHERE follower_id = $1 OR followee_id = $1",
+	"DELETE FROM activities WHERE actor_id = $1",
+	"DELETE FROM feed_dismissals WHERE user_id = $1",
+	"DELETE FROM cfp_reviewers WHERE user_id = $1",
+	"DELETE FROM invitation_acceptances WHERE user_id = $1",
+	"DELETE FROM invitations WHERE invitee_id = $1",
+
+	// уведомления и внешние аккаунты
+	"DELETE FROM notifications WHERE user_id = $1",
+	"DELETE FROM notification_preferences WHERE user_id = $1",
+	"DELETE FROM telegram_accounts WHERE user_id = $1",
+	"DELETE FROM telegram_link_codes WHERE user_id = $1",
+
+	// знакомства и блокировки
+	"DELETE FROM match_profiles WHERE user_id = $1",
+	"DELETE FROM match_requests WHERE requester_id = $1 OR recipient_id = $1",
+	"DELETE FROM user_blocks WHERE blocker_id = $1 OR blocked_id = $1",
+
+	// авторское содержимое остаётся без имени автора
+	"UPDATE feedback SET anonymous = TRUE WHERE user_id = $1",
+	"UPDATE speakers SET user_id = NULL WHERE user_id = $1",
+
+	"DELETE FROM data_exports WHERE user_id = $1",
+	"DELETE FROM email_changes WHERE user_id = $1",
+}
+
+// AnonymizeUser обезличивает аккаунт, удаление которого подошло по сроку: стирает
+// почту, пароль и профиль и удаляет личные данные. Строка пользователя остаётся, чтобы
+// его мероприятия, комментарии и сообщения не пропали у остальных. Если удаление
+// отменено или ещё не наступило, возвращает ErrDeletionNotScheduled
+func (r *AccountPostgres) AnonymizeUser(userID int, audit models.AuditEvent) error {
+	const op = "repository.AccountPostgres.AnonymizeUser"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var email string
+	err = tx.QueryRow("SELECT email FROM users WHERE id = $1 FOR UPDATE", userID).Scan(&email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, ErrDeletionNotScheduled)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// почта должна остаться уникальной, адрес в зоне .invalid никому не принадлежит
+	res, err := tx.Exec(
+		"UPDATE users SET email = 'deleted-' || id || '@deleted.invalid', email_duplicate = FALSE, pass_hash = '', "+
+			"name = '', username = NULL, city = '', bio = '', skills = '{}', seniority = '', role = 'user', "+
+			"token_version = token_version + 1, deletion_scheduled_at = NULL, deleted_at = now() "+
+			"WHERE id = $1 AND deleted_at IS NULL AND deletion_scheduled_at <= now()",
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrDeletionNotScheduled)
+	}
+
+	if _, err := tx.Exec(promoteEmailDuplicateQuery, email); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, statement := range anonymizeStatements {
+		if _, err := tx.Exec(statement, userID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := insertAuditEvent(tx, audit); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UserRSVPs, UserComments и UserMessages собирают данные для выгрузки. Тексты, скрытые
+// модератором, тоже попадают в неё: это данные самого пользователя
+func (r *AccountPostgres) UserRSVPs(userID int) ([]models.RSVP, error) {
+	const op = "repository.AccountPostgres.UserRSVPs"
+
+	rows, err := r.db.Query("SELECT "+rsvpColumns+" FROM rsvps WHERE user_id = $1 ORDER BY id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	rsvps := make([]models.RSVP, 0)
+	for rows.Next() {
+		rsvp, err := scanRSVP(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		rsvps = append(rsvps, rsvp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rsvps, nil
+}
+
+func (r *AccountPostgres) UserComments(userID int) ([]models.Comment, error) {
+	const op = "repository.AccountPostgres.UserComments"
+
+	rows, err := r.db.Query(
+		"SELECT id, subject_type, subject_id, root_id, parent_id, author_id, body, body_html, created_at, edited_at, "+
+			"deleted_at, hidden_at FROM comments WHERE author_id = $1 ORDER BY id",
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	comments := make([]models.Comment, 0)
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return comments, nil
+}
+
+func (r *AccountPostgres) UserMessages(userID int) ([]models.Message, error) {
+	const op = "repository.AccountPostgres.UserMessages"
+
+	rows, err := r.db.Query(
+		"SELECT id, conversation_id, sender_id, body, created_at, hidden_at FROM messages WHERE sender_id = $1 ORDER BY id",
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	messages := make([]models.Message, 0)
+	for rows.Next() {
+		message, err := scanMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return messages, nil
+}
+
+func (r *AccountPostgres) CreateDataExport(userID int, token string) (models.DataExport, error) {
+	const op = "repository.AccountPostgres.CreateDataExport"
+
+	export, err := scanDataExport(r.db.QueryRow(
+		"INSERT INTO data_exports(user_id, token) VALUES($1, $2) RETURNING "+dataExportColumns, userID, token,
+	))
+	if err != nil {
+		return models.DataExport{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return export, nil
+}
+
+// LatestDataExport возвращает последнюю ещё не истёкшую выгрузку пользователя
+func (r *AccountPostgres) LatestDataExport(userID int) (models.DataExport, error) {
+	const op = "repository.AccountPostgres.LatestDataExport"
+
+	export, err := scanDataExport(r.db.QueryRow(
+		"SELECT "+dataExportColumns+" FROM data_exports WHERE user_id = $1 AND (expires_at IS NULL OR expires_at > now()) "+
+			"ORDER BY id DESC LIMIT 1",
+		userID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.DataExport{}, fmt.Errorf("%s: %w", op, ErrDataExportNotFound)
+		}
+
+		return models.DataExport{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return export, nil
+}
+
+// CompleteDataExport сохраняет готовый архив, ссылка на него действует до expiresAt
+func (r *AccountPostgres) CompleteDataExport(id int, archive []byte, expiresAt time.Time) error {
+	const op = "repository.AccountPostgres.CompleteDataExport"
+
+	res, err := r.db.Exec(
+		"UPDATE data_exports SET status = $2, archive = $3, ready_at = now(), expires_at = $4 WHERE id = $1",
+		id, models.DataExportReady, archive, expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrDataExportNotFound)
+	}
+
+	return nil
+}
+
+// DataExportArchive возвращает готовый архив по секрету ссылки, пока она не истекла
+func (r *AccountPostgres) DataExportArchive(token string) (models.DataExport, error) {
+	const op = "repository.AccountPostgres.DataExportArchive"
+
+	var export models.DataExport
+	err := r.db.QueryRow(
+		"SELECT id, user_id, archive, created_at FROM data_exports WHERE token = $1 AND status = $2 AND expires_at > now()",
+		token, models.DataExportReady,
+	).Scan(&export.ID, &export.UserID, &export.Archive, &export.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.DataExport{}, fmt.Errorf("%s: %w", op, ErrDataExportNotFound)
+		}
+
+		return models.DataExport{}, fmt.Errorf("%s: %w", op, err)
+	}
+	export.Token = token
+	export.Status = models.DataExportReady
+
+	return export, nil
+}
+
+// DeleteExpiredDataExports удаляет выгрузки, ссылки на которые истекли
+func (r *AccountPostgres) DeleteExpiredDataExports() (int, error) {
+	const op = "repository.AccountPostgres.DeleteExpiredDataExports"
+
+	res, err := r.db.Exec("DELETE FROM data_exports WHERE expires_at <= now()")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(affected), nil
+}
+
+// CreateEmailChange сохраняет запрошенную смену почты. Незавершённая смена того же
+// пользователя заменяется, и секрет из прежнего письма перестаёт действовать
+func (r *AccountPostgres) CreateEmailChange(change models.EmailChange) (models.EmailChange, error) {
+	const op = "repository.AccountPostgres.CreateEmailChange"
+
+	change, err := scanEmailChange(r.db.QueryRow(
+		"INSERT INTO email_changes(user_id, new_email, token, expires_at) VALUES($1, $2, $3, $4) "+
+			"ON CONFLICT (user_id) DO UPDATE SET new_email = EXCLUDED.new_email, token = EXCLUDED.token, "+
+			"created_at = now(), expires_at = EXCLUDED.expires_at RETURNING "+emailChangeColumns,
+		change.UserID, change.NewEmail, change.Token, change.ExpiresAt,
+	))
+	if err != nil {
+		return models.EmailChange{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return change, nil
+}
+
+// EmailChange возвращает смену почты по секрету из письма, пока он не истёк
+func (r *AccountPostgres) EmailChange(token string) (models.EmailChange, error) {
+	const op = "repository.AccountPostgres.EmailChange"
+
+	change, err := scanEmailChange(r.db.QueryRow(
+		"SELECT "+emailChangeColumns+" FROM email_changes WHERE token = $1 AND expires_at > now()", token,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.EmailChange{}, fmt.Errorf("%s: %w", op, ErrEmailChangeNotFound)
+		}
+
+		return models.EmailChange{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return change, nil
+}
+
+// CompleteEmailChange погашает секрет, меняет почту и отзывает выданные токены.
+// Адрес могли занять после запроса смены: тогда уникальный индекс users.email
+// откатывает транзакцию, секрет остаётся непогашенным, а возвращается ErrEmailTaken
+func (r *AccountPostgres) CompleteEmailChange(change models.EmailChange, audit models.AuditEvent) error {
+	const op = "repository.AccountPostgres.CompleteEmailChange"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(
+		"DELETE FROM email_changes WHERE id = $1 AND token = $2 AND expires_at > now()", change.ID, change.Token,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrEmailChangeNotFound)
+	}
+
+	var previous string
+	err = tx.QueryRow(
+		"SELECT email FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", change.UserID,
+	).Scan(&previous)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// с новым адресом аккаунт перестаёт быть дубликатом
+	_, err = tx.Exec(
+		"UPDATE users SET email = lower($2), email_duplicate = FALSE, token_version = token_version + 1 WHERE id = $1",
+		change.UserID, change.NewEmail,
+	)
+	if err != nil {
+		var pgsErr *pq.Error
+		if errors.As(err, &pgsErr) && pgsErr.Code.Name() == "unique_violation" {
+			return fmt.Errorf("%s: %w", op, ErrEmailTaken)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.Exec(promoteEmailDuplicateQuery, previous); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := insertAuditEvent(tx, audit); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func scanEmailChange(row rowScanner) (models.EmailChange, error) {
+	var change models.EmailChange
+
+	err := row.Scan(&change.ID, &change.UserID, &change.NewEmail, &change.Token, &change.CreatedAt, &change.ExpiresAt)
+	if err != nil {
+		return models.EmailChange{}, err
+	}
+
+	return change, nil
+}
+
+func scanDataExport(row rowScanner) (models.DataExport, error) {
+	var export models.DataExport
+	var readyAt, expiresAt sql.NullTime
+
+	err := row.Scan(&export.ID, &export.UserID, &export.Token, &export.Status, &export.CreatedAt, &readyAt, &expiresAt)
+	if err != nil {
+		return models.DataExport{}, err
+	}
+
+	if readyAt.Valid {
+		export.ReadyAt = &readyAt.Time
+	}
+	if expiresAt.Valid {
+		export.ExpiresAt = &expiresAt.Time
+	}
+
+	return export, nil
+}
@@ -0,0 +1,372 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// suspendedExpr - блокировка пользователя действует сейчас
+const suspendedExpr = "(suspended_at IS NOT NULL AND (suspended_until IS NULL OR suspended_until > now()))"
+
+type AdminPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewAdminPostgres(db *sql.DB, logger *slog.Logger) *AdminPostgres {
+	return &AdminPostgres{db: db, log: logger}
+}
+
+func (r *AdminPostgres) SearchUsers(filter models.AdminUserFilter) ([]models.User, error) {
+	const op = "repository.AdminPostgres.SearchUsers"
+
+	where := make([]string, 0, 4)
+	args := make([]any, 0, 5)
+	if filter.Query != "" {
+		args = append(args, "%"+escapeLike(filter.Query)+"%", filter.Query)
+		where = append(where, fmt.Sprintf(
+			"(email ILIKE $%[1]d OR name ILIKE $%[1]d OR username ILIKE $%[1]d OR id::text = $%[2]d)",
+			len(args)-1, len(args),
+		))
+	}
+	if filter.Role != "" {
+		args = append(args, filter.Role)
+		where = append(where, fmt.Sprintf("role = $%d", len(args)))
+	}
+	if filter.Suspended != nil {
+		args = append(args, *filter.Suspended)
+		where = append(where, fmt.Sprintf("%s = $%d", suspendedExpr, len(args)))
+	}
+	order := "id"
+	if filter.DuplicateEmail {
+		// аккаунты с одной почтой в разном регистре идут подряд, чтобы их было удобно объединять
+		where = append(where,
+			"EXISTS (SELECT 1 FROM users d WHERE d.id <> users.id AND lower(d.email) = lower(users.email))")
+		order = "lower(email), id"
+	}
+
+	query := "SELECT " + userColumns + " FROM users"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d OFFSET $%d", order, len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	users := make([]models.User, 0)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+func (r *AdminPostgres) AccountState(userID int) (models.AccountState, error) {
+	const op = "repository.AdminPostgres.AccountState"
+
+	user, err := scanUser(r.db.QueryRow("SELECT "+userColumns+" FROM users WHERE id = $1", userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.AccountState{}, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		return models.AccountState{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	state := models.AccountState{User: user}
+	err = r.db.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM telegram_accounts WHERE user_id = $1), "+
+			"(SELECT count(*) FROM events WHERE organizer_id = $1), "+
+			"(SELECT count(*) FROM groups WHERE owner_id = $1), "+
+			"(SELECT count(*) FROM reports WHERE target_user_id = $1 AND status = $2), "+
+			"(SELECT count(*) FROM moderation_actions WHERE target_user_id = $1)",
+		userID, models.ReportOpen,
+	).Scan(&state.TelegramLinked, &state.EventsOrganized, &state.GroupsOwned, &state.OpenReports, &state.ModerationActions)
+	if err != nil {
+		return models.AccountState{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return state, nil
+}
+
+// SetUserRole меняет роль пользователя и записывает изменение в журнал аудита
+func (r *AdminPostgres) SetUserRole(userID int, role string, audit models.AuditEvent) error {
+	const op = "repository.AdminPostgres.SetUserRole"
+
+	err := r.updateUser(audit, "UPDATE users SET role = $2 WHERE id = $1", userID, role)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SuspendUser блокирует пользователя до until, nil - бессрочно
+func (r *AdminPostgres) SuspendUser(userID int, until *time.Time, reason string, audit models.AuditEvent) error {
+	const op = "repository.AdminPostgres.SuspendUser"
+
+	err := r.updateUser(audit,
+		"UPDATE users SET suspended_at = now(), suspended_until = $2, suspension_reason = $3 WHERE id = $1",
+		userID, until, reason,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *AdminPostgres) UnsuspendUser(userID int, audit models.AuditEvent) error {
+	const op = "repository.AdminPostgres.UnsuspendUser"
+
+	err := r.updateUser(audit,
+		"UPDATE users SET suspended_at = NULL, suspended_until = NULL, suspension_reason = '' WHERE id = $1", userID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RevokeSessions увеличивает версию токенов пользователя: все выданные ранее токены
+// перестают приниматься
+func (r *AdminPostgres) RevokeSessions(userID int, audit models.AuditEvent) error {
+	const op = "repository.AdminPostgres.RevokeSessions"
+
+	err := r.updateUser(audit, "UPDATE users SET token_version = token_version + 1 WHERE id = $1", userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// updateUser выполняет изменение пользователя и запись аудита в одной транзакции
+func (r *AdminPostgres) updateUser(audit models.AuditEvent, query string, args ...any) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrUserNotFound
+	}
+
+	if err := insertAuditEvent(tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// mergeStatements переносят данные пользователя $2 на пользователя $1. Строки, которые
+// нарушили бы уникальность (оба состоят в одном сообществе, оба записаны на одно
+// мероприятие и т.п.), остаются у $2 и удаляются вместе с ним
+var mergeStatements = []string{
+	// мероприятия, доклады и программа
+	"UPDATE events SET organizer_id = $1 WHERE organizer_id = $2",
+	"UPDATE talks SET speaker_id = $1 WHERE speaker_id = $2",
+	"UPDATE cfp_reviewers r SET user_id = $1 WHERE user_id = $2 AND NOT EXISTS " +
+		"(SELECT 1 FROM cfp_reviewers o WHERE o.event_id = r.event_id AND o.user_id = $1)",
+	"UPDATE talk_reviews r SET reviewer_id = $1 WHERE reviewer_id = $2 AND NOT EXISTS " +
+		"(SELECT 1 FROM talk_reviews o WHERE o.talk_id = r.talk_id AND o.reviewer_id = $1)",
+	"UPDATE speakers SET user_id = $1 WHERE user_id = $2 AND NOT EXISTS (SELECT 1 FROM speakers WHERE user_id = $1)",
+	"UPDATE speakers SET created_by = $1 WHERE created_by = $2",
+	"UPDATE venues SET created_by = $1 WHERE created_by = $2",
+
+	// сообщества: если оба состоят в сообществе, владение переходит к $1
+	"UPDATE groups SET owner_id = $1 WHERE owner_id = $2",
+	"UPDATE group_members m SET role = s.role FROM group_members s " +
+		"WHERE m.user_id = $1 AND s.user_id = $2 AND s.group_id = m.group_id AND s.role = 'owner'",
+	"UPDATE group_members m SET user_id = $1 WHERE user_id = $2 AND NOT EXISTS " +
+		"(SELECT 1 FROM group_members o WHERE o.group_id = m.group_id AND o.user_id = $1)",
+	"UPDATE group_follows f SET user_id = $1 WHERE user_id = $2 AND NOT EXISTS " +
+		"(SELECT 1 FROM group_follows o WHERE o.group_id = f.group_id AND o.user_id = $1)",
+
+	// участие в мероприятиях
+	"UPDATE rsvps r SET user_id = $1 WHERE user_id = $2 AND NOT EXISTS " +
+		"(SELECT 1 FROM rsvps o WHERE o.event_id = r.event_id AND o.user_id = $1)",
+	"UPDATE rsvps SET checked_in_by = $1 WHERE checked_in_by = $2",
+	"UPDATE event_reminders r SET user_id = $1 WHERE user_id = $2 AND NOT EXISTS " +
+		"(SELECT 1 FROM event_reminders o WHERE o.event_id = r.event_id AND o.user_id = $1 " +
+		"AND o.offset_minutes = r.offset_minutes)",
+	"UPDATE feedback f SET user_id = $1 WHERE user_id = $2 AND NOT EXISTS " +
+		"(SELECT 1 FROM feedback o WHERE o.event_id = f.event_id AND o.slot_id IS NOT DISTINCT FROM f.slot_id " +
+		"AND o.user_id = $1)",
+	"UPDATE feed_dismissals d SET user_id = $1 WHERE user_id = $2 AND NOT EXISTS " +
+		"(SELECT 1 FROM feed_dismissals o WHERE o.event_id = d.event_id AND o.user_id = $1)",
+	"UPDATE comments SET author_id = $1 WHERE author_id = $2",
+
+	// приглашения
+	"UPDATE invitations SET inviter_id = $1 WHERE inviter_id = $2",
+	"UPDATE invitations SET invitee_id = $1 WHERE invitee_id = $2",
+	"UPDATE invitation_acceptances a SET user_id = $1 WHERE user_id = $2 AND NOT EXISTS " +
+		"(SELECT 1 FROM invitation_acceptances o WHERE o.invitation_id = a.invitation_id AND o.user_id = $1)",
+
+	// заказы: действующий заказ на мероприятие у пользователя может быть только один
+	"UPDATE orders r SET user_id = $1 WHERE user_id = $2 AND (r.status NOT IN ('pending', 'paid') OR NOT EXISTS " +
+		"(SELECT 1 FROM orders o WHERE o.event_id = r.event_id AND o.user_id = $1 AND o.status IN ('pending', 'paid')))",
+
+	// уведомления: непрочитанные с тем же ключом уже склеены у $1
+	"UPDATE notifications n SET user_id = $1 WHERE user_id = $2 AND (n.read_at IS NOT NULL OR n.group_key = '' " +
+		"OR NOT EXISTS (SELECT 1 FROM notifications o WHERE o.user_id = $1 AND o.group_key = n.group_key " +
+		"AND o.read_at IS NULL))",
+	"UPDATE notification_preferences p SET user_id = $1 WHERE user_id = $2 AND NOT EXISTS " +
+		"(SELECT 1 FROM notification_preferences o WHERE o.type = p.type AND o.user_id = $1)",
+	"UPDATE telegram_accounts SET user_id = $1 WHERE user_id = $2 AND NOT EXISTS " +
+		"(SELECT 1 FROM telegram_accounts WHERE user_id = $1)",
+
+	// личные диалоги: диалог $1 и $2 удаляется, диалоги $2 с теми, с кем у $1 уже есть
+	// диалог, вливаются в него, остальные получают ключ $1
+	"DELETE FROM conversations WHERE direct_key = LEAST($1::int, $2::int) || ':' || GREATEST($1::int, $2::int)",
+	"UPDATE conversations t SET last_message_at = GREATEST(t.last_message_at, c.last_message_at) " +
+		"FROM conversations c, conversation_members s, conversation_members o " +
+		"WHERE c.kind = 'direct' AND s.conversation_id = c.id AND s.user_id = $2 AND o.conversation_id = c.id " +
+		"AND o.user_id <> $2 AND t.direct_key = LEAST($1::int, o.user_id) || ':' || GREATEST($1::int, o.user_id)",
+	"UPDATE messages m SET conversation_id = t.id " +
+		"FROM conversations c, conversation_members s, conversation_members o, conversations t " +
+		"WHERE m.conversation_id = c.id AND c.kind = 'direct' AND s.conversation_id = c.id AND s.user_id = $2 " +
+		"AND o.conversation_id = c.id AND o.user_id <> $2 " +
+		"AND t.direct_key = LEAST($1::int, o.user_id) || ':' || GREATEST($1::int, o.user_id)",
+	"DELETE FROM conversations c USING conversation_members s, conversation_members o, conversations t " +
+		"WHERE c.kind = 'direct' AND s.conversation_id = c.id AND s.user_id = $2 " +
+		"AND o.conversation_id = c.id AND o.user_id <> $2 " +
+		"AND t.direct_key = LEAST($1::int, o.user_id) || ':' || GREATEST($1::int, o.user_id)",
+	"UPDATE conversations c SET direct_key = LEAST($1::int, o.user_id) || ':' || GREATEST($1::int, o.user_id) " +
+		"FROM conversation_members s, conversation_members o " +
+		"WHERE c.kind = 'direct' AND s.conversation_id = c.id AND s.user_id = $2 " +
+		"AND o.conversation_id = c.id AND o.user_id <> $2",
+	"UPDATE conversation_members m SET user_id = $1 WHERE user_id = $2 AND NOT EXISTS " +
+		"(SELECT 1 FROM conversation_members o WHERE o.conversation_id = m.conversation_id AND o.user_id = $1)",
+	"UPDATE conversations SET created_by = $1 WHERE created_by = $2",
+	"UPDATE messages SET sender_id = $1 WHERE sender_id = $2",
+
+	// связи между $1 и $2 теряют смысл
+	"DELETE FROM user_blocks WHERE blocker_id IN ($1, $2) AND blocked_id IN ($1, $2)",
+	"UPDATE user_blocks b SET blocker_id = $1 WHERE blocker_id = $2 AND NOT EXISTS " +
+		"(SELECT 1 FROM user_blocks o WHERE o.blocker_id = $1 AND o.blocked_id = b.blocked_id)",
+	"UPDATE user_blocks b SET blocked_id = $1 WHERE blocked_id = $2 AND NOT EXISTS " +
+		"(SELECT 1 FROM user_blocks o WHERE o.blocked_id = $1 AND o.blocker_id = b.blocker_id)",
+	"DELETE FROM user_follows WHERE follower_id IN ($1, $2) AND followee_id IN ($1, $2)",
+	"UPDATE user_follows f SET follower_id = $1 WHERE follower_id = $2 AND NOT EXISTS " +
+		"(SELECT 1 FROM user_follows o WHERE o.follower_id = $1 AND o.followee_id = f.followee_id)",
+	"UPDATE user_follows f SET followee_id = $1 WHERE followee_id = $2 AND NOT EXISTS " +
+		"(SELECT 1 FROM user_follows o WHERE o.followee_id = $1 AND o.follower_id = f.follower_id)",
+	"UPDATE activities SET actor_id = $1 WHERE actor_id = $2",
+
+	// знакомства
+	"UPDATE match_profiles SET user_id = $1 WHERE user_id = $2 AND NOT EXISTS " +
+		"(SELECT 1 FROM match_profiles WHERE user_id = $1)",
+	"DELETE FROM match_requests WHERE requester_id IN ($1, $2) AND recipient_id IN ($1, $2)",
+	"UPDATE match_requests r SET requester_id = $1 WHERE requester_id = $2 AND (r.status <> 'pending' " +
+		"OR NOT EXISTS (SELECT 1 FROM match_requests o WHERE o.requester_id = $1 AND o.recipient_id = r.recipient_id " +
+		"AND o.status = 'pending'))",
+	"UPDATE match_requests r SET recipient_id = $1 WHERE recipient_id = $2 AND (r.status <> 'pending' " +
+		"OR NOT EXISTS (SELECT 1 FROM match_requests o WHERE o.recipient_id = $1 AND o.requester_id = r.requester_id " +
+		"AND o.status = 'pending'))",
+
+	// модерация: жалобы и журнал следуют за пользователем
+	"UPDATE reports r SET reporter_id = $1 WHERE reporter_id = $2 AND (r.status <> 'open' " +
+		"OR NOT EXISTS (SELECT 1 FROM reports o WHERE o.reporter_id = $1 AND o.target_type = r.target_type " +
+		"AND o.target_id = r.target_id AND o.status = 'open'))",
+	"UPDATE reports SET target_user_id = $1 WHERE target_user_id = $2",
+	"UPDATE reports SET resolved_by = $1 WHERE resolved_by = $2",
+	"UPDATE moderation_actions SET moderator_id = $1 WHERE moderator_id = $2",
+	"UPDATE moderation_actions SET target_user_id = $1 WHERE target_user_id = $2",
+}
+
+// MergeUsers переносит данные дубликата sourceID на аккаунт targetID и удаляет дубликат.
+// Пустые поля профиля targetID заполняются из дубликата, навыки объединяются.
+// Почта, пароль, роль и блокировка targetID не меняются
+func (r *AdminPostgres) MergeUsers(targetID, sourceID int, audit models.AuditEvent) error {
+	const op = "repository.AdminPostgres.MergeUsers"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// блокируем оба аккаунта в одном порядке, чтобы встречные слияния не зависли
+	var locked int
+	err = tx.QueryRow(
+		"SELECT count(*) FROM (SELECT id FROM users WHERE id IN ($1, $2) ORDER BY id FOR UPDATE) u", targetID, sourceID,
+	).Scan(&locked)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if locked != 2 {
+		return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+	}
+
+	source, err := scanUser(tx.QueryRow("SELECT "+userColumns+" FROM users WHERE id = $1", sourceID))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, statement := range mergeStatements {
+		if _, err := tx.Exec(statement, targetID, sourceID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	// остатки дубликата удаляются каскадом; username освобождается до обновления профиля
+	if _, err := tx.Exec("DELETE FROM users WHERE id = $1", sourceID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err := tx.Exec(promoteEmailDuplicateQuery, source.Email); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(
+		"UPDATE users SET name = CASE WHEN name = '' THEN $2 ELSE name END, "+
+			"username = COALESCE(username, NULLIF($3, '')), "+
+			"city = CASE WHEN city = '' THEN $4 ELSE city END, "+
+			"bio = CASE WHEN bio = '' THEN $5 ELSE bio END, "+
+			"skills = ARRAY(SELECT s FROM unnest(skills || $6::text[]) WITH ORDINALITY u(s, n) GROUP BY s ORDER BY min(n)), "+
+			"seniority = CASE WHEN seniority = '' THEN $7 ELSE seniority END "+
+			"WHERE id = $1",
+		targetID, source.Name, source.Username, source.City, source.Bio, pq.Array(source.Skills), source.Seniority,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := insertAuditEvent(tx, audit); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
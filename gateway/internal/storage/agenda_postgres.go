@@ -0,0 +1,338 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"log/slog"
+)
+
+type AgendaPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewAgendaPostgres(db *sql.DB, logger *slog.Logger) *AgendaPostgres {
+	return &AgendaPostgres{db: db, log: logger}
+}
+
+func (r *AgendaPostgres) Agenda(eventID int) ([]models.AgendaSlot, error) {
+	const op = "repository.AgendaPostgres.Agenda"
+
+	rows, err := r.db.Query(
+		"SELECT id, event_id, position, kind, talk_id, title, description, starts_at, ends_at, slides_url, video_url "+
+			"FROM agenda_slots WHERE event_id = $1 ORDER BY position",
+		eventID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	slots := make([]models.AgendaSlot, 0)
+	index := make(map[int]int)
+	for rows.Next() {
+		slot, err := scanSlot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		index[slot.ID] = len(slots)
+		slots = append(slots, slot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	speakerRows, err := r.db.Query(
+		"SELECT ss.slot_id, s.id, s.user_id, s.name, s.bio, s.photo_url, COALESCE(s.created_by, 0) "+
+			"FROM agenda_slot_speakers ss "+
+			"JOIN speakers s ON s.id = ss.speaker_id "+
+			"JOIN agenda_slots a ON a.id = ss.slot_id "+
+			"WHERE a.event_id = $1 ORDER BY ss.slot_id, ss.position, s.id",
+		eventID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer speakerRows.Close()
+
+	for speakerRows.Next() {
+		var slotID int
+		var speaker models.Speaker
+		var userID sql.NullInt64
+		if err := speakerRows.Scan(&slotID, &speaker.ID, &userID, &speaker.Name, &speaker.Bio, &speaker.PhotoURL, &speaker.CreatedBy); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if userID.Valid {
+			uid := int(userID.Int64)
+			speaker.UserID = &uid
+		}
+		if i, ok := index[slotID]; ok {
+			slots[i].Speakers = append(slots[i].Speakers, speaker)
+		}
+	}
+	if err := speakerRows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return slots, nil
+}
+
+func (r *AgendaPostgres) Slot(id int) (models.AgendaSlot, error) {
+	const op = "repository.AgendaPostgres.Slot"
+
+	row := r.db.QueryRow(
+		"SELECT id, event_id, position, kind, talk_id, title, description, starts_at, ends_at, slides_url, video_url "+
+			"FROM agenda_slots WHERE id = $1",
+		id,
+	)
+
+	slot, err := scanSlot(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.AgendaSlot{}, fmt.Errorf("%s: %w", op, ErrSlotNotFound)
+		}
+
+		return models.AgendaSlot{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return slot, nil
+}
+
+// IsSlotSpeaker проверяет, выступает ли пользователь в слоте программы
+func (r *AgendaPostgres) IsSlotSpeaker(slotID, userID int) (bool, error) {
+	const op = "repository.AgendaPostgres.IsSlotSpeaker"
+
+	var exists bool
+	err := r.db.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM agenda_slot_speakers ss JOIN speakers s ON s.id = ss.speaker_id "+
+			"WHERE ss.slot_id = $1 AND s.user_id = $2)",
+		slotID, userID,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return exists, nil
+}
+
+// CreateSlot добавляет слот в конец программы мероприятия
+func (r *AgendaPostgres) CreateSlot(slot models.AgendaSlot, speakerIDs []int) (int, error) {
+	const op = "repository.AgendaPostgres.CreateSlot"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SELECT id FROM events WHERE id = $1 FOR UPDATE", slot.EventID); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var id int
+	err = tx.QueryRow(
+		"INSERT INTO agenda_slots(event_id, position, kind, talk_id, title, description, starts_at, ends_at, slides_url, video_url) "+
+			"SELECT $1, COALESCE(MAX(position), 0) + 1, $2, $3, $4, $5, $6, $7, $8, $9 FROM agenda_slots WHERE event_id = $1 "+
+			"RETURNING id",
+		slot.EventID, slot.Kind, slot.TalkID, slot.Title, slot.Description, slot.StartsAt, slot.EndsAt, slot.SlidesURL, slot.VideoURL,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := setSlotSpeakers(tx, id, speakerIDs); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// UpdateSlot обновляет слот. Если speakerIDs != nil, список докладчиков слота заменяется
+func (r *AgendaPostgres) UpdateSlot(slot models.AgendaSlot, speakerIDs []int) error {
+	const op = "repository.AgendaPostgres.UpdateSlot"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(
+		"UPDATE agenda_slots SET position = $1, kind = $2, title = $3, description = $4, starts_at = $5, ends_at = $6, "+
+			"slides_url = $7, video_url = $8 WHERE id = $9 AND event_id = $10",
+		slot.Position, slot.Kind, slot.Title, slot.Description, slot.StartsAt, slot.EndsAt,
+		slot.SlidesURL, slot.VideoURL, slot.ID, slot.EventID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrSlotNotFound)
+	}
+
+	if speakerIDs != nil {
+		if _, err := tx.Exec("DELETE FROM agenda_slot_speakers WHERE slot_id = $1", slot.ID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := setSlotSpeakers(tx, slot.ID, speakerIDs); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *AgendaPostgres) DeleteSlot(eventID, slotID int) error {
+	const op = "repository.AgendaPostgres.DeleteSlot"
+
+	res, err := r.db.Exec("DELETE FROM agenda_slots WHERE id = $1 AND event_id = $2", slotID, eventID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrSlotNotFound)
+	}
+
+	return nil
+}
+
+func (r *AgendaPostgres) CreateSpeaker(speaker models.Speaker) (int, error) {
+	const op = "repository.AgendaPostgres.CreateSpeaker"
+
+	var id int
+	err := r.db.QueryRow(
+		"INSERT INTO speakers(user_id, name, bio, photo_url, created_by) VALUES($1, $2, $3, $4, $5) RETURNING id",
+		speaker.UserID, speaker.Name, speaker.Bio, speaker.PhotoURL, speaker.CreatedBy,
+	).Scan(&id)
+	if err != nil {
+		var pgsErr *pq.Error
+		if errors.As(err, &pgsErr) {
+			switch pgsErr.Code.Name() {
+			case "unique_violation":
+				return 0, fmt.Errorf("%s: %w", op, ErrSpeakerExists)
+			case "foreign_key_violation":
+				return 0, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+			}
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *AgendaPostgres) Speaker(id int) (models.Speaker, error) {
+	const op = "repository.AgendaPostgres.Speaker"
+	var speaker models.Speaker
+	var userID sql.NullInt64
+
+	err := r.db.QueryRow(
+		"SELECT id, user_id, name, bio, photo_url, COALESCE(created_by, 0) FROM speakers WHERE id = $1", id,
+	).Scan(&speaker.ID, &userID, &speaker.Name, &speaker.Bio, &speaker.PhotoURL, &speaker.CreatedBy)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Speaker{}, fmt.Errorf("%s: %w", op, ErrSpeakerNotFound)
+		}
+
+		return models.Speaker{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if userID.Valid {
+		uid := int(userID.Int64)
+		speaker.UserID = &uid
+	}
+
+	return speaker, nil
+}
+
+func (r *AgendaPostgres) UpdateSpeaker(speaker models.Speaker) error {
+	const op = "repository.AgendaPostgres.UpdateSpeaker"
+
+	res, err := r.db.Exec(
+		"UPDATE speakers SET name = $1, bio = $2, photo_url = $3 WHERE id = $4",
+		speaker.Name, speaker.Bio, speaker.PhotoURL, speaker.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrSpeakerNotFound)
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSlot(row rowScanner) (models.AgendaSlot, error) {
+	var slot models.AgendaSlot
+	var talkID sql.NullInt64
+	var startsAt, endsAt sql.NullTime
+
+	err := row.Scan(&slot.ID, &slot.EventID, &slot.Position, &slot.Kind, &talkID, &slot.Title, &slot.Description,
+		&startsAt, &endsAt, &slot.SlidesURL, &slot.VideoURL)
+	if err != nil {
+		return models.AgendaSlot{}, err
+	}
+
+	if talkID.Valid {
+		id := int(talkID.Int64)
+		slot.TalkID = &id
+	}
+	if startsAt.Valid {
+		slot.StartsAt = &startsAt.Time
+	}
+	if endsAt.Valid {
+		slot.EndsAt = &endsAt.Time
+	}
+
+	return slot, nil
+}
+
+func setSlotSpeakers(tx *sql.Tx, slotID int, speakerIDs []int) error {
+	for i, speakerID := range speakerIDs {
+		_, err := tx.Exec(
+			"INSERT INTO agenda_slot_speakers(slot_id, speaker_id, position) VALUES($1, $2, $3) ON CONFLICT DO NOTHING",
+			slotID, speakerID, i,
+		)
+		if err != nil {
+			var pgsErr *pq.Error
+			if errors.As(err, &pgsErr) && pgsErr.Code.Name() == "foreign_key_violation" {
+				return ErrSpeakerNotFound
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const auditEventColumns = "id, actor_id, action, target_type, COALESCE(target_id, 0), before, after, ip, request_id, created_at"
+
+const insertAuditEventQuery = "INSERT INTO audit_events(actor_id, action, target_type, target_id, before, after, ip, " +
+	"request_id) VALUES($1, $2, $3, NULLIF($4, 0), $5, $6, $7, $8)"
+
+type AuditPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewAuditPostgres(db *sql.DB, logger *slog.Logger) *AuditPostgres {
+	return &AuditPostgres{db: db, log: logger}
+}
+
+func (r *AuditPostgres) CreateAuditEvent(event models.AuditEvent) error {
+	const op = "repository.AuditPostgres.CreateAuditEvent"
+
+	if _, err := r.db.Exec(insertAuditEventQuery, auditEventArgs(event)...); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AuditEvents возвращает записи журнала аудита, новые сначала
+func (r *AuditPostgres) AuditEvents(filter models.AuditFilter) ([]models.AuditEvent, error) {
+	const op = "repository.AuditPostgres.AuditEvents"
+
+	where := make([]string, 0, 7)
+	args := make([]any, 0, 8)
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.ActorID != 0 {
+		add("actor_id = $%d", filter.ActorID)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.TargetType != "" {
+		add("target_type = $%d", filter.TargetType)
+	}
+	if filter.TargetID != 0 {
+		add("target_id = $%d", filter.TargetID)
+	}
+	if filter.From != nil {
+		add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("created_at < $%d", *filter.To)
+	}
+	if filter.Cursor != 0 {
+		add("id < $%d", filter.Cursor)
+	}
+
+	query := "SELECT " + auditEventColumns + " FROM audit_events"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	events := make([]models.AuditEvent, 0)
+	for rows.Next() {
+		var event models.AuditEvent
+		var actorID sql.NullInt64
+		var before, after []byte
+		err := rows.Scan(&event.ID, &actorID, &event.Action, &event.TargetType, &event.TargetID, &before, &after,
+			&event.IP, &event.RequestID, &event.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if actorID.Valid {
+			id := int(actorID.Int64)
+			event.ActorID = &id
+		}
+		event.Before = before
+		event.After = after
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
+
+// DeleteAuditEventsBefore удаляет записи старше срока хранения
+func (r *AuditPostgres) DeleteAuditEventsBefore(before time.Time) (int, error) {
+	const op = "repository.AuditPostgres.DeleteAuditEventsBefore"
+
+	res, err := r.db.Exec("DELETE FROM audit_events WHERE created_at < $1", before)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(affected), nil
+}
+
+// insertAuditEvent записывает аудит в транзакции изменения, чтобы изменение
+// не прошло без записи в журнале
+func insertAuditEvent(tx *sql.Tx, event models.AuditEvent) error {
+	_, err := tx.Exec(insertAuditEventQuery, auditEventArgs(event)...)
+
+	return err
+}
+
+func auditEventArgs(event models.AuditEvent) []any {
+	return []any{
+		event.ActorID, event.Action, event.TargetType, event.TargetID, nullJSON(event.Before), nullJSON(event.After),
+		event.IP, event.RequestID,
+	}
+}
+
+// nullJSON передаёт пустой JSON как NULL
+func nullJSON(data []byte) any {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return data
+}
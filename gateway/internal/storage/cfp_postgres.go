@@ -0,0 +1,296 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"log/slog"
+)
+
+type CFPPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewCFPPostgres(db *sql.DB, logger *slog.Logger) *CFPPostgres {
+	return &CFPPostgres{db: db, log: logger}
+}
+
+func (r *CFPPostgres) CreateCFP(cfp models.CallForPapers) error {
+	const op = "repository.CFPPostgres.CreateCFP"
+
+	_, err := r.db.Exec(
+		"INSERT INTO call_for_papers(event_id, description, deadline) VALUES($1, $2, $3)",
+		cfp.EventID, cfp.Description, cfp.Deadline,
+	)
+	if err != nil {
+		var pgsErr *pq.Error
+		if errors.As(err, &pgsErr) && pgsErr.Code.Name() == "unique_violation" {
+			return fmt.Errorf("%s: %w", op, ErrCFPExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *CFPPostgres) CFP(eventID int) (models.CallForPapers, error) {
+	const op = "repository.CFPPostgres.CFP"
+	var cfp models.CallForPapers
+
+	err := r.db.QueryRow(
+		"SELECT event_id, description, deadline, created_at FROM call_for_papers WHERE event_id = $1", eventID,
+	).Scan(&cfp.EventID, &cfp.Description, &cfp.Deadline, &cfp.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.CallForPapers{}, fmt.Errorf("%s: %w", op, ErrCFPNotFound)
+		}
+
+		return models.CallForPapers{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return cfp, nil
+}
+
+func (r *CFPPostgres) AddReviewer(eventID, userID int) error {
+	const op = "repository.CFPPostgres.AddReviewer"
+
+	_, err := r.db.Exec(
+		"INSERT INTO cfp_reviewers(event_id, user_id) VALUES($1, $2) ON CONFLICT DO NOTHING", eventID, userID,
+	)
+	if err != nil {
+		var pgsErr *pq.Error
+		if errors.As(err, &pgsErr) && pgsErr.Code.Name() == "foreign_key_violation" {
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *CFPPostgres) IsReviewer(eventID, userID int) (bool, error) {
+	const op = "repository.CFPPostgres.IsReviewer"
+
+	var exists bool
+	err := r.db.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM cfp_reviewers WHERE event_id = $1 AND user_id = $2)", eventID, userID,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return exists, nil
+}
+
+func (r *CFPPostgres) CreateTalk(talk models.Talk) (int, error) {
+	const op = "repository.CFPPostgres.CreateTalk"
+
+	var id int
+	err := r.db.QueryRow(
+		"INSERT INTO talks(event_id, speaker_id, title, abstract, level, duration, tags) "+
+			"VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id",
+		talk.EventID, talk.SpeakerID, talk.Title, talk.Abstract, talk.Level, talk.Duration, pq.Array(talk.Tags),
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *CFPPostgres) Talk(id int) (models.Talk, error) {
+	const op = "repository.CFPPostgres.Talk"
+	var talk models.Talk
+
+	err := r.db.QueryRow(
+		"SELECT id, event_id, speaker_id, title, abstract, level, duration, tags, status, created_at FROM talks WHERE id = $1", id,
+	).Scan(&talk.ID, &talk.EventID, &talk.SpeakerID, &talk.Title, &talk.Abstract, &talk.Level,
+		&talk.Duration, pq.Array(&talk.Tags), &talk.Status, &talk.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Talk{}, fmt.Errorf("%s: %w", op, ErrTalkNotFound)
+		}
+
+		return models.Talk{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return talk, nil
+}
+
+func (r *CFPPostgres) TalksByEvent(eventID int) ([]models.TalkSummary, error) {
+	const op = "repository.CFPPostgres.TalksByEvent"
+
+	rows, err := r.db.Query(
+		"SELECT t.id, t.event_id, t.speaker_id, t.title, t.abstract, t.level, t.duration, t.tags, t.status, t.created_at, "+
+			"COALESCE(AVG(tr.score), 0), COUNT(tr.id) "+
+			"FROM talks t LEFT JOIN talk_reviews tr ON tr.talk_id = t.id "+
+			"WHERE t.event_id = $1 GROUP BY t.id ORDER BY t.created_at",
+		eventID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	talks := make([]models.TalkSummary, 0)
+	for rows.Next() {
+		var t models.TalkSummary
+		if err := rows.Scan(&t.ID, &t.EventID, &t.SpeakerID, &t.Title, &t.Abstract, &t.Level, &t.Duration,
+			pq.Array(&t.Tags), &t.Status, &t.CreatedAt, &t.AvgScore, &t.ReviewsCount); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		talks = append(talks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return talks, nil
+}
+
+func (r *CFPPostgres) CreateReview(review models.TalkReview) (int, error) {
+	const op = "repository.CFPPostgres.CreateReview"
+
+	var id int
+	err := r.db.QueryRow(
+		"INSERT INTO talk_reviews(talk_id, reviewer_id, score, comment) VALUES($1, $2, $3, $4) RETURNING id",
+		review.TalkID, review.ReviewerID, review.Score, review.Comment,
+	).Scan(&id)
+	if err != nil {
+		var pgsErr *pq.Error
+		if errors.As(err, &pgsErr) && pgsErr.Code.Name() == "unique_violation" {
+			return 0, fmt.Errorf("%s: %w", op, ErrReviewExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *CFPPostgres) ReviewsByTalk(talkID int) ([]models.TalkReview, error) {
+	const op = "repository.CFPPostgres.ReviewsByTalk"
+
+	rows, err := r.db.Query(
+		"SELECT id, talk_id, reviewer_id, score, comment, created_at FROM talk_reviews WHERE talk_id = $1 ORDER BY created_at",
+		talkID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	reviews := make([]models.TalkReview, 0)
+	for rows.Next() {
+		var review models.TalkReview
+		if err := rows.Scan(&review.ID, &review.TalkID, &review.ReviewerID, &review.Score, &review.Comment, &review.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		reviews = append(reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return reviews, nil
+}
+
+// AcceptTalk переводит доклад в статус accepted и добавляет его вместе с докладчиком
+// в конец программы мероприятия
+func (r *CFPPostgres) AcceptTalk(talkID int) error {
+	const op = "repository.CFPPostgres.AcceptTalk"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var eventID int
+	var title string
+	err = tx.QueryRow(
+		"UPDATE talks SET status = $1 WHERE id = $2 AND status = $3 RETURNING event_id, title",
+		models.TalkStatusAccepted, talkID, models.TalkStatusSubmitted,
+	).Scan(&eventID, &title)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, ErrTalkNotPending)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Блокируем мероприятие, чтобы параллельные accept не получили одинаковую позицию
+	if _, err := tx.Exec("SELECT id FROM events WHERE id = $1 FOR UPDATE", eventID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var slotID int
+	err = tx.QueryRow(
+		"INSERT INTO agenda_slots(event_id, position, kind, talk_id, title) "+
+			"SELECT $1, COALESCE(MAX(position), 0) + 1, $2, $3, $4 FROM agenda_slots WHERE event_id = $1 RETURNING id",
+		eventID, models.AgendaSlotTalk, talkID, title,
+	).Scan(&slotID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Карточку, которую к аккаунту докладчика привязал кто-то другой, не переиспользуем:
+	// её автор мог бы менять имя и фото на принятом докладе. Она остаётся карточкой гостя
+	_, err = tx.Exec(
+		"UPDATE speakers SET user_id = NULL "+
+			"WHERE user_id = (SELECT speaker_id FROM talks WHERE id = $1) AND created_by IS NOT NULL AND created_by <> user_id",
+		talkID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var speakerID int
+	err = tx.QueryRow(
+		"INSERT INTO speakers(user_id) SELECT speaker_id FROM talks WHERE id = $1 "+
+			"ON CONFLICT (user_id) DO UPDATE SET user_id = EXCLUDED.user_id RETURNING id",
+		talkID,
+	).Scan(&speakerID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec("INSERT INTO agenda_slot_speakers(slot_id, speaker_id) VALUES($1, $2)", slotID, speakerID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *CFPPostgres) RejectTalk(talkID int) error {
+	const op = "repository.CFPPostgres.RejectTalk"
+
+	res, err := r.db.Exec(
+		"UPDATE talks SET status = $1 WHERE id = $2 AND status = $3",
+		models.TalkStatusRejected, talkID, models.TalkStatusSubmitted,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrTalkNotPending)
+	}
+
+	return nil
+}
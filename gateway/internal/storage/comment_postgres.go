@@ -0,0 +1,228 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"log/slog"
+)
+
+// commentColumns не отдаёт текст скрытых модератором комментариев
+const commentColumns = "id, subject_type, subject_id, root_id, parent_id, author_id, " +
+	"CASE WHEN hidden_at IS NULL THEN body ELSE '' END, CASE WHEN hidden_at IS NULL THEN body_html ELSE '' END, " +
+	"created_at, edited_at, deleted_at, hidden_at"
+
+type CommentPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewCommentPostgres(db *sql.DB, logger *slog.Logger) *CommentPostgres {
+	return &CommentPostgres{db: db, log: logger}
+}
+
+func (r *CommentPostgres) CreateComment(comment models.Comment) (int, error) {
+	const op = "repository.CommentPostgres.CreateComment"
+
+	var id int
+	err := r.db.QueryRow(
+		"INSERT INTO comments(subject_type, subject_id, root_id, parent_id, author_id, body, body_html) "+
+			"VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id",
+		comment.SubjectType, comment.SubjectID, comment.RootID, comment.ParentID, comment.AuthorID,
+		comment.Body, comment.BodyHTML,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *CommentPostgres) Comment(id int) (models.Comment, error) {
+	const op = "repository.CommentPostgres.Comment"
+
+	comment, err := scanComment(r.db.QueryRow("SELECT "+commentColumns+" FROM comments WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Comment{}, fmt.Errorf("%s: %w", op, ErrCommentNotFound)
+		}
+
+		return models.Comment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return comment, nil
+}
+
+// Threads возвращает страницу тредов обсуждения, новые сначала, вместе с ответами
+// в порядке их написания
+func (r *CommentPostgres) Threads(filter models.CommentFilter) ([]models.Comment, error) {
+	const op = "repository.CommentPostgres.Threads"
+
+	query := "SELECT " + commentColumns + " FROM comments " +
+		"WHERE subject_type = $1 AND subject_id = $2 AND root_id IS NULL"
+	args := []any{filter.SubjectType, filter.SubjectID}
+	if filter.Cursor > 0 {
+		args = append(args, filter.Cursor)
+		query += fmt.Sprintf(" AND id < $%d", len(args))
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	threads, err := r.comments(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(threads) == 0 {
+		return threads, nil
+	}
+
+	rootIDs := make([]int64, 0, len(threads))
+	index := make(map[int]int, len(threads))
+	for i, thread := range threads {
+		rootIDs = append(rootIDs, int64(thread.ID))
+		index[thread.ID] = i
+	}
+
+	replies, err := r.comments(
+		"SELECT "+commentColumns+" FROM comments WHERE root_id = ANY($1) ORDER BY id", pq.Array(rootIDs),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, reply := range replies {
+		if i, ok := index[*reply.RootID]; ok {
+			threads[i].Replies = append(threads[i].Replies, reply)
+		}
+	}
+
+	return threads, nil
+}
+
+// UpdateComment меняет текст комментария, сохраняя прежний текст в истории правок
+func (r *CommentPostgres) UpdateComment(id int, body, bodyHTML string) error {
+	const op = "repository.CommentPostgres.UpdateComment"
+
+	if err := r.reviseComment(id, "body = $2, body_html = $3, edited_at = now()", body, bodyHTML); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteComment помечает комментарий удалённым. Текст уходит в историю правок,
+// а ответы на комментарий остаются в треде
+func (r *CommentPostgres) DeleteComment(id int) error {
+	const op = "repository.CommentPostgres.DeleteComment"
+
+	if err := r.reviseComment(id, "body = '', body_html = '', deleted_at = now()"); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// reviseComment одним запросом сохраняет текущий текст комментария в историю
+// и применяет изменение set. Удалённые и скрытые модератором комментарии не меняются
+func (r *CommentPostgres) reviseComment(id int, set string, args ...any) error {
+	res, err := r.db.Exec(
+		"WITH old AS (SELECT id, body FROM comments WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL "+
+			"FOR UPDATE), "+
+			"revision AS (INSERT INTO comment_revisions(comment_id, body) SELECT id, body FROM old) "+
+			"UPDATE comments c SET "+set+" FROM old WHERE c.id = old.id",
+		append([]any{id}, args...)...,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrCommentNotFound
+	}
+
+	return nil
+}
+
+func (r *CommentPostgres) CommentRevisions(commentID int) ([]models.CommentRevision, error) {
+	const op = "repository.CommentPostgres.CommentRevisions"
+
+	rows, err := r.db.Query(
+		"SELECT id, comment_id, body, created_at FROM comment_revisions WHERE comment_id = $1 ORDER BY id",
+		commentID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	revisions := make([]models.CommentRevision, 0)
+	for rows.Next() {
+		var revision models.CommentRevision
+		if err := rows.Scan(&revision.ID, &revision.CommentID, &revision.Body, &revision.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return revisions, nil
+}
+
+func (r *CommentPostgres) comments(query string, args ...any) ([]models.Comment, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := make([]models.Comment, 0)
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
+
+func scanComment(row rowScanner) (models.Comment, error) {
+	var comment models.Comment
+	var rootID, parentID sql.NullInt64
+	var editedAt, deletedAt, hiddenAt sql.NullTime
+
+	err := row.Scan(&comment.ID, &comment.SubjectType, &comment.SubjectID, &rootID, &parentID, &comment.AuthorID,
+		&comment.Body, &comment.BodyHTML, &comment.CreatedAt, &editedAt, &deletedAt, &hiddenAt)
+	if err != nil {
+		return models.Comment{}, err
+	}
+
+	if rootID.Valid {
+		id := int(rootID.Int64)
+		comment.RootID = &id
+	}
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		comment.ParentID = &id
+	}
+	if editedAt.Valid {
+		comment.EditedAt = &editedAt.Time
+	}
+	if deletedAt.Valid {
+		comment.DeletedAt = &deletedAt.Time
+	}
+	if hiddenAt.Valid {
+		comment.HiddenAt = &hiddenAt.Time
+	}
+
+	return comment, nil
+}
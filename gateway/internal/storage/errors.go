@@ -5,4 +5,70 @@ import "errors"
 var (
 	ErrUserExists   = errors.New("user already exists")
 	ErrUserNotFound = errors.New("user not found")
+
+	ErrDeletionNotScheduled = errors.New("account deletion is not scheduled")
+	ErrDataExportNotFound   = errors.New("data export not found or expired")
+	ErrEmailTaken           = errors.New("email already taken")
+	ErrEmailChangeNotFound  = errors.New("email change not found or expired")
+
+	ErrEventNotFound  = errors.New("event not found")
+	ErrEventCancelled = errors.New("event is cancelled")
+	ErrCFPExists      = errors.New("call for papers already exists")
+	ErrCFPNotFound    = errors.New("call for papers not found")
+	ErrTalkNotFound   = errors.New("talk not found")
+	ErrReviewExists   = errors.New("review already exists")
+	ErrTalkNotPending = errors.New("talk is already decided")
+
+	ErrSlotNotFound    = errors.New("agenda slot not found")
+	ErrSpeakerNotFound = errors.New("speaker not found")
+	ErrSpeakerExists   = errors.New("speaker already exists")
+
+	ErrVenueNotFound = errors.New("venue not found")
+
+	ErrUsernameTaken = errors.New("username already taken")
+	ErrGroupNotFound = errors.New("group not found")
+
+	ErrRSVPNotFound     = errors.New("rsvp not found")
+	ErrRSVPCancelled    = errors.New("rsvp is cancelled")
+	ErrAlreadyCheckedIn = errors.New("ticket is already used")
+
+	ErrCommentNotFound = errors.New("comment not found")
+
+	ErrNotificationNotFound = errors.New("notification not found")
+
+	ErrStreamEventNotFound = errors.New("stream event not found")
+
+	ErrWebhookNotFound = errors.New("webhook not found")
+
+	ErrConversationNotFound = errors.New("conversation not found")
+	ErrBlockNotFound        = errors.New("user is not blocked")
+	ErrMessageLimit         = errors.New("too many messages sent recently")
+
+	ErrFollowNotFound = errors.New("not following")
+
+	ErrReportTargetNotFound = errors.New("reported content not found")
+	ErrReportNotFound       = errors.New("report not found")
+	ErrReportExists         = errors.New("report is already open")
+	ErrReportNotOpen        = errors.New("report is already resolved")
+
+	ErrMatchProfileNotFound   = errors.New("user does not take part in matchmaking")
+	ErrMatchRequestNotFound   = errors.New("match request not found")
+	ErrMatchRequestExists     = errors.New("match request is already pending")
+	ErrMatchRequestNotPending = errors.New("match request is already answered")
+
+	ErrLinkCodeNotFound        = errors.New("telegram link code not found or expired")
+	ErrTelegramAccountNotFound = errors.New("telegram account is not linked")
+
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobNotDead  = errors.New("job is not dead")
+
+	ErrInvitationNotFound = errors.New("invitation not found or expired")
+	ErrInvitationUsedUp   = errors.New("invitation has no uses left")
+
+	ErrTicketTypeNotFound = errors.New("ticket type not found")
+	ErrTicketsSoldOut     = errors.New("tickets are sold out")
+	ErrOrderNotFound      = errors.New("order not found")
+	ErrOrderExists        = errors.New("order for this event already exists")
+	ErrOrderNotPending    = errors.New("order is not pending")
+	ErrOrderNotPaid       = errors.New("order is not paid")
 )
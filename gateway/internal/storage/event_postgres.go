@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"log/slog"
+	"strings"
+)
+
+const eventColumns = "e.id, e.organizer_id, e.venue_id, e.group_id, e.title, e.description, e.city, e.starts_at, e.ends_at, e.created_at, e.cancelled_at, e.private"
+
+type EventPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewEventPostgres(db *sql.DB, logger *slog.Logger) *EventPostgres {
+	return &EventPostgres{db: db, log: logger}
+}
+
+func (r *EventPostgres) CreateEvent(event models.Event) (int, error) {
+	const op = "repository.EventPostgres.CreateEvent"
+
+	var id int
+	err := r.db.QueryRow(
+		"INSERT INTO events(organizer_id, venue_id, group_id, title, description, city, starts_at, ends_at, private) "+
+			"VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id",
+		event.OrganizerID, event.VenueID, event.GroupID, event.Title, event.Description, event.City, event.StartsAt, event.EndsAt,
+		event.Private,
+	).Scan(&id)
+	if err != nil {
+		var pgsErr *pq.Error
+		if errors.As(err, &pgsErr) && pgsErr.Code.Name() == "foreign_key_violation" {
+			switch pgsErr.Constraint {
+			case "events_venue_id_fkey":
+				return 0, fmt.Errorf("%s: %w", op, ErrVenueNotFound)
+			case "events_group_id_fkey":
+				return 0, fmt.Errorf("%s: %w", op, ErrGroupNotFound)
+			}
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *EventPostgres) Event(id int) (models.Event, error) {
+	const op = "repository.EventPostgres.Event"
+
+	event, err := scanEvent(r.db.QueryRow("SELECT "+eventColumns+" FROM events e WHERE e.id = $1 AND e.hidden_at IS NULL", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Event{}, fmt.Errorf("%s: %w", op, ErrEventNotFound)
+		}
+
+		return models.Event{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return event, nil
+}
+
+// CancelEvent отменяет мероприятие
+func (r *EventPostgres) CancelEvent(id int) error {
+	const op = "repository.EventPostgres.CancelEvent"
+
+	res, err := r.db.Exec("UPDATE events SET cancelled_at = now() WHERE id = $1 AND cancelled_at IS NULL", id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		if _, err := r.Event(id); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return fmt.Errorf("%s: %w", op, ErrEventCancelled)
+	}
+
+	return nil
+}
+
+// Events возвращает мероприятия по фильтру. При заданном filter.Near в выборку попадают только
+// мероприятия с площадкой внутри радиуса, отсортированные по расстоянию
+func (r *EventPostgres) Events(filter models.EventFilter) ([]models.Event, error) {
+	const op = "repository.EventPostgres.Events"
+
+	var query strings.Builder
+	args := make([]any, 0)
+
+	if filter.Near != nil {
+		args = append(args, filter.Near.Point.Lat, filter.Near.Point.Lon, filter.Near.RadiusKm*1000)
+		query.WriteString("SELECT " + eventColumns + ", " + distanceExpr("v") + " AS distance " +
+			"FROM events e JOIN venues v ON v.id = e.venue_id " +
+			"WHERE " + withinRadiusExpr("v") + " AND e.hidden_at IS NULL ORDER BY distance, e.starts_at")
+	} else {
+		query.WriteString("SELECT " + eventColumns + ", NULL::float8 FROM events e WHERE e.hidden_at IS NULL ORDER BY e.starts_at DESC")
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query.WriteString(fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args)))
+
+	rows, err := r.db.Query(query.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	events := make([]models.Event, 0)
+	for rows.Next() {
+		var distance sql.NullFloat64
+		event, err := scanEvent(rows, &distance)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if distance.Valid {
+			event.DistanceKm = &distance.Float64
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
+
+// scanEvent читает колонки eventColumns и следующие за ними колонки в extra
+func scanEvent(row rowScanner, extra ...any) (models.Event, error) {
+	var event models.Event
+	var venueID, groupID sql.NullInt64
+	var cancelledAt sql.NullTime
+
+	dest := []any{&event.ID, &event.OrganizerID, &venueID, &groupID, &event.Title, &event.Description, &event.City,
+		&event.StartsAt, &event.EndsAt, &event.CreatedAt, &cancelledAt, &event.Private}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return models.Event{}, err
+	}
+
+	if venueID.Valid {
+		id := int(venueID.Int64)
+		event.VenueID = &id
+	}
+	if groupID.Valid {
+		id := int(groupID.Int64)
+		event.GroupID = &id
+	}
+	if cancelledAt.Valid {
+		event.CancelledAt = &cancelledAt.Time
+	}
+
+	return event, nil
+}
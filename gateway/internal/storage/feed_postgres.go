@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"log/slog"
+)
+
+// feedQuery выбирает предстоящие мероприятия для ленты пользователя $3 вместе с сигналами
+// ранжирования. Если заданы координаты $1, $2, считается расстояние до площадки
+var feedQuery = "WITH friends AS (" +
+	"SELECT cm2.user_id FROM conversation_members cm1 " +
+	"JOIN conversations c ON c.id = cm1.conversation_id AND c.kind = 'direct' " +
+	"JOIN conversation_members cm2 ON cm2.conversation_id = c.id AND cm2.user_id <> cm1.user_id " +
+	"WHERE cm1.user_id = $3 " +
+	"UNION SELECT followee_id FROM user_follows WHERE follower_id = $3), " +
+	"attended AS (SELECT e.group_id, e.organizer_id FROM rsvps r JOIN events e ON e.id = r.event_id " +
+	"WHERE r.user_id = $3 AND r.checked_in_at IS NOT NULL), " +
+	"dismissed AS (SELECT e.id, e.group_id, e.organizer_id FROM feed_dismissals d JOIN events e ON e.id = d.event_id " +
+	"WHERE d.user_id = $3) " +
+	"SELECT " + eventColumns + ", " +
+	distanceExpr("v") + ", " +
+	"EXISTS (SELECT 1 FROM group_members gm WHERE gm.group_id = e.group_id AND gm.user_id = $3), " +
+	"(SELECT COUNT(*) FROM rsvps r JOIN friends f ON f.user_id = r.user_id WHERE r.event_id = e.id AND r.status = 'going'), " +
+	"(SELECT COUNT(*) FROM attended a WHERE a.group_id = e.group_id), " +
+	"(SELECT COUNT(*) FROM attended a WHERE a.organizer_id = e.organizer_id), " +
+	"(SELECT COUNT(*) FROM dismissed d WHERE d.group_id = e.group_id), " +
+	"(SELECT COUNT(*) FROM dismissed d WHERE d.organizer_id = e.organizer_id), " +
+	"(SELECT COUNT(*) FROM rsvps r WHERE r.event_id = e.id AND r.status = 'going') " +
+	"FROM events e LEFT JOIN venues v ON v.id = e.venue_id " +
+	"WHERE e.starts_at > now() AND e.starts_at < now() + interval '90 days' AND e.cancelled_at IS NULL " +
+	"AND e.hidden_at IS NULL " +
+	"AND NOT EXISTS (SELECT 1 FROM dismissed d WHERE d.id = e.id) " +
+	"AND NOT EXISTS (SELECT 1 FROM rsvps r WHERE r.event_id = e.id AND r.user_id = $3 AND r.status = 'going') " +
+	"ORDER BY e.starts_at LIMIT $4"
+
+type FeedPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewFeedPostgres(db *sql.DB, logger *slog.Logger) *FeedPostgres {
+	return &FeedPostgres{db: db, log: logger}
+}
+
+func (r *FeedPostgres) FeedProfile(userID int) (models.FeedProfile, error) {
+	const op = "repository.FeedPostgres.FeedProfile"
+
+	var profile models.FeedProfile
+	err := r.db.QueryRow("SELECT skills, city FROM users WHERE id = $1", userID).Scan(pq.Array(&profile.Skills), &profile.City)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.FeedProfile{}, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		return models.FeedProfile{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return profile, nil
+}
+
+// FeedCandidates возвращает до limit ближайших по времени мероприятий, которые
+// пользователь не скрыл и на которые ещё не записался
+func (r *FeedPostgres) FeedCandidates(userID int, near *models.GeoPoint, limit int) ([]models.FeedCandidate, error) {
+	const op = "repository.FeedPostgres.FeedCandidates"
+
+	var lat, lon *float64
+	if near != nil {
+		lat, lon = &near.Lat, &near.Lon
+	}
+
+	rows, err := r.db.Query(feedQuery, lat, lon, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	candidates := make([]models.FeedCandidate, 0)
+	for rows.Next() {
+		var c models.FeedCandidate
+		var distance sql.NullFloat64
+		c.Event, err = scanEvent(rows, &distance, &c.InUserGroup, &c.FriendsGoing, &c.AttendedInGroup,
+			&c.AttendedOrganizer, &c.DismissedInGroup, &c.DismissedOrganizer, &c.Going)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if distance.Valid {
+			c.DistanceKm = &distance.Float64
+		}
+		candidates = append(candidates, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return candidates, nil
+}
+
+func (r *FeedPostgres) DismissEvent(userID, eventID int) error {
+	const op = "repository.FeedPostgres.DismissEvent"
+
+	_, err := r.db.Exec(
+		"INSERT INTO feed_dismissals(user_id, event_id) VALUES($1, $2) ON CONFLICT DO NOTHING", userID, eventID,
+	)
+	if err != nil {
+		var pgsErr *pq.Error
+		if errors.As(err, &pgsErr) && pgsErr.Code.Name() == "foreign_key_violation" {
+			return fmt.Errorf("%s: %w", op, ErrEventNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *FeedPostgres) UndismissEvent(userID, eventID int) error {
+	const op = "repository.FeedPostgres.UndismissEvent"
+
+	res, err := r.db.Exec("DELETE FROM feed_dismissals WHERE user_id = $1 AND event_id = $2", userID, eventID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrEventNotFound)
+	}
+
+	return nil
+}
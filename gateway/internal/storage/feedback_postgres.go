@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"fmt"
+	"log/slog"
+)
+
+const feedbackColumns = "id, event_id, slot_id, user_id, rating, comment, anonymous, created_at, updated_at"
+
+type FeedbackPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewFeedbackPostgres(db *sql.DB, logger *slog.Logger) *FeedbackPostgres {
+	return &FeedbackPostgres{db: db, log: logger}
+}
+
+// SaveFeedback сохраняет отзыв. Повторный отзыв на то же мероприятие или доклад
+// заменяет прежний
+func (r *FeedbackPostgres) SaveFeedback(feedback models.Feedback) (int, error) {
+	const op = "repository.FeedbackPostgres.SaveFeedback"
+
+	conflict := "(event_id, user_id) WHERE slot_id IS NULL"
+	if feedback.SlotID != nil {
+		conflict = "(slot_id, user_id) WHERE slot_id IS NOT NULL"
+	}
+
+	var id int
+	err := r.db.QueryRow(
+		"INSERT INTO feedback(event_id, slot_id, user_id, rating, comment, anonymous) VALUES($1, $2, $3, $4, $5, $6) "+
+			"ON CONFLICT "+conflict+" DO UPDATE SET rating = EXCLUDED.rating, comment = EXCLUDED.comment, "+
+			"anonymous = EXCLUDED.anonymous, updated_at = now() RETURNING id",
+		feedback.EventID, feedback.SlotID, feedback.UserID, feedback.Rating, feedback.Comment, feedback.Anonymous,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// EventFeedback возвращает отзывы на само мероприятие, без отзывов на доклады
+func (r *FeedbackPostgres) EventFeedback(eventID int) ([]models.Feedback, error) {
+	const op = "repository.FeedbackPostgres.EventFeedback"
+
+	feedback, err := r.feedback(
+		"SELECT "+feedbackColumns+" FROM feedback WHERE event_id = $1 AND slot_id IS NULL ORDER BY created_at DESC",
+		eventID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return feedback, nil
+}
+
+func (r *FeedbackPostgres) SlotFeedback(slotID int) ([]models.Feedback, error) {
+	const op = "repository.FeedbackPostgres.SlotFeedback"
+
+	feedback, err := r.feedback(
+		"SELECT "+feedbackColumns+" FROM feedback WHERE slot_id = $1 ORDER BY created_at DESC",
+		slotID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return feedback, nil
+}
+
+// TalkRatings возвращает сводные оценки всех докладов программы мероприятия,
+// включая доклады без отзывов
+func (r *FeedbackPostgres) TalkRatings(eventID int) ([]models.TalkRating, error) {
+	const op = "repository.FeedbackPostgres.TalkRatings"
+
+	rows, err := r.db.Query(
+		"SELECT a.id, a.title, COUNT(f.id), COALESCE(AVG(f.rating), 0), "+
+			"COUNT(*) FILTER (WHERE f.rating = 1), COUNT(*) FILTER (WHERE f.rating = 2), "+
+			"COUNT(*) FILTER (WHERE f.rating = 3), COUNT(*) FILTER (WHERE f.rating = 4), "+
+			"COUNT(*) FILTER (WHERE f.rating = 5) "+
+			"FROM agenda_slots a LEFT JOIN feedback f ON f.slot_id = a.id "+
+			"WHERE a.event_id = $1 AND a.kind = $2 GROUP BY a.id ORDER BY a.position",
+		eventID, models.AgendaSlotTalk,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	ratings := make([]models.TalkRating, 0)
+	for rows.Next() {
+		var rating models.TalkRating
+		d := &rating.Distribution
+		if err := rows.Scan(&rating.SlotID, &rating.Title, &rating.Count, &rating.Average, &d[0], &d[1], &d[2], &d[3], &d[4]); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		ratings = append(ratings, rating)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ratings, nil
+}
+
+func (r *FeedbackPostgres) feedback(query string, args ...any) ([]models.Feedback, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	feedback := make([]models.Feedback, 0)
+	for rows.Next() {
+		var f models.Feedback
+		var slotID sql.NullInt64
+		err := rows.Scan(&f.ID, &f.EventID, &slotID, &f.UserID, &f.Rating, &f.Comment, &f.Anonymous, &f.CreatedAt, &f.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if slotID.Valid {
+			id := int(slotID.Int64)
+			f.SlotID = &id
+		}
+		feedback = append(feedback, f)
+	}
+
+	return feedback, rows.Err()
+}
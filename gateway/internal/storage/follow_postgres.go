@@ -0,0 +1,265 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"log/slog"
+)
+
+const activityColumns = "a.id, a.kind, a.actor_id, u.name, COALESCE(u.username, ''), a.group_id, COALESCE(g.name, ''), " +
+	"a.event_id, COALESCE(e.title, ''), a.talk_id, COALESCE(t.title, ''), a.created_at " +
+	"FROM activities a JOIN users u ON u.id = a.actor_id LEFT JOIN groups g ON g.id = a.group_id " +
+	"LEFT JOIN events e ON e.id = a.event_id LEFT JOIN talks t ON t.id = a.talk_id "
+
+type FollowPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewFollowPostgres(db *sql.DB, logger *slog.Logger) *FollowPostgres {
+	return &FollowPostgres{db: db, log: logger}
+}
+
+// FollowUser подписывает followerID на followeeID. Повторная подписка не ошибка
+func (r *FollowPostgres) FollowUser(followerID, followeeID int) error {
+	const op = "repository.FollowPostgres.FollowUser"
+
+	_, err := r.db.Exec(
+		"INSERT INTO user_follows(follower_id, followee_id) VALUES($1, $2) ON CONFLICT DO NOTHING", followerID, followeeID,
+	)
+	if err != nil {
+		var pgsErr *pq.Error
+		if errors.As(err, &pgsErr) && pgsErr.Code.Name() == "foreign_key_violation" {
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *FollowPostgres) UnfollowUser(followerID, followeeID int) error {
+	const op = "repository.FollowPostgres.UnfollowUser"
+
+	res, err := r.db.Exec("DELETE FROM user_follows WHERE follower_id = $1 AND followee_id = $2", followerID, followeeID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkFollowDeleted(op, res)
+}
+
+// FollowGroup подписывает пользователя на сообщество. Повторная подписка не ошибка
+func (r *FollowPostgres) FollowGroup(userID, groupID int) error {
+	const op = "repository.FollowPostgres.FollowGroup"
+
+	_, err := r.db.Exec(
+		"INSERT INTO group_follows(user_id, group_id) VALUES($1, $2) ON CONFLICT DO NOTHING", userID, groupID,
+	)
+	if err != nil {
+		var pgsErr *pq.Error
+		if errors.As(err, &pgsErr) && pgsErr.Code.Name() == "foreign_key_violation" {
+			return fmt.Errorf("%s: %w", op, ErrGroupNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *FollowPostgres) UnfollowGroup(userID, groupID int) error {
+	const op = "repository.FollowPostgres.UnfollowGroup"
+
+	res, err := r.db.Exec("DELETE FROM group_follows WHERE user_id = $1 AND group_id = $2", userID, groupID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkFollowDeleted(op, res)
+}
+
+// Followers возвращает подписчиков пользователя, новые сначала
+func (r *FollowPostgres) Followers(userID, limit, offset int) ([]models.Follow, error) {
+	const op = "repository.FollowPostgres.Followers"
+
+	follows, err := r.queryFollows(
+		"SELECT u.id, COALESCE(u.username, ''), u.name, f.created_at FROM user_follows f "+
+			"JOIN users u ON u.id = f.follower_id WHERE f.followee_id = $1 "+
+			"ORDER BY f.created_at DESC, u.id LIMIT $2 OFFSET $3",
+		userID, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return follows, nil
+}
+
+// Following возвращает пользователей, на которых подписан userID, новые подписки сначала
+func (r *FollowPostgres) Following(userID, limit, offset int) ([]models.Follow, error) {
+	const op = "repository.FollowPostgres.Following"
+
+	follows, err := r.queryFollows(
+		"SELECT u.id, COALESCE(u.username, ''), u.name, f.created_at FROM user_follows f "+
+			"JOIN users u ON u.id = f.followee_id WHERE f.follower_id = $1 "+
+			"ORDER BY f.created_at DESC, u.id LIMIT $2 OFFSET $3",
+		userID, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return follows, nil
+}
+
+// GroupFollowers возвращает подписчиков сообщества, новые сначала
+func (r *FollowPostgres) GroupFollowers(groupID, limit, offset int) ([]models.Follow, error) {
+	const op = "repository.FollowPostgres.GroupFollowers"
+
+	follows, err := r.queryFollows(
+		"SELECT u.id, COALESCE(u.username, ''), u.name, f.created_at FROM group_follows f "+
+			"JOIN users u ON u.id = f.user_id WHERE f.group_id = $1 "+
+			"ORDER BY f.created_at DESC, u.id LIMIT $2 OFFSET $3",
+		groupID, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return follows, nil
+}
+
+// FollowedGroups возвращает сообщества, на которые подписан пользователь, новые подписки сначала
+func (r *FollowPostgres) FollowedGroups(userID, limit, offset int) ([]models.GroupFollow, error) {
+	const op = "repository.FollowPostgres.FollowedGroups"
+
+	rows, err := r.db.Query(
+		"SELECT g.id, g.name, g.city, f.created_at FROM group_follows f JOIN groups g ON g.id = f.group_id "+
+			"WHERE f.user_id = $1 ORDER BY f.created_at DESC, g.id LIMIT $2 OFFSET $3",
+		userID, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	groups := make([]models.GroupFollow, 0)
+	for rows.Next() {
+		var group models.GroupFollow
+		if err := rows.Scan(&group.GroupID, &group.Name, &group.City, &group.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		groups = append(groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return groups, nil
+}
+
+func (r *FollowPostgres) CreateActivity(activity models.Activity) error {
+	const op = "repository.FollowPostgres.CreateActivity"
+
+	_, err := r.db.Exec(
+		"INSERT INTO activities(actor_id, kind, group_id, event_id, talk_id) VALUES($1, $2, $3, $4, $5)",
+		activity.ActorID, activity.Kind, activity.GroupID, activity.EventID, activity.TalkID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Activities собирает ленту подписок userID: все действия пользователей, на которых он
+// подписан, и новые мероприятия сообществ, на которые он подписан. Действия тех, с кем
+// есть блокировка, в ленту не попадают
+func (r *FollowPostgres) Activities(userID int, filter models.ActivityFilter) ([]models.Activity, error) {
+	const op = "repository.FollowPostgres.Activities"
+
+	query := "SELECT " + activityColumns +
+		"WHERE a.actor_id <> $1 AND (a.event_id IS NULL OR e.hidden_at IS NULL) AND (" +
+		"a.actor_id IN (SELECT followee_id FROM user_follows WHERE follower_id = $1) " +
+		"OR (a.kind = $2 AND a.group_id IN (SELECT group_id FROM group_follows WHERE user_id = $1))) " +
+		"AND NOT EXISTS (SELECT 1 FROM user_blocks b WHERE (b.blocker_id = $1 AND b.blocked_id = a.actor_id) " +
+		"OR (b.blocker_id = a.actor_id AND b.blocked_id = $1))"
+	args := []any{userID, models.ActivityEventCreated}
+	if filter.Cursor > 0 {
+		args = append(args, filter.Cursor)
+		query += fmt.Sprintf(" AND a.id < $%d", len(args))
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY a.id DESC LIMIT $%d", len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	activities := make([]models.Activity, 0)
+	for rows.Next() {
+		var activity models.Activity
+		var groupID, eventID, talkID sql.NullInt64
+		err := rows.Scan(&activity.ID, &activity.Kind, &activity.ActorID, &activity.ActorName, &activity.ActorUsername,
+			&groupID, &activity.GroupName, &eventID, &activity.EventTitle, &talkID, &activity.TalkTitle, &activity.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if groupID.Valid {
+			id := int(groupID.Int64)
+			activity.GroupID = &id
+		}
+		if eventID.Valid {
+			id := int(eventID.Int64)
+			activity.EventID = &id
+		}
+		if talkID.Valid {
+			id := int(talkID.Int64)
+			activity.TalkID = &id
+		}
+		activities = append(activities, activity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return activities, nil
+}
+
+func (r *FollowPostgres) queryFollows(query string, args ...any) ([]models.Follow, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	follows := make([]models.Follow, 0)
+	for rows.Next() {
+		var follow models.Follow
+		if err := rows.Scan(&follow.UserID, &follow.Username, &follow.Name, &follow.CreatedAt); err != nil {
+			return nil, err
+		}
+		follows = append(follows, follow)
+	}
+
+	return follows, rows.Err()
+}
+
+func checkFollowDeleted(op string, res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrFollowNotFound)
+	}
+
+	return nil
+}
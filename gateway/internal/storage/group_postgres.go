@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+const groupColumns = "g.id, g.owner_id, g.name, g.description, g.city, g.private, g.created_at"
+
+type GroupPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewGroupPostgres(db *sql.DB, logger *slog.Logger) *GroupPostgres {
+	return &GroupPostgres{db: db, log: logger}
+}
+
+// CreateGroup создаёт сообщество и добавляет автора в участники с ролью owner
+func (r *GroupPostgres) CreateGroup(group models.Group) (int, error) {
+	const op = "repository.GroupPostgres.CreateGroup"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRow(
+		"INSERT INTO groups(owner_id, name, description, city, private) VALUES($1, $2, $3, $4, $5) RETURNING id",
+		group.OwnerID, group.Name, group.Description, group.City, group.Private,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(
+		"INSERT INTO group_members(group_id, user_id, role) VALUES($1, $2, $3)",
+		id, group.OwnerID, models.GroupRoleOwner,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *GroupPostgres) Group(id int) (models.Group, error) {
+	const op = "repository.GroupPostgres.Group"
+
+	group, err := scanGroup(r.db.QueryRow("SELECT "+groupColumns+" FROM groups g WHERE g.id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Group{}, fmt.Errorf("%s: %w", op, ErrGroupNotFound)
+		}
+
+		return models.Group{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return group, nil
+}
+
+func (r *GroupPostgres) Groups(limit, offset int) ([]models.Group, error) {
+	const op = "repository.GroupPostgres.Groups"
+
+	rows, err := r.db.Query(
+		"SELECT "+groupColumns+" FROM groups g ORDER BY g.created_at DESC LIMIT $1 OFFSET $2", limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	groups := make([]models.Group, 0)
+	for rows.Next() {
+		group, err := scanGroup(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		groups = append(groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return groups, nil
+}
+
+// AddMember добавляет участника и сообщает, вступил ли он только что
+func (r *GroupPostgres) AddMember(groupID, userID int) (bool, error) {
+	const op = "repository.GroupPostgres.AddMember"
+
+	res, err := r.db.Exec(
+		"INSERT INTO group_members(group_id, user_id, role) VALUES($1, $2, $3) ON CONFLICT DO NOTHING",
+		groupID, userID, models.GroupRoleMember,
+	)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return affected > 0, nil
+}
+
+// RemoveMember исключает участника. Владельца сообщества исключить нельзя
+func (r *GroupPostgres) RemoveMember(groupID, userID int) error {
+	const op = "repository.GroupPostgres.RemoveMember"
+
+	_, err := r.db.Exec(
+		"DELETE FROM group_members WHERE group_id = $1 AND user_id = $2 AND role <> $3",
+		groupID, userID, models.GroupRoleOwner,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// MemberRole возвращает роль пользователя в сообществе или пустую строку, если он не участник
+func (r *GroupPostgres) MemberRole(groupID, userID int) (string, error) {
+	const op = "repository.GroupPostgres.MemberRole"
+
+	var role string
+	err := r.db.QueryRow(
+		"SELECT role FROM group_members WHERE group_id = $1 AND user_id = $2", groupID, userID,
+	).Scan(&role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return role, nil
+}
+
+// MemberIDs возвращает идентификаторы всех участников сообщества
+func (r *GroupPostgres) MemberIDs(groupID int) ([]int, error) {
+	const op = "repository.GroupPostgres.MemberIDs"
+
+	rows, err := r.db.Query("SELECT user_id FROM group_members WHERE group_id = $1 ORDER BY user_id", groupID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ids, nil
+}
+
+func scanGroup(row rowScanner) (models.Group, error) {
+	var group models.Group
+
+	err := row.Scan(&group.ID, &group.OwnerID, &group.Name, &group.Description, &group.City, &group.Private, &group.CreatedAt)
+	if err != nil {
+		return models.Group{}, err
+	}
+
+	return group, nil
+}
@@ -0,0 +1,268 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+const invitationColumns = "i.id, i.subject_type, i.subject_id, i.inviter_id, i.invitee_id, i.email, i.token, i.max_uses, " +
+	"i.uses, i.expires_at, i.revoked_at, i.created_at, COALESCE(g.name, e.title, '')"
+
+// invitationFrom подтягивает к приглашению название сообщества или мероприятия
+const invitationFrom = " FROM invitations i " +
+	"LEFT JOIN groups g ON i.subject_type = '" + models.InvitationSubjectGroup + "' AND g.id = i.subject_id " +
+	"LEFT JOIN events e ON i.subject_type = '" + models.InvitationSubjectEvent + "' AND e.id = i.subject_id "
+
+// invitationActive отсекает отозванные и истёкшие приглашения
+const invitationActive = "i.revoked_at IS NULL AND (i.expires_at IS NULL OR i.expires_at > now())"
+
+type InvitationPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewInvitationPostgres(db *sql.DB, logger *slog.Logger) *InvitationPostgres {
+	return &InvitationPostgres{db: db, log: logger}
+}
+
+func (r *InvitationPostgres) CreateInvitation(invitation models.Invitation) (int, error) {
+	const op = "repository.InvitationPostgres.CreateInvitation"
+
+	var id int
+	err := r.db.QueryRow(
+		"INSERT INTO invitations(subject_type, subject_id, inviter_id, invitee_id, email, token, max_uses, expires_at) "+
+			"VALUES($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8) RETURNING id",
+		invitation.SubjectType, invitation.SubjectID, invitation.InviterID, invitation.InviteeID, invitation.Email,
+		invitation.Token, invitation.MaxUses, invitation.ExpiresAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *InvitationPostgres) Invitation(id int) (models.Invitation, error) {
+	const op = "repository.InvitationPostgres.Invitation"
+
+	invitation, err := scanInvitation(r.db.QueryRow("SELECT "+invitationColumns+invitationFrom+"WHERE i.id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Invitation{}, fmt.Errorf("%s: %w", op, ErrInvitationNotFound)
+		}
+
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invitation, nil
+}
+
+// InvitationByToken возвращает действующее приглашение по секрету ссылки или кода из письма
+func (r *InvitationPostgres) InvitationByToken(token string) (models.Invitation, error) {
+	const op = "repository.InvitationPostgres.InvitationByToken"
+
+	invitation, err := scanInvitation(r.db.QueryRow(
+		"SELECT "+invitationColumns+invitationFrom+"WHERE i.token = $1 AND "+invitationActive, token,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Invitation{}, fmt.Errorf("%s: %w", op, ErrInvitationNotFound)
+		}
+
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invitation, nil
+}
+
+// SubjectInvitations возвращает все приглашения в сообщество или на мероприятие, новые первыми
+func (r *InvitationPostgres) SubjectInvitations(subjectType string, subjectID, limit, offset int) ([]models.Invitation, error) {
+	const op = "repository.InvitationPostgres.SubjectInvitations"
+
+	invitations, err := r.invitations(
+		"SELECT "+invitationColumns+invitationFrom+"WHERE i.subject_type = $1 AND i.subject_id = $2 "+
+			"ORDER BY i.id DESC LIMIT $3 OFFSET $4",
+		subjectType, subjectID, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invitations, nil
+}
+
+// UserInvitations возвращает действующие личные приглашения пользователя, которые он ещё не принял
+func (r *InvitationPostgres) UserInvitations(userID int) ([]models.Invitation, error) {
+	const op = "repository.InvitationPostgres.UserInvitations"
+
+	invitations, err := r.invitations(
+		"SELECT "+invitationColumns+invitationFrom+"WHERE i.invitee_id = $1 AND "+invitationActive+" AND NOT EXISTS "+
+			"(SELECT 1 FROM invitation_acceptances a WHERE a.invitation_id = i.id AND a.user_id = $1) ORDER BY i.id DESC",
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invitations, nil
+}
+
+func (r *InvitationPostgres) RevokeInvitation(id int) error {
+	const op = "repository.InvitationPostgres.RevokeInvitation"
+
+	res, err := r.db.Exec("UPDATE invitations SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL", id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrInvitationNotFound)
+	}
+
+	return nil
+}
+
+// AcceptInvitation отмечает, что userID принял приглашение, и расходует одно использование.
+// Повторное принятие тем же пользователем ничего не меняет. Личное приглашение, выданное
+// другому пользователю, выглядит как несуществующее. Приглашение на почту привязывается к
+// тому, кто принял его по коду из письма
+func (r *InvitationPostgres) AcceptInvitation(token string, userID int) (models.Invitation, error) {
+	const op = "repository.InvitationPostgres.AcceptInvitation"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	invitation, err := scanInvitation(tx.QueryRow(
+		"SELECT "+invitationColumns+invitationFrom+"WHERE i.token = $1 AND "+invitationActive+" FOR UPDATE OF i", token,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Invitation{}, fmt.Errorf("%s: %w", op, ErrInvitationNotFound)
+		}
+
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if invitation.InviteeID != nil && *invitation.InviteeID != userID {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, ErrInvitationNotFound)
+	}
+
+	var accepted bool
+	err = tx.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM invitation_acceptances WHERE invitation_id = $1 AND user_id = $2)",
+		invitation.ID, userID,
+	).Scan(&accepted)
+	if err != nil {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if accepted {
+		return invitation, nil
+	}
+
+	if invitation.MaxUses != nil && invitation.Uses >= *invitation.MaxUses {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, ErrInvitationUsedUp)
+	}
+
+	_, err = tx.Exec("INSERT INTO invitation_acceptances(invitation_id, user_id) VALUES($1, $2)", invitation.ID, userID)
+	if err != nil {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(
+		"UPDATE invitations SET uses = uses + 1, "+
+			"invitee_id = CASE WHEN email IS NOT NULL THEN $2 ELSE invitee_id END WHERE id = $1",
+		invitation.ID, userID,
+	)
+	if err != nil {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	invitation.Uses++
+	if invitation.Email != "" {
+		invitation.InviteeID = &userID
+	}
+
+	return invitation, nil
+}
+
+// HasInvitation сообщает, принимал ли пользователь неотозванное приглашение в сообщество
+// или на мероприятие
+func (r *InvitationPostgres) HasInvitation(subjectType string, subjectID, userID int) (bool, error) {
+	const op = "repository.InvitationPostgres.HasInvitation"
+
+	var invited bool
+	err := r.db.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM invitation_acceptances a JOIN invitations i ON i.id = a.invitation_id "+
+			"WHERE i.subject_type = $1 AND i.subject_id = $2 AND a.user_id = $3 AND i.revoked_at IS NULL)",
+		subjectType, subjectID, userID,
+	).Scan(&invited)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invited, nil
+}
+
+func (r *InvitationPostgres) invitations(query string, args ...any) ([]models.Invitation, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := make([]models.Invitation, 0)
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	return invitations, rows.Err()
+}
+
+func scanInvitation(row rowScanner) (models.Invitation, error) {
+	var invitation models.Invitation
+	var inviteeID, maxUses sql.NullInt64
+	var email sql.NullString
+	var expiresAt, revokedAt sql.NullTime
+
+	err := row.Scan(&invitation.ID, &invitation.SubjectType, &invitation.SubjectID, &invitation.InviterID, &inviteeID,
+		&email, &invitation.Token, &maxUses, &invitation.Uses, &expiresAt, &revokedAt, &invitation.CreatedAt,
+		&invitation.SubjectTitle)
+	if err != nil {
+		return models.Invitation{}, err
+	}
+
+	invitation.Email = email.String
+	if inviteeID.Valid {
+		id := int(inviteeID.Int64)
+		invitation.InviteeID = &id
+	}
+	if maxUses.Valid {
+		n := int(maxUses.Int64)
+		invitation.MaxUses = &n
+	}
+	if expiresAt.Valid {
+		invitation.ExpiresAt = &expiresAt.Time
+	}
+	if revokedAt.Valid {
+		invitation.RevokedAt = &revokedAt.Time
+	}
+
+	return invitation, nil
+}
@@ -0,0 +1,220 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"fmt"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+const jobColumns = "id, type, payload, status, attempts, max_attempts, run_at, last_error, created_at, updated_at"
+
+type JobPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewJobPostgres(db *sql.DB, logger *slog.Logger) *JobPostgres {
+	return &JobPostgres{db: db, log: logger}
+}
+
+func (r *JobPostgres) CreateJob(job models.Job) (int64, error) {
+	const op = "repository.JobPostgres.CreateJob"
+
+	var id int64
+	err := r.db.QueryRow(
+		"INSERT INTO jobs(type, payload, max_attempts, run_at) VALUES($1, $2, $3, $4) RETURNING id",
+		job.Type, []byte(job.Payload), job.MaxAttempts, job.RunAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// ClaimJobs закрепляет за воркером до limit задач указанных типов на время lease.
+// Кроме ожидающих задач забираются и те, чей воркер не уложился в lease - например,
+// потому что его реплика упала. Задачи, которые прямо сейчас забирает другая реплика,
+// пропускаются
+func (r *JobPostgres) ClaimJobs(types []string, limit int, lease time.Duration) ([]models.Job, error) {
+	const op = "repository.JobPostgres.ClaimJobs"
+
+	jobs, err := r.jobs(
+		"UPDATE jobs SET status = $1, attempts = attempts + 1, locked_until = now() + $2 * interval '1 second', "+
+			"updated_at = now() WHERE id IN ("+
+			"SELECT id FROM jobs WHERE type = ANY($3) AND "+
+			"((status = $4 AND run_at <= now()) OR (status = $1 AND locked_until < now())) "+
+			"ORDER BY run_at, id LIMIT $5 FOR UPDATE SKIP LOCKED) "+
+			"RETURNING "+jobColumns,
+		models.JobStatusRunning, lease.Seconds(), pq.Array(types), models.JobStatusPending, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return jobs, nil
+}
+
+// CompleteJob отмечает задачу выполненной. Обновление проходит, только если задача всё ещё
+// закреплена за попыткой attempt: если воркер не уложился в lease и задачу забрал другой,
+// возвращается false, а результат чужой попытки не затирается
+func (r *JobPostgres) CompleteJob(id int64, attempt int) (bool, error) {
+	const op = "repository.JobPostgres.CompleteJob"
+
+	return r.finishJob(op,
+		"UPDATE jobs SET status = $1, locked_until = NULL, last_error = '', updated_at = now() "+
+			"WHERE id = $2 AND status = $3 AND attempts = $4",
+		models.JobStatusDone, id, models.JobStatusRunning, attempt,
+	)
+}
+
+// RetryJob возвращает задачу в очередь после неудачной попытки attempt.
+// Как и CompleteJob, возвращает false, если задача уже закреплена за другим воркером
+func (r *JobPostgres) RetryJob(id int64, attempt int, runAt time.Time, lastError string) (bool, error) {
+	const op = "repository.JobPostgres.RetryJob"
+
+	return r.finishJob(op,
+		"UPDATE jobs SET status = $1, run_at = $2, locked_until = NULL, last_error = $3, updated_at = now() "+
+			"WHERE id = $4 AND status = $5 AND attempts = $6",
+		models.JobStatusPending, runAt, lastError, id, models.JobStatusRunning, attempt,
+	)
+}
+
+// BuryJob переводит задачу, исчерпавшую попытки, в статус dead.
+// Как и CompleteJob, возвращает false, если задача уже закреплена за другим воркером
+func (r *JobPostgres) BuryJob(id int64, attempt int, lastError string) (bool, error) {
+	const op = "repository.JobPostgres.BuryJob"
+
+	return r.finishJob(op,
+		"UPDATE jobs SET status = $1, locked_until = NULL, last_error = $2, updated_at = now() "+
+			"WHERE id = $3 AND status = $4 AND attempts = $5",
+		models.JobStatusDead, lastError, id, models.JobStatusRunning, attempt,
+	)
+}
+
+// finishJob выполняет завершающее обновление задачи и сообщает, владел ли ею ещё воркер
+func (r *JobPostgres) finishJob(op, query string, args ...any) (bool, error) {
+	res, err := r.db.Exec(query, args...)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return affected > 0, nil
+}
+
+// DeleteDoneJobsBefore удаляет выполненные задачи, завершённые раньше before
+func (r *JobPostgres) DeleteDoneJobsBefore(before time.Time) (int, error) {
+	const op = "repository.JobPostgres.DeleteDoneJobsBefore"
+
+	res, err := r.db.Exec("DELETE FROM jobs WHERE status = $1 AND updated_at < $2", models.JobStatusDone, before)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(affected), nil
+}
+
+func (r *JobPostgres) Job(id int64) (models.Job, error) {
+	const op = "repository.JobPostgres.Job"
+
+	jobs, err := r.jobs("SELECT "+jobColumns+" FROM jobs WHERE id = $1", id)
+	if err != nil {
+		return models.Job{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(jobs) == 0 {
+		return models.Job{}, fmt.Errorf("%s: %w", op, ErrJobNotFound)
+	}
+
+	return jobs[0], nil
+}
+
+// Jobs возвращает задачи, новые сначала
+func (r *JobPostgres) Jobs(filter models.JobFilter) ([]models.Job, error) {
+	const op = "repository.JobPostgres.Jobs"
+
+	query := "SELECT " + jobColumns + " FROM jobs WHERE TRUE"
+	args := make([]any, 0, 4)
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		query += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+	if filter.Type != "" {
+		args = append(args, filter.Type)
+		query += fmt.Sprintf(" AND type = $%d", len(args))
+	}
+	if filter.Cursor > 0 {
+		args = append(args, filter.Cursor)
+		query += fmt.Sprintf(" AND id < $%d", len(args))
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	jobs, err := r.jobs(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return jobs, nil
+}
+
+// RequeueJob возвращает задачу в статусе dead в очередь с обнулённым числом попыток
+func (r *JobPostgres) RequeueJob(id int64) error {
+	const op = "repository.JobPostgres.RequeueJob"
+
+	res, err := r.db.Exec(
+		"UPDATE jobs SET status = $1, attempts = 0, run_at = now(), updated_at = now() WHERE id = $2 AND status = $3",
+		models.JobStatusPending, id, models.JobStatusDead,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		if _, err := r.Job(id); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return fmt.Errorf("%s: %w", op, ErrJobNotDead)
+	}
+
+	return nil
+}
+
+func (r *JobPostgres) jobs(query string, args ...any) ([]models.Job, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := make([]models.Job, 0)
+	for rows.Next() {
+		var job models.Job
+		var payload []byte
+		if err := rows.Scan(&job.ID, &job.Type, &payload, &job.Status, &job.Attempts, &job.MaxAttempts,
+			&job.RunAt, &job.LastError, &job.CreatedAt, &job.UpdatedAt); err != nil {
+			return nil, err
+		}
+		job.Payload = payload
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
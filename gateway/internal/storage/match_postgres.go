@@ -0,0 +1,274 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+const (
+	matchCandidateColumns = "u.id, u.name, COALESCE(u.username, ''), u.city, u.bio, u.skills, u.seniority, mp.goals, " +
+		"ARRAY(SELECT group_id FROM group_members gm WHERE gm.user_id = u.id ORDER BY group_id)"
+	matchRequestColumns = "id, requester_id, recipient_id, goal, message, status, conversation_id, created_at, responded_at"
+)
+
+type MatchPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewMatchPostgres(db *sql.DB, logger *slog.Logger) *MatchPostgres {
+	return &MatchPostgres{db: db, log: logger}
+}
+
+// SetMatchProfile включает участие пользователя в подборе или меняет его цели
+func (r *MatchPostgres) SetMatchProfile(userID int, goals []string) error {
+	const op = "repository.MatchPostgres.SetMatchProfile"
+
+	if _, err := r.db.Exec(
+		"INSERT INTO match_profiles(user_id, goals) VALUES($1, $2) "+
+			"ON CONFLICT (user_id) DO UPDATE SET goals = EXCLUDED.goals, updated_at = now()",
+		userID, pq.Array(goals),
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *MatchPostgres) DeleteMatchProfile(userID int) error {
+	const op = "repository.MatchPostgres.DeleteMatchProfile"
+
+	res, err := r.db.Exec("DELETE FROM match_profiles WHERE user_id = $1", userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrMatchProfileNotFound)
+	}
+
+	return nil
+}
+
+// MatchCandidate возвращает участника подбора. Если пользователь не участвует - ErrMatchProfileNotFound
+func (r *MatchPostgres) MatchCandidate(userID int) (models.MatchCandidate, error) {
+	const op = "repository.MatchPostgres.MatchCandidate"
+
+	candidate, err := scanMatchCandidate(r.db.QueryRow(
+		"SELECT "+matchCandidateColumns+" FROM match_profiles mp JOIN users u ON u.id = mp.user_id WHERE mp.user_id = $1",
+		userID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.MatchCandidate{}, fmt.Errorf("%s: %w", op, ErrMatchProfileNotFound)
+		}
+
+		return models.MatchCandidate{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return candidate, nil
+}
+
+// MatchCandidates возвращает участников подбора, у которых с userID есть общий навык,
+// город или сообщество. Пропускаются заблокированные в любую сторону и те, с кем
+// уже есть заявка на рассмотрении или принятая заявка. goal, если задана, отбирает
+// участников с этой целью
+func (r *MatchPostgres) MatchCandidates(userID int, goal string, limit int) ([]models.MatchCandidate, error) {
+	const op = "repository.MatchPostgres.MatchCandidates"
+
+	rows, err := r.db.Query(
+		"SELECT "+matchCandidateColumns+" FROM match_profiles mp JOIN users u ON u.id = mp.user_id, "+
+			"(SELECT skills, city FROM users WHERE id = $1) me "+
+			"WHERE mp.user_id <> $1 AND ($2 = '' OR $2 = ANY(mp.goals)) "+
+			"AND NOT EXISTS (SELECT 1 FROM user_blocks b WHERE (b.blocker_id = $1 AND b.blocked_id = u.id) "+
+			"OR (b.blocker_id = u.id AND b.blocked_id = $1)) "+
+			"AND NOT EXISTS (SELECT 1 FROM match_requests mr WHERE mr.status IN ('pending', 'accepted') "+
+			"AND ((mr.requester_id = $1 AND mr.recipient_id = u.id) OR (mr.requester_id = u.id AND mr.recipient_id = $1))) "+
+			"AND (u.skills && me.skills OR (me.city <> '' AND lower(u.city) = lower(me.city)) "+
+			"OR EXISTS (SELECT 1 FROM group_members a JOIN group_members b ON b.group_id = a.group_id "+
+			"WHERE a.user_id = $1 AND b.user_id = u.id)) "+
+			"ORDER BY mp.updated_at DESC LIMIT $3",
+		userID, goal, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	candidates := make([]models.MatchCandidate, 0)
+	for rows.Next() {
+		candidate, err := scanMatchCandidate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		candidates = append(candidates, candidate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return candidates, nil
+}
+
+func (r *MatchPostgres) CreateMatchRequest(request models.MatchRequest) (int, error) {
+	const op = "repository.MatchPostgres.CreateMatchRequest"
+
+	var id int
+	err := r.db.QueryRow(
+		"INSERT INTO match_requests(requester_id, recipient_id, goal, message) VALUES($1, $2, $3, $4) RETURNING id",
+		request.RequesterID, request.RecipientID, request.Goal, request.Message,
+	).Scan(&id)
+	if err != nil {
+		var pgsErr *pq.Error
+		if errors.As(err, &pgsErr) && pgsErr.Code.Name() == "unique_violation" {
+			return 0, fmt.Errorf("%s: %w", op, ErrMatchRequestExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *MatchPostgres) MatchRequest(id int) (models.MatchRequest, error) {
+	const op = "repository.MatchPostgres.MatchRequest"
+
+	request, err := scanMatchRequest(r.db.QueryRow("SELECT "+matchRequestColumns+" FROM match_requests WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.MatchRequest{}, fmt.Errorf("%s: %w", op, ErrMatchRequestNotFound)
+		}
+
+		return models.MatchRequest{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return request, nil
+}
+
+// MatchRequests возвращает входящие или исходящие заявки пользователя, новые сначала
+func (r *MatchPostgres) MatchRequests(userID int, filter models.MatchRequestFilter) ([]models.MatchRequest, error) {
+	const op = "repository.MatchPostgres.MatchRequests"
+
+	query := "SELECT " + matchRequestColumns + " FROM match_requests WHERE requester_id = $1"
+	if filter.Incoming {
+		query = "SELECT " + matchRequestColumns + " FROM match_requests WHERE recipient_id = $1"
+	}
+	args := []any{userID}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		query += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+	if filter.Cursor > 0 {
+		args = append(args, filter.Cursor)
+		query += fmt.Sprintf(" AND id < $%d", len(args))
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	requests := make([]models.MatchRequest, 0)
+	for rows.Next() {
+		request, err := scanMatchRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		requests = append(requests, request)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return requests, nil
+}
+
+// RespondMatchRequest принимает или отклоняет заявку, которая ещё на рассмотрении
+func (r *MatchPostgres) RespondMatchRequest(id int, status string, conversationID *int) error {
+	const op = "repository.MatchPostgres.RespondMatchRequest"
+
+	res, err := r.db.Exec(
+		"UPDATE match_requests SET status = $1, conversation_id = $2, responded_at = now() "+
+			"WHERE id = $3 AND status = 'pending'",
+		status, conversationID, id,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrMatchRequestNotPending)
+	}
+
+	return nil
+}
+
+// CountMatchRequestsSince возвращает, сколько заявок пользователь отправил после since
+func (r *MatchPostgres) CountMatchRequestsSince(userID int, since time.Time) (int, error) {
+	const op = "repository.MatchPostgres.CountMatchRequestsSince"
+
+	var count int
+	err := r.db.QueryRow(
+		"SELECT COUNT(*) FROM match_requests WHERE requester_id = $1 AND created_at > $2", userID, since,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func scanMatchCandidate(row rowScanner) (models.MatchCandidate, error) {
+	var candidate models.MatchCandidate
+	var groupIDs pq.Int64Array
+
+	err := row.Scan(&candidate.UserID, &candidate.Name, &candidate.Username, &candidate.City, &candidate.Bio,
+		pq.Array(&candidate.Skills), &candidate.Seniority, pq.Array(&candidate.Goals), &groupIDs)
+	if err != nil {
+		return models.MatchCandidate{}, err
+	}
+
+	candidate.GroupIDs = make([]int, 0, len(groupIDs))
+	for _, id := range groupIDs {
+		candidate.GroupIDs = append(candidate.GroupIDs, int(id))
+	}
+
+	return candidate, nil
+}
+
+func scanMatchRequest(row rowScanner) (models.MatchRequest, error) {
+	var request models.MatchRequest
+	var conversationID sql.NullInt64
+	var respondedAt sql.NullTime
+
+	err := row.Scan(&request.ID, &request.RequesterID, &request.RecipientID, &request.Goal, &request.Message,
+		&request.Status, &conversationID, &request.CreatedAt, &respondedAt)
+	if err != nil {
+		return models.MatchRequest{}, err
+	}
+	if conversationID.Valid {
+		id := int(conversationID.Int64)
+		request.ConversationID = &id
+	}
+	if respondedAt.Valid {
+		request.RespondedAt = &respondedAt.Time
+	}
+
+	return request, nil
+}
@@ -0,0 +1,448 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+const (
+	// messageColumns не отдаёт текст скрытых модератором сообщений
+	messageColumns = "id, conversation_id, sender_id, CASE WHEN hidden_at IS NULL THEN body ELSE '' END, created_at, hidden_at"
+	// conversationQuery выбирает диалоги участника $1 вместе с числом непрочитанных
+	// и последним сообщением
+	conversationQuery = "SELECT c.id, c.kind, c.title, c.created_by, c.created_at, c.last_message_at, " +
+		"(SELECT COUNT(*) FROM messages m WHERE m.conversation_id = c.id " +
+		"AND m.id > cm.last_read_message_id AND m.sender_id <> cm.user_id), " +
+		"lm.id, lm.sender_id, lm.body, lm.created_at " +
+		"FROM conversation_members cm JOIN conversations c ON c.id = cm.conversation_id " +
+		"LEFT JOIN LATERAL (SELECT id, sender_id, CASE WHEN hidden_at IS NULL THEN body ELSE '' END AS body, created_at " +
+		"FROM messages " +
+		"WHERE conversation_id = c.id ORDER BY id DESC LIMIT 1) lm ON true " +
+		"WHERE cm.user_id = $1"
+)
+
+type MessagePostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewMessagePostgres(db *sql.DB, logger *slog.Logger) *MessagePostgres {
+	return &MessagePostgres{db: db, log: logger}
+}
+
+// CreateConversation создаёт диалог с участниками memberIDs. Личный диалог двух
+// пользователей один: если он уже есть, возвращается его идентификатор
+func (r *MessagePostgres) CreateConversation(conversation models.Conversation, memberIDs []int) (int, error) {
+	const op = "repository.MessagePostgres.CreateConversation"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var found int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM users WHERE id = ANY($1)", pq.Array(memberIDs)).Scan(&found); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if found != len(memberIDs) {
+		return 0, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+	}
+
+	var directKey *string
+	if conversation.Kind == models.ConversationDirect && len(memberIDs) == 2 {
+		key := fmt.Sprintf("%d:%d", min(memberIDs[0], memberIDs[1]), max(memberIDs[0], memberIDs[1]))
+		directKey = &key
+	}
+
+	var id int
+	err = tx.QueryRow(
+		"INSERT INTO conversations(kind, title, direct_key, created_by) VALUES($1, $2, $3, $4) "+
+			"ON CONFLICT (direct_key) DO UPDATE SET direct_key = EXCLUDED.direct_key RETURNING id",
+		conversation.Kind, conversation.Title, directKey, conversation.CreatedBy,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.Exec(
+		"INSERT INTO conversation_members(conversation_id, user_id) SELECT $1, unnest($2::int[]) ON CONFLICT DO NOTHING",
+		id, pq.Array(memberIDs),
+	); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// Conversation возвращает диалог, если userID - его участник
+func (r *MessagePostgres) Conversation(id, userID int) (models.Conversation, error) {
+	const op = "repository.MessagePostgres.Conversation"
+
+	conversations, err := r.conversations(conversationQuery+" AND c.id = $2", userID, id)
+	if err != nil {
+		return models.Conversation{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(conversations) == 0 {
+		return models.Conversation{}, fmt.Errorf("%s: %w", op, ErrConversationNotFound)
+	}
+
+	return conversations[0], nil
+}
+
+// Conversations возвращает диалоги пользователя, недавно активные сначала
+func (r *MessagePostgres) Conversations(userID, limit, offset int) ([]models.Conversation, error) {
+	const op = "repository.MessagePostgres.Conversations"
+
+	conversations, err := r.conversations(
+		conversationQuery+" ORDER BY COALESCE(c.last_message_at, c.created_at) DESC, c.id DESC LIMIT $2 OFFSET $3",
+		userID, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return conversations, nil
+}
+
+// CreateMessage сохраняет сообщение, если после since отправитель отправил меньше limit
+// сообщений, иначе возвращает ErrMessageLimit. Своё сообщение отправитель считает прочитанным
+func (r *MessagePostgres) CreateMessage(message models.Message, limit int, since time.Time) (models.Message, error) {
+	const op = "repository.MessagePostgres.CreateMessage"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.Message{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// Блокируем отправителя, чтобы параллельные отправки не прошли лимит одновременно
+	if _, err := tx.Exec("SELECT id FROM users WHERE id = $1 FOR UPDATE", message.SenderID); err != nil {
+		return models.Message{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var sent int
+	err = tx.QueryRow(
+		"SELECT COUNT(*) FROM messages WHERE sender_id = $1 AND created_at > $2", message.SenderID, since,
+	).Scan(&sent)
+	if err != nil {
+		return models.Message{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if sent >= limit {
+		return models.Message{}, fmt.Errorf("%s: %w", op, ErrMessageLimit)
+	}
+
+	created, err := scanMessage(tx.QueryRow(
+		"INSERT INTO messages(conversation_id, sender_id, body) VALUES($1, $2, $3) RETURNING "+messageColumns,
+		message.ConversationID, message.SenderID, message.Body,
+	))
+	if err != nil {
+		return models.Message{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.Exec(
+		"UPDATE conversations SET last_message_at = $1 WHERE id = $2", created.CreatedAt, created.ConversationID,
+	); err != nil {
+		return models.Message{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.Exec(
+		"UPDATE conversation_members SET last_read_message_id = $1 WHERE conversation_id = $2 AND user_id = $3",
+		created.ID, created.ConversationID, created.SenderID,
+	); err != nil {
+		return models.Message{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Message{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return created, nil
+}
+
+// Messages возвращает сообщения диалога, новые сначала
+func (r *MessagePostgres) Messages(conversationID int, filter models.MessageFilter) ([]models.Message, error) {
+	const op = "repository.MessagePostgres.Messages"
+
+	query := "SELECT " + messageColumns + " FROM messages WHERE conversation_id = $1"
+	args := []any{conversationID}
+	if filter.Cursor > 0 {
+		args = append(args, filter.Cursor)
+		query += fmt.Sprintf(" AND id < $%d", len(args))
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	messages := make([]models.Message, 0)
+	for rows.Next() {
+		message, err := scanMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return messages, nil
+}
+
+// MarkConversationRead сдвигает отметку о прочтении до messageID, но не дальше последнего
+// сообщения диалога и не назад. Возвращает итоговую отметку
+func (r *MessagePostgres) MarkConversationRead(conversationID, userID, messageID int) (int, error) {
+	const op = "repository.MessagePostgres.MarkConversationRead"
+
+	var lastRead int
+	err := r.db.QueryRow(
+		"UPDATE conversation_members SET last_read_message_id = GREATEST(last_read_message_id, "+
+			"(SELECT COALESCE(MAX(id), 0) FROM messages WHERE conversation_id = $1 AND id <= $3)) "+
+			"WHERE conversation_id = $1 AND user_id = $2 RETURNING last_read_message_id",
+		conversationID, userID, messageID,
+	).Scan(&lastRead)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, ErrConversationNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return lastRead, nil
+}
+
+// CountConversationsSince возвращает, сколько диалогов пользователь создал после since
+func (r *MessagePostgres) CountConversationsSince(userID int, since time.Time) (int, error) {
+	const op = "repository.MessagePostgres.CountConversationsSince"
+
+	var count int
+	err := r.db.QueryRow(
+		"SELECT COUNT(*) FROM conversations WHERE created_by = $1 AND created_at > $2", userID, since,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func (r *MessagePostgres) BlockUser(blockerID, blockedID int) error {
+	const op = "repository.MessagePostgres.BlockUser"
+
+	res, err := r.db.Exec(
+		"INSERT INTO user_blocks(blocker_id, blocked_id) SELECT $1, id FROM users WHERE id = $2 ON CONFLICT DO NOTHING",
+		blockerID, blockedID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		// Либо пользователя нет, либо он уже заблокирован - второе не ошибка
+		var exists bool
+		if err := r.db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)", blockedID).Scan(&exists); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if !exists {
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+	}
+
+	return nil
+}
+
+func (r *MessagePostgres) UnblockUser(blockerID, blockedID int) error {
+	const op = "repository.MessagePostgres.UnblockUser"
+
+	res, err := r.db.Exec("DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2", blockerID, blockedID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrBlockNotFound)
+	}
+
+	return nil
+}
+
+// Blocks возвращает пользователей, которых заблокировал userID, последние сначала
+func (r *MessagePostgres) Blocks(userID int) ([]models.Block, error) {
+	const op = "repository.MessagePostgres.Blocks"
+
+	rows, err := r.db.Query(
+		"SELECT u.id, COALESCE(u.username, ''), u.name, b.created_at FROM user_blocks b "+
+			"JOIN users u ON u.id = b.blocked_id WHERE b.blocker_id = $1 ORDER BY b.created_at DESC",
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	blocks := make([]models.Block, 0)
+	for rows.Next() {
+		var block models.Block
+		if err := rows.Scan(&block.UserID, &block.Username, &block.Name, &block.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		blocks = append(blocks, block)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return blocks, nil
+}
+
+// BlockedBetween сообщает, заблокировал ли userID кого-то из userIDs или кто-то из них - его
+func (r *MessagePostgres) BlockedBetween(userID int, userIDs []int) (bool, error) {
+	const op = "repository.MessagePostgres.BlockedBetween"
+
+	var blocked bool
+	err := r.db.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM user_blocks WHERE "+
+			"(blocker_id = $1 AND blocked_id = ANY($2)) OR (blocked_id = $1 AND blocker_id = ANY($2)))",
+		userID, pq.Array(userIDs),
+	).Scan(&blocked)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return blocked, nil
+}
+
+// Blockers возвращает тех из userIDs, кто заблокировал blockedID
+func (r *MessagePostgres) Blockers(blockedID int, userIDs []int) ([]int, error) {
+	const op = "repository.MessagePostgres.Blockers"
+
+	rows, err := r.db.Query(
+		"SELECT blocker_id FROM user_blocks WHERE blocked_id = $1 AND blocker_id = ANY($2)",
+		blockedID, pq.Array(userIDs),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	blockers := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		blockers = append(blockers, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return blockers, nil
+}
+
+// conversations выполняет запрос на основе conversationQuery и дозагружает участников
+func (r *MessagePostgres) conversations(query string, args ...any) ([]models.Conversation, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	conversations := make([]models.Conversation, 0)
+	ids := make([]int, 0)
+	for rows.Next() {
+		var c models.Conversation
+		var lastMessageAt sql.NullTime
+		var lastID, lastSenderID sql.NullInt64
+		var lastBody sql.NullString
+		var lastCreatedAt sql.NullTime
+		if err := rows.Scan(&c.ID, &c.Kind, &c.Title, &c.CreatedBy, &c.CreatedAt, &lastMessageAt, &c.UnreadCount,
+			&lastID, &lastSenderID, &lastBody, &lastCreatedAt); err != nil {
+			return nil, err
+		}
+		if lastMessageAt.Valid {
+			c.LastMessageAt = &lastMessageAt.Time
+		}
+		if lastID.Valid {
+			c.LastMessage = &models.Message{
+				ID:             int(lastID.Int64),
+				ConversationID: c.ID,
+				SenderID:       int(lastSenderID.Int64),
+				Body:           lastBody.String,
+				CreatedAt:      lastCreatedAt.Time,
+			}
+		}
+		conversations = append(conversations, c)
+		ids = append(ids, c.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return conversations, nil
+	}
+
+	members, err := r.db.Query(
+		"SELECT cm.conversation_id, cm.user_id, COALESCE(u.username, ''), u.name, cm.last_read_message_id, cm.joined_at "+
+			"FROM conversation_members cm JOIN users u ON u.id = cm.user_id "+
+			"WHERE cm.conversation_id = ANY($1) ORDER BY cm.joined_at, cm.user_id",
+		pq.Array(ids),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer members.Close()
+
+	byID := make(map[int]*models.Conversation, len(conversations))
+	for i := range conversations {
+		byID[conversations[i].ID] = &conversations[i]
+	}
+	for members.Next() {
+		var conversationID int
+		var m models.ConversationMember
+		if err := members.Scan(&conversationID, &m.UserID, &m.Username, &m.Name, &m.LastReadMessageID, &m.JoinedAt); err != nil {
+			return nil, err
+		}
+		byID[conversationID].Members = append(byID[conversationID].Members, m)
+	}
+
+	return conversations, members.Err()
+}
+
+func scanMessage(row rowScanner) (models.Message, error) {
+	var message models.Message
+	var hiddenAt sql.NullTime
+	err := row.Scan(&message.ID, &message.ConversationID, &message.SenderID, &message.Body, &message.CreatedAt, &hiddenAt)
+	if err != nil {
+		return models.Message{}, err
+	}
+
+	if hiddenAt.Valid {
+		message.HiddenAt = &hiddenAt.Time
+	}
+
+	return message, nil
+}
@@ -0,0 +1,313 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+const (
+	reportColumns = "id, reporter_id, target_type, target_id, target_user_id, reason, details, snapshot, status, " +
+		"resolved_by, resolved_at, created_at"
+	moderationActionColumns = "id, moderator_id, action, target_type, target_id, target_user_id, report_ids, note, " +
+		"suspended_until, created_at"
+)
+
+// reportTargetQueries выбирают автора и текст содержимого $1. На сообщение может пожаловаться
+// только участник диалога $2
+var reportTargetQueries = map[string]string{
+	models.ReportTargetUser: "SELECT id, concat_ws(E'\\n', name, username, bio) FROM users WHERE id = $1",
+	models.ReportTargetEvent: "SELECT organizer_id, concat_ws(E'\\n', title, description) FROM events " +
+		"WHERE id = $1 AND hidden_at IS NULL",
+	models.ReportTargetComment: "SELECT author_id, body FROM comments " +
+		"WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL",
+	models.ReportTargetMessage: "SELECT m.sender_id, m.body FROM messages m JOIN conversation_members cm " +
+		"ON cm.conversation_id = m.conversation_id AND cm.user_id = $2 WHERE m.id = $1 AND m.hidden_at IS NULL",
+}
+
+// hiddenTables - таблицы содержимого, которое модератор может скрыть
+var hiddenTables = map[string]string{
+	models.ReportTargetEvent:   "events",
+	models.ReportTargetComment: "comments",
+	models.ReportTargetMessage: "messages",
+}
+
+type ModerationPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewModerationPostgres(db *sql.DB, logger *slog.Logger) *ModerationPostgres {
+	return &ModerationPostgres{db: db, log: logger}
+}
+
+// ReportTarget возвращает автора и текст содержимого, на которое жалуется reporterID
+func (r *ModerationPostgres) ReportTarget(reporterID int, targetType string, targetID int) (models.ReportTarget, error) {
+	const op = "repository.ModerationPostgres.ReportTarget"
+
+	query, ok := reportTargetQueries[targetType]
+	if !ok {
+		return models.ReportTarget{}, fmt.Errorf("%s: %w", op, ErrReportTargetNotFound)
+	}
+
+	args := []any{targetID}
+	if targetType == models.ReportTargetMessage {
+		args = append(args, reporterID)
+	}
+
+	var target models.ReportTarget
+	if err := r.db.QueryRow(query, args...).Scan(&target.UserID, &target.Content); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ReportTarget{}, fmt.Errorf("%s: %w", op, ErrReportTargetNotFound)
+		}
+
+		return models.ReportTarget{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return target, nil
+}
+
+func (r *ModerationPostgres) CreateReport(report models.Report) (int, error) {
+	const op = "repository.ModerationPostgres.CreateReport"
+
+	var id int
+	err := r.db.QueryRow(
+		"INSERT INTO reports(reporter_id, target_type, target_id, target_user_id, reason, details, snapshot) "+
+			"VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id",
+		report.ReporterID, report.TargetType, report.TargetID, report.TargetUserID, report.Reason, report.Details,
+		report.Snapshot,
+	).Scan(&id)
+	if err != nil {
+		var pgsErr *pq.Error
+		if errors.As(err, &pgsErr) && pgsErr.Code.Name() == "unique_violation" {
+			return 0, fmt.Errorf("%s: %w", op, ErrReportExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *ModerationPostgres) CountReportsSince(reporterID int, since time.Time) (int, error) {
+	const op = "repository.ModerationPostgres.CountReportsSince"
+
+	var count int
+	err := r.db.QueryRow(
+		"SELECT COUNT(*) FROM reports WHERE reporter_id = $1 AND created_at > $2", reporterID, since,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func (r *ModerationPostgres) Report(id int) (models.Report, error) {
+	const op = "repository.ModerationPostgres.Report"
+
+	report, err := scanReport(r.db.QueryRow("SELECT "+reportColumns+" FROM reports WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Report{}, fmt.Errorf("%s: %w", op, ErrReportNotFound)
+		}
+
+		return models.Report{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return report, nil
+}
+
+// Reports возвращает очередь жалоб, старые сначала
+func (r *ModerationPostgres) Reports(filter models.ReportFilter) ([]models.Report, error) {
+	const op = "repository.ModerationPostgres.Reports"
+
+	query := "SELECT " + reportColumns + " FROM reports WHERE status = $1"
+	args := []any{filter.Status}
+	if filter.TargetType != "" {
+		args = append(args, filter.TargetType)
+		query += fmt.Sprintf(" AND target_type = $%d", len(args))
+	}
+	if filter.Cursor > 0 {
+		args = append(args, filter.Cursor)
+		query += fmt.Sprintf(" AND id > $%d", len(args))
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY id LIMIT $%d", len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	reports := make([]models.Report, 0)
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		reports = append(reports, report)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return reports, nil
+}
+
+// Moderate в одной транзакции закрывает все открытые жалобы на цель действия, применяет
+// действие (скрывает содержимое или блокирует автора) и записывает его в журнал
+func (r *ModerationPostgres) Moderate(action models.ModerationAction) (models.ModerationAction, error) {
+	const op = "repository.ModerationPostgres.Moderate"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.ModerationAction{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	status := models.ReportActioned
+	if action.Action == models.ModerationDismiss {
+		status = models.ReportDismissed
+	}
+
+	var reportIDs pq.Int64Array
+	err = tx.QueryRow(
+		"WITH resolved AS (UPDATE reports SET status = $1, resolved_by = $2, resolved_at = now() "+
+			"WHERE target_type = $3 AND target_id = $4 AND status = $5 RETURNING id) "+
+			"SELECT COALESCE(array_agg(id ORDER BY id), '{}') FROM resolved",
+		status, action.ModeratorID, action.TargetType, action.TargetID, models.ReportOpen,
+	).Scan(&reportIDs)
+	if err != nil {
+		return models.ModerationAction{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(reportIDs) == 0 {
+		return models.ModerationAction{}, fmt.Errorf("%s: %w", op, ErrReportNotOpen)
+	}
+
+	switch action.Action {
+	case models.ModerationHide:
+		table, ok := hiddenTables[action.TargetType]
+		if !ok {
+			return models.ModerationAction{}, fmt.Errorf("%s: cannot hide %s", op, action.TargetType)
+		}
+		if _, err := tx.Exec("UPDATE "+table+" SET hidden_at = now() WHERE id = $1 AND hidden_at IS NULL", action.TargetID); err != nil {
+			return models.ModerationAction{}, fmt.Errorf("%s: %w", op, err)
+		}
+	case models.ModerationSuspend:
+		_, err := tx.Exec(
+			"UPDATE users SET suspended_at = now(), suspended_until = $2, suspension_reason = $3 WHERE id = $1",
+			action.TargetUserID, action.SuspendedUntil, action.Note,
+		)
+		if err != nil {
+			return models.ModerationAction{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	action.ReportIDs = make([]int, 0, len(reportIDs))
+	for _, id := range reportIDs {
+		action.ReportIDs = append(action.ReportIDs, int(id))
+	}
+
+	err = tx.QueryRow(
+		"INSERT INTO moderation_actions(moderator_id, action, target_type, target_id, target_user_id, report_ids, note, "+
+			"suspended_until) VALUES($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at",
+		action.ModeratorID, action.Action, action.TargetType, action.TargetID, action.TargetUserID, reportIDs,
+		action.Note, action.SuspendedUntil,
+	).Scan(&action.ID, &action.CreatedAt)
+	if err != nil {
+		return models.ModerationAction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.ModerationAction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return action, nil
+}
+
+// ModerationActions возвращает журнал модерации, новые записи сначала
+func (r *ModerationPostgres) ModerationActions(filter models.ModerationActionFilter) ([]models.ModerationAction, error) {
+	const op = "repository.ModerationPostgres.ModerationActions"
+
+	query := "SELECT " + moderationActionColumns + " FROM moderation_actions WHERE true"
+	args := make([]any, 0)
+	if filter.TargetUserID > 0 {
+		args = append(args, filter.TargetUserID)
+		query += fmt.Sprintf(" AND target_user_id = $%d", len(args))
+	}
+	if filter.Cursor > 0 {
+		args = append(args, filter.Cursor)
+		query += fmt.Sprintf(" AND id < $%d", len(args))
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	actions := make([]models.ModerationAction, 0)
+	for rows.Next() {
+		var action models.ModerationAction
+		var moderatorID, targetUserID sql.NullInt64
+		var reportIDs pq.Int64Array
+		var suspendedUntil sql.NullTime
+		err := rows.Scan(&action.ID, &moderatorID, &action.Action, &action.TargetType, &action.TargetID, &targetUserID,
+			&reportIDs, &action.Note, &suspendedUntil, &action.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if moderatorID.Valid {
+			id := int(moderatorID.Int64)
+			action.ModeratorID = &id
+		}
+		if targetUserID.Valid {
+			id := int(targetUserID.Int64)
+			action.TargetUserID = &id
+		}
+		if suspendedUntil.Valid {
+			action.SuspendedUntil = &suspendedUntil.Time
+		}
+		action.ReportIDs = make([]int, 0, len(reportIDs))
+		for _, id := range reportIDs {
+			action.ReportIDs = append(action.ReportIDs, int(id))
+		}
+		actions = append(actions, action)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return actions, nil
+}
+
+func scanReport(row rowScanner) (models.Report, error) {
+	var report models.Report
+	var resolvedBy sql.NullInt64
+	var resolvedAt sql.NullTime
+
+	err := row.Scan(&report.ID, &report.ReporterID, &report.TargetType, &report.TargetID, &report.TargetUserID,
+		&report.Reason, &report.Details, &report.Snapshot, &report.Status, &resolvedBy, &resolvedAt, &report.CreatedAt)
+	if err != nil {
+		return models.Report{}, err
+	}
+
+	if resolvedBy.Valid {
+		id := int(resolvedBy.Int64)
+		report.ResolvedBy = &id
+	}
+	if resolvedAt.Valid {
+		report.ResolvedAt = &resolvedAt.Time
+	}
+
+	return report, nil
+}
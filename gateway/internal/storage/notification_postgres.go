@@ -0,0 +1,298 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"log/slog"
+)
+
+// digestLockKey - ключ advisory-блокировки рассылки email-сводок
+const digestLockKey = 33_000_001
+
+const notificationColumns = "id, user_id, type, title, body, group_key, count, read_at, created_at, updated_at"
+
+type NotificationPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewNotificationPostgres(db *sql.DB, logger *slog.Logger) *NotificationPostgres {
+	return &NotificationPostgres{db: db, log: logger}
+}
+
+// CreateNotification сохраняет уведомление. Если у пользователя уже есть непрочитанное
+// уведомление с тем же GroupKey, оно обновляется и его счётчик увеличивается
+func (r *NotificationPostgres) CreateNotification(notification models.Notification, email bool) (int, error) {
+	const op = "repository.NotificationPostgres.CreateNotification"
+
+	var id int
+	err := r.db.QueryRow(
+		"INSERT INTO notifications(user_id, type, title, body, group_key, email_pending) VALUES($1, $2, $3, $4, $5, $6) "+
+			"ON CONFLICT (user_id, group_key) WHERE read_at IS NULL AND group_key <> '' DO UPDATE SET "+
+			"title = EXCLUDED.title, body = EXCLUDED.body, count = notifications.count + 1, "+
+			"email_pending = notifications.email_pending OR EXCLUDED.email_pending, updated_at = now() "+
+			"RETURNING id",
+		notification.UserID, notification.Type, notification.Title, notification.Body, notification.GroupKey, email,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *NotificationPostgres) Notifications(userID int, filter models.NotificationFilter) ([]models.Notification, error) {
+	const op = "repository.NotificationPostgres.Notifications"
+
+	query := "SELECT " + notificationColumns + " FROM notifications WHERE user_id = $1"
+	args := []any{userID}
+	if filter.UnreadOnly {
+		query += " AND read_at IS NULL"
+	}
+	if filter.Cursor > 0 {
+		args = append(args, filter.Cursor)
+		query += fmt.Sprintf(" AND id < $%d", len(args))
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	notifications, err := r.notifications(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return notifications, nil
+}
+
+func (r *NotificationPostgres) UnreadCount(userID int) (int, error) {
+	const op = "repository.NotificationPostgres.UnreadCount"
+
+	var count int
+	err := r.db.QueryRow(
+		"SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL", userID,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func (r *NotificationPostgres) MarkRead(userID, id int) error {
+	const op = "repository.NotificationPostgres.MarkRead"
+
+	res, err := r.db.Exec(
+		"UPDATE notifications SET read_at = COALESCE(read_at, now()) WHERE id = $1 AND user_id = $2", id, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrNotificationNotFound)
+	}
+
+	return nil
+}
+
+// MarkAllRead отмечает прочитанными все уведомления пользователя и возвращает их число
+func (r *NotificationPostgres) MarkAllRead(userID int) (int, error) {
+	const op = "repository.NotificationPostgres.MarkAllRead"
+
+	res, err := r.db.Exec(
+		"UPDATE notifications SET read_at = now() WHERE user_id = $1 AND read_at IS NULL", userID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(affected), nil
+}
+
+// NotificationPreferences возвращает явно заданные пользователем каналы доставки
+func (r *NotificationPostgres) NotificationPreferences(userID int) ([]models.NotificationPreference, error) {
+	const op = "repository.NotificationPostgres.NotificationPreferences"
+
+	rows, err := r.db.Query("SELECT type, channel FROM notification_preferences WHERE user_id = $1", userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	preferences := make([]models.NotificationPreference, 0)
+	for rows.Next() {
+		var preference models.NotificationPreference
+		if err := rows.Scan(&preference.Type, &preference.Channel); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		preferences = append(preferences, preference)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return preferences, nil
+}
+
+// NotificationChannel возвращает канал доставки уведомлений типа notificationType.
+// Если пользователь его не выбирал, возвращает пустую строку
+func (r *NotificationPostgres) NotificationChannel(userID int, notificationType string) (string, error) {
+	const op = "repository.NotificationPostgres.NotificationChannel"
+
+	var channel string
+	err := r.db.QueryRow(
+		"SELECT channel FROM notification_preferences WHERE user_id = $1 AND type = $2", userID, notificationType,
+	).Scan(&channel)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return channel, nil
+}
+
+func (r *NotificationPostgres) SetNotificationPreferences(userID int, preferences []models.NotificationPreference) error {
+	const op = "repository.NotificationPostgres.SetNotificationPreferences"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	for _, preference := range preferences {
+		if _, err := tx.Exec(
+			"INSERT INTO notification_preferences(user_id, type, channel) VALUES($1, $2, $3) "+
+				"ON CONFLICT (user_id, type) DO UPDATE SET channel = EXCLUDED.channel",
+			userID, preference.Type, preference.Channel,
+		); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ClaimEmailNotifications снимает отметку email_pending с уведомлений не более чем
+// users пользователей и возвращает их, сгруппированные по пользователям. Сводку
+// пользователя забирает одна реплика целиком: пока одна реплика разбирает уведомления,
+// остальные пропускают проход
+func (r *NotificationPostgres) ClaimEmailNotifications(users int) ([]models.Notification, error) {
+	const op = "repository.NotificationPostgres.ClaimEmailNotifications"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRow("SELECT pg_try_advisory_xact_lock($1)", digestLockKey).Scan(&locked); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !locked {
+		return nil, nil
+	}
+
+	rows, err := tx.Query(
+		"WITH claimed AS (UPDATE notifications SET email_pending = FALSE WHERE email_pending AND user_id IN "+
+			"(SELECT user_id FROM notifications WHERE email_pending GROUP BY user_id ORDER BY user_id LIMIT $1) "+
+			"RETURNING "+notificationColumns+") SELECT "+notificationColumns+" FROM claimed ORDER BY user_id, id",
+		users,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	notifications := make([]models.Notification, 0)
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		notifications = append(notifications, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return notifications, nil
+}
+
+// ReleaseEmailNotifications возвращает уведомления в очередь email-сводки, если письмо
+// отправить не удалось
+func (r *NotificationPostgres) ReleaseEmailNotifications(ids []int) error {
+	const op = "repository.NotificationPostgres.ReleaseEmailNotifications"
+
+	pqIDs := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		pqIDs = append(pqIDs, int64(id))
+	}
+
+	if _, err := r.db.Exec(
+		"UPDATE notifications SET email_pending = TRUE WHERE id = ANY($1)", pq.Array(pqIDs),
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *NotificationPostgres) notifications(query string, args ...any) ([]models.Notification, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := make([]models.Notification, 0)
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
+
+func scanNotification(row rowScanner) (models.Notification, error) {
+	var n models.Notification
+	var readAt sql.NullTime
+
+	err := row.Scan(&n.ID, &n.UserID, &n.Type, &n.Title, &n.Body, &n.GroupKey, &n.Count, &readAt,
+		&n.CreatedAt, &n.UpdatedAt)
+	if err != nil {
+		return models.Notification{}, err
+	}
+	if readAt.Valid {
+		n.ReadAt = &readAt.Time
+	}
+
+	return n, nil
+}
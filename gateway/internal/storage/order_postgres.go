@@ -0,0 +1,445 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"log/slog"
+)
+
+const (
+	ticketTypeColumns = "id, event_id, kind, name, price, currency, quantity, reserved, sales_end_at, created_at"
+	orderColumns      = "id, event_id, ticket_type_id, user_id, amount, currency, status, provider, payment_id, " +
+		"payment_url, expires_at, paid_at, refunded_at, created_at"
+)
+
+type OrderPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewOrderPostgres(db *sql.DB, logger *slog.Logger) *OrderPostgres {
+	return &OrderPostgres{db: db, log: logger}
+}
+
+func (r *OrderPostgres) CreateTicketType(ticketType models.TicketType) (int, error) {
+	const op = "repository.OrderPostgres.CreateTicketType"
+
+	var id int
+	err := r.db.QueryRow(
+		"INSERT INTO ticket_types(event_id, kind, name, price, currency, quantity, sales_end_at) "+
+			"VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id",
+		ticketType.EventID, ticketType.Kind, ticketType.Name, ticketType.Price, ticketType.Currency,
+		ticketType.Quantity, ticketType.SalesEndAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *OrderPostgres) TicketType(id int) (models.TicketType, error) {
+	const op = "repository.OrderPostgres.TicketType"
+
+	ticketType, err := scanTicketType(r.db.QueryRow("SELECT "+ticketTypeColumns+" FROM ticket_types WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.TicketType{}, fmt.Errorf("%s: %w", op, ErrTicketTypeNotFound)
+		}
+
+		return models.TicketType{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ticketType, nil
+}
+
+func (r *OrderPostgres) EventTicketTypes(eventID int) ([]models.TicketType, error) {
+	const op = "repository.OrderPostgres.EventTicketTypes"
+
+	rows, err := r.db.Query("SELECT "+ticketTypeColumns+" FROM ticket_types WHERE event_id = $1 ORDER BY id", eventID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	ticketTypes := make([]models.TicketType, 0)
+	for rows.Next() {
+		ticketType, err := scanTicketType(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		ticketTypes = append(ticketTypes, ticketType)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ticketTypes, nil
+}
+
+// TicketRequired сообщает, что на мероприятие продаются билеты, а у пользователя
+// нет оплаченного заказа
+func (r *OrderPostgres) TicketRequired(eventID, userID int) (bool, error) {
+	const op = "repository.OrderPostgres.TicketRequired"
+
+	var required bool
+	err := r.db.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM ticket_types WHERE event_id = $1) AND NOT EXISTS "+
+			"(SELECT 1 FROM orders WHERE event_id = $1 AND user_id = $2 AND status = $3)",
+		eventID, userID, models.OrderStatusPaid,
+	).Scan(&required)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return required, nil
+}
+
+// CreateOrder создаёт неоплаченный заказ и удерживает под него билет. Если билеты
+// закончились, возвращает ErrTicketsSoldOut, если у пользователя уже есть действующий
+// заказ на мероприятие - ErrOrderExists
+func (r *OrderPostgres) CreateOrder(order models.Order) (models.Order, error) {
+	const op = "repository.OrderPostgres.CreateOrder"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := reserveTicket(tx, order.TicketTypeID); err != nil {
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	order, err = scanOrder(tx.QueryRow(
+		"INSERT INTO orders(event_id, ticket_type_id, user_id, amount, currency, expires_at) "+
+			"VALUES($1, $2, $3, $4, $5, $6) RETURNING "+orderColumns,
+		order.EventID, order.TicketTypeID, order.UserID, order.Amount, order.Currency, order.ExpiresAt,
+	))
+	if err != nil {
+		var pgsErr *pq.Error
+		if errors.As(err, &pgsErr) && pgsErr.Code.Name() == "unique_violation" {
+			return models.Order{}, fmt.Errorf("%s: %w", op, ErrOrderExists)
+		}
+
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return order, nil
+}
+
+// SetOrderPayment сохраняет платёж, созданный у провайдера под заказ
+func (r *OrderPostgres) SetOrderPayment(id int, provider, paymentID, paymentURL string) error {
+	const op = "repository.OrderPostgres.SetOrderPayment"
+
+	_, err := r.db.Exec(
+		"UPDATE orders SET provider = $2, payment_id = $3, payment_url = $4 WHERE id = $1",
+		id, provider, paymentID, paymentURL,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *OrderPostgres) Order(id int) (models.Order, error) {
+	const op = "repository.OrderPostgres.Order"
+
+	order, err := scanOrder(r.db.QueryRow("SELECT "+orderColumns+" FROM orders WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Order{}, fmt.Errorf("%s: %w", op, ErrOrderNotFound)
+		}
+
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return order, nil
+}
+
+func (r *OrderPostgres) OrderByPayment(provider, paymentID string) (models.Order, error) {
+	const op = "repository.OrderPostgres.OrderByPayment"
+
+	order, err := scanOrder(r.db.QueryRow(
+		"SELECT "+orderColumns+" FROM orders WHERE provider = $1 AND payment_id = $2", provider, paymentID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Order{}, fmt.Errorf("%s: %w", op, ErrOrderNotFound)
+		}
+
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return order, nil
+}
+
+func (r *OrderPostgres) UserOrders(userID, limit, offset int) ([]models.Order, error) {
+	const op = "repository.OrderPostgres.UserOrders"
+
+	orders, err := r.orders(
+		"SELECT "+orderColumns+" FROM orders WHERE user_id = $1 ORDER BY id DESC LIMIT $2 OFFSET $3", userID, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return orders, nil
+}
+
+func (r *OrderPostgres) EventOrders(eventID, limit, offset int) ([]models.Order, error) {
+	const op = "repository.OrderPostgres.EventOrders"
+
+	orders, err := r.orders(
+		"SELECT "+orderColumns+" FROM orders WHERE event_id = $1 ORDER BY id DESC LIMIT $2 OFFSET $3", eventID, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return orders, nil
+}
+
+// PayOrder отмечает заказ оплаченным и записывает покупателя на мероприятие. Повторная
+// оплата ничего не меняет и возвращает запись nil - так вызывающий отличает новую оплату
+// от повторного извещения. Оплата, которая пришла после истечения заказа, снова
+// удерживает билет, если он ещё есть. Возвращённый заказ оплатить нельзя
+func (r *OrderPostgres) PayOrder(id int) (models.Order, *models.RSVP, error) {
+	const op = "repository.OrderPostgres.PayOrder"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.Order{}, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	order, err := scanOrder(tx.QueryRow("SELECT "+orderColumns+" FROM orders WHERE id = $1 FOR UPDATE", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Order{}, nil, fmt.Errorf("%s: %w", op, ErrOrderNotFound)
+		}
+
+		return models.Order{}, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	switch order.Status {
+	case models.OrderStatusPaid:
+		return order, nil, nil
+	case models.OrderStatusRefunded:
+		return models.Order{}, nil, fmt.Errorf("%s: %w", op, ErrOrderNotPending)
+	case models.OrderStatusExpired:
+		if err := reserveTicket(tx, order.TicketTypeID); err != nil {
+			return models.Order{}, nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	order, err = scanOrder(tx.QueryRow(
+		"UPDATE orders SET status = $2, paid_at = now() WHERE id = $1 RETURNING "+orderColumns, id, models.OrderStatusPaid,
+	))
+	if err != nil {
+		var pgsErr *pq.Error
+		if errors.As(err, &pgsErr) && pgsErr.Code.Name() == "unique_violation" {
+			return models.Order{}, nil, fmt.Errorf("%s: %w", op, ErrOrderExists)
+		}
+
+		return models.Order{}, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rsvp, err := scanRSVP(tx.QueryRow(
+		"INSERT INTO rsvps(event_id, user_id, status) VALUES($1, $2, $3) "+
+			"ON CONFLICT (event_id, user_id) DO UPDATE SET status = EXCLUDED.status RETURNING "+rsvpColumns,
+		order.EventID, order.UserID, models.RSVPStatusGoing,
+	))
+	if err != nil {
+		return models.Order{}, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Order{}, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return order, &rsvp, nil
+}
+
+// ExpireOrder снимает удержание с неоплаченного заказа, срок которого вышел. Если заказ
+// уже оплачен или ещё не истёк, возвращает ErrOrderNotPending
+func (r *OrderPostgres) ExpireOrder(id int) error {
+	const op = "repository.OrderPostgres.ExpireOrder"
+
+	if err := r.releaseOrder(id, true); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CancelOrder снимает удержание с неоплаченного заказа досрочно: платёж отменён
+// или его не удалось создать
+func (r *OrderPostgres) CancelOrder(id int) error {
+	const op = "repository.OrderPostgres.CancelOrder"
+
+	if err := r.releaseOrder(id, false); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RefundOrder отмечает оплаченный заказ возвращённым, освобождает билет и отменяет
+// запись покупателя на мероприятие
+func (r *OrderPostgres) RefundOrder(id int, audit models.AuditEvent) (models.Order, error) {
+	const op = "repository.OrderPostgres.RefundOrder"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	order, err := scanOrder(tx.QueryRow(
+		"UPDATE orders SET status = $2, refunded_at = now() WHERE id = $1 AND status = $3 RETURNING "+orderColumns,
+		id, models.OrderStatusRefunded, models.OrderStatusPaid,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Order{}, fmt.Errorf("%s: %w", op, ErrOrderNotPaid)
+		}
+
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.Exec("UPDATE ticket_types SET reserved = reserved - 1 WHERE id = $1", order.TicketTypeID); err != nil {
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(
+		"UPDATE rsvps SET status = $3 WHERE event_id = $1 AND user_id = $2",
+		order.EventID, order.UserID, models.RSVPStatusCancelled,
+	)
+	if err != nil {
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := insertAuditEvent(tx, audit); err != nil {
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Order{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return order, nil
+}
+
+func (r *OrderPostgres) releaseOrder(id int, onlyOverdue bool) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var ticketTypeID int
+	err = tx.QueryRow(
+		"UPDATE orders SET status = $2 WHERE id = $1 AND status = $3 AND (NOT $4 OR expires_at <= now()) "+
+			"RETURNING ticket_type_id",
+		id, models.OrderStatusExpired, models.OrderStatusPending, onlyOverdue,
+	).Scan(&ticketTypeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrOrderNotPending
+		}
+
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE ticket_types SET reserved = reserved - 1 WHERE id = $1", ticketTypeID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// reserveTicket удерживает один билет типа ticketTypeID или возвращает ErrTicketsSoldOut
+func reserveTicket(tx *sql.Tx, ticketTypeID int) error {
+	res, err := tx.Exec(
+		"UPDATE ticket_types SET reserved = reserved + 1 WHERE id = $1 AND reserved < quantity", ticketTypeID,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrTicketsSoldOut
+	}
+
+	return nil
+}
+
+func (r *OrderPostgres) orders(query string, args ...any) ([]models.Order, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make([]models.Order, 0)
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, rows.Err()
+}
+
+func scanTicketType(row rowScanner) (models.TicketType, error) {
+	var ticketType models.TicketType
+	var salesEndAt sql.NullTime
+
+	err := row.Scan(&ticketType.ID, &ticketType.EventID, &ticketType.Kind, &ticketType.Name, &ticketType.Price,
+		&ticketType.Currency, &ticketType.Quantity, &ticketType.Reserved, &salesEndAt, &ticketType.CreatedAt)
+	if err != nil {
+		return models.TicketType{}, err
+	}
+	if salesEndAt.Valid {
+		ticketType.SalesEndAt = &salesEndAt.Time
+	}
+
+	return ticketType, nil
+}
+
+func scanOrder(row rowScanner) (models.Order, error) {
+	var order models.Order
+	var paymentID sql.NullString
+	var paidAt, refundedAt sql.NullTime
+
+	err := row.Scan(&order.ID, &order.EventID, &order.TicketTypeID, &order.UserID, &order.Amount, &order.Currency,
+		&order.Status, &order.Provider, &paymentID, &order.PaymentURL, &order.ExpiresAt, &paidAt, &refundedAt,
+		&order.CreatedAt)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	order.PaymentID = paymentID.String
+	if paidAt.Valid {
+		order.PaidAt = &paidAt.Time
+	}
+	if refundedAt.Valid {
+		order.RefundedAt = &refundedAt.Time
+	}
+
+	return order, nil
+}
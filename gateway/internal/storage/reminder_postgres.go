@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// reminderLockKey - ключ advisory-блокировки планировщика напоминаний
+const reminderLockKey = 35_000_001
+
+type ReminderPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewReminderPostgres(db *sql.DB, logger *slog.Logger) *ReminderPostgres {
+	return &ReminderPostgres{db: db, log: logger}
+}
+
+// ClaimReminders отмечает отправленными напоминания с отступом offset участникам
+// мероприятий, которые начинаются в промежутке (from, to], и возвращает их. Отменённые
+// и скрытые модерацией мероприятия пропускаются.
+// Пока одна реплика разбирает напоминания, остальные пропускают проход, а вставка
+// с ON CONFLICT DO NOTHING гарантирует, что каждое напоминание достанется только одной
+func (r *ReminderPostgres) ClaimReminders(offset time.Duration, from, to time.Time) ([]models.Reminder, error) {
+	const op = "repository.ReminderPostgres.ClaimReminders"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRow("SELECT pg_try_advisory_xact_lock($1)", reminderLockKey).Scan(&locked); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !locked {
+		return nil, nil
+	}
+
+	rows, err := tx.Query(
+		"WITH claimed AS ("+
+			"INSERT INTO event_reminders(event_id, user_id, offset_minutes) "+
+			"SELECT r.event_id, r.user_id, $1 FROM rsvps r JOIN events e ON e.id = r.event_id "+
+			"WHERE r.status = $2 AND e.starts_at > $3 AND e.starts_at <= $4 "+
+			"AND e.cancelled_at IS NULL AND e.hidden_at IS NULL "+
+			"ON CONFLICT DO NOTHING RETURNING event_id, user_id) "+
+			"SELECT c.event_id, c.user_id, e.title, e.starts_at FROM claimed c JOIN events e ON e.id = c.event_id",
+		int(offset.Minutes()), models.RSVPStatusGoing, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	reminders := make([]models.Reminder, 0)
+	for rows.Next() {
+		reminder := models.Reminder{Offset: offset}
+		if err := rows.Scan(&reminder.EventID, &reminder.UserID, &reminder.EventTitle, &reminder.StartsAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		reminders = append(reminders, reminder)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return reminders, nil
+}
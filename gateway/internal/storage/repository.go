@@ -7,10 +7,60 @@ import (
 
 type Repository struct {
 	*UserPostgres
+	*EventPostgres
+	*CFPPostgres
+	*AgendaPostgres
+	*VenuePostgres
+	*GroupPostgres
+	*SearchPostgres
+	*RSVPPostgres
+	*FeedbackPostgres
+	*CommentPostgres
+	*NotificationPostgres
+	*StreamPostgres
+	*ReminderPostgres
+	*JobPostgres
+	*WebhookPostgres
+	*TelegramPostgres
+	*MessagePostgres
+	*MatchPostgres
+	*FeedPostgres
+	*FollowPostgres
+	*ModerationPostgres
+	*AdminPostgres
+	*AuditPostgres
+	*AccountPostgres
+	*InvitationPostgres
+	*OrderPostgres
 }
 
 func NewRepository(db *sql.DB, logger *slog.Logger) *Repository {
 	return &Repository{
-		UserPostgres: NewUserPostgres(db, logger),
+		UserPostgres:         NewUserPostgres(db, logger),
+		EventPostgres:        NewEventPostgres(db, logger),
+		CFPPostgres:          NewCFPPostgres(db, logger),
+		AgendaPostgres:       NewAgendaPostgres(db, logger),
+		VenuePostgres:        NewVenuePostgres(db, logger),
+		GroupPostgres:        NewGroupPostgres(db, logger),
+		SearchPostgres:       NewSearchPostgres(db, logger),
+		RSVPPostgres:         NewRSVPPostgres(db, logger),
+		FeedbackPostgres:     NewFeedbackPostgres(db, logger),
+		CommentPostgres:      NewCommentPostgres(db, logger),
+		NotificationPostgres: NewNotificationPostgres(db, logger),
+		StreamPostgres:       NewStreamPostgres(db, logger),
+		ReminderPostgres:     NewReminderPostgres(db, logger),
+		JobPostgres:          NewJobPostgres(db, logger),
+		WebhookPostgres:      NewWebhookPostgres(db, logger),
+		TelegramPostgres:     NewTelegramPostgres(db, logger),
+		MessagePostgres:      NewMessagePostgres(db, logger),
+		MatchPostgres:        NewMatchPostgres(db, logger),
+		FeedPostgres:         NewFeedPostgres(db, logger),
+		FollowPostgres:       NewFollowPostgres(db, logger),
+		ModerationPostgres:   NewModerationPostgres(db, logger),
+		AdminPostgres:        NewAdminPostgres(db, logger),
+		AuditPostgres:        NewAuditPostgres(db, logger),
+		AccountPostgres:      NewAccountPostgres(db, logger),
+		InvitationPostgres:   NewInvitationPostgres(db, logger),
+		OrderPostgres:        NewOrderPostgres(db, logger),
 	}
 }
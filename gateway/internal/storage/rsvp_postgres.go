@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+const rsvpColumns = "id, event_id, user_id, status, checked_in_at, checked_in_by, created_at"
+
+type RSVPPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewRSVPPostgres(db *sql.DB, logger *slog.Logger) *RSVPPostgres {
+	return &RSVPPostgres{db: db, log: logger}
+}
+
+// CreateRSVP записывает пользователя на мероприятие. Повторная запись после отмены
+// восстанавливает прежнюю, поэтому номер билета не меняется
+func (r *RSVPPostgres) CreateRSVP(eventID, userID int) (models.RSVP, error) {
+	const op = "repository.RSVPPostgres.CreateRSVP"
+
+	rsvp, err := scanRSVP(r.db.QueryRow(
+		"INSERT INTO rsvps(event_id, user_id, status) VALUES($1, $2, $3) "+
+			"ON CONFLICT (event_id, user_id) DO UPDATE SET status = EXCLUDED.status RETURNING "+rsvpColumns,
+		eventID, userID, models.RSVPStatusGoing,
+	))
+	if err != nil {
+		return models.RSVP{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rsvp, nil
+}
+
+func (r *RSVPPostgres) CancelRSVP(eventID, userID int) error {
+	const op = "repository.RSVPPostgres.CancelRSVP"
+
+	res, err := r.db.Exec(
+		"UPDATE rsvps SET status = $1 WHERE event_id = $2 AND user_id = $3 AND status = $4",
+		models.RSVPStatusCancelled, eventID, userID, models.RSVPStatusGoing,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrRSVPNotFound)
+	}
+
+	return nil
+}
+
+func (r *RSVPPostgres) RSVP(eventID, userID int) (models.RSVP, error) {
+	const op = "repository.RSVPPostgres.RSVP"
+
+	rsvp, err := scanRSVP(r.db.QueryRow(
+		"SELECT "+rsvpColumns+" FROM rsvps WHERE event_id = $1 AND user_id = $2", eventID, userID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.RSVP{}, fmt.Errorf("%s: %w", op, ErrRSVPNotFound)
+		}
+
+		return models.RSVP{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rsvp, nil
+}
+
+func (r *RSVPPostgres) RSVPByID(id int) (models.RSVP, error) {
+	const op = "repository.RSVPPostgres.RSVPByID"
+
+	rsvp, err := scanRSVP(r.db.QueryRow("SELECT "+rsvpColumns+" FROM rsvps WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.RSVP{}, fmt.Errorf("%s: %w", op, ErrRSVPNotFound)
+		}
+
+		return models.RSVP{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rsvp, nil
+}
+
+// CheckIn отмечает приход по билету. Отметка ставится атомарно, поэтому один билет
+// не пройдёт дважды даже при одновременном сканировании на двух входах
+func (r *RSVPPostgres) CheckIn(id, checkedInBy int) (models.RSVP, error) {
+	const op = "repository.RSVPPostgres.CheckIn"
+
+	rsvp, err := scanRSVP(r.db.QueryRow(
+		"UPDATE rsvps SET checked_in_at = now(), checked_in_by = $1 "+
+			"WHERE id = $2 AND status = $3 AND checked_in_at IS NULL RETURNING "+rsvpColumns,
+		checkedInBy, id, models.RSVPStatusGoing,
+	))
+	if err == nil {
+		return rsvp, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return models.RSVP{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Билет не прошёл: выясняем причину
+	rsvp, err = r.RSVPByID(id)
+	if err != nil {
+		return models.RSVP{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if rsvp.Status != models.RSVPStatusGoing {
+		return rsvp, fmt.Errorf("%s: %w", op, ErrRSVPCancelled)
+	}
+
+	return rsvp, fmt.Errorf("%s: %w", op, ErrAlreadyCheckedIn)
+}
+
+func (r *RSVPPostgres) Attendance(eventID int) (models.Attendance, error) {
+	const op = "repository.RSVPPostgres.Attendance"
+
+	var attendance models.Attendance
+	err := r.db.QueryRow(
+		"SELECT COUNT(*), COUNT(checked_in_at) FROM rsvps WHERE event_id = $1 AND status = $2",
+		eventID, models.RSVPStatusGoing,
+	).Scan(&attendance.Going, &attendance.CheckedIn)
+	if err != nil {
+		return models.Attendance{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return attendance, nil
+}
+
+func scanRSVP(row rowScanner) (models.RSVP, error) {
+	var rsvp models.RSVP
+	var checkedInAt sql.NullTime
+	var checkedInBy sql.NullInt64
+
+	err := row.Scan(&rsvp.ID, &rsvp.EventID, &rsvp.UserID, &rsvp.Status, &checkedInAt, &checkedInBy, &rsvp.CreatedAt)
+	if err != nil {
+		return models.RSVP{}, err
+	}
+
+	if checkedInAt.Valid {
+		rsvp.CheckedInAt = &checkedInAt.Time
+	}
+	if checkedInBy.Valid {
+		by := int(checkedInBy.Int64)
+		rsvp.CheckedInBy = &by
+	}
+
+	return rsvp, nil
+}
@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"fmt"
+	"html"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// ts_headline размечает совпадения управляющими символами, а не тегами: текст
+// сниппета пишут пользователи, поэтому он экранируется до вставки <mark>
+const (
+	headlineStart   = "\x02"
+	headlineStop    = "\x03"
+	headlineOptions = "StartSel=" + headlineStart + ", StopSel=" + headlineStop + ", MaxFragments=2, MaxWords=30, MinWords=10"
+)
+
+type SearchPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewSearchPostgres(db *sql.DB, logger *slog.Logger) *SearchPostgres {
+	return &SearchPostgres{db: db, log: logger}
+}
+
+// Search ищет мероприятия, сообщества и разработчиков по tsvector-колонкам с нечётким
+// совпадением названий через pg_trgm. Фильтр, неприменимый к типу (даты для сообществ,
+// навык для мероприятий), исключает этот тип из выдачи
+func (r *SearchPostgres) Search(query models.SearchQuery) ([]models.SearchResult, error) {
+	const op = "repository.SearchPostgres.Search"
+
+	b := searchBuilder{args: []any{query.Query}}
+	parts := make([]string, 0, 3)
+
+	if query.Wants(models.SearchTypeEvent) && query.Skill == "" {
+		where := []string{"(e.search_vector @@ q.query OR e.title % $1)", "e.hidden_at IS NULL"}
+		if query.City != "" {
+			where = append(where, "e.city ILIKE "+b.arg(escapeLike(query.City)))
+		}
+		if query.DateFrom != nil {
+			where = append(where, "e.starts_at >= "+b.arg(*query.DateFrom))
+		}
+		if query.DateTo != nil {
+			where = append(where, "e.starts_at < "+b.arg(*query.DateTo))
+		}
+
+		parts = append(parts, "SELECT '"+models.SearchTypeEvent+"' AS type, e.id, e.title, "+
+			headline("e.description")+" AS snippet, "+
+			"ts_rank_cd(e.search_vector, q.query) + similarity(e.title, $1) AS rank "+
+			"FROM events e, q WHERE "+strings.Join(where, " AND "))
+	}
+
+	if query.Wants(models.SearchTypeGroup) && query.Skill == "" && query.DateFrom == nil && query.DateTo == nil {
+		where := []string{"(g.search_vector @@ q.query OR g.name % $1)"}
+		if query.City != "" {
+			where = append(where, "g.city ILIKE "+b.arg(escapeLike(query.City)))
+		}
+
+		parts = append(parts, "SELECT '"+models.SearchTypeGroup+"', g.id, g.name, "+
+			headline("g.description")+", "+
+			"ts_rank_cd(g.search_vector, q.query) + similarity(g.name, $1) "+
+			"FROM groups g, q WHERE "+strings.Join(where, " AND "))
+	}
+
+	if query.Wants(models.SearchTypeDeveloper) && query.DateFrom == nil && query.DateTo == nil {
+		where := []string{
+			"(u.search_vector @@ q.query OR (u.name || ' ' || COALESCE(u.username, '')) % $1)", "u.deleted_at IS NULL",
+		}
+		if query.City != "" {
+			where = append(where, "u.city ILIKE "+b.arg(escapeLike(query.City)))
+		}
+		if query.Skill != "" {
+			where = append(where, "u.skills @> ARRAY["+b.arg(strings.ToLower(query.Skill))+"]::text[]")
+		}
+
+		parts = append(parts, "SELECT '"+models.SearchTypeDeveloper+"', u.id, COALESCE(NULLIF(u.name, ''), u.username, ''), "+
+			headline("u.bio")+", "+
+			"ts_rank_cd(u.search_vector, q.query) + similarity(u.name || ' ' || COALESCE(u.username, ''), $1) "+
+			"FROM users u, q WHERE "+strings.Join(where, " AND "))
+	}
+
+	if len(parts) == 0 {
+		return []models.SearchResult{}, nil
+	}
+
+	sqlQuery := "WITH q AS (SELECT websearch_to_tsquery('russian', $1) || websearch_to_tsquery('english', $1) || " +
+		"websearch_to_tsquery('simple', $1) AS query) " +
+		strings.Join(parts, " UNION ALL ") +
+		" ORDER BY rank DESC, type, id LIMIT " + b.arg(query.Limit) + " OFFSET " + b.arg(query.Offset)
+
+	rows, err := r.db.Query(sqlQuery, b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	results := make([]models.SearchResult, 0)
+	for rows.Next() {
+		var result models.SearchResult
+		if err := rows.Scan(&result.Type, &result.ID, &result.Title, &result.Snippet, &result.Rank); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result.Snippet = markSnippet(result.Snippet)
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return results, nil
+}
+
+// headline строит сниппет колонки column. Управляющие символы разметки вырезаются из
+// исходного текста, чтобы пользователь не мог открыть <mark> сам
+func headline(column string) string {
+	return "ts_headline('russian', translate(" + column + ", '" + headlineStart + headlineStop + "', ''), q.query, '" +
+		headlineOptions + "')"
+}
+
+// markSnippet экранирует сниппет как HTML и заменяет разметку ts_headline тегами mark
+func markSnippet(snippet string) string {
+	return strings.NewReplacer(headlineStart, "<mark>", headlineStop, "</mark>").Replace(html.EscapeString(snippet))
+}
+
+type searchBuilder struct {
+	args []any
+}
+
+// arg добавляет параметр запроса и возвращает его плейсхолдер
+func (b *searchBuilder) arg(v any) string {
+	b.args = append(b.args, v)
+
+	return "$" + strconv.Itoa(len(b.args))
+}
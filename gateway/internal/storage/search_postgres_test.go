@@ -0,0 +1,45 @@
+package storage
+
+import "testing"
+
+func TestMarkSnippet(t *testing.T) {
+	tests := []struct {
+		name    string
+		snippet string
+		want    string
+	}{
+		{
+			name:    "highlight",
+			snippet: "Доклады про " + headlineStart + "конкурентность" + headlineStop + " в Go",
+			want:    "Доклады про <mark>конкурентность</mark> в Go",
+		},
+		{
+			name:    "script is escaped",
+			snippet: "<script>alert(1)</script> " + headlineStart + "Go" + headlineStop,
+			want:    "&lt;script&gt;alert(1)&lt;/script&gt; <mark>Go</mark>",
+		},
+		{
+			name:    "attribute injection",
+			snippet: `<img src=x onerror="alert(1)">`,
+			want:    "&lt;img src=x onerror=&#34;alert(1)&#34;&gt;",
+		},
+		{
+			name:    "user mark is text",
+			snippet: "<mark>" + headlineStart + "go" + headlineStop + "</mark>",
+			want:    "&lt;mark&gt;<mark>go</mark>&lt;/mark&gt;",
+		},
+		{
+			name:    "empty",
+			snippet: "",
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markSnippet(tt.snippet); got != tt.want {
+				t.Errorf("markSnippet(%q) = %q, want %q", tt.snippet, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/lib/pq"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+const streamChannel = "stream_events"
+
+// StreamListener слушает NOTIFY о новых событиях журнала на отдельном соединении
+// с Postgres. Так события, записанные любой репликой шлюза, доходят до всех реплик
+type StreamListener struct {
+	listener *pq.Listener
+	log      *slog.Logger
+}
+
+func NewStreamListener(dsn string, logger *slog.Logger) (*StreamListener, error) {
+	const op = "repository.StreamListener.New"
+
+	listener := pq.NewListener(dsn, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			logger.Error("stream listener connection problem", slog.String("error", err.Error()))
+		}
+	})
+
+	if err := listener.Listen(streamChannel); err != nil {
+		listener.Close()
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &StreamListener{listener: listener, log: logger}, nil
+}
+
+// Events возвращает канал идентификаторов новых событий журнала в порядке их фиксации.
+// Ноль означает, что соединение переустановлено и часть оповещений могла потеряться:
+// получателю нужно дочитать журнал самому
+func (l *StreamListener) Events(ctx context.Context) <-chan int64 {
+	ids := make(chan int64, 256)
+
+	go func() {
+		defer l.listener.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case n := <-l.listener.Notify:
+				var id int64
+				if n != nil {
+					parsed, err := strconv.ParseInt(n.Extra, 10, 64)
+					if err != nil {
+						l.log.Error("invalid stream notification", slog.String("payload", n.Extra))
+						continue
+					}
+					id = parsed
+				}
+
+				select {
+				case ids <- id:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return ids
+}
@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"fmt"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+const streamEventColumns = "id, user_id, topic, type, payload, created_at"
+
+type StreamPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewStreamPostgres(db *sql.DB, logger *slog.Logger) *StreamPostgres {
+	return &StreamPostgres{db: db, log: logger}
+}
+
+// CreateStreamEvent записывает событие в журнал. Триггер на вставку оповещает
+// все реплики через NOTIFY stream_events
+func (r *StreamPostgres) CreateStreamEvent(event models.StreamEvent) (int64, error) {
+	const op = "repository.StreamPostgres.CreateStreamEvent"
+
+	var id int64
+	err := r.db.QueryRow(
+		"INSERT INTO stream_events(user_id, topic, type, payload) VALUES($1, $2, $3, $4) RETURNING id",
+		event.UserID, event.Topic, event.Type, []byte(event.Payload),
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *StreamPostgres) StreamEvent(id int64) (models.StreamEvent, error) {
+	const op = "repository.StreamPostgres.StreamEvent"
+
+	events, err := r.streamEvents("SELECT "+streamEventColumns+" FROM stream_events WHERE id = $1", id)
+	if err != nil {
+		return models.StreamEvent{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(events) == 0 {
+		return models.StreamEvent{}, fmt.Errorf("%s: %w", op, ErrStreamEventNotFound)
+	}
+
+	return events[0], nil
+}
+
+// StreamEventsAfter возвращает все события журнала после afterID по возрастанию
+func (r *StreamPostgres) StreamEventsAfter(afterID int64, limit int) ([]models.StreamEvent, error) {
+	const op = "repository.StreamPostgres.StreamEventsAfter"
+
+	events, err := r.streamEvents(
+		"SELECT "+streamEventColumns+" FROM stream_events WHERE id > $1 ORDER BY id LIMIT $2", afterID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
+
+// SubscriberStreamEvents возвращает события после afterID, адресованные пользователю
+// или его темам, - для досылки пропущенного после переподключения
+func (r *StreamPostgres) SubscriberStreamEvents(userID int, topics []string, afterID int64, limit int) ([]models.StreamEvent, error) {
+	const op = "repository.StreamPostgres.SubscriberStreamEvents"
+
+	events, err := r.streamEvents(
+		"SELECT "+streamEventColumns+" FROM stream_events "+
+			"WHERE id > $1 AND (user_id = $2 OR topic = ANY($3)) ORDER BY id LIMIT $4",
+		afterID, userID, pq.Array(topics), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
+
+func (r *StreamPostgres) LastStreamEventID() (int64, error) {
+	const op = "repository.StreamPostgres.LastStreamEventID"
+
+	var id int64
+	if err := r.db.QueryRow("SELECT COALESCE(MAX(id), 0) FROM stream_events").Scan(&id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// DeleteStreamEventsBefore удаляет из журнала события старше before
+func (r *StreamPostgres) DeleteStreamEventsBefore(before time.Time) (int, error) {
+	const op = "repository.StreamPostgres.DeleteStreamEventsBefore"
+
+	res, err := r.db.Exec("DELETE FROM stream_events WHERE created_at < $1", before)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(affected), nil
+}
+
+func (r *StreamPostgres) streamEvents(query string, args ...any) ([]models.StreamEvent, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]models.StreamEvent, 0)
+	for rows.Next() {
+		var event models.StreamEvent
+		var userID sql.NullInt64
+		var payload []byte
+		if err := rows.Scan(&event.ID, &userID, &event.Topic, &event.Type, &payload, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		if userID.Valid {
+			id := int(userID.Int64)
+			event.UserID = &id
+		}
+		event.Payload = payload
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
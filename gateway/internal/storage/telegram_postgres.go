@@ -0,0 +1,259 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	// telegramPollLockKey - ключ сессионной advisory-блокировки получения обновлений бота
+	telegramPollLockKey = 38_000_001
+	// telegramPollLockCheck - как часто проверять, что соединение с блокировкой живо
+	telegramPollLockCheck = 15 * time.Second
+)
+
+type TelegramPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewTelegramPostgres(db *sql.DB, logger *slog.Logger) *TelegramPostgres {
+	return &TelegramPostgres{db: db, log: logger}
+}
+
+// CreateTelegramLinkCode сохраняет код привязки. Прежние коды пользователя удаляются
+func (r *TelegramPostgres) CreateTelegramLinkCode(userID int, code string, expiresAt time.Time) error {
+	const op = "repository.TelegramPostgres.CreateTelegramLinkCode"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM telegram_link_codes WHERE user_id = $1 OR expires_at < now()", userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.Exec(
+		"INSERT INTO telegram_link_codes(code, user_id, expires_at) VALUES($1, $2, $3)", code, userID, expiresAt,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// LinkTelegramAccount погашает код и привязывает Telegram-аккаунт к его владельцу.
+// Если этот Telegram-аккаунт был привязан к другому пользователю, старая привязка снимается
+func (r *TelegramPostgres) LinkTelegramAccount(code string, telegramUserID int64) (int, error) {
+	const op = "repository.TelegramPostgres.LinkTelegramAccount"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var userID int
+	err = tx.QueryRow(
+		"DELETE FROM telegram_link_codes WHERE code = $1 AND expires_at > now() RETURNING user_id", code,
+	).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, ErrLinkCodeNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.Exec(
+		"DELETE FROM telegram_accounts WHERE telegram_user_id = $1 AND user_id <> $2", telegramUserID, userID,
+	); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.Exec(
+		"INSERT INTO telegram_accounts(user_id, telegram_user_id) VALUES($1, $2) "+
+			"ON CONFLICT (user_id) DO UPDATE SET telegram_user_id = EXCLUDED.telegram_user_id, linked_at = now()",
+		userID, telegramUserID,
+	); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userID, nil
+}
+
+func (r *TelegramPostgres) UnlinkTelegramAccount(userID int) error {
+	const op = "repository.TelegramPostgres.UnlinkTelegramAccount"
+
+	res, err := r.db.Exec("DELETE FROM telegram_accounts WHERE user_id = $1", userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrTelegramAccountNotFound)
+	}
+
+	return nil
+}
+
+// UserIDByTelegramID возвращает пользователя, к которому привязан Telegram-аккаунт
+func (r *TelegramPostgres) UserIDByTelegramID(telegramUserID int64) (int, error) {
+	const op = "repository.TelegramPostgres.UserIDByTelegramID"
+
+	var userID int
+	err := r.db.QueryRow(
+		"SELECT user_id FROM telegram_accounts WHERE telegram_user_id = $1", telegramUserID,
+	).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, ErrTelegramAccountNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userID, nil
+}
+
+func (r *TelegramPostgres) AddGroupChat(groupID int, chatID int64) error {
+	const op = "repository.TelegramPostgres.AddGroupChat"
+
+	if _, err := r.db.Exec(
+		"INSERT INTO telegram_group_chats(group_id, chat_id) VALUES($1, $2) ON CONFLICT DO NOTHING", groupID, chatID,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *TelegramPostgres) RemoveGroupChat(groupID int, chatID int64) error {
+	const op = "repository.TelegramPostgres.RemoveGroupChat"
+
+	if _, err := r.db.Exec(
+		"DELETE FROM telegram_group_chats WHERE group_id = $1 AND chat_id = $2", groupID, chatID,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RemoveChat отвязывает чат от всех сообществ, например когда бота удалили из чата
+func (r *TelegramPostgres) RemoveChat(chatID int64) error {
+	const op = "repository.TelegramPostgres.RemoveChat"
+
+	if _, err := r.db.Exec("DELETE FROM telegram_group_chats WHERE chat_id = $1", chatID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *TelegramPostgres) GroupChats(groupID int) ([]int64, error) {
+	const op = "repository.TelegramPostgres.GroupChats"
+
+	rows, err := r.db.Query("SELECT chat_id FROM telegram_group_chats WHERE group_id = $1 ORDER BY linked_at", groupID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	chats := make([]int64, 0)
+	for rows.Next() {
+		var chatID int64
+		if err := rows.Scan(&chatID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		chats = append(chats, chatID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return chats, nil
+}
+
+// WithTelegramPollLock запускает poll, если удалось взять блокировку получения обновлений, и
+// держит её, пока poll не вернётся. Блокировка сессионная: она живёт на отдельном соединении
+// и снимается Postgres, если реплика упала. Если соединение оборвалось, контекст poll
+// отменяется - блокировку к этому моменту может взять другая реплика. Возвращает false,
+// если обновления уже получает другая реплика
+func (r *TelegramPostgres) WithTelegramPollLock(ctx context.Context, poll func(ctx context.Context)) (bool, error) {
+	const op = "repository.TelegramPostgres.WithTelegramPollLock"
+
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", telegramPollLockKey).Scan(&locked); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	if !locked {
+		return false, nil
+	}
+
+	pollCtx, cancel := context.WithCancel(ctx)
+	checked := make(chan struct{})
+	go func() {
+		defer close(checked)
+		r.watchLockConn(pollCtx, conn, cancel)
+	}()
+
+	poll(pollCtx)
+	cancel()
+	<-checked
+
+	// ctx уже может быть отменён, а блокировку нужно снять до возврата соединения в пул
+	if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", telegramPollLockKey); err != nil {
+		r.log.Warn("failed to release telegram poll lock", slog.String("error", err.Error()))
+		// соединение с неснятой блокировкой нельзя возвращать в пул
+		_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+	}
+
+	return true, nil
+}
+
+// watchLockConn отменяет поллинг, если соединение, на котором держится блокировка, оборвалось
+func (r *TelegramPostgres) watchLockConn(ctx context.Context, conn *sql.Conn, cancel context.CancelFunc) {
+	ticker := time.NewTicker(telegramPollLockCheck)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := conn.PingContext(ctx); err != nil {
+				if ctx.Err() == nil {
+					r.log.Error("telegram poll lock connection lost", slog.String("error", err.Error()))
+					cancel()
+				}
+				return
+			}
+		}
+	}
+}
@@ -9,6 +9,16 @@ import (
 	"log/slog"
 )
 
+const userColumns = "id, email, email_duplicate, pass_hash, role, name, COALESCE(username, ''), city, bio, skills, " +
+	"seniority, suspended_at, suspended_until, suspension_reason, token_version, deletion_scheduled_at, deleted_at"
+
+// promoteEmailDuplicateQuery делает основным старейший аккаунт с адресом $1, если
+// основного аккаунта с этим адресом не осталось. Выполняется, когда аккаунт уходит из
+// группы дубликатов, чтобы адрес снова защищал уникальный индекс
+const promoteEmailDuplicateQuery = "UPDATE users SET email_duplicate = FALSE " +
+	"WHERE id = (SELECT min(id) FROM users WHERE lower(email) = lower($1)) " +
+	"AND NOT EXISTS (SELECT 1 FROM users WHERE lower(email) = lower($1) AND NOT email_duplicate)"
+
 type UserPostgres struct {
 	db  *sql.DB
 	log *slog.Logger
@@ -22,7 +32,9 @@ func (r *UserPostgres) CreateUser(user models.User) (int, error) {
 	const op = "repository.AuthPostgres.CreateUser"
 
 	var id int
-	err := r.db.QueryRow("INSERT INTO users(email, pass_hash) VALUES($1, $2) RETURNING id", user.Email, user.PassHash).Scan(&id)
+	err := r.db.QueryRow(
+		"INSERT INTO users(email, pass_hash) VALUES(lower($1), $2) RETURNING id", user.Email, user.PassHash,
+	).Scan(&id)
 
 	if err != nil {
 		var pgsErr *pq.Error
@@ -36,12 +48,14 @@ func (r *UserPostgres) CreateUser(user models.User) (int, error) {
 	return id, nil
 }
 
+// UserByEmail ищет пользователя по почте без учёта регистра. Среди дубликатов,
+// заведённых до нормализации почты, возвращает основной аккаунт
 func (r *UserPostgres) UserByEmail(email string) (models.User, error) {
-	const op = "repository.AuthPostgres.User"
-	var user models.User
+	const op = "repository.AuthPostgres.UserByEmail"
 
-	err := r.db.QueryRow("SELECT id, email, pass_hash FROM users WHERE email = $1", email).
-		Scan(&user.ID, &user.Email, &user.PassHash)
+	user, err := scanUser(r.db.QueryRow(
+		"SELECT "+userColumns+" FROM users WHERE lower(email) = lower($1) ORDER BY email_duplicate, id LIMIT 1", email,
+	))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.User{}, fmt.Errorf("%s: %w", op, ErrUserNotFound)
@@ -53,12 +67,41 @@ func (r *UserPostgres) UserByEmail(email string) (models.User, error) {
 	return user, nil
 }
 
+// UsersByEmail возвращает все аккаунты с почтой без учёта регистра, основной первым.
+// Больше одного их бывает только у дубликатов, заведённых до нормализации почты
+func (r *UserPostgres) UsersByEmail(email string) ([]models.User, error) {
+	const op = "repository.AuthPostgres.UsersByEmail"
+
+	rows, err := r.db.Query(
+		"SELECT "+userColumns+" FROM users WHERE lower(email) = lower($1) ORDER BY email_duplicate, id", email,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	users := make([]models.User, 0, 1)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(users) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+	}
+
+	return users, nil
+}
+
 func (r *UserPostgres) User(id int) (models.User, error) {
 	const op = "repository.AuthPostgres.User"
-	var user models.User
 
-	err := r.db.QueryRow("SELECT id, email, pass_hash FROM users WHERE id = $1", id).
-		Scan(&user.ID, &user.Email, &user.PassHash)
+	user, err := scanUser(r.db.QueryRow("SELECT "+userColumns+" FROM users WHERE id = $1", id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.User{}, fmt.Errorf("%s: %w", op, ErrUserNotFound)
@@ -69,3 +112,116 @@ func (r *UserPostgres) User(id int) (models.User, error) {
 
 	return user, nil
 }
+
+func (r *UserPostgres) UpdateProfile(id int, profile models.Profile) error {
+	const op = "repository.AuthPostgres.UpdateProfile"
+
+	res, err := r.db.Exec(
+		"UPDATE users SET name = $1, username = NULLIF($2, ''), city = $3, bio = $4, skills = $5, seniority = $6 WHERE id = $7",
+		profile.Name, profile.Username, profile.City, profile.Bio, pq.Array(profile.Skills), profile.Seniority, id,
+	)
+	if err != nil {
+		var pgsErr *pq.Error
+		if errors.As(err, &pgsErr) && pgsErr.Code.Name() == "unique_violation" {
+			return fmt.Errorf("%s: %w", op, ErrUsernameTaken)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// UsersByUsernames возвращает пользователей с указанными именами, без учёта регистра
+func (r *UserPostgres) UsersByUsernames(usernames []string) ([]models.User, error) {
+	const op = "repository.AuthPostgres.UsersByUsernames"
+
+	rows, err := r.db.Query(
+		"SELECT "+userColumns+" FROM users WHERE lower(username) = ANY($1)", pq.Array(usernames),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	users := make([]models.User, 0)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+// UpdatePassword меняет хеш пароля и отзывает выданные токены. Возвращает новую версию токенов
+func (r *UserPostgres) UpdatePassword(id int, passHash string) (int, error) {
+	const op = "repository.AuthPostgres.UpdatePassword"
+
+	var version int
+	err := r.db.QueryRow(
+		"UPDATE users SET pass_hash = $2, token_version = token_version + 1 WHERE id = $1 RETURNING token_version",
+		id, passHash,
+	).Scan(&version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return version, nil
+}
+
+// CancelDeletion отменяет запрошенное удаление аккаунта
+func (r *UserPostgres) CancelDeletion(id int) error {
+	const op = "repository.AuthPostgres.CancelDeletion"
+
+	_, err := r.db.Exec("UPDATE users SET deletion_scheduled_at = NULL WHERE id = $1 AND deleted_at IS NULL", id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func scanUser(row rowScanner) (models.User, error) {
+	var user models.User
+	var suspendedAt, suspendedUntil, deletionScheduledAt, deletedAt sql.NullTime
+
+	err := row.Scan(&user.ID, &user.Email, &user.EmailDuplicate, &user.PassHash, &user.Role, &user.Name, &user.Username, &user.City, &user.Bio,
+		pq.Array(&user.Skills), &user.Seniority, &suspendedAt, &suspendedUntil, &user.SuspensionReason,
+		&user.TokenVersion, &deletionScheduledAt, &deletedAt)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if suspendedAt.Valid {
+		user.SuspendedAt = &suspendedAt.Time
+	}
+	if suspendedUntil.Valid {
+		user.SuspendedUntil = &suspendedUntil.Time
+	}
+	if deletionScheduledAt.Valid {
+		user.DeletionScheduledAt = &deletionScheduledAt.Time
+	}
+	if deletedAt.Valid {
+		user.DeletedAt = &deletedAt.Time
+	}
+
+	return user, nil
+}
@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+const venueColumns = "v.id, v.name, v.address, v.latitude, v.longitude, v.capacity, v.accessibility_notes, " +
+	"v.host_company, COALESCE(v.created_by, 0), v.created_at"
+
+type VenuePostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewVenuePostgres(db *sql.DB, logger *slog.Logger) *VenuePostgres {
+	return &VenuePostgres{db: db, log: logger}
+}
+
+func (r *VenuePostgres) CreateVenue(venue models.Venue) (int, error) {
+	const op = "repository.VenuePostgres.CreateVenue"
+
+	var id int
+	err := r.db.QueryRow(
+		"INSERT INTO venues(name, address, latitude, longitude, capacity, accessibility_notes, host_company, created_by) "+
+			"VALUES($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id",
+		venue.Name, venue.Address, venue.Location.Lat, venue.Location.Lon, venue.Capacity,
+		venue.AccessibilityNotes, venue.HostCompany, venue.CreatedBy,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *VenuePostgres) UpdateVenue(venue models.Venue) error {
+	const op = "repository.VenuePostgres.UpdateVenue"
+
+	res, err := r.db.Exec(
+		"UPDATE venues SET name = $1, address = $2, latitude = $3, longitude = $4, capacity = $5, "+
+			"accessibility_notes = $6, host_company = $7 WHERE id = $8",
+		venue.Name, venue.Address, venue.Location.Lat, venue.Location.Lon, venue.Capacity,
+		venue.AccessibilityNotes, venue.HostCompany, venue.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrVenueNotFound)
+	}
+
+	return nil
+}
+
+func (r *VenuePostgres) Venue(id int) (models.Venue, error) {
+	const op = "repository.VenuePostgres.Venue"
+
+	venue, err := scanVenue(r.db.QueryRow("SELECT "+venueColumns+" FROM venues v WHERE v.id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Venue{}, fmt.Errorf("%s: %w", op, ErrVenueNotFound)
+		}
+
+		return models.Venue{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return venue, nil
+}
+
+// Venues ищет площадки по подстроке в названии или адресе. При заданном filter.Near
+// результаты ограничены радиусом и отсортированы по расстоянию
+func (r *VenuePostgres) Venues(filter models.VenueFilter) ([]models.Venue, error) {
+	const op = "repository.VenuePostgres.Venues"
+
+	args := make([]any, 0)
+	where := make([]string, 0)
+	distance := "NULL::float8"
+	order := "lower(v.name)"
+
+	if filter.Near != nil {
+		args = append(args, filter.Near.Point.Lat, filter.Near.Point.Lon, filter.Near.RadiusKm*1000)
+		where = append(where, withinRadiusExpr("v"))
+		distance = distanceExpr("v")
+		order = "distance"
+	}
+
+	if filter.Query != "" {
+		args = append(args, "%"+escapeLike(filter.Query)+"%")
+		where = append(where, fmt.Sprintf("(v.name ILIKE $%d OR v.address ILIKE $%d)", len(args), len(args)))
+	}
+
+	query := "SELECT " + venueColumns + ", " + distance + " AS distance FROM venues v"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(" ORDER BY %s, v.id LIMIT $%d OFFSET $%d", order, len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	venues := make([]models.Venue, 0)
+	for rows.Next() {
+		var distance sql.NullFloat64
+		venue, err := scanVenue(rows, &distance)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if distance.Valid {
+			venue.DistanceKm = &distance.Float64
+		}
+		venues = append(venues, venue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return venues, nil
+}
+
+// scanVenue читает колонки venueColumns и следующие за ними колонки в extra
+func scanVenue(row rowScanner, extra ...any) (models.Venue, error) {
+	var venue models.Venue
+
+	dest := []any{&venue.ID, &venue.Name, &venue.Address, &venue.Location.Lat, &venue.Location.Lon, &venue.Capacity,
+		&venue.AccessibilityNotes, &venue.HostCompany, &venue.CreatedBy, &venue.CreatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return models.Venue{}, err
+	}
+
+	return venue, nil
+}
+
+// distanceExpr - расстояние в километрах от точки ($1, $2) до площадки alias.
+// Используется вместе с withinRadiusExpr, поэтому параметры должны идти первыми
+func distanceExpr(alias string) string {
+	return fmt.Sprintf("earth_distance(ll_to_earth($1, $2), ll_to_earth(%[1]s.latitude, %[1]s.longitude)) / 1000", alias)
+}
+
+// withinRadiusExpr отбирает площадки в радиусе $3 метров от точки ($1, $2).
+// earth_box использует gist-индекс idx_venues_location, earth_distance отсекает углы куба
+func withinRadiusExpr(alias string) string {
+	return fmt.Sprintf("earth_box(ll_to_earth($1, $2), $3) @> ll_to_earth(%[1]s.latitude, %[1]s.longitude) "+
+		"AND earth_distance(ll_to_earth($1, $2), ll_to_earth(%[1]s.latitude, %[1]s.longitude)) <= $3", alias)
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
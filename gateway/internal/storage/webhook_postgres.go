@@ -0,0 +1,246 @@
+package storage
+
+import (
+	"database/sql"
+	"dev_meets/internal/domain/models"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+const (
+	webhookColumns  = "id, group_id, url, secret, event_types, active, failure_count, disabled_at, created_at"
+	deliveryColumns = "id, webhook_id, delivery_id, event_type, payload, attempt, status_code, error, duration_ms, success, created_at"
+)
+
+type WebhookPostgres struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewWebhookPostgres(db *sql.DB, logger *slog.Logger) *WebhookPostgres {
+	return &WebhookPostgres{db: db, log: logger}
+}
+
+func (r *WebhookPostgres) CreateWebhook(webhook models.Webhook) (int, error) {
+	const op = "repository.WebhookPostgres.CreateWebhook"
+
+	var id int
+	err := r.db.QueryRow(
+		"INSERT INTO webhooks(group_id, url, secret, event_types) VALUES($1, $2, $3, $4) RETURNING id",
+		webhook.GroupID, webhook.URL, webhook.Secret, pq.Array(webhook.EventTypes),
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *WebhookPostgres) Webhook(id int) (models.Webhook, error) {
+	const op = "repository.WebhookPostgres.Webhook"
+
+	webhook, err := scanWebhook(r.db.QueryRow("SELECT "+webhookColumns+" FROM webhooks WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Webhook{}, fmt.Errorf("%s: %w", op, ErrWebhookNotFound)
+		}
+
+		return models.Webhook{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return webhook, nil
+}
+
+func (r *WebhookPostgres) GroupWebhooks(groupID int) ([]models.Webhook, error) {
+	const op = "repository.WebhookPostgres.GroupWebhooks"
+
+	webhooks, err := r.webhooks("SELECT "+webhookColumns+" FROM webhooks WHERE group_id = $1 ORDER BY id", groupID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return webhooks, nil
+}
+
+// SubscribedWebhooks возвращает включённые вебхуки сообщества, подписанные на eventType
+func (r *WebhookPostgres) SubscribedWebhooks(groupID int, eventType string) ([]models.Webhook, error) {
+	const op = "repository.WebhookPostgres.SubscribedWebhooks"
+
+	webhooks, err := r.webhooks(
+		"SELECT "+webhookColumns+" FROM webhooks WHERE group_id = $1 AND active AND $2 = ANY(event_types) ORDER BY id",
+		groupID, eventType,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return webhooks, nil
+}
+
+// UpdateWebhook меняет адрес, события и состояние вебхука. Включение
+// сбрасывает счётчик неудачных доставок
+func (r *WebhookPostgres) UpdateWebhook(webhook models.Webhook) error {
+	const op = "repository.WebhookPostgres.UpdateWebhook"
+
+	res, err := r.db.Exec(
+		"UPDATE webhooks SET url = $1, event_types = $2, active = $3, "+
+			"failure_count = CASE WHEN $3 THEN 0 ELSE failure_count END, "+
+			"disabled_at = CASE WHEN $3 THEN NULL ELSE COALESCE(disabled_at, now()) END "+
+			"WHERE id = $4",
+		webhook.URL, pq.Array(webhook.EventTypes), webhook.Active, webhook.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrWebhookNotFound)
+	}
+
+	return nil
+}
+
+func (r *WebhookPostgres) DeleteWebhook(id int) error {
+	const op = "repository.WebhookPostgres.DeleteWebhook"
+
+	res, err := r.db.Exec("DELETE FROM webhooks WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrWebhookNotFound)
+	}
+
+	return nil
+}
+
+// RecordDelivery сохраняет попытку доставки с очередным номером и обновляет счётчик неудач подряд.
+// Вебхук, набравший disableAfter неудач подряд, отключается - тогда возвращается true
+func (r *WebhookPostgres) RecordDelivery(delivery models.WebhookDelivery, disableAfter int) (bool, error) {
+	const op = "repository.WebhookPostgres.RecordDelivery"
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		"INSERT INTO webhook_deliveries(webhook_id, delivery_id, event_type, payload, attempt, status_code, error, "+
+			"duration_ms, success) SELECT $1, $2, $3, $4, COUNT(*) + 1, $5, $6, $7, $8 "+
+			"FROM webhook_deliveries WHERE delivery_id = $2",
+		delivery.WebhookID, delivery.DeliveryID, delivery.EventType, []byte(delivery.Payload),
+		delivery.StatusCode, delivery.Error, delivery.Duration.Milliseconds(), delivery.Success,
+	); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var disabled bool
+	err = tx.QueryRow(
+		"WITH old AS (SELECT id, active FROM webhooks WHERE id = $3 FOR UPDATE) "+
+			"UPDATE webhooks w SET failure_count = CASE WHEN $1 THEN 0 ELSE w.failure_count + 1 END, "+
+			"active = w.active AND ($1 OR w.failure_count + 1 < $2), "+
+			"disabled_at = CASE WHEN w.active AND NOT $1 AND w.failure_count + 1 >= $2 THEN now() ELSE w.disabled_at END "+
+			"FROM old WHERE w.id = old.id RETURNING old.active AND NOT w.active",
+		delivery.Success, disableAfter, delivery.WebhookID,
+	).Scan(&disabled)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, fmt.Errorf("%s: %w", op, ErrWebhookNotFound)
+		}
+
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return disabled, nil
+}
+
+// WebhookDeliveries возвращает попытки доставки, новые сначала
+func (r *WebhookPostgres) WebhookDeliveries(webhookID, cursor, limit int) ([]models.WebhookDelivery, error) {
+	const op = "repository.WebhookPostgres.WebhookDeliveries"
+
+	query := "SELECT " + deliveryColumns + " FROM webhook_deliveries WHERE webhook_id = $1"
+	args := []any{webhookID}
+	if cursor > 0 {
+		args = append(args, cursor)
+		query += fmt.Sprintf(" AND id < $%d", len(args))
+	}
+	args = append(args, limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	for rows.Next() {
+		var d models.WebhookDelivery
+		var payload []byte
+		var durationMs int64
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.DeliveryID, &d.EventType, &payload, &d.Attempt, &d.StatusCode,
+			&d.Error, &durationMs, &d.Success, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		d.Payload = payload
+		d.Duration = time.Duration(durationMs) * time.Millisecond
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *WebhookPostgres) webhooks(query string, args ...any) ([]models.Webhook, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := make([]models.Webhook, 0)
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func scanWebhook(row rowScanner) (models.Webhook, error) {
+	var webhook models.Webhook
+	var disabledAt sql.NullTime
+
+	err := row.Scan(&webhook.ID, &webhook.GroupID, &webhook.URL, &webhook.Secret, pq.Array(&webhook.EventTypes),
+		&webhook.Active, &webhook.FailureCount, &disabledAt, &webhook.CreatedAt)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	if disabledAt.Valid {
+		webhook.DisabledAt = &disabledAt.Time
+	}
+
+	return webhook, nil
+}
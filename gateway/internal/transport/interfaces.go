@@ -1,12 +1,220 @@
 package transport
 
-import "dev_meets/internal/domain/models"
+import (
+	"context"
+	"crypto/ed25519"
+	"dev_meets/internal/domain/models"
+	"dev_meets/pkg/telegram"
+	"net/http"
+	"time"
+)
 
 type AuthorizationServiceInt interface {
-	RegisterNewUser(user models.User, pass string) (int, error)
-	Login(username, password string) (string, error)
+	RegisterNewUser(ctx context.Context, user models.User, pass, inviteToken string) (int, error)
+	Login(ctx context.Context, username, password string) (string, error)
+	ChangePassword(ctx context.Context, userID int, current, password string) (string, error)
+	CheckUserActive(userID, tokenVersion int) error
 }
 
 type UserServiceInt interface {
 	CurrentUser(token string) (models.User, error)
+	UpdateProfile(userID int, profile models.Profile) error
+}
+
+type AccountServiceInt interface {
+	RequestEmailChange(ctx context.Context, userID int, password, newEmail string) (models.EmailChange, error)
+	ConfirmEmailChange(ctx context.Context, token string) error
+	DeleteAccount(ctx context.Context, userID int, password string) (time.Time, error)
+	RequestDataExport(ctx context.Context, userID int) (models.DataExport, error)
+	DataExport(userID int) (models.DataExport, error)
+	DataExportArchive(token string) (models.DataExport, error)
+}
+
+type EventServiceInt interface {
+	CreateEvent(ctx context.Context, event models.Event) (int, error)
+	Event(id int) (models.Event, error)
+	Events(filter models.EventFilter) ([]models.Event, error)
+	CancelEvent(ctx context.Context, userID, eventID int) error
+}
+
+type CFPServiceInt interface {
+	OpenCFP(ctx context.Context, userID int, cfp models.CallForPapers) error
+	CFP(eventID int) (models.CallForPapers, error)
+	AddReviewer(ctx context.Context, userID, eventID, reviewerID int) error
+	SubmitTalk(talk models.Talk) (int, error)
+	Talks(userID, eventID int) ([]models.TalkSummary, error)
+	ReviewTalk(review models.TalkReview) (int, error)
+	Reviews(userID, talkID int) ([]models.TalkReview, error)
+	AcceptTalk(ctx context.Context, userID, talkID int) error
+	RejectTalk(ctx context.Context, userID, talkID int) error
+}
+
+type AgendaServiceInt interface {
+	Agenda(eventID int) ([]models.AgendaSlot, error)
+	AddSlot(userID int, slot models.AgendaSlot, speakerIDs []int) (int, error)
+	UpdateSlot(userID int, slot models.AgendaSlot, speakerIDs []int) error
+	DeleteSlot(userID, eventID, slotID int) error
+	CreateSpeaker(speaker models.Speaker) (int, error)
+	Speaker(id int) (models.Speaker, error)
+	UpdateSpeaker(userID int, speaker models.Speaker) error
+}
+
+type VenueServiceInt interface {
+	CreateVenue(venue models.Venue) (int, error)
+	UpdateVenue(userID int, venue models.Venue) error
+	Venue(id int) (models.Venue, error)
+	Venues(filter models.VenueFilter) ([]models.Venue, error)
+}
+
+type GroupServiceInt interface {
+	CreateGroup(group models.Group) (int, error)
+	Group(id int) (models.Group, error)
+	Groups(limit, offset int) ([]models.Group, error)
+	JoinGroup(userID, groupID int) error
+	LeaveGroup(userID, groupID int) error
+}
+
+type InvitationServiceInt interface {
+	Invite(inviterID int, subjectType string, subjectID int, username, email string) (models.Invitation, error)
+	CreateInviteLink(inviterID int, subjectType string, subjectID int, maxUses *int, expiresAt *time.Time) (models.Invitation, error)
+	SubjectInvitations(userID int, subjectType string, subjectID, limit, offset int) ([]models.Invitation, error)
+	UserInvitations(userID int) ([]models.Invitation, error)
+	RevokeInvitation(userID, id int) error
+	Invitation(token string) (models.Invitation, error)
+	AcceptInvitation(userID int, token string) (models.Invitation, error)
+}
+
+type OrderServiceInt interface {
+	CreateTicketType(userID int, ticketType models.TicketType) (int, error)
+	TicketTypes(eventID int) ([]models.TicketType, error)
+	CreateOrder(ctx context.Context, userID, eventID, ticketTypeID int) (models.Order, error)
+	Order(userID, id int) (models.Order, error)
+	UserOrders(userID, limit, offset int) ([]models.Order, error)
+	EventOrders(userID, eventID, limit, offset int) ([]models.Order, error)
+	RefundOrder(ctx context.Context, userID, id int) (models.Order, error)
+	HandlePaymentWebhook(ctx context.Context, header http.Header, body []byte) error
+}
+
+type SearchServiceInt interface {
+	Search(query models.SearchQuery) ([]models.SearchResult, error)
+}
+
+type RSVPServiceInt interface {
+	RSVP(userID, eventID int) (models.Ticket, error)
+	CancelRSVP(userID, eventID int) error
+	Ticket(userID, eventID int) (models.Ticket, error)
+	CheckIn(organizerID, eventID int, payload string) (models.RSVP, models.Attendance, error)
+	Attendance(organizerID, eventID int) (models.Attendance, error)
+	TicketPublicKey() ed25519.PublicKey
+}
+
+type FeedbackServiceInt interface {
+	LeaveEventFeedback(feedback models.Feedback) (int, error)
+	LeaveTalkFeedback(feedback models.Feedback) (int, error)
+	EventFeedback(organizerID, eventID int) (models.EventFeedbackReport, error)
+	TalkFeedback(userID, eventID, slotID int) (models.TalkFeedbackReport, error)
+}
+
+type CommentServiceInt interface {
+	CreateComment(comment models.Comment) (int, error)
+	Comments(filter models.CommentFilter) ([]models.Comment, error)
+	UpdateComment(userID, id int, body string) error
+	DeleteComment(userID, id int) error
+	CommentHistory(userID, id int) ([]models.CommentRevision, error)
+}
+
+type NotificationServiceInt interface {
+	Notifications(userID int, filter models.NotificationFilter) ([]models.Notification, int, error)
+	MarkRead(userID, id int) error
+	MarkAllRead(userID int) (int, error)
+	NotificationPreferences(userID int) ([]models.NotificationPreference, error)
+	SetNotificationPreferences(userID int, preferences []models.NotificationPreference) error
+}
+
+type StreamServiceInt interface {
+	Subscribe(userID int, topics []string, lastEventID int64) (*models.StreamSubscription, []models.StreamEvent, error)
+	Unsubscribe(sub *models.StreamSubscription)
+}
+
+type JobServiceInt interface {
+	Jobs(userID int, filter models.JobFilter) ([]models.Job, error)
+	Job(userID int, id int64) (models.Job, error)
+	RetryJob(userID int, id int64) error
+}
+
+type WebhookServiceInt interface {
+	CreateWebhook(ctx context.Context, userID int, webhook models.Webhook) (models.Webhook, error)
+	Webhooks(userID, groupID int) ([]models.Webhook, error)
+	UpdateWebhook(ctx context.Context, userID int, webhook models.Webhook) error
+	DeleteWebhook(ctx context.Context, userID, id int) error
+	WebhookDeliveries(userID, webhookID, cursor, limit int) ([]models.WebhookDelivery, error)
+}
+
+type TelegramServiceInt interface {
+	CreateTelegramLinkCode(userID int) (models.TelegramLinkCode, error)
+	UnlinkTelegram(userID int) error
+	HandleTelegramWebhook(ctx context.Context, secret string, update telegram.Update) error
+}
+
+type MessageServiceInt interface {
+	CreateConversation(userID int, memberIDs []int, title string) (models.Conversation, error)
+	Conversation(userID, id int) (models.Conversation, error)
+	Conversations(userID, limit, offset int) ([]models.Conversation, error)
+	Messages(userID, conversationID int, filter models.MessageFilter) ([]models.Message, error)
+	SendMessage(userID, conversationID int, body string) (models.Message, error)
+	ReadConversation(userID, conversationID, messageID int) (int, error)
+	BlockUser(userID, blockedID int) error
+	UnblockUser(userID, blockedID int) error
+	Blocks(userID int) ([]models.Block, error)
+}
+
+type MatchServiceInt interface {
+	MatchGoals(userID int) ([]string, error)
+	SetMatchGoals(userID int, goals []string) error
+	DisableMatching(userID int) error
+	Matches(userID int, goal string, limit int) ([]models.Match, error)
+	RequestMatch(userID, recipientID int, goal, message string) (int, error)
+	MatchRequests(userID int, filter models.MatchRequestFilter) ([]models.MatchRequest, error)
+	AcceptMatchRequest(userID, id int) (models.MatchRequest, error)
+	DeclineMatchRequest(userID, id int) error
+}
+
+type FollowServiceInt interface {
+	FollowUser(userID, followeeID int) error
+	UnfollowUser(userID, followeeID int) error
+	FollowGroup(userID, groupID int) error
+	UnfollowGroup(userID, groupID int) error
+	Followers(userID, limit, offset int) ([]models.Follow, error)
+	Following(userID, limit, offset int) ([]models.Follow, error)
+	FollowedGroups(userID, limit, offset int) ([]models.GroupFollow, error)
+	GroupFollowers(groupID, limit, offset int) ([]models.Follow, error)
+	Activities(userID int, filter models.ActivityFilter) ([]models.Activity, error)
+}
+
+type ModerationServiceInt interface {
+	Report(userID int, report models.Report) (int, error)
+	Reports(userID int, filter models.ReportFilter) ([]models.Report, error)
+	ModerationReport(userID, id int) (models.Report, error)
+	ResolveReport(userID, reportID int, decision models.ModerationDecision) (models.ModerationAction, error)
+	ModerationLog(userID int, filter models.ModerationActionFilter) ([]models.ModerationAction, error)
+}
+
+type AdminServiceInt interface {
+	AdminUsers(adminID int, filter models.AdminUserFilter) ([]models.User, error)
+	AdminUser(adminID, userID int) (models.AccountState, error)
+	SetUserRole(ctx context.Context, adminID, userID int, role string) error
+	SuspendUser(ctx context.Context, adminID, userID, days int, reason string) error
+	UnsuspendUser(ctx context.Context, adminID, userID int) error
+	ForceLogout(ctx context.Context, adminID, userID int) error
+	MergeUsers(ctx context.Context, adminID, targetID, sourceID int) error
+}
+
+type AuditServiceInt interface {
+	AuditEvents(userID int, filter models.AuditFilter) ([]models.AuditEvent, error)
+}
+
+type FeedServiceInt interface {
+	Feed(userID int, near *models.GeoPoint, limit, offset int) ([]models.FeedItem, error)
+	DismissEvent(userID, eventID int) error
+	UndismissEvent(userID, eventID int) error
 }
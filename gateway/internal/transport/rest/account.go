@@ -0,0 +1,209 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// dataExportPath - ссылка на скачивание выгрузки, секрет в пути заменяет авторизацию
+const dataExportPath = "/api/v1/data-exports/"
+
+type AccountHandler struct {
+	services transport.AccountServiceInt
+	logger   *slog.Logger
+}
+
+func NewAccountHandler(serv transport.AccountServiceInt, logger *slog.Logger) *AccountHandler {
+	return &AccountHandler{services: serv, logger: logger}
+}
+
+type emailChangeInput struct {
+	Password string `json:"password" validate:"required" example:"password"`
+	NewEmail string `json:"new_email" validate:"required,email,max=254" example:"new-email@gmail.com"`
+}
+
+type EmailChangeOkResponse struct {
+	Status    string    `json:"status" example:"ok"`
+	NewEmail  string    `json:"new_email" example:"new-email@gmail.com"`
+	ExpiresAt time.Time `json:"expires_at" example:"2024-02-21T13:00:00+03:00"`
+}
+
+type confirmEmailChangeInput struct {
+	Token string `json:"token" validate:"required,max=100" example:"3f9a..."`
+}
+
+type deleteAccountInput struct {
+	Password string `json:"password" validate:"required" example:"password"`
+}
+
+type DeleteAccountOkResponse struct {
+	Status              string    `json:"status" example:"ok"`
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at" example:"2024-03-21T13:00:00+03:00"`
+}
+
+type DataExportResponse struct {
+	Id          int        `json:"id" example:"4"`
+	Status      string     `json:"status" example:"ready"`
+	CreatedAt   time.Time  `json:"created_at" example:"2024-02-20T13:00:00+03:00"`
+	ReadyAt     *time.Time `json:"ready_at,omitempty" example:"2024-02-20T13:01:00+03:00"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" example:"2024-02-22T13:01:00+03:00"`
+	DownloadUrl string     `json:"download_url,omitempty" example:"/api/v1/data-exports/3f9a..."`
+}
+
+type DataExportOkResponse struct {
+	Status string             `json:"status" example:"ok"`
+	Export DataExportResponse `json:"export"`
+}
+
+func newDataExportResponse(export models.DataExport) DataExportResponse {
+	response := DataExportResponse{
+		Id:        export.ID,
+		Status:    export.Status,
+		CreatedAt: export.CreatedAt,
+		ReadyAt:   export.ReadyAt,
+		ExpiresAt: export.ExpiresAt,
+	}
+	if export.Status == models.DataExportReady {
+		response.DownloadUrl = dataExportPath + export.Token
+	}
+
+	return response
+}
+
+// Смена почты
+// @Summary Запрос смены почты текущего пользователя
+// @Description Нужен пароль. На новый адрес приходит код подтверждения, на текущий - предупреждение.
+// @Description Пока код не подтверждён, вход выполняется по текущему адресу. Новый запрос отменяет прежний.
+// @Tags Пользователь
+// @Param Request body emailChangeInput true "Пароль и новая почта"
+// @Success 200 {object} EmailChangeOkResponse "Код подтверждения отправлен"
+// @Failure 201 {object} ErrResponse "Неверный пароль или адрес уже занят"
+// @Router /api/v1/personal-profile/email [post]
+func (h *AccountHandler) RequestEmailChange(w http.ResponseWriter, r *http.Request) {
+	var input emailChangeInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	change, err := h.services.RequestEmailChange(r.Context(), currentUserID(r), input.Password, input.NewEmail)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, EmailChangeOkResponse{Status: "ok", NewEmail: change.NewEmail, ExpiresAt: change.ExpiresAt})
+}
+
+// Подтверждение смены почты
+// @Summary Подтверждение новой почты кодом из письма
+// @Description Авторизация не нужна: код из письма на новый адрес заменяет её. После подтверждения
+// @Description все выданные токены перестают действовать, входить нужно с новой почтой.
+// @Tags Пользователь
+// @Param Request body confirmEmailChangeInput true "Код из письма"
+// @Success 200 {object} StatusResponse "Почта изменена"
+// @Failure 201 {object} ErrResponse "Код не найден или истёк, адрес уже занят"
+// @Router /api/v1/email-changes/confirm [post]
+func (h *AccountHandler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	var input confirmEmailChangeInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	if err := h.services.ConfirmEmailChange(r.Context(), input.Token); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Удаление аккаунта
+// @Summary Удаление аккаунта текущего пользователя
+// @Description Нужен пароль. Аккаунт удаляется по истечении срока ожидания (по умолчанию 30 дней), все
+// @Description выданные токены сразу перестают действовать. Вход до deletion_scheduled_at отменяет
+// @Description удаление. После удаления почта, профиль, записи
+// @Description на мероприятия, подписки и уведомления стираются, а мероприятия, комментарии, сообщения
+// @Description и отзывы остаются без имени автора. Модератору и администратору сначала нужно снять роль.
+// @Tags Пользователь
+// @Param Request body deleteAccountInput true "Пароль"
+// @Success 200 {object} DeleteAccountOkResponse "Удаление назначено"
+// @Failure 201 {object} ErrResponse "Неверный пароль или нет прав"
+// @Router /api/v1/personal-profile [delete]
+func (h *AccountHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	var input deleteAccountInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	at, err := h.services.DeleteAccount(r.Context(), currentUserID(r), input.Password)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, DeleteAccountOkResponse{Status: "ok", DeletionScheduledAt: at})
+}
+
+// Запрос выгрузки данных
+// @Summary Запрос архива с данными текущего пользователя
+// @Description Архив собирается в фоне: профиль, записи на мероприятия, комментарии и сообщения в JSON.
+// @Description Когда он готов, приходит уведомление, а в выгрузке появляется download_url. Пока предыдущая
+// @Description выгрузка собирается, возвращается она.
+// @Tags Пользователь
+// @Success 200 {object} DataExportOkResponse "Выгрузка запрошена"
+// @Failure 201 {object} ErrResponse "Внутренняя ошибка сервиса"
+// @Router /api/v1/personal-profile/export [post]
+func (h *AccountHandler) RequestDataExport(w http.ResponseWriter, r *http.Request) {
+	export, err := h.services.RequestDataExport(r.Context(), currentUserID(r))
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, DataExportOkResponse{Status: "ok", Export: newDataExportResponse(export)})
+}
+
+// Состояние выгрузки данных
+// @Summary Последняя выгрузка данных текущего пользователя
+// @Tags Пользователь
+// @Success 200 {object} DataExportOkResponse "Выгрузка"
+// @Failure 201 {object} ErrResponse "Выгрузки нет или ссылка истекла"
+// @Router /api/v1/personal-profile/export [get]
+func (h *AccountHandler) DataExport(w http.ResponseWriter, r *http.Request) {
+	export, err := h.services.DataExport(currentUserID(r))
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, DataExportOkResponse{Status: "ok", Export: newDataExportResponse(export)})
+}
+
+// Скачивание выгрузки данных
+// @Summary Скачивание архива с данными по ссылке из download_url
+// @Description Авторизация не нужна: ссылка содержит секрет и действует до expires_at.
+// @Tags Пользователь
+// @Produce application/zip
+// @Param token path string true "Секрет из ссылки"
+// @Success 200 {file} binary "ZIP-архив"
+// @Failure 201 {object} ErrResponse "Выгрузка не найдена или ссылка истекла"
+// @Router /api/v1/data-exports/{token} [get]
+func (h *AccountHandler) DownloadDataExport(w http.ResponseWriter, r *http.Request) {
+	export, err := h.services.DataExportArchive(chi.URLParam(r, "token"))
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="dev-meets-data-%s.zip"`, export.CreatedAt.Format("2006-01-02")))
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(export.Archive)
+}
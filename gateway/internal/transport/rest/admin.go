@@ -0,0 +1,294 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type AdminHandler struct {
+	services transport.AdminServiceInt
+	logger   *slog.Logger
+}
+
+func NewAdminHandler(serv transport.AdminServiceInt, logger *slog.Logger) *AdminHandler {
+	return &AdminHandler{services: serv, logger: logger}
+}
+
+type roleInput struct {
+	Role string `json:"role" validate:"required,oneof=user moderator admin" example:"moderator"`
+}
+
+type suspendInput struct {
+	Days   int    `json:"days" validate:"min=0,max=365" example:"7"`
+	Reason string `json:"reason" validate:"max=1000" example:"Спам в обсуждениях"`
+}
+
+type mergeInput struct {
+	SourceId int `json:"source_id" validate:"required,gt=0" example:"15"`
+}
+
+type AdminUserResponse struct {
+	Id               int        `json:"id" example:"12"`
+	Email            string     `json:"email" example:"email@gmail.com"`
+	EmailDuplicate   bool       `json:"email_duplicate" example:"false"`
+	Name             string     `json:"name" example:"Иван Петров"`
+	Username         string     `json:"username,omitempty" example:"ivan_petrov"`
+	Role             string     `json:"role" example:"user"`
+	Suspended        bool       `json:"suspended" example:"false"`
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty" example:"2024-02-20T13:00:00+03:00"`
+	SuspendedUntil   *time.Time `json:"suspended_until,omitempty" example:"2024-02-27T13:00:00+03:00"`
+	SuspensionReason string     `json:"suspension_reason,omitempty" example:"Спам в обсуждениях"`
+}
+
+type AccountStateResponse struct {
+	AdminUserResponse
+	TelegramLinked    bool `json:"telegram_linked" example:"true"`
+	EventsOrganized   int  `json:"events_organized" example:"3"`
+	GroupsOwned       int  `json:"groups_owned" example:"1"`
+	OpenReports       int  `json:"open_reports" example:"0"`
+	ModerationActions int  `json:"moderation_actions" example:"2"`
+}
+
+type AdminUsersOkResponse struct {
+	Status string              `json:"status" example:"ok"`
+	Users  []AdminUserResponse `json:"users"`
+}
+
+type AccountStateOkResponse struct {
+	Status  string               `json:"status" example:"ok"`
+	Account AccountStateResponse `json:"account"`
+}
+
+func newAdminUserResponse(user models.User) AdminUserResponse {
+	return AdminUserResponse{
+		Id:               user.ID,
+		Email:            user.Email,
+		EmailDuplicate:   user.EmailDuplicate,
+		Name:             user.Name,
+		Username:         user.Username,
+		Role:             user.Role,
+		Suspended:        user.Suspended(time.Now()),
+		SuspendedAt:      user.SuspendedAt,
+		SuspendedUntil:   user.SuspendedUntil,
+		SuspensionReason: user.SuspensionReason,
+	}
+}
+
+// Поиск пользователей
+// @Summary Поиск пользователей администратором
+// @Description Доступно только администраторам.
+// @Tags Администрирование
+// @Param query query string false "Часть почты, имени или username либо идентификатор"
+// @Param role query string false "Роль: user, moderator или admin"
+// @Param suspended query bool false "true - только заблокированные, false - только активные"
+// @Param duplicate_email query bool false "true - только аккаунты с одной почтой в разном регистре, подряд по почте"
+// @Param limit query int false "Количество пользователей (по умолчанию 20)"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} AdminUsersOkResponse "Пользователи"
+// @Failure 201 {object} ErrResponse "Нет прав или неверный фильтр"
+// @Router /api/v1/admin/users [get]
+func (h *AdminHandler) AdminUsers(w http.ResponseWriter, r *http.Request) {
+	limit, offset := pagination(r)
+	filter := models.AdminUserFilter{
+		Query:  r.URL.Query().Get("query"),
+		Role:   r.URL.Query().Get("role"),
+		Limit:  limit,
+		Offset: offset,
+	}
+	if value := r.URL.Query().Get("suspended"); value != "" {
+		suspended, err := strconv.ParseBool(value)
+		if err != nil {
+			render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+			return
+		}
+		filter.Suspended = &suspended
+	}
+	if value := r.URL.Query().Get("duplicate_email"); value != "" {
+		duplicate, err := strconv.ParseBool(value)
+		if err != nil {
+			render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+			return
+		}
+		filter.DuplicateEmail = duplicate
+	}
+
+	users, err := h.services.AdminUsers(currentUserID(r), filter)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := AdminUsersOkResponse{Status: "ok", Users: make([]AdminUserResponse, 0, len(users))}
+	for _, user := range users {
+		response.Users = append(response.Users, newAdminUserResponse(user))
+	}
+
+	render.JSON(w, r, response)
+}
+
+// Состояние аккаунта
+// @Summary Состояние аккаунта пользователя
+// @Description Роль, блокировка, привязка Telegram, число мероприятий, сообществ, открытых жалоб
+// @Description и решений модераторов по пользователю.
+// @Tags Администрирование
+// @Param id path int true "Идентификатор пользователя"
+// @Success 200 {object} AccountStateOkResponse "Аккаунт"
+// @Failure 201 {object} ErrResponse "Пользователь не найден или нет прав"
+// @Router /api/v1/admin/users/{id} [get]
+func (h *AdminHandler) AdminUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	state, err := h.services.AdminUser(currentUserID(r), id)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, AccountStateOkResponse{Status: "ok", Account: AccountStateResponse{
+		AdminUserResponse: newAdminUserResponse(state.User),
+		TelegramLinked:    state.TelegramLinked,
+		EventsOrganized:   state.EventsOrganized,
+		GroupsOwned:       state.GroupsOwned,
+		OpenReports:       state.OpenReports,
+		ModerationActions: state.ModerationActions,
+	}})
+}
+
+// Смена роли
+// @Summary Смена роли пользователя
+// @Description Свою роль администратор изменить не может.
+// @Tags Администрирование
+// @Param id path int true "Идентификатор пользователя"
+// @Param Request body roleInput true "Роль"
+// @Success 200 {object} StatusResponse "Роль изменена"
+// @Failure 201 {object} ErrResponse "Пользователь не найден или нет прав"
+// @Router /api/v1/admin/users/{id}/role [put]
+func (h *AdminHandler) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input roleInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	if err := h.services.SetUserRole(r.Context(), currentUserID(r), id, input.Role); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Блокировка пользователя
+// @Summary Блокировка пользователя на days дней, 0 - бессрочно
+// @Description Заблокированный пользователь не может войти, его токены перестают приниматься.
+// @Description Администратора заблокировать нельзя.
+// @Tags Администрирование
+// @Param id path int true "Идентификатор пользователя"
+// @Param Request body suspendInput true "Срок и причина"
+// @Success 200 {object} StatusResponse "Пользователь заблокирован"
+// @Failure 201 {object} ErrResponse "Пользователь не найден или нет прав"
+// @Router /api/v1/admin/users/{id}/suspend [post]
+func (h *AdminHandler) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input suspendInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	if err := h.services.SuspendUser(r.Context(), currentUserID(r), id, input.Days, input.Reason); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Снятие блокировки
+// @Summary Снятие блокировки пользователя
+// @Tags Администрирование
+// @Param id path int true "Идентификатор пользователя"
+// @Success 200 {object} StatusResponse "Блокировка снята"
+// @Failure 201 {object} ErrResponse "Пользователь не найден или нет прав"
+// @Router /api/v1/admin/users/{id}/unsuspend [post]
+func (h *AdminHandler) UnsuspendUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.UnsuspendUser(r.Context(), currentUserID(r), id); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Принудительный выход
+// @Summary Принудительный выход пользователя на всех устройствах
+// @Description Все выданные пользователю токены перестают приниматься.
+// @Tags Администрирование
+// @Param id path int true "Идентификатор пользователя"
+// @Success 200 {object} StatusResponse "Токены отозваны"
+// @Failure 201 {object} ErrResponse "Пользователь не найден или нет прав"
+// @Router /api/v1/admin/users/{id}/logout [post]
+func (h *AdminHandler) ForceLogout(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.ForceLogout(r.Context(), currentUserID(r), id); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Слияние аккаунтов
+// @Summary Слияние дубликата source_id с аккаунтом пользователя
+// @Description Мероприятия, сообщества, доклады, записи, комментарии, диалоги, подписки и прочие данные
+// @Description дубликата переходят к аккаунту id, дубликат удаляется. Пустые поля профиля заполняются
+// @Description из дубликата. Почта, пароль и роль аккаунта id не меняются.
+// @Description Дубликат с ролью модератора или администратора слить нельзя.
+// @Tags Администрирование
+// @Param id path int true "Идентификатор аккаунта, который остаётся"
+// @Param Request body mergeInput true "Дубликат"
+// @Success 200 {object} StatusResponse "Аккаунты объединены"
+// @Failure 201 {object} ErrResponse "Пользователь не найден или нет прав"
+// @Router /api/v1/admin/users/{id}/merge [post]
+func (h *AdminHandler) MergeUsers(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input mergeInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	if err := h.services.MergeUsers(r.Context(), currentUserID(r), id, input.SourceId); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
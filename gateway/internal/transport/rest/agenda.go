@@ -0,0 +1,323 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type AgendaHandler struct {
+	services transport.AgendaServiceInt
+	logger   *slog.Logger
+}
+
+func NewAgendaHandler(serv transport.AgendaServiceInt, logger *slog.Logger) *AgendaHandler {
+	return &AgendaHandler{services: serv, logger: logger}
+}
+
+type slotInput struct {
+	Kind        string     `json:"kind" validate:"required,oneof=talk break networking" example:"break"`
+	Title       string     `json:"title" validate:"required,max=200" example:"Кофе-брейк"`
+	Description string     `json:"description" example:""`
+	StartsAt    *time.Time `json:"starts_at" example:"2024-03-01T20:00:00+03:00"`
+	EndsAt      *time.Time `json:"ends_at" example:"2024-03-01T20:15:00+03:00"`
+	SlidesURL   string     `json:"slides_url" validate:"omitempty,url" example:"https://example.com/slides.pdf"`
+	VideoURL    string     `json:"video_url" validate:"omitempty,url" example:"https://youtu.be/xxxx"`
+	SpeakerIds  []int      `json:"speaker_ids" validate:"max=10,dive,gt=0" example:"1,2"`
+}
+
+type slotUpdateInput struct {
+	slotInput
+	Position int `json:"position" validate:"required,gt=0" example:"2"`
+}
+
+type speakerInput struct {
+	UserId   *int   `json:"user_id" validate:"omitempty,gt=0" example:"123"`
+	Name     string `json:"name" validate:"required_without=UserId,max=200" example:"Иван Петров"`
+	Bio      string `json:"bio" example:"Backend-разработчик, 10 лет в Go"`
+	PhotoURL string `json:"photo_url" validate:"omitempty,url" example:"https://example.com/photo.jpg"`
+}
+
+type SpeakerResponse struct {
+	Id       int    `json:"id" example:"1"`
+	UserId   *int   `json:"user_id,omitempty" example:"123"`
+	Name     string `json:"name" example:"Иван Петров"`
+	Bio      string `json:"bio" example:"Backend-разработчик, 10 лет в Go"`
+	PhotoURL string `json:"photo_url" example:"https://example.com/photo.jpg"`
+}
+
+type SpeakerOkResponse struct {
+	Status  string          `json:"status" example:"ok"`
+	Speaker SpeakerResponse `json:"speaker"`
+}
+
+type AgendaSlotResponse struct {
+	Id          int               `json:"id" example:"1"`
+	Position    int               `json:"position" example:"1"`
+	Kind        string            `json:"kind" example:"talk"`
+	TalkId      *int              `json:"talk_id,omitempty" example:"7"`
+	Title       string            `json:"title" example:"Профилирование Go-сервисов"`
+	Description string            `json:"description" example:""`
+	StartsAt    *time.Time        `json:"starts_at,omitempty" example:"2024-03-01T19:00:00+03:00"`
+	EndsAt      *time.Time        `json:"ends_at,omitempty" example:"2024-03-01T19:30:00+03:00"`
+	SlidesURL   string            `json:"slides_url,omitempty" example:"https://example.com/slides.pdf"`
+	VideoURL    string            `json:"video_url,omitempty" example:"https://youtu.be/xxxx"`
+	Speakers    []SpeakerResponse `json:"speakers"`
+}
+
+type AgendaOkResponse struct {
+	Status string               `json:"status" example:"ok"`
+	Agenda []AgendaSlotResponse `json:"agenda"`
+}
+
+func newSpeakerResponse(speaker models.Speaker) SpeakerResponse {
+	return SpeakerResponse{
+		Id:       speaker.ID,
+		UserId:   speaker.UserID,
+		Name:     speaker.Name,
+		Bio:      speaker.Bio,
+		PhotoURL: speaker.PhotoURL,
+	}
+}
+
+func newAgendaResponse(slots []models.AgendaSlot) []AgendaSlotResponse {
+	agenda := make([]AgendaSlotResponse, 0, len(slots))
+	for _, slot := range slots {
+		speakers := make([]SpeakerResponse, 0, len(slot.Speakers))
+		for _, speaker := range slot.Speakers {
+			speakers = append(speakers, newSpeakerResponse(speaker))
+		}
+
+		agenda = append(agenda, AgendaSlotResponse{
+			Id:          slot.ID,
+			Position:    slot.Position,
+			Kind:        slot.Kind,
+			TalkId:      slot.TalkID,
+			Title:       slot.Title,
+			Description: slot.Description,
+			StartsAt:    slot.StartsAt,
+			EndsAt:      slot.EndsAt,
+			SlidesURL:   slot.SlidesURL,
+			VideoURL:    slot.VideoURL,
+			Speakers:    speakers,
+		})
+	}
+
+	return agenda
+}
+
+// Программа мероприятия
+// @Summary Программа мероприятия с докладчиками
+// @Tags Программа
+// @Param id path int true "Идентификатор мероприятия"
+// @Success 200 {object} AgendaOkResponse "Программа мероприятия"
+// @Failure 201 {object} ErrResponse "Мероприятие не найдено"
+// @Router /api/v1/events/{id}/agenda [get]
+func (h *AgendaHandler) Agenda(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	slots, err := h.services.Agenda(eventID)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, AgendaOkResponse{Status: "ok", Agenda: newAgendaResponse(slots)})
+}
+
+// Добавление слота в программу
+// @Summary Добавление слота (доклад, перерыв, нетворкинг) в программу (только организатор)
+// @Tags Программа
+// @Param id path int true "Идентификатор мероприятия"
+// @Param Request body slotInput true "Слот программы"
+// @Success 200 {object} IdResponse "Слот добавлен"
+// @Failure 201 {object} ErrResponse "Ошибка при добавлении слота"
+// @Router /api/v1/events/{id}/agenda [post]
+func (h *AgendaHandler) AddSlot(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input slotInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	slot := input.slot()
+	slot.EventID = eventID
+
+	id, err := h.services.AddSlot(currentUserID(r), slot, input.SpeakerIds)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, IdResponse{Status: "ok", Id: id})
+}
+
+// Изменение слота программы
+// @Summary Изменение слота программы: время, порядок, докладчики, ссылки на слайды и видео (только организатор)
+// @Tags Программа
+// @Param id path int true "Идентификатор мероприятия"
+// @Param slotId path int true "Идентификатор слота"
+// @Param Request body slotUpdateInput true "Слот программы"
+// @Success 200 {object} StatusResponse "Слот изменён"
+// @Failure 201 {object} ErrResponse "Ошибка при изменении слота"
+// @Router /api/v1/events/{id}/agenda/{slotId} [put]
+func (h *AgendaHandler) UpdateSlot(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	slotID, ok := idParam(w, r, "slotId")
+	if !ok {
+		return
+	}
+
+	var input slotUpdateInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	slot := input.slot()
+	slot.ID = slotID
+	slot.EventID = eventID
+	slot.Position = input.Position
+
+	if err := h.services.UpdateSlot(currentUserID(r), slot, input.SpeakerIds); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Удаление слота программы
+// @Summary Удаление слота из программы (только организатор)
+// @Tags Программа
+// @Param id path int true "Идентификатор мероприятия"
+// @Param slotId path int true "Идентификатор слота"
+// @Success 200 {object} StatusResponse "Слот удалён"
+// @Failure 201 {object} ErrResponse "Ошибка при удалении слота"
+// @Router /api/v1/events/{id}/agenda/{slotId} [delete]
+func (h *AgendaHandler) DeleteSlot(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	slotID, ok := idParam(w, r, "slotId")
+	if !ok {
+		return
+	}
+
+	if err := h.services.DeleteSlot(currentUserID(r), eventID, slotID); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Создание докладчика
+// @Summary Создание карточки докладчика: своей или внешнего гостя
+// @Tags Программа
+// @Param Request body speakerInput true "Докладчик"
+// @Success 200 {object} IdResponse "Докладчик создан"
+// @Failure 201 {object} ErrResponse "Ошибка при создании докладчика"
+// @Router /api/v1/speakers [post]
+func (h *AgendaHandler) CreateSpeaker(w http.ResponseWriter, r *http.Request) {
+	var input speakerInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	id, err := h.services.CreateSpeaker(models.Speaker{
+		UserID:    input.UserId,
+		Name:      input.Name,
+		Bio:       input.Bio,
+		PhotoURL:  input.PhotoURL,
+		CreatedBy: currentUserID(r),
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, IdResponse{Status: "ok", Id: id})
+}
+
+// Докладчик
+// @Summary Карточка докладчика
+// @Tags Программа
+// @Param id path int true "Идентификатор докладчика"
+// @Success 200 {object} SpeakerOkResponse "Докладчик"
+// @Failure 201 {object} ErrResponse "Докладчик не найден"
+// @Router /api/v1/speakers/{id} [get]
+func (h *AgendaHandler) Speaker(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	speaker, err := h.services.Speaker(id)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, SpeakerOkResponse{Status: "ok", Speaker: newSpeakerResponse(speaker)})
+}
+
+// Изменение докладчика
+// @Summary Изменение карточки докладчика (автор карточки или сам докладчик)
+// @Tags Программа
+// @Param id path int true "Идентификатор докладчика"
+// @Param Request body speakerInput true "Докладчик"
+// @Success 200 {object} StatusResponse "Докладчик изменён"
+// @Failure 201 {object} ErrResponse "Ошибка при изменении докладчика"
+// @Router /api/v1/speakers/{id} [put]
+func (h *AgendaHandler) UpdateSpeaker(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input speakerInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	err := h.services.UpdateSpeaker(currentUserID(r), models.Speaker{
+		ID:       id,
+		Name:     input.Name,
+		Bio:      input.Bio,
+		PhotoURL: input.PhotoURL,
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+func (i slotInput) slot() models.AgendaSlot {
+	return models.AgendaSlot{
+		Kind:        i.Kind,
+		Title:       i.Title,
+		Description: i.Description,
+		StartsAt:    i.StartsAt,
+		EndsAt:      i.EndsAt,
+		SlidesURL:   i.SlidesURL,
+		VideoURL:    i.VideoURL,
+	}
+}
@@ -0,0 +1,138 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/service"
+	"dev_meets/internal/transport"
+	"encoding/json"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type AuditHandler struct {
+	services transport.AuditServiceInt
+	logger   *slog.Logger
+}
+
+func NewAuditHandler(serv transport.AuditServiceInt, logger *slog.Logger) *AuditHandler {
+	return &AuditHandler{services: serv, logger: logger}
+}
+
+type AuditEventResponse struct {
+	Id         int64           `json:"id" example:"120"`
+	ActorId    *int            `json:"actor_id,omitempty" example:"1"`
+	Action     string          `json:"action" example:"user.role_changed"`
+	TargetType string          `json:"target_type" example:"user"`
+	TargetId   int             `json:"target_id,omitempty" example:"12"`
+	Before     json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After      json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	Ip         string          `json:"ip" example:"203.0.113.7"`
+	RequestId  string          `json:"request_id" example:"host/abcdef-000001"`
+	CreatedAt  time.Time       `json:"created_at" example:"2024-02-20T13:00:00+03:00"`
+}
+
+type AuditEventsOkResponse struct {
+	Status     string               `json:"status" example:"ok"`
+	Events     []AuditEventResponse `json:"events"`
+	NextCursor string               `json:"next_cursor,omitempty" example:"MTIw"`
+}
+
+// requestMeta передаёт сервисам адрес клиента и идентификатор запроса для журнала аудита.
+// Ставится после middleware.RequestID и middleware.RealIP
+func requestMeta(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+
+		ctx := service.WithRequestMeta(r.Context(), models.RequestMeta{
+			IP:        ip,
+			RequestID: middleware.GetReqID(r.Context()),
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Журнал аудита
+// @Summary Журнал аудита: кто, когда и откуда выполнил действие, новые записи сначала
+// @Description Входы, регистрации, смена пароля, действия администраторов и организаторов.
+// @Description before и after - состояние объекта до и после изменения. Доступно только администраторам.
+// @Tags Администрирование
+// @Param actor_id query int false "Кто выполнил действие"
+// @Param action query string false "Действие, например user.role_changed или auth.sign_in_failed"
+// @Param target_type query string false "Тип объекта: user, event, talk, webhook или order"
+// @Param target_id query int false "Идентификатор объекта"
+// @Param from query string false "Не раньше (RFC 3339)"
+// @Param to query string false "Раньше (RFC 3339)"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Success 200 {object} AuditEventsOkResponse "Записи журнала"
+// @Failure 201 {object} ErrResponse "Нет прав или неверный фильтр"
+// @Router /api/v1/admin/audit [get]
+func (h *AuditHandler) AuditEvents(w http.ResponseWriter, r *http.Request) {
+	limit, cursor, ok := cursorPagination(r)
+	if !ok {
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+		return
+	}
+
+	query := r.URL.Query()
+	filter := models.AuditFilter{
+		Action:     query.Get("action"),
+		TargetType: query.Get("target_type"),
+		Cursor:     int64(cursor),
+		Limit:      limit,
+	}
+	for name, dest := range map[string]*int{"actor_id": &filter.ActorID, "target_id": &filter.TargetID} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+			return
+		}
+		*dest = id
+	}
+	var fromOk, toOk bool
+	filter.From, fromOk = timeParam(query.Get("from"))
+	filter.To, toOk = timeParam(query.Get("to"))
+	if !fromOk || !toOk {
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+		return
+	}
+
+	events, err := h.services.AuditEvents(currentUserID(r), filter)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := AuditEventsOkResponse{Status: "ok", Events: make([]AuditEventResponse, 0, len(events))}
+	for _, event := range events {
+		response.Events = append(response.Events, AuditEventResponse{
+			Id:         event.ID,
+			ActorId:    event.ActorID,
+			Action:     event.Action,
+			TargetType: event.TargetType,
+			TargetId:   event.TargetID,
+			Before:     event.Before,
+			After:      event.After,
+			Ip:         event.IP,
+			RequestId:  event.RequestID,
+			CreatedAt:  event.CreatedAt,
+		})
+	}
+	if len(events) > 0 {
+		response.NextCursor = nextCursor(int(events[len(events)-1].ID), len(events), limit)
+	}
+
+	render.JSON(w, r, response)
+}
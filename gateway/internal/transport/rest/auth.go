@@ -1,7 +1,10 @@
 package rest
 
 import (
+	"context"
 	"dev_meets/internal/domain/models"
+	"dev_meets/internal/service"
+	"dev_meets/internal/storage"
 	"dev_meets/internal/transport"
 	"dev_meets/pkg/jwt"
 	"errors"
@@ -35,12 +38,14 @@ type SignUpOkResponse struct {
 // Регистрация
 // @Summary Регистрация нового пользователя
 // @Tags Регистрация
-// @Param Request body signInUpInput true "Почта и Пароль"
+// @Description Если пользователь пришёл по приглашению, invite_token применяет его сразу после
+// @Description регистрации. Приглашения, отправленные на почту, принимаются только по коду из письма.
+// @Param Request body signUpInput true "Почта, пароль и код приглашения"
 // @Success 200 {object} SignUpOkResponse "Успешная регистрация нового пользователя"
 // @Failure 201 {object} ErrResponse "Ошибка при попытке зарегистрироваться"
 // @Router /api/v1/sign-up [post]
 func (h *AuthHandler) signUp(w http.ResponseWriter, r *http.Request) {
-	var input signInUpInput
+	var input signUpInput
 
 	err := render.DecodeJSON(r.Body, &input)
 	if errors.Is(err, io.EOF) {
@@ -76,16 +81,9 @@ func (h *AuthHandler) signUp(w http.ResponseWriter, r *http.Request) {
 
 	user := models.User{Email: input.Email}
 
-	id, err := h.services.RegisterNewUser(user, input.Password)
+	id, err := h.services.RegisterNewUser(r.Context(), user, input.Password, input.InviteToken)
 	if err != nil {
-		e := slog.Attr{
-			Key:   "error",
-			Value: slog.StringValue(err.Error()),
-		}
-		h.logger.Error("internal_server_error", e)
-		render.JSON(w, r, ErrResponse{
-			Status: "internal_server_error",
-		})
+		renderError(w, r, h.logger, err)
 		return
 	}
 
@@ -100,6 +98,11 @@ type signInUpInput struct {
 	Password string `json:"password" validate:"required" example:"password"`
 }
 
+type signUpInput struct {
+	signInUpInput
+	InviteToken string `json:"invite_token" validate:"max=64" example:"9f2c4e..."`
+}
+
 // Авторизация
 // @Summary Авторизация пользователя
 // @Tags Авторизация
@@ -135,7 +138,7 @@ func (h *AuthHandler) signIn(w http.ResponseWriter, r *http.Request) {
 	h.logger.Info("request body decoded", slog.Any("request", input))
 
 	if err := validator.New().Struct(input); err != nil {
-		h.logger.Error("invalid params", err)
+		h.logger.Error("invalid params", slog.String("error", err.Error()))
 		render.JSON(w, r, ErrResponse{
 			Status: "wrong_params",
 		})
@@ -143,9 +146,15 @@ func (h *AuthHandler) signIn(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := h.services.Login(input.Email, input.Password)
+	token, err := h.services.Login(r.Context(), input.Email, input.Password)
+	if errors.Is(err, service.ErrUserSuspended) {
+		render.JSON(w, r, ErrResponse{
+			Status: "forbidden",
+		})
+		return
+	}
 	if err != nil {
-		h.logger.Error("internal error", err)
+		h.logger.Error("internal error", slog.String("error", err.Error()))
 		render.JSON(w, r, ErrResponse{
 			Status: "internal_server_error",
 		})
@@ -158,6 +167,38 @@ func (h *AuthHandler) signIn(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+type changePasswordInput struct {
+	CurrentPassword string `json:"current_password" validate:"required" example:"password"`
+	// bcrypt учитывает только первые 72 байта пароля
+	NewPassword string `json:"new_password" validate:"required,min=8,max=72" example:"new-password"`
+}
+
+// Смена пароля
+// @Summary Смена пароля текущего пользователя
+// @Description Нужен текущий пароль. Все выданные ранее токены перестают действовать, в ответе новый токен.
+// @Tags Авторизация
+// @Param Request body changePasswordInput true "Текущий и новый пароль"
+// @Success 200 {object} SignInOkResponse "Пароль изменён"
+// @Failure 201 {object} ErrResponse "Неверный текущий пароль"
+// @Router /api/v1/personal-profile/password [put]
+func (h *AuthHandler) changePassword(w http.ResponseWriter, r *http.Request) {
+	var input changePasswordInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	token, err := h.services.ChangePassword(r.Context(), currentUserID(r), input.CurrentPassword, input.NewPassword)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, SignInOkResponse{
+		Status: "ok",
+		Token:  token,
+	})
+}
+
 func (h *AuthHandler) userIdentity(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Header["Authorization"] == nil {
@@ -167,15 +208,43 @@ func (h *AuthHandler) userIdentity(next http.Handler) http.Handler {
 
 		authorization := r.Header.Get("Authorization")
 		token := strings.TrimSpace(strings.Replace(authorization, "Bearer", "", 1))
-		if _, err := jwt.VerifyToken(token); err != nil {
+		claims, err := jwt.ParseToken(token)
+		if err != nil {
 			e := slog.Attr{
 				Key:   "error",
 				Value: slog.StringValue(err.Error()),
 			}
 			h.logger.Error("failed to decode token", e)
 			w.WriteHeader(http.StatusUnauthorized)
-		} else {
-			next.ServeHTTP(w, r)
+			return
 		}
+
+		// Блокировка и принудительный выход действуют и на уже выданные токены
+		if err := h.services.CheckUserActive(claims.UserID, claims.Version); err != nil {
+			switch {
+			case errors.Is(err, service.ErrUserSuspended):
+				w.WriteHeader(http.StatusForbidden)
+			case errors.Is(err, storage.ErrUserNotFound), errors.Is(err, service.ErrSessionRevoked):
+				w.WriteHeader(http.StatusUnauthorized)
+			default:
+				h.logger.Error("failed to check user", slog.String("error", err.Error()))
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userIDCtxKey, claims.UserID)
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+type ctxKey string
+
+const userIDCtxKey ctxKey = "user_id"
+
+// currentUserID возвращает идентификатор пользователя, сохранённый userIdentity
+func currentUserID(r *http.Request) int {
+	uid, _ := r.Context().Value(userIDCtxKey).(int)
+
+	return uid
+}
@@ -0,0 +1,359 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type CFPHandler struct {
+	services transport.CFPServiceInt
+	logger   *slog.Logger
+}
+
+func NewCFPHandler(serv transport.CFPServiceInt, logger *slog.Logger) *CFPHandler {
+	return &CFPHandler{services: serv, logger: logger}
+}
+
+type cfpInput struct {
+	Description string    `json:"description" example:"Ищем доклады про Go в продакшене"`
+	Deadline    time.Time `json:"deadline" validate:"required" example:"2024-02-15T23:59:59+03:00"`
+}
+
+type reviewerInput struct {
+	UserId int `json:"user_id" validate:"required,gt=0" example:"42"`
+}
+
+type talkInput struct {
+	Title    string   `json:"title" validate:"required,max=200" example:"Профилирование Go-сервисов"`
+	Abstract string   `json:"abstract" validate:"required" example:"Как найти утечку памяти за 15 минут"`
+	Level    string   `json:"level" validate:"required,oneof=beginner intermediate advanced" example:"intermediate"`
+	Duration int      `json:"duration" validate:"required,gt=0,lte=240" example:"30"`
+	Tags     []string `json:"tags" validate:"max=10,dive,required,max=50" example:"go,performance"`
+}
+
+type reviewInput struct {
+	Score   int    `json:"score" validate:"required,min=1,max=5" example:"4"`
+	Comment string `json:"comment" example:"Хорошая тема, но слишком длинно"`
+}
+
+type CFPResponse struct {
+	EventId     int       `json:"event_id" example:"1"`
+	Description string    `json:"description" example:"Ищем доклады про Go в продакшене"`
+	Deadline    time.Time `json:"deadline" example:"2024-02-15T23:59:59+03:00"`
+	IsOpen      bool      `json:"is_open" example:"true"`
+}
+
+type CFPOkResponse struct {
+	Status string      `json:"status" example:"ok"`
+	CFP    CFPResponse `json:"cfp"`
+}
+
+type TalkResponse struct {
+	Id           int       `json:"id" example:"7"`
+	EventId      int       `json:"event_id" example:"1"`
+	SpeakerId    int       `json:"speaker_id" example:"123"`
+	Title        string    `json:"title" example:"Профилирование Go-сервисов"`
+	Abstract     string    `json:"abstract" example:"Как найти утечку памяти за 15 минут"`
+	Level        string    `json:"level" example:"intermediate"`
+	Duration     int       `json:"duration" example:"30"`
+	Tags         []string  `json:"tags" example:"go,performance"`
+	Status       string    `json:"status" example:"submitted"`
+	AvgScore     float64   `json:"avg_score" example:"4.5"`
+	ReviewsCount int       `json:"reviews_count" example:"2"`
+	CreatedAt    time.Time `json:"created_at" example:"2024-02-01T12:00:00+03:00"`
+}
+
+type TalksOkResponse struct {
+	Status string         `json:"status" example:"ok"`
+	Talks  []TalkResponse `json:"talks"`
+}
+
+type ReviewResponse struct {
+	Id         int       `json:"id" example:"3"`
+	ReviewerId int       `json:"reviewer_id" example:"42"`
+	Score      int       `json:"score" example:"4"`
+	Comment    string    `json:"comment" example:"Хорошая тема, но слишком длинно"`
+	CreatedAt  time.Time `json:"created_at" example:"2024-02-03T12:00:00+03:00"`
+}
+
+type ReviewsOkResponse struct {
+	Status  string           `json:"status" example:"ok"`
+	Reviews []ReviewResponse `json:"reviews"`
+}
+
+// Открытие приёма докладов
+// @Summary Открытие приёма докладов (CFP) на мероприятие
+// @Tags Доклады
+// @Param id path int true "Идентификатор мероприятия"
+// @Param Request body cfpInput true "Параметры CFP"
+// @Success 200 {object} StatusResponse "Приём докладов открыт"
+// @Failure 201 {object} ErrResponse "Ошибка при открытии CFP"
+// @Router /api/v1/events/{id}/cfp [post]
+func (h *CFPHandler) OpenCFP(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input cfpInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	err := h.services.OpenCFP(r.Context(), currentUserID(r), models.CallForPapers{
+		EventID:     eventID,
+		Description: input.Description,
+		Deadline:    input.Deadline,
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Параметры приёма докладов
+// @Summary Параметры приёма докладов на мероприятие
+// @Tags Доклады
+// @Param id path int true "Идентификатор мероприятия"
+// @Success 200 {object} CFPOkResponse "Параметры CFP"
+// @Failure 201 {object} ErrResponse "CFP не найден"
+// @Router /api/v1/events/{id}/cfp [get]
+func (h *CFPHandler) CFP(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	cfp, err := h.services.CFP(eventID)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, CFPOkResponse{
+		Status: "ok",
+		CFP: CFPResponse{
+			EventId:     cfp.EventID,
+			Description: cfp.Description,
+			Deadline:    cfp.Deadline,
+			IsOpen:      cfp.IsOpen(time.Now()),
+		},
+	})
+}
+
+// Назначение рецензента
+// @Summary Назначение рецензента докладов (только организатор)
+// @Tags Доклады
+// @Param id path int true "Идентификатор мероприятия"
+// @Param Request body reviewerInput true "Рецензент"
+// @Success 200 {object} StatusResponse "Рецензент назначен"
+// @Failure 201 {object} ErrResponse "Ошибка при назначении рецензента"
+// @Router /api/v1/events/{id}/cfp/reviewers [post]
+func (h *CFPHandler) AddReviewer(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input reviewerInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	if err := h.services.AddReviewer(r.Context(), currentUserID(r), eventID, input.UserId); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Подача доклада
+// @Summary Подача заявки на доклад
+// @Tags Доклады
+// @Param id path int true "Идентификатор мероприятия"
+// @Param Request body talkInput true "Заявка на доклад"
+// @Success 200 {object} IdResponse "Заявка принята на рассмотрение"
+// @Failure 201 {object} ErrResponse "Ошибка при подаче заявки"
+// @Router /api/v1/events/{id}/talks [post]
+func (h *CFPHandler) SubmitTalk(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input talkInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	id, err := h.services.SubmitTalk(models.Talk{
+		EventID:   eventID,
+		SpeakerID: currentUserID(r),
+		Title:     input.Title,
+		Abstract:  input.Abstract,
+		Level:     input.Level,
+		Duration:  input.Duration,
+		Tags:      input.Tags,
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, IdResponse{Status: "ok", Id: id})
+}
+
+// Заявки на доклады
+// @Summary Заявки на доклады с оценками (организатор и рецензенты)
+// @Tags Доклады
+// @Param id path int true "Идентификатор мероприятия"
+// @Success 200 {object} TalksOkResponse "Заявки на доклады"
+// @Failure 201 {object} ErrResponse "Ошибка при получении заявок"
+// @Router /api/v1/events/{id}/talks [get]
+func (h *CFPHandler) Talks(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	talks, err := h.services.Talks(currentUserID(r), eventID)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := TalksOkResponse{Status: "ok", Talks: make([]TalkResponse, 0, len(talks))}
+	for _, talk := range talks {
+		response.Talks = append(response.Talks, TalkResponse{
+			Id:           talk.ID,
+			EventId:      talk.EventID,
+			SpeakerId:    talk.SpeakerID,
+			Title:        talk.Title,
+			Abstract:     talk.Abstract,
+			Level:        talk.Level,
+			Duration:     talk.Duration,
+			Tags:         talk.Tags,
+			Status:       talk.Status,
+			AvgScore:     talk.AvgScore,
+			ReviewsCount: talk.ReviewsCount,
+			CreatedAt:    talk.CreatedAt,
+		})
+	}
+
+	render.JSON(w, r, response)
+}
+
+// Рецензия на доклад
+// @Summary Оценка доклада рецензентом
+// @Tags Доклады
+// @Param id path int true "Идентификатор доклада"
+// @Param Request body reviewInput true "Оценка и комментарий"
+// @Success 200 {object} IdResponse "Рецензия сохранена"
+// @Failure 201 {object} ErrResponse "Ошибка при сохранении рецензии"
+// @Router /api/v1/talks/{id}/reviews [post]
+func (h *CFPHandler) ReviewTalk(w http.ResponseWriter, r *http.Request) {
+	talkID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input reviewInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	id, err := h.services.ReviewTalk(models.TalkReview{
+		TalkID:     talkID,
+		ReviewerID: currentUserID(r),
+		Score:      input.Score,
+		Comment:    input.Comment,
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, IdResponse{Status: "ok", Id: id})
+}
+
+// Рецензии на доклад
+// @Summary Рецензии на доклад (организатор и рецензенты)
+// @Tags Доклады
+// @Param id path int true "Идентификатор доклада"
+// @Success 200 {object} ReviewsOkResponse "Рецензии"
+// @Failure 201 {object} ErrResponse "Ошибка при получении рецензий"
+// @Router /api/v1/talks/{id}/reviews [get]
+func (h *CFPHandler) Reviews(w http.ResponseWriter, r *http.Request) {
+	talkID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	reviews, err := h.services.Reviews(currentUserID(r), talkID)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := ReviewsOkResponse{Status: "ok", Reviews: make([]ReviewResponse, 0, len(reviews))}
+	for _, review := range reviews {
+		response.Reviews = append(response.Reviews, ReviewResponse{
+			Id:         review.ID,
+			ReviewerId: review.ReviewerID,
+			Score:      review.Score,
+			Comment:    review.Comment,
+			CreatedAt:  review.CreatedAt,
+		})
+	}
+
+	render.JSON(w, r, response)
+}
+
+// Принятие доклада
+// @Summary Принятие доклада в программу мероприятия (только организатор)
+// @Tags Доклады
+// @Param id path int true "Идентификатор доклада"
+// @Success 200 {object} StatusResponse "Доклад принят и добавлен в программу"
+// @Failure 201 {object} ErrResponse "Ошибка при принятии доклада"
+// @Router /api/v1/talks/{id}/accept [post]
+func (h *CFPHandler) AcceptTalk(w http.ResponseWriter, r *http.Request) {
+	talkID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.AcceptTalk(r.Context(), currentUserID(r), talkID); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Отклонение доклада
+// @Summary Отклонение доклада (только организатор)
+// @Tags Доклады
+// @Param id path int true "Идентификатор доклада"
+// @Success 200 {object} StatusResponse "Доклад отклонён"
+// @Failure 201 {object} ErrResponse "Ошибка при отклонении доклада"
+// @Router /api/v1/talks/{id}/reject [post]
+func (h *CFPHandler) RejectTalk(w http.ResponseWriter, r *http.Request) {
+	talkID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.RejectTalk(r.Context(), currentUserID(r), talkID); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
@@ -0,0 +1,268 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type CommentHandler struct {
+	services transport.CommentServiceInt
+	logger   *slog.Logger
+}
+
+func NewCommentHandler(serv transport.CommentServiceInt, logger *slog.Logger) *CommentHandler {
+	return &CommentHandler{services: serv, logger: logger}
+}
+
+type commentInput struct {
+	Body     string `json:"body" validate:"required,max=10000" example:"Есть ли рядом парковка? @organizer"`
+	ParentId *int   `json:"parent_id" validate:"omitempty,gt=0" example:"12"`
+}
+
+type commentUpdateInput struct {
+	Body string `json:"body" validate:"required,max=10000" example:"Есть ли рядом бесплатная парковка?"`
+}
+
+type CommentResponse struct {
+	Id        int               `json:"id" example:"12"`
+	ParentId  *int              `json:"parent_id,omitempty" example:"10"`
+	AuthorId  int               `json:"author_id" example:"123"`
+	Body      string            `json:"body" example:"Есть ли рядом **парковка**?"`
+	BodyHTML  string            `json:"body_html" example:"<p>Есть ли рядом <strong>парковка</strong>?</p>"`
+	CreatedAt time.Time         `json:"created_at" example:"2024-02-20T12:00:00+03:00"`
+	EditedAt  *time.Time        `json:"edited_at,omitempty" example:"2024-02-20T12:05:00+03:00"`
+	Deleted   bool              `json:"deleted" example:"false"`
+	Hidden    bool              `json:"hidden" example:"false"`
+	Replies   []CommentResponse `json:"replies,omitempty"`
+}
+
+type CommentsOkResponse struct {
+	Status     string            `json:"status" example:"ok"`
+	Comments   []CommentResponse `json:"comments"`
+	NextCursor string            `json:"next_cursor,omitempty" example:"MTI"`
+}
+
+type CommentRevisionResponse struct {
+	Body      string    `json:"body" example:"Есть ли рядом парковка?"`
+	CreatedAt time.Time `json:"created_at" example:"2024-02-20T12:05:00+03:00"`
+}
+
+type CommentHistoryOkResponse struct {
+	Status    string                    `json:"status" example:"ok"`
+	Revisions []CommentRevisionResponse `json:"revisions"`
+}
+
+func newCommentResponse(comment models.Comment) CommentResponse {
+	response := CommentResponse{
+		Id:        comment.ID,
+		ParentId:  comment.ParentID,
+		AuthorId:  comment.AuthorID,
+		Body:      comment.Body,
+		BodyHTML:  comment.BodyHTML,
+		CreatedAt: comment.CreatedAt,
+		EditedAt:  comment.EditedAt,
+		Deleted:   comment.DeletedAt != nil,
+		Hidden:    comment.HiddenAt != nil,
+	}
+
+	for _, reply := range comment.Replies {
+		response.Replies = append(response.Replies, newCommentResponse(reply))
+	}
+
+	return response
+}
+
+// Обсуждение мероприятия
+// @Summary Треды обсуждения мероприятия с ответами, новые сначала
+// @Tags Обсуждения
+// @Param id path int true "Идентификатор мероприятия"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param limit query int false "Количество тредов (по умолчанию 20)"
+// @Success 200 {object} CommentsOkResponse "Треды"
+// @Failure 201 {object} ErrResponse "Ошибка при получении обсуждения"
+// @Router /api/v1/events/{id}/comments [get]
+func (h *CommentHandler) EventComments(w http.ResponseWriter, r *http.Request) {
+	h.comments(w, r, models.CommentSubjectEvent)
+}
+
+// Комментарий к мероприятию
+// @Summary Комментарий или ответ в обсуждении мероприятия
+// @Description Текст в Markdown, HTML очищается. Упомянутые через @username пользователи получают уведомление.
+// @Tags Обсуждения
+// @Param id path int true "Идентификатор мероприятия"
+// @Param Request body commentInput true "Комментарий"
+// @Success 200 {object} IdResponse "Комментарий добавлен"
+// @Failure 201 {object} ErrResponse "Ошибка при добавлении комментария"
+// @Router /api/v1/events/{id}/comments [post]
+func (h *CommentHandler) CreateEventComment(w http.ResponseWriter, r *http.Request) {
+	h.createComment(w, r, models.CommentSubjectEvent)
+}
+
+// Обсуждение сообщества
+// @Summary Треды обсуждения сообщества с ответами, новые сначала
+// @Tags Обсуждения
+// @Param id path int true "Идентификатор сообщества"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param limit query int false "Количество тредов (по умолчанию 20)"
+// @Success 200 {object} CommentsOkResponse "Треды"
+// @Failure 201 {object} ErrResponse "Ошибка при получении обсуждения"
+// @Router /api/v1/groups/{id}/comments [get]
+func (h *CommentHandler) GroupComments(w http.ResponseWriter, r *http.Request) {
+	h.comments(w, r, models.CommentSubjectGroup)
+}
+
+// Комментарий в сообществе
+// @Summary Комментарий или ответ в обсуждении сообщества
+// @Description Текст в Markdown, HTML очищается. Упомянутые через @username пользователи получают уведомление.
+// @Tags Обсуждения
+// @Param id path int true "Идентификатор сообщества"
+// @Param Request body commentInput true "Комментарий"
+// @Success 200 {object} IdResponse "Комментарий добавлен"
+// @Failure 201 {object} ErrResponse "Ошибка при добавлении комментария"
+// @Router /api/v1/groups/{id}/comments [post]
+func (h *CommentHandler) CreateGroupComment(w http.ResponseWriter, r *http.Request) {
+	h.createComment(w, r, models.CommentSubjectGroup)
+}
+
+// Редактирование комментария
+// @Summary Редактирование комментария (только автор), прежний текст сохраняется в истории
+// @Tags Обсуждения
+// @Param id path int true "Идентификатор комментария"
+// @Param Request body commentUpdateInput true "Новый текст"
+// @Success 200 {object} StatusResponse "Комментарий изменён"
+// @Failure 201 {object} ErrResponse "Ошибка при изменении комментария"
+// @Router /api/v1/comments/{id} [put]
+func (h *CommentHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input commentUpdateInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	if err := h.services.UpdateComment(currentUserID(r), id, input.Body); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Удаление комментария
+// @Summary Удаление комментария (автор, организатор мероприятия или владелец сообщества)
+// @Description Ответы на удалённый комментарий остаются в треде.
+// @Tags Обсуждения
+// @Param id path int true "Идентификатор комментария"
+// @Success 200 {object} StatusResponse "Комментарий удалён"
+// @Failure 201 {object} ErrResponse "Ошибка при удалении комментария"
+// @Router /api/v1/comments/{id} [delete]
+func (h *CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.DeleteComment(currentUserID(r), id); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// История комментария
+// @Summary Прежние версии комментария (автор, организатор мероприятия или владелец сообщества)
+// @Tags Обсуждения
+// @Param id path int true "Идентификатор комментария"
+// @Success 200 {object} CommentHistoryOkResponse "История правок"
+// @Failure 201 {object} ErrResponse "Ошибка при получении истории"
+// @Router /api/v1/comments/{id}/history [get]
+func (h *CommentHandler) CommentHistory(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	revisions, err := h.services.CommentHistory(currentUserID(r), id)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := CommentHistoryOkResponse{Status: "ok", Revisions: make([]CommentRevisionResponse, 0, len(revisions))}
+	for _, revision := range revisions {
+		response.Revisions = append(response.Revisions, CommentRevisionResponse{
+			Body:      revision.Body,
+			CreatedAt: revision.CreatedAt,
+		})
+	}
+
+	render.JSON(w, r, response)
+}
+
+func (h *CommentHandler) comments(w http.ResponseWriter, r *http.Request, subjectType string) {
+	subjectID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	limit, cursor, ok := cursorPagination(r)
+	if !ok {
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+		return
+	}
+
+	threads, err := h.services.Comments(models.CommentFilter{
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		Cursor:      cursor,
+		Limit:       limit,
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := CommentsOkResponse{Status: "ok", Comments: make([]CommentResponse, 0, len(threads))}
+	for _, thread := range threads {
+		response.Comments = append(response.Comments, newCommentResponse(thread))
+	}
+	if len(threads) > 0 {
+		response.NextCursor = nextCursor(threads[len(threads)-1].ID, len(threads), limit)
+	}
+
+	render.JSON(w, r, response)
+}
+
+func (h *CommentHandler) createComment(w http.ResponseWriter, r *http.Request, subjectType string) {
+	subjectID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input commentInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	id, err := h.services.CreateComment(models.Comment{
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		ParentID:    input.ParentId,
+		AuthorID:    currentUserID(r),
+		Body:        input.Body,
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, IdResponse{Status: "ok", Id: id})
+}
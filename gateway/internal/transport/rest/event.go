@@ -0,0 +1,261 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"dev_meets/pkg/ics"
+	"fmt"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type EventHandler struct {
+	services transport.EventServiceInt
+	logger   *slog.Logger
+}
+
+func NewEventHandler(serv transport.EventServiceInt, logger *slog.Logger) *EventHandler {
+	return &EventHandler{services: serv, logger: logger}
+}
+
+type eventInput struct {
+	VenueId     *int      `json:"venue_id" validate:"omitempty,gt=0" example:"5"`
+	GroupId     *int      `json:"group_id" validate:"omitempty,gt=0" example:"3"`
+	City        string    `json:"city" validate:"max=100" example:"Москва"`
+	Title       string    `json:"title" validate:"required,max=200" example:"Go meetup #12"`
+	Description string    `json:"description" example:"Доклады про конкурентность в Go"`
+	StartsAt    time.Time `json:"starts_at" validate:"required" example:"2024-03-01T19:00:00+03:00"`
+	EndsAt      time.Time `json:"ends_at" validate:"required" example:"2024-03-01T22:00:00+03:00"`
+	// Private - записаться можно только по приглашению
+	Private bool `json:"private" example:"false"`
+}
+
+type EventResponse struct {
+	Id          int                  `json:"id" example:"1"`
+	OrganizerId int                  `json:"organizer_id" example:"123"`
+	VenueId     *int                 `json:"venue_id,omitempty" example:"5"`
+	GroupId     *int                 `json:"group_id,omitempty" example:"3"`
+	City        string               `json:"city" example:"Москва"`
+	DistanceKm  *float64             `json:"distance_km,omitempty" example:"1.7"`
+	Title       string               `json:"title" example:"Go meetup #12"`
+	Description string               `json:"description" example:"Доклады про конкурентность в Go"`
+	StartsAt    time.Time            `json:"starts_at" example:"2024-03-01T19:00:00+03:00"`
+	EndsAt      time.Time            `json:"ends_at" example:"2024-03-01T22:00:00+03:00"`
+	CancelledAt *time.Time           `json:"cancelled_at,omitempty" example:"2024-02-25T10:00:00+03:00"`
+	Private     bool                 `json:"private" example:"false"`
+	Agenda      []AgendaSlotResponse `json:"agenda,omitempty"`
+}
+
+type EventOkResponse struct {
+	Status string        `json:"status" example:"ok"`
+	Event  EventResponse `json:"event"`
+}
+
+type EventsOkResponse struct {
+	Status string          `json:"status" example:"ok"`
+	Events []EventResponse `json:"events"`
+}
+
+func newEventResponse(event models.Event) EventResponse {
+	return EventResponse{
+		Id:          event.ID,
+		OrganizerId: event.OrganizerID,
+		VenueId:     event.VenueID,
+		GroupId:     event.GroupID,
+		City:        event.City,
+		DistanceKm:  event.DistanceKm,
+		Title:       event.Title,
+		Description: event.Description,
+		StartsAt:    event.StartsAt,
+		EndsAt:      event.EndsAt,
+		CancelledAt: event.CancelledAt,
+		Private:     event.Private,
+		Agenda:      newAgendaResponse(event.Agenda),
+	}
+}
+
+// Создание мероприятия
+// @Summary Создание мероприятия
+// @Tags Мероприятия
+// @Param Request body eventInput true "Параметры мероприятия"
+// @Success 200 {object} IdResponse "Мероприятие создано"
+// @Failure 201 {object} ErrResponse "Ошибка при создании мероприятия"
+// @Router /api/v1/events [post]
+func (h *EventHandler) CreateEvent(w http.ResponseWriter, r *http.Request) {
+	var input eventInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	id, err := h.services.CreateEvent(r.Context(), models.Event{
+		OrganizerID: currentUserID(r),
+		VenueID:     input.VenueId,
+		GroupID:     input.GroupId,
+		City:        input.City,
+		Title:       input.Title,
+		Description: input.Description,
+		StartsAt:    input.StartsAt,
+		EndsAt:      input.EndsAt,
+		Private:     input.Private,
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, IdResponse{Status: "ok", Id: id})
+}
+
+// Отмена мероприятия
+// @Summary Отмена мероприятия организатором
+// @Description Записаться на отменённое мероприятие нельзя. Вебхуки сообщества получат event.cancelled.
+// @Tags Мероприятия
+// @Param id path int true "Идентификатор мероприятия"
+// @Success 200 {object} StatusResponse "Мероприятие отменено"
+// @Failure 201 {object} ErrResponse "Мероприятие не найдено, уже отменено или нет прав"
+// @Router /api/v1/events/{id}/cancel [post]
+func (h *EventHandler) CancelEvent(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.CancelEvent(r.Context(), currentUserID(r), id); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Мероприятие
+// @Summary Мероприятие по идентификатору вместе с программой
+// @Tags Мероприятия
+// @Param id path int true "Идентификатор мероприятия"
+// @Success 200 {object} EventOkResponse "Мероприятие"
+// @Failure 201 {object} ErrResponse "Мероприятие не найдено"
+// @Router /api/v1/events/{id} [get]
+func (h *EventHandler) Event(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	event, err := h.services.Event(id)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, EventOkResponse{Status: "ok", Event: newEventResponse(event)})
+}
+
+// Список мероприятий
+// @Summary Список мероприятий, при заданном near - ближайшие к точке
+// @Tags Мероприятия
+// @Param near query string false "Координаты центра поиска: lat,lon"
+// @Param radius_km query number false "Радиус поиска в километрах (по умолчанию 10)"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} EventsOkResponse "Мероприятия"
+// @Failure 201 {object} ErrResponse "Внутренняя ошибка сервиса"
+// @Router /api/v1/events [get]
+func (h *EventHandler) Events(w http.ResponseWriter, r *http.Request) {
+	limit, offset := pagination(r)
+
+	near, err := geoFilter(r)
+	if err != nil {
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+		return
+	}
+
+	events, err := h.services.Events(models.EventFilter{Near: near, Limit: limit, Offset: offset})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := EventsOkResponse{Status: "ok", Events: make([]EventResponse, 0, len(events))}
+	for _, event := range events {
+		response.Events = append(response.Events, newEventResponse(event))
+	}
+
+	render.JSON(w, r, response)
+}
+
+// Мероприятие в формате iCalendar
+// @Summary Файл .ics для добавления мероприятия в календарь, программа в описании
+// @Tags Мероприятия
+// @Produce text/calendar
+// @Param id path int true "Идентификатор мероприятия"
+// @Success 200 {string} string "Файл iCalendar"
+// @Failure 201 {object} ErrResponse "Мероприятие не найдено"
+// @Router /api/v1/events/{id}/ics [get]
+func (h *EventHandler) EventICS(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	event, err := h.services.Event(id)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	calendar := ics.Calendar(ics.Event{
+		UID:         ics.UID("event", event.ID, "dev_meets"),
+		Summary:     event.Title,
+		Description: icsDescription(event),
+		Start:       event.StartsAt,
+		End:         event.EndsAt,
+		Stamp:       time.Now(),
+	})
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%d.ics"`, event.ID))
+	w.Write([]byte(calendar))
+}
+
+// icsDescription дополняет описание мероприятия его программой
+func icsDescription(event models.Event) string {
+	if len(event.Agenda) == 0 {
+		return event.Description
+	}
+
+	var b strings.Builder
+	if event.Description != "" {
+		b.WriteString(event.Description)
+		b.WriteString("\n\n")
+	}
+	b.WriteString("Программа:")
+
+	for _, slot := range event.Agenda {
+		b.WriteString("\n")
+		if slot.StartsAt != nil {
+			b.WriteString(slot.StartsAt.In(event.StartsAt.Location()).Format("15:04"))
+			if slot.EndsAt != nil {
+				b.WriteString("–")
+				b.WriteString(slot.EndsAt.In(event.StartsAt.Location()).Format("15:04"))
+			}
+			b.WriteString(" ")
+		}
+		b.WriteString(slot.Title)
+
+		names := make([]string, 0, len(slot.Speakers))
+		for _, speaker := range slot.Speakers {
+			if speaker.Name != "" {
+				names = append(names, speaker.Name)
+			}
+		}
+		if len(names) > 0 {
+			b.WriteString(" — ")
+			b.WriteString(strings.Join(names, ", "))
+		}
+	}
+
+	return b.String()
+}
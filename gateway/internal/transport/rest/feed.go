@@ -0,0 +1,116 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type FeedHandler struct {
+	services transport.FeedServiceInt
+	logger   *slog.Logger
+}
+
+func NewFeedHandler(serv transport.FeedServiceInt, logger *slog.Logger) *FeedHandler {
+	return &FeedHandler{services: serv, logger: logger}
+}
+
+type FeedItemResponse struct {
+	Event   EventResponse `json:"event"`
+	Score   float64       `json:"score" example:"8.5"`
+	Reasons []string      `json:"reasons" example:"Мероприятие вашего сообщества,Идут ваши знакомые: 2"`
+}
+
+type FeedOkResponse struct {
+	Status string             `json:"status" example:"ok"`
+	Items  []FeedItemResponse `json:"items"`
+}
+
+// Лента
+// @Summary Персональная лента предстоящих мероприятий
+// @Description Мероприятия ранжируются по навыкам пользователя, его сообществам, прошлым посещениям,
+// @Description записям знакомых и расстоянию до площадки, reasons объясняет оценку.
+// @Description Мероприятия, на которые пользователь уже записался или которые скрыл, не показываются.
+// @Description Лента обновляется раз в несколько минут.
+// @Tags Лента
+// @Param near query string false "Координаты пользователя в формате lat,lon"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} FeedOkResponse "Лента"
+// @Failure 201 {object} ErrResponse "Ошибка при получении ленты"
+// @Router /api/v1/feed [get]
+func (h *FeedHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	limit, offset := pagination(r)
+
+	filter, err := geoFilter(r)
+	if err != nil {
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+		return
+	}
+	var near *models.GeoPoint
+	if filter != nil {
+		near = &filter.Point
+	}
+
+	items, err := h.services.Feed(currentUserID(r), near, limit, offset)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := FeedOkResponse{Status: "ok", Items: make([]FeedItemResponse, 0, len(items))}
+	for _, item := range items {
+		response.Items = append(response.Items, FeedItemResponse{
+			Event:   newEventResponse(item.Event),
+			Score:   item.Score,
+			Reasons: item.Reasons,
+		})
+	}
+
+	render.JSON(w, r, response)
+}
+
+// Неинтересное мероприятие
+// @Summary Отметка «не интересно»
+// @Description Мероприятие пропадает из ленты, другие мероприятия того же сообщества и организатора опускаются ниже.
+// @Tags Лента
+// @Param id path int true "Идентификатор мероприятия"
+// @Success 200 {object} StatusResponse "Мероприятие скрыто"
+// @Failure 201 {object} ErrResponse "Мероприятие не найдено"
+// @Router /api/v1/feed/{id}/not-interested [post]
+func (h *FeedHandler) DismissFeedEvent(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.DismissEvent(currentUserID(r), id); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Отмена отметки
+// @Summary Отмена отметки «не интересно»
+// @Tags Лента
+// @Param id path int true "Идентификатор мероприятия"
+// @Success 200 {object} StatusResponse "Мероприятие снова в ленте"
+// @Failure 201 {object} ErrResponse "Мероприятие не было скрыто"
+// @Router /api/v1/feed/{id}/not-interested [delete]
+func (h *FeedHandler) UndismissFeedEvent(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.UndismissEvent(currentUserID(r), id); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
@@ -0,0 +1,227 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type FeedbackHandler struct {
+	services transport.FeedbackServiceInt
+	logger   *slog.Logger
+}
+
+func NewFeedbackHandler(serv transport.FeedbackServiceInt, logger *slog.Logger) *FeedbackHandler {
+	return &FeedbackHandler{services: serv, logger: logger}
+}
+
+type feedbackInput struct {
+	Rating    int    `json:"rating" validate:"required,min=1,max=5" example:"5"`
+	Comment   string `json:"comment" validate:"max=5000" example:"Отличные доклады, хотелось бы больше времени на вопросы"`
+	Anonymous bool   `json:"anonymous" example:"false"`
+}
+
+type RatingSummaryResponse struct {
+	Count        int     `json:"count" example:"42"`
+	Average      float64 `json:"average" example:"4.6"`
+	Distribution []int   `json:"distribution" example:"0,1,2,10,29"`
+}
+
+type FeedbackCommentResponse struct {
+	UserId    *int      `json:"user_id,omitempty" example:"123"`
+	Rating    int       `json:"rating" example:"5"`
+	Comment   string    `json:"comment" example:"Отличные доклады, хотелось бы больше времени на вопросы"`
+	CreatedAt time.Time `json:"created_at" example:"2024-03-02T10:00:00+03:00"`
+}
+
+type TalkRatingResponse struct {
+	SlotId int    `json:"slot_id" example:"2"`
+	Title  string `json:"title" example:"Конкурентность в Go"`
+	RatingSummaryResponse
+}
+
+type EventFeedbackOkResponse struct {
+	Status   string                    `json:"status" example:"ok"`
+	Rating   RatingSummaryResponse     `json:"rating"`
+	Comments []FeedbackCommentResponse `json:"comments"`
+	Talks    []TalkRatingResponse      `json:"talks"`
+}
+
+type TalkFeedbackOkResponse struct {
+	Status   string                    `json:"status" example:"ok"`
+	SlotId   int                       `json:"slot_id" example:"2"`
+	Title    string                    `json:"title" example:"Конкурентность в Go"`
+	Rating   RatingSummaryResponse     `json:"rating"`
+	Comments []FeedbackCommentResponse `json:"comments"`
+}
+
+func newRatingSummaryResponse(summary models.RatingSummary) RatingSummaryResponse {
+	return RatingSummaryResponse{
+		Count:        summary.Count,
+		Average:      summary.Average,
+		Distribution: summary.Distribution[:],
+	}
+}
+
+func newFeedbackCommentsResponse(feedback []models.Feedback) []FeedbackCommentResponse {
+	comments := make([]FeedbackCommentResponse, 0, len(feedback))
+	for _, f := range feedback {
+		comment := FeedbackCommentResponse{Rating: f.Rating, Comment: f.Comment, CreatedAt: f.CreatedAt}
+		if !f.Anonymous {
+			userID := f.UserID
+			comment.UserId = &userID
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments
+}
+
+// Оценка мероприятия
+// @Summary Оценка мероприятия после его окончания (только записавшиеся участники)
+// @Description Повторная оценка заменяет прежнюю. Автор анонимного отзыва не виден организатору.
+// @Tags Отзывы
+// @Param id path int true "Идентификатор мероприятия"
+// @Param Request body feedbackInput true "Отзыв"
+// @Success 200 {object} IdResponse "Отзыв сохранён"
+// @Failure 201 {object} ErrResponse "Ошибка при сохранении отзыва"
+// @Router /api/v1/events/{id}/feedback [post]
+func (h *FeedbackHandler) LeaveEventFeedback(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input feedbackInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	id, err := h.services.LeaveEventFeedback(models.Feedback{
+		EventID:   eventID,
+		UserID:    currentUserID(r),
+		Rating:    input.Rating,
+		Comment:   input.Comment,
+		Anonymous: input.Anonymous,
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, IdResponse{Status: "ok", Id: id})
+}
+
+// Оценка доклада
+// @Summary Оценка доклада из программы мероприятия после его окончания (только записавшиеся участники)
+// @Tags Отзывы
+// @Param id path int true "Идентификатор мероприятия"
+// @Param slotId path int true "Идентификатор слота программы"
+// @Param Request body feedbackInput true "Отзыв"
+// @Success 200 {object} IdResponse "Отзыв сохранён"
+// @Failure 201 {object} ErrResponse "Ошибка при сохранении отзыва"
+// @Router /api/v1/events/{id}/agenda/{slotId}/feedback [post]
+func (h *FeedbackHandler) LeaveTalkFeedback(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	slotID, ok := idParam(w, r, "slotId")
+	if !ok {
+		return
+	}
+
+	var input feedbackInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	id, err := h.services.LeaveTalkFeedback(models.Feedback{
+		EventID:   eventID,
+		SlotID:    &slotID,
+		UserID:    currentUserID(r),
+		Rating:    input.Rating,
+		Comment:   input.Comment,
+		Anonymous: input.Anonymous,
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, IdResponse{Status: "ok", Id: id})
+}
+
+// Отзывы о мероприятии
+// @Summary Сводка оценок мероприятия и его докладов (только организатор)
+// @Tags Отзывы
+// @Param id path int true "Идентификатор мероприятия"
+// @Success 200 {object} EventFeedbackOkResponse "Сводка отзывов"
+// @Failure 201 {object} ErrResponse "Ошибка при получении отзывов"
+// @Router /api/v1/events/{id}/feedback [get]
+func (h *FeedbackHandler) EventFeedback(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	report, err := h.services.EventFeedback(currentUserID(r), eventID)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := EventFeedbackOkResponse{
+		Status:   "ok",
+		Rating:   newRatingSummaryResponse(report.RatingSummary),
+		Comments: newFeedbackCommentsResponse(report.Comments),
+		Talks:    make([]TalkRatingResponse, 0, len(report.Talks)),
+	}
+	for _, talk := range report.Talks {
+		response.Talks = append(response.Talks, TalkRatingResponse{
+			SlotId:                talk.SlotID,
+			Title:                 talk.Title,
+			RatingSummaryResponse: newRatingSummaryResponse(talk.RatingSummary),
+		})
+	}
+
+	render.JSON(w, r, response)
+}
+
+// Отзывы о докладе
+// @Summary Отзывы о докладе (только докладчики этого доклада и организатор)
+// @Tags Отзывы
+// @Param id path int true "Идентификатор мероприятия"
+// @Param slotId path int true "Идентификатор слота программы"
+// @Success 200 {object} TalkFeedbackOkResponse "Отзывы о докладе"
+// @Failure 201 {object} ErrResponse "Ошибка при получении отзывов"
+// @Router /api/v1/events/{id}/agenda/{slotId}/feedback [get]
+func (h *FeedbackHandler) TalkFeedback(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	slotID, ok := idParam(w, r, "slotId")
+	if !ok {
+		return
+	}
+
+	report, err := h.services.TalkFeedback(currentUserID(r), eventID, slotID)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, TalkFeedbackOkResponse{
+		Status:   "ok",
+		SlotId:   report.Slot.ID,
+		Title:    report.Slot.Title,
+		Rating:   newRatingSummaryResponse(report.RatingSummary),
+		Comments: newFeedbackCommentsResponse(report.Comments),
+	})
+}
@@ -0,0 +1,322 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type FollowHandler struct {
+	services transport.FollowServiceInt
+	logger   *slog.Logger
+}
+
+func NewFollowHandler(serv transport.FollowServiceInt, logger *slog.Logger) *FollowHandler {
+	return &FollowHandler{services: serv, logger: logger}
+}
+
+type FollowResponse struct {
+	UserId    int       `json:"user_id" example:"12"`
+	Username  string    `json:"username,omitempty" example:"gopher"`
+	Name      string    `json:"name" example:"Иван Петров"`
+	CreatedAt time.Time `json:"created_at" example:"2024-02-20T12:00:00+03:00"`
+}
+
+type FollowsOkResponse struct {
+	Status  string           `json:"status" example:"ok"`
+	Follows []FollowResponse `json:"follows"`
+}
+
+type GroupFollowResponse struct {
+	GroupId   int       `json:"group_id" example:"4"`
+	Name      string    `json:"name" example:"Moscow Gophers"`
+	City      string    `json:"city" example:"Москва"`
+	CreatedAt time.Time `json:"created_at" example:"2024-02-20T12:00:00+03:00"`
+}
+
+type GroupFollowsOkResponse struct {
+	Status string                `json:"status" example:"ok"`
+	Groups []GroupFollowResponse `json:"groups"`
+}
+
+type ActivityResponse struct {
+	Id            int       `json:"id" example:"31"`
+	Kind          string    `json:"kind" example:"event_created"`
+	ActorId       int       `json:"actor_id" example:"12"`
+	ActorName     string    `json:"actor_name" example:"Иван Петров"`
+	ActorUsername string    `json:"actor_username,omitempty" example:"gopher"`
+	GroupId       *int      `json:"group_id,omitempty" example:"4"`
+	GroupName     string    `json:"group_name,omitempty" example:"Moscow Gophers"`
+	EventId       *int      `json:"event_id,omitempty" example:"7"`
+	EventTitle    string    `json:"event_title,omitempty" example:"Go Meetup #12"`
+	TalkId        *int      `json:"talk_id,omitempty" example:"3"`
+	TalkTitle     string    `json:"talk_title,omitempty" example:"Профилирование Go-сервисов"`
+	CreatedAt     time.Time `json:"created_at" example:"2024-02-20T12:00:00+03:00"`
+}
+
+type ActivitiesOkResponse struct {
+	Status     string             `json:"status" example:"ok"`
+	Activities []ActivityResponse `json:"activities"`
+	NextCursor string             `json:"next_cursor,omitempty" example:"MzE"`
+}
+
+func newFollowsResponse(follows []models.Follow) FollowsOkResponse {
+	response := FollowsOkResponse{Status: "ok", Follows: make([]FollowResponse, 0, len(follows))}
+	for _, f := range follows {
+		response.Follows = append(response.Follows, FollowResponse{
+			UserId:    f.UserID,
+			Username:  f.Username,
+			Name:      f.Name,
+			CreatedAt: f.CreatedAt,
+		})
+	}
+
+	return response
+}
+
+// Подписка на пользователя
+// @Summary Подписка на пользователя
+// @Description Его новые мероприятия, принятые доклады и вступления в сообщества появятся в ленте подписок.
+// @Description Подписаться на того, с кем есть блокировка, нельзя.
+// @Tags Подписки
+// @Param id path int true "Идентификатор пользователя"
+// @Success 200 {object} StatusResponse "Подписка оформлена"
+// @Failure 201 {object} ErrResponse "Пользователь не найден или заблокирован"
+// @Router /api/v1/users/{id}/follow [post]
+func (h *FollowHandler) FollowUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.FollowUser(currentUserID(r), id); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Отписка от пользователя
+// @Summary Отписка от пользователя
+// @Tags Подписки
+// @Param id path int true "Идентификатор пользователя"
+// @Success 200 {object} StatusResponse "Подписка отменена"
+// @Failure 201 {object} ErrResponse "Подписки нет"
+// @Router /api/v1/users/{id}/follow [delete]
+func (h *FollowHandler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.UnfollowUser(currentUserID(r), id); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Подписчики пользователя
+// @Summary Подписчики пользователя, новые сначала
+// @Tags Подписки
+// @Param id path int true "Идентификатор пользователя"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} FollowsOkResponse "Подписчики"
+// @Failure 201 {object} ErrResponse "Ошибка при получении списка"
+// @Router /api/v1/users/{id}/followers [get]
+func (h *FollowHandler) Followers(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+	limit, offset := pagination(r)
+
+	follows, err := h.services.Followers(id, limit, offset)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, newFollowsResponse(follows))
+}
+
+// Подписки пользователя
+// @Summary Пользователи, на которых подписан пользователь, новые подписки сначала
+// @Tags Подписки
+// @Param id path int true "Идентификатор пользователя"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} FollowsOkResponse "Подписки"
+// @Failure 201 {object} ErrResponse "Ошибка при получении списка"
+// @Router /api/v1/users/{id}/following [get]
+func (h *FollowHandler) Following(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+	limit, offset := pagination(r)
+
+	follows, err := h.services.Following(id, limit, offset)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, newFollowsResponse(follows))
+}
+
+// Сообщества в подписках пользователя
+// @Summary Сообщества, на которые подписан пользователь, новые подписки сначала
+// @Tags Подписки
+// @Param id path int true "Идентификатор пользователя"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} GroupFollowsOkResponse "Сообщества"
+// @Failure 201 {object} ErrResponse "Ошибка при получении списка"
+// @Router /api/v1/users/{id}/following/groups [get]
+func (h *FollowHandler) FollowedGroups(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+	limit, offset := pagination(r)
+
+	groups, err := h.services.FollowedGroups(id, limit, offset)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := GroupFollowsOkResponse{Status: "ok", Groups: make([]GroupFollowResponse, 0, len(groups))}
+	for _, g := range groups {
+		response.Groups = append(response.Groups, GroupFollowResponse{
+			GroupId:   g.GroupID,
+			Name:      g.Name,
+			City:      g.City,
+			CreatedAt: g.CreatedAt,
+		})
+	}
+
+	render.JSON(w, r, response)
+}
+
+// Подписка на сообщество
+// @Summary Подписка на сообщество
+// @Description Новые мероприятия сообщества появятся в ленте подписок. Вступать в сообщество для этого не нужно.
+// @Tags Подписки
+// @Param id path int true "Идентификатор сообщества"
+// @Success 200 {object} StatusResponse "Подписка оформлена"
+// @Failure 201 {object} ErrResponse "Сообщество не найдено"
+// @Router /api/v1/groups/{id}/follow [post]
+func (h *FollowHandler) FollowGroup(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.FollowGroup(currentUserID(r), id); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Отписка от сообщества
+// @Summary Отписка от сообщества
+// @Tags Подписки
+// @Param id path int true "Идентификатор сообщества"
+// @Success 200 {object} StatusResponse "Подписка отменена"
+// @Failure 201 {object} ErrResponse "Подписки нет"
+// @Router /api/v1/groups/{id}/follow [delete]
+func (h *FollowHandler) UnfollowGroup(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.UnfollowGroup(currentUserID(r), id); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Подписчики сообщества
+// @Summary Подписчики сообщества, новые сначала
+// @Tags Подписки
+// @Param id path int true "Идентификатор сообщества"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} FollowsOkResponse "Подписчики"
+// @Failure 201 {object} ErrResponse "Ошибка при получении списка"
+// @Router /api/v1/groups/{id}/followers [get]
+func (h *FollowHandler) GroupFollowers(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+	limit, offset := pagination(r)
+
+	follows, err := h.services.GroupFollowers(id, limit, offset)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, newFollowsResponse(follows))
+}
+
+// Лента подписок
+// @Summary Действия тех, на кого подписан текущий пользователь, новые сначала
+// @Description kind: event_created (новое мероприятие), talk_accepted (доклад принят в программу),
+// @Description group_joined (вступление в сообщество). Из сообществ в подписках приходят только новые мероприятия.
+// @Tags Подписки
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Success 200 {object} ActivitiesOkResponse "Лента"
+// @Failure 201 {object} ErrResponse "Ошибка при получении ленты"
+// @Router /api/v1/activity [get]
+func (h *FollowHandler) Activities(w http.ResponseWriter, r *http.Request) {
+	limit, cursor, ok := cursorPagination(r)
+	if !ok {
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+		return
+	}
+
+	activities, err := h.services.Activities(currentUserID(r), models.ActivityFilter{Cursor: cursor, Limit: limit})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := ActivitiesOkResponse{Status: "ok", Activities: make([]ActivityResponse, 0, len(activities))}
+	for _, a := range activities {
+		response.Activities = append(response.Activities, ActivityResponse{
+			Id:            a.ID,
+			Kind:          a.Kind,
+			ActorId:       a.ActorID,
+			ActorName:     a.ActorName,
+			ActorUsername: a.ActorUsername,
+			GroupId:       a.GroupID,
+			GroupName:     a.GroupName,
+			EventId:       a.EventID,
+			EventTitle:    a.EventTitle,
+			TalkId:        a.TalkID,
+			TalkTitle:     a.TalkTitle,
+			CreatedAt:     a.CreatedAt,
+		})
+	}
+	if len(activities) > 0 {
+		response.NextCursor = nextCursor(activities[len(activities)-1].ID, len(activities), limit)
+	}
+
+	render.JSON(w, r, response)
+}
@@ -0,0 +1,176 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type GroupHandler struct {
+	services transport.GroupServiceInt
+	logger   *slog.Logger
+}
+
+func NewGroupHandler(serv transport.GroupServiceInt, logger *slog.Logger) *GroupHandler {
+	return &GroupHandler{services: serv, logger: logger}
+}
+
+type groupInput struct {
+	Name        string `json:"name" validate:"required,max=200" example:"Moscow Gophers"`
+	Description string `json:"description" validate:"max=5000" example:"Сообщество Go-разработчиков Москвы"`
+	City        string `json:"city" validate:"max=100" example:"Москва"`
+	// Private - вступить можно только по приглашению
+	Private bool `json:"private" example:"false"`
+}
+
+type GroupResponse struct {
+	Id          int       `json:"id" example:"3"`
+	OwnerId     int       `json:"owner_id" example:"123"`
+	Name        string    `json:"name" example:"Moscow Gophers"`
+	Description string    `json:"description" example:"Сообщество Go-разработчиков Москвы"`
+	City        string    `json:"city" example:"Москва"`
+	Private     bool      `json:"private" example:"false"`
+	CreatedAt   time.Time `json:"created_at" example:"2024-01-10T12:00:00+03:00"`
+}
+
+type GroupOkResponse struct {
+	Status string        `json:"status" example:"ok"`
+	Group  GroupResponse `json:"group"`
+}
+
+type GroupsOkResponse struct {
+	Status string          `json:"status" example:"ok"`
+	Groups []GroupResponse `json:"groups"`
+}
+
+func newGroupResponse(group models.Group) GroupResponse {
+	return GroupResponse{
+		Id:          group.ID,
+		OwnerId:     group.OwnerID,
+		Name:        group.Name,
+		Description: group.Description,
+		City:        group.City,
+		Private:     group.Private,
+		CreatedAt:   group.CreatedAt,
+	}
+}
+
+// Создание сообщества
+// @Summary Создание сообщества
+// @Tags Сообщества
+// @Param Request body groupInput true "Сообщество"
+// @Success 200 {object} IdResponse "Сообщество создано"
+// @Failure 201 {object} ErrResponse "Ошибка при создании сообщества"
+// @Router /api/v1/groups [post]
+func (h *GroupHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	var input groupInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	id, err := h.services.CreateGroup(models.Group{
+		OwnerID:     currentUserID(r),
+		Name:        input.Name,
+		Description: input.Description,
+		City:        input.City,
+		Private:     input.Private,
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, IdResponse{Status: "ok", Id: id})
+}
+
+// Сообщество
+// @Summary Сообщество по идентификатору
+// @Tags Сообщества
+// @Param id path int true "Идентификатор сообщества"
+// @Success 200 {object} GroupOkResponse "Сообщество"
+// @Failure 201 {object} ErrResponse "Сообщество не найдено"
+// @Router /api/v1/groups/{id} [get]
+func (h *GroupHandler) Group(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	group, err := h.services.Group(id)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, GroupOkResponse{Status: "ok", Group: newGroupResponse(group)})
+}
+
+// Список сообществ
+// @Summary Список сообществ
+// @Tags Сообщества
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} GroupsOkResponse "Сообщества"
+// @Failure 201 {object} ErrResponse "Внутренняя ошибка сервиса"
+// @Router /api/v1/groups [get]
+func (h *GroupHandler) Groups(w http.ResponseWriter, r *http.Request) {
+	limit, offset := pagination(r)
+
+	groups, err := h.services.Groups(limit, offset)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := GroupsOkResponse{Status: "ok", Groups: make([]GroupResponse, 0, len(groups))}
+	for _, group := range groups {
+		response.Groups = append(response.Groups, newGroupResponse(group))
+	}
+
+	render.JSON(w, r, response)
+}
+
+// Вступление в сообщество
+// @Summary Вступление текущего пользователя в сообщество
+// @Tags Сообщества
+// @Param id path int true "Идентификатор сообщества"
+// @Success 200 {object} StatusResponse "Пользователь вступил в сообщество"
+// @Failure 201 {object} ErrResponse "Ошибка при вступлении в сообщество"
+// @Router /api/v1/groups/{id}/members [post]
+func (h *GroupHandler) JoinGroup(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.JoinGroup(currentUserID(r), id); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Выход из сообщества
+// @Summary Выход текущего пользователя из сообщества
+// @Tags Сообщества
+// @Param id path int true "Идентификатор сообщества"
+// @Success 200 {object} StatusResponse "Пользователь вышел из сообщества"
+// @Failure 201 {object} ErrResponse "Ошибка при выходе из сообщества"
+// @Router /api/v1/groups/{id}/members [delete]
+func (h *GroupHandler) LeaveGroup(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.LeaveGroup(currentUserID(r), id); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
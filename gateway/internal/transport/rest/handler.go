@@ -13,22 +13,276 @@ import (
 type AuthorizationHandlerInt interface {
 	signUp(w http.ResponseWriter, r *http.Request)
 	signIn(w http.ResponseWriter, r *http.Request)
+	changePassword(w http.ResponseWriter, r *http.Request)
 	userIdentity(next http.Handler) http.Handler
 }
 
 type ProfileHandlerInt interface {
 	PersonalProfile(w http.ResponseWriter, r *http.Request)
+	UpdatePersonalProfile(w http.ResponseWriter, r *http.Request)
+}
+
+type AccountHandlerInt interface {
+	RequestEmailChange(w http.ResponseWriter, r *http.Request)
+	ConfirmEmailChange(w http.ResponseWriter, r *http.Request)
+	DeleteAccount(w http.ResponseWriter, r *http.Request)
+	RequestDataExport(w http.ResponseWriter, r *http.Request)
+	DataExport(w http.ResponseWriter, r *http.Request)
+	DownloadDataExport(w http.ResponseWriter, r *http.Request)
+}
+
+type EventHandlerInt interface {
+	CreateEvent(w http.ResponseWriter, r *http.Request)
+	Event(w http.ResponseWriter, r *http.Request)
+	Events(w http.ResponseWriter, r *http.Request)
+	EventICS(w http.ResponseWriter, r *http.Request)
+	CancelEvent(w http.ResponseWriter, r *http.Request)
+}
+
+type CFPHandlerInt interface {
+	OpenCFP(w http.ResponseWriter, r *http.Request)
+	CFP(w http.ResponseWriter, r *http.Request)
+	AddReviewer(w http.ResponseWriter, r *http.Request)
+	SubmitTalk(w http.ResponseWriter, r *http.Request)
+	Talks(w http.ResponseWriter, r *http.Request)
+	ReviewTalk(w http.ResponseWriter, r *http.Request)
+	Reviews(w http.ResponseWriter, r *http.Request)
+	AcceptTalk(w http.ResponseWriter, r *http.Request)
+	RejectTalk(w http.ResponseWriter, r *http.Request)
+}
+
+type AgendaHandlerInt interface {
+	Agenda(w http.ResponseWriter, r *http.Request)
+	AddSlot(w http.ResponseWriter, r *http.Request)
+	UpdateSlot(w http.ResponseWriter, r *http.Request)
+	DeleteSlot(w http.ResponseWriter, r *http.Request)
+	CreateSpeaker(w http.ResponseWriter, r *http.Request)
+	Speaker(w http.ResponseWriter, r *http.Request)
+	UpdateSpeaker(w http.ResponseWriter, r *http.Request)
+}
+
+type VenueHandlerInt interface {
+	CreateVenue(w http.ResponseWriter, r *http.Request)
+	UpdateVenue(w http.ResponseWriter, r *http.Request)
+	Venue(w http.ResponseWriter, r *http.Request)
+	Venues(w http.ResponseWriter, r *http.Request)
+}
+
+type GroupHandlerInt interface {
+	CreateGroup(w http.ResponseWriter, r *http.Request)
+	Group(w http.ResponseWriter, r *http.Request)
+	Groups(w http.ResponseWriter, r *http.Request)
+	JoinGroup(w http.ResponseWriter, r *http.Request)
+	LeaveGroup(w http.ResponseWriter, r *http.Request)
+}
+
+type InvitationHandlerInt interface {
+	InviteToGroup(w http.ResponseWriter, r *http.Request)
+	GroupInvitations(w http.ResponseWriter, r *http.Request)
+	CreateGroupInviteLink(w http.ResponseWriter, r *http.Request)
+	InviteToEvent(w http.ResponseWriter, r *http.Request)
+	EventInvitations(w http.ResponseWriter, r *http.Request)
+	CreateEventInviteLink(w http.ResponseWriter, r *http.Request)
+	Invitations(w http.ResponseWriter, r *http.Request)
+	RevokeInvitation(w http.ResponseWriter, r *http.Request)
+	InvitePreview(w http.ResponseWriter, r *http.Request)
+	AcceptInvitation(w http.ResponseWriter, r *http.Request)
+}
+
+type OrderHandlerInt interface {
+	CreateTicketType(w http.ResponseWriter, r *http.Request)
+	TicketTypes(w http.ResponseWriter, r *http.Request)
+	CreateOrder(w http.ResponseWriter, r *http.Request)
+	EventOrders(w http.ResponseWriter, r *http.Request)
+	Orders(w http.ResponseWriter, r *http.Request)
+	Order(w http.ResponseWriter, r *http.Request)
+	RefundOrder(w http.ResponseWriter, r *http.Request)
+	PaymentWebhook(w http.ResponseWriter, r *http.Request)
+}
+
+type RSVPHandlerInt interface {
+	RSVP(w http.ResponseWriter, r *http.Request)
+	CancelRSVP(w http.ResponseWriter, r *http.Request)
+	Ticket(w http.ResponseWriter, r *http.Request)
+	TicketQR(w http.ResponseWriter, r *http.Request)
+	CheckIn(w http.ResponseWriter, r *http.Request)
+	Attendance(w http.ResponseWriter, r *http.Request)
+	TicketPublicKey(w http.ResponseWriter, r *http.Request)
+}
+
+type FeedbackHandlerInt interface {
+	LeaveEventFeedback(w http.ResponseWriter, r *http.Request)
+	LeaveTalkFeedback(w http.ResponseWriter, r *http.Request)
+	EventFeedback(w http.ResponseWriter, r *http.Request)
+	TalkFeedback(w http.ResponseWriter, r *http.Request)
+}
+
+type CommentHandlerInt interface {
+	EventComments(w http.ResponseWriter, r *http.Request)
+	CreateEventComment(w http.ResponseWriter, r *http.Request)
+	GroupComments(w http.ResponseWriter, r *http.Request)
+	CreateGroupComment(w http.ResponseWriter, r *http.Request)
+	UpdateComment(w http.ResponseWriter, r *http.Request)
+	DeleteComment(w http.ResponseWriter, r *http.Request)
+	CommentHistory(w http.ResponseWriter, r *http.Request)
+}
+
+type NotificationHandlerInt interface {
+	Notifications(w http.ResponseWriter, r *http.Request)
+	MarkRead(w http.ResponseWriter, r *http.Request)
+	MarkAllRead(w http.ResponseWriter, r *http.Request)
+	NotificationPreferences(w http.ResponseWriter, r *http.Request)
+	UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request)
+}
+
+type StreamHandlerInt interface {
+	Stream(w http.ResponseWriter, r *http.Request)
+}
+
+type WebhookHandlerInt interface {
+	CreateWebhook(w http.ResponseWriter, r *http.Request)
+	Webhooks(w http.ResponseWriter, r *http.Request)
+	UpdateWebhook(w http.ResponseWriter, r *http.Request)
+	DeleteWebhook(w http.ResponseWriter, r *http.Request)
+	WebhookDeliveries(w http.ResponseWriter, r *http.Request)
+}
+
+type MessageHandlerInt interface {
+	CreateConversation(w http.ResponseWriter, r *http.Request)
+	Conversations(w http.ResponseWriter, r *http.Request)
+	Conversation(w http.ResponseWriter, r *http.Request)
+	Messages(w http.ResponseWriter, r *http.Request)
+	SendMessage(w http.ResponseWriter, r *http.Request)
+	ReadConversation(w http.ResponseWriter, r *http.Request)
+	BlockUser(w http.ResponseWriter, r *http.Request)
+	UnblockUser(w http.ResponseWriter, r *http.Request)
+	Blocks(w http.ResponseWriter, r *http.Request)
+}
+
+type MatchHandlerInt interface {
+	MatchSettings(w http.ResponseWriter, r *http.Request)
+	UpdateMatchSettings(w http.ResponseWriter, r *http.Request)
+	DisableMatching(w http.ResponseWriter, r *http.Request)
+	Matches(w http.ResponseWriter, r *http.Request)
+	RequestMatch(w http.ResponseWriter, r *http.Request)
+	MatchRequests(w http.ResponseWriter, r *http.Request)
+	AcceptMatchRequest(w http.ResponseWriter, r *http.Request)
+	DeclineMatchRequest(w http.ResponseWriter, r *http.Request)
+}
+
+type FollowHandlerInt interface {
+	FollowUser(w http.ResponseWriter, r *http.Request)
+	UnfollowUser(w http.ResponseWriter, r *http.Request)
+	Followers(w http.ResponseWriter, r *http.Request)
+	Following(w http.ResponseWriter, r *http.Request)
+	FollowedGroups(w http.ResponseWriter, r *http.Request)
+	FollowGroup(w http.ResponseWriter, r *http.Request)
+	UnfollowGroup(w http.ResponseWriter, r *http.Request)
+	GroupFollowers(w http.ResponseWriter, r *http.Request)
+	Activities(w http.ResponseWriter, r *http.Request)
+}
+
+type ModerationHandlerInt interface {
+	Report(w http.ResponseWriter, r *http.Request)
+	Reports(w http.ResponseWriter, r *http.Request)
+	ModerationReport(w http.ResponseWriter, r *http.Request)
+	ResolveReport(w http.ResponseWriter, r *http.Request)
+	ModerationLog(w http.ResponseWriter, r *http.Request)
+}
+
+type AdminHandlerInt interface {
+	AdminUsers(w http.ResponseWriter, r *http.Request)
+	AdminUser(w http.ResponseWriter, r *http.Request)
+	SetUserRole(w http.ResponseWriter, r *http.Request)
+	SuspendUser(w http.ResponseWriter, r *http.Request)
+	UnsuspendUser(w http.ResponseWriter, r *http.Request)
+	ForceLogout(w http.ResponseWriter, r *http.Request)
+	MergeUsers(w http.ResponseWriter, r *http.Request)
+}
+
+type AuditHandlerInt interface {
+	AuditEvents(w http.ResponseWriter, r *http.Request)
+}
+
+type FeedHandlerInt interface {
+	Feed(w http.ResponseWriter, r *http.Request)
+	DismissFeedEvent(w http.ResponseWriter, r *http.Request)
+	UndismissFeedEvent(w http.ResponseWriter, r *http.Request)
+}
+
+type TelegramHandlerInt interface {
+	CreateTelegramLinkCode(w http.ResponseWriter, r *http.Request)
+	UnlinkTelegram(w http.ResponseWriter, r *http.Request)
+	TelegramWebhook(w http.ResponseWriter, r *http.Request)
+}
+
+type JobHandlerInt interface {
+	Jobs(w http.ResponseWriter, r *http.Request)
+	Job(w http.ResponseWriter, r *http.Request)
+	RetryJob(w http.ResponseWriter, r *http.Request)
+}
+
+type SearchHandlerInt interface {
+	Search(w http.ResponseWriter, r *http.Request)
 }
 
 type Handler struct {
 	AuthorizationHandlerInt
 	ProfileHandlerInt
+	AccountHandlerInt
+	EventHandlerInt
+	CFPHandlerInt
+	AgendaHandlerInt
+	VenueHandlerInt
+	GroupHandlerInt
+	InvitationHandlerInt
+	OrderHandlerInt
+	RSVPHandlerInt
+	FeedbackHandlerInt
+	CommentHandlerInt
+	NotificationHandlerInt
+	StreamHandlerInt
+	WebhookHandlerInt
+	TelegramHandlerInt
+	MessageHandlerInt
+	MatchHandlerInt
+	FeedHandlerInt
+	FollowHandlerInt
+	ModerationHandlerInt
+	AdminHandlerInt
+	AuditHandlerInt
+	JobHandlerInt
+	SearchHandlerInt
 }
 
 func NewHandler(services *service.Service, logger *slog.Logger) *Handler {
 	return &Handler{
 		AuthorizationHandlerInt: NewAuthHandler(services.AuthService, logger),
 		ProfileHandlerInt:       NewProfileHandler(services.UserService, logger),
+		AccountHandlerInt:       NewAccountHandler(services.AccountService, logger),
+		EventHandlerInt:         NewEventHandler(services.EventService, logger),
+		CFPHandlerInt:           NewCFPHandler(services.CFPService, logger),
+		AgendaHandlerInt:        NewAgendaHandler(services.AgendaService, logger),
+		VenueHandlerInt:         NewVenueHandler(services.VenueService, logger),
+		GroupHandlerInt:         NewGroupHandler(services.GroupService, logger),
+		InvitationHandlerInt:    NewInvitationHandler(services.InvitationService, logger),
+		OrderHandlerInt:         NewOrderHandler(services.OrderService, logger),
+		RSVPHandlerInt:          NewRSVPHandler(services.RSVPService, logger),
+		FeedbackHandlerInt:      NewFeedbackHandler(services.FeedbackService, logger),
+		CommentHandlerInt:       NewCommentHandler(services.CommentService, logger),
+		NotificationHandlerInt:  NewNotificationHandler(services.NotificationService, logger),
+		StreamHandlerInt:        NewStreamHandler(services.StreamService, logger),
+		WebhookHandlerInt:       NewWebhookHandler(services.WebhookService, logger),
+		TelegramHandlerInt:      NewTelegramHandler(services.TelegramService, logger),
+		MessageHandlerInt:       NewMessageHandler(services.MessageService, logger),
+		MatchHandlerInt:         NewMatchHandler(services.MatchService, logger),
+		FeedHandlerInt:          NewFeedHandler(services.FeedService, logger),
+		FollowHandlerInt:        NewFollowHandler(services.FollowService, logger),
+		ModerationHandlerInt:    NewModerationHandler(services.ModerationService, logger),
+		AdminHandlerInt:         NewAdminHandler(services.AdminService, logger),
+		AuditHandlerInt:         NewAuditHandler(services.AuditService, logger),
+		JobHandlerInt:           NewJobHandler(services.JobService, logger),
+		SearchHandlerInt:        NewSearchHandler(services.SearchService, logger),
 	}
 }
 
@@ -36,6 +290,7 @@ func (h *Handler) InitRoutes() *chi.Mux {
 	router := chi.NewRouter()
 	router.Use(middleware.RequestID)
 	router.Use(middleware.RealIP)
+	router.Use(requestMeta)
 	router.Use(middleware.Logger)
 
 	router.HandleFunc("/swagger", func(w http.ResponseWriter, r *http.Request) {
@@ -55,6 +310,241 @@ func (h *Handler) InitRoutes() *chi.Mux {
 			r.Route("/personal-profile", func(r chi.Router) {
 				r.Use(h.AuthorizationHandlerInt.userIdentity)
 				r.Get("/", h.ProfileHandlerInt.PersonalProfile)
+				r.Put("/", h.ProfileHandlerInt.UpdatePersonalProfile)
+				r.Put("/password", h.AuthorizationHandlerInt.changePassword)
+				r.Post("/email", h.AccountHandlerInt.RequestEmailChange)
+				r.Delete("/", h.AccountHandlerInt.DeleteAccount)
+				r.Post("/export", h.AccountHandlerInt.RequestDataExport)
+				r.Get("/export", h.AccountHandlerInt.DataExport)
+			})
+
+			r.Post("/email-changes/confirm", h.AccountHandlerInt.ConfirmEmailChange)
+			r.Get("/data-exports/{token}", h.AccountHandlerInt.DownloadDataExport)
+
+			r.Route("/events", func(r chi.Router) {
+				r.Get("/", h.EventHandlerInt.Events)
+				r.Get("/{id}", h.EventHandlerInt.Event)
+				r.Get("/{id}/ics", h.EventHandlerInt.EventICS)
+				r.Get("/{id}/cfp", h.CFPHandlerInt.CFP)
+				r.Get("/{id}/agenda", h.AgendaHandlerInt.Agenda)
+				r.Get("/{id}/comments", h.CommentHandlerInt.EventComments)
+				r.Get("/{id}/ticket-types", h.OrderHandlerInt.TicketTypes)
+
+				r.Group(func(r chi.Router) {
+					r.Use(h.AuthorizationHandlerInt.userIdentity)
+					r.Post("/", h.EventHandlerInt.CreateEvent)
+					r.Post("/{id}/cancel", h.EventHandlerInt.CancelEvent)
+					r.Post("/{id}/cfp", h.CFPHandlerInt.OpenCFP)
+					r.Post("/{id}/cfp/reviewers", h.CFPHandlerInt.AddReviewer)
+					r.Post("/{id}/talks", h.CFPHandlerInt.SubmitTalk)
+					r.Get("/{id}/talks", h.CFPHandlerInt.Talks)
+					r.Post("/{id}/agenda", h.AgendaHandlerInt.AddSlot)
+					r.Put("/{id}/agenda/{slotId}", h.AgendaHandlerInt.UpdateSlot)
+					r.Delete("/{id}/agenda/{slotId}", h.AgendaHandlerInt.DeleteSlot)
+					r.Post("/{id}/rsvp", h.RSVPHandlerInt.RSVP)
+					r.Delete("/{id}/rsvp", h.RSVPHandlerInt.CancelRSVP)
+					r.Get("/{id}/ticket", h.RSVPHandlerInt.Ticket)
+					r.Get("/{id}/ticket.png", h.RSVPHandlerInt.TicketQR)
+					r.Post("/{id}/check-in", h.RSVPHandlerInt.CheckIn)
+					r.Get("/{id}/attendance", h.RSVPHandlerInt.Attendance)
+					r.Post("/{id}/feedback", h.FeedbackHandlerInt.LeaveEventFeedback)
+					r.Get("/{id}/feedback", h.FeedbackHandlerInt.EventFeedback)
+					r.Post("/{id}/agenda/{slotId}/feedback", h.FeedbackHandlerInt.LeaveTalkFeedback)
+					r.Get("/{id}/agenda/{slotId}/feedback", h.FeedbackHandlerInt.TalkFeedback)
+					r.Post("/{id}/comments", h.CommentHandlerInt.CreateEventComment)
+					r.Post("/{id}/invitations", h.InvitationHandlerInt.InviteToEvent)
+					r.Get("/{id}/invitations", h.InvitationHandlerInt.EventInvitations)
+					r.Post("/{id}/invite-links", h.InvitationHandlerInt.CreateEventInviteLink)
+					r.Post("/{id}/ticket-types", h.OrderHandlerInt.CreateTicketType)
+					r.Post("/{id}/orders", h.OrderHandlerInt.CreateOrder)
+					r.Get("/{id}/orders", h.OrderHandlerInt.EventOrders)
+				})
+			})
+
+			r.Route("/orders", func(r chi.Router) {
+				r.Use(h.AuthorizationHandlerInt.userIdentity)
+				r.Get("/", h.OrderHandlerInt.Orders)
+				r.Get("/{id}", h.OrderHandlerInt.Order)
+				r.Post("/{id}/refund", h.OrderHandlerInt.RefundOrder)
+			})
+
+			r.Post("/payments/webhook", h.OrderHandlerInt.PaymentWebhook)
+
+			r.Get("/search", h.SearchHandlerInt.Search)
+			r.Get("/tickets/public-key", h.RSVPHandlerInt.TicketPublicKey)
+
+			r.Route("/groups", func(r chi.Router) {
+				r.Get("/", h.GroupHandlerInt.Groups)
+				r.Get("/{id}", h.GroupHandlerInt.Group)
+				r.Get("/{id}/comments", h.CommentHandlerInt.GroupComments)
+				r.Get("/{id}/followers", h.FollowHandlerInt.GroupFollowers)
+
+				r.Group(func(r chi.Router) {
+					r.Use(h.AuthorizationHandlerInt.userIdentity)
+					r.Post("/", h.GroupHandlerInt.CreateGroup)
+					r.Post("/{id}/members", h.GroupHandlerInt.JoinGroup)
+					r.Delete("/{id}/members", h.GroupHandlerInt.LeaveGroup)
+					r.Post("/{id}/follow", h.FollowHandlerInt.FollowGroup)
+					r.Delete("/{id}/follow", h.FollowHandlerInt.UnfollowGroup)
+					r.Post("/{id}/comments", h.CommentHandlerInt.CreateGroupComment)
+					r.Post("/{id}/webhooks", h.WebhookHandlerInt.CreateWebhook)
+					r.Get("/{id}/webhooks", h.WebhookHandlerInt.Webhooks)
+					r.Post("/{id}/invitations", h.InvitationHandlerInt.InviteToGroup)
+					r.Get("/{id}/invitations", h.InvitationHandlerInt.GroupInvitations)
+					r.Post("/{id}/invite-links", h.InvitationHandlerInt.CreateGroupInviteLink)
+				})
+			})
+
+			r.Route("/invitations", func(r chi.Router) {
+				r.Use(h.AuthorizationHandlerInt.userIdentity)
+				r.Get("/", h.InvitationHandlerInt.Invitations)
+				r.Delete("/{id}", h.InvitationHandlerInt.RevokeInvitation)
+			})
+
+			r.Route("/invites/{token}", func(r chi.Router) {
+				r.Get("/", h.InvitationHandlerInt.InvitePreview)
+				r.With(h.AuthorizationHandlerInt.userIdentity).Post("/accept", h.InvitationHandlerInt.AcceptInvitation)
+			})
+
+			r.Route("/users/{id}", func(r chi.Router) {
+				r.Get("/followers", h.FollowHandlerInt.Followers)
+				r.Get("/following", h.FollowHandlerInt.Following)
+				r.Get("/following/groups", h.FollowHandlerInt.FollowedGroups)
+
+				r.Group(func(r chi.Router) {
+					r.Use(h.AuthorizationHandlerInt.userIdentity)
+					r.Post("/follow", h.FollowHandlerInt.FollowUser)
+					r.Delete("/follow", h.FollowHandlerInt.UnfollowUser)
+				})
+			})
+
+			r.With(h.AuthorizationHandlerInt.userIdentity).Get("/activity", h.FollowHandlerInt.Activities)
+
+			r.Route("/venues", func(r chi.Router) {
+				r.Get("/", h.VenueHandlerInt.Venues)
+				r.Get("/{id}", h.VenueHandlerInt.Venue)
+
+				r.Group(func(r chi.Router) {
+					r.Use(h.AuthorizationHandlerInt.userIdentity)
+					r.Post("/", h.VenueHandlerInt.CreateVenue)
+					r.Put("/{id}", h.VenueHandlerInt.UpdateVenue)
+				})
+			})
+
+			r.Route("/speakers", func(r chi.Router) {
+				r.Get("/{id}", h.AgendaHandlerInt.Speaker)
+
+				r.Group(func(r chi.Router) {
+					r.Use(h.AuthorizationHandlerInt.userIdentity)
+					r.Post("/", h.AgendaHandlerInt.CreateSpeaker)
+					r.Put("/{id}", h.AgendaHandlerInt.UpdateSpeaker)
+				})
+			})
+
+			r.With(tokenFromQuery, h.AuthorizationHandlerInt.userIdentity).Get("/stream", h.StreamHandlerInt.Stream)
+
+			r.Route("/notifications", func(r chi.Router) {
+				r.Use(h.AuthorizationHandlerInt.userIdentity)
+				r.Get("/", h.NotificationHandlerInt.Notifications)
+				r.Post("/read-all", h.NotificationHandlerInt.MarkAllRead)
+				r.Get("/preferences", h.NotificationHandlerInt.NotificationPreferences)
+				r.Put("/preferences", h.NotificationHandlerInt.UpdateNotificationPreferences)
+				r.Post("/{id}/read", h.NotificationHandlerInt.MarkRead)
+			})
+
+			r.Route("/comments/{id}", func(r chi.Router) {
+				r.Use(h.AuthorizationHandlerInt.userIdentity)
+				r.Put("/", h.CommentHandlerInt.UpdateComment)
+				r.Delete("/", h.CommentHandlerInt.DeleteComment)
+				r.Get("/history", h.CommentHandlerInt.CommentHistory)
+			})
+
+			r.Route("/webhooks/{id}", func(r chi.Router) {
+				r.Use(h.AuthorizationHandlerInt.userIdentity)
+				r.Put("/", h.WebhookHandlerInt.UpdateWebhook)
+				r.Delete("/", h.WebhookHandlerInt.DeleteWebhook)
+				r.Get("/deliveries", h.WebhookHandlerInt.WebhookDeliveries)
+			})
+
+			r.Route("/conversations", func(r chi.Router) {
+				r.Use(h.AuthorizationHandlerInt.userIdentity)
+				r.Post("/", h.MessageHandlerInt.CreateConversation)
+				r.Get("/", h.MessageHandlerInt.Conversations)
+				r.Get("/{id}", h.MessageHandlerInt.Conversation)
+				r.Get("/{id}/messages", h.MessageHandlerInt.Messages)
+				r.Post("/{id}/messages", h.MessageHandlerInt.SendMessage)
+				r.Post("/{id}/read", h.MessageHandlerInt.ReadConversation)
+			})
+
+			r.Route("/blocks", func(r chi.Router) {
+				r.Use(h.AuthorizationHandlerInt.userIdentity)
+				r.Get("/", h.MessageHandlerInt.Blocks)
+				r.Post("/{id}", h.MessageHandlerInt.BlockUser)
+				r.Delete("/{id}", h.MessageHandlerInt.UnblockUser)
+			})
+
+			r.Route("/matches", func(r chi.Router) {
+				r.Use(h.AuthorizationHandlerInt.userIdentity)
+				r.Get("/", h.MatchHandlerInt.Matches)
+				r.Get("/settings", h.MatchHandlerInt.MatchSettings)
+				r.Put("/settings", h.MatchHandlerInt.UpdateMatchSettings)
+				r.Delete("/settings", h.MatchHandlerInt.DisableMatching)
+				r.Post("/requests", h.MatchHandlerInt.RequestMatch)
+				r.Get("/requests", h.MatchHandlerInt.MatchRequests)
+				r.Post("/requests/{id}/accept", h.MatchHandlerInt.AcceptMatchRequest)
+				r.Post("/requests/{id}/decline", h.MatchHandlerInt.DeclineMatchRequest)
+			})
+
+			r.Route("/feed", func(r chi.Router) {
+				r.Use(h.AuthorizationHandlerInt.userIdentity)
+				r.Get("/", h.FeedHandlerInt.Feed)
+				r.Post("/{id}/not-interested", h.FeedHandlerInt.DismissFeedEvent)
+				r.Delete("/{id}/not-interested", h.FeedHandlerInt.UndismissFeedEvent)
+			})
+
+			r.Route("/telegram", func(r chi.Router) {
+				r.Post("/webhook", h.TelegramHandlerInt.TelegramWebhook)
+
+				r.Group(func(r chi.Router) {
+					r.Use(h.AuthorizationHandlerInt.userIdentity)
+					r.Post("/link-code", h.TelegramHandlerInt.CreateTelegramLinkCode)
+					r.Delete("/link", h.TelegramHandlerInt.UnlinkTelegram)
+				})
+			})
+
+			r.With(h.AuthorizationHandlerInt.userIdentity).Post("/reports", h.ModerationHandlerInt.Report)
+
+			r.Route("/moderation", func(r chi.Router) {
+				r.Use(h.AuthorizationHandlerInt.userIdentity)
+				r.Get("/reports", h.ModerationHandlerInt.Reports)
+				r.Get("/reports/{id}", h.ModerationHandlerInt.ModerationReport)
+				r.Post("/reports/{id}/resolve", h.ModerationHandlerInt.ResolveReport)
+				r.Get("/log", h.ModerationHandlerInt.ModerationLog)
+			})
+
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(h.AuthorizationHandlerInt.userIdentity)
+				r.Get("/jobs", h.JobHandlerInt.Jobs)
+				r.Get("/jobs/{id}", h.JobHandlerInt.Job)
+				r.Post("/jobs/{id}/retry", h.JobHandlerInt.RetryJob)
+
+				r.Get("/users", h.AdminHandlerInt.AdminUsers)
+				r.Get("/users/{id}", h.AdminHandlerInt.AdminUser)
+				r.Put("/users/{id}/role", h.AdminHandlerInt.SetUserRole)
+				r.Post("/users/{id}/suspend", h.AdminHandlerInt.SuspendUser)
+				r.Post("/users/{id}/unsuspend", h.AdminHandlerInt.UnsuspendUser)
+				r.Post("/users/{id}/logout", h.AdminHandlerInt.ForceLogout)
+				r.Post("/users/{id}/merge", h.AdminHandlerInt.MergeUsers)
+
+				r.Get("/audit", h.AuditHandlerInt.AuditEvents)
+			})
+
+			r.Route("/talks/{id}", func(r chi.Router) {
+				r.Use(h.AuthorizationHandlerInt.userIdentity)
+				r.Post("/reviews", h.CFPHandlerInt.ReviewTalk)
+				r.Get("/reviews", h.CFPHandlerInt.Reviews)
+				r.Post("/accept", h.CFPHandlerInt.AcceptTalk)
+				r.Post("/reject", h.CFPHandlerInt.RejectTalk)
 			})
 		})
 	})
@@ -0,0 +1,325 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const invitePath = "/api/v1/invites/"
+
+type InvitationHandler struct {
+	services transport.InvitationServiceInt
+	logger   *slog.Logger
+}
+
+func NewInvitationHandler(serv transport.InvitationServiceInt, logger *slog.Logger) *InvitationHandler {
+	return &InvitationHandler{services: serv, logger: logger}
+}
+
+type inviteInput struct {
+	Username string `json:"username" validate:"required_without=Email,excluded_with=Email,max=50" example:"gopher"`
+	Email    string `json:"email" validate:"omitempty,email,max=254" example:"friend@gmail.com"`
+}
+
+type inviteLinkInput struct {
+	// MaxUses - сколько раз можно воспользоваться ссылкой, не указано - без ограничения
+	MaxUses *int `json:"max_uses" validate:"omitempty,min=1,max=10000" example:"50"`
+	// ExpiresAt - до какого момента действует ссылка, не указано - бессрочно
+	ExpiresAt *time.Time `json:"expires_at" example:"2024-03-01T00:00:00+03:00"`
+}
+
+type InvitationResponse struct {
+	Id           int        `json:"id" example:"12"`
+	SubjectType  string     `json:"subject_type" example:"group"`
+	SubjectId    int        `json:"subject_id" example:"3"`
+	SubjectTitle string     `json:"subject_title" example:"Moscow Gophers"`
+	InviterId    int        `json:"inviter_id" example:"123"`
+	InviteeId    *int       `json:"invitee_id,omitempty" example:"456"`
+	Email        string     `json:"email,omitempty" example:"friend@gmail.com"`
+	Token        string     `json:"token" example:"9f2c4e..."`
+	InviteUrl    string     `json:"invite_url" example:"/api/v1/invites/9f2c4e..."`
+	MaxUses      *int       `json:"max_uses,omitempty" example:"50"`
+	Uses         int        `json:"uses" example:"7"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty" example:"2024-03-01T00:00:00+03:00"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty" example:"2024-02-25T10:00:00+03:00"`
+	CreatedAt    time.Time  `json:"created_at" example:"2024-02-20T13:00:00+03:00"`
+}
+
+type InvitationOkResponse struct {
+	Status     string             `json:"status" example:"ok"`
+	Invitation InvitationResponse `json:"invitation"`
+}
+
+type InvitationsOkResponse struct {
+	Status      string               `json:"status" example:"ok"`
+	Invitations []InvitationResponse `json:"invitations"`
+}
+
+// InvitePreviewResponse - то, что видно по ссылке-приглашению до входа
+type InvitePreviewResponse struct {
+	SubjectType  string     `json:"subject_type" example:"group"`
+	SubjectId    int        `json:"subject_id" example:"3"`
+	SubjectTitle string     `json:"subject_title" example:"Moscow Gophers"`
+	Personal     bool       `json:"personal" example:"false"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty" example:"2024-03-01T00:00:00+03:00"`
+}
+
+type InvitePreviewOkResponse struct {
+	Status string                `json:"status" example:"ok"`
+	Invite InvitePreviewResponse `json:"invite"`
+}
+
+func newInvitationResponse(invitation models.Invitation) InvitationResponse {
+	return InvitationResponse{
+		Id:           invitation.ID,
+		SubjectType:  invitation.SubjectType,
+		SubjectId:    invitation.SubjectID,
+		SubjectTitle: invitation.SubjectTitle,
+		InviterId:    invitation.InviterID,
+		InviteeId:    invitation.InviteeID,
+		Email:        invitation.Email,
+		Token:        invitation.Token,
+		InviteUrl:    invitePath + invitation.Token,
+		MaxUses:      invitation.MaxUses,
+		Uses:         invitation.Uses,
+		ExpiresAt:    invitation.ExpiresAt,
+		RevokedAt:    invitation.RevokedAt,
+		CreatedAt:    invitation.CreatedAt,
+	}
+}
+
+func newInvitationsResponse(invitations []models.Invitation) InvitationsOkResponse {
+	response := InvitationsOkResponse{Status: "ok", Invitations: make([]InvitationResponse, 0, len(invitations))}
+	for _, invitation := range invitations {
+		response.Invitations = append(response.Invitations, newInvitationResponse(invitation))
+	}
+
+	return response
+}
+
+// Приглашение в сообщество
+// @Summary Личное приглашение в сообщество
+// @Description Только для владельца сообщества. Нужно указать либо username, либо email. Пользователь
+// @Description получает уведомление, а на почту приходит код, который принимается при регистрации или после входа.
+// @Tags Приглашения
+// @Param id path int true "Идентификатор сообщества"
+// @Param Request body inviteInput true "Кого пригласить"
+// @Success 200 {object} InvitationOkResponse "Приглашение отправлено"
+// @Failure 201 {object} ErrResponse "Ошибка при отправке приглашения"
+// @Router /api/v1/groups/{id}/invitations [post]
+func (h *InvitationHandler) InviteToGroup(w http.ResponseWriter, r *http.Request) {
+	h.invite(w, r, models.InvitationSubjectGroup)
+}
+
+// Приглашения в сообщество
+// @Summary Приглашения и ссылки-приглашения в сообщество
+// @Description Только для владельца сообщества.
+// @Tags Приглашения
+// @Param id path int true "Идентификатор сообщества"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} InvitationsOkResponse "Приглашения, новые первыми"
+// @Failure 201 {object} ErrResponse "Ошибка при получении приглашений"
+// @Router /api/v1/groups/{id}/invitations [get]
+func (h *InvitationHandler) GroupInvitations(w http.ResponseWriter, r *http.Request) {
+	h.subjectInvitations(w, r, models.InvitationSubjectGroup)
+}
+
+// Ссылка-приглашение в сообщество
+// @Summary Создание ссылки-приглашения в сообщество
+// @Description Только для владельца сообщества. Ссылкой может воспользоваться любой, пока она не истекла
+// @Description и не исчерпано число использований.
+// @Tags Приглашения
+// @Param id path int true "Идентификатор сообщества"
+// @Param Request body inviteLinkInput true "Ограничения ссылки"
+// @Success 200 {object} InvitationOkResponse "Ссылка создана"
+// @Failure 201 {object} ErrResponse "Ошибка при создании ссылки"
+// @Router /api/v1/groups/{id}/invite-links [post]
+func (h *InvitationHandler) CreateGroupInviteLink(w http.ResponseWriter, r *http.Request) {
+	h.createInviteLink(w, r, models.InvitationSubjectGroup)
+}
+
+// Приглашение на мероприятие
+// @Summary Личное приглашение на мероприятие
+// @Description Только для организатора. Нужно указать либо username, либо email. Пользователь
+// @Description получает уведомление, а на почту приходит код, который принимается при регистрации или после входа.
+// @Tags Приглашения
+// @Param id path int true "Идентификатор мероприятия"
+// @Param Request body inviteInput true "Кого пригласить"
+// @Success 200 {object} InvitationOkResponse "Приглашение отправлено"
+// @Failure 201 {object} ErrResponse "Ошибка при отправке приглашения"
+// @Router /api/v1/events/{id}/invitations [post]
+func (h *InvitationHandler) InviteToEvent(w http.ResponseWriter, r *http.Request) {
+	h.invite(w, r, models.InvitationSubjectEvent)
+}
+
+// Приглашения на мероприятие
+// @Summary Приглашения и ссылки-приглашения на мероприятие
+// @Description Только для организатора.
+// @Tags Приглашения
+// @Param id path int true "Идентификатор мероприятия"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} InvitationsOkResponse "Приглашения, новые первыми"
+// @Failure 201 {object} ErrResponse "Ошибка при получении приглашений"
+// @Router /api/v1/events/{id}/invitations [get]
+func (h *InvitationHandler) EventInvitations(w http.ResponseWriter, r *http.Request) {
+	h.subjectInvitations(w, r, models.InvitationSubjectEvent)
+}
+
+// Ссылка-приглашение на мероприятие
+// @Summary Создание ссылки-приглашения на мероприятие
+// @Description Только для организатора. Ссылкой может воспользоваться любой, пока она не истекла
+// @Description и не исчерпано число использований.
+// @Tags Приглашения
+// @Param id path int true "Идентификатор мероприятия"
+// @Param Request body inviteLinkInput true "Ограничения ссылки"
+// @Success 200 {object} InvitationOkResponse "Ссылка создана"
+// @Failure 201 {object} ErrResponse "Ошибка при создании ссылки"
+// @Router /api/v1/events/{id}/invite-links [post]
+func (h *InvitationHandler) CreateEventInviteLink(w http.ResponseWriter, r *http.Request) {
+	h.createInviteLink(w, r, models.InvitationSubjectEvent)
+}
+
+// Мои приглашения
+// @Summary Приглашения, которые ждут ответа текущего пользователя
+// @Tags Приглашения
+// @Success 200 {object} InvitationsOkResponse "Приглашения, новые первыми"
+// @Failure 201 {object} ErrResponse "Ошибка при получении приглашений"
+// @Router /api/v1/invitations [get]
+func (h *InvitationHandler) Invitations(w http.ResponseWriter, r *http.Request) {
+	invitations, err := h.services.UserInvitations(currentUserID(r))
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, newInvitationsResponse(invitations))
+}
+
+// Отзыв приглашения
+// @Summary Отзыв приглашения или ссылки-приглашения
+// @Description Только для владельца сообщества или организатора. Принявшие приглашение остаются
+// @Description участниками, но после выхода не смогут вернуться по нему.
+// @Tags Приглашения
+// @Param id path int true "Идентификатор приглашения"
+// @Success 200 {object} StatusResponse "Приглашение отозвано"
+// @Failure 201 {object} ErrResponse "Ошибка при отзыве приглашения"
+// @Router /api/v1/invitations/{id} [delete]
+func (h *InvitationHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.RevokeInvitation(currentUserID(r), id); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Просмотр приглашения
+// @Summary Куда ведёт приглашение
+// @Description Авторизация не нужна: по ссылке можно узнать, куда она приглашает, до входа или регистрации.
+// @Tags Приглашения
+// @Param token path string true "Секрет из ссылки или код из письма"
+// @Success 200 {object} InvitePreviewOkResponse "Приглашение"
+// @Failure 201 {object} ErrResponse "Приглашение не найдено, отозвано или истекло"
+// @Router /api/v1/invites/{token} [get]
+func (h *InvitationHandler) InvitePreview(w http.ResponseWriter, r *http.Request) {
+	invitation, err := h.services.Invitation(chi.URLParam(r, "token"))
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, InvitePreviewOkResponse{Status: "ok", Invite: InvitePreviewResponse{
+		SubjectType:  invitation.SubjectType,
+		SubjectId:    invitation.SubjectID,
+		SubjectTitle: invitation.SubjectTitle,
+		Personal:     invitation.Personal(),
+		ExpiresAt:    invitation.ExpiresAt,
+	}})
+}
+
+// Принятие приглашения
+// @Summary Принятие приглашения текущим пользователем
+// @Description Пользователь сразу вступает в сообщество или записывается на мероприятие.
+// @Description Личное приглашение может принять только тот, кому оно адресовано.
+// @Tags Приглашения
+// @Param token path string true "Секрет из ссылки или код из письма"
+// @Success 200 {object} InvitationOkResponse "Приглашение принято"
+// @Failure 201 {object} ErrResponse "Ошибка при принятии приглашения"
+// @Router /api/v1/invites/{token}/accept [post]
+func (h *InvitationHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	invitation, err := h.services.AcceptInvitation(currentUserID(r), chi.URLParam(r, "token"))
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, InvitationOkResponse{Status: "ok", Invitation: newInvitationResponse(invitation)})
+}
+
+func (h *InvitationHandler) invite(w http.ResponseWriter, r *http.Request, subjectType string) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input inviteInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	invitation, err := h.services.Invite(currentUserID(r), subjectType, id, input.Username, input.Email)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, InvitationOkResponse{Status: "ok", Invitation: newInvitationResponse(invitation)})
+}
+
+func (h *InvitationHandler) subjectInvitations(w http.ResponseWriter, r *http.Request, subjectType string) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+	limit, offset := pagination(r)
+
+	invitations, err := h.services.SubjectInvitations(currentUserID(r), subjectType, id, limit, offset)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, newInvitationsResponse(invitations))
+}
+
+func (h *InvitationHandler) createInviteLink(w http.ResponseWriter, r *http.Request, subjectType string) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input inviteLinkInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	invitation, err := h.services.CreateInviteLink(currentUserID(r), subjectType, id, input.MaxUses, input.ExpiresAt)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, InvitationOkResponse{Status: "ok", Invitation: newInvitationResponse(invitation)})
+}
@@ -0,0 +1,143 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"encoding/json"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type JobHandler struct {
+	services transport.JobServiceInt
+	logger   *slog.Logger
+}
+
+func NewJobHandler(serv transport.JobServiceInt, logger *slog.Logger) *JobHandler {
+	return &JobHandler{services: serv, logger: logger}
+}
+
+type JobResponse struct {
+	Id          int64           `json:"id" example:"1024"`
+	Type        string          `json:"type" example:"email.send"`
+	Payload     json.RawMessage `json:"payload" swaggertype:"object"`
+	Status      string          `json:"status" example:"dead"`
+	Attempts    int             `json:"attempts" example:"10"`
+	MaxAttempts int             `json:"max_attempts" example:"10"`
+	RunAt       time.Time       `json:"run_at" example:"2024-02-20T12:00:00+03:00"`
+	LastError   string          `json:"last_error,omitempty" example:"dial tcp: connection refused"`
+	CreatedAt   time.Time       `json:"created_at" example:"2024-02-20T11:00:00+03:00"`
+	UpdatedAt   time.Time       `json:"updated_at" example:"2024-02-20T12:00:05+03:00"`
+}
+
+type JobsOkResponse struct {
+	Status     string        `json:"status" example:"ok"`
+	Jobs       []JobResponse `json:"jobs"`
+	NextCursor string        `json:"next_cursor,omitempty" example:"MTAyNA"`
+}
+
+type JobOkResponse struct {
+	Status string      `json:"status" example:"ok"`
+	Job    JobResponse `json:"job"`
+}
+
+// Фоновые задачи
+// @Summary Задачи очереди, новые сначала. Только для администраторов
+// @Description pending - ждёт выполнения, running - выполняется, done - выполнена, dead - исчерпала попытки.
+// @Tags Администрирование
+// @Param status query string false "Статус: pending, running, done, dead"
+// @Param type query string false "Тип задачи"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Success 200 {object} JobsOkResponse "Задачи"
+// @Failure 201 {object} ErrResponse "Ошибка при получении задач"
+// @Router /api/v1/admin/jobs [get]
+func (h *JobHandler) Jobs(w http.ResponseWriter, r *http.Request) {
+	limit, cursor, ok := cursorPagination(r)
+	if !ok {
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+		return
+	}
+
+	jobs, err := h.services.Jobs(currentUserID(r), models.JobFilter{
+		Status: r.URL.Query().Get("status"),
+		Type:   r.URL.Query().Get("type"),
+		Cursor: cursor,
+		Limit:  limit,
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := JobsOkResponse{Status: "ok", Jobs: make([]JobResponse, 0, len(jobs))}
+	for _, job := range jobs {
+		response.Jobs = append(response.Jobs, jobResponse(job))
+	}
+	if len(jobs) > 0 {
+		response.NextCursor = nextCursor(int(jobs[len(jobs)-1].ID), len(jobs), limit)
+	}
+
+	render.JSON(w, r, response)
+}
+
+// Фоновая задача
+// @Summary Задача очереди с полезной нагрузкой и последней ошибкой. Только для администраторов
+// @Tags Администрирование
+// @Param id path int true "Идентификатор задачи"
+// @Success 200 {object} JobOkResponse "Задача"
+// @Failure 201 {object} ErrResponse "Задача не найдена"
+// @Router /api/v1/admin/jobs/{id} [get]
+func (h *JobHandler) Job(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	job, err := h.services.Job(currentUserID(r), int64(id))
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, JobOkResponse{Status: "ok", Job: jobResponse(job)})
+}
+
+// Перезапуск задачи
+// @Summary Перезапуск задачи, исчерпавшей попытки. Только для администраторов
+// @Description Задача в статусе dead возвращается в очередь и получает новый набор попыток.
+// @Tags Администрирование
+// @Param id path int true "Идентификатор задачи"
+// @Success 200 {object} StatusResponse "Задача возвращена в очередь"
+// @Failure 201 {object} ErrResponse "Задача не найдена или не в статусе dead"
+// @Router /api/v1/admin/jobs/{id}/retry [post]
+func (h *JobHandler) RetryJob(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.RetryJob(currentUserID(r), int64(id)); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+func jobResponse(job models.Job) JobResponse {
+	return JobResponse{
+		Id:          job.ID,
+		Type:        job.Type,
+		Payload:     job.Payload,
+		Status:      job.Status,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt,
+		LastError:   job.LastError,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+}
@@ -0,0 +1,286 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type MatchHandler struct {
+	services transport.MatchServiceInt
+	logger   *slog.Logger
+}
+
+func NewMatchHandler(serv transport.MatchServiceInt, logger *slog.Logger) *MatchHandler {
+	return &MatchHandler{services: serv, logger: logger}
+}
+
+type matchSettingsInput struct {
+	Goals []string `json:"goals" validate:"required,min=1,dive,oneof=mentorship pair_programming cofounding" example:"mentorship,pair_programming"`
+}
+
+type matchRequestInput struct {
+	UserId  int    `json:"user_id" validate:"required,gt=0" example:"12"`
+	Goal    string `json:"goal" validate:"required,oneof=mentorship pair_programming cofounding" example:"pair_programming"`
+	Message string `json:"message" validate:"max=500" example:"Привет! Хочу вместе покодить на Go по выходным"`
+}
+
+type MatchSettingsOkResponse struct {
+	Status string   `json:"status" example:"ok"`
+	Goals  []string `json:"goals" example:"mentorship,pair_programming"`
+}
+
+type MatchResponse struct {
+	UserId    int      `json:"user_id" example:"12"`
+	Name      string   `json:"name" example:"Иван Петров"`
+	Username  string   `json:"username,omitempty" example:"gopher"`
+	City      string   `json:"city" example:"Москва"`
+	Skills    []string `json:"skills" example:"go,postgres"`
+	Seniority string   `json:"seniority" example:"senior"`
+	Goal      string   `json:"goal" example:"mentorship"`
+	Score     float64  `json:"score" example:"7.5"`
+	Reasons   []string `json:"reasons" example:"Может стать вашим ментором: уровень senior,Общие навыки: go"`
+}
+
+type MatchesOkResponse struct {
+	Status  string          `json:"status" example:"ok"`
+	Matches []MatchResponse `json:"matches"`
+}
+
+type MatchRequestResponse struct {
+	Id             int        `json:"id" example:"3"`
+	RequesterId    int        `json:"requester_id" example:"7"`
+	RecipientId    int        `json:"recipient_id" example:"12"`
+	Goal           string     `json:"goal" example:"pair_programming"`
+	Message        string     `json:"message,omitempty" example:"Привет! Хочу вместе покодить на Go по выходным"`
+	Status         string     `json:"status" example:"pending"`
+	ConversationId *int       `json:"conversation_id,omitempty" example:"5"`
+	CreatedAt      time.Time  `json:"created_at" example:"2024-02-20T12:00:00+03:00"`
+	RespondedAt    *time.Time `json:"responded_at,omitempty" example:"2024-02-20T13:00:00+03:00"`
+}
+
+type MatchRequestOkResponse struct {
+	Status  string               `json:"status" example:"ok"`
+	Request MatchRequestResponse `json:"request"`
+}
+
+type MatchRequestsOkResponse struct {
+	Status     string                 `json:"status" example:"ok"`
+	Requests   []MatchRequestResponse `json:"requests"`
+	NextCursor string                 `json:"next_cursor,omitempty" example:"Mw"`
+}
+
+func newMatchRequestResponse(request models.MatchRequest) MatchRequestResponse {
+	return MatchRequestResponse{
+		Id:             request.ID,
+		RequesterId:    request.RequesterID,
+		RecipientId:    request.RecipientID,
+		Goal:           request.Goal,
+		Message:        request.Message,
+		Status:         request.Status,
+		ConversationId: request.ConversationID,
+		CreatedAt:      request.CreatedAt,
+		RespondedAt:    request.RespondedAt,
+	}
+}
+
+// Настройки подбора
+// @Summary Цели, с которыми текущий пользователь участвует в подборе собеседников
+// @Tags Знакомства
+// @Success 200 {object} MatchSettingsOkResponse "Цели"
+// @Failure 201 {object} ErrResponse "Пользователь не участвует в подборе"
+// @Router /api/v1/matches/settings [get]
+func (h *MatchHandler) MatchSettings(w http.ResponseWriter, r *http.Request) {
+	goals, err := h.services.MatchGoals(currentUserID(r))
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, MatchSettingsOkResponse{Status: "ok", Goals: goals})
+}
+
+// Участие в подборе
+// @Summary Согласие участвовать в подборе собеседников и цели знакомства
+// @Description Цели: mentorship (менторство), pair_programming (парное программирование), cofounding (совместный проект).
+// @Description Пока пользователь не согласился, он не видит подборку и не попадает в чужие.
+// @Tags Знакомства
+// @Param Request body matchSettingsInput true "Цели"
+// @Success 200 {object} StatusResponse "Настройки сохранены"
+// @Failure 201 {object} ErrResponse "Неизвестная цель"
+// @Router /api/v1/matches/settings [put]
+func (h *MatchHandler) UpdateMatchSettings(w http.ResponseWriter, r *http.Request) {
+	var input matchSettingsInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	if err := h.services.SetMatchGoals(currentUserID(r), input.Goals); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Отказ от подбора
+// @Summary Выход из подбора собеседников
+// @Tags Знакомства
+// @Success 200 {object} StatusResponse "Пользователь исключён из подбора"
+// @Failure 201 {object} ErrResponse "Пользователь не участвует в подборе"
+// @Router /api/v1/matches/settings [delete]
+func (h *MatchHandler) DisableMatching(w http.ResponseWriter, r *http.Request) {
+	if err := h.services.DisableMatching(currentUserID(r)); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Подборка собеседников
+// @Summary С кем стоит познакомиться
+// @Description Кандидаты оцениваются по навыкам, уровню, городу и общим сообществам, reasons объясняет оценку.
+// @Description Подходят только участники с общей целью; те, с кем уже есть заявка, и заблокированные не показываются.
+// @Tags Знакомства
+// @Param goal query string false "Цель: mentorship, pair_programming или cofounding"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Success 200 {object} MatchesOkResponse "Подборка"
+// @Failure 201 {object} ErrResponse "Пользователь не участвует в подборе"
+// @Router /api/v1/matches [get]
+func (h *MatchHandler) Matches(w http.ResponseWriter, r *http.Request) {
+	limit, _ := pagination(r)
+
+	matches, err := h.services.Matches(currentUserID(r), r.URL.Query().Get("goal"), limit)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := MatchesOkResponse{Status: "ok", Matches: make([]MatchResponse, 0, len(matches))}
+	for _, m := range matches {
+		response.Matches = append(response.Matches, MatchResponse{
+			UserId:    m.Candidate.UserID,
+			Name:      m.Candidate.Name,
+			Username:  m.Candidate.Username,
+			City:      m.Candidate.City,
+			Skills:    m.Candidate.Skills,
+			Seniority: m.Candidate.Seniority,
+			Goal:      m.Goal,
+			Score:     m.Score,
+			Reasons:   m.Reasons,
+		})
+	}
+
+	render.JSON(w, r, response)
+}
+
+// Заявка на знакомство
+// @Summary Заявка на знакомство с участником подбора
+// @Description Цель должна быть у обоих. Получатель получает уведомление match_request.
+// @Description Не больше 20 заявок в сутки, иначе too_many_requests.
+// @Tags Знакомства
+// @Param Request body matchRequestInput true "Заявка"
+// @Success 200 {object} IdResponse "Заявка отправлена"
+// @Failure 201 {object} ErrResponse "Ошибка при отправке заявки"
+// @Router /api/v1/matches/requests [post]
+func (h *MatchHandler) RequestMatch(w http.ResponseWriter, r *http.Request) {
+	var input matchRequestInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	id, err := h.services.RequestMatch(currentUserID(r), input.UserId, input.Goal, input.Message)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, IdResponse{Status: "ok", Id: id})
+}
+
+// Заявки на знакомство
+// @Summary Входящие или отправленные заявки, новые сначала
+// @Tags Знакомства
+// @Param incoming query bool false "Входящие заявки (по умолчанию отправленные)"
+// @Param status query string false "Статус: pending, accepted или declined"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Success 200 {object} MatchRequestsOkResponse "Заявки"
+// @Failure 201 {object} ErrResponse "Ошибка при получении заявок"
+// @Router /api/v1/matches/requests [get]
+func (h *MatchHandler) MatchRequests(w http.ResponseWriter, r *http.Request) {
+	limit, cursor, ok := cursorPagination(r)
+	if !ok {
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+		return
+	}
+
+	requests, err := h.services.MatchRequests(currentUserID(r), models.MatchRequestFilter{
+		Incoming: r.URL.Query().Get("incoming") == "true",
+		Status:   r.URL.Query().Get("status"),
+		Cursor:   cursor,
+		Limit:    limit,
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := MatchRequestsOkResponse{Status: "ok", Requests: make([]MatchRequestResponse, 0, len(requests))}
+	for _, request := range requests {
+		response.Requests = append(response.Requests, newMatchRequestResponse(request))
+	}
+	if len(requests) > 0 {
+		response.NextCursor = nextCursor(requests[len(requests)-1].ID, len(requests), limit)
+	}
+
+	render.JSON(w, r, response)
+}
+
+// Принятие заявки
+// @Summary Принятие заявки на знакомство
+// @Description Открывает личный диалог с автором заявки, его идентификатор возвращается в conversation_id.
+// @Tags Знакомства
+// @Param id path int true "Идентификатор заявки"
+// @Success 200 {object} MatchRequestOkResponse "Заявка принята"
+// @Failure 201 {object} ErrResponse "Заявка не найдена, уже рассмотрена или нет прав"
+// @Router /api/v1/matches/requests/{id}/accept [post]
+func (h *MatchHandler) AcceptMatchRequest(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	request, err := h.services.AcceptMatchRequest(currentUserID(r), id)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, MatchRequestOkResponse{Status: "ok", Request: newMatchRequestResponse(request)})
+}
+
+// Отклонение заявки
+// @Summary Отклонение заявки на знакомство
+// @Tags Знакомства
+// @Param id path int true "Идентификатор заявки"
+// @Success 200 {object} StatusResponse "Заявка отклонена"
+// @Failure 201 {object} ErrResponse "Заявка не найдена, уже рассмотрена или нет прав"
+// @Router /api/v1/matches/requests/{id}/decline [post]
+func (h *MatchHandler) DeclineMatchRequest(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.DeclineMatchRequest(currentUserID(r), id); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
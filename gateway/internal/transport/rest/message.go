@@ -0,0 +1,377 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type MessageHandler struct {
+	services transport.MessageServiceInt
+	logger   *slog.Logger
+}
+
+func NewMessageHandler(serv transport.MessageServiceInt, logger *slog.Logger) *MessageHandler {
+	return &MessageHandler{services: serv, logger: logger}
+}
+
+type conversationInput struct {
+	UserIds []int  `json:"user_ids" validate:"required,min=1,max=9,dive,gt=0" example:"12"`
+	Title   string `json:"title" validate:"max=100" example:"Организаторы Go meetup"`
+}
+
+type messageInput struct {
+	Body string `json:"body" validate:"required,max=4000" example:"Привет! Увидимся на митапе?"`
+}
+
+type readInput struct {
+	MessageId int `json:"message_id" validate:"required,gt=0" example:"128"`
+}
+
+type ConversationMemberResponse struct {
+	UserId            int       `json:"user_id" example:"12"`
+	Username          string    `json:"username,omitempty" example:"gopher"`
+	Name              string    `json:"name" example:"Иван Петров"`
+	LastReadMessageId int       `json:"last_read_message_id" example:"127"`
+	JoinedAt          time.Time `json:"joined_at" example:"2024-02-20T12:00:00+03:00"`
+}
+
+type MessageResponse struct {
+	Id             int       `json:"id" example:"128"`
+	ConversationId int       `json:"conversation_id" example:"5"`
+	SenderId       int       `json:"sender_id" example:"12"`
+	Body           string    `json:"body" example:"Привет! Увидимся на митапе?"`
+	Hidden         bool      `json:"hidden" example:"false"`
+	CreatedAt      time.Time `json:"created_at" example:"2024-02-20T12:05:00+03:00"`
+}
+
+type ConversationResponse struct {
+	Id            int                          `json:"id" example:"5"`
+	Kind          string                       `json:"kind" example:"direct"`
+	Title         string                       `json:"title,omitempty" example:"Организаторы Go meetup"`
+	CreatedBy     int                          `json:"created_by" example:"7"`
+	Members       []ConversationMemberResponse `json:"members"`
+	LastMessage   *MessageResponse             `json:"last_message,omitempty"`
+	UnreadCount   int                          `json:"unread_count" example:"2"`
+	CreatedAt     time.Time                    `json:"created_at" example:"2024-02-20T12:00:00+03:00"`
+	LastMessageAt *time.Time                   `json:"last_message_at,omitempty" example:"2024-02-20T12:05:00+03:00"`
+}
+
+type ConversationOkResponse struct {
+	Status       string               `json:"status" example:"ok"`
+	Conversation ConversationResponse `json:"conversation"`
+}
+
+type ConversationsOkResponse struct {
+	Status        string                 `json:"status" example:"ok"`
+	Conversations []ConversationResponse `json:"conversations"`
+}
+
+type MessageOkResponse struct {
+	Status  string          `json:"status" example:"ok"`
+	Message MessageResponse `json:"message"`
+}
+
+type MessagesOkResponse struct {
+	Status     string            `json:"status" example:"ok"`
+	Messages   []MessageResponse `json:"messages"`
+	NextCursor string            `json:"next_cursor,omitempty" example:"MTI4"`
+}
+
+type ReadOkResponse struct {
+	Status            string `json:"status" example:"ok"`
+	LastReadMessageId int    `json:"last_read_message_id" example:"128"`
+}
+
+type BlockResponse struct {
+	UserId    int       `json:"user_id" example:"12"`
+	Username  string    `json:"username,omitempty" example:"spammer"`
+	Name      string    `json:"name" example:"Иван Петров"`
+	CreatedAt time.Time `json:"created_at" example:"2024-02-20T12:00:00+03:00"`
+}
+
+type BlocksOkResponse struct {
+	Status string          `json:"status" example:"ok"`
+	Blocks []BlockResponse `json:"blocks"`
+}
+
+func newMessageResponse(message models.Message) MessageResponse {
+	return MessageResponse{
+		Id:             message.ID,
+		ConversationId: message.ConversationID,
+		SenderId:       message.SenderID,
+		Body:           message.Body,
+		Hidden:         message.HiddenAt != nil,
+		CreatedAt:      message.CreatedAt,
+	}
+}
+
+func newConversationResponse(conversation models.Conversation) ConversationResponse {
+	response := ConversationResponse{
+		Id:            conversation.ID,
+		Kind:          conversation.Kind,
+		Title:         conversation.Title,
+		CreatedBy:     conversation.CreatedBy,
+		Members:       make([]ConversationMemberResponse, 0, len(conversation.Members)),
+		UnreadCount:   conversation.UnreadCount,
+		CreatedAt:     conversation.CreatedAt,
+		LastMessageAt: conversation.LastMessageAt,
+	}
+	for _, m := range conversation.Members {
+		response.Members = append(response.Members, ConversationMemberResponse{
+			UserId:            m.UserID,
+			Username:          m.Username,
+			Name:              m.Name,
+			LastReadMessageId: m.LastReadMessageID,
+			JoinedAt:          m.JoinedAt,
+		})
+	}
+	if conversation.LastMessage != nil {
+		last := newMessageResponse(*conversation.LastMessage)
+		response.LastMessage = &last
+	}
+
+	return response
+}
+
+// Создание диалога
+// @Summary Личный диалог или группа до 10 участников
+// @Description С одним собеседником и без названия создаётся личный диалог; если он уже есть, возвращается существующий.
+// @Description Нельзя начать диалог с тем, кто заблокировал вас или кого заблокировали вы.
+// @Description Не больше 20 новых диалогов в час, иначе too_many_requests.
+// @Tags Сообщения
+// @Param Request body conversationInput true "Участники и название"
+// @Success 200 {object} ConversationOkResponse "Диалог"
+// @Failure 201 {object} ErrResponse "Ошибка при создании диалога"
+// @Router /api/v1/conversations [post]
+func (h *MessageHandler) CreateConversation(w http.ResponseWriter, r *http.Request) {
+	var input conversationInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	conversation, err := h.services.CreateConversation(currentUserID(r), input.UserIds, input.Title)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ConversationOkResponse{Status: "ok", Conversation: newConversationResponse(conversation)})
+}
+
+// Диалоги
+// @Summary Диалоги текущего пользователя, недавно активные сначала
+// @Tags Сообщения
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} ConversationsOkResponse "Диалоги"
+// @Failure 201 {object} ErrResponse "Ошибка при получении диалогов"
+// @Router /api/v1/conversations [get]
+func (h *MessageHandler) Conversations(w http.ResponseWriter, r *http.Request) {
+	limit, offset := pagination(r)
+
+	conversations, err := h.services.Conversations(currentUserID(r), limit, offset)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := ConversationsOkResponse{Status: "ok", Conversations: make([]ConversationResponse, 0, len(conversations))}
+	for _, conversation := range conversations {
+		response.Conversations = append(response.Conversations, newConversationResponse(conversation))
+	}
+
+	render.JSON(w, r, response)
+}
+
+// Диалог
+// @Summary Диалог с участниками и их отметками о прочтении
+// @Tags Сообщения
+// @Param id path int true "Идентификатор диалога"
+// @Success 200 {object} ConversationOkResponse "Диалог"
+// @Failure 201 {object} ErrResponse "Диалог не найден"
+// @Router /api/v1/conversations/{id} [get]
+func (h *MessageHandler) Conversation(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	conversation, err := h.services.Conversation(currentUserID(r), id)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ConversationOkResponse{Status: "ok", Conversation: newConversationResponse(conversation)})
+}
+
+// История сообщений
+// @Summary Сообщения диалога, новые сначала
+// @Tags Сообщения
+// @Param id path int true "Идентификатор диалога"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Success 200 {object} MessagesOkResponse "Сообщения"
+// @Failure 201 {object} ErrResponse "Диалог не найден"
+// @Router /api/v1/conversations/{id}/messages [get]
+func (h *MessageHandler) Messages(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	limit, cursor, ok := cursorPagination(r)
+	if !ok {
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+		return
+	}
+
+	messages, err := h.services.Messages(currentUserID(r), id, models.MessageFilter{Cursor: cursor, Limit: limit})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := MessagesOkResponse{Status: "ok", Messages: make([]MessageResponse, 0, len(messages))}
+	for _, message := range messages {
+		response.Messages = append(response.Messages, newMessageResponse(message))
+	}
+	if len(messages) > 0 {
+		response.NextCursor = nextCursor(messages[len(messages)-1].ID, len(messages), limit)
+	}
+
+	render.JSON(w, r, response)
+}
+
+// Отправка сообщения
+// @Summary Отправка сообщения в диалог
+// @Description Участники получают событие message в потоке /api/v1/stream.
+// @Description В личный диалог нельзя писать, если один из собеседников заблокировал другого.
+// @Description Не больше 30 сообщений в минуту, иначе too_many_requests.
+// @Tags Сообщения
+// @Param id path int true "Идентификатор диалога"
+// @Param Request body messageInput true "Сообщение"
+// @Success 200 {object} MessageOkResponse "Сообщение отправлено"
+// @Failure 201 {object} ErrResponse "Ошибка при отправке сообщения"
+// @Router /api/v1/conversations/{id}/messages [post]
+func (h *MessageHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input messageInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	message, err := h.services.SendMessage(currentUserID(r), id, input.Body)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, MessageOkResponse{Status: "ok", Message: newMessageResponse(message)})
+}
+
+// Прочтение диалога
+// @Summary Отметка сообщений до message_id прочитанными
+// @Description Остальные участники получают событие message_read в потоке /api/v1/stream.
+// @Tags Сообщения
+// @Param id path int true "Идентификатор диалога"
+// @Param Request body readInput true "Последнее прочитанное сообщение"
+// @Success 200 {object} ReadOkResponse "Отметка о прочтении"
+// @Failure 201 {object} ErrResponse "Диалог не найден"
+// @Router /api/v1/conversations/{id}/read [post]
+func (h *MessageHandler) ReadConversation(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input readInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	lastRead, err := h.services.ReadConversation(currentUserID(r), id, input.MessageId)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ReadOkResponse{Status: "ok", LastReadMessageId: lastRead})
+}
+
+// Блокировка пользователя
+// @Summary Блокировка пользователя
+// @Description Заблокированный не может начать с вами диалог и писать в личный диалог, его сообщения в группах не приходят вам в поток.
+// @Tags Сообщения
+// @Param id path int true "Идентификатор пользователя"
+// @Success 200 {object} StatusResponse "Пользователь заблокирован"
+// @Failure 201 {object} ErrResponse "Пользователь не найден"
+// @Router /api/v1/blocks/{id} [post]
+func (h *MessageHandler) BlockUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.BlockUser(currentUserID(r), id); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Разблокировка пользователя
+// @Summary Разблокировка пользователя
+// @Tags Сообщения
+// @Param id path int true "Идентификатор пользователя"
+// @Success 200 {object} StatusResponse "Пользователь разблокирован"
+// @Failure 201 {object} ErrResponse "Пользователь не заблокирован"
+// @Router /api/v1/blocks/{id} [delete]
+func (h *MessageHandler) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.UnblockUser(currentUserID(r), id); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Заблокированные пользователи
+// @Summary Пользователи, которых заблокировал текущий
+// @Tags Сообщения
+// @Success 200 {object} BlocksOkResponse "Заблокированные"
+// @Failure 201 {object} ErrResponse "Ошибка при получении списка"
+// @Router /api/v1/blocks [get]
+func (h *MessageHandler) Blocks(w http.ResponseWriter, r *http.Request) {
+	blocks, err := h.services.Blocks(currentUserID(r))
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := BlocksOkResponse{Status: "ok", Blocks: make([]BlockResponse, 0, len(blocks))}
+	for _, b := range blocks {
+		response.Blocks = append(response.Blocks, BlockResponse{
+			UserId:    b.UserID,
+			Username:  b.Username,
+			Name:      b.Name,
+			CreatedAt: b.CreatedAt,
+		})
+	}
+
+	render.JSON(w, r, response)
+}
@@ -0,0 +1,285 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type ModerationHandler struct {
+	services transport.ModerationServiceInt
+	logger   *slog.Logger
+}
+
+func NewModerationHandler(serv transport.ModerationServiceInt, logger *slog.Logger) *ModerationHandler {
+	return &ModerationHandler{services: serv, logger: logger}
+}
+
+type reportInput struct {
+	TargetType string `json:"target_type" validate:"required,oneof=user event comment message" example:"comment"`
+	TargetId   int    `json:"target_id" validate:"required,gt=0" example:"42"`
+	Reason     string `json:"reason" validate:"required,oneof=spam abuse inappropriate other" example:"spam"`
+	Details    string `json:"details" validate:"max=1000" example:"Реклама курсов в каждом обсуждении"`
+}
+
+type resolveReportInput struct {
+	Action      string `json:"action" validate:"required,oneof=dismiss hide warn suspend" example:"hide"`
+	Note        string `json:"note" validate:"max=1000" example:"Реклама"`
+	SuspendDays int    `json:"suspend_days" validate:"min=0,max=365" example:"7"`
+}
+
+type ReportResponse struct {
+	Id           int        `json:"id" example:"9"`
+	ReporterId   int        `json:"reporter_id" example:"7"`
+	TargetType   string     `json:"target_type" example:"comment"`
+	TargetId     int        `json:"target_id" example:"42"`
+	TargetUserId int        `json:"target_user_id" example:"12"`
+	Reason       string     `json:"reason" example:"spam"`
+	Details      string     `json:"details,omitempty" example:"Реклама курсов в каждом обсуждении"`
+	Snapshot     string     `json:"snapshot" example:"Лучшие курсы по Go со скидкой!"`
+	Status       string     `json:"status" example:"open"`
+	ResolvedBy   *int       `json:"resolved_by,omitempty" example:"1"`
+	ResolvedAt   *time.Time `json:"resolved_at,omitempty" example:"2024-02-20T13:00:00+03:00"`
+	CreatedAt    time.Time  `json:"created_at" example:"2024-02-20T12:00:00+03:00"`
+}
+
+type ReportOkResponse struct {
+	Status string         `json:"status" example:"ok"`
+	Report ReportResponse `json:"report"`
+}
+
+type ReportsOkResponse struct {
+	Status     string           `json:"status" example:"ok"`
+	Reports    []ReportResponse `json:"reports"`
+	NextCursor string           `json:"next_cursor,omitempty" example:"OQ"`
+}
+
+type ModerationActionResponse struct {
+	Id             int        `json:"id" example:"5"`
+	ModeratorId    *int       `json:"moderator_id,omitempty" example:"1"`
+	Action         string     `json:"action" example:"hide"`
+	TargetType     string     `json:"target_type" example:"comment"`
+	TargetId       int        `json:"target_id" example:"42"`
+	TargetUserId   *int       `json:"target_user_id,omitempty" example:"12"`
+	ReportIds      []int      `json:"report_ids" example:"9,10"`
+	Note           string     `json:"note,omitempty" example:"Реклама"`
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty" example:"2024-02-27T13:00:00+03:00"`
+	CreatedAt      time.Time  `json:"created_at" example:"2024-02-20T13:00:00+03:00"`
+}
+
+type ModerationActionOkResponse struct {
+	Status string                   `json:"status" example:"ok"`
+	Action ModerationActionResponse `json:"action"`
+}
+
+type ModerationLogOkResponse struct {
+	Status     string                     `json:"status" example:"ok"`
+	Actions    []ModerationActionResponse `json:"actions"`
+	NextCursor string                     `json:"next_cursor,omitempty" example:"NQ"`
+}
+
+func newReportResponse(report models.Report) ReportResponse {
+	return ReportResponse{
+		Id:           report.ID,
+		ReporterId:   report.ReporterID,
+		TargetType:   report.TargetType,
+		TargetId:     report.TargetID,
+		TargetUserId: report.TargetUserID,
+		Reason:       report.Reason,
+		Details:      report.Details,
+		Snapshot:     report.Snapshot,
+		Status:       report.Status,
+		ResolvedBy:   report.ResolvedBy,
+		ResolvedAt:   report.ResolvedAt,
+		CreatedAt:    report.CreatedAt,
+	}
+}
+
+func newModerationActionResponse(action models.ModerationAction) ModerationActionResponse {
+	return ModerationActionResponse{
+		Id:             action.ID,
+		ModeratorId:    action.ModeratorID,
+		Action:         action.Action,
+		TargetType:     action.TargetType,
+		TargetId:       action.TargetID,
+		TargetUserId:   action.TargetUserID,
+		ReportIds:      action.ReportIDs,
+		Note:           action.Note,
+		SuspendedUntil: action.SuspendedUntil,
+		CreatedAt:      action.CreatedAt,
+	}
+}
+
+// Жалоба
+// @Summary Жалоба на пользователя, мероприятие, комментарий или сообщение
+// @Description Жалобу разбирают модераторы. Пожаловаться на сообщение может только участник диалога.
+// @Description Не больше 10 жалоб в час, иначе too_many_requests.
+// @Tags Модерация
+// @Param Request body reportInput true "Жалоба"
+// @Success 200 {object} IdResponse "Жалоба принята"
+// @Failure 201 {object} ErrResponse "Содержимое не найдено или жалоба уже подана"
+// @Router /api/v1/reports [post]
+func (h *ModerationHandler) Report(w http.ResponseWriter, r *http.Request) {
+	var input reportInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	id, err := h.services.Report(currentUserID(r), models.Report{
+		TargetType: input.TargetType,
+		TargetID:   input.TargetId,
+		Reason:     input.Reason,
+		Details:    input.Details,
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, IdResponse{Status: "ok", Id: id})
+}
+
+// Очередь жалоб
+// @Summary Очередь жалоб для модераторов, старые сначала
+// @Description Доступно модераторам и администраторам.
+// @Tags Модерация
+// @Param status query string false "Статус: open (по умолчанию), dismissed или actioned"
+// @Param target_type query string false "Тип содержимого: user, event, comment или message"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Success 200 {object} ReportsOkResponse "Жалобы"
+// @Failure 201 {object} ErrResponse "Нет прав"
+// @Router /api/v1/moderation/reports [get]
+func (h *ModerationHandler) Reports(w http.ResponseWriter, r *http.Request) {
+	limit, cursor, ok := cursorPagination(r)
+	if !ok {
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+		return
+	}
+
+	reports, err := h.services.Reports(currentUserID(r), models.ReportFilter{
+		Status:     r.URL.Query().Get("status"),
+		TargetType: r.URL.Query().Get("target_type"),
+		Cursor:     cursor,
+		Limit:      limit,
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := ReportsOkResponse{Status: "ok", Reports: make([]ReportResponse, 0, len(reports))}
+	for _, report := range reports {
+		response.Reports = append(response.Reports, newReportResponse(report))
+	}
+	if len(reports) > 0 {
+		response.NextCursor = nextCursor(reports[len(reports)-1].ID, len(reports), limit)
+	}
+
+	render.JSON(w, r, response)
+}
+
+// Жалоба для модератора
+// @Summary Жалоба вместе с текстом содержимого на момент подачи
+// @Tags Модерация
+// @Param id path int true "Идентификатор жалобы"
+// @Success 200 {object} ReportOkResponse "Жалоба"
+// @Failure 201 {object} ErrResponse "Жалоба не найдена или нет прав"
+// @Router /api/v1/moderation/reports/{id} [get]
+func (h *ModerationHandler) ModerationReport(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	report, err := h.services.ModerationReport(currentUserID(r), id)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ReportOkResponse{Status: "ok", Report: newReportResponse(report)})
+}
+
+// Решение по жалобе
+// @Summary Решение модератора по жалобе
+// @Description action: dismiss (отклонить), hide (скрыть содержимое), warn (предупредить автора),
+// @Description suspend (заблокировать автора на suspend_days дней, 0 - бессрочно).
+// @Description Решение закрывает все открытые жалобы на то же содержимое и записывается в журнал модерации.
+// @Description Модераторов и администраторов заблокировать нельзя.
+// @Tags Модерация
+// @Param id path int true "Идентификатор жалобы"
+// @Param Request body resolveReportInput true "Решение"
+// @Success 200 {object} ModerationActionOkResponse "Запись журнала модерации"
+// @Failure 201 {object} ErrResponse "Жалоба не найдена, уже рассмотрена или нет прав"
+// @Router /api/v1/moderation/reports/{id}/resolve [post]
+func (h *ModerationHandler) ResolveReport(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input resolveReportInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	action, err := h.services.ResolveReport(currentUserID(r), id, models.ModerationDecision{
+		Action:      input.Action,
+		Note:        input.Note,
+		SuspendDays: input.SuspendDays,
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, ModerationActionOkResponse{Status: "ok", Action: newModerationActionResponse(action)})
+}
+
+// Журнал модерации
+// @Summary Журнал решений модераторов, новые сначала
+// @Tags Модерация
+// @Param user_id query int false "Только решения по содержимому этого пользователя"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Success 200 {object} ModerationLogOkResponse "Журнал"
+// @Failure 201 {object} ErrResponse "Нет прав"
+// @Router /api/v1/moderation/log [get]
+func (h *ModerationHandler) ModerationLog(w http.ResponseWriter, r *http.Request) {
+	limit, cursor, ok := cursorPagination(r)
+	if !ok {
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+		return
+	}
+
+	filter := models.ModerationActionFilter{Cursor: cursor, Limit: limit}
+	if userID := r.URL.Query().Get("user_id"); userID != "" {
+		id, err := strconv.Atoi(userID)
+		if err != nil || id <= 0 {
+			render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+			return
+		}
+		filter.TargetUserID = id
+	}
+
+	actions, err := h.services.ModerationLog(currentUserID(r), filter)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := ModerationLogOkResponse{Status: "ok", Actions: make([]ModerationActionResponse, 0, len(actions))}
+	for _, action := range actions {
+		response.Actions = append(response.Actions, newModerationActionResponse(action))
+	}
+	if len(actions) > 0 {
+		response.NextCursor = nextCursor(actions[len(actions)-1].ID, len(actions), limit)
+	}
+
+	render.JSON(w, r, response)
+}
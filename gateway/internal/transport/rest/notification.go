@@ -0,0 +1,219 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type NotificationHandler struct {
+	services transport.NotificationServiceInt
+	logger   *slog.Logger
+}
+
+func NewNotificationHandler(serv transport.NotificationServiceInt, logger *slog.Logger) *NotificationHandler {
+	return &NotificationHandler{services: serv, logger: logger}
+}
+
+type notificationPreferenceInput struct {
+	Type    string `json:"type" validate:"required" example:"group_discussion"`
+	Channel string `json:"channel" validate:"required,oneof=in_app email none" example:"email"`
+}
+
+type notificationPreferencesInput struct {
+	Preferences []notificationPreferenceInput `json:"preferences" validate:"required,min=1,dive"`
+}
+
+type NotificationResponse struct {
+	Id        int        `json:"id" example:"42"`
+	Type      string     `json:"type" example:"talk_accepted"`
+	Title     string     `json:"title" example:"Доклад «Конкурентность в Go» принят"`
+	Body      string     `json:"body" example:"Ваш доклад добавлен в программу мероприятия «Go meetup #12»"`
+	Count     int        `json:"count" example:"1"`
+	Read      bool       `json:"read" example:"false"`
+	ReadAt    *time.Time `json:"read_at,omitempty" example:"2024-02-20T12:00:00+03:00"`
+	CreatedAt time.Time  `json:"created_at" example:"2024-02-20T11:00:00+03:00"`
+	UpdatedAt time.Time  `json:"updated_at" example:"2024-02-20T11:30:00+03:00"`
+}
+
+type NotificationsOkResponse struct {
+	Status        string                 `json:"status" example:"ok"`
+	UnreadCount   int                    `json:"unread_count" example:"3"`
+	Notifications []NotificationResponse `json:"notifications"`
+	NextCursor    string                 `json:"next_cursor,omitempty" example:"NDI"`
+}
+
+type MarkAllReadOkResponse struct {
+	Status string `json:"status" example:"ok"`
+	Count  int    `json:"count" example:"3"`
+}
+
+type NotificationPreferenceResponse struct {
+	Type    string `json:"type" example:"group_discussion"`
+	Channel string `json:"channel" example:"in_app"`
+}
+
+type NotificationPreferencesOkResponse struct {
+	Status      string                           `json:"status" example:"ok"`
+	Preferences []NotificationPreferenceResponse `json:"preferences"`
+}
+
+// Уведомления
+// @Summary Уведомления текущего пользователя, новые сначала, и число непрочитанных
+// @Description Однотипные уведомления (например, новые обсуждения в сообществе) склеиваются, count показывает их число.
+// @Tags Уведомления
+// @Param unread query bool false "Только непрочитанные"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Success 200 {object} NotificationsOkResponse "Уведомления"
+// @Failure 201 {object} ErrResponse "Ошибка при получении уведомлений"
+// @Router /api/v1/notifications [get]
+func (h *NotificationHandler) Notifications(w http.ResponseWriter, r *http.Request) {
+	limit, cursor, ok := cursorPagination(r)
+	if !ok {
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+		return
+	}
+
+	notifications, unread, err := h.services.Notifications(currentUserID(r), models.NotificationFilter{
+		UnreadOnly: r.URL.Query().Get("unread") == "true",
+		Cursor:     cursor,
+		Limit:      limit,
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := NotificationsOkResponse{
+		Status:        "ok",
+		UnreadCount:   unread,
+		Notifications: make([]NotificationResponse, 0, len(notifications)),
+	}
+	for _, n := range notifications {
+		response.Notifications = append(response.Notifications, NotificationResponse{
+			Id:        n.ID,
+			Type:      n.Type,
+			Title:     n.Title,
+			Body:      n.Body,
+			Count:     n.Count,
+			Read:      n.ReadAt != nil,
+			ReadAt:    n.ReadAt,
+			CreatedAt: n.CreatedAt,
+			UpdatedAt: n.UpdatedAt,
+		})
+	}
+	if len(notifications) > 0 {
+		response.NextCursor = nextCursor(notifications[len(notifications)-1].ID, len(notifications), limit)
+	}
+
+	render.JSON(w, r, response)
+}
+
+// Прочтение уведомления
+// @Summary Отметка уведомления прочитанным
+// @Tags Уведомления
+// @Param id path int true "Идентификатор уведомления"
+// @Success 200 {object} StatusResponse "Уведомление прочитано"
+// @Failure 201 {object} ErrResponse "Уведомление не найдено"
+// @Router /api/v1/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.MarkRead(currentUserID(r), id); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Прочтение всех уведомлений
+// @Summary Отметка всех уведомлений прочитанными
+// @Tags Уведомления
+// @Success 200 {object} MarkAllReadOkResponse "Число отмеченных уведомлений"
+// @Failure 201 {object} ErrResponse "Внутренняя ошибка сервиса"
+// @Router /api/v1/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	count, err := h.services.MarkAllRead(currentUserID(r))
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, MarkAllReadOkResponse{Status: "ok", Count: count})
+}
+
+// Настройки уведомлений
+// @Summary Каналы доставки по типам уведомлений
+// @Description in_app - только в центре уведомлений, email - ещё и в периодической email-сводке, none - не доставлять.
+// @Tags Уведомления
+// @Success 200 {object} NotificationPreferencesOkResponse "Настройки"
+// @Failure 201 {object} ErrResponse "Внутренняя ошибка сервиса"
+// @Router /api/v1/notifications/preferences [get]
+func (h *NotificationHandler) NotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	preferences, err := h.services.NotificationPreferences(currentUserID(r))
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	h.renderPreferences(w, r, preferences)
+}
+
+// Изменение настроек уведомлений
+// @Summary Изменение каналов доставки для указанных типов уведомлений
+// @Tags Уведомления
+// @Param Request body notificationPreferencesInput true "Настройки"
+// @Success 200 {object} NotificationPreferencesOkResponse "Настройки после изменения"
+// @Failure 201 {object} ErrResponse "Ошибка при изменении настроек"
+// @Router /api/v1/notifications/preferences [put]
+func (h *NotificationHandler) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	var input notificationPreferencesInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	preferences := make([]models.NotificationPreference, 0, len(input.Preferences))
+	for _, preference := range input.Preferences {
+		preferences = append(preferences, models.NotificationPreference{
+			Type:    preference.Type,
+			Channel: preference.Channel,
+		})
+	}
+
+	userID := currentUserID(r)
+	if err := h.services.SetNotificationPreferences(userID, preferences); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	saved, err := h.services.NotificationPreferences(userID)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	h.renderPreferences(w, r, saved)
+}
+
+func (h *NotificationHandler) renderPreferences(w http.ResponseWriter, r *http.Request, preferences []models.NotificationPreference) {
+	response := NotificationPreferencesOkResponse{
+		Status:      "ok",
+		Preferences: make([]NotificationPreferenceResponse, 0, len(preferences)),
+	}
+	for _, preference := range preferences {
+		response.Preferences = append(response.Preferences, NotificationPreferenceResponse{
+			Type:    preference.Type,
+			Channel: preference.Channel,
+		})
+	}
+
+	render.JSON(w, r, response)
+}
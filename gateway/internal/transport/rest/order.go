@@ -0,0 +1,339 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"github.com/go-chi/render"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// maxPaymentWebhookBody - предел тела вебхука платёжного провайдера
+const maxPaymentWebhookBody = 64 << 10
+
+type OrderHandler struct {
+	services transport.OrderServiceInt
+	logger   *slog.Logger
+}
+
+func NewOrderHandler(serv transport.OrderServiceInt, logger *slog.Logger) *OrderHandler {
+	return &OrderHandler{services: serv, logger: logger}
+}
+
+type ticketTypeInput struct {
+	// Kind - free, paid, early_bird или sponsor
+	Kind string `json:"kind" validate:"required,oneof=free paid early_bird sponsor" example:"early_bird"`
+	Name string `json:"name" validate:"required,max=100" example:"Ранняя регистрация"`
+	// Price - цена в копейках, у бесплатных билетов 0
+	Price int64 `json:"price" validate:"min=0" example:"150000"`
+	// Currency - код валюты ISO 4217, у бесплатных билетов не нужен
+	Currency string `json:"currency" validate:"omitempty,len=3" example:"RUB"`
+	Quantity int    `json:"quantity" validate:"required,min=1,max=100000" example:"50"`
+	// SalesEndAt - до какого момента продаются билеты, для early_bird обязателен
+	SalesEndAt *time.Time `json:"sales_end_at" example:"2024-02-20T00:00:00+03:00"`
+}
+
+type orderInput struct {
+	TicketTypeId int `json:"ticket_type_id" validate:"required,min=1" example:"4"`
+}
+
+type TicketTypeResponse struct {
+	Id       int    `json:"id" example:"4"`
+	EventId  int    `json:"event_id" example:"10"`
+	Kind     string `json:"kind" example:"early_bird"`
+	Name     string `json:"name" example:"Ранняя регистрация"`
+	Price    int64  `json:"price" example:"150000"`
+	Currency string `json:"currency,omitempty" example:"RUB"`
+	Quantity int    `json:"quantity" example:"50"`
+	// Available - сколько билетов ещё можно заказать
+	Available  int        `json:"available" example:"12"`
+	SalesEndAt *time.Time `json:"sales_end_at,omitempty" example:"2024-02-20T00:00:00+03:00"`
+}
+
+type TicketTypesOkResponse struct {
+	Status      string               `json:"status" example:"ok"`
+	TicketTypes []TicketTypeResponse `json:"ticket_types"`
+}
+
+type OrderResponse struct {
+	Id           int    `json:"id" example:"31"`
+	EventId      int    `json:"event_id" example:"10"`
+	TicketTypeId int    `json:"ticket_type_id" example:"4"`
+	UserId       int    `json:"user_id" example:"123"`
+	Amount       int64  `json:"amount" example:"150000"`
+	Currency     string `json:"currency,omitempty" example:"RUB"`
+	// Status - pending, paid, refunded или expired
+	Status string `json:"status" example:"pending"`
+	// PaymentUrl - страница оплаты, пока заказ не оплачен
+	PaymentUrl string     `json:"payment_url,omitempty" example:"https://pay.example.com/checkout/2d8f"`
+	ExpiresAt  time.Time  `json:"expires_at" example:"2024-02-20T13:15:00+03:00"`
+	PaidAt     *time.Time `json:"paid_at,omitempty" example:"2024-02-20T13:05:00+03:00"`
+	RefundedAt *time.Time `json:"refunded_at,omitempty" example:"2024-02-22T10:00:00+03:00"`
+	CreatedAt  time.Time  `json:"created_at" example:"2024-02-20T13:00:00+03:00"`
+}
+
+type OrderOkResponse struct {
+	Status string        `json:"status" example:"ok"`
+	Order  OrderResponse `json:"order"`
+}
+
+type OrdersOkResponse struct {
+	Status string          `json:"status" example:"ok"`
+	Orders []OrderResponse `json:"orders"`
+}
+
+func newOrderResponse(order models.Order) OrderResponse {
+	response := OrderResponse{
+		Id:           order.ID,
+		EventId:      order.EventID,
+		TicketTypeId: order.TicketTypeID,
+		UserId:       order.UserID,
+		Amount:       order.Amount,
+		Currency:     order.Currency,
+		Status:       order.Status,
+		ExpiresAt:    order.ExpiresAt,
+		PaidAt:       order.PaidAt,
+		RefundedAt:   order.RefundedAt,
+		CreatedAt:    order.CreatedAt,
+	}
+	if order.Status == models.OrderStatusPending {
+		response.PaymentUrl = order.PaymentURL
+	}
+
+	return response
+}
+
+func newOrdersResponse(orders []models.Order) OrdersOkResponse {
+	response := OrdersOkResponse{Status: "ok", Orders: make([]OrderResponse, 0, len(orders))}
+	for _, order := range orders {
+		response.Orders = append(response.Orders, newOrderResponse(order))
+	}
+
+	return response
+}
+
+// Создание типа билета
+// @Summary Создание типа билета на мероприятие
+// @Description Только для организатора. Пока у мероприятия есть билеты, записаться на него можно только
+// @Description с оплаченным заказом. Бесплатный билет стоит 0, платный, ранний и спонсорский - больше 0
+// @Description в указанной валюте. Ранние билеты продаются до sales_end_at, который раньше начала мероприятия.
+// @Tags Билеты
+// @Param id path int true "Идентификатор мероприятия"
+// @Param Request body ticketTypeInput true "Тип билета"
+// @Success 200 {object} IdResponse "Тип билета создан"
+// @Failure 201 {object} ErrResponse "Ошибка при создании типа билета"
+// @Router /api/v1/events/{id}/ticket-types [post]
+func (h *OrderHandler) CreateTicketType(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input ticketTypeInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	ticketTypeID, err := h.services.CreateTicketType(currentUserID(r), models.TicketType{
+		EventID:    id,
+		Kind:       input.Kind,
+		Name:       input.Name,
+		Price:      input.Price,
+		Currency:   input.Currency,
+		Quantity:   input.Quantity,
+		SalesEndAt: input.SalesEndAt,
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, IdResponse{Status: "ok", Id: ticketTypeID})
+}
+
+// Типы билетов
+// @Summary Типы билетов мероприятия
+// @Tags Билеты
+// @Param id path int true "Идентификатор мероприятия"
+// @Success 200 {object} TicketTypesOkResponse "Типы билетов"
+// @Failure 201 {object} ErrResponse "Ошибка при получении типов билетов"
+// @Router /api/v1/events/{id}/ticket-types [get]
+func (h *OrderHandler) TicketTypes(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	ticketTypes, err := h.services.TicketTypes(id)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := TicketTypesOkResponse{Status: "ok", TicketTypes: make([]TicketTypeResponse, 0, len(ticketTypes))}
+	for _, ticketType := range ticketTypes {
+		response.TicketTypes = append(response.TicketTypes, TicketTypeResponse{
+			Id:         ticketType.ID,
+			EventId:    ticketType.EventID,
+			Kind:       ticketType.Kind,
+			Name:       ticketType.Name,
+			Price:      ticketType.Price,
+			Currency:   ticketType.Currency,
+			Quantity:   ticketType.Quantity,
+			Available:  ticketType.Available(),
+			SalesEndAt: ticketType.SalesEndAt,
+		})
+	}
+
+	render.JSON(w, r, response)
+}
+
+// Заказ билета
+// @Summary Заказ билета на мероприятие
+// @Description Заказ удерживает билет, пока его не оплатят по payment_url или не истечёт срок оплаты.
+// @Description Бесплатный заказ оплачивается сразу. Оплаченный заказ записывает на мероприятие.
+// @Tags Билеты
+// @Param id path int true "Идентификатор мероприятия"
+// @Param Request body orderInput true "Тип билета"
+// @Success 200 {object} OrderOkResponse "Заказ создан"
+// @Failure 201 {object} ErrResponse "Ошибка при создании заказа"
+// @Router /api/v1/events/{id}/orders [post]
+func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input orderInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	order, err := h.services.CreateOrder(r.Context(), currentUserID(r), id, input.TicketTypeId)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, OrderOkResponse{Status: "ok", Order: newOrderResponse(order)})
+}
+
+// Заказы мероприятия
+// @Summary Заказы билетов на мероприятие
+// @Description Только для организатора.
+// @Tags Билеты
+// @Param id path int true "Идентификатор мероприятия"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} OrdersOkResponse "Заказы, новые первыми"
+// @Failure 201 {object} ErrResponse "Ошибка при получении заказов"
+// @Router /api/v1/events/{id}/orders [get]
+func (h *OrderHandler) EventOrders(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+	limit, offset := pagination(r)
+
+	orders, err := h.services.EventOrders(currentUserID(r), id, limit, offset)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, newOrdersResponse(orders))
+}
+
+// Мои заказы
+// @Summary Заказы билетов текущего пользователя
+// @Tags Билеты
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} OrdersOkResponse "Заказы, новые первыми"
+// @Failure 201 {object} ErrResponse "Ошибка при получении заказов"
+// @Router /api/v1/orders [get]
+func (h *OrderHandler) Orders(w http.ResponseWriter, r *http.Request) {
+	limit, offset := pagination(r)
+
+	orders, err := h.services.UserOrders(currentUserID(r), limit, offset)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, newOrdersResponse(orders))
+}
+
+// Заказ
+// @Summary Заказ билета
+// @Description Доступен покупателю и организатору мероприятия.
+// @Tags Билеты
+// @Param id path int true "Идентификатор заказа"
+// @Success 200 {object} OrderOkResponse "Заказ"
+// @Failure 201 {object} ErrResponse "Ошибка при получении заказа"
+// @Router /api/v1/orders/{id} [get]
+func (h *OrderHandler) Order(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	order, err := h.services.Order(currentUserID(r), id)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, OrderOkResponse{Status: "ok", Order: newOrderResponse(order)})
+}
+
+// Возврат заказа
+// @Summary Возврат денег за оплаченный заказ
+// @Description Только для организатора. Билет снова поступает в продажу, запись покупателя на мероприятие отменяется.
+// @Tags Билеты
+// @Param id path int true "Идентификатор заказа"
+// @Success 200 {object} OrderOkResponse "Деньги возвращены"
+// @Failure 201 {object} ErrResponse "Ошибка при возврате"
+// @Router /api/v1/orders/{id}/refund [post]
+func (h *OrderHandler) RefundOrder(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	order, err := h.services.RefundOrder(r.Context(), currentUserID(r), id)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, OrderOkResponse{Status: "ok", Order: newOrderResponse(order)})
+}
+
+// Вебхук платёжного провайдера
+// @Summary Приём извещений об исходе платежей
+// @Description Вызывается платёжным провайдером. Извещение эквайринга без верной подписи X-Payment-Signature
+// @Description отклоняется. Повторные извещения ничего не меняют.
+// @Tags Билеты
+// @Param X-Payment-Signature header string false "HMAC-SHA256 тела запроса в hex"
+// @Success 200 {object} StatusResponse "Извещение принято"
+// @Failure 201 {object} ErrResponse "Неверная подпись или неизвестный платёж"
+// @Router /api/v1/payments/webhook [post]
+func (h *OrderHandler) PaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPaymentWebhookBody))
+	if err != nil {
+		h.logger.Error("failed to read payment webhook", slog.String("error", err.Error()))
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+
+		return
+	}
+
+	if err := h.services.HandlePaymentWebhook(r.Context(), r.Header, body); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
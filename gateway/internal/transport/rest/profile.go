@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"dev_meets/internal/domain/models"
 	"dev_meets/internal/transport"
 	"github.com/go-chi/render"
 	"log/slog"
@@ -18,7 +19,22 @@ func NewProfileHandler(serv transport.UserServiceInt, logger *slog.Logger) *Prof
 }
 
 type ProfileResponse struct {
-	Email string `json:"email" example:"email@gmail.com"`
+	Email     string   `json:"email" example:"email@gmail.com"`
+	Name      string   `json:"name" example:"Иван Петров"`
+	Username  string   `json:"username" example:"ivan_petrov"`
+	City      string   `json:"city" example:"Москва"`
+	Bio       string   `json:"bio" example:"Пишу на Go, люблю распределённые системы"`
+	Skills    []string `json:"skills" example:"go,postgresql"`
+	Seniority string   `json:"seniority" example:"senior"`
+}
+
+type profileInput struct {
+	Name      string   `json:"name" validate:"max=200" example:"Иван Петров"`
+	Username  string   `json:"username" validate:"max=33" example:"ivan_petrov"`
+	City      string   `json:"city" validate:"max=100" example:"Москва"`
+	Bio       string   `json:"bio" validate:"max=2000" example:"Пишу на Go, люблю распределённые системы"`
+	Skills    []string `json:"skills" validate:"max=30,dive,max=50" example:"go,postgresql"`
+	Seniority string   `json:"seniority" validate:"omitempty,oneof=junior middle senior lead" example:"senior"`
 }
 
 type OkResponse struct {
@@ -46,8 +62,43 @@ func (h *ProfileHandler) PersonalProfile(w http.ResponseWriter, r *http.Request)
 	response := OkResponse{
 		Status: "ok",
 		Profile: ProfileResponse{
-			Email: user.Email,
+			Email:     user.Email,
+			Name:      user.Name,
+			Username:  user.Username,
+			City:      user.City,
+			Bio:       user.Bio,
+			Skills:    user.Skills,
+			Seniority: user.Seniority,
 		},
 	}
 	render.JSON(w, r, response)
 }
+
+// Изменение профиля текущего пользователя
+// @Summary Изменение профиля текущего пользователя
+// @Tags Пользователь
+// @Param Request body profileInput true "Профиль"
+// @Success 200 {object} StatusResponse "Профиль изменён"
+// @Failure 201 {object} ErrResponse "Ошибка при изменении профиля"
+// @Router /api/v1/personal-profile [put]
+func (h *ProfileHandler) UpdatePersonalProfile(w http.ResponseWriter, r *http.Request) {
+	var input profileInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	err := h.services.UpdateProfile(currentUserID(r), models.Profile{
+		Name:      input.Name,
+		Username:  input.Username,
+		City:      input.City,
+		Bio:       input.Bio,
+		Skills:    input.Skills,
+		Seniority: input.Seniority,
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
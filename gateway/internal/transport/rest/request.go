@@ -0,0 +1,153 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"encoding/base64"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRadiusKm = 10
+)
+
+var errInvalidNear = errors.New("near must be in lat,lon format")
+
+// decodeInput читает тело запроса в input и валидирует его.
+// При ошибке отвечает клиенту wrong_params и возвращает false
+func decodeInput(w http.ResponseWriter, r *http.Request, logger *slog.Logger, input any) bool {
+	err := render.DecodeJSON(r.Body, input)
+	if errors.Is(err, io.EOF) {
+		logger.Error("request body is empty")
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+
+		return false
+	}
+	if err != nil {
+		logger.Error("failed to decode request body")
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+
+		return false
+	}
+
+	if err := validator.New().Struct(input); err != nil {
+		logger.Error("invalid params", slog.String("error", err.Error()))
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+
+		return false
+	}
+
+	return true
+}
+
+// idParam достаёт числовой параметр пути. При ошибке отвечает клиенту wrong_params
+func idParam(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, name))
+	if err != nil || id <= 0 {
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+
+		return 0, false
+	}
+
+	return id, true
+}
+
+// pagination читает limit и offset из query-параметров
+func pagination(r *http.Request) (int, int) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	return limit, offset
+}
+
+// cursorPagination читает limit и непрозрачный курсор из query-параметров.
+// Пустой курсор означает первую страницу, неразборчивый - ошибку
+func cursorPagination(r *http.Request) (int, int, bool) {
+	limit, _ := pagination(r)
+
+	value := r.URL.Query().Get("cursor")
+	if value == "" {
+		return limit, 0, true
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	cursor, err := strconv.Atoi(string(raw))
+	if err != nil || cursor <= 0 {
+		return 0, 0, false
+	}
+
+	return limit, cursor, true
+}
+
+// nextCursor возвращает курсор следующей страницы или пустую строку, если страница последняя
+func nextCursor(lastID, count, limit int) string {
+	if count < limit {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(lastID)))
+}
+
+// geoFilter читает параметры near=lat,lon и radius_km. Без near возвращает nil
+func geoFilter(r *http.Request) (*models.GeoFilter, error) {
+	near := r.URL.Query().Get("near")
+	if near == "" {
+		return nil, nil
+	}
+
+	lat, lon, ok := strings.Cut(near, ",")
+	if !ok {
+		return nil, errInvalidNear
+	}
+
+	var filter models.GeoFilter
+	var err error
+	if filter.Point.Lat, err = strconv.ParseFloat(strings.TrimSpace(lat), 64); err != nil {
+		return nil, errInvalidNear
+	}
+	if filter.Point.Lon, err = strconv.ParseFloat(strings.TrimSpace(lon), 64); err != nil {
+		return nil, errInvalidNear
+	}
+
+	filter.RadiusKm = defaultRadiusKm
+	if radius := r.URL.Query().Get("radius_km"); radius != "" {
+		if filter.RadiusKm, err = strconv.ParseFloat(radius, 64); err != nil {
+			return nil, errInvalidNear
+		}
+	}
+
+	return &filter, nil
+}
+
+// timeParam разбирает необязательный параметр в формате RFC 3339
+func timeParam(value string) (*time.Time, bool) {
+	if value == "" {
+		return nil, true
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, false
+	}
+
+	return &t, true
+}
@@ -1,5 +1,163 @@
 package rest
 
+import (
+	"dev_meets/internal/service"
+	"dev_meets/internal/storage"
+	"errors"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
 type ErrResponse struct {
-	Status string `json:"status" example:"wrong_params, internal_server_error"`
+	Status string `json:"status" example:"wrong_params, forbidden, not_found, conflict, too_many_requests, internal_server_error"`
+}
+
+type StatusResponse struct {
+	Status string `json:"status" example:"ok"`
+}
+
+type IdResponse struct {
+	Status string `json:"status" example:"ok"`
+	Id     int    `json:"id" example:"123"`
+}
+
+var notFoundErrors = []error{
+	storage.ErrUserNotFound,
+	storage.ErrEventNotFound,
+	storage.ErrCFPNotFound,
+	storage.ErrTalkNotFound,
+	storage.ErrSlotNotFound,
+	storage.ErrSpeakerNotFound,
+	storage.ErrVenueNotFound,
+	storage.ErrGroupNotFound,
+	storage.ErrRSVPNotFound,
+	storage.ErrCommentNotFound,
+	storage.ErrNotificationNotFound,
+	storage.ErrJobNotFound,
+	storage.ErrWebhookNotFound,
+	storage.ErrTelegramAccountNotFound,
+	storage.ErrConversationNotFound,
+	storage.ErrBlockNotFound,
+	storage.ErrMatchProfileNotFound,
+	storage.ErrMatchRequestNotFound,
+	storage.ErrFollowNotFound,
+	storage.ErrReportTargetNotFound,
+	storage.ErrReportNotFound,
+	storage.ErrDataExportNotFound,
+	storage.ErrEmailChangeNotFound,
+	storage.ErrInvitationNotFound,
+	storage.ErrTicketTypeNotFound,
+	storage.ErrOrderNotFound,
+}
+
+var conflictErrors = []error{
+	storage.ErrUserExists,
+	storage.ErrCFPExists,
+	storage.ErrReviewExists,
+	storage.ErrTalkNotPending,
+	storage.ErrSpeakerExists,
+	storage.ErrUsernameTaken,
+	storage.ErrEmailTaken,
+	storage.ErrRSVPCancelled,
+	storage.ErrAlreadyCheckedIn,
+	storage.ErrJobNotDead,
+	storage.ErrEventCancelled,
+	service.ErrTooManyWebhooks,
+	service.ErrTelegramDisabled,
+	service.ErrUserBlocked,
+	storage.ErrMatchRequestExists,
+	storage.ErrMatchRequestNotPending,
+	storage.ErrReportExists,
+	storage.ErrReportNotOpen,
+	storage.ErrInvitationUsedUp,
+	storage.ErrTicketsSoldOut,
+	storage.ErrOrderExists,
+	storage.ErrOrderNotPending,
+	storage.ErrOrderNotPaid,
+}
+
+var wrongParamsErrors = []error{
+	service.ErrInvalidEventDates,
+	service.ErrInvalidDeadline,
+	service.ErrCFPClosed,
+	service.ErrOwnTalkReview,
+	service.ErrInvalidSlotKind,
+	service.ErrInvalidSlotTimes,
+	service.ErrInvalidGeoFilter,
+	service.ErrInvalidProfile,
+	service.ErrInvalidSearchQuery,
+	service.ErrEventFinished,
+	service.ErrInvalidTicket,
+	service.ErrTicketWrongEvent,
+	service.ErrEventNotFinished,
+	service.ErrInvalidRating,
+	service.ErrSlotNotTalk,
+	service.ErrInvalidComment,
+	service.ErrInvalidCommentSubject,
+	service.ErrInvalidNotificationPreference,
+	service.ErrInvalidStreamTopic,
+	service.ErrInvalidJobFilter,
+	service.ErrInvalidWebhook,
+	service.ErrInvalidConversation,
+	service.ErrInvalidMessage,
+	service.ErrInvalidBlock,
+	service.ErrInvalidMatchGoal,
+	service.ErrInvalidMatchMessage,
+	service.ErrInvalidMatchFilter,
+	service.ErrInvalidFollow,
+	service.ErrInvalidReport,
+	service.ErrInvalidModerationAction,
+	service.ErrInvalidReportFilter,
+	service.ErrInvalidRole,
+	service.ErrOwnAccount,
+	service.ErrInvalidMerge,
+	service.ErrInvalidSuspension,
+	service.ErrInvalidAdminFilter,
+	service.ErrInvalidAuditFilter,
+	service.ErrInvalidEmailChange,
+	service.ErrInvalidInvitation,
+	service.ErrInvalidInviteLink,
+	service.ErrInvalidTicketType,
+	service.ErrTicketSalesClosed,
+}
+
+// errStatus сопоставляет ошибку сервиса со статусом ответа
+func errStatus(err error) string {
+	switch {
+	case errors.Is(err, service.ErrForbidden), errors.Is(err, service.ErrUserSuspended),
+		errors.Is(err, service.ErrInvalidCredentials), errors.Is(err, service.ErrInvitationRequired),
+		errors.Is(err, service.ErrTicketRequired):
+		return "forbidden"
+	case errors.Is(err, service.ErrRateLimited):
+		return "too_many_requests"
+	case isOneOf(err, notFoundErrors):
+		return "not_found"
+	case isOneOf(err, conflictErrors):
+		return "conflict"
+	case isOneOf(err, wrongParamsErrors):
+		return "wrong_params"
+	default:
+		return "internal_server_error"
+	}
+}
+
+func isOneOf(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// renderError логирует внутренние ошибки и отвечает клиенту статусом из errStatus
+func renderError(w http.ResponseWriter, r *http.Request, logger *slog.Logger, err error) {
+	status := errStatus(err)
+	if status == "internal_server_error" {
+		logger.Error("internal_server_error", slog.String("error", err.Error()))
+	}
+
+	render.JSON(w, r, ErrResponse{Status: status})
 }
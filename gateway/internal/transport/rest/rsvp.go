@@ -0,0 +1,271 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/service"
+	"dev_meets/internal/storage"
+	"dev_meets/internal/transport"
+	"encoding/base64"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/skip2/go-qrcode"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	qrSize = 512
+)
+
+type RSVPHandler struct {
+	services transport.RSVPServiceInt
+	logger   *slog.Logger
+}
+
+func NewRSVPHandler(serv transport.RSVPServiceInt, logger *slog.Logger) *RSVPHandler {
+	return &RSVPHandler{services: serv, logger: logger}
+}
+
+type checkInInput struct {
+	Payload string `json:"payload" validate:"required" example:"DM1.eyJ0IjoxLCJlIjoxLCJ1IjoxMjMsImlhdCI6MTcwOTI5MTYwMH0.c2ln..."`
+}
+
+type TicketResponse struct {
+	Id      int    `json:"id" example:"17"`
+	EventId int    `json:"event_id" example:"1"`
+	Payload string `json:"payload" example:"DM1.eyJ0IjoxLCJlIjoxLCJ1IjoxMjMsImlhdCI6MTcwOTI5MTYwMH0.c2ln..."`
+	QRPng   string `json:"qr_png" example:"iVBORw0KGgoAAAANSUhEUgAA..."`
+}
+
+type TicketOkResponse struct {
+	Status string         `json:"status" example:"ok"`
+	Ticket TicketResponse `json:"ticket"`
+}
+
+type AttendanceResponse struct {
+	Going     int `json:"going" example:"120"`
+	CheckedIn int `json:"checked_in" example:"87"`
+}
+
+type AttendanceOkResponse struct {
+	Status     string             `json:"status" example:"ok"`
+	Attendance AttendanceResponse `json:"attendance"`
+}
+
+type CheckInOkResponse struct {
+	Status      string             `json:"status" example:"ok"`
+	UserId      int                `json:"user_id" example:"123"`
+	CheckedInAt *time.Time         `json:"checked_in_at,omitempty" example:"2024-03-01T18:55:00+03:00"`
+	Attendance  AttendanceResponse `json:"attendance"`
+}
+
+type PublicKeyOkResponse struct {
+	Status    string `json:"status" example:"ok"`
+	Algorithm string `json:"algorithm" example:"Ed25519"`
+	PublicKey string `json:"public_key" example:"MCowBQYDK2VwAyEA..."`
+}
+
+// Запись на мероприятие
+// @Summary Запись на мероприятие, в ответе - билет с подписанным QR-кодом
+// @Tags Билеты
+// @Param id path int true "Идентификатор мероприятия"
+// @Success 200 {object} TicketOkResponse "Билет"
+// @Failure 201 {object} ErrResponse "Ошибка при записи на мероприятие"
+// @Router /api/v1/events/{id}/rsvp [post]
+func (h *RSVPHandler) RSVP(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	t, err := h.services.RSVP(currentUserID(r), eventID)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	h.renderTicket(w, r, t)
+}
+
+// Отмена записи на мероприятие
+// @Summary Отмена записи на мероприятие, билет перестаёт действовать
+// @Tags Билеты
+// @Param id path int true "Идентификатор мероприятия"
+// @Success 200 {object} StatusResponse "Запись отменена"
+// @Failure 201 {object} ErrResponse "Ошибка при отмене записи"
+// @Router /api/v1/events/{id}/rsvp [delete]
+func (h *RSVPHandler) CancelRSVP(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.CancelRSVP(currentUserID(r), eventID); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Билет на мероприятие
+// @Summary Билет текущего пользователя: строка для QR-кода и PNG в base64
+// @Tags Билеты
+// @Param id path int true "Идентификатор мероприятия"
+// @Success 200 {object} TicketOkResponse "Билет"
+// @Failure 201 {object} ErrResponse "Билет не найден"
+// @Router /api/v1/events/{id}/ticket [get]
+func (h *RSVPHandler) Ticket(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	t, err := h.services.Ticket(currentUserID(r), eventID)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	h.renderTicket(w, r, t)
+}
+
+// QR-код билета
+// @Summary QR-код билета текущего пользователя в формате PNG
+// @Tags Билеты
+// @Produce png
+// @Param id path int true "Идентификатор мероприятия"
+// @Success 200 {file} binary "QR-код"
+// @Failure 201 {object} ErrResponse "Билет не найден"
+// @Router /api/v1/events/{id}/ticket.png [get]
+func (h *RSVPHandler) TicketQR(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	t, err := h.services.Ticket(currentUserID(r), eventID)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	png, err := qrcode.Encode(t.Payload, qrcode.Medium, qrSize)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Write(png)
+}
+
+// Отметка прихода
+// @Summary Проверка билета на входе и отметка прихода (только организатор)
+// @Description Статусы ошибок: invalid_ticket - подпись не прошла проверку, wrong_event - билет на другое мероприятие,
+// @Description ticket_cancelled - запись отменена, already_checked_in - билет уже использован.
+// @Tags Билеты
+// @Param id path int true "Идентификатор мероприятия"
+// @Param Request body checkInInput true "Содержимое QR-кода"
+// @Success 200 {object} CheckInOkResponse "Приход отмечен"
+// @Failure 201 {object} ErrResponse "Билет не прошёл проверку"
+// @Router /api/v1/events/{id}/check-in [post]
+func (h *RSVPHandler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input checkInInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	rsvp, attendance, err := h.services.CheckIn(currentUserID(r), eventID, input.Payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTicket), errors.Is(err, storage.ErrRSVPNotFound):
+			render.JSON(w, r, ErrResponse{Status: "invalid_ticket"})
+		case errors.Is(err, service.ErrTicketWrongEvent):
+			render.JSON(w, r, ErrResponse{Status: "wrong_event"})
+		case errors.Is(err, storage.ErrRSVPCancelled):
+			render.JSON(w, r, ErrResponse{Status: "ticket_cancelled"})
+		case errors.Is(err, storage.ErrAlreadyCheckedIn):
+			render.JSON(w, r, CheckInOkResponse{
+				Status:      "already_checked_in",
+				UserId:      rsvp.UserID,
+				CheckedInAt: rsvp.CheckedInAt,
+			})
+		default:
+			renderError(w, r, h.logger, err)
+		}
+
+		return
+	}
+
+	render.JSON(w, r, CheckInOkResponse{
+		Status:      "ok",
+		UserId:      rsvp.UserID,
+		CheckedInAt: rsvp.CheckedInAt,
+		Attendance:  newAttendanceResponse(attendance),
+	})
+}
+
+// Посещаемость
+// @Summary Текущее число записавшихся и пришедших (только организатор)
+// @Tags Билеты
+// @Param id path int true "Идентификатор мероприятия"
+// @Success 200 {object} AttendanceOkResponse "Посещаемость"
+// @Failure 201 {object} ErrResponse "Ошибка при получении посещаемости"
+// @Router /api/v1/events/{id}/attendance [get]
+func (h *RSVPHandler) Attendance(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	attendance, err := h.services.Attendance(currentUserID(r), eventID)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, AttendanceOkResponse{Status: "ok", Attendance: newAttendanceResponse(attendance)})
+}
+
+// Ключ проверки билетов
+// @Summary Публичный ключ Ed25519 для офлайн-проверки подписи билетов
+// @Description Билет имеет вид DM1.<claims>.<signature>: подпись Ed25519 строки "DM1.<claims>", части в base64url.
+// @Tags Билеты
+// @Success 200 {object} PublicKeyOkResponse "Публичный ключ (сырые 32 байта в base64)"
+// @Router /api/v1/tickets/public-key [get]
+func (h *RSVPHandler) TicketPublicKey(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, PublicKeyOkResponse{
+		Status:    "ok",
+		Algorithm: "Ed25519",
+		PublicKey: base64.StdEncoding.EncodeToString(h.services.TicketPublicKey()),
+	})
+}
+
+func (h *RSVPHandler) renderTicket(w http.ResponseWriter, r *http.Request, t models.Ticket) {
+	png, err := qrcode.Encode(t.Payload, qrcode.Medium, qrSize)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, TicketOkResponse{
+		Status: "ok",
+		Ticket: TicketResponse{
+			Id:      t.ID,
+			EventId: t.EventID,
+			Payload: t.Payload,
+			QRPng:   base64.StdEncoding.EncodeToString(png),
+		},
+	})
+}
+
+func newAttendanceResponse(attendance models.Attendance) AttendanceResponse {
+	return AttendanceResponse{Going: attendance.Going, CheckedIn: attendance.CheckedIn}
+}
@@ -0,0 +1,95 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type SearchHandler struct {
+	services transport.SearchServiceInt
+	logger   *slog.Logger
+}
+
+func NewSearchHandler(serv transport.SearchServiceInt, logger *slog.Logger) *SearchHandler {
+	return &SearchHandler{services: serv, logger: logger}
+}
+
+type SearchResultResponse struct {
+	Type  string `json:"type" example:"event"`
+	Id    int    `json:"id" example:"1"`
+	Title string `json:"title" example:"Go meetup #12"`
+	// Snippet - фрагмент описания в HTML: текст экранирован, совпадения обёрнуты в mark
+	Snippet string  `json:"snippet" example:"Доклады про <mark>конкурентность</mark> в Go"`
+	Rank    float64 `json:"rank" example:"0.42"`
+}
+
+type SearchOkResponse struct {
+	Status  string                 `json:"status" example:"ok"`
+	Results []SearchResultResponse `json:"results"`
+}
+
+// Поиск
+// @Summary Полнотекстовый поиск по мероприятиям, сообществам и разработчикам
+// @Description Названия весят больше описаний, опечатки в названиях и именах находятся по триграммам.
+// @Description Фильтр, неприменимый к типу (даты для сообществ и разработчиков, навык для мероприятий и сообществ), исключает этот тип из выдачи.
+// @Tags Поиск
+// @Param q query string true "Поисковый запрос"
+// @Param type query string false "Типы через запятую: event, group, developer"
+// @Param city query string false "Город"
+// @Param date_from query string false "Мероприятия с даты (RFC 3339)"
+// @Param date_to query string false "Мероприятия до даты (RFC 3339)"
+// @Param skill query string false "Навык разработчика"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} SearchOkResponse "Результаты поиска, сниппеты размечены тегом mark"
+// @Failure 201 {object} ErrResponse "Ошибка при поиске"
+// @Router /api/v1/search [get]
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	limit, offset := pagination(r)
+
+	query := models.SearchQuery{
+		Query:  params.Get("q"),
+		City:   strings.TrimSpace(params.Get("city")),
+		Skill:  strings.TrimSpace(params.Get("skill")),
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	if types := params.Get("type"); types != "" {
+		query.Types = strings.Split(types, ",")
+	}
+
+	var ok bool
+	if query.DateFrom, ok = timeParam(params.Get("date_from")); !ok {
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+		return
+	}
+	if query.DateTo, ok = timeParam(params.Get("date_to")); !ok {
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+		return
+	}
+
+	results, err := h.services.Search(query)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := SearchOkResponse{Status: "ok", Results: make([]SearchResultResponse, 0, len(results))}
+	for _, result := range results {
+		response.Results = append(response.Results, SearchResultResponse{
+			Type:    result.Type,
+			Id:      result.ID,
+			Title:   result.Title,
+			Snippet: result.Snippet,
+			Rank:    result.Rank,
+		})
+	}
+
+	render.JSON(w, r, response)
+}
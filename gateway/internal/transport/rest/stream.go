@@ -0,0 +1,227 @@
+package rest
+
+import (
+	"context"
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"encoding/json"
+	"fmt"
+	"github.com/go-chi/render"
+	"github.com/gorilla/websocket"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	heartbeatInterval = 25 * time.Second
+	sseRetry          = 3 * time.Second
+	wsWriteTimeout    = 10 * time.Second
+	wsReadLimit       = 512
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+type StreamHandler struct {
+	services transport.StreamServiceInt
+	logger   *slog.Logger
+}
+
+func NewStreamHandler(serv transport.StreamServiceInt, logger *slog.Logger) *StreamHandler {
+	return &StreamHandler{services: serv, logger: logger}
+}
+
+// StreamMessage - сообщение WebSocket-потока. В SSE те же поля передаются как id, event и data
+type StreamMessage struct {
+	Id   int64           `json:"id" example:"1024"`
+	Type string          `json:"type" example:"rsvp_count"`
+	Data json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+// Поток событий
+// @Summary Поток событий в реальном времени: SSE, а при заголовке Upgrade - WebSocket
+// @Description Пользователь получает свои уведомления (notification), счётчик пришедших на свои мероприятия (attendance),
+// @Description личные сообщения (message) и отметки о прочтении (message_read),
+// @Description а по темам - число записавшихся (rsvp_count) и изменения обсуждений (comment).
+// @Description Раз в 25 секунд приходит heartbeat: комментарий SSE или ping-фрейм WebSocket.
+// @Description После переподключения пропущенные события досылаются по Last-Event-ID (или параметру last_event_id).
+// @Description EventSource и WebSocket в браузере не передают заголовки, поэтому токен можно передать параметром access_token.
+// @Tags Поток событий
+// @Produce text/event-stream
+// @Param topics query string false "Темы через запятую: event:<id>, group:<id>"
+// @Param Last-Event-ID header string false "Идентификатор последнего полученного события"
+// @Param last_event_id query int false "То же, что Last-Event-ID, для WebSocket"
+// @Param access_token query string false "JWT, если нельзя передать заголовок Authorization"
+// @Success 200 {object} StreamMessage "Событие"
+// @Failure 201 {object} ErrResponse "Неверные темы"
+// @Router /api/v1/stream [get]
+func (h *StreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	var topics []string
+	if value := r.URL.Query().Get("topics"); value != "" {
+		topics = strings.Split(value, ",")
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	var after int64
+	if lastEventID != "" {
+		var err error
+		if after, err = strconv.ParseInt(lastEventID, 10, 64); err != nil || after < 0 {
+			render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+			return
+		}
+	}
+
+	sub, backlog, err := h.services.Subscribe(currentUserID(r), topics, after)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+	defer h.services.Unsubscribe(sub)
+
+	if websocket.IsWebSocketUpgrade(r) {
+		h.serveWebSocket(w, r, sub, backlog)
+		return
+	}
+
+	h.serveSSE(w, r, sub, backlog)
+}
+
+func (h *StreamHandler) serveSSE(w http.ResponseWriter, r *http.Request, sub *models.StreamSubscription, backlog []models.StreamEvent) {
+	rc := http.NewResponseController(w)
+	// Поток живёт дольше WriteTimeout сервера
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Error("failed to reset write deadline", slog.String("error", err.Error()))
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	write := func(format string, args ...any) bool {
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			return false
+		}
+
+		return rc.Flush() == nil
+	}
+
+	if !write("retry: %d\n\n", sseRetry.Milliseconds()) {
+		return
+	}
+
+	h.pump(r.Context(), sub, backlog,
+		func(event models.StreamEvent) bool {
+			return write("id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Payload)
+		},
+		func() bool {
+			return write(": ping\n\n")
+		},
+	)
+}
+
+func (h *StreamHandler) serveWebSocket(w http.ResponseWriter, r *http.Request, sub *models.StreamSubscription, backlog []models.StreamEvent) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		h.logger.Error("failed to upgrade connection", slog.String("error", err.Error()))
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// Клиент ничего не присылает, но читать нужно, чтобы обработать close и pong
+	conn.SetReadLimit(wsReadLimit)
+	conn.SetReadDeadline(time.Now().Add(2 * heartbeatInterval))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * heartbeatInterval))
+	})
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	h.pump(ctx, sub, backlog,
+		func(event models.StreamEvent) bool {
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+
+			return conn.WriteJSON(StreamMessage{Id: event.ID, Type: event.Type, Data: event.Payload}) == nil
+		},
+		func() bool {
+			return conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)) == nil
+		},
+	)
+	// Поток мог закончиться из-за остановки сервера: соединение перехвачено у http.Server,
+	// поэтому клиента нужно предупредить самим
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""),
+		time.Now().Add(wsWriteTimeout))
+}
+
+// pump отправляет клиенту пропущенные события, затем новые и heartbeat, пока клиент
+// подключён. Событие из журнала может прийти повторно через подписку - такие пропускаются
+func (h *StreamHandler) pump(
+	ctx context.Context,
+	sub *models.StreamSubscription,
+	backlog []models.StreamEvent,
+	send func(models.StreamEvent) bool,
+	heartbeat func() bool,
+) {
+	sent := make(map[int64]struct{}, len(backlog))
+	for _, event := range backlog {
+		if !send(event) {
+			return
+		}
+		sent[event.ID] = struct{}{}
+	}
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+			if _, ok := sent[event.ID]; ok {
+				continue
+			}
+			if !send(event) {
+				return
+			}
+		case <-ticker.C:
+			if !heartbeat() {
+				return
+			}
+		}
+	}
+}
+
+// tokenFromQuery подставляет токен из параметра access_token в заголовок Authorization.
+// Нужен клиентам EventSource и WebSocket в браузере, которые не умеют передавать заголовки
+func tokenFromQuery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token := r.URL.Query().Get("access_token"); token != "" && r.Header.Get("Authorization") == "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,87 @@
+package rest
+
+import (
+	"dev_meets/internal/transport"
+	"dev_meets/pkg/telegram"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type TelegramHandler struct {
+	services transport.TelegramServiceInt
+	logger   *slog.Logger
+}
+
+func NewTelegramHandler(serv transport.TelegramServiceInt, logger *slog.Logger) *TelegramHandler {
+	return &TelegramHandler{services: serv, logger: logger}
+}
+
+type TelegramLinkCodeOkResponse struct {
+	Status    string    `json:"status" example:"ok"`
+	Code      string    `json:"code" example:"3f9a1c0be47d2a65"`
+	Link      string    `json:"link,omitempty" example:"https://t.me/dev_meets_bot?start=3f9a1c0be47d2a65"`
+	ExpiresAt time.Time `json:"expires_at" example:"2024-02-20T12:15:00+03:00"`
+}
+
+// Код привязки Telegram
+// @Summary Одноразовый код для привязки Telegram-аккаунта
+// @Description Код действует 15 минут. Его нужно отправить боту командой /link <код> или открыть ссылку link.
+// @Description После привязки можно записываться на мероприятия кнопкой под анонсом в Telegram.
+// @Description Новый код отменяет прежний.
+// @Tags Telegram
+// @Success 200 {object} TelegramLinkCodeOkResponse "Код привязки"
+// @Failure 201 {object} ErrResponse "Бот не настроен"
+// @Router /api/v1/telegram/link-code [post]
+func (h *TelegramHandler) CreateTelegramLinkCode(w http.ResponseWriter, r *http.Request) {
+	code, err := h.services.CreateTelegramLinkCode(currentUserID(r))
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, TelegramLinkCodeOkResponse{
+		Status:    "ok",
+		Code:      code.Code,
+		Link:      code.Link,
+		ExpiresAt: code.ExpiresAt,
+	})
+}
+
+// Отвязка Telegram
+// @Summary Отвязка Telegram-аккаунта
+// @Tags Telegram
+// @Success 200 {object} StatusResponse "Аккаунт отвязан"
+// @Failure 201 {object} ErrResponse "Аккаунт не привязан"
+// @Router /api/v1/telegram/link [delete]
+func (h *TelegramHandler) UnlinkTelegram(w http.ResponseWriter, r *http.Request) {
+	if err := h.services.UnlinkTelegram(currentUserID(r)); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Вебхук бота
+// @Summary Приём обновлений от Telegram в режиме webhook
+// @Description Вызывается серверами Telegram. Запрос без верного заголовка X-Telegram-Bot-Api-Secret-Token отклоняется.
+// @Tags Telegram
+// @Param X-Telegram-Bot-Api-Secret-Token header string true "Секрет вебхука"
+// @Success 200 {object} StatusResponse "Обновление принято"
+// @Failure 201 {object} ErrResponse "Неверный секрет или бот работает в режиме polling"
+// @Router /api/v1/telegram/webhook [post]
+func (h *TelegramHandler) TelegramWebhook(w http.ResponseWriter, r *http.Request) {
+	var update telegram.Update
+	if !decodeInput(w, r, h.logger, &update) {
+		return
+	}
+
+	if err := h.services.HandleTelegramWebhook(r.Context(), r.Header.Get(telegram.SecretTokenHeader), update); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
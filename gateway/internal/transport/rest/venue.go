@@ -0,0 +1,192 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type VenueHandler struct {
+	services transport.VenueServiceInt
+	logger   *slog.Logger
+}
+
+func NewVenueHandler(serv transport.VenueServiceInt, logger *slog.Logger) *VenueHandler {
+	return &VenueHandler{services: serv, logger: logger}
+}
+
+type venueInput struct {
+	Name               string  `json:"name" validate:"required,max=200" example:"Офис Яндекса"`
+	Address            string  `json:"address" validate:"required,max=500" example:"Москва, ул. Льва Толстого, 16"`
+	Lat                float64 `json:"lat" validate:"min=-90,max=90" example:"55.7338"`
+	Lon                float64 `json:"lon" validate:"min=-180,max=180" example:"37.5879"`
+	Capacity           int     `json:"capacity" validate:"min=0" example:"120"`
+	AccessibilityNotes string  `json:"accessibility_notes" example:"Пандус у главного входа, лифт"`
+	HostCompany        string  `json:"host_company" example:"Яндекс"`
+}
+
+type VenueResponse struct {
+	Id                 int      `json:"id" example:"5"`
+	Name               string   `json:"name" example:"Офис Яндекса"`
+	Address            string   `json:"address" example:"Москва, ул. Льва Толстого, 16"`
+	Lat                float64  `json:"lat" example:"55.7338"`
+	Lon                float64  `json:"lon" example:"37.5879"`
+	Capacity           int      `json:"capacity" example:"120"`
+	AccessibilityNotes string   `json:"accessibility_notes" example:"Пандус у главного входа, лифт"`
+	HostCompany        string   `json:"host_company" example:"Яндекс"`
+	DistanceKm         *float64 `json:"distance_km,omitempty" example:"1.7"`
+}
+
+type VenueOkResponse struct {
+	Status string        `json:"status" example:"ok"`
+	Venue  VenueResponse `json:"venue"`
+}
+
+type VenuesOkResponse struct {
+	Status string          `json:"status" example:"ok"`
+	Venues []VenueResponse `json:"venues"`
+}
+
+func newVenueResponse(venue models.Venue) VenueResponse {
+	return VenueResponse{
+		Id:                 venue.ID,
+		Name:               venue.Name,
+		Address:            venue.Address,
+		Lat:                venue.Location.Lat,
+		Lon:                venue.Location.Lon,
+		Capacity:           venue.Capacity,
+		AccessibilityNotes: venue.AccessibilityNotes,
+		HostCompany:        venue.HostCompany,
+		DistanceKm:         venue.DistanceKm,
+	}
+}
+
+// Создание площадки
+// @Summary Создание площадки для офлайн-мероприятий
+// @Tags Площадки
+// @Param Request body venueInput true "Площадка"
+// @Success 200 {object} IdResponse "Площадка создана"
+// @Failure 201 {object} ErrResponse "Ошибка при создании площадки"
+// @Router /api/v1/venues [post]
+func (h *VenueHandler) CreateVenue(w http.ResponseWriter, r *http.Request) {
+	var input venueInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	venue := input.venue()
+	venue.CreatedBy = currentUserID(r)
+
+	id, err := h.services.CreateVenue(venue)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, IdResponse{Status: "ok", Id: id})
+}
+
+// Изменение площадки
+// @Summary Изменение площадки (только автор)
+// @Tags Площадки
+// @Param id path int true "Идентификатор площадки"
+// @Param Request body venueInput true "Площадка"
+// @Success 200 {object} StatusResponse "Площадка изменена"
+// @Failure 201 {object} ErrResponse "Ошибка при изменении площадки"
+// @Router /api/v1/venues/{id} [put]
+func (h *VenueHandler) UpdateVenue(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input venueInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	venue := input.venue()
+	venue.ID = id
+
+	if err := h.services.UpdateVenue(currentUserID(r), venue); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Площадка
+// @Summary Площадка по идентификатору
+// @Tags Площадки
+// @Param id path int true "Идентификатор площадки"
+// @Success 200 {object} VenueOkResponse "Площадка"
+// @Failure 201 {object} ErrResponse "Площадка не найдена"
+// @Router /api/v1/venues/{id} [get]
+func (h *VenueHandler) Venue(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	venue, err := h.services.Venue(id)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, VenueOkResponse{Status: "ok", Venue: newVenueResponse(venue)})
+}
+
+// Поиск площадок
+// @Summary Поиск площадок по названию или адресу, при заданном near - сортировка по расстоянию
+// @Tags Площадки
+// @Param q query string false "Подстрока названия или адреса"
+// @Param near query string false "Координаты центра поиска: lat,lon"
+// @Param radius_km query number false "Радиус поиска в километрах (по умолчанию 10)"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} VenuesOkResponse "Площадки"
+// @Failure 201 {object} ErrResponse "Ошибка при поиске площадок"
+// @Router /api/v1/venues [get]
+func (h *VenueHandler) Venues(w http.ResponseWriter, r *http.Request) {
+	limit, offset := pagination(r)
+
+	near, err := geoFilter(r)
+	if err != nil {
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+		return
+	}
+
+	venues, err := h.services.Venues(models.VenueFilter{
+		Query:  strings.TrimSpace(r.URL.Query().Get("q")),
+		Near:   near,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := VenuesOkResponse{Status: "ok", Venues: make([]VenueResponse, 0, len(venues))}
+	for _, venue := range venues {
+		response.Venues = append(response.Venues, newVenueResponse(venue))
+	}
+
+	render.JSON(w, r, response)
+}
+
+func (i venueInput) venue() models.Venue {
+	return models.Venue{
+		Name:               i.Name,
+		Address:            i.Address,
+		Location:           models.GeoPoint{Lat: i.Lat, Lon: i.Lon},
+		Capacity:           i.Capacity,
+		AccessibilityNotes: i.AccessibilityNotes,
+		HostCompany:        i.HostCompany,
+	}
+}
@@ -0,0 +1,257 @@
+package rest
+
+import (
+	"dev_meets/internal/domain/models"
+	"dev_meets/internal/transport"
+	"encoding/json"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type WebhookHandler struct {
+	services transport.WebhookServiceInt
+	logger   *slog.Logger
+}
+
+func NewWebhookHandler(serv transport.WebhookServiceInt, logger *slog.Logger) *WebhookHandler {
+	return &WebhookHandler{services: serv, logger: logger}
+}
+
+type webhookInput struct {
+	URL        string   `json:"url" validate:"required,url,max=2000" example:"https://example.com/hooks/dev-meets"`
+	EventTypes []string `json:"event_types" validate:"required,min=1,dive,required" example:"event.published,rsvp.created"`
+}
+
+type webhookUpdateInput struct {
+	webhookInput
+	Active bool `json:"active" example:"true"`
+}
+
+type WebhookResponse struct {
+	Id           int        `json:"id" example:"7"`
+	GroupId      int        `json:"group_id" example:"3"`
+	URL          string     `json:"url" example:"https://example.com/hooks/dev-meets"`
+	Secret       string     `json:"secret,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	EventTypes   []string   `json:"event_types" example:"event.published,rsvp.created"`
+	Active       bool       `json:"active" example:"true"`
+	FailureCount int        `json:"failure_count" example:"0"`
+	DisabledAt   *time.Time `json:"disabled_at,omitempty" example:"2024-02-20T12:00:00+03:00"`
+	CreatedAt    time.Time  `json:"created_at" example:"2024-02-01T12:00:00+03:00"`
+}
+
+type WebhookOkResponse struct {
+	Status  string          `json:"status" example:"ok"`
+	Webhook WebhookResponse `json:"webhook"`
+}
+
+type WebhooksOkResponse struct {
+	Status   string            `json:"status" example:"ok"`
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+
+type WebhookDeliveryResponse struct {
+	Id         int64           `json:"id" example:"1024"`
+	DeliveryId string          `json:"delivery_id" example:"5d41402abc4b2a76b9719d911017c592"`
+	EventType  string          `json:"event_type" example:"rsvp.created"`
+	Payload    json.RawMessage `json:"payload" swaggertype:"object"`
+	Attempt    int             `json:"attempt" example:"1"`
+	StatusCode int             `json:"status_code,omitempty" example:"200"`
+	Error      string          `json:"error,omitempty" example:"webhook responded with non-2xx status: 502"`
+	DurationMs int64           `json:"duration_ms" example:"120"`
+	Success    bool            `json:"success" example:"true"`
+	CreatedAt  time.Time       `json:"created_at" example:"2024-02-20T12:00:00+03:00"`
+}
+
+type WebhookDeliveriesOkResponse struct {
+	Status     string                    `json:"status" example:"ok"`
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+	NextCursor string                    `json:"next_cursor,omitempty" example:"MTAyNA"`
+}
+
+func newWebhookResponse(webhook models.Webhook) WebhookResponse {
+	return WebhookResponse{
+		Id:           webhook.ID,
+		GroupId:      webhook.GroupID,
+		URL:          webhook.URL,
+		EventTypes:   webhook.EventTypes,
+		Active:       webhook.Active,
+		FailureCount: webhook.FailureCount,
+		DisabledAt:   webhook.DisabledAt,
+		CreatedAt:    webhook.CreatedAt,
+	}
+}
+
+// Создание вебхука
+// @Summary Регистрация вебхука сообщества. Доступно владельцу сообщества
+// @Description События: event.published, rsvp.created, event.cancelled. Секрет возвращается только при создании.
+// @Description Каждый запрос содержит заголовки X-DevMeets-Event, X-DevMeets-Delivery, X-DevMeets-Timestamp
+// @Description и X-DevMeets-Signature: sha256=hex(HMAC-SHA256(секрет, "<timestamp>.<тело запроса>")).
+// @Description Ответ не из 2xx считается ошибкой, доставка повторяется с нарастающей задержкой.
+// @Description После серии ошибок подряд (по умолчанию 20) вебхук отключается, а владелец получает уведомление.
+// @Tags Вебхуки
+// @Param id path int true "Идентификатор сообщества"
+// @Param Request body webhookInput true "Вебхук"
+// @Success 200 {object} WebhookOkResponse "Вебхук создан"
+// @Failure 201 {object} ErrResponse "Ошибка при создании вебхука"
+// @Router /api/v1/groups/{id}/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	groupID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input webhookInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	webhook, err := h.services.CreateWebhook(r.Context(), currentUserID(r), models.Webhook{
+		GroupID:    groupID,
+		URL:        input.URL,
+		EventTypes: input.EventTypes,
+	})
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := newWebhookResponse(webhook)
+	response.Secret = webhook.Secret
+
+	render.JSON(w, r, WebhookOkResponse{Status: "ok", Webhook: response})
+}
+
+// Вебхуки сообщества
+// @Summary Вебхуки сообщества. Доступно владельцу сообщества
+// @Tags Вебхуки
+// @Param id path int true "Идентификатор сообщества"
+// @Success 200 {object} WebhooksOkResponse "Вебхуки"
+// @Failure 201 {object} ErrResponse "Сообщество не найдено или нет прав"
+// @Router /api/v1/groups/{id}/webhooks [get]
+func (h *WebhookHandler) Webhooks(w http.ResponseWriter, r *http.Request) {
+	groupID, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	webhooks, err := h.services.Webhooks(currentUserID(r), groupID)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := WebhooksOkResponse{Status: "ok", Webhooks: make([]WebhookResponse, 0, len(webhooks))}
+	for _, webhook := range webhooks {
+		response.Webhooks = append(response.Webhooks, newWebhookResponse(webhook))
+	}
+
+	render.JSON(w, r, response)
+}
+
+// Изменение вебхука
+// @Summary Изменение адреса, событий и состояния вебхука
+// @Description active=true включает вебхук, отключённый из-за ошибок, и сбрасывает счётчик ошибок.
+// @Tags Вебхуки
+// @Param id path int true "Идентификатор вебхука"
+// @Param Request body webhookUpdateInput true "Вебхук"
+// @Success 200 {object} StatusResponse "Вебхук изменён"
+// @Failure 201 {object} ErrResponse "Ошибка при изменении вебхука"
+// @Router /api/v1/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var input webhookUpdateInput
+	if !decodeInput(w, r, h.logger, &input) {
+		return
+	}
+
+	if err := h.services.UpdateWebhook(r.Context(), currentUserID(r), models.Webhook{
+		ID:         id,
+		URL:        input.URL,
+		EventTypes: input.EventTypes,
+		Active:     input.Active,
+	}); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Удаление вебхука
+// @Summary Удаление вебхука вместе с журналом доставок
+// @Tags Вебхуки
+// @Param id path int true "Идентификатор вебхука"
+// @Success 200 {object} StatusResponse "Вебхук удалён"
+// @Failure 201 {object} ErrResponse "Вебхук не найден или нет прав"
+// @Router /api/v1/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.services.DeleteWebhook(r.Context(), currentUserID(r), id); err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	render.JSON(w, r, StatusResponse{Status: "ok"})
+}
+
+// Журнал доставок
+// @Summary Попытки доставки событий на вебхук, новые сначала
+// @Tags Вебхуки
+// @Param id path int true "Идентификатор вебхука"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param limit query int false "Количество записей (по умолчанию 20)"
+// @Success 200 {object} WebhookDeliveriesOkResponse "Доставки"
+// @Failure 201 {object} ErrResponse "Вебхук не найден или нет прав"
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) WebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	limit, cursor, ok := cursorPagination(r)
+	if !ok {
+		render.JSON(w, r, ErrResponse{Status: "wrong_params"})
+		return
+	}
+
+	deliveries, err := h.services.WebhookDeliveries(currentUserID(r), id, cursor, limit)
+	if err != nil {
+		renderError(w, r, h.logger, err)
+		return
+	}
+
+	response := WebhookDeliveriesOkResponse{
+		Status:     "ok",
+		Deliveries: make([]WebhookDeliveryResponse, 0, len(deliveries)),
+	}
+	for _, d := range deliveries {
+		response.Deliveries = append(response.Deliveries, WebhookDeliveryResponse{
+			Id:         d.ID,
+			DeliveryId: d.DeliveryID,
+			EventType:  d.EventType,
+			Payload:    d.Payload,
+			Attempt:    d.Attempt,
+			StatusCode: d.StatusCode,
+			Error:      d.Error,
+			DurationMs: d.Duration.Milliseconds(),
+			Success:    d.Success,
+			CreatedAt:  d.CreatedAt,
+		})
+	}
+	if len(deliveries) > 0 {
+		response.NextCursor = nextCursor(int(deliveries[len(deliveries)-1].ID), len(deliveries), limit)
+	}
+
+	render.JSON(w, r, response)
+}
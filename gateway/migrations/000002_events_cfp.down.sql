@@ -0,0 +1,7 @@
+
+DROP TABLE agenda_slots;
+DROP TABLE talk_reviews;
+DROP TABLE talks;
+DROP TABLE cfp_reviewers;
+DROP TABLE call_for_papers;
+DROP TABLE events;
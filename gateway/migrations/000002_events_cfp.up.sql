@@ -0,0 +1,67 @@
+
+CREATE TABLE IF NOT EXISTS events
+(
+    id           SERIAL PRIMARY KEY,
+    organizer_id INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    title        TEXT        NOT NULL,
+    description  TEXT        NOT NULL DEFAULT '',
+    starts_at    TIMESTAMPTZ NOT NULL,
+    ends_at      TIMESTAMPTZ NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (ends_at >= starts_at)
+);
+CREATE INDEX IF NOT EXISTS idx_events_starts_at ON events (starts_at);
+CREATE INDEX IF NOT EXISTS idx_events_organizer_id ON events (organizer_id);
+
+CREATE TABLE IF NOT EXISTS call_for_papers
+(
+    event_id    INT PRIMARY KEY REFERENCES events (id) ON DELETE CASCADE,
+    description TEXT        NOT NULL DEFAULT '',
+    deadline    TIMESTAMPTZ NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS cfp_reviewers
+(
+    event_id INT NOT NULL REFERENCES call_for_papers (event_id) ON DELETE CASCADE,
+    user_id  INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    PRIMARY KEY (event_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS talks
+(
+    id         SERIAL PRIMARY KEY,
+    event_id   INT         NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    speaker_id INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    title      TEXT        NOT NULL,
+    abstract   TEXT        NOT NULL,
+    level      TEXT        NOT NULL,
+    duration   INT         NOT NULL CHECK (duration > 0),
+    tags       TEXT[]      NOT NULL DEFAULT '{}',
+    status     TEXT        NOT NULL DEFAULT 'submitted',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_talks_event_id ON talks (event_id);
+CREATE INDEX IF NOT EXISTS idx_talks_speaker_id ON talks (speaker_id);
+
+CREATE TABLE IF NOT EXISTS talk_reviews
+(
+    id          SERIAL PRIMARY KEY,
+    talk_id     INT         NOT NULL REFERENCES talks (id) ON DELETE CASCADE,
+    reviewer_id INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    score       INT         NOT NULL CHECK (score BETWEEN 1 AND 5),
+    comment     TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (talk_id, reviewer_id)
+);
+
+CREATE TABLE IF NOT EXISTS agenda_slots
+(
+    id       SERIAL PRIMARY KEY,
+    event_id INT  NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    position INT  NOT NULL,
+    kind     TEXT NOT NULL,
+    talk_id  INT UNIQUE REFERENCES talks (id) ON DELETE CASCADE,
+    title    TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_agenda_slots_event_id ON agenda_slots (event_id, position);
@@ -0,0 +1,11 @@
+
+DROP TABLE agenda_slot_speakers;
+DROP TABLE speakers;
+
+ALTER TABLE agenda_slots
+    DROP CONSTRAINT agenda_slots_time_check,
+    DROP COLUMN description,
+    DROP COLUMN starts_at,
+    DROP COLUMN ends_at,
+    DROP COLUMN slides_url,
+    DROP COLUMN video_url;
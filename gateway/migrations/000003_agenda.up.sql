@@ -0,0 +1,41 @@
+
+ALTER TABLE agenda_slots
+    ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS starts_at   TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS ends_at     TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS slides_url  TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS video_url   TEXT NOT NULL DEFAULT '',
+    ADD CONSTRAINT agenda_slots_time_check CHECK (ends_at >= starts_at);
+
+CREATE TABLE IF NOT EXISTS speakers
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    INT UNIQUE REFERENCES users (id) ON DELETE SET NULL,
+    name       TEXT        NOT NULL DEFAULT '',
+    bio        TEXT        NOT NULL DEFAULT '',
+    photo_url  TEXT        NOT NULL DEFAULT '',
+    created_by INT REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS agenda_slot_speakers
+(
+    slot_id    INT NOT NULL REFERENCES agenda_slots (id) ON DELETE CASCADE,
+    speaker_id INT NOT NULL REFERENCES speakers (id) ON DELETE CASCADE,
+    position   INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (slot_id, speaker_id)
+);
+
+-- докладчики уже принятых докладов
+INSERT INTO speakers(user_id)
+SELECT DISTINCT t.speaker_id
+FROM talks t
+         JOIN agenda_slots a ON a.talk_id = t.id
+ON CONFLICT (user_id) DO NOTHING;
+
+INSERT INTO agenda_slot_speakers(slot_id, speaker_id)
+SELECT a.id, s.id
+FROM agenda_slots a
+         JOIN talks t ON t.id = a.talk_id
+         JOIN speakers s ON s.user_id = t.speaker_id
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,8 @@
+
+ALTER TABLE events
+    DROP COLUMN venue_id;
+
+DROP TABLE venues;
+
+DROP EXTENSION IF EXISTS earthdistance;
+DROP EXTENSION IF EXISTS cube;
@@ -0,0 +1,24 @@
+
+CREATE EXTENSION IF NOT EXISTS cube;
+CREATE EXTENSION IF NOT EXISTS earthdistance;
+
+CREATE TABLE IF NOT EXISTS venues
+(
+    id                  SERIAL PRIMARY KEY,
+    name                TEXT             NOT NULL,
+    address             TEXT             NOT NULL,
+    latitude            DOUBLE PRECISION NOT NULL CHECK (latitude BETWEEN -90 AND 90),
+    longitude           DOUBLE PRECISION NOT NULL CHECK (longitude BETWEEN -180 AND 180),
+    capacity            INT              NOT NULL DEFAULT 0 CHECK (capacity >= 0),
+    accessibility_notes TEXT             NOT NULL DEFAULT '',
+    host_company        TEXT             NOT NULL DEFAULT '',
+    created_by          INT REFERENCES users (id) ON DELETE SET NULL,
+    created_at          TIMESTAMPTZ      NOT NULL DEFAULT now()
+);
+-- индекс для earth_box(...) @> ll_to_earth(...)
+CREATE INDEX IF NOT EXISTS idx_venues_location ON venues USING gist (ll_to_earth(latitude, longitude));
+CREATE INDEX IF NOT EXISTS idx_venues_name ON venues (lower(name));
+
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS venue_id INT REFERENCES venues (id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_events_venue_id ON events (venue_id);
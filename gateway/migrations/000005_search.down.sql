@@ -0,0 +1,26 @@
+
+ALTER TABLE users
+    DROP COLUMN search_vector;
+ALTER TABLE groups
+    DROP COLUMN search_vector;
+ALTER TABLE events
+    DROP COLUMN search_vector;
+
+ALTER TABLE events
+    DROP COLUMN group_id,
+    DROP COLUMN city;
+
+DROP TABLE group_members;
+DROP TABLE groups;
+
+ALTER TABLE users
+    DROP COLUMN name,
+    DROP COLUMN username,
+    DROP COLUMN city,
+    DROP COLUMN bio,
+    DROP COLUMN skills,
+    DROP COLUMN seniority;
+
+DROP FUNCTION immutable_array_to_string(TEXT[], TEXT);
+
+DROP EXTENSION IF EXISTS pg_trgm;
@@ -0,0 +1,81 @@
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- array_to_string помечена как STABLE и не может использоваться в генерируемых колонках
+CREATE OR REPLACE FUNCTION immutable_array_to_string(TEXT[], TEXT) RETURNS TEXT
+    LANGUAGE sql
+    IMMUTABLE PARALLEL SAFE AS
+$$
+SELECT array_to_string($1, $2)
+$$;
+
+-- профиль разработчика
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS name      TEXT   NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS username  TEXT,
+    ADD COLUMN IF NOT EXISTS city      TEXT   NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS bio       TEXT   NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS skills    TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS seniority TEXT   NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users (lower(username));
+CREATE INDEX IF NOT EXISTS idx_users_skills ON users USING gin (skills);
+
+-- сообщества
+CREATE TABLE IF NOT EXISTS groups
+(
+    id          SERIAL PRIMARY KEY,
+    owner_id    INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name        TEXT        NOT NULL,
+    description TEXT        NOT NULL DEFAULT '',
+    city        TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS group_members
+(
+    group_id  INT         NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    user_id   INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role      TEXT        NOT NULL DEFAULT 'member',
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (group_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_group_members_user_id ON group_members (user_id);
+
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS group_id INT REFERENCES groups (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS city     TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_events_group_id ON events (group_id);
+
+-- полнотекстовый поиск: заголовки важнее описаний, словари русского и английского
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', title), 'A') ||
+        setweight(to_tsvector('english', title), 'A') ||
+        setweight(to_tsvector('russian', description), 'B') ||
+        setweight(to_tsvector('english', description), 'B')
+        ) STORED;
+
+ALTER TABLE groups
+    ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', name), 'A') ||
+        setweight(to_tsvector('english', name), 'A') ||
+        setweight(to_tsvector('russian', description), 'B') ||
+        setweight(to_tsvector('english', description), 'B')
+        ) STORED;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', name || ' ' || COALESCE(username, '')), 'A') ||
+        setweight(to_tsvector('simple', immutable_array_to_string(skills, ' ')), 'B') ||
+        setweight(to_tsvector('russian', bio), 'C') ||
+        setweight(to_tsvector('english', bio), 'C')
+        ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_events_search ON events USING gin (search_vector);
+CREATE INDEX IF NOT EXISTS idx_groups_search ON groups USING gin (search_vector);
+CREATE INDEX IF NOT EXISTS idx_users_search ON users USING gin (search_vector);
+
+-- нечёткий поиск по названиям и именам с опечатками
+CREATE INDEX IF NOT EXISTS idx_events_title_trgm ON events USING gin (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_groups_name_trgm ON groups USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_users_name_trgm ON users USING gin ((name || ' ' || COALESCE(username, '')) gin_trgm_ops);
@@ -0,0 +1,13 @@
+
+CREATE TABLE IF NOT EXISTS rsvps
+(
+    id            SERIAL PRIMARY KEY,
+    event_id      INT         NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    user_id       INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status        TEXT        NOT NULL DEFAULT 'going',
+    checked_in_at TIMESTAMPTZ,
+    checked_in_by INT REFERENCES users (id) ON DELETE SET NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (event_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_rsvps_user_id ON rsvps (user_id);
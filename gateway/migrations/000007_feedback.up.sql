@@ -0,0 +1,17 @@
+
+CREATE TABLE IF NOT EXISTS feedback
+(
+    id         SERIAL PRIMARY KEY,
+    event_id   INT         NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    slot_id    INT REFERENCES agenda_slots (id) ON DELETE CASCADE,
+    user_id    INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    rating     SMALLINT    NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    comment    TEXT        NOT NULL DEFAULT '',
+    anonymous  BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- один отзыв пользователя на мероприятие и один на каждый доклад
+CREATE UNIQUE INDEX IF NOT EXISTS idx_feedback_event_user ON feedback (event_id, user_id) WHERE slot_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_feedback_slot_user ON feedback (slot_id, user_id) WHERE slot_id IS NOT NULL;
@@ -0,0 +1,3 @@
+
+DROP TABLE comment_revisions;
+DROP TABLE comments;
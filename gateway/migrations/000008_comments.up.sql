@@ -0,0 +1,26 @@
+
+CREATE TABLE IF NOT EXISTS comments
+(
+    id           SERIAL PRIMARY KEY,
+    subject_type TEXT        NOT NULL CHECK (subject_type IN ('event', 'group')),
+    subject_id   INT         NOT NULL,
+    root_id      INT REFERENCES comments (id) ON DELETE CASCADE,
+    parent_id    INT REFERENCES comments (id) ON DELETE CASCADE,
+    author_id    INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    body         TEXT        NOT NULL,
+    body_html    TEXT        NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    edited_at    TIMESTAMPTZ,
+    deleted_at   TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_comments_subject ON comments (subject_type, subject_id, id DESC) WHERE root_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_comments_root_id ON comments (root_id, id);
+
+CREATE TABLE IF NOT EXISTS comment_revisions
+(
+    id         SERIAL PRIMARY KEY,
+    comment_id INT         NOT NULL REFERENCES comments (id) ON DELETE CASCADE,
+    body       TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_comment_revisions_comment_id ON comment_revisions (comment_id, id);
@@ -0,0 +1,29 @@
+
+CREATE TABLE IF NOT EXISTS notifications
+(
+    id            SERIAL PRIMARY KEY,
+    user_id       INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    type          TEXT        NOT NULL,
+    title         TEXT        NOT NULL,
+    body          TEXT        NOT NULL DEFAULT '',
+    group_key     TEXT        NOT NULL DEFAULT '',
+    count         INT         NOT NULL DEFAULT 1,
+    email_pending BOOLEAN     NOT NULL DEFAULT FALSE,
+    read_at       TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications (user_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications (user_id) WHERE read_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_notifications_email_pending ON notifications (user_id, id) WHERE email_pending;
+-- непрочитанные уведомления с одним ключом склеиваются в одно
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_group_key ON notifications (user_id, group_key)
+    WHERE read_at IS NULL AND group_key <> '';
+
+CREATE TABLE IF NOT EXISTS notification_preferences
+(
+    user_id INT  NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    type    TEXT NOT NULL,
+    channel TEXT NOT NULL CHECK (channel IN ('in_app', 'email', 'none')),
+    PRIMARY KEY (user_id, type)
+);
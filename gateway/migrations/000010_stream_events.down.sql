@@ -0,0 +1,4 @@
+
+DROP TRIGGER stream_events_notify ON stream_events;
+DROP FUNCTION notify_stream_event();
+DROP TABLE stream_events;
@@ -0,0 +1,30 @@
+
+-- журнал событий для real-time доставки: по нему клиенты догоняют пропущенное
+-- после переподключения (Last-Event-ID), а реплики шлюза узнают о новых событиях
+CREATE TABLE IF NOT EXISTS stream_events
+(
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    INT REFERENCES users (id) ON DELETE CASCADE,
+    topic      TEXT        NOT NULL DEFAULT '',
+    type       TEXT        NOT NULL,
+    payload    JSONB       NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (user_id IS NOT NULL OR topic <> '')
+);
+CREATE INDEX IF NOT EXISTS idx_stream_events_user_id ON stream_events (user_id, id) WHERE user_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_stream_events_topic ON stream_events (topic, id) WHERE topic <> '';
+CREATE INDEX IF NOT EXISTS idx_stream_events_created_at ON stream_events (created_at);
+
+CREATE OR REPLACE FUNCTION notify_stream_event() RETURNS trigger AS
+$$
+BEGIN
+    PERFORM pg_notify('stream_events', NEW.id::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER stream_events_notify
+    AFTER INSERT
+    ON stream_events
+    FOR EACH ROW
+EXECUTE FUNCTION notify_stream_event();
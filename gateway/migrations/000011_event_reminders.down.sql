@@ -0,0 +1,2 @@
+
+DROP TABLE event_reminders;
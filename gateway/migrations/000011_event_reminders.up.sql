@@ -0,0 +1,11 @@
+
+-- отправленные напоминания: первичный ключ не даёт отправить одно напоминание дважды,
+-- даже если планировщик работает на нескольких репликах
+CREATE TABLE IF NOT EXISTS event_reminders
+(
+    event_id       INT         NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    user_id        INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    offset_minutes INT         NOT NULL,
+    sent_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (event_id, user_id, offset_minutes)
+);
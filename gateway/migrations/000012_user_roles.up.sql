@@ -0,0 +1,4 @@
+
+-- роль пользователя на платформе: user или admin
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'user';
@@ -0,0 +1,22 @@
+
+-- очередь фоновых задач: воркеры забирают задачи через SELECT ... FOR UPDATE SKIP LOCKED,
+-- поэтому задачу выполняет только одна реплика
+CREATE TABLE IF NOT EXISTS jobs
+(
+    id           BIGSERIAL PRIMARY KEY,
+    type         TEXT        NOT NULL,
+    payload      JSONB       NOT NULL DEFAULT '{}',
+    status       TEXT        NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'done', 'dead')),
+    attempts     INT         NOT NULL DEFAULT 0,
+    max_attempts INT         NOT NULL,
+    run_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    -- locked_until - до какого момента задача закреплена за воркером; после него
+    -- задачу упавшей реплики заберёт другая
+    locked_until TIMESTAMPTZ,
+    last_error   TEXT        NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_jobs_pending ON jobs (run_at, id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_jobs_running ON jobs (locked_until) WHERE status = 'running';
+CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs (status, id DESC);
@@ -0,0 +1,6 @@
+
+DROP TABLE webhook_deliveries;
+DROP TABLE webhooks;
+
+ALTER TABLE events
+    DROP COLUMN cancelled_at;
@@ -0,0 +1,36 @@
+
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS webhooks
+(
+    id            SERIAL PRIMARY KEY,
+    group_id      INT         NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    url           TEXT        NOT NULL,
+    secret        TEXT        NOT NULL,
+    event_types   TEXT[]      NOT NULL,
+    active        BOOLEAN     NOT NULL DEFAULT TRUE,
+    -- failure_count - число неудачных доставок подряд, при достижении порога вебхук отключается
+    failure_count INT         NOT NULL DEFAULT 0,
+    disabled_at   TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_webhooks_group_id ON webhooks (group_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id          BIGSERIAL PRIMARY KEY,
+    webhook_id  INT         NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    -- delivery_id одинаков у всех попыток доставки одного события
+    delivery_id TEXT        NOT NULL,
+    event_type  TEXT        NOT NULL,
+    payload     JSONB       NOT NULL,
+    attempt     INT         NOT NULL,
+    status_code INT         NOT NULL DEFAULT 0,
+    error       TEXT        NOT NULL DEFAULT '',
+    duration_ms INT         NOT NULL,
+    success     BOOLEAN     NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_delivery_id ON webhook_deliveries (delivery_id);
@@ -0,0 +1,4 @@
+
+DROP TABLE telegram_group_chats;
+DROP TABLE telegram_link_codes;
+DROP TABLE telegram_accounts;
@@ -0,0 +1,26 @@
+
+CREATE TABLE IF NOT EXISTS telegram_accounts
+(
+    user_id          INT         PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    telegram_user_id BIGINT      NOT NULL UNIQUE,
+    linked_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- одноразовые коды для привязки аккаунта: пользователь отправляет код боту
+CREATE TABLE IF NOT EXISTS telegram_link_codes
+(
+    code       TEXT PRIMARY KEY,
+    user_id    INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_telegram_link_codes_user_id ON telegram_link_codes (user_id);
+
+-- чаты, в которые бот публикует мероприятия сообщества
+CREATE TABLE IF NOT EXISTS telegram_group_chats
+(
+    group_id  INT         NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    chat_id   BIGINT      NOT NULL,
+    linked_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (group_id, chat_id)
+);
+CREATE INDEX IF NOT EXISTS idx_telegram_group_chats_chat_id ON telegram_group_chats (chat_id);
@@ -0,0 +1,5 @@
+
+DROP TABLE user_blocks;
+DROP TABLE messages;
+DROP TABLE conversation_members;
+DROP TABLE conversations;
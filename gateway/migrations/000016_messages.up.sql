@@ -0,0 +1,45 @@
+
+CREATE TABLE IF NOT EXISTS conversations
+(
+    id              SERIAL PRIMARY KEY,
+    kind            TEXT        NOT NULL CHECK (kind IN ('direct', 'group')),
+    title           TEXT        NOT NULL DEFAULT '',
+    -- direct_key - "<меньший id>:<больший id>", у двух пользователей один личный диалог
+    direct_key      TEXT UNIQUE,
+    created_by      INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_message_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS conversation_members
+(
+    conversation_id      INT         NOT NULL REFERENCES conversations (id) ON DELETE CASCADE,
+    user_id              INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    -- last_read_message_id - последнее прочитанное сообщение, из него строятся отметки о прочтении
+    last_read_message_id INT         NOT NULL DEFAULT 0,
+    joined_at            TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (conversation_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_conversation_members_user_id ON conversation_members (user_id);
+
+CREATE TABLE IF NOT EXISTS messages
+(
+    id              SERIAL PRIMARY KEY,
+    conversation_id INT         NOT NULL REFERENCES conversations (id) ON DELETE CASCADE,
+    sender_id       INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    body            TEXT        NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_messages_conversation_id ON messages (conversation_id, id DESC);
+-- для ограничения частоты отправки
+CREATE INDEX IF NOT EXISTS idx_messages_sender_id ON messages (sender_id, created_at);
+
+CREATE TABLE IF NOT EXISTS user_blocks
+(
+    blocker_id INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    blocked_id INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id <> blocked_id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_blocks_blocked_id ON user_blocks (blocked_id);
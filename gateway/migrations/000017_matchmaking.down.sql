@@ -0,0 +1,3 @@
+
+DROP TABLE match_requests;
+DROP TABLE match_profiles;
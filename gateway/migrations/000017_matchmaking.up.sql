@@ -0,0 +1,26 @@
+
+-- участие в подборе собеседников: пользователь без записи в подборке не участвует
+CREATE TABLE IF NOT EXISTS match_profiles
+(
+    user_id    INT PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    goals      TEXT[]      NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_match_profiles_goals ON match_profiles USING gin (goals);
+
+CREATE TABLE IF NOT EXISTS match_requests
+(
+    id              SERIAL PRIMARY KEY,
+    requester_id    INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    recipient_id    INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    goal            TEXT        NOT NULL,
+    message         TEXT        NOT NULL DEFAULT '',
+    status          TEXT        NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined')),
+    conversation_id INT REFERENCES conversations (id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    responded_at    TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_match_requests_pending ON match_requests (requester_id, recipient_id)
+    WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_match_requests_recipient_id ON match_requests (recipient_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_match_requests_requester_id ON match_requests (requester_id, id DESC);
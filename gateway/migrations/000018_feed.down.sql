@@ -0,0 +1,3 @@
+
+DROP INDEX idx_rsvps_event_id_going;
+DROP TABLE feed_dismissals;
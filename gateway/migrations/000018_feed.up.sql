@@ -0,0 +1,10 @@
+
+-- мероприятия, которые пользователь скрыл из ленты кнопкой «Не интересно»
+CREATE TABLE IF NOT EXISTS feed_dismissals
+(
+    user_id    INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    event_id   INT         NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, event_id)
+);
+CREATE INDEX IF NOT EXISTS idx_rsvps_event_id_going ON rsvps (event_id) WHERE status = 'going';
@@ -0,0 +1,4 @@
+
+DROP TABLE activities;
+DROP TABLE group_follows;
+DROP TABLE user_follows;
@@ -0,0 +1,34 @@
+
+CREATE TABLE IF NOT EXISTS user_follows
+(
+    follower_id INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    followee_id INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_follows_followee_id ON user_follows (followee_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS group_follows
+(
+    user_id    INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    group_id   INT         NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, group_id)
+);
+CREATE INDEX IF NOT EXISTS idx_group_follows_group_id ON group_follows (group_id, created_at DESC);
+
+-- действия пользователей. Лента подписок собирается из них при чтении, поэтому
+-- действие записывается один раз, сколько бы подписчиков ни было у автора
+CREATE TABLE IF NOT EXISTS activities
+(
+    id         BIGSERIAL PRIMARY KEY,
+    actor_id   INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    kind       TEXT        NOT NULL CHECK (kind IN ('event_created', 'talk_accepted', 'group_joined')),
+    group_id   INT REFERENCES groups (id) ON DELETE CASCADE,
+    event_id   INT REFERENCES events (id) ON DELETE CASCADE,
+    talk_id    INT REFERENCES talks (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_activities_actor_id ON activities (actor_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_activities_group_id ON activities (group_id, id DESC) WHERE group_id IS NOT NULL;
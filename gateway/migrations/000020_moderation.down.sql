@@ -0,0 +1,13 @@
+
+DROP TABLE moderation_actions;
+DROP TABLE reports;
+ALTER TABLE messages
+    DROP COLUMN hidden_at;
+ALTER TABLE comments
+    DROP COLUMN hidden_at;
+ALTER TABLE events
+    DROP COLUMN hidden_at;
+ALTER TABLE users
+    DROP COLUMN suspension_reason,
+    DROP COLUMN suspended_until,
+    DROP COLUMN suspended_at;
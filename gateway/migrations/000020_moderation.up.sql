@@ -0,0 +1,52 @@
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS suspended_at      TIMESTAMPTZ,
+    -- NULL при suspended_at - бессрочная блокировка
+    ADD COLUMN IF NOT EXISTS suspended_until   TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS suspension_reason TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMPTZ;
+ALTER TABLE comments
+    ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMPTZ;
+ALTER TABLE messages
+    ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS reports
+(
+    id             SERIAL PRIMARY KEY,
+    reporter_id    INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    target_type    TEXT        NOT NULL CHECK (target_type IN ('user', 'event', 'comment', 'message')),
+    target_id      INT         NOT NULL,
+    -- автор содержимого или сам пользователь, на которого пожаловались
+    target_user_id INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    reason         TEXT        NOT NULL CHECK (reason IN ('spam', 'abuse', 'inappropriate', 'other')),
+    details        TEXT        NOT NULL DEFAULT '',
+    -- текст содержимого на момент жалобы: автор может его изменить или удалить
+    snapshot       TEXT        NOT NULL DEFAULT '',
+    status         TEXT        NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'dismissed', 'actioned')),
+    resolved_by    INT REFERENCES users (id) ON DELETE SET NULL,
+    resolved_at    TIMESTAMPTZ,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reports_open ON reports (reporter_id, target_type, target_id)
+    WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_reports_queue ON reports (id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_reports_target ON reports (target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_reports_reporter_id ON reports (reporter_id, created_at);
+
+-- журнал модерации: записи только добавляются
+CREATE TABLE IF NOT EXISTS moderation_actions
+(
+    id              SERIAL PRIMARY KEY,
+    moderator_id    INT REFERENCES users (id) ON DELETE SET NULL,
+    action          TEXT        NOT NULL CHECK (action IN ('dismiss', 'hide', 'warn', 'suspend')),
+    target_type     TEXT        NOT NULL,
+    target_id       INT         NOT NULL,
+    target_user_id  INT REFERENCES users (id) ON DELETE SET NULL,
+    report_ids      INT[]       NOT NULL DEFAULT '{}',
+    note            TEXT        NOT NULL DEFAULT '',
+    suspended_until TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_moderation_actions_target_user_id ON moderation_actions (target_user_id, id DESC);
@@ -0,0 +1,4 @@
+
+DROP TABLE audit_events;
+ALTER TABLE users
+    DROP COLUMN token_version;
\ No newline at end of file
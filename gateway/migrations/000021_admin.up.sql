@@ -0,0 +1,20 @@
+
+-- token_version увеличивается при принудительном выходе: токены со старой версией
+-- перестают приниматься
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS token_version INT NOT NULL DEFAULT 0;
+
+-- журнал действий администраторов: записи только добавляются
+CREATE TABLE IF NOT EXISTS audit_events
+(
+    id          BIGSERIAL PRIMARY KEY,
+    actor_id    INT REFERENCES users (id) ON DELETE SET NULL,
+    action      TEXT        NOT NULL,
+    target_type TEXT        NOT NULL,
+    target_id   INT         NOT NULL,
+    -- состояние объекта до и после изменения
+    before      JSONB,
+    after       JSONB,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_audit_events_target ON audit_events (target_type, target_id, id DESC);
\ No newline at end of file
@@ -0,0 +1,11 @@
+
+DROP TRIGGER audit_events_immutable ON audit_events;
+DROP FUNCTION audit_events_immutable();
+DROP INDEX idx_audit_events_created_at;
+DROP INDEX idx_audit_events_action;
+DROP INDEX idx_audit_events_actor_id;
+DELETE FROM audit_events WHERE target_id IS NULL;
+ALTER TABLE audit_events
+    ALTER COLUMN target_id SET NOT NULL,
+    DROP COLUMN request_id,
+    DROP COLUMN ip;
\ No newline at end of file
@@ -0,0 +1,31 @@
+
+-- адрес и идентификатор запроса, в рамках которого выполнено действие
+ALTER TABLE audit_events
+    ADD COLUMN IF NOT EXISTS ip         TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS request_id TEXT NOT NULL DEFAULT '',
+    -- неудачный вход с несуществующей почтой не относится ни к одному пользователю
+    ALTER COLUMN target_id DROP NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor_id ON audit_events (actor_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_action ON audit_events (action, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events (created_at);
+
+-- журнал только дополняется: менять записи нельзя, кроме обнуления actor_id
+-- при удалении пользователя. Старые записи удаляются по сроку хранения
+CREATE OR REPLACE FUNCTION audit_events_immutable() RETURNS trigger AS
+$$
+BEGIN
+    IF NEW.actor_id IS NULL AND (NEW.id, NEW.action, NEW.target_type, NEW.target_id, NEW.before, NEW.after, NEW.ip,
+                                 NEW.request_id, NEW.created_at) IS NOT DISTINCT FROM
+                                (OLD.id, OLD.action, OLD.target_type, OLD.target_id, OLD.before, OLD.after, OLD.ip,
+                                 OLD.request_id, OLD.created_at) THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_immutable
+    BEFORE UPDATE
+    ON audit_events
+    FOR EACH ROW
+EXECUTE FUNCTION audit_events_immutable();
\ No newline at end of file
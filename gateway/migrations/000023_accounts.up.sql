@@ -0,0 +1,22 @@
+
+-- запрошенное удаление аккаунта: до deletion_scheduled_at пользователь может
+-- передумать, войдя снова. deleted_at - аккаунт обезличен, авторское содержимое осталось
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS deleted_at            TIMESTAMPTZ;
+
+-- выгрузки данных пользователя: архив хранится в базе, чтобы его могла отдать любая
+-- реплика, и удаляется после expires_at
+CREATE TABLE IF NOT EXISTS data_exports
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    -- token - секрет ссылки на скачивание
+    token      TEXT        NOT NULL UNIQUE,
+    status     TEXT        NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'ready')),
+    archive    BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ready_at   TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_data_exports_user_id ON data_exports (user_id, id DESC);
\ No newline at end of file
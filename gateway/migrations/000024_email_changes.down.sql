@@ -0,0 +1 @@
+DROP TABLE email_changes;
\ No newline at end of file
@@ -0,0 +1,12 @@
+-- запрошенная смена почты: новый адрес начинает действовать, только когда владелец
+-- подтвердит его секретом из письма. У пользователя одна незавершённая смена, новая заменяет прежнюю
+CREATE TABLE IF NOT EXISTS email_changes
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    INT         NOT NULL UNIQUE REFERENCES users (id) ON DELETE CASCADE,
+    new_email  TEXT        NOT NULL,
+    -- token - секрет из письма на новый адрес
+    token      TEXT        NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
\ No newline at end of file
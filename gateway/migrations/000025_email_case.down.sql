@@ -0,0 +1,6 @@
+DROP INDEX idx_users_email_lower;
+DROP INDEX users_email_lower_key;
+ALTER TABLE users
+    DROP COLUMN email_duplicate,
+    ADD CONSTRAINT users_email_key UNIQUE (email);
+CREATE INDEX IF NOT EXISTS idx_email ON users (email);
\ No newline at end of file
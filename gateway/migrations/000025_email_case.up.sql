@@ -0,0 +1,23 @@
+-- почта сравнивается без учёта регистра. Адреса, которые не совпадают с другими
+-- без учёта регистра, приводятся к нижнему регистру
+UPDATE users u
+SET email = lower(u.email)
+WHERE u.email <> lower(u.email)
+  AND NOT EXISTS (SELECT 1 FROM users d WHERE d.id <> u.id AND lower(d.email) = lower(u.email));
+
+-- email_duplicate отмечает аккаунты, заведённые до нормализации на адрес, который уже
+-- был у другого аккаунта в другом регистре. Старейший аккаунт группы остаётся основным,
+-- остальные не попадают в уникальный индекс, пока администратор их не объединит.
+-- Найти их можно в GET /api/v1/admin/users?duplicate_email=true
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS email_duplicate BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE users u
+SET email_duplicate = TRUE
+WHERE EXISTS (SELECT 1 FROM users d WHERE d.id < u.id AND lower(d.email) = lower(u.email));
+
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_email_key;
+DROP INDEX IF EXISTS idx_email;
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower_key ON users (lower(email)) WHERE NOT email_duplicate;
+CREATE INDEX IF NOT EXISTS idx_users_email_lower ON users (lower(email));
\ No newline at end of file
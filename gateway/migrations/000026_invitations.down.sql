@@ -0,0 +1,6 @@
+DROP TABLE invitation_acceptances;
+DROP TABLE invitations;
+ALTER TABLE events
+    DROP COLUMN private;
+ALTER TABLE groups
+    DROP COLUMN private;
\ No newline at end of file
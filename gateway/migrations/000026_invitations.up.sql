@@ -0,0 +1,40 @@
+-- закрытые сообщества и мероприятия: вступить или записаться можно только по приглашению.
+-- В списках, поиске и ленте они видны как обычно
+ALTER TABLE groups
+    ADD COLUMN IF NOT EXISTS private BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS private BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- приглашения в сообщества и на мероприятия. Личное приглашение адресовано пользователю
+-- (invitee_id) или почте без аккаунта (email) и используется один раз. Ссылка ни к кому
+-- не привязана, max_uses NULL - без ограничения. token - секрет ссылки или кода из письма
+CREATE TABLE IF NOT EXISTS invitations
+(
+    id           SERIAL PRIMARY KEY,
+    subject_type TEXT        NOT NULL CHECK (subject_type IN ('group', 'event')),
+    subject_id   INT         NOT NULL,
+    inviter_id   INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    invitee_id   INT REFERENCES users (id) ON DELETE CASCADE,
+    email        TEXT,
+    token        TEXT        NOT NULL UNIQUE,
+    max_uses     INT CHECK (max_uses > 0),
+    uses         INT         NOT NULL DEFAULT 0,
+    expires_at   TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_invitations_subject ON invitations (subject_type, subject_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_invitations_invitee_id ON invitations (invitee_id, id DESC) WHERE invitee_id IS NOT NULL;
+-- приглашения на почту, владелец которой ещё не зарегистрировался
+CREATE INDEX IF NOT EXISTS idx_invitations_email ON invitations (lower(email)) WHERE invitee_id IS NULL;
+
+-- кто принял приглашение. Принятое приглашение позволяет вступить в сообщество или
+-- записаться на мероприятие и повторно, после выхода или отмены записи
+CREATE TABLE IF NOT EXISTS invitation_acceptances
+(
+    invitation_id INT         NOT NULL REFERENCES invitations (id) ON DELETE CASCADE,
+    user_id       INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    accepted_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (invitation_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_invitation_acceptances_user_id ON invitation_acceptances (user_id);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE orders;
+DROP TABLE ticket_types;
\ No newline at end of file
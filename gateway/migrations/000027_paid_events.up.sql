@@ -0,0 +1,42 @@
+-- типы билетов мероприятия. Если у мероприятия есть типы билетов, записаться на него
+-- можно только заказом билета. Цена - в минимальных единицах валюты (копейках).
+-- reserved - сколько билетов оплачено или удерживается неоплаченными заказами
+CREATE TABLE IF NOT EXISTS ticket_types
+(
+    id           SERIAL PRIMARY KEY,
+    event_id     INT         NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    kind         TEXT        NOT NULL CHECK (kind IN ('free', 'paid', 'early_bird', 'sponsor')),
+    name         TEXT        NOT NULL,
+    price        BIGINT      NOT NULL DEFAULT 0 CHECK (price >= 0),
+    currency     TEXT        NOT NULL DEFAULT '',
+    quantity     INT         NOT NULL CHECK (quantity > 0),
+    reserved     INT         NOT NULL DEFAULT 0 CHECK (reserved >= 0 AND reserved <= quantity),
+    sales_end_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_ticket_types_event_id ON ticket_types (event_id, id);
+
+-- заказы билетов. Неоплаченный заказ удерживает билет до expires_at, после чего истекает.
+-- payment_id - номер платежа у платёжного провайдера provider
+CREATE TABLE IF NOT EXISTS orders
+(
+    id             SERIAL PRIMARY KEY,
+    event_id       INT         NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    ticket_type_id INT         NOT NULL REFERENCES ticket_types (id) ON DELETE CASCADE,
+    user_id        INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    amount         BIGINT      NOT NULL,
+    currency       TEXT        NOT NULL,
+    status         TEXT        NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'paid', 'refunded', 'expired')),
+    provider       TEXT        NOT NULL DEFAULT '',
+    payment_id     TEXT,
+    payment_url    TEXT        NOT NULL DEFAULT '',
+    expires_at     TIMESTAMPTZ NOT NULL,
+    paid_at        TIMESTAMPTZ,
+    refunded_at    TIMESTAMPTZ,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (provider, payment_id)
+);
+CREATE INDEX IF NOT EXISTS idx_orders_event_id ON orders (event_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_orders_user_id ON orders (user_id, id DESC);
+-- у пользователя не больше одного действующего заказа на мероприятие
+CREATE UNIQUE INDEX IF NOT EXISTS orders_active_key ON orders (event_id, user_id) WHERE status IN ('pending', 'paid');
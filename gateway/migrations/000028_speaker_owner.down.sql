@@ -0,0 +1 @@
+-- отвязанные карточки докладчиков не привязываются обратно
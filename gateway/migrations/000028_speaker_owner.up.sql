@@ -0,0 +1,8 @@
+-- карточку докладчика можно было привязать к чужому аккаунту, и её автор сохранял право
+-- менять имя и фото, которые видны на принятых докладах этого пользователя. Такие карточки
+-- отвязываются от аккаунта и остаются карточками гостей
+UPDATE speakers
+SET user_id = NULL
+WHERE user_id IS NOT NULL
+  AND created_by IS NOT NULL
+  AND created_by <> user_id;
@@ -0,0 +1,192 @@
+// Package email отправляет письма через SMTP-сервер: текстовые письма в UTF-8,
+// STARTTLS или TLS с первого байта, авторизация PLAIN
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lineLength - длина строки тела письма в base64 по RFC 2045
+const lineLength = 76
+
+var (
+	ErrInvalidAddress = errors.New("email: invalid address")
+	ErrInvalidHeader  = errors.New("email: header contains a line break")
+	// ErrRecipientRejected - сервер навсегда отказался принимать письмо для адресата
+	ErrRecipientRejected = errors.New("email: recipient rejected")
+)
+
+type Config struct {
+	Host string
+	Port int
+	// From - адрес отправителя, можно с именем: "dev meets <noreply@devmeets.ru>"
+	From     string
+	Username string
+	Password string
+	// ImplicitTLS - соединение сразу по TLS (обычно порт 465). Иначе используется
+	// STARTTLS, если сервер его поддерживает
+	ImplicitTLS bool
+	Timeout     time.Duration
+}
+
+type SMTP struct {
+	config Config
+}
+
+func NewSMTP(config Config) *SMTP {
+	return &SMTP{config: config}
+}
+
+// Send отправляет текстовое письмо на адрес to
+func (s *SMTP) Send(to, subject, body string) error {
+	from, err := mail.ParseAddress(s.config.From)
+	if err != nil {
+		return fmt.Errorf("%w: sender: %v", ErrInvalidAddress, err)
+	}
+	recipient, err := mail.ParseAddress(to)
+	if err != nil {
+		return fmt.Errorf("%w: recipient: %v", ErrInvalidAddress, err)
+	}
+
+	message, err := buildMessage(from, recipient, subject, body, time.Now())
+	if err != nil {
+		return err
+	}
+
+	client, err := s.dial()
+	if err != nil {
+		return fmt.Errorf("email: connect: %w", err)
+	}
+	defer client.Close()
+
+	if !s.config.ImplicitTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: s.config.Host}); err != nil {
+				return fmt.Errorf("email: starttls: %w", err)
+			}
+		}
+	}
+
+	if s.config.Username != "" {
+		auth := smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("email: auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("email: mail from: %w", err)
+	}
+	if err := client.Rcpt(recipient.Address); err != nil {
+		var protoErr *textproto.Error
+		if errors.As(err, &protoErr) && protoErr.Code >= 500 {
+			return fmt.Errorf("%w: %w", ErrRecipientRejected, err)
+		}
+
+		return fmt.Errorf("email: rcpt to: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("email: data: %w", err)
+	}
+	if _, err := w.Write(message); err != nil {
+		return fmt.Errorf("email: data: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("email: data: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// dial подключается к серверу. Timeout ограничивает весь разговор с сервером, а не только
+// установку соединения: net/smtp сам таймаутов не ставит
+func (s *SMTP) dial() (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	dialer := &net.Dialer{Timeout: s.config.Timeout}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+	if s.config.ImplicitTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: s.config.Host})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.config.Timeout > 0 {
+		if err := conn.SetDeadline(time.Now().Add(s.config.Timeout)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// IsPermanent сообщает, что повтор отправки этого письма не поможет: адрес некорректен
+// или сервер отказался принимать почту для адресата. Ошибки авторизации и прочие отказы
+// сервера постоянными не считаются - их исправляют в настройках, и письма уходят повторно
+func IsPermanent(err error) bool {
+	return errors.Is(err, ErrInvalidAddress) || errors.Is(err, ErrInvalidHeader) || errors.Is(err, ErrRecipientRejected)
+}
+
+func buildMessage(from, to *mail.Address, subject, body string, date time.Time) ([]byte, error) {
+	if strings.ContainsAny(subject, "\r\n") {
+		return nil, ErrInvalidHeader
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("email: message id: %w", err)
+	}
+	_, domain, _ := strings.Cut(from.Address, "@")
+
+	var b bytes.Buffer
+	header := func(name, value string) {
+		b.WriteString(name + ": " + value + "\r\n")
+	}
+	header("From", from.String())
+	header("To", to.String())
+	header("Subject", mime.QEncoding.Encode("utf-8", subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("Message-ID", "<"+hex.EncodeToString(id)+"@"+domain+">")
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "base64")
+	b.WriteString("\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(body))
+	for len(encoded) > lineLength {
+		b.WriteString(encoded[:lineLength] + "\r\n")
+		encoded = encoded[lineLength:]
+	}
+	b.WriteString(encoded + "\r\n")
+
+	return b.Bytes(), nil
+}
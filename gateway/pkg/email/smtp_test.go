@@ -0,0 +1,206 @@
+package email
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeServer - SMTP-сервер без TLS, который принимает одно письмо и запоминает разговор.
+// rejectRcpt - код, которым сервер отвечает на RCPT TO, 0 - принять адресата
+type fakeServer struct {
+	listener   net.Listener
+	rejectRcpt int
+
+	auth     string
+	mailFrom string
+	rcptTo   string
+	data     string
+	done     chan struct{}
+}
+
+func newFakeServer(t *testing.T, rejectRcpt int) *fakeServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	s := &fakeServer{listener: listener, rejectRcpt: rejectRcpt, done: make(chan struct{})}
+	go s.serve()
+
+	return s
+}
+
+func (s *fakeServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeServer) serve() {
+	defer close(s.done)
+
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	c := textproto.NewConn(conn)
+	_ = c.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+
+		command, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(command) {
+		case "EHLO":
+			_ = c.PrintfLine("250-localhost")
+			_ = c.PrintfLine("250 AUTH PLAIN")
+		case "AUTH":
+			s.auth = arg
+			_ = c.PrintfLine("235 ok")
+		case "MAIL":
+			s.mailFrom = arg
+			_ = c.PrintfLine("250 ok")
+		case "RCPT":
+			s.rcptTo = arg
+			if s.rejectRcpt != 0 {
+				_ = c.PrintfLine("%d no such user", s.rejectRcpt)
+				continue
+			}
+			_ = c.PrintfLine("250 ok")
+		case "DATA":
+			_ = c.PrintfLine("354 go ahead")
+			data, err := c.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.data = string(data)
+			_ = c.PrintfLine("250 queued")
+		case "QUIT":
+			_ = c.PrintfLine("221 bye")
+			return
+		default:
+			_ = c.PrintfLine("250 ok")
+		}
+	}
+}
+
+func testConfig(port int) Config {
+	return Config{
+		Host:     "127.0.0.1",
+		Port:     port,
+		From:     "dev meets <noreply@devmeets.test>",
+		Username: "mailer",
+		Password: "secret",
+		Timeout:  5 * time.Second,
+	}
+}
+
+func TestSMTPSend(t *testing.T) {
+	server := newFakeServer(t, 0)
+	body := "Код подтверждения: 123456\n" + strings.Repeat("длинная строка ", 20)
+
+	if err := NewSMTP(testConfig(server.port())).Send("user@example.com", "Подтверждение почты", body); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	<-server.done
+
+	if want := "PLAIN " + base64.StdEncoding.EncodeToString([]byte("\x00mailer\x00secret")); server.auth != want {
+		t.Errorf("AUTH %q, want %q", server.auth, want)
+	}
+	if server.mailFrom != "FROM:<noreply@devmeets.test>" {
+		t.Errorf("MAIL %q", server.mailFrom)
+	}
+	if server.rcptTo != "TO:<user@example.com>" {
+		t.Errorf("RCPT %q", server.rcptTo)
+	}
+
+	message, err := mail.ReadMessage(strings.NewReader(server.data))
+	if err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	if err != nil || subject != "Подтверждение почты" {
+		t.Errorf("Subject = %q, %v", subject, err)
+	}
+	if got := message.Header.Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	// ReadDotBytes заменяет CRLF на LF
+	encoded, err := io.ReadAll(message.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	lines := strings.Fields(string(encoded))
+	for _, line := range lines {
+		if len(line) > lineLength {
+			t.Errorf("body line is %d characters long", len(line))
+		}
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.Join(lines, ""))
+	if err != nil || string(decoded) != body {
+		t.Errorf("body = %q, %v, want %q", decoded, err, body)
+	}
+}
+
+func TestSMTPSendPermanentErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		rejectRcpt int
+		to         string
+		subject    string
+		permanent  bool
+	}{
+		{name: "recipient rejected", rejectRcpt: 550, to: "user@example.com", subject: "Тема", permanent: true},
+		{name: "mailbox busy", rejectRcpt: 450, to: "user@example.com", subject: "Тема", permanent: false},
+		{name: "invalid recipient", to: "not an address", subject: "Тема", permanent: true},
+		{name: "header injection", to: "user@example.com", subject: "Тема\r\nBcc: victim@example.com", permanent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeServer(t, tt.rejectRcpt)
+
+			err := NewSMTP(testConfig(server.port())).Send(tt.to, tt.subject, "body")
+			if err == nil {
+				t.Fatal("Send() error = nil")
+			}
+			if got := IsPermanent(err); got != tt.permanent {
+				t.Errorf("IsPermanent(%v) = %v, want %v", err, got, tt.permanent)
+			}
+		})
+	}
+}
+
+func TestSMTPSendUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	err = NewSMTP(testConfig(port)).Send("user@example.com", "Тема", "body")
+	if err == nil {
+		t.Fatal("Send() error = nil")
+	}
+	if IsPermanent(err) {
+		t.Errorf("connection error %v is permanent", err)
+	}
+	if errors.Is(err, ErrRecipientRejected) || !strings.Contains(err.Error(), strconv.Itoa(port)) {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package ics
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	prodID     = "-//dev_meets//dev_meets//RU"
+	timeLayout = "20060102T150405Z"
+	lineLimit  = 75
+)
+
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	Stamp       time.Time
+}
+
+// Calendar формирует iCalendar (RFC 5545) с одним VEVENT
+func Calendar(event Event) string {
+	var b strings.Builder
+
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+prodID)
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	writeLine(&b, "BEGIN:VEVENT")
+	writeLine(&b, "UID:"+escape(event.UID))
+	writeLine(&b, "DTSTAMP:"+event.Stamp.UTC().Format(timeLayout))
+	writeLine(&b, "DTSTART:"+event.Start.UTC().Format(timeLayout))
+	writeLine(&b, "DTEND:"+event.End.UTC().Format(timeLayout))
+	writeLine(&b, "SUMMARY:"+escape(event.Summary))
+	if event.Description != "" {
+		writeLine(&b, "DESCRIPTION:"+escape(event.Description))
+	}
+	writeLine(&b, "END:VEVENT")
+	writeLine(&b, "END:VCALENDAR")
+
+	return b.String()
+}
+
+// UID возвращает стабильный идентификатор события календаря
+func UID(kind string, id int, domain string) string {
+	return fmt.Sprintf("%s-%d@%s", kind, id, domain)
+}
+
+func escape(s string) string {
+	// одиночный \r тоже разрывает строку контента, поэтому все переводы строк приводятся к \n
+	s = strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(s)
+
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// writeLine пишет строку контента, перенося её по 75 октетов без разрыва UTF-8 символов
+func writeLine(b *strings.Builder, line string) {
+	limit := lineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// строки продолжения начинаются с пробела, который тоже считается
+		limit = lineLimit - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
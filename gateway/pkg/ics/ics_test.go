@@ -0,0 +1,55 @@
+package ics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain", in: "Go в проде", want: "Go в проде"},
+		{name: "separators", in: `a;b,c\d`, want: `a\;b\,c\\d`},
+		{name: "crlf", in: "строка 1\r\nстрока 2", want: `строка 1\nстрока 2`},
+		{name: "lf", in: "строка 1\nстрока 2", want: `строка 1\nстрока 2`},
+		{name: "lone cr", in: "строка 1\rEND:VEVENT", want: `строка 1\nEND:VEVENT`},
+		{name: "cr before crlf", in: "a\r\r\nb", want: `a\n\nb`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escape(tt.in); got != tt.want {
+				t.Errorf("escape(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// Текст докладчика не может добавить в календарь свои строки контента
+func TestCalendarKeepsLineStructure(t *testing.T) {
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	calendar := Calendar(Event{
+		UID:         UID("event", 1, "devmeets.test"),
+		Summary:     "Доклад\rX-INJECTED:1",
+		Description: "Биография\r\nBEGIN:VALARM\rTRIGGER:-PT1M\nEND:VALARM",
+		Start:       start,
+		End:         start.Add(time.Hour),
+		Stamp:       start,
+	})
+
+	if !strings.HasSuffix(calendar, "\r\n") {
+		t.Fatalf("calendar does not end with CRLF: %q", calendar)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(calendar, "\r\n"), "\r\n") {
+		if strings.ContainsAny(line, "\r\n") {
+			t.Errorf("line contains a bare line break: %q", line)
+		}
+		if strings.HasPrefix(line, "X-INJECTED") || strings.HasPrefix(line, "BEGIN:VALARM") || strings.HasPrefix(line, "TRIGGER") {
+			t.Errorf("injected content line: %q", line)
+		}
+	}
+}
@@ -17,6 +17,7 @@ func NewToken(user models.User, duration time.Duration) (string, error) {
 	claims := token.Claims.(jwt.MapClaims)
 	claims["uid"] = user.ID
 	claims["email"] = user.Email
+	claims["ver"] = user.TokenVersion
 	claims["exp"] = time.Now().Add(duration).Unix()
 
 	tokenString, err := token.SignedString([]byte(SECRET))
@@ -27,7 +28,24 @@ func NewToken(user models.User, duration time.Duration) (string, error) {
 	return tokenString, nil
 }
 
+// Claims - данные пользователя из проверенного токена
+type Claims struct {
+	UserID int
+	// Version - версия токенов пользователя на момент выдачи
+	Version int
+}
+
 func VerifyToken(tokenString string) (int, error) {
+	claims, err := ParseToken(tokenString)
+	if err != nil {
+		return 0, err
+	}
+
+	return claims.UserID, nil
+}
+
+// ParseToken проверяет подпись и срок действия токена и возвращает его данные
+func ParseToken(tokenString string) (Claims, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return "", fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
@@ -37,12 +55,19 @@ func VerifyToken(tokenString string) (int, error) {
 	})
 
 	if err != nil {
-		return 0, err
+		return Claims{}, err
 	}
 	if !token.Valid {
-		return 0, fmt.Errorf("invalid token")
+		return Claims{}, fmt.Errorf("invalid token")
 	}
 
 	claims := token.Claims.(jwt.MapClaims)
-	return int(claims["uid"].(float64)), nil
+	uid, ok := claims["uid"].(float64)
+	if !ok {
+		return Claims{}, fmt.Errorf("invalid token")
+	}
+	// у токенов, выданных до появления версий, её нет - это версия 0
+	ver, _ := claims["ver"].(float64)
+
+	return Claims{UserID: int(uid), Version: int(ver)}, nil
 }
@@ -0,0 +1,78 @@
+package markdown
+
+import (
+	"bytes"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+	"regexp"
+	"strings"
+)
+
+var (
+	md = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+	// policy разрешает только разметку пользовательского контента:
+	// ссылки получают rel="nofollow", скрипты и обработчики событий вырезаются
+	policy = bluemonday.UGCPolicy()
+
+	mentionRe = regexp.MustCompile(`(?:^|[^\w@/])@([a-zA-Z0-9_]{3,32})\b`)
+)
+
+// Render преобразует Markdown в безопасный HTML
+func Render(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+
+	return policy.Sanitize(buf.String()), nil
+}
+
+// Mentions возвращает упомянутые в тексте имена пользователей (@username)
+// в нижнем регистре, без повторов. Упоминания внутри кода не учитываются
+func Mentions(source string) []string {
+	seen := make(map[string]struct{})
+	usernames := make([]string, 0)
+	for _, match := range mentionRe.FindAllStringSubmatch(plainText(source), -1) {
+		username := strings.ToLower(match[1])
+		if _, ok := seen[username]; ok {
+			continue
+		}
+		seen[username] = struct{}{}
+		usernames = append(usernames, username)
+	}
+
+	return usernames
+}
+
+// plainText возвращает текст документа без блоков кода и фрагментов `кода`
+func plainText(source string) string {
+	raw := []byte(source)
+	doc := md.Parser().Parse(text.NewReader(raw))
+
+	var buf strings.Builder
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		switch n := node.(type) {
+		case *ast.CodeSpan, *ast.CodeBlock, *ast.FencedCodeBlock, *ast.HTMLBlock, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			if entering {
+				buf.Write(n.Segment.Value(raw))
+				if n.SoftLineBreak() || n.HardLineBreak() {
+					buf.WriteByte('\n')
+				}
+			}
+		default:
+			if !entering && node.Type() == ast.TypeBlock {
+				buf.WriteByte('\n')
+			}
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return buf.String()
+}
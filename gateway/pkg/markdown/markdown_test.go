@@ -0,0 +1,103 @@
+package markdown
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		wantIn    []string
+		wantNotIn []string
+	}{
+		{
+			name:   "markup",
+			source: "**жирный** и [ссылка](https://example.com)",
+			wantIn: []string{"<strong>жирный</strong>", `href="https://example.com"`, `rel="nofollow"`},
+		},
+		{
+			name:      "script tag",
+			source:    "до <script>alert(1)</script> после",
+			wantIn:    []string{"до", "после"},
+			wantNotIn: []string{"<script"},
+		},
+		{
+			name:      "javascript link",
+			source:    "[жми](javascript:alert(1))",
+			wantIn:    []string{"жми"},
+			wantNotIn: []string{"javascript:"},
+		},
+		{
+			name:      "raw html block",
+			source:    "<div onclick=\"alert(1)\"><iframe src=\"https://evil.test\"></iframe></div>",
+			wantNotIn: []string{"onclick", "<iframe", "evil.test"},
+		},
+		{
+			name:      "image onerror",
+			source:    "<img src=\"x\" onerror=\"alert(1)\">",
+			wantNotIn: []string{"onerror", "alert(1)"},
+		},
+		{
+			name:      "image with javascript source",
+			source:    "![картинка](javascript:alert(1))",
+			wantNotIn: []string{"javascript:"},
+		},
+		{
+			name:      "html in code is escaped",
+			source:    "`<script>alert(1)</script>`",
+			wantIn:    []string{"<code>&lt;script&gt;"},
+			wantNotIn: []string{"<script"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.source)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			for _, want := range tt.wantIn {
+				if !strings.Contains(got, want) {
+					t.Errorf("Render() = %q, want it to contain %q", got, want)
+				}
+			}
+			for _, unwanted := range tt.wantNotIn {
+				if strings.Contains(strings.ToLower(got), unwanted) {
+					t.Errorf("Render() = %q, must not contain %q", got, unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{name: "none", source: "просто текст", want: []string{}},
+		{name: "single", source: "спасибо @gopher!", want: []string{"gopher"}},
+		{name: "start of text", source: "@Gopher привет", want: []string{"gopher"}},
+		{name: "repeated in different case", source: "@gopher и @GOPHER", want: []string{"gopher"}},
+		{name: "several lines", source: "@alice\n\n- @bob_1\n> @carol", want: []string{"alice", "bob_1", "carol"}},
+		{name: "in emphasis and link", source: "**@alice** [@bob](https://example.com)", want: []string{"alice", "bob"}},
+		{name: "email", source: "пишите на team@example.com", want: []string{}},
+		{name: "too short", source: "@ab", want: []string{}},
+		{name: "code span", source: "вызовите `@decorator` и @alice", want: []string{"alice"}},
+		{name: "fenced code", source: "```\n@override\n```\n@alice", want: []string{"alice"}},
+		{name: "indented code", source: "текст\n\n    @override\n\n@alice", want: []string{"alice"}},
+		{name: "html block", source: "<div>\n@hidden\n</div>\n\n@alice", want: []string{"alice"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Mentions(tt.source); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Mentions(%q) = %v, want %v", tt.source, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// AcquirerName - имя провайдера эквайринга в заказах
+	AcquirerName = "acquirer"
+	// SignatureHeader - заголовок с HMAC-SHA256 тела вебхука в hex
+	SignatureHeader = "X-Payment-Signature"
+	// idempotenceHeader - ключ, по которому эквайринг не создаёт повторный платёж
+	// или возврат при повторе запроса
+	idempotenceHeader = "Idempotence-Key"
+)
+
+// Error - ошибка, которую вернул API эквайринга
+type Error struct {
+	StatusCode  int
+	Description string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("payment: acquirer responded %d %s", e.StatusCode, e.Description)
+}
+
+type AcquirerConfig struct {
+	APIURL string
+	ShopID string
+	// SecretKey - ключ API магазина
+	SecretKey string
+	// WebhookSecret - ключ подписи вебхуков
+	WebhookSecret string
+	// ReturnURL - куда вернуть покупателя после оплаты
+	ReturnURL string
+	Timeout   time.Duration
+}
+
+// Acquirer - клиент эквайринга. Платёж создаётся в статусе pending, покупатель
+// оплачивает его на странице эквайринга, а результат приходит вебхуком, подписанным
+// WebhookSecret
+type Acquirer struct {
+	config AcquirerConfig
+	http   *http.Client
+}
+
+func NewAcquirer(config AcquirerConfig) *Acquirer {
+	return &Acquirer{config: config, http: &http.Client{Timeout: config.Timeout}}
+}
+
+func (a *Acquirer) Name() string {
+	return AcquirerName
+}
+
+type amount struct {
+	Value    int64  `json:"value"`
+	Currency string `json:"currency"`
+}
+
+func (a *Acquirer) CreatePayment(ctx context.Context, req Request) (Payment, error) {
+	var result struct {
+		ID              string `json:"id"`
+		Status          string `json:"status"`
+		ConfirmationURL string `json:"confirmation_url"`
+	}
+	err := a.call(ctx, "/payments", "order-"+strconv.Itoa(req.OrderID), map[string]any{
+		"amount":      amount{Value: req.Amount, Currency: req.Currency},
+		"description": req.Description,
+		"return_url":  a.config.ReturnURL,
+		"metadata":    map[string]string{"order_id": strconv.Itoa(req.OrderID)},
+	}, &result)
+	if err != nil {
+		return Payment{}, err
+	}
+
+	return Payment{ID: result.ID, Status: result.Status, ConfirmationURL: result.ConfirmationURL}, nil
+}
+
+// Refund возвращает деньги за платёж. Возврат, который эквайринг отклонил, - ошибка
+func (a *Acquirer) Refund(ctx context.Context, paymentID string, value int64, currency string) error {
+	var result struct {
+		Status string `json:"status"`
+	}
+	err := a.call(ctx, "/refunds", "refund-"+paymentID, map[string]any{
+		"payment_id": paymentID,
+		"amount":     amount{Value: value, Currency: currency},
+	}, &result)
+	if err != nil {
+		return err
+	}
+	if result.Status == StatusCanceled {
+		return fmt.Errorf("payment: refund of %s is declined", paymentID)
+	}
+
+	return nil
+}
+
+// ParseWebhook проверяет подпись вебхука и разбирает извещение. Эквайринг присылает
+// payment.succeeded, payment.canceled и refund.succeeded, остальные события - ошибка
+func (a *Acquirer) ParseWebhook(header http.Header, body []byte) (Event, error) {
+	signature, err := hex.DecodeString(header.Get(SignatureHeader))
+	if err != nil || a.config.WebhookSecret == "" {
+		return Event{}, ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(a.config.WebhookSecret))
+	mac.Write(body)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return Event{}, ErrInvalidSignature
+	}
+
+	var notification struct {
+		Event  string `json:"event"`
+		Object struct {
+			ID        string `json:"id"`
+			PaymentID string `json:"payment_id"`
+		} `json:"object"`
+	}
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return Event{}, ErrMalformedEvent
+	}
+
+	var event Event
+	switch notification.Event {
+	case "payment.succeeded":
+		event = Event{PaymentID: notification.Object.ID, Status: StatusSucceeded}
+	case "payment.canceled":
+		event = Event{PaymentID: notification.Object.ID, Status: StatusCanceled}
+	case "refund.succeeded":
+		// объект возврата ссылается на платёж
+		event = Event{PaymentID: notification.Object.PaymentID, Status: StatusRefunded}
+	default:
+		return Event{}, ErrMalformedEvent
+	}
+	if event.PaymentID == "" {
+		return Event{}, ErrMalformedEvent
+	}
+
+	return event, nil
+}
+
+func (a *Acquirer) call(ctx context.Context, path, idempotenceKey string, params, result any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("payment: encode %s: %w", path, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.config.APIURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("payment: %s: %w", path, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(idempotenceHeader, idempotenceKey)
+	req.SetBasicAuth(a.config.ShopID, a.config.SecretKey)
+
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("payment: %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		description, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &Error{StatusCode: resp.StatusCode, Description: string(description)}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("payment: decode %s: %w", path, err)
+	}
+
+	return nil
+}
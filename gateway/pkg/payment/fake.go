@@ -0,0 +1,89 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// FakeName - имя фейкового провайдера в заказах
+const FakeName = "fake"
+
+// Fake хранит платежи в памяти. С autoConfirm платёж сразу считается оплаченным, иначе
+// его исход сообщает вебхук с телом {"payment_id": "fake-1", "status": "succeeded"}.
+// Подпись вебхука не проверяется, поэтому в продакшене Fake использовать нельзя
+type Fake struct {
+	mu          sync.Mutex
+	autoConfirm bool
+	seq         int
+	payments    map[string]string
+}
+
+func NewFake(autoConfirm bool) *Fake {
+	return &Fake{autoConfirm: autoConfirm, payments: make(map[string]string)}
+}
+
+func (f *Fake) Name() string {
+	return FakeName
+}
+
+func (f *Fake) CreatePayment(_ context.Context, req Request) (Payment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.seq++
+	payment := Payment{
+		ID:              fmt.Sprintf("fake-%d", f.seq),
+		Status:          StatusPending,
+		ConfirmationURL: fmt.Sprintf("https://payments.invalid/fake/%d?order=%d", f.seq, req.OrderID),
+	}
+	if f.autoConfirm {
+		payment.Status = StatusSucceeded
+	}
+	f.payments[payment.ID] = payment.Status
+
+	return payment, nil
+}
+
+func (f *Fake) Refund(_ context.Context, paymentID string, _ int64, _ string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	status, ok := f.payments[paymentID]
+	if !ok {
+		return ErrPaymentNotFound
+	}
+	if status != StatusSucceeded {
+		return fmt.Errorf("payment: cannot refund %s payment", status)
+	}
+	f.payments[paymentID] = StatusRefunded
+
+	return nil
+}
+
+func (f *Fake) ParseWebhook(_ http.Header, body []byte) (Event, error) {
+	var event struct {
+		PaymentID string `json:"payment_id"`
+		Status    string `json:"status"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil || event.PaymentID == "" {
+		return Event{}, ErrMalformedEvent
+	}
+	switch event.Status {
+	case StatusSucceeded, StatusCanceled, StatusRefunded:
+	default:
+		return Event{}, ErrMalformedEvent
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.payments[event.PaymentID]; !ok {
+		return Event{}, ErrPaymentNotFound
+	}
+	f.payments[event.PaymentID] = event.Status
+
+	return Event{PaymentID: event.PaymentID, Status: event.Status}, nil
+}
@@ -0,0 +1,43 @@
+// Package payment - платёжные провайдеры для оплаты билетов: Fake работает в процессе
+// и нужен для тестов и локальной разработки, Acquirer - клиент эквайринга, который
+// сообщает о результате оплаты вебхуками
+package payment
+
+import "errors"
+
+// Статусы платежа
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusCanceled  = "canceled"
+	StatusRefunded  = "refunded"
+)
+
+var (
+	ErrInvalidSignature = errors.New("payment: invalid webhook signature")
+	ErrMalformedEvent   = errors.New("payment: malformed webhook event")
+	ErrPaymentNotFound  = errors.New("payment: payment not found")
+)
+
+// Request - платёж за заказ. Amount - в минимальных единицах валюты Currency
+type Request struct {
+	OrderID     int
+	Amount      int64
+	Currency    string
+	Description string
+}
+
+// Payment - платёж у провайдера. ConfirmationURL - страница, на которой покупатель
+// оплачивает заказ. Платёж может быть сразу в статусе StatusSucceeded, если провайдеру
+// не нужно подтверждение покупателя
+type Payment struct {
+	ID              string
+	Status          string
+	ConfirmationURL string
+}
+
+// Event - извещение провайдера о том, что платёж PaymentID перешёл в статус Status
+type Event struct {
+	PaymentID string
+	Status    string
+}
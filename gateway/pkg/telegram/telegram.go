@@ -0,0 +1,183 @@
+// Package telegram - минимальный клиент Telegram Bot API: только методы, которые нужны
+// боту dev meets. Адрес API настраивается, поэтому клиент можно направить на локальный
+// фейковый сервер
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultAPIURL - адрес официального Bot API
+const DefaultAPIURL = "https://api.telegram.org"
+
+// SecretTokenHeader - заголовок с секретом, который Telegram передаёт в запросах на вебхук
+const SecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// Error - ошибка, которую вернул Bot API
+type Error struct {
+	Code        int
+	Description string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("telegram: %d %s", e.Code, e.Description)
+}
+
+type Update struct {
+	UpdateID      int64          `json:"update_id"`
+	Message       *Message       `json:"message,omitempty"`
+	CallbackQuery *CallbackQuery `json:"callback_query,omitempty"`
+}
+
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	From      *User  `json:"from,omitempty"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text,omitempty"`
+}
+
+type User struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username,omitempty"`
+}
+
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+// IsPrivate сообщает, что это личный чат с ботом
+func (c Chat) IsPrivate() bool {
+	return c.Type == "private"
+}
+
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	From    User     `json:"from"`
+	Message *Message `json:"message,omitempty"`
+	Data    string   `json:"data,omitempty"`
+}
+
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data,omitempty"`
+	URL          string `json:"url,omitempty"`
+}
+
+type SendMessageParams struct {
+	ChatID      int64                 `json:"chat_id"`
+	Text        string                `json:"text"`
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+type Client struct {
+	apiURL string
+	token  string
+	http   *http.Client
+}
+
+func NewClient(apiURL, token string) *Client {
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+
+	return &Client{apiURL: apiURL, token: token, http: &http.Client{}}
+}
+
+// GetUpdates ждёт новые обновления до timeout (long polling)
+func (c *Client) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error) {
+	var updates []Update
+	err := c.call(ctx, "getUpdates", map[string]any{
+		"offset":          offset,
+		"timeout":         int(timeout.Seconds()),
+		"allowed_updates": []string{"message", "callback_query"},
+	}, &updates)
+
+	return updates, err
+}
+
+func (c *Client) SendMessage(ctx context.Context, params SendMessageParams) error {
+	return c.call(ctx, "sendMessage", params, nil)
+}
+
+// AnswerCallbackQuery показывает пользователю всплывающий ответ на нажатие кнопки
+func (c *Client) AnswerCallbackQuery(ctx context.Context, callbackQueryID, text string) error {
+	return c.call(ctx, "answerCallbackQuery", map[string]any{
+		"callback_query_id": callbackQueryID,
+		"text":              text,
+	}, nil)
+}
+
+// SetWebhook просит Telegram присылать обновления на url с секретом в заголовке SecretTokenHeader
+func (c *Client) SetWebhook(ctx context.Context, url, secret string) error {
+	return c.call(ctx, "setWebhook", map[string]any{
+		"url":             url,
+		"secret_token":    secret,
+		"allowed_updates": []string{"message", "callback_query"},
+	}, nil)
+}
+
+// DeleteWebhook отключает вебхук, без этого getUpdates не работает
+func (c *Client) DeleteWebhook(ctx context.Context) error {
+	return c.call(ctx, "deleteWebhook", map[string]any{}, nil)
+}
+
+type response struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+}
+
+func (c *Client) call(ctx context.Context, method string, params, result any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("telegram: encode %s: %w", method, err)
+	}
+
+	endpoint := fmt.Sprintf("%s/bot%s/%s", c.apiURL, c.token, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("telegram: %s: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		// В url.Error есть адрес запроса, а в нём токен бота - в лог он попасть не должен
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+
+		return fmt.Errorf("telegram: %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var r response
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return fmt.Errorf("telegram: decode %s: %w", method, err)
+	}
+	if !r.OK {
+		return &Error{Code: r.ErrorCode, Description: r.Description}
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(r.Result, result); err != nil {
+			return fmt.Errorf("telegram: decode %s: %w", method, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package ticket
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Билет имеет вид DM1.<claims>.<signature>, где claims - JSON в base64url,
+// а signature - подпись Ed25519 строки "DM1.<claims>". Публичного ключа достаточно,
+// чтобы проверить билет на входе без доступа к серверу
+const prefix = "DM1"
+
+var (
+	ErrMalformed        = errors.New("malformed ticket")
+	ErrInvalidSignature = errors.New("invalid ticket signature")
+)
+
+type Claims struct {
+	TicketID int   `json:"t"`
+	EventID  int   `json:"e"`
+	UserID   int   `json:"u"`
+	IssuedAt int64 `json:"iat"`
+}
+
+type Signer struct {
+	key ed25519.PrivateKey
+}
+
+// NewSigner создаёт подписчика из seed-ключа Ed25519 (32 байта) в base64
+func NewSigner(seed string) (*Signer, error) {
+	raw, err := base64.StdEncoding.DecodeString(seed)
+	if err != nil {
+		return nil, fmt.Errorf("ticket: decode signing key: %w", err)
+	}
+	if len(raw) != ed25519.SeedSize {
+		return nil, fmt.Errorf("ticket: signing key must be %d bytes, got %d", ed25519.SeedSize, len(raw))
+	}
+
+	return &Signer{key: ed25519.NewKeyFromSeed(raw)}, nil
+}
+
+func (s *Signer) Sign(claims Claims) (string, error) {
+	body, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signed := prefix + "." + base64.RawURLEncoding.EncodeToString(body)
+	signature := ed25519.Sign(s.key, []byte(signed))
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func (s *Signer) Verify(payload string) (Claims, error) {
+	return Verify(s.PublicKey(), payload)
+}
+
+func (s *Signer) PublicKey() ed25519.PublicKey {
+	return s.key.Public().(ed25519.PublicKey)
+}
+
+// Verify проверяет подпись билета публичным ключом и возвращает его содержимое
+func Verify(publicKey ed25519.PublicKey, payload string) (Claims, error) {
+	// ed25519.Verify паникует на ключе другой длины
+	if len(publicKey) != ed25519.PublicKeySize {
+		return Claims{}, ErrInvalidSignature
+	}
+
+	parts := strings.Split(payload, ".")
+	if len(parts) != 3 || parts[0] != prefix {
+		return Claims{}, ErrMalformed
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, ErrMalformed
+	}
+
+	if !ed25519.Verify(publicKey, []byte(parts[0]+"."+parts[1]), signature) {
+		return Claims{}, ErrInvalidSignature
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Claims{}, ErrMalformed
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(body, &claims); err != nil {
+		return Claims{}, ErrMalformed
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,123 @@
+package ticket
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newTestSigner(t *testing.T, fill byte) *Signer {
+	t.Helper()
+
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = fill
+	}
+	signer, err := NewSigner(base64.StdEncoding.EncodeToString(seed))
+	if err != nil {
+		t.Fatalf("NewSigner() error = %v", err)
+	}
+
+	return signer
+}
+
+// signRaw подписывает произвольное содержимое билета, минуя Sign
+func signRaw(s *Signer, body string) string {
+	signed := prefix + "." + base64.RawURLEncoding.EncodeToString([]byte(body))
+	signature := ed25519.Sign(s.key, []byte(signed))
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestVerify(t *testing.T) {
+	signer, other := newTestSigner(t, 0), newTestSigner(t, 1)
+	claims := Claims{TicketID: 3, EventID: 7, UserID: 2, IssuedAt: 1700000000}
+
+	payload, err := signer.Sign(claims)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	parts := strings.Split(payload, ".")
+	forged, _ := other.Sign(Claims{TicketID: 3, EventID: 7, UserID: 9})
+	forgedClaims := strings.Split(forged, ".")[1]
+
+	tests := []struct {
+		name      string
+		publicKey ed25519.PublicKey
+		payload   string
+		wantErr   error
+	}{
+		{name: "valid", publicKey: signer.PublicKey(), payload: payload},
+		{
+			name:      "tampered claims",
+			publicKey: signer.PublicKey(),
+			payload:   parts[0] + "." + forgedClaims + "." + parts[2],
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "tampered signature",
+			publicKey: signer.PublicKey(),
+			payload:   parts[0] + "." + parts[1] + "." + strings.Split(forged, ".")[2],
+			wantErr:   ErrInvalidSignature,
+		},
+		{name: "wrong key", publicKey: other.PublicKey(), payload: payload, wantErr: ErrInvalidSignature},
+		{name: "short key", publicKey: signer.PublicKey()[:16], payload: payload, wantErr: ErrInvalidSignature},
+		{name: "no key", payload: payload, wantErr: ErrInvalidSignature},
+		{name: "empty", publicKey: signer.PublicKey(), wantErr: ErrMalformed},
+		{name: "missing signature", publicKey: signer.PublicKey(), payload: parts[0] + "." + parts[1], wantErr: ErrMalformed},
+		{name: "extra part", publicKey: signer.PublicKey(), payload: payload + ".x", wantErr: ErrMalformed},
+		{
+			name:      "wrong prefix",
+			publicKey: signer.PublicKey(),
+			payload:   "DM2." + parts[1] + "." + parts[2],
+			wantErr:   ErrMalformed,
+		},
+		{
+			name:      "bad signature encoding",
+			publicKey: signer.PublicKey(),
+			payload:   parts[0] + "." + parts[1] + ".!!!",
+			wantErr:   ErrMalformed,
+		},
+		{
+			name:      "truncated signature",
+			publicKey: signer.PublicKey(),
+			payload:   payload[:len(payload)-4],
+			wantErr:   ErrInvalidSignature,
+		},
+		{name: "signed non-JSON claims", publicKey: signer.PublicKey(), payload: signRaw(signer, "not json"), wantErr: ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Verify(tt.publicKey, tt.payload)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got != claims {
+				t.Errorf("Verify() = %+v, want %+v", got, claims)
+			}
+		})
+	}
+}
+
+func TestNewSigner(t *testing.T) {
+	tests := []struct {
+		name    string
+		seed    string
+		wantErr bool
+	}{
+		{name: "valid", seed: base64.StdEncoding.EncodeToString(make([]byte, ed25519.SeedSize))},
+		{name: "short", seed: base64.StdEncoding.EncodeToString(make([]byte, 16)), wantErr: true},
+		{name: "not base64", seed: "не ключ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSigner(tt.seed); (err != nil) != tt.wantErr {
+				t.Errorf("NewSigner() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}